@@ -30,12 +30,23 @@ type ThanosCompactSpec struct {
 	// ObjectStorageConfig is the object storage configuration for the compact component.
 	// +kubebuilder:validation:Required
 	ObjectStorageConfig ObjectStorageConfig `json:"objectStorageConfig"`
+	// VerifyObjectStorage runs a Job that lists the bucket to check that the object storage is reachable
+	// with the configuration, and records the result in the ObjectStorageVerified condition.
+	// +kubebuilder:validation:Optional
+	VerifyObjectStorage *bool `json:"verifyObjectStorage,omitempty"`
 	// StorageConfiguration represents the storage to be used by the Thanos Compact StatefulSets.
 	// +kubebuilder:validation:Required
 	StorageConfiguration StorageConfiguration `json:"storage"`
 	// RetentionConfig is the retention configuration for the compact component.
 	// +kubebuilder:validation:Required
 	RetentionConfig RetentionResolutionConfig `json:"retentionConfig,omitempty"`
+	// RetentionConfigFrom is the name of another ThanosCompact in the namespace whose retentionConfig is used
+	// instead of the one of this resource. Compactors sharing a bucket, such as those compacting the blocks of
+	// different tenants, can so take their retention from a single resource. The retentionConfigFrom of the
+	// referenced resource is not followed. The compactor is not updated while the referenced resource is missing.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	RetentionConfigFrom *string `json:"retentionConfigFrom,omitempty"`
 	// BlockConfig defines settings for block handling.
 	// +kubebuilder:validation:Optional
 	BlockConfig *BlockConfig `json:"blockConfig,omitempty"`
@@ -67,6 +78,13 @@ type ThanosCompactSpec struct {
 	// will be performed on the underlying objects.
 	// +kubebuilder:validation:Optional
 	Paused *bool `json:"paused,omitempty"`
+	// TargetCluster is the name of the workload cluster in which the child resources are created.
+	// The cluster must be registered with the operator using the --target-cluster flag.
+	// If unset, the child resources are created in the cluster of this resource.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="targetCluster is immutable"
+	TargetCluster *string `json:"targetCluster,omitempty"`
 	// Additional configuration for the Thanos components. Allows you to add
 	// additional args, containers, volumes, and volume mounts to Thanos Deployments,
 	// and StatefulSets. Ideal to use for things like sidecars.
@@ -83,6 +101,9 @@ type ThanosCompactStatus struct {
 	Paused *bool `json:"paused,omitempty"`
 	// ShardStatuses is the status of the shards in the compact component.
 	ShardStatuses map[string]StatefulSetStatus `json:"shardStatuses,omitempty"`
+	// ObservedGeneration is the most recent generation of the ThanosCompact observed by the operator.
+	// +kubebuilder:validation:Optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // BlockViewerGlobalSyncConfig is the configuration for syncing the blocks between local and remote view for /global Block Viewer UI.
@@ -207,6 +228,8 @@ type ExternalLabelShardingConfig struct {
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // ThanosCompact is the Schema for the thanoscompacts API
 type ThanosCompact struct {
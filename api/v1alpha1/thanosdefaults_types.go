@@ -0,0 +1,83 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ThanosDefaultsSpec defines the values inherited by the Thanos resources in scope.
+// A value is only inherited if the resource does not set it.
+type ThanosDefaultsSpec struct {
+	// NamespaceSelector selects the namespaces of the Thanos resources that inherit these defaults.
+	// If not specified, the defaults apply to Thanos resources in all namespaces.
+	// +kubebuilder:validation:Optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Version of Thanos to be deployed.
+	// +kubebuilder:validation:Optional
+	Version *string `json:"version,omitempty"`
+	// Base container image (without tags) to use for the Thanos components deployed via operator.
+	// +kubebuilder:validation:Optional
+	Image *string `json:"baseImage,omitempty"`
+	// ResourceRequirements for the Thanos component containers.
+	// +kubebuilder:validation:Optional
+	ResourceRequirements *corev1.ResourceRequirements `json:"resourceRequirements,omitempty"`
+	// Log level for Thanos.
+	// +kubebuilder:validation:Enum=debug;info;warn;error
+	// +kubebuilder:validation:Optional
+	LogLevel *string `json:"logLevel,omitempty"`
+	// SecurityContext holds pod-level security attributes and common container settings.
+	// +kubebuilder:validation:Optional
+	SecurityContext *corev1.PodSecurityContext `json:"securityContext,omitempty"`
+	// ContainerSecurityContext replaces the security context of the Thanos component containers.
+	// +kubebuilder:validation:Optional
+	ContainerSecurityContext *corev1.SecurityContext `json:"containerSecurityContext,omitempty"`
+	// PriorityClassName is the name of the PriorityClass of the pods of the Thanos components.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	PriorityClassName *string `json:"priorityClassName,omitempty"`
+	// TracingConfig is the secret key that contains the tracing configuration.
+	// The secret needs to be in the namespace of each Thanos resource that inherits it.
+	// +kubebuilder:validation:Optional
+	TracingConfig *corev1.SecretKeySelector `json:"tracingConfig,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+
+// ThanosDefaults is the Schema for the thanosdefaults API.
+// It holds fleet-wide defaults that are inherited by the Thanos resources in the selected namespaces.
+type ThanosDefaults struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ThanosDefaultsSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ThanosDefaultsList contains a list of ThanosDefaults
+type ThanosDefaultsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ThanosDefaults `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ThanosDefaults{}, &ThanosDefaultsList{})
+}
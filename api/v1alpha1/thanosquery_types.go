@@ -21,13 +21,19 @@ import (
 )
 
 // ThanosQuerySpec defines the desired state of ThanosQuery
+// +kubebuilder:validation:XValidation:rule="!has(self.readProbe) || !has(self.targetCluster)", message="readProbe is not supported when targetCluster is set"
+// +kubebuilder:validation:XValidation:rule="!has(self.federation) || !has(self.grpcClientTLS) || self.federation.remotes.all(r, !has(r.tlsSecret))", message="the tlsSecret of federation remotes cannot be set together with grpcClientTLS"
 type ThanosQuerySpec struct {
 	CommonFields `json:",inline"`
-	// Replicas is the number of querier replicas.
+	// DeploymentFields are the options available to all Thanos components managed as Deployments.
+	DeploymentFields `json:",inline"`
+	// Replicas is the number of querier replicas. It is exposed through the scale subresource,
+	// so that a HorizontalPodAutoscaler can target the ThanosQuery.
+	// If unset, the replicas of the Querier Deployment are managed externally, for example by a HorizontalPodAutoscaler
+	// targeting the Deployment, and the operator leaves them unchanged.
 	// +kubebuilder:validation:Minimum=1
-	// +kubebuilder:default=1
-	// +kubebuilder:validation:Required
-	Replicas int32 `json:"replicas,omitempty"`
+	// +kubebuilder:validation:Optional
+	Replicas *int32 `json:"replicas,omitempty"`
 	// ReplicaLabels are labels to treat as a replica indicator along which data is deduplicated.
 	// Data can still be queried without deduplication using 'dedup=false' parameter.
 	// Data includes time series, recording rules, and alerting rules.
@@ -35,6 +41,62 @@ type ThanosQuerySpec struct {
 	// +kubebuilder:default:={"replica"}
 	// +kubebuilder:validation:Optional
 	ReplicaLabels []string `json:"replicaLabels,omitempty"`
+	// DiscoverReplicaLabels adds the replica labels of the ThanosReceive resources whose ingesters are discovered
+	// as StoreAPI endpoints to ReplicaLabels, so that the series replicated by the ingesters are deduplicated.
+	// The replica labels of a ThanosReceive are the external labels of its hashrings whose values are derived from the pod name.
+	// Set to false to only use ReplicaLabels.
+	// +kubebuilder:default=true
+	// +kubebuilder:validation:Optional
+	DiscoverReplicaLabels *bool `json:"discoverReplicaLabels,omitempty"`
+	// Timeout is the maximum time to process a query by the Querier.
+	// +kubebuilder:default="15m"
+	// +kubebuilder:validation:Optional
+	Timeout *Duration `json:"timeout,omitempty"`
+	// LookbackDelta is the maximum lookback duration for retrieving metrics during expression evaluations.
+	// +kubebuilder:default="5m"
+	// +kubebuilder:validation:Optional
+	LookbackDelta *Duration `json:"lookbackDelta,omitempty"`
+	// MaxConcurrent is the maximum number of queries processed concurrently by each Querier.
+	// +kubebuilder:default=20
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	MaxConcurrent *int32 `json:"maxConcurrent,omitempty"`
+	// AutoDownsampling queries downsampled data when no max_source_resolution parameter is set,
+	// picking the resolution from the step of the query.
+	// +kubebuilder:default=true
+	// +kubebuilder:validation:Optional
+	AutoDownsampling *bool `json:"autoDownsampling,omitempty"`
+	// PartialResponse returns the results of the StoreAPI endpoints that answered when others fail,
+	// for queries without a partial_response parameter. If unset, the Thanos default is used.
+	// +kubebuilder:validation:Optional
+	PartialResponse *bool `json:"partialResponse,omitempty"`
+	// DeduplicationFunc is the function used to deduplicate the series of replicas along ReplicaLabels.
+	// penalty is suited to series scraped by Prometheus HA pairs, and chain to series ingested by
+	// replicated receivers. If unset, the Thanos default of penalty is used.
+	// Refer to https://thanos.io/tip/components/query.md/#deduplication-functions
+	// +kubebuilder:validation:Enum=penalty;chain
+	// +kubebuilder:validation:Optional
+	DeduplicationFunc *string `json:"deduplicationFunc,omitempty"`
+	// DiscoveryMode selects how the discovered StoreAPI Services are wired to the Querier.
+	// Label wires each Service according to its operator.thanos.io/endpoint* label, resolving the replicas behind
+	// Services without a group label with DNS SRV and querying all of them.
+	// EndpointGroup wires every Service as an endpoint group, resolved through gRPC DNS service discovery and
+	// load balanced round robin, so that each query reaches a single replica behind the Service. Strict Services stay strict.
+	// The ingesters of a ThanosReceive hold different series on each replica, so their Services are wired according
+	// to their label in both modes.
+	// +kubebuilder:validation:Enum=Label;EndpointGroup
+	// +kubebuilder:default=Label
+	// +kubebuilder:validation:Optional
+	DiscoveryMode *QueryDiscoveryMode `json:"discoveryMode,omitempty"`
+	// EndpointDiscoveryScheme selects how the Querier resolves the addresses of the discovered StoreAPI Services.
+	// dns resolves the A and AAAA records of the Services, reaching every replica behind headless Services.
+	// dnssrv resolves the SRV records of the gRPC port of the Services, which must be named.
+	// plain dials the Service addresses as they are, leaving the resolution to gRPC.
+	// Endpoint groups are always resolved by gRPC, only plain changes their addresses.
+	// +kubebuilder:validation:Enum=dns;dnssrv;plain
+	// +kubebuilder:default=dns
+	// +kubebuilder:validation:Optional
+	EndpointDiscoveryScheme *EndpointDiscoveryScheme `json:"endpointDiscoveryScheme,omitempty"`
 	// StoreLabelSelector enables adding additional labels to build a custom label selector
 	// for discoverable StoreAPIs. Values provided here will be appended to the default which are
 	// {"operator.thanos.io/store-api": "true", "app.kubernetes.io/part-of": "thanos"}.
@@ -43,6 +105,15 @@ type ThanosQuerySpec struct {
 	// TelemetryQuantiles is the configuration for the request telemetry quantiles.
 	// +kubebuilder:validation:Optional
 	TelemetryQuantiles *TelemetryQuantiles `json:"telemetryQuantiles,omitempty"`
+	// RequestLogging configures the logging of the HTTP and gRPC requests served by the Querier.
+	// If unset, requests are not logged.
+	// +kubebuilder:validation:Optional
+	RequestLogging *RequestLoggingConfig `json:"requestLogging,omitempty"`
+	// ActiveQueryTracking records the queries in progress in a file on an emptyDir volume,
+	// so that the queries that were running when a Querier crashed, for example because it ran out of memory,
+	// are logged when it restarts.
+	// +kubebuilder:validation:Optional
+	ActiveQueryTracking *bool `json:"activeQueryTracking,omitempty"`
 	// WebConfig is the configuration for the Query UI and API web options.
 	// +kubebuilder:validation:Optional
 	WebConfig *WebConfig `json:"webConfig,omitempty"`
@@ -58,6 +129,65 @@ type ThanosQuerySpec struct {
 	// will be performed on the underlying objects.
 	// +kubebuilder:validation:Optional
 	Paused *bool `json:"paused,omitempty"`
+	// DeletionProtection rejects the deletion of the ThanosQuery by the validating webhook of the operator,
+	// guarding against accidental deletions. It must be unset or set to false before the ThanosQuery can be deleted.
+	// The operator.thanos.io/deletion-protection annotation set to "true" protects the ThanosQuery too.
+	// +kubebuilder:validation:Optional
+	DeletionProtection *bool `json:"deletionProtection,omitempty"`
+	// TargetCluster is the name of the workload cluster in which the child resources are created.
+	// The cluster must be registered with the operator using the --target-cluster flag.
+	// If unset, the child resources are created in the cluster of this resource.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="targetCluster is immutable"
+	TargetCluster *string `json:"targetCluster,omitempty"`
+	// ReadProbe runs a synthetic probe that periodically issues an instant and a range query for a canary series
+	// through the Query Frontend, or the Querier if no Query Frontend is deployed, and checks their latency.
+	// The probe is run by the operator, which must be able to reach the Services of the ThanosQuery.
+	// The result of the latest probe is recorded in the status and in the ReadProbeSucceeded condition.
+	// +kubebuilder:validation:Optional
+	ReadProbe *ReadProbeSpec `json:"readProbe,omitempty"`
+	// HealthChecks enables health checks of the discovered StoreAPI endpoints by the operator, using the standard
+	// gRPC health service served by Thanos components. This catches endpoints that are reachable but not serving,
+	// which the readiness of the pods of the Querier does not reflect.
+	// Endpoints requiring client certificates are presented the client certificate of the Querier.
+	// The health of each endpoint is recorded in the status and in the EndpointsHealthy condition.
+	// Endpoints are not checked when the ThanosQuery is deployed to a target cluster.
+	// +kubebuilder:validation:Optional
+	HealthChecks *bool `json:"healthChecks,omitempty"`
+	// ArgsFile passes the flags of the Querier in an args file mounted from a ConfigMap instead of inline.
+	// This keeps the Deployment small and its diffs readable when there are many endpoints.
+	// Flags referencing environment variables are kept inline, as these are only expanded by Kubernetes.
+	// +kubebuilder:validation:Optional
+	ArgsFile *bool `json:"argsFile,omitempty"`
+	// GRPCServerTLS configures TLS for the gRPC server of the Querier, which serves the StoreAPI to other queriers.
+	// The Querier Service is labeled with operator.thanos.io/grpc-tls, so that other queriers connect to it over TLS.
+	// +kubebuilder:validation:Optional
+	GRPCServerTLS *TLSConfig `json:"grpcServerTLS,omitempty"`
+	// GRPCClientTLS configures TLS for the connections of the Querier to its StoreAPI endpoints.
+	// The Querier also connects over TLS when one of its endpoints advertises TLS with the operator.thanos.io/grpc-tls
+	// label, verifying server certificates against the system roots if this is not set.
+	// Thanos uses the same TLS configuration for every endpoint of a Querier, so endpoints that do not serve TLS
+	// are unreachable once TLS is used.
+	// +kubebuilder:validation:Optional
+	GRPCClientTLS *GRPCClientTLSConfig `json:"grpcClientTLS,omitempty"`
+	// ExternalEndpoints are StoreAPI endpoints outside of the cluster, such as Thanos sidecars of Prometheus servers
+	// running on virtual machines, as host:port.
+	// They are written to a file SD file mounted from a ConfigMap, which the Querier reloads when it changes,
+	// so that endpoints can be added and removed without rolling out the Querier.
+	// +kubebuilder:validation:items:Pattern=`^[^\s/:]+:[0-9]+$`
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	ExternalEndpoints []string `json:"externalEndpoints,omitempty"`
+	// Federation declares the StoreAPI endpoints of remote clusters, typically their Queriers, so that a global query
+	// layer federating several clusters is managed by a single ThanosQuery.
+	// The reachability of the remote endpoints from the operator is recorded in the status.
+	// +kubebuilder:validation:Optional
+	Federation *QueryFederationSpec `json:"federation,omitempty"`
+	// Ingress exposes the query UI and API outside of the cluster through an Ingress or a Gateway API HTTPRoute
+	// routing the given hosts to the Query Frontend Service, or to the Querier Service if there is no Query Frontend.
+	// +kubebuilder:validation:Optional
+	Ingress *IngressConfig `json:"ingress,omitempty"`
 	// Additional configuration for the Thanos components. Allows you to add
 	// additional args, containers, volumes, and volume mounts to Thanos Deployments,
 	// and StatefulSets. Ideal to use for things like sidecars.
@@ -65,9 +195,98 @@ type ThanosQuerySpec struct {
 	Additional `json:",inline"`
 }
 
+// QueryDiscoveryMode is how the discovered StoreAPI Services are wired to the Querier.
+type QueryDiscoveryMode string
+
+const (
+	// QueryDiscoveryModeLabel wires each Service according to its endpoint label.
+	QueryDiscoveryModeLabel QueryDiscoveryMode = "Label"
+	// QueryDiscoveryModeEndpointGroup wires the Services as endpoint groups.
+	QueryDiscoveryModeEndpointGroup QueryDiscoveryMode = "EndpointGroup"
+)
+
+// EndpointDiscoveryScheme is how the Querier resolves the addresses of the StoreAPI Services.
+type EndpointDiscoveryScheme string
+
+const (
+	// EndpointDiscoverySchemeDNS resolves the A and AAAA records of the Services.
+	EndpointDiscoverySchemeDNS EndpointDiscoveryScheme = "dns"
+	// EndpointDiscoverySchemeDNSSRV resolves the SRV records of the gRPC port of the Services.
+	EndpointDiscoverySchemeDNSSRV EndpointDiscoveryScheme = "dnssrv"
+	// EndpointDiscoverySchemePlain dials the Service addresses as they are.
+	EndpointDiscoverySchemePlain EndpointDiscoveryScheme = "plain"
+)
+
+// QueryFederationSpec declares the StoreAPI endpoints of remote clusters queried by the Querier.
+// +kubebuilder:validation:XValidation:rule="self.remotes.all(r, has(r.tlsSecret) == has(self.remotes[0].tlsSecret) && (!has(r.tlsSecret) || r.tlsSecret == self.remotes[0].tlsSecret))",message="all remotes must set the same tlsSecret, the Querier uses a single TLS configuration for its endpoints"
+type QueryFederationSpec struct {
+	// Remotes are the StoreAPI endpoints of the remote clusters.
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:Required
+	Remotes []RemoteQueryEndpoint `json:"remotes"`
+}
+
+// RemoteQueryEndpoint is the StoreAPI endpoint of a remote cluster.
+type RemoteQueryEndpoint struct {
+	// Name identifies the remote cluster in the status.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Address is the address of the gRPC server of the StoreAPI, as host:port.
+	// +kubebuilder:validation:Pattern=`^[^\s/:]+:[0-9]+$`
+	// +kubebuilder:validation:Required
+	Address string `json:"address"`
+	// TLSSecret is the name of a Secret in the namespace of the resource holding the client certificate presented
+	// to the remote in the tls.crt and tls.key keys, and the CA verifying its server certificate in the ca.crt key,
+	// such as a Secret issued by cert-manager.
+	// Thanos uses the same TLS configuration for every endpoint of a Querier, so all remotes must set the same Secret,
+	// and endpoints discovered in the cluster are only reachable if they serve TLS with a certificate signed by the same CA.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Optional
+	TLSSecret *string `json:"tlsSecret,omitempty"`
+	// Strict keeps the remote in the endpoint set of the Querier when its health checks fail,
+	// so that queries fail or return partial responses instead of silently ignoring the remote cluster.
+	// +kubebuilder:validation:Optional
+	Strict bool `json:"strict,omitempty"`
+	// Group resolves the address through gRPC DNS service discovery and load balances the requests round robin
+	// across the addresses behind it, so that each query reaches a single replica of the remote Queriers.
+	// +kubebuilder:validation:Optional
+	Group bool `json:"group,omitempty"`
+}
+
+// ReadProbeSpec is the configuration of the read path probe.
+type ReadProbeSpec struct {
+	// Query is the PromQL expression selecting the canary series. The probe fails if it returns no data.
+	// Defaults to the canary series written by the write probe of ThanosReceive.
+	// +kubebuilder:default="thanos_operator_write_probe"
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Optional
+	Query *string `json:"query,omitempty"`
+	// Interval between two probes.
+	// +kubebuilder:default="1m"
+	// +kubebuilder:validation:Optional
+	Interval *Duration `json:"interval,omitempty"`
+	// Range is the time range covered by the range query, ending at the time of the probe.
+	// +kubebuilder:default="1h"
+	// +kubebuilder:validation:Optional
+	Range *Duration `json:"range,omitempty"`
+	// InstantQueryLatencyThreshold is the latency above which the instant query fails the probe.
+	// +kubebuilder:default="1s"
+	// +kubebuilder:validation:Optional
+	InstantQueryLatencyThreshold *Duration `json:"instantQueryLatencyThreshold,omitempty"`
+	// RangeQueryLatencyThreshold is the latency above which the range query fails the probe.
+	// +kubebuilder:default="5s"
+	// +kubebuilder:validation:Optional
+	RangeQueryLatencyThreshold *Duration `json:"rangeQueryLatencyThreshold,omitempty"`
+}
+
 // QueryFrontendSpec defines the desired state of ThanosQueryFrontend
 type QueryFrontendSpec struct {
 	CommonFields `json:",inline"`
+	// DeploymentFields are the options available to all Thanos components managed as Deployments.
+	DeploymentFields `json:",inline"`
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:default=1
 	Replicas int32 `json:"replicas,omitempty"`
@@ -86,6 +305,9 @@ type QueryFrontendSpec struct {
 	// QueryRangeResponseCacheConfig holds the configuration for the query range response cache
 	// +kubebuilder:validation:Optional
 	QueryRangeResponseCacheConfig *CacheConfig `json:"queryRangeResponseCacheConfig,omitempty"`
+	// Cache deploys a memcached managed by the operator for the response cache.
+	// +kubebuilder:validation:Optional
+	Cache *ManagedCacheSpec `json:"cache,omitempty"`
 	// QueryRangeSplitInterval sets the split interval for query range
 	// +kubebuilder:validation:Optional
 	QueryRangeSplitInterval *Duration `json:"queryRangeSplitInterval,omitempty"`
@@ -103,10 +325,95 @@ type QueryFrontendSpec struct {
 	// LabelsDefaultTimeRange sets the default time range for label queries
 	// +kubebuilder:validation:Optional
 	LabelsDefaultTimeRange *Duration `json:"labelsDefaultTimeRange,omitempty"`
+	// QueryRangeMaxQueryParallelism sets the maximum number of split query range requests
+	// that are scheduled in parallel against the Queriers for a single incoming request.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	QueryRangeMaxQueryParallelism *int32 `json:"queryRangeMaxQueryParallelism,omitempty"`
+	// LabelsMaxQueryParallelism sets the maximum number of split label requests
+	// that are scheduled in parallel against the Queriers for a single incoming request.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	LabelsMaxQueryParallelism *int32 `json:"labelsMaxQueryParallelism,omitempty"`
+	// QueryRangeMaxQueryLength limits the time range of range queries. Longer queries are rejected.
+	// If unset, the time range of range queries is not limited.
+	// +kubebuilder:validation:Optional
+	QueryRangeMaxQueryLength *Duration `json:"queryRangeMaxQueryLength,omitempty"`
+	// DownstreamConfig configures the connections from the Query Frontend to the Queriers.
+	// +kubebuilder:validation:Optional
+	DownstreamConfig *QueryFrontendDownstreamConfig `json:"downstreamConfig,omitempty"`
+	// SessionAffinity routes the requests of a client to the same Query Frontend replica,
+	// so that long running UI sessions are not spread across replicas as they scale or roll.
+	// Requests are spread across replicas if not set.
+	// +kubebuilder:validation:Optional
+	SessionAffinity *SessionAffinityConfig `json:"sessionAffinity,omitempty"`
+	// Service configures how the Query Frontend Service is exposed, for example through a cloud load balancer.
+	// +kubebuilder:validation:Optional
+	Service *ServiceConfig `json:"service,omitempty"`
 	// Additional configuration for the Thanos components
 	Additional `json:",inline"`
 }
 
+// SessionAffinityConfig configures the client IP based session affinity of a Service.
+type SessionAffinityConfig struct {
+	// TimeoutSeconds is how long the requests of a client stick to the same replica after its last request.
+	// +kubebuilder:default:=10800
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=86400
+	// +kubebuilder:validation:Optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// QueryFrontendDownstreamConfig configures the connections from the Query Frontend to the Queriers.
+// Together with the max retries and max query parallelism settings, it bounds the amount of work
+// a single slow downstream can cause to pile up.
+type QueryFrontendDownstreamConfig struct {
+	// ResponseHeaderTimeout is the maximum time to wait for the response headers of a single
+	// attempt against a Querier. Attempts that time out are retried up to the configured max retries.
+	// +kubebuilder:validation:Optional
+	ResponseHeaderTimeout *Duration `json:"responseHeaderTimeout,omitempty"`
+	// IdleConnectionTimeout is the maximum time an idle connection to a Querier is kept open.
+	// +kubebuilder:validation:Optional
+	IdleConnectionTimeout *Duration `json:"idleConnectionTimeout,omitempty"`
+	// MaxConnectionsPerHost limits the number of concurrent connections to a single Querier.
+	// Requests above the limit wait for a connection to become available.
+	// 0 means no limit.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Optional
+	MaxConnectionsPerHost *int32 `json:"maxConnectionsPerHost,omitempty"`
+	// MaxIdleConnectionsPerHost limits the number of idle connections kept open to a single Querier.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Optional
+	MaxIdleConnectionsPerHost *int32 `json:"maxIdleConnectionsPerHost,omitempty"`
+}
+
+// RequestLoggingConfig configures the logging of the requests served by a Thanos component.
+// Refer to https://thanos.io/tip/thanos/logging.md/#request-logging
+type RequestLoggingConfig struct {
+	// HTTP configures the logging of the HTTP requests. If unset, HTTP requests are not logged.
+	// +kubebuilder:validation:Optional
+	HTTP *RequestLoggingOptions `json:"http,omitempty"`
+	// GRPC configures the logging of the gRPC requests. If unset, gRPC requests are not logged.
+	// +kubebuilder:validation:Optional
+	GRPC *RequestLoggingOptions `json:"grpc,omitempty"`
+}
+
+// RequestLoggingOptions configures when and at which level requests are logged.
+type RequestLoggingOptions struct {
+	// Level is the level of the request logs.
+	// +kubebuilder:validation:Enum=DEBUG;INFO;WARN;ERROR
+	// +kubebuilder:default=INFO
+	// +kubebuilder:validation:Optional
+	Level string `json:"level,omitempty"`
+	// LogStart logs requests when they start.
+	// +kubebuilder:validation:Optional
+	LogStart bool `json:"logStart,omitempty"`
+	// LogEnd logs requests when they end, with their duration and status.
+	// +kubebuilder:default=true
+	// +kubebuilder:validation:Optional
+	LogEnd *bool `json:"logEnd,omitempty"`
+}
+
 // TelemetryQuantiles is the configuration for the request telemetry quantiles.
 // Float usage is discouraged by controller-runtime, so we use string instead.
 type TelemetryQuantiles struct {
@@ -158,10 +465,67 @@ type ThanosQueryStatus struct {
 	Querier DeploymentStatus `json:"querierStatus,omitempty"`
 	// QueryFrontend is the status of the Query Frontend.
 	QueryFrontend DeploymentStatus `json:"queryFrontendStatus,omitempty"`
+	// EndpointCount is the number of StoreAPI endpoints discovered for the Querier.
+	// +kubebuilder:validation:Optional
+	EndpointCount int32 `json:"endpointCount,omitempty"`
+	// Endpoints are the StoreAPI endpoints discovered for the Querier.
+	// +kubebuilder:validation:Optional
+	Endpoints []QueryEndpointStatus `json:"endpoints,omitempty"`
+	// ObservedGeneration is the most recent generation of the ThanosQuery observed by the operator.
+	// +kubebuilder:validation:Optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// ReadProbe is the result of the latest read probe.
+	// +kubebuilder:validation:Optional
+	ReadProbe *ReadProbeStatus `json:"readProbe,omitempty"`
+	// Remotes is the reachability of the remote endpoints declared in the federation.
+	// +kubebuilder:validation:Optional
+	Remotes []RemoteEndpointStatus `json:"remotes,omitempty"`
+	// EndpointHealth is the health of the discovered StoreAPI endpoints at the last health check.
+	// +kubebuilder:validation:Optional
+	EndpointHealth []EndpointHealthStatus `json:"endpointHealth,omitempty"`
+}
+
+// RemoteEndpointStatus is the reachability of a remote endpoint from the operator.
+type RemoteEndpointStatus struct {
+	// Name is the name of the remote.
+	Name string `json:"name"`
+	// Address is the address of the remote.
+	Address string `json:"address"`
+	// Reachable is true if the operator could open a connection to the remote at the last check.
+	Reachable bool `json:"reachable"`
+	// LastCheckTime is the time of the last check.
+	LastCheckTime metav1.Time `json:"lastCheckTime"`
+	// Message is the reason the remote is unreachable.
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+}
+
+// ReadProbeStatus is the result of a read probe.
+type ReadProbeStatus struct {
+	// LastProbeTime is the time at which the probe ran.
+	LastProbeTime metav1.Time `json:"lastProbeTime"`
+	// InstantQueryLatency is the latency of the instant query. Not set if the query failed.
+	// +kubebuilder:validation:Optional
+	InstantQueryLatency *metav1.Duration `json:"instantQueryLatency,omitempty"`
+	// RangeQueryLatency is the latency of the range query. Not set if the query failed or did not run.
+	// +kubebuilder:validation:Optional
+	RangeQueryLatency *metav1.Duration `json:"rangeQueryLatency,omitempty"`
+}
+
+// QueryEndpointStatus is a StoreAPI endpoint discovered for the Querier.
+type QueryEndpointStatus struct {
+	// Name is the name of the Service exposing the StoreAPI.
+	Name string `json:"name"`
+	// Type is the endpoint label of the Service, which sets how the Querier connects to it.
+	Type string `json:"type"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.querierStatus.replicas,selectorpath=.status.querierStatus.selector
+//+kubebuilder:storageversion
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // ThanosQuery is the Schema for the thanosqueries API
 type ThanosQuery struct {
@@ -17,6 +17,9 @@ limitations under the License.
 package v1alpha1
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -43,6 +46,16 @@ const (
 	ReplicationProtocolCapnProto ReplicationProtocol = "capnproto"
 )
 
+// HashringConfigReloadStrategy defines how the routers pick up changes to the hashring configuration.
+type HashringConfigReloadStrategy string
+
+const (
+	// HashringConfigReloadWatch relies on the routers watching the mounted hashring configuration file.
+	HashringConfigReloadWatch HashringConfigReloadStrategy = "Watch"
+	// HashringConfigReloadRollout rolls the routers out whenever the hashring configuration changes.
+	HashringConfigReloadRollout HashringConfigReloadStrategy = "Rollout"
+)
+
 // GRPCCompression defines the compression algorithm for gRPC communication.
 type GRPCCompression string
 
@@ -54,25 +67,44 @@ const (
 )
 
 // RouterSpec represents the configuration for the router
+// +kubebuilder:validation:XValidation:rule="!has(self.existingService) || (!has(self.service) && !has(self.serviceTraffic))",message="service and serviceTraffic cannot be set with existingService"
+// +kubebuilder:validation:XValidation:rule="!has(self.existingHashringConfigMap) || !has(self.hashringConfigReload) || self.hashringConfigReload != 'Rollout'",message="hashringConfigReload cannot be Rollout with existingHashringConfigMap"
 type RouterSpec struct {
 	// CommonFields are the options available to all Thanos components.
 	// +kubebuilder:validation:Optional
 	CommonFields `json:",inline"`
-	// Replicas is the number of router replicas.
+	// DeploymentFields are the options available to all Thanos components managed as Deployments.
+	DeploymentFields `json:",inline"`
+	// Replicas is the number of router replicas. It is exposed through the scale subresource of the ThanosReceive,
+	// so that a HorizontalPodAutoscaler can target the ThanosReceive to scale the routers.
+	// If unset, the replicas of the router Deployment are managed externally, for example by a HorizontalPodAutoscaler
+	// targeting the Deployment, and the operator leaves them unchanged.
 	// +kubebuilder:validation:Minimum=1
-	// +kubebuilder:default=1
-	// +kubebuilder:validation:Required
-	Replicas int32 `json:"replicas,omitempty"`
+	// +kubebuilder:validation:Optional
+	Replicas *int32 `json:"replicas,omitempty"`
 	// ReplicationFactor is the replication factor for the router.
 	// +kubebuilder:default=1
 	// +kubebuilder:validation:Enum=1;3;5
 	// +kubebuilder:validation:Required
 	ReplicationFactor int32 `json:"replicationFactor,omitempty"`
 	// ReplicationProtocol is the protocol for remote write replication.
+	// The capnproto protocol requires Thanos v0.35.0 or later on the routers and the ingesters it is used with.
 	// +kubebuilder:default="grpc"
 	// +kubebuilder:validation:Enum=grpc;capnproto
 	// +kubebuilder:validation:Optional
 	ReplicationProtocol *ReplicationProtocol `json:"replicationProtocol,omitempty"`
+	// AsyncForwardWorkerCount is the number of concurrent workers of each router forwarding remote write requests
+	// to the ingesters. The Thanos default is used if not set.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	AsyncForwardWorkerCount *uint64 `json:"asyncForwardWorkerCount,omitempty"`
+	// ReplicationMaxRetries is the number of times a router retries replicating a remote write request to an ingester
+	// that is unavailable. Retries only apply to the grpc replication protocol and are disabled when set to 0.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=4
+	// +kubebuilder:validation:Optional
+	ReplicationMaxRetries *int32 `json:"replicationMaxRetries,omitempty"`
 	// HashringPolicy defines the policy for how the hashring is built and maintained at runtime.
 	// +kubebuilder:default="static"
 	// +kubebuilder:validation:Enum=static;dynamic
@@ -82,6 +114,63 @@ type RouterSpec struct {
 	// +kubebuilder:default={receive: "true"}
 	// +kubebuilder:validation:Required
 	ExternalLabels ExternalLabels `json:"externalLabels,omitempty"`
+	// Tenancy configures how the routers determine the tenant of remote write requests.
+	// The Thanos defaults are used for the fields that are not set.
+	// +kubebuilder:validation:Optional
+	Tenancy *RouterTenancyConfig `json:"tenancy,omitempty"`
+	// RemoteWriteTLS configures TLS for the remote write endpoint served by the router.
+	// When a client CA is set, producers must authenticate with a client certificate signed by that CA.
+	// +kubebuilder:validation:Optional
+	RemoteWriteTLS *TLSConfig `json:"remoteWriteTLS,omitempty"`
+	// ServiceTraffic configures how in-cluster traffic is routed by the router Service.
+	// This allows in-cluster producers to prefer routers in their own zone, reducing the cross-zone
+	// network cost of the write path.
+	// +kubebuilder:validation:Optional
+	ServiceTraffic *ServiceTrafficConfig `json:"serviceTraffic,omitempty"`
+	// Service configures how the router Service is exposed, for example to accept remote writes from outside
+	// of the cluster through a cloud load balancer.
+	// +kubebuilder:validation:Optional
+	Service *ServiceConfig `json:"service,omitempty"`
+	// ExistingService is the name of an existing Service in the namespace of the resource that exposes the routers.
+	// If set, the operator does not create or update the router Service, and the write probe writes through
+	// the existing Service on port 19291.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Optional
+	ExistingService *string `json:"existingService,omitempty"`
+	// ExistingHashringConfigMap is the name of an existing ConfigMap in the namespace of the resource holding
+	// the hashring configuration of the routers under the hashrings.json key.
+	// If set, the routers read their hashrings from it and the operator does not create or update it,
+	// leaving the hashrings to be managed outside of the operator.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Optional
+	ExistingHashringConfigMap *string `json:"existingHashringConfigMap,omitempty"`
+	// HashringConfigReload defines how the routers pick up changes to the hashring configuration.
+	// With Watch, the routers re-read the mounted configuration file once the kubelet has synced the ConfigMap,
+	// which may take a few minutes. With Rollout, the hash of the configuration is set on the pod template of the
+	// routers, so that every change rolls the routers out and is in effect once the rollout completes.
+	// +kubebuilder:default=Watch
+	// +kubebuilder:validation:Enum=Watch;Rollout
+	// +kubebuilder:validation:Optional
+	HashringConfigReload *HashringConfigReloadStrategy `json:"hashringConfigReload,omitempty"`
+	// Ingress exposes the remote write endpoint of the routers outside of the cluster through an Ingress or a
+	// Gateway API HTTPRoute routing the given hosts to the router Service.
+	// +kubebuilder:validation:Optional
+	Ingress *IngressConfig `json:"ingress,omitempty"`
+	// RelabelConfigs are relabeling rules applied by the routers to the series of remote write requests
+	// before they are forwarded to the ingesters, for example to drop series or labels.
+	// The rules are applied in order, with the semantics of Prometheus relabeling.
+	// +kubebuilder:validation:MaxItems=100
+	// +kubebuilder:validation:Optional
+	RelabelConfigs []RelabelConfig `json:"relabelConfigs,omitempty"`
+	// Groups are additional groups of routers, for example for a region or a class of tenants, so that
+	// routing load can be isolated without duplicating the ThanosReceive.
+	// Each group is a Deployment of its own with its own Service, configured like the routers of the routerSpec
+	// and sharing their hashring configuration and limits.
+	// +kubebuilder:validation:MaxItems=20
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=name
+	Groups []RouterGroupSpec `json:"groups,omitempty"`
 	// Additional configuration for the Thanos components. Allows you to add
 	// additional args, containers, volumes, and volume mounts to Thanos Deployments,
 	// and StatefulSets. Ideal to use for things like sidecars.
@@ -89,6 +178,127 @@ type RouterSpec struct {
 	Additional `json:",inline"`
 }
 
+// RouterGroupSpec is a group of routers sharing the hashring configuration of the ThanosReceive.
+type RouterGroupSpec struct {
+	// Name is the name of the group.
+	// Name will be used to generate the names for the resources created for the group.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// Replicas is the number of routers of the group.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Required
+	Replicas int32 `json:"replicas,omitempty"`
+	// Tenancy configures how the routers of the group determine the tenant of remote write requests.
+	// The fields that are not set are taken from the tenancy of the routerSpec.
+	// +kubebuilder:validation:Optional
+	Tenancy *RouterTenancyConfig `json:"tenancy,omitempty"`
+	// Service configures how the Service of the group is exposed.
+	// The Service is of type ClusterIP if not set.
+	// +kubebuilder:validation:Optional
+	Service *ServiceConfig `json:"service,omitempty"`
+}
+
+// RelabelAction is the action of a relabeling rule.
+// +kubebuilder:validation:Enum=replace;keep;drop;keepequal;dropequal;hashmod;labelmap;labeldrop;labelkeep;lowercase;uppercase
+type RelabelAction string
+
+// RelabelConfig is a Prometheus relabeling rule.
+// See https://prometheus.io/docs/prometheus/latest/configuration/configuration/#relabel_config for the semantics of the fields.
+// +kubebuilder:validation:XValidation:rule="!(self.action in ['hashmod', 'replace', 'keepequal', 'dropequal', 'lowercase', 'uppercase']) || has(self.targetLabel)",message="targetLabel is required for the replace, keepequal, dropequal, hashmod, lowercase and uppercase actions"
+// +kubebuilder:validation:XValidation:rule="self.action != 'hashmod' || (has(self.modulus) && self.modulus > 0)",message="modulus is required for the hashmod action"
+type RelabelConfig struct {
+	// SourceLabels are the labels whose values are concatenated with the separator and matched against the regex.
+	// +kubebuilder:validation:Optional
+	SourceLabels []string `json:"sourceLabels,omitempty"`
+	// Separator is placed between the values of the source labels. Defaults to ;.
+	// +kubebuilder:validation:Optional
+	Separator *string `json:"separator,omitempty"`
+	// TargetLabel is the label written by the replace, hashmod, lowercase and uppercase actions.
+	// +kubebuilder:validation:Optional
+	TargetLabel *string `json:"targetLabel,omitempty"`
+	// Regex is the regular expression matched against the concatenated source label values. Defaults to (.*).
+	// +kubebuilder:validation:Optional
+	Regex *string `json:"regex,omitempty"`
+	// Modulus is the modulus of the hash of the source label values for the hashmod action.
+	// +kubebuilder:validation:Optional
+	Modulus *uint64 `json:"modulus,omitempty"`
+	// Replacement is the value written to the target label by the replace action, with regex capture groups expanded.
+	// Defaults to $1.
+	// +kubebuilder:validation:Optional
+	Replacement *string `json:"replacement,omitempty"`
+	// Action is the action performed by the rule.
+	// +kubebuilder:default=replace
+	// +kubebuilder:validation:Optional
+	Action RelabelAction `json:"action,omitempty"`
+}
+
+// ServiceTopologyMode is the topology aware routing mode of a Service.
+type ServiceTopologyMode string
+
+const (
+	// ServiceTopologyModeAuto enables topology aware routing, endpoints are allocated proportionally to each zone.
+	ServiceTopologyModeAuto ServiceTopologyMode = "Auto"
+	// ServiceTopologyModeDisabled disables topology aware routing.
+	ServiceTopologyModeDisabled ServiceTopologyMode = "Disabled"
+)
+
+// ServiceTrafficConfig configures how in-cluster traffic is routed by a Service.
+type ServiceTrafficConfig struct {
+	// TopologyMode sets the service.kubernetes.io/topology-mode annotation on the Service.
+	// When set to Auto, kube-proxy prefers endpoints in the same zone as the client.
+	// See https://kubernetes.io/docs/concepts/services-networking/topology-aware-routing/
+	// +kubebuilder:validation:Enum=Auto;Disabled
+	// +kubebuilder:validation:Optional
+	TopologyMode *ServiceTopologyMode `json:"topologyMode,omitempty"`
+	// InternalTrafficPolicy describes how nodes distribute traffic they receive on the ClusterIP.
+	// When set to Local, traffic is only routed to endpoints on the same node as the client.
+	// See https://kubernetes.io/docs/concepts/services-networking/service-traffic-policy/
+	// +kubebuilder:validation:Enum=Cluster;Local
+	// +kubebuilder:validation:Optional
+	InternalTrafficPolicy *corev1.ServiceInternalTrafficPolicy `json:"internalTrafficPolicy,omitempty"`
+}
+
+// ScaleDownStrategy defines what happens to the resources of a hashring that is removed from the spec.
+// +kubebuilder:validation:Enum=Delete;Orphan
+type ScaleDownStrategy string
+
+const (
+	// ScaleDownStrategyDelete deletes the resources of removed hashrings.
+	ScaleDownStrategyDelete ScaleDownStrategy = "Delete"
+	// ScaleDownStrategyOrphan releases the resources of removed hashrings from the ThanosReceive without deleting them.
+	ScaleDownStrategyOrphan ScaleDownStrategy = "Orphan"
+)
+
+// HashringRolloutStrategy defines how changes to the pods of the ingesters are rolled out across hashrings.
+// +kubebuilder:validation:Enum=Parallel;Sequential
+type HashringRolloutStrategy string
+
+const (
+	// HashringRolloutStrategyParallel rolls out the changes to all hashrings at once.
+	HashringRolloutStrategyParallel HashringRolloutStrategy = "Parallel"
+	// HashringRolloutStrategySequential rolls out the changes to one hashring at a time, in the order of the spec.
+	HashringRolloutStrategySequential HashringRolloutStrategy = "Sequential"
+)
+
+// HashringRolloutSpec configures how changes to the pods of the ingesters are rolled out across hashrings.
+type HashringRolloutSpec struct {
+	// Strategy is the rollout strategy.
+	// Sequential rolls out a change to one hashring at a time, in the order of the hashrings in the spec.
+	// The next hashring is only updated once all the ingesters of the previous one are updated and ready,
+	// and have rejoined the hashring configuration of the router.
+	// +kubebuilder:default=Parallel
+	// +kubebuilder:validation:Optional
+	Strategy HashringRolloutStrategy `json:"strategy,omitempty"`
+	// Paused stops a Sequential rollout from moving to the next hashring.
+	// The hashrings that have not started rolling out keep running their current pods.
+	// +kubebuilder:validation:Optional
+	Paused bool `json:"paused,omitempty"`
+}
+
 // IngesterSpec represents the configuration for the ingestor
 type IngesterSpec struct {
 	// DefaultObjectStorageConfig is the secret that contains the object storage configuration for the ingest components.
@@ -101,6 +311,63 @@ type IngesterSpec struct {
 	// +listType=map
 	// +listMapKey=name
 	Hashrings []IngesterHashringSpec `json:"hashrings,omitempty"`
+	// HashringUpdateInterval is the minimum time between two updates of the hashring configuration of the routers
+	// that add or change endpoints. Endpoints joining the hashrings within the interval, such as during a rolling
+	// restart of the ingesters, and changes to the zones and Cap'n Proto addresses of the endpoints are applied in
+	// a single update once the interval has passed since the last update.
+	// Endpoints leaving the hashrings, and changes to the hashrings, tenants and algorithms in the spec, are
+	// always applied straight away.
+	// Every change is applied straight away if not set.
+	// +kubebuilder:validation:Optional
+	HashringUpdateInterval *Duration `json:"hashringUpdateInterval,omitempty"`
+	// ShutdownDrainSeconds is the number of seconds an ingester keeps serving after it is asked to terminate.
+	// A terminating ingester is removed from the hashring as soon as it stops being ready, and the
+	// drain period gives the routers time to reload the hashring before the ingester shuts down,
+	// reducing write errors during voluntary restarts.
+	// The drain period counts towards terminationGracePeriodSeconds.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	ShutdownDrainSeconds *int64 `json:"shutdownDrainSeconds,omitempty"`
+	// SpreadAcrossZones requires the ingesters of each hashring to be spread evenly across the zones of the
+	// topology.kubernetes.io/zone node label. Ingesters that would skew the spread are left pending.
+	// With a storage class using the WaitForFirstConsumer volume binding mode, volumes are provisioned in the zone
+	// of their ingester, so the volumes of a hashring are spread across zones too.
+	// +kubebuilder:validation:Optional
+	SpreadAcrossZones *bool `json:"spreadAcrossZones,omitempty"`
+	// ScaleDownStrategy controls what happens to the StatefulSet, Services and ServiceAccount of a hashring
+	// that is removed from the spec.
+	// Delete deletes them, once the prune grace period of the operator has expired if one is set.
+	// Orphan removes their owner references and owner label, so that they are no longer managed
+	// nor garbage collected with the ThanosReceive, and keeps them for manual removal.
+	// +kubebuilder:default=Delete
+	// +kubebuilder:validation:Optional
+	ScaleDownStrategy *ScaleDownStrategy `json:"scaleDownStrategy,omitempty"`
+	// UploadLagChecks enables checks of the upload lag of the running ingesters by the operator, which scrapes
+	// their shipper and TSDB metrics. The ingesters are scraped by Pod IP, so they are not checked when the
+	// ThanosReceive is deployed to a target cluster.
+	// The upload lag of each hashring is recorded in the status and in the UploadLagDegraded condition.
+	// +kubebuilder:validation:Optional
+	UploadLagChecks *bool `json:"uploadLagChecks,omitempty"`
+	// UploadLagThreshold is the upload lag of a hashring above which the UploadLagDegraded condition is set.
+	// Blocks that have not been uploaded to object storage only exist on the data volumes of the ingesters,
+	// so a high upload lag is the amount of data that would be lost if a volume disappeared.
+	// Ingesters cut a block every two hours, so the threshold should leave room for a block to be cut and uploaded.
+	// +kubebuilder:default="3h"
+	// +kubebuilder:validation:Optional
+	UploadLagThreshold *Duration `json:"uploadLagThreshold,omitempty"`
+	// HealthChecks enables health checks of the running ingesters by the operator, using the standard gRPC health
+	// service served by Thanos. The ingesters are checked by Pod IP, so they are not checked when the ThanosReceive
+	// is deployed to a target cluster.
+	// Ingesters requiring client certificates are presented the certificate of grpcTLS.client.certSecret.
+	// The generated NetworkPolicies do not allow the operator to connect to the ingesters, so it must be allowed
+	// separately when they are used.
+	// The health of each ingester is recorded in the status and in the EndpointsHealthy condition.
+	// +kubebuilder:validation:Optional
+	HealthChecks *bool `json:"healthChecks,omitempty"`
+	// Rollout configures how changes to the pods of the ingesters, such as a new Thanos version, are rolled out
+	// across hashrings.
+	// +kubebuilder:validation:Optional
+	Rollout *HashringRolloutSpec `json:"rollout,omitempty"`
 	// Additional configuration for the Thanos components. Allows you to add
 	// additional args, containers, volumes, and volume mounts to Thanos Deployments,
 	// and StatefulSets. Ideal to use for things like sidecars.
@@ -108,7 +375,39 @@ type IngesterSpec struct {
 	Additional `json:",inline"`
 }
 
+// HashringEndpointPolicy defines which ingesters of a hashring are published in the hashring configuration.
+type HashringEndpointPolicy string
+
+const (
+	// HashringEndpointPolicyReadyOnly publishes the ingesters that are ready and not terminating.
+	HashringEndpointPolicyReadyOnly HashringEndpointPolicy = "ReadyOnly"
+	// HashringEndpointPolicyAll publishes every ingester with an endpoint, whether it is ready or not.
+	HashringEndpointPolicyAll HashringEndpointPolicy = "All"
+)
+
+// HashringAutoscalingSpec configures a hashring whose replicas are managed externally, such as by a
+// HorizontalPodAutoscaler or KEDA targeting the StatefulSet of the hashring.
+// +kubebuilder:validation:XValidation:rule="self.minReplicas <= self.maxReplicas",message="minReplicas cannot be greater than maxReplicas"
+type HashringAutoscalingSpec struct {
+	// MinReplicas is the lower bound of the replicas of the hashring.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Required
+	MinReplicas int32 `json:"minReplicas"`
+	// MaxReplicas is the upper bound of the replicas of the hashring.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Required
+	MaxReplicas int32 `json:"maxReplicas"`
+	// ConfigDebounce is how long the replicas of the hashring must differ from those in the hashring configuration
+	// before new ingesters are added to it, so that short lived scale ups do not reshuffle the hashring.
+	// Ingesters removed by a scale down are left out of the hashring configuration straight away.
+	// +kubebuilder:default="1m"
+	// +kubebuilder:validation:Optional
+	ConfigDebounce *Duration `json:"configDebounce,omitempty"`
+}
+
 // IngesterHashringSpec represents the configuration for a hashring to be used by the Thanos Receive StatefulSet.
+// +kubebuilder:validation:XValidation:rule="!has(self.minReadyReplicas) || self.minReadyReplicas <= self.replicas",message="minReadyReplicas cannot be greater than replicas"
+// +kubebuilder:validation:XValidation:rule="!has(self.autoscaling) || !has(self.scaleDownGracePeriod)",message="scaleDownGracePeriod cannot be set on an autoscaled hashring"
 type IngesterHashringSpec struct {
 	// CommonFields are the options available to all Thanos components.
 	// +kubebuilder:validation:Optional
@@ -136,8 +435,15 @@ type IngesterHashringSpec struct {
 	// +kubebuilder:validation:Optional
 	ObjectStorageConfig *ObjectStorageConfig `json:"objectStorageConfig,omitempty"`
 	// StorageConfiguration represents the storage to be used by the Thanos Receive StatefulSets.
+	// Increasing the size expands the existing data volumes of the hashring, if their storage class allows
+	// volume expansion. Volumes cannot be shrunk.
 	// +kubebuilder:validation:Required
 	StorageConfiguration StorageConfiguration `json:"storage"`
+	// PersistentVolumeClaimRetentionPolicy controls whether the data volumes of the hashring are deleted when
+	// the hashring is scaled down or its StatefulSet is deleted.
+	// It overrides the persistentVolumeClaimRetentionPolicy of the ThanosReceive.
+	// +kubebuilder:validation:Optional
+	PersistentVolumeClaimRetentionPolicy *PersistentVolumeClaimRetentionPolicy `json:"persistentVolumeClaimRetentionPolicy,omitempty"`
 	// TenancyConfig is the configuration for the tenancy options.
 	// +kubebuilder:validation:Optional
 	TenancyConfig *TenancyConfig `json:"tenancyConfig,omitempty"`
@@ -162,6 +468,106 @@ type IngesterHashringSpec struct {
 	// +kubebuilder:default="ketama"
 	// +kubebuilder:validation:Enum=ketama;hashmod
 	HashingAlgorithm *string `json:"hashingAlgorithm,omitempty"`
+	// EndpointAddress controls the addresses of the hashring members written to the hashring configuration.
+	// This allows hashrings running different Thanos versions or port layouts to coexist, for example during upgrades.
+	// +kubebuilder:validation:Optional
+	EndpointAddress *EndpointAddressConfig `json:"endpointAddress,omitempty"`
+	// ServiceAccount configures the ServiceAccount of the ingesters of the hashring.
+	// Every hashring has its own ServiceAccount, so that hashrings writing to different buckets
+	// can be bound to different cloud IAM roles with workload identity.
+	// +kubebuilder:validation:Optional
+	ServiceAccount *ServiceAccountConfig `json:"serviceAccount,omitempty"`
+	// UpdateStrategy is the strategy used to update the ingesters of the hashring.
+	// It overrides the updateStrategy of the ThanosReceive, so that upgrades can be staged hashring by hashring.
+	// Unless rollingUpdate.maxUnavailable is set, it is derived from the replication factor of the routers.
+	// +kubebuilder:validation:XValidation:rule="!has(self.rollingUpdate) || !has(self.type) || self.type == 'RollingUpdate'",message="rollingUpdate can only be set with the RollingUpdate strategy"
+	// +kubebuilder:validation:Optional
+	UpdateStrategy *appsv1.StatefulSetUpdateStrategy `json:"updateStrategy,omitempty"`
+	// EndpointPolicy defines which ingesters of the hashring are published in the hashring configuration.
+	// ReadyOnly publishes the ingesters that are ready and not terminating. All publishes every ingester with an
+	// endpoint, so that the members of the hashring do not change while ingesters restart.
+	// +kubebuilder:default=ReadyOnly
+	// +kubebuilder:validation:Enum=ReadyOnly;All
+	// +kubebuilder:validation:Optional
+	EndpointPolicy *HashringEndpointPolicy `json:"endpointPolicy,omitempty"`
+	// MinReadyReplicas is the number of ready ingesters the hashring needs before its configuration is updated.
+	// While fewer ingesters are ready, the routers keep the last configuration of the hashring, and a new hashring
+	// is not added to the configuration. The hashring policy of the router applies on top of it.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	MinReadyReplicas *int32 `json:"minReadyReplicas,omitempty"`
+	// ScaleDownGracePeriod enables the graceful scale down of the hashring.
+	// When the replicas are decreased, the ingesters to be removed are first left out of the hashring configuration,
+	// and the StatefulSet is only scaled down once the grace period has passed, so that the routers have stopped
+	// forwarding writes to them before they flush and upload their blocks on shutdown.
+	// The StatefulSet is scaled down straight away if not set.
+	// +kubebuilder:validation:Optional
+	ScaleDownGracePeriod *Duration `json:"scaleDownGracePeriod,omitempty"`
+	// UploadConcurrency is the number of goroutines the ingesters use to upload the files of a block to object storage.
+	// Lowering it limits the egress of the ingesters when many blocks are uploaded at once, such as after an outage
+	// of object storage. Thanos uploads the files of a block sequentially if not set.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	UploadConcurrency *int32 `json:"uploadConcurrency,omitempty"`
+	// Autoscaling hands the replicas of the hashring over to an external autoscaler.
+	// The operator keeps the replicas of the StatefulSet within the bounds and converges the hashring configuration
+	// to the ingesters created by the autoscaler. Replicas is then only used when the StatefulSet is created.
+	// +kubebuilder:validation:Optional
+	Autoscaling *HashringAutoscalingSpec `json:"autoscaling,omitempty"`
+	// AdditionalArgs are additional arguments to pass to the ingesters of the hashring.
+	// They are applied after the additionalArgs of the ingesterSpec and override them if there is a conflict.
+	// +kubebuilder:validation:items:Pattern=`^--[a-z]`
+	// +kubebuilder:validation:Optional
+	AdditionalArgs []string `json:"additionalArgs,omitempty"`
+	// AdditionalVolumes are additional volumes to add to the ingesters of the hashring, such as the credentials
+	// of the object storage of the hashring. They are added after the additionalVolumes of the ingesterSpec.
+	// +kubebuilder:validation:Optional
+	AdditionalVolumes []corev1.Volume `json:"additionalVolumes,omitempty"`
+	// AdditionalVolumeMounts are additional volume mounts to add to the Thanos container of the ingesters of the hashring.
+	// They are added after the additionalVolumeMounts of the ingesterSpec.
+	// +kubebuilder:validation:Optional
+	AdditionalVolumeMounts []corev1.VolumeMount `json:"additionalVolumeMounts,omitempty"`
+}
+
+// EndpointHostFormat defines how the host of a hashring member address is built.
+type EndpointHostFormat string
+
+const (
+	// EndpointHostFormatHostname addresses members by their stable DNS name, <pod>.<service>.<namespace>.svc.
+	EndpointHostFormatHostname EndpointHostFormat = "Hostname"
+	// EndpointHostFormatIP addresses members by their Pod IP.
+	EndpointHostFormatIP EndpointHostFormat = "IP"
+)
+
+// EndpointAddressConfig controls the addresses of the hashring members.
+// Fields that are not set default to the router configuration and the default gRPC and Cap'n Proto ports.
+// The ingesters of the hashring serve gRPC and Cap'n Proto on the configured ports, which are also the ports
+// of their Service.
+// +kubebuilder:validation:XValidation:rule="!has(self.capnProtoPort) || !has(self.replicationProtocol) || self.replicationProtocol == 'capnproto'",message="capnProtoPort can only be set when the replication protocol is capnproto"
+// +kubebuilder:validation:XValidation:rule="!has(self.grpcPort) || !has(self.capnProtoPort) || self.grpcPort != self.capnProtoPort",message="grpcPort and capnProtoPort must differ"
+type EndpointAddressConfig struct {
+	// ReplicationProtocol overrides the router replication protocol for the members of this hashring.
+	// Set it to grpc for hashrings running a Thanos version without Cap'n Proto support.
+	// +kubebuilder:validation:Enum=grpc;capnproto
+	// +kubebuilder:validation:Optional
+	ReplicationProtocol *ReplicationProtocol `json:"replicationProtocol,omitempty"`
+	// HostFormat defines how the host of the member addresses is built.
+	// +kubebuilder:validation:Enum=Hostname;IP
+	// +kubebuilder:default=Hostname
+	// +kubebuilder:validation:Optional
+	HostFormat *EndpointHostFormat `json:"hostFormat,omitempty"`
+	// GRPCPort is the port the members serve gRPC on, and the port of their gRPC address.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:validation:XValidation:rule="self != 10902 && self != 19291",message="grpcPort must differ from the HTTP port 10902 and the remote write port 19291 of the ingesters"
+	// +kubebuilder:validation:Optional
+	GRPCPort *int32 `json:"grpcPort,omitempty"`
+	// CapnProtoPort is the port the members serve Cap'n Proto replication on, and the port of their Cap'n Proto address.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:validation:XValidation:rule="self != 10902 && self != 19291",message="capnProtoPort must differ from the HTTP port 10902 and the remote write port 19291 of the ingesters"
+	// +kubebuilder:validation:Optional
+	CapnProtoPort *int32 `json:"capnProtoPort,omitempty"`
 }
 
 // TenancyConfig is the configuration for the tenancy options.
@@ -171,9 +577,24 @@ type TenancyConfig struct {
 	// +kubebuilder:validation:Optional
 	Tenants []string `json:"tenants,omitempty"`
 	// TenantMatcherType is the type of tenant matching to use.
+	// With exact, tenants are matched by name and cannot contain the *, ? and [ glob characters.
+	// With glob, tenants are shell patterns, for example team-*.
 	// +kubebuilder:default:="exact"
 	// +kubebuilder:validation:Enum=exact;glob
 	TenantMatcherType string `json:"tenantMatcherType,omitempty"`
+	// ExcludeTenants are tenants that must not be routed to this hashring, such as the tenants
+	// with a dedicated hashring when this hashring matches all the others.
+	// The router routes a tenant to the first hashring matching it, so this hashring is placed after
+	// the hashrings listing the excluded tenants. Each excluded tenant must be listed by another hashring.
+	// +kubebuilder:validation:Optional
+	ExcludeTenants []string `json:"excludeTenants,omitempty"`
+	// Priority orders the hashring in the configuration of the routers, which route the writes of a tenant to the
+	// first hashring matching it. Hashrings with a higher priority come first, so that they take precedence over the
+	// hashrings whose tenants overlap with theirs, such as a hashring matching all tenants or a broader glob.
+	// Hashrings with the same priority are ordered by name, and excluded tenants are always honoured.
+	// +kubebuilder:default=0
+	// +kubebuilder:validation:Optional
+	Priority int32 `json:"priority,omitempty"`
 	// TenantHeader is the HTTP header to determine tenant for write requests.
 	// +kubebuilder:default="THANOS-TENANT"
 	TenantHeader string `json:"tenantHeader,omitempty"`
@@ -192,8 +613,28 @@ type TenancyConfig struct {
 	TenantLabelName string `json:"tenantLabelName,omitempty"`
 }
 
+// RouterTenancyConfig configures how the routers determine the tenant of remote write requests.
+type RouterTenancyConfig struct {
+	// TenantHeader is the HTTP header holding the tenant of remote write requests.
+	// This allows the routers to sit behind gateways that forward the tenant in a header of their own.
+	// +kubebuilder:validation:XValidation:rule="self != ''",message="tenantHeader must not be empty"
+	// +kubebuilder:validation:Optional
+	TenantHeader *string `json:"tenantHeader,omitempty"`
+	// DefaultTenantID is the tenant of remote write requests that do not carry the tenant header.
+	// +kubebuilder:validation:XValidation:rule="self != ''",message="defaultTenantID must not be empty"
+	// +kubebuilder:validation:Optional
+	DefaultTenantID *string `json:"defaultTenantID,omitempty"` //nolint:tagliatelle
+	// TenantLabelName is the name of the label holding the tenant, added to the series of each tenant.
+	// +kubebuilder:validation:XValidation:rule="self.matches('^[a-zA-Z_][a-zA-Z0-9_]*$')",message="tenantLabelName must be a valid label name"
+	// +kubebuilder:validation:Optional
+	TenantLabelName *string `json:"tenantLabelName,omitempty"`
+}
+
 // ThanosReceiveSpec defines the desired state of ThanosReceive
 // +kubebuilder:validation:XValidation:rule="self.ingesterSpec.hashrings.all(h, h.replicas >= self.routerSpec.replicationFactor )", message=" Ingester replicas must be greater than or equal to the Router replicas"
+// +kubebuilder:validation:XValidation:rule="self.ingesterSpec.hashrings.all(h, !has(h.autoscaling) || h.autoscaling.minReplicas >= self.routerSpec.replicationFactor)", message="Ingester autoscaling minReplicas must be greater than or equal to the Router replicationFactor"
+// +kubebuilder:validation:XValidation:rule="!has(self.ingesterSpec.shutdownDrainSeconds) || !has(self.terminationGracePeriodSeconds) || self.ingesterSpec.shutdownDrainSeconds < self.terminationGracePeriodSeconds", message="Ingester shutdownDrainSeconds must be less than terminationGracePeriodSeconds"
+// +kubebuilder:validation:XValidation:rule="!has(self.writeProbe) || !has(self.routerSpec.remoteWriteTLS)", message="writeProbe is not supported when remoteWriteTLS is set"
 type ThanosReceiveSpec struct {
 	// Router is the configuration for the router.
 	// +kubebuilder:validation:Required
@@ -201,15 +642,188 @@ type ThanosReceiveSpec struct {
 	// Ingester is the configuration for the ingestor.
 	// +kubebuilder:validation:Required
 	Ingester IngesterSpec `json:"ingesterSpec,omitempty"`
+	// VerifyObjectStorage runs a Job that lists the bucket to check that the object storage is reachable
+	// with the configuration of each hashring, and records the result in the ObjectStorageVerified condition.
+	// +kubebuilder:validation:Optional
+	VerifyObjectStorage *bool `json:"verifyObjectStorage,omitempty"`
 	// When a resource is paused, no actions except for deletion
 	// will be performed on the underlying objects.
 	// +kubebuilder:validation:Optional
 	Paused *bool `json:"paused,omitempty"`
+	// DeletionProtection rejects the deletion of the ThanosReceive by the validating webhook of the operator,
+	// guarding against accidental deletions. It must be unset or set to false before the ThanosReceive can be deleted.
+	// The operator.thanos.io/deletion-protection annotation set to "true" protects the ThanosReceive too.
+	// +kubebuilder:validation:Optional
+	DeletionProtection *bool `json:"deletionProtection,omitempty"`
+	// TargetCluster is the name of the workload cluster in which the child resources are created.
+	// The cluster must be registered with the operator using the --target-cluster flag.
+	// If unset, the child resources are created in the cluster of this resource.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="targetCluster is immutable"
+	TargetCluster *string `json:"targetCluster,omitempty"`
+	// WriteProbe deploys a synthetic probe that periodically remote writes a canary series through the router
+	// and verifies that it can be queried through a ThanosQuery within a deadline.
+	// The result of the latest probe is recorded in the WriteProbeSucceeded condition.
+	// +kubebuilder:validation:Optional
+	WriteProbe *WriteProbeSpec `json:"writeProbe,omitempty"`
+	// Limits are the write limits enforced by the router, globally and per tenant.
+	// The limits are rendered into a ConfigMap that is mounted into the router and reloaded on change.
+	// See https://thanos.io/tip/components/receive.md/#limits--gates-experimental
+	// +kubebuilder:validation:Optional
+	Limits *ReceiveLimitsSpec `json:"limits,omitempty"`
+	// TenantSelector selects the ThanosTenant resources in the namespace of the ThanosReceive whose tenants are
+	// added to the tenants of their hashring and whose limits are added to the limits of the tenants.
+	// ThanosTenant resources are not used if unset.
+	// +kubebuilder:validation:Optional
+	TenantSelector *metav1.LabelSelector `json:"tenantSelector,omitempty"`
+	// GRPCTLS configures TLS for the gRPC endpoints of the ingesters, which serve the StoreAPI to queriers
+	// and receive the writes forwarded by the router.
+	// The ingester Services are labeled with operator.thanos.io/grpc-tls, so that queriers connect to them over TLS.
+	// +kubebuilder:validation:Optional
+	GRPCTLS *ReceiveGRPCTLSConfig `json:"grpcTLS,omitempty"`
+	// NetworkPolicy generates NetworkPolicies restricting the traffic of the routers and ingesters.
+	// The ingesters only accept connections from the routers and queriers, and the routers only accept
+	// remote writes from the configured sources. Metrics are scraped from any source.
+	// No NetworkPolicies are generated if unset.
+	// +kubebuilder:validation:Optional
+	NetworkPolicy *ReceiveNetworkPolicySpec `json:"networkPolicy,omitempty"`
+	// Deletion configures the cleanup performed by the operator before the ThanosReceive is deleted.
+	// +kubebuilder:validation:Optional
+	Deletion *ReceiveDeletionSpec `json:"deletion,omitempty"`
 	// StatefulSetFields are the options available to all Thanos stateful
 	// components.
 	StatefulSetFields `json:",inline"`
 }
 
+// ReceiveGRPCTLSConfig is the configuration for TLS between the router and the ingesters.
+// +kubebuilder:validation:XValidation:rule="!has(self.server.clientCA) || (has(self.client) && has(self.client.certSecret))",message="client.certSecret is required when server.clientCA is set, so that the router can authenticate to the ingesters"
+type ReceiveGRPCTLSConfig struct {
+	// Server configures TLS for the gRPC server of the ingesters.
+	// When a client CA is set, the router and queriers must authenticate with a client certificate signed by that CA.
+	// +kubebuilder:validation:Required
+	Server TLSConfig `json:"server"`
+	// Client configures TLS for the connections of the router to the ingesters.
+	// The router verifies the ingester certificates against the system roots if not set.
+	// +kubebuilder:validation:Optional
+	Client *GRPCClientTLSConfig `json:"client,omitempty"`
+}
+
+// ReceiveLimitsSpec is the configuration of the write limits enforced by the router.
+// +kubebuilder:validation:XValidation:rule="((!has(self.default) || !has(self.default.headSeriesLimit)) && (!has(self.tenants) || self.tenants.all(t, !has(self.tenants[t].headSeriesLimit)))) || (has(self.global) && has(self.global.metaMonitoringURL))",message="global.metaMonitoringURL is required when a head series limit is set"
+type ReceiveLimitsSpec struct {
+	// Global are the limits applied to each router as a whole.
+	// +kubebuilder:validation:Optional
+	Global *GlobalWriteLimits `json:"global,omitempty"`
+	// Default are the limits applied to every tenant.
+	// +kubebuilder:validation:Optional
+	Default *WriteLimits `json:"default,omitempty"`
+	// Tenants are the limits of individual tenants, keyed by tenant ID.
+	// Limits that are not set for a tenant fall back to the default limits.
+	// +kubebuilder:validation:Optional
+	Tenants map[string]WriteLimits `json:"tenants,omitempty"`
+}
+
+// GlobalWriteLimits are the limits applied to each router as a whole.
+type GlobalWriteLimits struct {
+	// MaxConcurrency is the maximum number of remote write requests processed concurrently by a router.
+	// Zero means no limit.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Optional
+	MaxConcurrency *int64 `json:"maxConcurrency,omitempty"`
+	// MetaMonitoringURL is the URL of a Prometheus compatible API that is queried for the number of active series
+	// of each tenant. It is required to enforce head series limits.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Optional
+	MetaMonitoringURL *string `json:"metaMonitoringURL,omitempty"`
+	// MetaMonitoringLimitQuery is the PromQL query returning the number of active series of each tenant.
+	// If not set, the Thanos default is used.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Optional
+	MetaMonitoringLimitQuery *string `json:"metaMonitoringLimitQuery,omitempty"`
+}
+
+// WriteLimits are the write limits of a tenant. Zero means no limit.
+type WriteLimits struct {
+	// SizeBytesLimit is the maximum size in bytes of a remote write request.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Optional
+	SizeBytesLimit *int64 `json:"sizeBytesLimit,omitempty"`
+	// SeriesLimit is the maximum number of series in a remote write request.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Optional
+	SeriesLimit *int64 `json:"seriesLimit,omitempty"`
+	// SamplesLimit is the maximum number of samples in a remote write request.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Optional
+	SamplesLimit *int64 `json:"samplesLimit,omitempty"`
+	// HeadSeriesLimit is the maximum number of active series of the tenant across all ingesters.
+	// Requires global.metaMonitoringURL to be set.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Optional
+	HeadSeriesLimit *int64 `json:"headSeriesLimit,omitempty"`
+}
+
+// ReceiveNetworkPolicySpec is the configuration of the NetworkPolicies of the routers and ingesters.
+type ReceiveNetworkPolicySpec struct {
+	// RemoteWriteSources are the peers allowed to remote write to the routers, such as the pods of an ingress controller.
+	// The write probe of this ThanosReceive is always allowed. Remote writes are allowed from any source if empty.
+	// +kubebuilder:validation:Optional
+	RemoteWriteSources []networkingv1.NetworkPolicyPeer `json:"remoteWriteSources,omitempty"`
+	// ObjectStorage are the destinations the ingesters are allowed to connect to, typically the IP ranges
+	// of the object storage endpoint. DNS lookups are always allowed.
+	// The egress of the ingesters is not restricted if empty.
+	// +kubebuilder:validation:Optional
+	ObjectStorage []networkingv1.NetworkPolicyPeer `json:"objectStorage,omitempty"`
+}
+
+// VolumeRetentionPolicy defines what happens to the data volumes of the ingesters when the ThanosReceive is deleted.
+// +kubebuilder:validation:Enum=Retain;Delete
+type VolumeRetentionPolicy string
+
+const (
+	// VolumeRetentionPolicyRetain keeps the data volumes of the ingesters.
+	VolumeRetentionPolicyRetain VolumeRetentionPolicy = "Retain"
+	// VolumeRetentionPolicyDelete deletes the data volumes of the ingesters once they have terminated.
+	VolumeRetentionPolicyDelete VolumeRetentionPolicy = "Delete"
+)
+
+// ReceiveDeletionSpec is the configuration of the cleanup performed before a ThanosReceive is deleted.
+type ReceiveDeletionSpec struct {
+	// FlushIngesters scales the ingesters down to zero and waits for them to terminate before the deletion completes,
+	// so that they upload their head block to object storage on shutdown before the volumes can be deleted.
+	// +kubebuilder:default=false
+	// +kubebuilder:validation:Optional
+	FlushIngesters *bool `json:"flushIngesters,omitempty"`
+	// VolumeRetentionPolicy defines whether the data volumes of the ingesters are kept or deleted.
+	// The volumes are created by the StatefulSets and are not owned by the ThanosReceive, so they are kept by default.
+	// +kubebuilder:default=Retain
+	// +kubebuilder:validation:Optional
+	VolumeRetentionPolicy *VolumeRetentionPolicy `json:"volumeRetentionPolicy,omitempty"`
+}
+
+// WriteProbeSpec is the configuration of the write path probe.
+type WriteProbeSpec struct {
+	// QueryName is the name of the ThanosQuery in the same namespace through which the canary series is queried.
+	// The ThanosQuery must be able to query the ingesters of this ThanosReceive.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	QueryName string `json:"queryName"`
+	// Schedule on which the probe runs, in Cron format.
+	// +kubebuilder:default="*/5 * * * *"
+	// +kubebuilder:validation:Optional
+	Schedule *string `json:"schedule,omitempty"`
+	// DeadlineSeconds is the time within which the canary series must become queryable for the probe to succeed.
+	// +kubebuilder:default=120
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	DeadlineSeconds *int32 `json:"deadlineSeconds,omitempty"`
+	// Image is the container image of the probe. The image must provide promtool and a shell.
+	// If not specified, the Prometheus image is used.
+	// +kubebuilder:validation:Optional
+	Image *string `json:"image,omitempty"`
+}
+
 // ThanosReceiveStatus defines the observed state of ThanosReceive
 type ThanosReceiveStatus struct {
 	// Conditions represent the latest available observations of the state of the ThanosReceive CRD.
@@ -221,10 +835,82 @@ type ThanosReceiveStatus struct {
 	Router DeploymentStatus `json:"routerStatus,omitempty"`
 	// HashringStatus is a map of ingester statuses to hashring names.
 	HashringStatus map[string]StatefulSetStatus `json:"hashringStatus,omitempty"`
+	// RouterGroupStatus is a map of the statuses of the router groups to group names.
+	// +kubebuilder:validation:Optional
+	RouterGroupStatus map[string]DeploymentStatus `json:"routerGroupStatus,omitempty"`
+	// IngesterVolumes is the placement of the data volumes of the ingesters, keyed by hashring name.
+	// +kubebuilder:validation:Optional
+	IngesterVolumes map[string][]IngesterVolumeStatus `json:"ingesterVolumes,omitempty"`
+	// UploadLag is the upload lag of the ingesters of each hashring at the last check, keyed by hashring name.
+	// It is the age of the oldest block of the ingesters of the hashring that has not been uploaded to object
+	// storage, as reported by their shipper and TSDB metrics, and zero when all their blocks have been uploaded.
+	// Hashrings whose ingesters could not be scraped are left out.
+	// +kubebuilder:validation:Optional
+	UploadLag map[string]metav1.Duration `json:"uploadLag,omitempty"`
+	// UploadLagCheckTime is the time of the last check of the upload lag.
+	// +kubebuilder:validation:Optional
+	UploadLagCheckTime *metav1.Time `json:"uploadLagCheckTime,omitempty"`
+	// IngesterHealth is the health of the running ingesters of each hashring at the last health check,
+	// keyed by hashring name.
+	// +kubebuilder:validation:Optional
+	IngesterHealth map[string][]EndpointHealthStatus `json:"ingesterHealth,omitempty"`
+	// HashringConfigHash is the hash of the hashring configuration currently applied to the router.
+	// +kubebuilder:validation:Optional
+	HashringConfigHash string `json:"hashringConfigHash,omitempty"`
+	// HashringConfigVersion is the version of the hashring configuration applied to the router.
+	// It is increased every time the content of the configuration changes.
+	// +kubebuilder:validation:Optional
+	HashringConfigVersion int64 `json:"hashringConfigVersion,omitempty"`
+	// HashringConfigUpdateTime is when the hashring configuration applied to the router last changed.
+	// +kubebuilder:validation:Optional
+	HashringConfigUpdateTime *metav1.Time `json:"hashringConfigUpdateTime,omitempty"`
+	// RouterHashringConfigHash is the hash of the hashring configuration the routers have rolled out with.
+	// It is only set with the Rollout hashring configuration reload, once all the routers run with the same
+	// configuration, and matches hashringConfigHash once the latest configuration is in effect.
+	// +kubebuilder:validation:Optional
+	RouterHashringConfigHash string `json:"routerHashringConfigHash,omitempty"`
+	// Rollout is the progress of a Sequential rollout across hashrings. It is not set when no rollout is in progress.
+	// +kubebuilder:validation:Optional
+	Rollout *HashringRolloutStatus `json:"rollout,omitempty"`
+	// ObservedGeneration is the most recent generation of the ThanosReceive observed by the operator.
+	// +kubebuilder:validation:Optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// HashringRolloutStatus is the progress of a Sequential rollout across hashrings.
+type HashringRolloutStatus struct {
+	// Hashring is the hashring currently rolling out.
+	// +kubebuilder:validation:Optional
+	Hashring string `json:"hashring,omitempty"`
+	// Pending are the hashrings waiting to roll out, in the order they will be rolled out.
+	// +kubebuilder:validation:Optional
+	Pending []string `json:"pending,omitempty"`
+	// Paused is true if the rollout is paused.
+	// +kubebuilder:validation:Optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+// IngesterVolumeStatus is the placement of the data volume of an ingester.
+type IngesterVolumeStatus struct {
+	// Claim is the name of the PersistentVolumeClaim of the ingester.
+	Claim string `json:"claim"`
+	// Volume is the name of the PersistentVolume bound to the claim. Empty while the claim is not bound.
+	// +kubebuilder:validation:Optional
+	Volume string `json:"volume,omitempty"`
+	// Zone is the zone the volume is pinned to by its node affinity or topology labels, if any.
+	// +kubebuilder:validation:Optional
+	Zone string `json:"zone,omitempty"`
+	// Node is the node the volume is pinned to, for volumes local to a node.
+	// +kubebuilder:validation:Optional
+	Node string `json:"node,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:subresource:scale:specpath=.spec.routerSpec.replicas,statuspath=.status.routerStatus.replicas,selectorpath=.status.routerStatus.selector
+//+kubebuilder:storageversion
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // ThanosReceive is the Schema for the thanosreceives API
 type ThanosReceive struct {
@@ -40,6 +40,10 @@ type ThanosRulerSpec struct {
 	// ObjectStorageConfig is the secret that contains the object storage configuration for Ruler to upload blocks.
 	// +kubebuilder:validation:Required
 	ObjectStorageConfig ObjectStorageConfig `json:"objectStorageConfig,omitempty"`
+	// VerifyObjectStorage runs a Job that lists the bucket to check that the object storage is reachable
+	// with the configuration, and records the result in the ObjectStorageVerified condition.
+	// +kubebuilder:validation:Optional
+	VerifyObjectStorage *bool `json:"verifyObjectStorage,omitempty"`
 	// RuleConfigSelector is the label selector to discover ConfigMaps with rule files.
 	// It also discovers PrometheusRule CustomResources if the feature flag is enabled.
 	// PrometheusRules are converted them into ConfigMaps with rule files internally.
@@ -76,6 +80,13 @@ type ThanosRulerSpec struct {
 	// will be performed on the underlying objects.
 	// +kubebuilder:validation:Optional
 	Paused *bool `json:"paused,omitempty"`
+	// TargetCluster is the name of the workload cluster in which the child resources are created.
+	// The cluster must be registered with the operator using the --target-cluster flag.
+	// If unset, the child resources are created in the cluster of this resource.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="targetCluster is immutable"
+	TargetCluster *string `json:"targetCluster,omitempty"`
 	// RuleTenancyConfig is the configuration for the rule tenancy.
 	// +kubebuilder:validation:Optional
 	RuleTenancyConfig *RuleTenancyConfig `json:"ruleTenancyConfig,omitempty"`
@@ -108,10 +119,15 @@ type ThanosRulerStatus struct {
 	// +kubebuilder:validation:Optional
 	Paused            *bool `json:"paused,omitempty"`
 	StatefulSetStatus `json:",inline"`
+	// ObservedGeneration is the most recent generation of the ThanosRuler observed by the operator.
+	// +kubebuilder:validation:Optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // ThanosRuler is the Schema for the thanosrulers API
 type ThanosRuler struct {
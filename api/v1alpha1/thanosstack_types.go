@@ -0,0 +1,208 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StackLabel is set on the resources managed by a ThanosStack to the name of the ThanosStack.
+// The components of a ThanosStack only discover each other through this label.
+const StackLabel = "operator.thanos.io/stack"
+
+// ThanosStackSpec defines the desired state of ThanosStack.
+// Each component is deployed with defaults suited to a single stack, and can be disabled or tuned
+// through its section. Components that need further configuration can be deployed on their own instead.
+type ThanosStackSpec struct {
+	// CommonFields are applied to all the components of the stack.
+	CommonFields `json:",inline"`
+	// ObjectStorageConfig is the secret that contains the object storage configuration shared by all the components.
+	// +kubebuilder:validation:Required
+	ObjectStorageConfig ObjectStorageConfig `json:"objectStorageConfig"`
+	// Receive configures the ThanosReceive of the stack, which ingests metrics over remote write.
+	// +kubebuilder:default:={}
+	// +kubebuilder:validation:Optional
+	Receive StackReceiveSpec `json:"receive,omitempty"`
+	// Query configures the ThanosQuery of the stack, which queries the other components.
+	// +kubebuilder:default:={}
+	// +kubebuilder:validation:Optional
+	Query StackQuerySpec `json:"query,omitempty"`
+	// Store configures the ThanosStore of the stack, which serves the blocks in object storage.
+	// +kubebuilder:default:={}
+	// +kubebuilder:validation:Optional
+	Store StackStoreSpec `json:"store,omitempty"`
+	// Compact configures the ThanosCompact of the stack, which compacts, downsamples and applies retention
+	// to the blocks in object storage.
+	// +kubebuilder:default:={}
+	// +kubebuilder:validation:Optional
+	Compact StackCompactSpec `json:"compact,omitempty"`
+	// Ruler configures the ThanosRuler of the stack, which evaluates recording and alerting rules.
+	// It is only deployed once an Alertmanager URL is set.
+	// +kubebuilder:validation:Optional
+	Ruler StackRulerSpec `json:"ruler,omitempty"`
+	// When a resource is paused, no actions except for deletion
+	// will be performed on the underlying objects.
+	// +kubebuilder:validation:Optional
+	Paused *bool `json:"paused,omitempty"`
+}
+
+// StackComponentSpec are the options available to all the components of a ThanosStack.
+type StackComponentSpec struct {
+	// Enabled deploys the component. The resource of a disabled component is deleted.
+	// +kubebuilder:default=true
+	// +kubebuilder:validation:Optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// Replicas is the number of replicas of the component.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Optional
+	Replicas int32 `json:"replicas,omitempty"`
+}
+
+// StackReceiveSpec configures the ThanosReceive of a ThanosStack.
+// A single hashring is deployed, named default.
+// +kubebuilder:validation:XValidation:rule="self.ingesterReplicas >= self.replicationFactor",message="ingesterReplicas must be at least the replicationFactor"
+type StackReceiveSpec struct {
+	StackComponentSpec `json:",inline"`
+	// IngesterReplicas is the number of ingesters in the hashring.
+	// It must be at least the replication factor.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Optional
+	IngesterReplicas int32 `json:"ingesterReplicas,omitempty"`
+	// ReplicationFactor is the replication factor for the ingesters.
+	// +kubebuilder:validation:Enum=1;3;5
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Optional
+	ReplicationFactor int32 `json:"replicationFactor,omitempty"`
+	// Retention is the duration for which the ingesters retain data locally.
+	// +kubebuilder:default="2h"
+	// +kubebuilder:validation:Optional
+	Retention Duration `json:"retention,omitempty"`
+	// Storage is the storage of each ingester.
+	// +kubebuilder:default:={size: "10Gi"}
+	// +kubebuilder:validation:Optional
+	StorageConfiguration *StorageConfiguration `json:"storage,omitempty"`
+}
+
+// StackQuerySpec configures the ThanosQuery of a ThanosStack.
+type StackQuerySpec struct {
+	StackComponentSpec `json:",inline"`
+	// QueryFrontend deploys a Query Frontend in front of the Queriers.
+	// +kubebuilder:default=true
+	// +kubebuilder:validation:Optional
+	QueryFrontend *bool `json:"queryFrontend,omitempty"`
+}
+
+// StackStoreSpec configures the ThanosStore of a ThanosStack.
+type StackStoreSpec struct {
+	StackComponentSpec `json:",inline"`
+	// Storage is the storage of each Store Gateway replica.
+	// +kubebuilder:default:={size: "10Gi"}
+	// +kubebuilder:validation:Optional
+	StorageConfiguration *StorageConfiguration `json:"storage,omitempty"`
+}
+
+// StackCompactSpec configures the ThanosCompact of a ThanosStack.
+type StackCompactSpec struct {
+	// Enabled deploys the component. The resource of a disabled component is deleted.
+	// +kubebuilder:default=true
+	// +kubebuilder:validation:Optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// Storage is the storage of the Compactor.
+	// +kubebuilder:default:={size: "10Gi"}
+	// +kubebuilder:validation:Optional
+	StorageConfiguration *StorageConfiguration `json:"storage,omitempty"`
+	// RetentionConfig is the retention of the blocks in object storage per resolution.
+	// +kubebuilder:default:={raw: "30d", fiveMinutes: "90d", oneHour: "1y"}
+	// +kubebuilder:validation:Optional
+	RetentionConfig *RetentionResolutionConfig `json:"retentionConfig,omitempty"`
+}
+
+// StackRulerSpec configures the ThanosRuler of a ThanosStack.
+type StackRulerSpec struct {
+	// Replicas is the number of Ruler replicas.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Optional
+	Replicas int32 `json:"replicas,omitempty"`
+	// AlertmanagerURL is the URL of the Alertmanager to which the Ruler sends alerts.
+	// The Ruler is deployed once it is set. The scheme may be prefixed with 'dns+' or 'dnssrv+'
+	// to detect Alertmanager IPs through respective DNS lookups.
+	// +kubebuilder:validation:Pattern=`^((dns\+)?(dnssrv\+)?(http|https):\/\/)[a-zA-Z0-9\-\.]+\.[a-zA-Z]{2,}(:[0-9]{1,5})?$`
+	// +kubebuilder:validation:Optional
+	AlertmanagerURL string `json:"alertmanagerURL,omitempty"` //nolint:tagliatelle
+	// Storage is the storage of each Ruler replica.
+	// +kubebuilder:default:={size: "10Gi"}
+	// +kubebuilder:validation:Optional
+	StorageConfiguration *StorageConfiguration `json:"storage,omitempty"`
+}
+
+// ThanosStackComponentStatus is the status of a resource managed by a ThanosStack.
+type ThanosStackComponentStatus struct {
+	// Kind is the kind of the resource.
+	Kind string `json:"kind"`
+	// Name is the name of the resource.
+	Name string `json:"name"`
+	// Ready is the status of the Ready condition of the resource.
+	// +kubebuilder:validation:Optional
+	Ready metav1.ConditionStatus `json:"ready,omitempty"`
+}
+
+// ThanosStackStatus defines the observed state of ThanosStack.
+type ThanosStackStatus struct {
+	// Conditions represent the latest available observations of the state of the stack.
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+	// Paused is a flag that indicates if the stack is paused.
+	// +kubebuilder:validation:Optional
+	Paused *bool `json:"paused,omitempty"`
+	// Components are the resources managed by the stack.
+	// +kubebuilder:validation:Optional
+	Components []ThanosStackComponentStatus `json:"components,omitempty"`
+	// ObservedGeneration is the most recent generation of the ThanosStack observed by the operator.
+	// +kubebuilder:validation:Optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ThanosStack is the Schema for the thanosstacks API.
+// It deploys a complete Thanos stack sharing a single object storage, by managing a ThanosReceive,
+// ThanosQuery, ThanosStore, ThanosCompact and ThanosRuler named after it.
+type ThanosStack struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ThanosStackSpec   `json:"spec,omitempty"`
+	Status ThanosStackStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ThanosStackList contains a list of ThanosStack
+type ThanosStackList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ThanosStack `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ThanosStack{}, &ThanosStackList{})
+}
@@ -21,6 +21,7 @@ import (
 )
 
 // ThanosStoreSpec defines the desired state of ThanosStore
+// +kubebuilder:validation:XValidation:rule="!has(self.timeRangeConfig) || !has(self.shardingStrategy) || self.shardingStrategy.type != 'time'",message="timeRangeConfig cannot be set when the sharding strategy type is time"
 type ThanosStoreSpec struct {
 	CommonFields `json:",inline"`
 	// StatefulSetFields are the options available to all Thanos stateful
@@ -34,6 +35,10 @@ type ThanosStoreSpec struct {
 	// ObjectStorageConfig is the secret that contains the object storage configuration for Store Gateways.
 	// +kubebuilder:validation:Required
 	ObjectStorageConfig ObjectStorageConfig `json:"objectStorageConfig,omitempty"`
+	// VerifyObjectStorage runs a Job that lists the bucket to check that the object storage is reachable
+	// with the configuration, and records the result in the ObjectStorageVerified condition.
+	// +kubebuilder:validation:Optional
+	VerifyObjectStorage *bool `json:"verifyObjectStorage,omitempty"`
 	// StorageConfiguration represents the storage to be used by the Thanos Store StatefulSets.
 	// +kubebuilder:validation:Required
 	StorageConfiguration StorageConfiguration `json:"storage"`
@@ -52,6 +57,10 @@ type ThanosStoreSpec struct {
 	// See format details: https://thanos.io/tip/components/store.md/#caching-bucket
 	// +kubebuilder:validation:Optional
 	CachingBucketConfig *CacheConfig `json:"cachingBucketConfig,omitempty"`
+	// Cache deploys a memcached managed by the operator, shared by the shards of the store,
+	// for the index cache and the caching bucket.
+	// +kubebuilder:validation:Optional
+	Cache *ManagedCacheSpec `json:"cache,omitempty"`
 	// ShardingStrategy defines the sharding strategy for the Store Gateways across object storage blocks.
 	// +kubebuilder:validation:Required
 	ShardingStrategy ShardingStrategy `json:"shardingStrategy,omitempty"`
@@ -71,6 +80,13 @@ type ThanosStoreSpec struct {
 	// will be performed on the underlying objects.
 	// +kubebuilder:validation:Optional
 	Paused *bool `json:"paused,omitempty"`
+	// TargetCluster is the name of the workload cluster in which the child resources are created.
+	// The cluster must be registered with the operator using the --target-cluster flag.
+	// If unset, the child resources are created in the cluster of this resource.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="targetCluster is immutable"
+	TargetCluster *string `json:"targetCluster,omitempty"`
 	// Additional configuration for the Thanos components. Allows you to add
 	// additional args, containers, volumes, and volume mounts to Thanos Deployments,
 	// and StatefulSets. Ideal to use for things like sidecars.
@@ -83,20 +99,40 @@ type ShardingStrategyType string
 const (
 	// Block is the block modulo sharding strategy for sharding Stores according to block ids.
 	Block ShardingStrategyType = "block"
+	// Time is the time based sharding strategy for sharding Stores according to the time range of the data they serve.
+	Time ShardingStrategyType = "time"
 )
 
 // ShardingStrategy controls the automatic deployment of multiple store gateways sharded by block ID
-// by hashmoding __block_id label value.
+// by hashmoding __block_id label value, or by the time range of the data they serve.
+// +kubebuilder:validation:XValidation:rule="self.type != 'time' || (has(self.timeRanges) && size(self.timeRanges) > 0) || has(self.timeRange)",message="timeRanges or timeRange must be set when the sharding strategy type is time"
+// +kubebuilder:validation:XValidation:rule="self.type == 'time' || !has(self.timeRanges)",message="timeRanges can only be set when the sharding strategy type is time"
+// +kubebuilder:validation:XValidation:rule="self.type == 'time' || !has(self.timeRange)",message="timeRange can only be set when the sharding strategy type is time"
+// +kubebuilder:validation:XValidation:rule="!has(self.timeRanges) || !has(self.timeRange)",message="timeRanges and timeRange are mutually exclusive"
+// +kubebuilder:validation:XValidation:rule="!has(self.timeRange) || has(self.timeRange.minTime)",message="timeRange.minTime must be set to split the time range into shards"
 type ShardingStrategy struct {
 	// Type here is the type of sharding strategy.
 	// +kubebuilder:validation:Required
 	// +kubebuilder:default="block"
-	// +kubebuilder:validation:Enum=block
+	// +kubebuilder:validation:Enum=block;time
 	Type ShardingStrategyType `json:"type,omitempty"`
 	// Shards is the number of shards to split the data into.
+	// Used by the block sharding strategy, and by the time sharding strategy to split the TimeRange.
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:default=1
 	Shards int32 `json:"shards,omitempty"`
+	// TimeRanges are the time ranges of data served by each shard when the sharding strategy type is time.
+	// A shard is deployed for each time range. Ranges are expected not to overlap, otherwise blocks
+	// will be served by several shards.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=32
+	TimeRanges []TimeRangeConfig `json:"timeRanges,omitempty"`
+	// TimeRange is split into Shards consecutive time ranges of the same length when the sharding strategy type is time,
+	// and a shard is deployed for each of them, the first shard serving the most recent data.
+	// MinTime must be set, and MinTime and MaxTime must either both be relative durations or both be timestamps.
+	// An unset MaxTime is the current time, and can only be used with a relative MinTime.
+	// +kubebuilder:validation:Optional
+	TimeRange *TimeRangeConfig `json:"timeRange,omitempty"`
 }
 
 // IndexHeaderConfig allows configuration of the Store Gateway index header.
@@ -126,10 +162,15 @@ type ThanosStoreStatus struct {
 	Paused *bool `json:"paused,omitempty"`
 	// ShardStatuses is a map of shard statuses to shard numbers.
 	ShardStatuses map[string]StatefulSetStatus `json:"shardStatuses,omitempty"`
+	// ObservedGeneration is the most recent generation of the ThanosStore observed by the operator.
+	// +kubebuilder:validation:Optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // ThanosStore is the Schema for the thanosstores API
 type ThanosStore struct {
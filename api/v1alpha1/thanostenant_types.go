@@ -0,0 +1,67 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ThanosTenantSpec declares a tenant of the ThanosReceive resources selecting it.
+type ThanosTenantSpec struct {
+	// TenantID is the ID of the tenant, as determined by the routers from the remote write requests.
+	// It is matched exactly, so it cannot contain the *, ? and [ glob characters.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Pattern=`^[^*?\[]+$`
+	// +kubebuilder:validation:Required
+	TenantID string `json:"tenantID"` //nolint:tagliatelle
+	// Hashring is the name of the hashring of the ThanosReceive the writes of the tenant are routed to.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Required
+	Hashring string `json:"hashring"`
+	// Limits are the write limits of the tenant. Limits that are not set fall back to the default limits
+	// of the ThanosReceive. Limits set for the tenant by the ThanosReceive take precedence.
+	// +kubebuilder:validation:Optional
+	Limits *WriteLimits `json:"limits,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:printcolumn:name="Tenant",type=string,JSONPath=`.spec.tenantID`
+//+kubebuilder:printcolumn:name="Hashring",type=string,JSONPath=`.spec.hashring`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ThanosTenant is the Schema for the thanostenants API.
+// It onboards a tenant to the ThanosReceive resources in its namespace whose tenantSelector selects it,
+// routing the tenant to a hashring and setting its write limits.
+type ThanosTenant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ThanosTenantSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ThanosTenantList contains a list of ThanosTenant
+type ThanosTenantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ThanosTenant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ThanosTenant{}, &ThanosTenantList{})
+}
@@ -1,24 +1,109 @@
 package v1alpha1
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 )
 
+// ControllerIDAnnotation assigns a resource to a specific operator instance.
+// An operator started with --controller-id only reconciles resources whose annotation value matches its ID.
+// An operator started without an ID only reconciles resources that do not carry this annotation.
+const ControllerIDAnnotation = "operator.thanos.io/controller-id"
+
+// The following annotations trigger maintenance actions on a resource. Setting them with a label selector, for example
+// with kubectl annotate -l, or with the fleet subcommand of the operator, applies the action to a fleet of resources at once.
+const (
+	// RestartedAtAnnotation restarts the pods of a resource whenever its value changes, for example to the current time.
+	// The value is copied to the pod templates of the workloads generated for the resource.
+	RestartedAtAnnotation = "operator.thanos.io/restarted-at"
+	// PausedAnnotation pauses the reconciliation of a resource when set to "true", like the paused field of its spec.
+	PausedAnnotation = "operator.thanos.io/paused"
+	// ResyncRequestedAtAnnotation reconciles a resource whenever its value changes, for example to the current time.
+	ResyncRequestedAtAnnotation = "operator.thanos.io/resync-requested-at"
+)
+
+// DeletionProtectionAnnotation protects a ThanosReceive or ThanosQuery against deletion when set to "true",
+// like the deletionProtection field of its spec.
+const DeletionProtectionAnnotation = "operator.thanos.io/deletion-protection"
+
 // Duration is a valid time duration that can be parsed by Prometheus model.ParseDuration() function.
 // Supported units: y, w, d, h, m, s, ms
 // Examples: `30s`, `1m`, `1h20m15s`, `15d`
 // +kubebuilder:validation:Pattern="^(-?(0|(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?)|([0-9]{4}-[0-9]{2}-[0-9]{2}T[0-9]{2}:[0-9]{2}:[0-9]{2}(\\.[0-9]+)?(Z|[+-][0-9]{2}:[0-9]{2})))$"
 type Duration string
 
-// ObjectStorageConfig is the secret that contains the object storage configuration.
-// The secret needs to be in the same namespace as the ReceiveHashring object.
+// ObjectStorageConfig is the object storage configuration.
+// It is read from a key of a Secret or a ConfigMap, or set inline. Inline configurations and configurations read
+// from a ConfigMap are copied by the operator into a managed Secret, which is mounted into the pods.
+// The Secret and the ConfigMap need to be in the same namespace as the owning object.
 // See https://thanos.io/tip/thanos/storage.md/#supported-clients for relevant documentation.
-type ObjectStorageConfig corev1.SecretKeySelector
+// +kubebuilder:validation:XValidation:rule="!has(self.configMap) || !has(self.inline)",message="configMap and inline are mutually exclusive"
+// +kubebuilder:validation:XValidation:rule="!(has(self.configMap) || has(self.inline)) || !has(self.name) || size(self.name) == 0",message="name cannot be set with configMap or inline"
+// +kubebuilder:validation:XValidation:rule="has(self.configMap) || has(self.inline) || has(self.key)",message="key is required when the configuration is read from a Secret"
+// +kubebuilder:validation:XValidation:rule="!has(self.inline) || !has(self.env)",message="env cannot be set with inline"
+type ObjectStorageConfig struct {
+	// The name of the secret in the pod's namespace to select from.
+	corev1.LocalObjectReference `json:",inline"`
+	// The key of the secret to select from. Must be a valid secret key.
+	// +kubebuilder:validation:Optional
+	Key string `json:"key,omitempty"`
+	// ConfigMap selects a key of a ConfigMap holding the object storage configuration.
+	// It is meant for configurations without credentials, such as the filesystem backend.
+	// +kubebuilder:validation:Optional
+	ConfigMap *corev1.ConfigMapKeySelector `json:"configMap,omitempty"`
+	// Inline is the object storage configuration in YAML.
+	// It is meant for configurations without credentials, such as the filesystem backend in development.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	Inline *string `json:"inline,omitempty"`
+	// Specify whether the Secret or its key must be defined
+	// +optional
+	Optional *bool `json:"optional,omitempty"`
+	// Mode selects how the object storage configuration is passed to Thanos.
+	// Inline passes the configuration from an env var with --objstore.config.
+	// File mounts the configuration and passes its path with --objstore.config-file.
+	// +kubebuilder:default=Inline
+	// +kubebuilder:validation:Optional
+	Mode *ObjectStorageConfigMode `json:"mode,omitempty"`
+	// Env projects keys of the secret as env vars of the Thanos container.
+	// This is useful for providers that read credentials from the environment, such as
+	// Azure managed identities or GCS application default credentials.
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:XValidation:rule="self.all(e, e.name != 'OBJSTORE_CONFIG')",message="OBJSTORE_CONFIG is reserved for the object storage configuration"
+	Env []ObjectStorageEnvVar `json:"env,omitempty"`
+}
+
+// ObjectStorageConfigMode is the way the object storage configuration is passed to Thanos.
+// +kubebuilder:validation:Enum=Inline;File
+type ObjectStorageConfigMode string
+
+const (
+	// ObjectStorageConfigModeInline passes the object storage configuration with --objstore.config.
+	ObjectStorageConfigModeInline ObjectStorageConfigMode = "Inline"
+	// ObjectStorageConfigModeFile passes the object storage configuration with --objstore.config-file.
+	ObjectStorageConfigModeFile ObjectStorageConfigMode = "File"
+)
+
+// ObjectStorageEnvVar projects a key of the object storage secret as an env var.
+type ObjectStorageEnvVar struct {
+	// Name is the name of the env var.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern="^[-._a-zA-Z][-._a-zA-Z0-9]*$"
+	Name string `json:"name"`
+	// Key is the key of the secret to project.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+}
 
 // CacheConfig is the configuration for the cache.
 // If both InMemoryCacheConfig and ExternalCacheConfig are specified, the operator will prefer the ExternalCacheConfig.
+// If neither is specified and the component has a cache managed by the operator, the managed cache is used.
 // +kubebuilder:validation:Optional
 type CacheConfig struct {
 	// InMemoryCacheConfig is the configuration for the in-memory cache.
@@ -35,6 +120,36 @@ type InMemoryCacheConfig struct {
 	MaxItemSize *StorageSize `json:"maxItemSize,omitempty"`
 }
 
+// ManagedCacheSpec is the configuration of a memcached cache deployed by the operator for a component.
+type ManagedCacheSpec struct {
+	// Managed deploys a memcached StatefulSet and a headless Service for the component.
+	// The cache configurations of the component that set neither inMemoryCacheConfig nor externalCacheConfig use it.
+	// The memcached is removed when this is unset or set to false.
+	// +kubebuilder:validation:Optional
+	Managed *bool `json:"managed,omitempty"`
+	// Replicas is the number of memcached replicas. Items are spread across the replicas by the Thanos clients.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Optional
+	Replicas *int32 `json:"replicas,omitempty"`
+	// Image is the memcached image.
+	// +kubebuilder:default="docker.io/library/memcached:1.6.38-alpine"
+	// +kubebuilder:validation:Optional
+	Image *string `json:"image,omitempty"`
+	// MemoryLimit is the memory used by each memcached replica for items.
+	// The container memory is requested and limited to this plus a margin, unless Resources are set.
+	// +kubebuilder:default="1Gi"
+	// +kubebuilder:validation:Optional
+	MemoryLimit *StorageSize `json:"memoryLimit,omitempty"`
+	// MaxItemSize is the maximum size of an item stored in memcached.
+	// +kubebuilder:default="1Mi"
+	// +kubebuilder:validation:Optional
+	MaxItemSize *StorageSize `json:"maxItemSize,omitempty"`
+	// Resources are the compute resources of the memcached container.
+	// +kubebuilder:validation:Optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
 // ExternalLabels are the labels to add to the metrics.
 // POD_NAME and POD_NAMESPACE are available via the downward API.
 // +kubebuilder:validation:MinProperties=1
@@ -65,6 +180,25 @@ type TSDBConfig struct {
 	// +kubebuilder:default="2h"
 	// +kubebuilder:validation:Required
 	Retention Duration `json:"retention,omitempty"`
+	// TooFarInFutureTimeWindow is the allowed time window for ingesting samples too far in the future.
+	// 0s means disabled. Takes precedence over the tooFarInFutureTimeWindow field of the hashring.
+	// +kubebuilder:validation:Optional
+	TooFarInFutureTimeWindow *Duration `json:"tooFarInFutureTimeWindow,omitempty"`
+	// OutOfOrderTimeWindow is how far in the past of the most recent sample out of order samples are accepted.
+	// 0s means disabled. The Thanos default is used if not set.
+	// +kubebuilder:validation:Optional
+	OutOfOrderTimeWindow *Duration `json:"outOfOrderTimeWindow,omitempty"`
+	// WALCompression compresses the write ahead log. The Thanos default, enabled, is used if not set.
+	// +kubebuilder:validation:Optional
+	WALCompression *bool `json:"walCompression,omitempty"`
+	// MinBlockDuration is the duration of the blocks cut from the head, and so the interval at which the head
+	// is compacted into a block that is uploaded to the object storage. The Thanos default is used if not set.
+	// +kubebuilder:validation:Optional
+	MinBlockDuration *Duration `json:"minBlockDuration,omitempty"`
+	// MaxBlockDuration is the maximum duration of the blocks compacted locally.
+	// It must not be lower than MinBlockDuration. The Thanos default is used if not set.
+	// +kubebuilder:validation:Optional
+	MaxBlockDuration *Duration `json:"maxBlockDuration,omitempty"`
 }
 
 // CommonFields are the options available to all Thanos components.
@@ -91,6 +225,14 @@ type CommonFields struct {
 	// See http://kubernetes.io/docs/user-guide/images#specifying-imagepullsecrets-on-a-pod
 	// +kubebuilder:validation:Optional
 	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// ServiceAccountAnnotations are added to the ServiceAccount created by the operator for the component,
+	// for example to bind it to a cloud IAM role with workload identity, such as IRSA or GKE Workload Identity.
+	// +kubebuilder:validation:Optional
+	ServiceAccountAnnotations map[string]string `json:"serviceAccountAnnotations,omitempty"`
+	// ServiceAnnotations are added to the Services exposing the component, for example to configure ExternalDNS
+	// or the load balancer of the cloud provider. The dedicated metrics Service is left unchanged.
+	// +kubebuilder:validation:Optional
+	ServiceAnnotations map[string]string `json:"serviceAnnotations,omitempty"`
 	// ResourceRequirements for the Thanos component container.
 	// +kubebuilder:validation:Optional
 	ResourceRequirements *corev1.ResourceRequirements `json:"resourceRequirements,omitempty"`
@@ -120,13 +262,41 @@ type CommonFields struct {
 	// If not specified, the operator will default to FSGroup=1001.
 	// +kubebuilder:validation:Optional
 	SecurityContext *corev1.PodSecurityContext `json:"securityContext,omitempty"`
+	// ContainerSecurityContext replaces the security context of the Thanos component container.
+	// If not specified, the container runs as non-root without privilege escalation, with all capabilities
+	// dropped and the RuntimeDefault seccomp profile, which complies with the restricted Pod Security Standard.
+	// +kubebuilder:validation:Optional
+	ContainerSecurityContext *corev1.SecurityContext `json:"containerSecurityContext,omitempty"`
+	// PriorityClassName is the name of the PriorityClass of the pods of the component.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	PriorityClassName *string `json:"priorityClassName,omitempty"`
+	// TracingConfig is the secret key that contains the tracing configuration for Thanos.
+	// See https://thanos.io/tip/thanos/tracing.md/#configuration for relevant documentation.
+	// +kubebuilder:validation:Optional
+	TracingConfig *corev1.SecretKeySelector `json:"tracingConfig,omitempty"`
+	// Tracing configures distributed tracing for Thanos without a tracing configuration secret.
+	// It is ignored if TracingConfig is set.
+	// +kubebuilder:validation:Optional
+	Tracing *TracingSpec `json:"tracing,omitempty"`
 	// PodDisruptionBudgetConfig holds the configuration for the PodDisruptionBudget.
 	// This allows enabling or disabling the creation of a PodDisruptionBudget for the Thanos component.
 	// When enabled, a resource that has more than one replica will have a PodDisruptionBudget created
-	// that sets maxUnavailable to 1.
+	// that sets maxUnavailable to 1, unless minAvailable or maxUnavailable is set.
+	// For Thanos Receive ingesters, maxUnavailable is derived by default from the replication factor and the
+	// number of replicas in the hashring, so that write quorum is preserved during disruptions.
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default={enable: true}
 	PodDisruptionBudgetConfig *PodDisruptionBudgetConfig `json:"podDisruptionBudgetConfig,omitempty"`
+	// MetricsService holds the configuration for a dedicated Service exposing only the metrics of the component
+	// on a port named http-metrics. This allows scrape configs, ServiceMonitors and NetworkPolicies to target
+	// the metrics without exposing the gRPC or remote write ports.
+	// When enabled, the ServiceMonitor managed by the operator scrapes this Service.
+	// +kubebuilder:validation:Optional
+	MetricsService *MetricsServiceConfig `json:"metricsService,omitempty"`
+	// Probes tunes the liveness, readiness and startup probes of the Thanos component container.
+	// +kubebuilder:validation:Optional
+	Probes *ProbesSpec `json:"probes,omitempty"`
 	// Labels are additional labels to add to components.
 	// In case of conflicts, these labels take precedence.
 	// +kubebuilder:validation:Optional
@@ -137,6 +307,95 @@ type CommonFields struct {
 	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
+// ProbesSpec tunes the probes of a Thanos component container.
+// The probe handlers are managed by the operator, only their timings and thresholds can be set.
+type ProbesSpec struct {
+	// Liveness tunes the liveness probe.
+	// +kubebuilder:validation:Optional
+	Liveness *ProbeSpec `json:"liveness,omitempty"`
+	// Readiness tunes the readiness probe.
+	// +kubebuilder:validation:Optional
+	Readiness *ProbeSpec `json:"readiness,omitempty"`
+	// Startup adds a startup probe using the handler of the liveness probe.
+	// Liveness and readiness probes are not run until the startup probe succeeds, which gives
+	// components with a slow startup, such as ingesters replaying a large WAL, time to start.
+	// +kubebuilder:validation:Optional
+	Startup *ProbeSpec `json:"startup,omitempty"`
+}
+
+// ProbeSpec holds the timings and thresholds of a probe.
+// See https://kubernetes.io/docs/tasks/configure-pod-container/configure-liveness-readiness-startup-probes/#configure-probes
+type ProbeSpec struct {
+	// InitialDelaySeconds is the number of seconds after the container has started before the probe is initiated.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Optional
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+	// PeriodSeconds is how often, in seconds, to perform the probe.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+	// TimeoutSeconds is the number of seconds after which the probe times out.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+	// SuccessThreshold is the minimum consecutive successes for the probe to be considered successful after having failed.
+	// Must be 1 for liveness and startup probes.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	SuccessThreshold *int32 `json:"successThreshold,omitempty"`
+	// FailureThreshold is the minimum consecutive failures for the probe to be considered failed after having succeeded.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+}
+
+// DeploymentFields are the options available to all Thanos components managed as Deployments.
+// These fields reflect runtime changes to managed Deployment resources.
+// +k8s:deepcopy-gen=true
+type DeploymentFields struct {
+	// TerminationGracePeriodSeconds is the duration in seconds the pod is allowed to terminate gracefully after SIGTERM.
+	// +kubebuilder:validation:Optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+	// Strategy is the strategy used to replace the pods of the Deployment.
+	// If not specified, the default strategy of the component is used.
+	// +kubebuilder:validation:XValidation:rule="!has(self.rollingUpdate) || !has(self.type) || self.type == 'RollingUpdate'",message="rollingUpdate can only be set with the RollingUpdate strategy"
+	// +kubebuilder:validation:Optional
+	Strategy *appsv1.DeploymentStrategy `json:"strategy,omitempty"`
+}
+
+// TracingType is the tracing provider that spans are sent to.
+// +kubebuilder:validation:Enum=OTLP;JAEGER
+type TracingType string
+
+const (
+	// TracingTypeOTLP sends spans to an OpenTelemetry collector over OTLP gRPC.
+	TracingTypeOTLP TracingType = "OTLP"
+	// TracingTypeJaeger sends spans to a Jaeger collector.
+	TracingTypeJaeger TracingType = "JAEGER"
+)
+
+// TracingSpec configures the tracing provider of a Thanos component.
+// See https://thanos.io/tip/thanos/tracing.md/#configuration for relevant documentation.
+type TracingSpec struct {
+	// Type is the tracing provider.
+	// +kubebuilder:default=OTLP
+	// +kubebuilder:validation:Optional
+	Type TracingType `json:"type,omitempty"`
+	// Endpoint is the address of the collector receiving the spans, for example otel-collector.monitoring.svc:4317
+	// for OTLP, or http://jaeger-collector.monitoring.svc:14268/api/traces for Jaeger.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Required
+	Endpoint string `json:"endpoint"`
+	// Insecure disables TLS for the connection to an OTLP collector.
+	// +kubebuilder:validation:Optional
+	Insecure *bool `json:"insecure,omitempty"`
+	// SamplingRatio is the ratio of traces that are sampled, between 0 and 1.
+	// If not set, all traces are sampled.
+	// +kubebuilder:validation:Pattern=`^(0(\.[0-9]+)?|1(\.0+)?)$`
+	// +kubebuilder:validation:Optional
+	SamplingRatio *string `json:"samplingRatio,omitempty"`
+}
+
 // StatefulSetFields are the options available to all Thanos components.
 // These fields reflect runtime changes to managed StatefulSet resources.
 // +k8s:deepcopy-gen=true
@@ -155,6 +414,13 @@ type StatefulSetFields struct {
 	// any of its container crashing, for it to be considered available.
 	// +kubebuilder:validation:Optional
 	MinReadySeconds *int32 `json:"minReadySeconds,omitempty"`
+	// UpdateStrategy is the strategy used to update the pods of the StatefulSet.
+	// A RollingUpdate with a partition only updates the pods with an ordinal greater than or equal to the
+	// partition, and OnDelete only updates pods once they are deleted, which allows staging upgrades.
+	// If not specified, pods are updated with a RollingUpdate.
+	// +kubebuilder:validation:XValidation:rule="!has(self.rollingUpdate) || !has(self.type) || self.type == 'RollingUpdate'",message="rollingUpdate can only be set with the RollingUpdate strategy"
+	// +kubebuilder:validation:Optional
+	UpdateStrategy *appsv1.StatefulSetUpdateStrategy `json:"updateStrategy,omitempty"`
 }
 
 // PersistentVolumeClaimRetentionPolicyType is a string enumeration of the policies that will determine
@@ -210,6 +476,8 @@ const (
 type Additional struct {
 	// Additional arguments to pass to the Thanos components.
 	// An additional argument will override an existing argument provided by the operator if there is a conflict.
+	// Arguments must be flags of the form --flag or --flag=value.
+	// +kubebuilder:validation:items:Pattern=`^--[a-z]`
 	// +kubebuilder:validation:Optional
 	Args []string `json:"additionalArgs,omitempty"`
 	// Additional containers to add to the Thanos components.
@@ -230,6 +498,11 @@ type Additional struct {
 	// controlled by the operator.
 	// +kubebuilder:validation:Optional
 	Env []corev1.EnvVar `json:"additionalEnv,omitempty"`
+	// Additional sources of environment variables, such as ConfigMaps and Secrets, to add to the Thanos component
+	// container in a Deployment or StatefulSet controlled by the operator.
+	// Variables set in additionalEnv take precedence over those of the sources.
+	// +kubebuilder:validation:Optional
+	EnvFrom []corev1.EnvFromSource `json:"additionalEnvFrom,omitempty"`
 	// AdditionalServicePorts are additional ports to expose on the Service for the Thanos component.
 	// +kubebuilder:validation:Optional
 	ServicePorts []corev1.ServicePort `json:"additionalServicePorts,omitempty"`
@@ -245,12 +518,203 @@ type Additional struct {
 	Secrets []string `json:"secrets,omitempty"`
 }
 
+// ServiceAccountConfig configures the ServiceAccount used by the pods of a Thanos component.
+// +kubebuilder:validation:XValidation:rule="!has(self.name) || !has(self.annotations)",message="annotations cannot be set when using an existing ServiceAccount"
+type ServiceAccountConfig struct {
+	// Name is the name of an existing ServiceAccount in the namespace of the resource used by the pods.
+	// If set, the operator does not create a ServiceAccount.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	Name *string `json:"name,omitempty"`
+	// Annotations are added to the ServiceAccount created by the operator, for example to bind it
+	// to a cloud IAM role with workload identity.
+	// +kubebuilder:validation:Optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// TLSConfig is the configuration for a TLS server exposed by a Thanos component.
+type TLSConfig struct {
+	// CertSecret is the name of the Secret holding the server certificate and private key.
+	// The Secret must be in the same namespace as the resource and contain the tls.crt and tls.key keys,
+	// which is the case for Secrets of type kubernetes.io/tls.
+	// Thanos reloads the certificate and key when the Secret is updated.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Required
+	CertSecret string `json:"certSecret"`
+	// ClientCA references the CA certificate used to verify client certificates.
+	// When set, clients must present a certificate signed by this CA.
+	// The CA is only read at startup, so the operator rolls the pods when its contents change.
+	// +kubebuilder:validation:Optional
+	ClientCA *corev1.SecretKeySelector `json:"clientCA,omitempty"`
+}
+
+// GRPCClientTLSConfig is the configuration for TLS on the gRPC connections of a Thanos component to its peers.
+type GRPCClientTLSConfig struct {
+	// CertSecret is the name of the Secret holding the client certificate and private key in the tls.crt and tls.key keys.
+	// The certificate is presented to servers that require client certificates.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Optional
+	CertSecret *string `json:"certSecret,omitempty"`
+	// CA references the CA certificate used to verify the server certificates.
+	// The system roots are used if not set.
+	// The CA is only read at startup, so the operator rolls the pods when its contents change.
+	// +kubebuilder:validation:Optional
+	CA *corev1.SecretKeySelector `json:"ca,omitempty"`
+	// ServerName is the name used to verify the server certificates, instead of the host of the address dialed.
+	// +kubebuilder:validation:Optional
+	ServerName *string `json:"serverName,omitempty"`
+}
+
 // PodDisruptionBudgetConfig is the configuration for the PodDisruptionBudget.
 // +kubebuilder:validation:Optional
+// +kubebuilder:validation:XValidation:rule="!(has(self.minAvailable) && has(self.maxUnavailable))",message="minAvailable and maxUnavailable are mutually exclusive"
 type PodDisruptionBudgetConfig struct {
 	// Enabled enables the creation of a PodDisruptionBudget for the Thanos component.
 	// +kubebuilder:validation:Optional
 	Enable *bool `json:"enable,omitempty"`
+	// MinAvailable is the minimum number of pods that must still be available during a disruption.
+	// Mutually exclusive with maxUnavailable.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Optional
+	MinAvailable *int32 `json:"minAvailable,omitempty"`
+	// MaxUnavailable is the maximum number of pods that can be unavailable during a disruption.
+	// When neither minAvailable nor maxUnavailable is set, the operator picks maxUnavailable.
+	// Mutually exclusive with minAvailable.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Optional
+	MaxUnavailable *int32 `json:"maxUnavailable,omitempty"`
+}
+
+// MetricsServiceConfig is the configuration for the dedicated metrics Service.
+type MetricsServiceConfig struct {
+	// Enable enables the creation of a dedicated metrics Service for the Thanos component.
+	// +kubebuilder:validation:Optional
+	Enable *bool `json:"enable,omitempty"`
+}
+
+// ServiceConfig configures how the Service of a Thanos component is exposed.
+// +kubebuilder:validation:XValidation:rule="self.type != 'ClusterIP' || !has(self.ports) || self.ports.all(p, !has(p.nodePort))",message="nodePort cannot be set when the Service type is ClusterIP"
+// +kubebuilder:validation:XValidation:rule="self.type == 'LoadBalancer' || !has(self.loadBalancerSourceRanges)",message="loadBalancerSourceRanges can only be set when the Service type is LoadBalancer"
+type ServiceConfig struct {
+	// Type is the type of the Service. Set to LoadBalancer or NodePort to expose the component outside of the cluster.
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	// +kubebuilder:default=ClusterIP
+	// +kubebuilder:validation:Optional
+	Type corev1.ServiceType `json:"type,omitempty"`
+	// Annotations are added to the Service, for example to configure the load balancer of the cloud provider.
+	// +kubebuilder:validation:Optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Ports overrides the ports of the Service by name. The Service forwards the overridden ports
+	// to the unchanged container ports.
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:Optional
+	Ports []ServicePortConfig `json:"ports,omitempty"`
+	// LoadBalancerSourceRanges restricts the client IP ranges allowed to reach a LoadBalancer Service.
+	// +kubebuilder:validation:Optional
+	LoadBalancerSourceRanges []string `json:"loadBalancerSourceRanges,omitempty"`
+	// ExternalTrafficPolicy describes how nodes distribute the traffic they receive on the external addresses
+	// of a NodePort or LoadBalancer Service. When set to Local, the client IP is preserved.
+	// +kubebuilder:validation:Enum=Cluster;Local
+	// +kubebuilder:validation:Optional
+	ExternalTrafficPolicy *corev1.ServiceExternalTrafficPolicy `json:"externalTrafficPolicy,omitempty"`
+	// Hostname is the external DNS name of the Service. It is set as the external-dns.alpha.kubernetes.io/hostname
+	// annotation, so that ExternalDNS publishes a record pointing at the address of the Service.
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^(\*\.)?[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	// +kubebuilder:validation:Optional
+	Hostname *string `json:"hostname,omitempty"`
+}
+
+// ServicePortConfig overrides a port of a Service.
+type ServicePortConfig struct {
+	// Name is the name of the port to override, such as remote-write, http or grpc.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Port is the port exposed by the Service.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:validation:Required
+	Port int32 `json:"port"`
+	// NodePort is the port on each node on which the port is exposed for NodePort and LoadBalancer Services.
+	// Kubernetes allocates one if not set.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:validation:Optional
+	NodePort *int32 `json:"nodePort,omitempty"`
+}
+
+// IngressType is the kind of object generated to expose a Thanos component outside of the cluster.
+type IngressType string
+
+const (
+	// IngressTypeIngress generates a networking.k8s.io Ingress.
+	IngressTypeIngress IngressType = "Ingress"
+	// IngressTypeHTTPRoute generates a Gateway API HTTPRoute.
+	IngressTypeHTTPRoute IngressType = "HTTPRoute"
+)
+
+// IngressConfig configures an Ingress or a Gateway API HTTPRoute routing the given hosts to a Thanos component.
+// +kubebuilder:validation:XValidation:rule="self.type == 'Ingress' || (has(self.parentRefs) && !has(self.className) && !has(self.tlsSecret))",message="parentRefs is required, and className and tlsSecret cannot be set, when the type is HTTPRoute"
+// +kubebuilder:validation:XValidation:rule="self.type == 'HTTPRoute' || !has(self.parentRefs)",message="parentRefs can only be set when the type is HTTPRoute"
+type IngressConfig struct {
+	// Type is the kind of object generated. HTTPRoute requires the gateway-api feature to be enabled on the operator.
+	// +kubebuilder:validation:Enum=Ingress;HTTPRoute
+	// +kubebuilder:default=Ingress
+	// +kubebuilder:validation:Optional
+	Type IngressType `json:"type,omitempty"`
+	// Hosts are the host names routed to the component.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:Required
+	// +listType=set
+	Hosts []string `json:"hosts"`
+	// Annotations are added to the generated object, for example to configure the ingress controller.
+	// +kubebuilder:validation:Optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// ClassName is the name of the IngressClass of the Ingress. The default IngressClass of the cluster is used if not set.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Optional
+	ClassName *string `json:"className,omitempty"`
+	// TLSSecret is the name of a Secret in the namespace of the resource holding the certificate served for the hosts
+	// by the Ingress. The hosts are served over plain HTTP if not set.
+	// The TLS of an HTTPRoute is terminated by the listener of the Gateway it is attached to.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Optional
+	TLSSecret *string `json:"tlsSecret,omitempty"`
+	// ParentRefs are the Gateways the HTTPRoute is attached to.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:Optional
+	ParentRefs []GatewayReference `json:"parentRefs,omitempty"`
+}
+
+// GatewayReference references a listener of a Gateway API Gateway.
+type GatewayReference struct {
+	// Name is the name of the Gateway.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Namespace is the namespace of the Gateway. Defaults to the namespace of the resource.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Optional
+	Namespace *string `json:"namespace,omitempty"`
+	// SectionName is the name of the listener of the Gateway to attach to, such as an HTTPS listener.
+	// The HTTPRoute is attached to all the listeners that allow it if not set.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Optional
+	SectionName *string `json:"sectionName,omitempty"`
+}
+
+// PortOr returns the port the Service exposes for the named port, or def if it is not overridden.
+func (s *ServiceConfig) PortOr(name string, def int32) int32 {
+	if s == nil {
+		return def
+	}
+	for _, p := range s.Ports {
+		if p.Name == name {
+			return p.Port
+		}
+	}
+	return def
 }
 
 func (osc *ObjectStorageConfig) ToSecretKeySelector() corev1.SecretKeySelector {
@@ -345,4 +809,22 @@ type DeploymentStatus struct {
 	UnavailableReplicas int32 `json:"unavailableReplicas"`
 	// ReadyReplicas is the number of pods created for this Deployment with a Ready Condition.
 	ReadyReplicas int32 `json:"readyReplicas"`
+	// Selector is the label selector of the pods of the Deployment, in the string form read by the scale subresource.
+	// +kubebuilder:validation:Optional
+	Selector string `json:"selector,omitempty"`
+}
+
+// EndpointHealthStatus is the health of a gRPC endpoint checked by the operator.
+type EndpointHealthStatus struct {
+	// Name is the name of the endpoint, which is the name of its Service or Pod.
+	Name string `json:"name"`
+	// Address is the address the endpoint was checked at.
+	Address string `json:"address"`
+	// Healthy is true if the endpoint reported that it is serving at the last check.
+	Healthy bool `json:"healthy"`
+	// LastCheckTime is the time of the last check.
+	LastCheckTime metav1.Time `json:"lastCheckTime"`
+	// Message is the reason the endpoint is unhealthy.
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
 }
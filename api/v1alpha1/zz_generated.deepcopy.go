@@ -21,7 +21,9 @@ limitations under the License.
 package v1alpha1
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -67,6 +69,13 @@ func (in *Additional) DeepCopyInto(out *Additional) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]corev1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.ServicePorts != nil {
 		in, out := &in.ServicePorts, &out.ServicePorts
 		*out = make([]corev1.ServicePort, len(*in))
@@ -194,6 +203,20 @@ func (in *CommonFields) DeepCopyInto(out *CommonFields) {
 		*out = make([]corev1.LocalObjectReference, len(*in))
 		copy(*out, *in)
 	}
+	if in.ServiceAccountAnnotations != nil {
+		in, out := &in.ServiceAccountAnnotations, &out.ServiceAccountAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ServiceAnnotations != nil {
+		in, out := &in.ServiceAnnotations, &out.ServiceAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.ResourceRequirements != nil {
 		in, out := &in.ResourceRequirements, &out.ResourceRequirements
 		*out = new(corev1.ResourceRequirements)
@@ -240,11 +263,41 @@ func (in *CommonFields) DeepCopyInto(out *CommonFields) {
 		*out = new(corev1.PodSecurityContext)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ContainerSecurityContext != nil {
+		in, out := &in.ContainerSecurityContext, &out.ContainerSecurityContext
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PriorityClassName != nil {
+		in, out := &in.PriorityClassName, &out.PriorityClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.TracingConfig != nil {
+		in, out := &in.TracingConfig, &out.TracingConfig
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tracing != nil {
+		in, out := &in.Tracing, &out.Tracing
+		*out = new(TracingSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.PodDisruptionBudgetConfig != nil {
 		in, out := &in.PodDisruptionBudgetConfig, &out.PodDisruptionBudgetConfig
 		*out = new(PodDisruptionBudgetConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MetricsService != nil {
+		in, out := &in.MetricsService, &out.MetricsService
+		*out = new(MetricsServiceConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = new(ProbesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Labels != nil {
 		in, out := &in.Labels, &out.Labels
 		*out = make(map[string]string, len(*in))
@@ -336,6 +389,31 @@ func (in *DebugConfig) DeepCopy() *DebugConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeploymentFields) DeepCopyInto(out *DeploymentFields) {
+	*out = *in
+	if in.TerminationGracePeriodSeconds != nil {
+		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Strategy != nil {
+		in, out := &in.Strategy, &out.Strategy
+		*out = new(appsv1.DeploymentStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentFields.
+func (in *DeploymentFields) DeepCopy() *DeploymentFields {
+	if in == nil {
+		return nil
+	}
+	out := new(DeploymentFields)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeploymentStatus) DeepCopyInto(out *DeploymentStatus) {
 	*out = *in
@@ -376,6 +454,57 @@ func (in *DownsamplingConfig) DeepCopy() *DownsamplingConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EndpointAddressConfig) DeepCopyInto(out *EndpointAddressConfig) {
+	*out = *in
+	if in.ReplicationProtocol != nil {
+		in, out := &in.ReplicationProtocol, &out.ReplicationProtocol
+		*out = new(ReplicationProtocol)
+		**out = **in
+	}
+	if in.HostFormat != nil {
+		in, out := &in.HostFormat, &out.HostFormat
+		*out = new(EndpointHostFormat)
+		**out = **in
+	}
+	if in.GRPCPort != nil {
+		in, out := &in.GRPCPort, &out.GRPCPort
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CapnProtoPort != nil {
+		in, out := &in.CapnProtoPort, &out.CapnProtoPort
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointAddressConfig.
+func (in *EndpointAddressConfig) DeepCopy() *EndpointAddressConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointAddressConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EndpointHealthStatus) DeepCopyInto(out *EndpointHealthStatus) {
+	*out = *in
+	in.LastCheckTime.DeepCopyInto(&out.LastCheckTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointHealthStatus.
+func (in *EndpointHealthStatus) DeepCopy() *EndpointHealthStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointHealthStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalLabelShardingConfig) DeepCopyInto(out *ExternalLabelShardingConfig) {
 	*out = *in
@@ -412,6 +541,146 @@ func (in ExternalLabels) DeepCopy() ExternalLabels {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GRPCClientTLSConfig) DeepCopyInto(out *GRPCClientTLSConfig) {
+	*out = *in
+	if in.CertSecret != nil {
+		in, out := &in.CertSecret, &out.CertSecret
+		*out = new(string)
+		**out = **in
+	}
+	if in.CA != nil {
+		in, out := &in.CA, &out.CA
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServerName != nil {
+		in, out := &in.ServerName, &out.ServerName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GRPCClientTLSConfig.
+func (in *GRPCClientTLSConfig) DeepCopy() *GRPCClientTLSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GRPCClientTLSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayReference) DeepCopyInto(out *GatewayReference) {
+	*out = *in
+	if in.Namespace != nil {
+		in, out := &in.Namespace, &out.Namespace
+		*out = new(string)
+		**out = **in
+	}
+	if in.SectionName != nil {
+		in, out := &in.SectionName, &out.SectionName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayReference.
+func (in *GatewayReference) DeepCopy() *GatewayReference {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlobalWriteLimits) DeepCopyInto(out *GlobalWriteLimits) {
+	*out = *in
+	if in.MaxConcurrency != nil {
+		in, out := &in.MaxConcurrency, &out.MaxConcurrency
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MetaMonitoringURL != nil {
+		in, out := &in.MetaMonitoringURL, &out.MetaMonitoringURL
+		*out = new(string)
+		**out = **in
+	}
+	if in.MetaMonitoringLimitQuery != nil {
+		in, out := &in.MetaMonitoringLimitQuery, &out.MetaMonitoringLimitQuery
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalWriteLimits.
+func (in *GlobalWriteLimits) DeepCopy() *GlobalWriteLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(GlobalWriteLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HashringAutoscalingSpec) DeepCopyInto(out *HashringAutoscalingSpec) {
+	*out = *in
+	if in.ConfigDebounce != nil {
+		in, out := &in.ConfigDebounce, &out.ConfigDebounce
+		*out = new(Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HashringAutoscalingSpec.
+func (in *HashringAutoscalingSpec) DeepCopy() *HashringAutoscalingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HashringAutoscalingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HashringRolloutSpec) DeepCopyInto(out *HashringRolloutSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HashringRolloutSpec.
+func (in *HashringRolloutSpec) DeepCopy() *HashringRolloutSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HashringRolloutSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HashringRolloutStatus) DeepCopyInto(out *HashringRolloutStatus) {
+	*out = *in
+	if in.Pending != nil {
+		in, out := &in.Pending, &out.Pending
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HashringRolloutStatus.
+func (in *HashringRolloutStatus) DeepCopy() *HashringRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HashringRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InMemoryCacheConfig) DeepCopyInto(out *InMemoryCacheConfig) {
 	*out = *in
@@ -478,13 +747,18 @@ func (in *IngesterHashringSpec) DeepCopyInto(out *IngesterHashringSpec) {
 			(*out)[key] = val
 		}
 	}
-	out.TSDBConfig = in.TSDBConfig
+	in.TSDBConfig.DeepCopyInto(&out.TSDBConfig)
 	if in.ObjectStorageConfig != nil {
 		in, out := &in.ObjectStorageConfig, &out.ObjectStorageConfig
 		*out = new(ObjectStorageConfig)
 		(*in).DeepCopyInto(*out)
 	}
 	in.StorageConfiguration.DeepCopyInto(&out.StorageConfiguration)
+	if in.PersistentVolumeClaimRetentionPolicy != nil {
+		in, out := &in.PersistentVolumeClaimRetentionPolicy, &out.PersistentVolumeClaimRetentionPolicy
+		*out = new(PersistentVolumeClaimRetentionPolicy)
+		**out = **in
+	}
 	if in.TenancyConfig != nil {
 		in, out := &in.TenancyConfig, &out.TenancyConfig
 		*out = new(TenancyConfig)
@@ -515,6 +789,65 @@ func (in *IngesterHashringSpec) DeepCopyInto(out *IngesterHashringSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.EndpointAddress != nil {
+		in, out := &in.EndpointAddress, &out.EndpointAddress
+		*out = new(EndpointAddressConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceAccount != nil {
+		in, out := &in.ServiceAccount, &out.ServiceAccount
+		*out = new(ServiceAccountConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(appsv1.StatefulSetUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EndpointPolicy != nil {
+		in, out := &in.EndpointPolicy, &out.EndpointPolicy
+		*out = new(HashringEndpointPolicy)
+		**out = **in
+	}
+	if in.MinReadyReplicas != nil {
+		in, out := &in.MinReadyReplicas, &out.MinReadyReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ScaleDownGracePeriod != nil {
+		in, out := &in.ScaleDownGracePeriod, &out.ScaleDownGracePeriod
+		*out = new(Duration)
+		**out = **in
+	}
+	if in.UploadConcurrency != nil {
+		in, out := &in.UploadConcurrency, &out.UploadConcurrency
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(HashringAutoscalingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalArgs != nil {
+		in, out := &in.AdditionalArgs, &out.AdditionalArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AdditionalVolumes != nil {
+		in, out := &in.AdditionalVolumes, &out.AdditionalVolumes
+		*out = make([]corev1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdditionalVolumeMounts != nil {
+		in, out := &in.AdditionalVolumeMounts, &out.AdditionalVolumeMounts
+		*out = make([]corev1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngesterHashringSpec.
@@ -538,6 +871,46 @@ func (in *IngesterSpec) DeepCopyInto(out *IngesterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.HashringUpdateInterval != nil {
+		in, out := &in.HashringUpdateInterval, &out.HashringUpdateInterval
+		*out = new(Duration)
+		**out = **in
+	}
+	if in.ShutdownDrainSeconds != nil {
+		in, out := &in.ShutdownDrainSeconds, &out.ShutdownDrainSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SpreadAcrossZones != nil {
+		in, out := &in.SpreadAcrossZones, &out.SpreadAcrossZones
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ScaleDownStrategy != nil {
+		in, out := &in.ScaleDownStrategy, &out.ScaleDownStrategy
+		*out = new(ScaleDownStrategy)
+		**out = **in
+	}
+	if in.UploadLagChecks != nil {
+		in, out := &in.UploadLagChecks, &out.UploadLagChecks
+		*out = new(bool)
+		**out = **in
+	}
+	if in.UploadLagThreshold != nil {
+		in, out := &in.UploadLagThreshold, &out.UploadLagThreshold
+		*out = new(Duration)
+		**out = **in
+	}
+	if in.HealthChecks != nil {
+		in, out := &in.HealthChecks, &out.HealthChecks
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(HashringRolloutSpec)
+		**out = **in
+	}
 	in.Additional.DeepCopyInto(&out.Additional)
 }
 
@@ -552,37 +925,196 @@ func (in *IngesterSpec) DeepCopy() *IngesterSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObjectStorageConfig) DeepCopyInto(out *ObjectStorageConfig) {
+func (in *IngesterVolumeStatus) DeepCopyInto(out *IngesterVolumeStatus) {
 	*out = *in
-	out.LocalObjectReference = in.LocalObjectReference
-	if in.Optional != nil {
-		in, out := &in.Optional, &out.Optional
-		*out = new(bool)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStorageConfig.
-func (in *ObjectStorageConfig) DeepCopy() *ObjectStorageConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngesterVolumeStatus.
+func (in *IngesterVolumeStatus) DeepCopy() *IngesterVolumeStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ObjectStorageConfig)
+	out := new(IngesterVolumeStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PersistentVolumeClaimRetentionPolicy) DeepCopyInto(out *PersistentVolumeClaimRetentionPolicy) {
+func (in *IngressConfig) DeepCopyInto(out *IngressConfig) {
 	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistentVolumeClaimRetentionPolicy.
-func (in *PersistentVolumeClaimRetentionPolicy) DeepCopy() *PersistentVolumeClaimRetentionPolicy {
-	if in == nil {
-		return nil
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	out := new(PersistentVolumeClaimRetentionPolicy)
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ClassName != nil {
+		in, out := &in.ClassName, &out.ClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.TLSSecret != nil {
+		in, out := &in.TLSSecret, &out.TLSSecret
+		*out = new(string)
+		**out = **in
+	}
+	if in.ParentRefs != nil {
+		in, out := &in.ParentRefs, &out.ParentRefs
+		*out = make([]GatewayReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressConfig.
+func (in *IngressConfig) DeepCopy() *IngressConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedCacheSpec) DeepCopyInto(out *ManagedCacheSpec) {
+	*out = *in
+	if in.Managed != nil {
+		in, out := &in.Managed, &out.Managed
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(string)
+		**out = **in
+	}
+	if in.MemoryLimit != nil {
+		in, out := &in.MemoryLimit, &out.MemoryLimit
+		*out = new(StorageSize)
+		**out = **in
+	}
+	if in.MaxItemSize != nil {
+		in, out := &in.MaxItemSize, &out.MaxItemSize
+		*out = new(StorageSize)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedCacheSpec.
+func (in *ManagedCacheSpec) DeepCopy() *ManagedCacheSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedCacheSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsServiceConfig) DeepCopyInto(out *MetricsServiceConfig) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsServiceConfig.
+func (in *MetricsServiceConfig) DeepCopy() *MetricsServiceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsServiceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectStorageConfig) DeepCopyInto(out *ObjectStorageConfig) {
+	*out = *in
+	out.LocalObjectReference = in.LocalObjectReference
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Inline != nil {
+		in, out := &in.Inline, &out.Inline
+		*out = new(string)
+		**out = **in
+	}
+	if in.Optional != nil {
+		in, out := &in.Optional, &out.Optional
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Mode != nil {
+		in, out := &in.Mode, &out.Mode
+		*out = new(ObjectStorageConfigMode)
+		**out = **in
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]ObjectStorageEnvVar, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStorageConfig.
+func (in *ObjectStorageConfig) DeepCopy() *ObjectStorageConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectStorageConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectStorageEnvVar) DeepCopyInto(out *ObjectStorageEnvVar) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStorageEnvVar.
+func (in *ObjectStorageEnvVar) DeepCopy() *ObjectStorageEnvVar {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectStorageEnvVar)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersistentVolumeClaimRetentionPolicy) DeepCopyInto(out *PersistentVolumeClaimRetentionPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistentVolumeClaimRetentionPolicy.
+func (in *PersistentVolumeClaimRetentionPolicy) DeepCopy() *PersistentVolumeClaimRetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PersistentVolumeClaimRetentionPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -595,6 +1127,16 @@ func (in *PodDisruptionBudgetConfig) DeepCopyInto(out *PodDisruptionBudgetConfig
 		*out = new(bool)
 		**out = **in
 	}
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDisruptionBudgetConfig.
@@ -608,157 +1150,1040 @@ func (in *PodDisruptionBudgetConfig) DeepCopy() *PodDisruptionBudgetConfig {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *QueryFrontendSpec) DeepCopyInto(out *QueryFrontendSpec) {
+func (in *ProbeSpec) DeepCopyInto(out *ProbeSpec) {
 	*out = *in
-	in.CommonFields.DeepCopyInto(&out.CommonFields)
-	if in.QueryLabelSelector != nil {
-		in, out := &in.QueryLabelSelector, &out.QueryLabelSelector
-		*out = new(v1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.LogQueriesLongerThan != nil {
-		in, out := &in.LogQueriesLongerThan, &out.LogQueriesLongerThan
-		*out = new(Duration)
+	if in.InitialDelaySeconds != nil {
+		in, out := &in.InitialDelaySeconds, &out.InitialDelaySeconds
+		*out = new(int32)
 		**out = **in
 	}
-	if in.QueryRangeResponseCacheConfig != nil {
-		in, out := &in.QueryRangeResponseCacheConfig, &out.QueryRangeResponseCacheConfig
-		*out = new(CacheConfig)
-		(*in).DeepCopyInto(*out)
+	if in.PeriodSeconds != nil {
+		in, out := &in.PeriodSeconds, &out.PeriodSeconds
+		*out = new(int32)
+		**out = **in
 	}
-	if in.QueryRangeSplitInterval != nil {
-		in, out := &in.QueryRangeSplitInterval, &out.QueryRangeSplitInterval
-		*out = new(Duration)
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
 		**out = **in
 	}
-	if in.LabelsSplitInterval != nil {
-		in, out := &in.LabelsSplitInterval, &out.LabelsSplitInterval
-		*out = new(Duration)
+	if in.SuccessThreshold != nil {
+		in, out := &in.SuccessThreshold, &out.SuccessThreshold
+		*out = new(int32)
 		**out = **in
 	}
-	if in.LabelsDefaultTimeRange != nil {
-		in, out := &in.LabelsDefaultTimeRange, &out.LabelsDefaultTimeRange
-		*out = new(Duration)
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int32)
 		**out = **in
 	}
-	in.Additional.DeepCopyInto(&out.Additional)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryFrontendSpec.
-func (in *QueryFrontendSpec) DeepCopy() *QueryFrontendSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeSpec.
+func (in *ProbeSpec) DeepCopy() *ProbeSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(QueryFrontendSpec)
+	out := new(ProbeSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RetentionResolutionConfig) DeepCopyInto(out *RetentionResolutionConfig) {
+func (in *ProbesSpec) DeepCopyInto(out *ProbesSpec) {
 	*out = *in
+	if in.Liveness != nil {
+		in, out := &in.Liveness, &out.Liveness
+		*out = new(ProbeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(ProbeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Startup != nil {
+		in, out := &in.Startup, &out.Startup
+		*out = new(ProbeSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetentionResolutionConfig.
-func (in *RetentionResolutionConfig) DeepCopy() *RetentionResolutionConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbesSpec.
+func (in *ProbesSpec) DeepCopy() *ProbesSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RetentionResolutionConfig)
+	out := new(ProbesSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RouterSpec) DeepCopyInto(out *RouterSpec) {
+func (in *QueryEndpointStatus) DeepCopyInto(out *QueryEndpointStatus) {
 	*out = *in
-	in.CommonFields.DeepCopyInto(&out.CommonFields)
-	if in.ReplicationProtocol != nil {
-		in, out := &in.ReplicationProtocol, &out.ReplicationProtocol
-		*out = new(ReplicationProtocol)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryEndpointStatus.
+func (in *QueryEndpointStatus) DeepCopy() *QueryEndpointStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryEndpointStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryFederationSpec) DeepCopyInto(out *QueryFederationSpec) {
+	*out = *in
+	if in.Remotes != nil {
+		in, out := &in.Remotes, &out.Remotes
+		*out = make([]RemoteQueryEndpoint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryFederationSpec.
+func (in *QueryFederationSpec) DeepCopy() *QueryFederationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryFederationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryFrontendDownstreamConfig) DeepCopyInto(out *QueryFrontendDownstreamConfig) {
+	*out = *in
+	if in.ResponseHeaderTimeout != nil {
+		in, out := &in.ResponseHeaderTimeout, &out.ResponseHeaderTimeout
+		*out = new(Duration)
 		**out = **in
 	}
-	if in.HashringPolicy != nil {
-		in, out := &in.HashringPolicy, &out.HashringPolicy
-		*out = new(HashringPolicy)
+	if in.IdleConnectionTimeout != nil {
+		in, out := &in.IdleConnectionTimeout, &out.IdleConnectionTimeout
+		*out = new(Duration)
 		**out = **in
 	}
-	if in.ExternalLabels != nil {
-		in, out := &in.ExternalLabels, &out.ExternalLabels
-		*out = make(ExternalLabels, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.MaxConnectionsPerHost != nil {
+		in, out := &in.MaxConnectionsPerHost, &out.MaxConnectionsPerHost
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxIdleConnectionsPerHost != nil {
+		in, out := &in.MaxIdleConnectionsPerHost, &out.MaxIdleConnectionsPerHost
+		*out = new(int32)
+		**out = **in
 	}
-	in.Additional.DeepCopyInto(&out.Additional)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouterSpec.
-func (in *RouterSpec) DeepCopy() *RouterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryFrontendDownstreamConfig.
+func (in *QueryFrontendDownstreamConfig) DeepCopy() *QueryFrontendDownstreamConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(RouterSpec)
+	out := new(QueryFrontendDownstreamConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RuleTenancyConfig) DeepCopyInto(out *RuleTenancyConfig) {
+func (in *QueryFrontendSpec) DeepCopyInto(out *QueryFrontendSpec) {
 	*out = *in
-	if in.EnforcedTenantIdentifier != nil {
-		in, out := &in.EnforcedTenantIdentifier, &out.EnforcedTenantIdentifier
-		*out = new(string)
+	in.CommonFields.DeepCopyInto(&out.CommonFields)
+	in.DeploymentFields.DeepCopyInto(&out.DeploymentFields)
+	if in.QueryLabelSelector != nil {
+		in, out := &in.QueryLabelSelector, &out.QueryLabelSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LogQueriesLongerThan != nil {
+		in, out := &in.LogQueriesLongerThan, &out.LogQueriesLongerThan
+		*out = new(Duration)
 		**out = **in
 	}
-	if in.TenantSpecifierLabel != nil {
-		in, out := &in.TenantSpecifierLabel, &out.TenantSpecifierLabel
-		*out = new(string)
+	if in.QueryRangeResponseCacheConfig != nil {
+		in, out := &in.QueryRangeResponseCacheConfig, &out.QueryRangeResponseCacheConfig
+		*out = new(CacheConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(ManagedCacheSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.QueryRangeSplitInterval != nil {
+		in, out := &in.QueryRangeSplitInterval, &out.QueryRangeSplitInterval
+		*out = new(Duration)
 		**out = **in
 	}
+	if in.LabelsSplitInterval != nil {
+		in, out := &in.LabelsSplitInterval, &out.LabelsSplitInterval
+		*out = new(Duration)
+		**out = **in
+	}
+	if in.LabelsDefaultTimeRange != nil {
+		in, out := &in.LabelsDefaultTimeRange, &out.LabelsDefaultTimeRange
+		*out = new(Duration)
+		**out = **in
+	}
+	if in.QueryRangeMaxQueryParallelism != nil {
+		in, out := &in.QueryRangeMaxQueryParallelism, &out.QueryRangeMaxQueryParallelism
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LabelsMaxQueryParallelism != nil {
+		in, out := &in.LabelsMaxQueryParallelism, &out.LabelsMaxQueryParallelism
+		*out = new(int32)
+		**out = **in
+	}
+	if in.QueryRangeMaxQueryLength != nil {
+		in, out := &in.QueryRangeMaxQueryLength, &out.QueryRangeMaxQueryLength
+		*out = new(Duration)
+		**out = **in
+	}
+	if in.DownstreamConfig != nil {
+		in, out := &in.DownstreamConfig, &out.DownstreamConfig
+		*out = new(QueryFrontendDownstreamConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SessionAffinity != nil {
+		in, out := &in.SessionAffinity, &out.SessionAffinity
+		*out = new(SessionAffinityConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ServiceConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Additional.DeepCopyInto(&out.Additional)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryFrontendSpec.
+func (in *QueryFrontendSpec) DeepCopy() *QueryFrontendSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryFrontendSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadProbeSpec) DeepCopyInto(out *ReadProbeSpec) {
+	*out = *in
+	if in.Query != nil {
+		in, out := &in.Query, &out.Query
+		*out = new(string)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(Duration)
+		**out = **in
+	}
+	if in.Range != nil {
+		in, out := &in.Range, &out.Range
+		*out = new(Duration)
+		**out = **in
+	}
+	if in.InstantQueryLatencyThreshold != nil {
+		in, out := &in.InstantQueryLatencyThreshold, &out.InstantQueryLatencyThreshold
+		*out = new(Duration)
+		**out = **in
+	}
+	if in.RangeQueryLatencyThreshold != nil {
+		in, out := &in.RangeQueryLatencyThreshold, &out.RangeQueryLatencyThreshold
+		*out = new(Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadProbeSpec.
+func (in *ReadProbeSpec) DeepCopy() *ReadProbeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadProbeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadProbeStatus) DeepCopyInto(out *ReadProbeStatus) {
+	*out = *in
+	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
+	if in.InstantQueryLatency != nil {
+		in, out := &in.InstantQueryLatency, &out.InstantQueryLatency
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RangeQueryLatency != nil {
+		in, out := &in.RangeQueryLatency, &out.RangeQueryLatency
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadProbeStatus.
+func (in *ReadProbeStatus) DeepCopy() *ReadProbeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadProbeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReceiveDeletionSpec) DeepCopyInto(out *ReceiveDeletionSpec) {
+	*out = *in
+	if in.FlushIngesters != nil {
+		in, out := &in.FlushIngesters, &out.FlushIngesters
+		*out = new(bool)
+		**out = **in
+	}
+	if in.VolumeRetentionPolicy != nil {
+		in, out := &in.VolumeRetentionPolicy, &out.VolumeRetentionPolicy
+		*out = new(VolumeRetentionPolicy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReceiveDeletionSpec.
+func (in *ReceiveDeletionSpec) DeepCopy() *ReceiveDeletionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReceiveDeletionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReceiveGRPCTLSConfig) DeepCopyInto(out *ReceiveGRPCTLSConfig) {
+	*out = *in
+	in.Server.DeepCopyInto(&out.Server)
+	if in.Client != nil {
+		in, out := &in.Client, &out.Client
+		*out = new(GRPCClientTLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReceiveGRPCTLSConfig.
+func (in *ReceiveGRPCTLSConfig) DeepCopy() *ReceiveGRPCTLSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ReceiveGRPCTLSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReceiveLimitsSpec) DeepCopyInto(out *ReceiveLimitsSpec) {
+	*out = *in
+	if in.Global != nil {
+		in, out := &in.Global, &out.Global
+		*out = new(GlobalWriteLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(WriteLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tenants != nil {
+		in, out := &in.Tenants, &out.Tenants
+		*out = make(map[string]WriteLimits, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReceiveLimitsSpec.
+func (in *ReceiveLimitsSpec) DeepCopy() *ReceiveLimitsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReceiveLimitsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReceiveNetworkPolicySpec) DeepCopyInto(out *ReceiveNetworkPolicySpec) {
+	*out = *in
+	if in.RemoteWriteSources != nil {
+		in, out := &in.RemoteWriteSources, &out.RemoteWriteSources
+		*out = make([]networkingv1.NetworkPolicyPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ObjectStorage != nil {
+		in, out := &in.ObjectStorage, &out.ObjectStorage
+		*out = make([]networkingv1.NetworkPolicyPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReceiveNetworkPolicySpec.
+func (in *ReceiveNetworkPolicySpec) DeepCopy() *ReceiveNetworkPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReceiveNetworkPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RelabelConfig) DeepCopyInto(out *RelabelConfig) {
+	*out = *in
+	if in.SourceLabels != nil {
+		in, out := &in.SourceLabels, &out.SourceLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Separator != nil {
+		in, out := &in.Separator, &out.Separator
+		*out = new(string)
+		**out = **in
+	}
+	if in.TargetLabel != nil {
+		in, out := &in.TargetLabel, &out.TargetLabel
+		*out = new(string)
+		**out = **in
+	}
+	if in.Regex != nil {
+		in, out := &in.Regex, &out.Regex
+		*out = new(string)
+		**out = **in
+	}
+	if in.Modulus != nil {
+		in, out := &in.Modulus, &out.Modulus
+		*out = new(uint64)
+		**out = **in
+	}
+	if in.Replacement != nil {
+		in, out := &in.Replacement, &out.Replacement
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RelabelConfig.
+func (in *RelabelConfig) DeepCopy() *RelabelConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RelabelConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteEndpointStatus) DeepCopyInto(out *RemoteEndpointStatus) {
+	*out = *in
+	in.LastCheckTime.DeepCopyInto(&out.LastCheckTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteEndpointStatus.
+func (in *RemoteEndpointStatus) DeepCopy() *RemoteEndpointStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteEndpointStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteQueryEndpoint) DeepCopyInto(out *RemoteQueryEndpoint) {
+	*out = *in
+	if in.TLSSecret != nil {
+		in, out := &in.TLSSecret, &out.TLSSecret
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteQueryEndpoint.
+func (in *RemoteQueryEndpoint) DeepCopy() *RemoteQueryEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteQueryEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequestLoggingConfig) DeepCopyInto(out *RequestLoggingConfig) {
+	*out = *in
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(RequestLoggingOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GRPC != nil {
+		in, out := &in.GRPC, &out.GRPC
+		*out = new(RequestLoggingOptions)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequestLoggingConfig.
+func (in *RequestLoggingConfig) DeepCopy() *RequestLoggingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RequestLoggingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequestLoggingOptions) DeepCopyInto(out *RequestLoggingOptions) {
+	*out = *in
+	if in.LogEnd != nil {
+		in, out := &in.LogEnd, &out.LogEnd
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequestLoggingOptions.
+func (in *RequestLoggingOptions) DeepCopy() *RequestLoggingOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(RequestLoggingOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetentionResolutionConfig) DeepCopyInto(out *RetentionResolutionConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetentionResolutionConfig.
+func (in *RetentionResolutionConfig) DeepCopy() *RetentionResolutionConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RetentionResolutionConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouterGroupSpec) DeepCopyInto(out *RouterGroupSpec) {
+	*out = *in
+	if in.Tenancy != nil {
+		in, out := &in.Tenancy, &out.Tenancy
+		*out = new(RouterTenancyConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ServiceConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouterGroupSpec.
+func (in *RouterGroupSpec) DeepCopy() *RouterGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RouterGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouterSpec) DeepCopyInto(out *RouterSpec) {
+	*out = *in
+	in.CommonFields.DeepCopyInto(&out.CommonFields)
+	in.DeploymentFields.DeepCopyInto(&out.DeploymentFields)
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ReplicationProtocol != nil {
+		in, out := &in.ReplicationProtocol, &out.ReplicationProtocol
+		*out = new(ReplicationProtocol)
+		**out = **in
+	}
+	if in.AsyncForwardWorkerCount != nil {
+		in, out := &in.AsyncForwardWorkerCount, &out.AsyncForwardWorkerCount
+		*out = new(uint64)
+		**out = **in
+	}
+	if in.ReplicationMaxRetries != nil {
+		in, out := &in.ReplicationMaxRetries, &out.ReplicationMaxRetries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.HashringPolicy != nil {
+		in, out := &in.HashringPolicy, &out.HashringPolicy
+		*out = new(HashringPolicy)
+		**out = **in
+	}
+	if in.ExternalLabels != nil {
+		in, out := &in.ExternalLabels, &out.ExternalLabels
+		*out = make(ExternalLabels, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tenancy != nil {
+		in, out := &in.Tenancy, &out.Tenancy
+		*out = new(RouterTenancyConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RemoteWriteTLS != nil {
+		in, out := &in.RemoteWriteTLS, &out.RemoteWriteTLS
+		*out = new(TLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceTraffic != nil {
+		in, out := &in.ServiceTraffic, &out.ServiceTraffic
+		*out = new(ServiceTrafficConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ServiceConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExistingService != nil {
+		in, out := &in.ExistingService, &out.ExistingService
+		*out = new(string)
+		**out = **in
+	}
+	if in.ExistingHashringConfigMap != nil {
+		in, out := &in.ExistingHashringConfigMap, &out.ExistingHashringConfigMap
+		*out = new(string)
+		**out = **in
+	}
+	if in.HashringConfigReload != nil {
+		in, out := &in.HashringConfigReload, &out.HashringConfigReload
+		*out = new(HashringConfigReloadStrategy)
+		**out = **in
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(IngressConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RelabelConfigs != nil {
+		in, out := &in.RelabelConfigs, &out.RelabelConfigs
+		*out = make([]RelabelConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]RouterGroupSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Additional.DeepCopyInto(&out.Additional)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouterSpec.
+func (in *RouterSpec) DeepCopy() *RouterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RouterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouterTenancyConfig) DeepCopyInto(out *RouterTenancyConfig) {
+	*out = *in
+	if in.TenantHeader != nil {
+		in, out := &in.TenantHeader, &out.TenantHeader
+		*out = new(string)
+		**out = **in
+	}
+	if in.DefaultTenantID != nil {
+		in, out := &in.DefaultTenantID, &out.DefaultTenantID
+		*out = new(string)
+		**out = **in
+	}
+	if in.TenantLabelName != nil {
+		in, out := &in.TenantLabelName, &out.TenantLabelName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouterTenancyConfig.
+func (in *RouterTenancyConfig) DeepCopy() *RouterTenancyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RouterTenancyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleTenancyConfig) DeepCopyInto(out *RuleTenancyConfig) {
+	*out = *in
+	if in.EnforcedTenantIdentifier != nil {
+		in, out := &in.EnforcedTenantIdentifier, &out.EnforcedTenantIdentifier
+		*out = new(string)
+		**out = **in
+	}
+	if in.TenantSpecifierLabel != nil {
+		in, out := &in.TenantSpecifierLabel, &out.TenantSpecifierLabel
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleTenancyConfig.
+func (in *RuleTenancyConfig) DeepCopy() *RuleTenancyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleTenancyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccountConfig) DeepCopyInto(out *ServiceAccountConfig) {
+	*out = *in
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountConfig.
+func (in *ServiceAccountConfig) DeepCopy() *ServiceAccountConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceConfig) DeepCopyInto(out *ServiceConfig) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]ServicePortConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LoadBalancerSourceRanges != nil {
+		in, out := &in.LoadBalancerSourceRanges, &out.LoadBalancerSourceRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExternalTrafficPolicy != nil {
+		in, out := &in.ExternalTrafficPolicy, &out.ExternalTrafficPolicy
+		*out = new(corev1.ServiceExternalTrafficPolicy)
+		**out = **in
+	}
+	if in.Hostname != nil {
+		in, out := &in.Hostname, &out.Hostname
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceConfig.
+func (in *ServiceConfig) DeepCopy() *ServiceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServicePortConfig) DeepCopyInto(out *ServicePortConfig) {
+	*out = *in
+	if in.NodePort != nil {
+		in, out := &in.NodePort, &out.NodePort
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServicePortConfig.
+func (in *ServicePortConfig) DeepCopy() *ServicePortConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ServicePortConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceTrafficConfig) DeepCopyInto(out *ServiceTrafficConfig) {
+	*out = *in
+	if in.TopologyMode != nil {
+		in, out := &in.TopologyMode, &out.TopologyMode
+		*out = new(ServiceTopologyMode)
+		**out = **in
+	}
+	if in.InternalTrafficPolicy != nil {
+		in, out := &in.InternalTrafficPolicy, &out.InternalTrafficPolicy
+		*out = new(corev1.ServiceInternalTrafficPolicy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceTrafficConfig.
+func (in *ServiceTrafficConfig) DeepCopy() *ServiceTrafficConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceTrafficConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionAffinityConfig) DeepCopyInto(out *SessionAffinityConfig) {
+	*out = *in
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionAffinityConfig.
+func (in *SessionAffinityConfig) DeepCopy() *SessionAffinityConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionAffinityConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShardingConfig) DeepCopyInto(out *ShardingConfig) {
+	*out = *in
+	if in.ExternalLabelSharding != nil {
+		in, out := &in.ExternalLabelSharding, &out.ExternalLabelSharding
+		*out = make([]ExternalLabelShardingConfig, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShardingConfig.
+func (in *ShardingConfig) DeepCopy() *ShardingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ShardingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShardingStrategy) DeepCopyInto(out *ShardingStrategy) {
+	*out = *in
+	if in.TimeRanges != nil {
+		in, out := &in.TimeRanges, &out.TimeRanges
+		*out = make([]TimeRangeConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TimeRange != nil {
+		in, out := &in.TimeRange, &out.TimeRange
+		*out = new(TimeRangeConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShardingStrategy.
+func (in *ShardingStrategy) DeepCopy() *ShardingStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(ShardingStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StackCompactSpec) DeepCopyInto(out *StackCompactSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.StorageConfiguration != nil {
+		in, out := &in.StorageConfiguration, &out.StorageConfiguration
+		*out = new(StorageConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RetentionConfig != nil {
+		in, out := &in.RetentionConfig, &out.RetentionConfig
+		*out = new(RetentionResolutionConfig)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StackCompactSpec.
+func (in *StackCompactSpec) DeepCopy() *StackCompactSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StackCompactSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StackComponentSpec) DeepCopyInto(out *StackComponentSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StackComponentSpec.
+func (in *StackComponentSpec) DeepCopy() *StackComponentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StackComponentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StackQuerySpec) DeepCopyInto(out *StackQuerySpec) {
+	*out = *in
+	in.StackComponentSpec.DeepCopyInto(&out.StackComponentSpec)
+	if in.QueryFrontend != nil {
+		in, out := &in.QueryFrontend, &out.QueryFrontend
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StackQuerySpec.
+func (in *StackQuerySpec) DeepCopy() *StackQuerySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StackQuerySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StackReceiveSpec) DeepCopyInto(out *StackReceiveSpec) {
+	*out = *in
+	in.StackComponentSpec.DeepCopyInto(&out.StackComponentSpec)
+	if in.StorageConfiguration != nil {
+		in, out := &in.StorageConfiguration, &out.StorageConfiguration
+		*out = new(StorageConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleTenancyConfig.
-func (in *RuleTenancyConfig) DeepCopy() *RuleTenancyConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StackReceiveSpec.
+func (in *StackReceiveSpec) DeepCopy() *StackReceiveSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RuleTenancyConfig)
+	out := new(StackReceiveSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ShardingConfig) DeepCopyInto(out *ShardingConfig) {
+func (in *StackRulerSpec) DeepCopyInto(out *StackRulerSpec) {
 	*out = *in
-	if in.ExternalLabelSharding != nil {
-		in, out := &in.ExternalLabelSharding, &out.ExternalLabelSharding
-		*out = make([]ExternalLabelShardingConfig, len(*in))
-		copy(*out, *in)
+	if in.StorageConfiguration != nil {
+		in, out := &in.StorageConfiguration, &out.StorageConfiguration
+		*out = new(StorageConfiguration)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShardingConfig.
-func (in *ShardingConfig) DeepCopy() *ShardingConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StackRulerSpec.
+func (in *StackRulerSpec) DeepCopy() *StackRulerSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ShardingConfig)
+	out := new(StackRulerSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ShardingStrategy) DeepCopyInto(out *ShardingStrategy) {
+func (in *StackStoreSpec) DeepCopyInto(out *StackStoreSpec) {
 	*out = *in
+	in.StackComponentSpec.DeepCopyInto(&out.StackComponentSpec)
+	if in.StorageConfiguration != nil {
+		in, out := &in.StorageConfiguration, &out.StorageConfiguration
+		*out = new(StorageConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShardingStrategy.
-func (in *ShardingStrategy) DeepCopy() *ShardingStrategy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StackStoreSpec.
+func (in *StackStoreSpec) DeepCopy() *StackStoreSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ShardingStrategy)
+	out := new(StackStoreSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -786,6 +2211,11 @@ func (in *StatefulSetFields) DeepCopyInto(out *StatefulSetFields) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(appsv1.StatefulSetUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatefulSetFields.
@@ -848,9 +2278,54 @@ func (in *StoreLimitsOptions) DeepCopy() *StoreLimitsOptions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSConfig) DeepCopyInto(out *TLSConfig) {
+	*out = *in
+	if in.ClientCA != nil {
+		in, out := &in.ClientCA, &out.ClientCA
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSConfig.
+func (in *TLSConfig) DeepCopy() *TLSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TSDBConfig) DeepCopyInto(out *TSDBConfig) {
 	*out = *in
+	if in.TooFarInFutureTimeWindow != nil {
+		in, out := &in.TooFarInFutureTimeWindow, &out.TooFarInFutureTimeWindow
+		*out = new(Duration)
+		**out = **in
+	}
+	if in.OutOfOrderTimeWindow != nil {
+		in, out := &in.OutOfOrderTimeWindow, &out.OutOfOrderTimeWindow
+		*out = new(Duration)
+		**out = **in
+	}
+	if in.WALCompression != nil {
+		in, out := &in.WALCompression, &out.WALCompression
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MinBlockDuration != nil {
+		in, out := &in.MinBlockDuration, &out.MinBlockDuration
+		*out = new(Duration)
+		**out = **in
+	}
+	if in.MaxBlockDuration != nil {
+		in, out := &in.MaxBlockDuration, &out.MaxBlockDuration
+		*out = new(Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TSDBConfig.
@@ -901,6 +2376,11 @@ func (in *TenancyConfig) DeepCopyInto(out *TenancyConfig) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ExcludeTenants != nil {
+		in, out := &in.ExcludeTenants, &out.ExcludeTenants
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.TenantCertificateField != nil {
 		in, out := &in.TenantCertificateField, &out.TenantCertificateField
 		*out = new(string)
@@ -988,8 +2468,18 @@ func (in *ThanosCompactSpec) DeepCopyInto(out *ThanosCompactSpec) {
 	in.CommonFields.DeepCopyInto(&out.CommonFields)
 	in.StatefulSetFields.DeepCopyInto(&out.StatefulSetFields)
 	in.ObjectStorageConfig.DeepCopyInto(&out.ObjectStorageConfig)
+	if in.VerifyObjectStorage != nil {
+		in, out := &in.VerifyObjectStorage, &out.VerifyObjectStorage
+		*out = new(bool)
+		**out = **in
+	}
 	in.StorageConfiguration.DeepCopyInto(&out.StorageConfiguration)
 	out.RetentionConfig = in.RetentionConfig
+	if in.RetentionConfigFrom != nil {
+		in, out := &in.RetentionConfigFrom, &out.RetentionConfigFrom
+		*out = new(string)
+		**out = **in
+	}
 	if in.BlockConfig != nil {
 		in, out := &in.BlockConfig, &out.BlockConfig
 		*out = new(BlockConfig)
@@ -1037,6 +2527,11 @@ func (in *ThanosCompactSpec) DeepCopyInto(out *ThanosCompactSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.TargetCluster != nil {
+		in, out := &in.TargetCluster, &out.TargetCluster
+		*out = new(string)
+		**out = **in
+	}
 	in.Additional.DeepCopyInto(&out.Additional)
 }
 
@@ -1084,6 +2579,124 @@ func (in *ThanosCompactStatus) DeepCopy() *ThanosCompactStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThanosDefaults) DeepCopyInto(out *ThanosDefaults) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosDefaults.
+func (in *ThanosDefaults) DeepCopy() *ThanosDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(ThanosDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ThanosDefaults) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThanosDefaultsList) DeepCopyInto(out *ThanosDefaultsList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ThanosDefaults, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosDefaultsList.
+func (in *ThanosDefaultsList) DeepCopy() *ThanosDefaultsList {
+	if in == nil {
+		return nil
+	}
+	out := new(ThanosDefaultsList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ThanosDefaultsList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThanosDefaultsSpec) DeepCopyInto(out *ThanosDefaultsSpec) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(string)
+		**out = **in
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(string)
+		**out = **in
+	}
+	if in.ResourceRequirements != nil {
+		in, out := &in.ResourceRequirements, &out.ResourceRequirements
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LogLevel != nil {
+		in, out := &in.LogLevel, &out.LogLevel
+		*out = new(string)
+		**out = **in
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(corev1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ContainerSecurityContext != nil {
+		in, out := &in.ContainerSecurityContext, &out.ContainerSecurityContext
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PriorityClassName != nil {
+		in, out := &in.PriorityClassName, &out.PriorityClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.TracingConfig != nil {
+		in, out := &in.TracingConfig, &out.TracingConfig
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosDefaultsSpec.
+func (in *ThanosDefaultsSpec) DeepCopy() *ThanosDefaultsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ThanosDefaultsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ThanosQuery) DeepCopyInto(out *ThanosQuery) {
 	*out = *in
@@ -1147,11 +2760,62 @@ func (in *ThanosQueryList) DeepCopyObject() runtime.Object {
 func (in *ThanosQuerySpec) DeepCopyInto(out *ThanosQuerySpec) {
 	*out = *in
 	in.CommonFields.DeepCopyInto(&out.CommonFields)
+	in.DeploymentFields.DeepCopyInto(&out.DeploymentFields)
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
 	if in.ReplicaLabels != nil {
 		in, out := &in.ReplicaLabels, &out.ReplicaLabels
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.DiscoverReplicaLabels != nil {
+		in, out := &in.DiscoverReplicaLabels, &out.DiscoverReplicaLabels
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(Duration)
+		**out = **in
+	}
+	if in.LookbackDelta != nil {
+		in, out := &in.LookbackDelta, &out.LookbackDelta
+		*out = new(Duration)
+		**out = **in
+	}
+	if in.MaxConcurrent != nil {
+		in, out := &in.MaxConcurrent, &out.MaxConcurrent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AutoDownsampling != nil {
+		in, out := &in.AutoDownsampling, &out.AutoDownsampling
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PartialResponse != nil {
+		in, out := &in.PartialResponse, &out.PartialResponse
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DeduplicationFunc != nil {
+		in, out := &in.DeduplicationFunc, &out.DeduplicationFunc
+		*out = new(string)
+		**out = **in
+	}
+	if in.DiscoveryMode != nil {
+		in, out := &in.DiscoveryMode, &out.DiscoveryMode
+		*out = new(QueryDiscoveryMode)
+		**out = **in
+	}
+	if in.EndpointDiscoveryScheme != nil {
+		in, out := &in.EndpointDiscoveryScheme, &out.EndpointDiscoveryScheme
+		*out = new(EndpointDiscoveryScheme)
+		**out = **in
+	}
 	if in.StoreLabelSelector != nil {
 		in, out := &in.StoreLabelSelector, &out.StoreLabelSelector
 		*out = new(v1.LabelSelector)
@@ -1162,6 +2826,16 @@ func (in *ThanosQuerySpec) DeepCopyInto(out *ThanosQuerySpec) {
 		*out = new(TelemetryQuantiles)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RequestLogging != nil {
+		in, out := &in.RequestLogging, &out.RequestLogging
+		*out = new(RequestLoggingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ActiveQueryTracking != nil {
+		in, out := &in.ActiveQueryTracking, &out.ActiveQueryTracking
+		*out = new(bool)
+		**out = **in
+	}
 	if in.WebConfig != nil {
 		in, out := &in.WebConfig, &out.WebConfig
 		*out = new(WebConfig)
@@ -1177,6 +2851,56 @@ func (in *ThanosQuerySpec) DeepCopyInto(out *ThanosQuerySpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.DeletionProtection != nil {
+		in, out := &in.DeletionProtection, &out.DeletionProtection
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TargetCluster != nil {
+		in, out := &in.TargetCluster, &out.TargetCluster
+		*out = new(string)
+		**out = **in
+	}
+	if in.ReadProbe != nil {
+		in, out := &in.ReadProbe, &out.ReadProbe
+		*out = new(ReadProbeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthChecks != nil {
+		in, out := &in.HealthChecks, &out.HealthChecks
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ArgsFile != nil {
+		in, out := &in.ArgsFile, &out.ArgsFile
+		*out = new(bool)
+		**out = **in
+	}
+	if in.GRPCServerTLS != nil {
+		in, out := &in.GRPCServerTLS, &out.GRPCServerTLS
+		*out = new(TLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GRPCClientTLS != nil {
+		in, out := &in.GRPCClientTLS, &out.GRPCClientTLS
+		*out = new(GRPCClientTLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalEndpoints != nil {
+		in, out := &in.ExternalEndpoints, &out.ExternalEndpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Federation != nil {
+		in, out := &in.Federation, &out.Federation
+		*out = new(QueryFederationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(IngressConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Additional.DeepCopyInto(&out.Additional)
 }
 
@@ -1207,6 +2931,30 @@ func (in *ThanosQueryStatus) DeepCopyInto(out *ThanosQueryStatus) {
 	}
 	out.Querier = in.Querier
 	out.QueryFrontend = in.QueryFrontend
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]QueryEndpointStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReadProbe != nil {
+		in, out := &in.ReadProbe, &out.ReadProbe
+		*out = new(ReadProbeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Remotes != nil {
+		in, out := &in.Remotes, &out.Remotes
+		*out = make([]RemoteEndpointStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EndpointHealth != nil {
+		in, out := &in.EndpointHealth, &out.EndpointHealth
+		*out = make([]EndpointHealthStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosQueryStatus.
@@ -1283,11 +3031,56 @@ func (in *ThanosReceiveSpec) DeepCopyInto(out *ThanosReceiveSpec) {
 	*out = *in
 	in.Router.DeepCopyInto(&out.Router)
 	in.Ingester.DeepCopyInto(&out.Ingester)
+	if in.VerifyObjectStorage != nil {
+		in, out := &in.VerifyObjectStorage, &out.VerifyObjectStorage
+		*out = new(bool)
+		**out = **in
+	}
 	if in.Paused != nil {
 		in, out := &in.Paused, &out.Paused
 		*out = new(bool)
 		**out = **in
 	}
+	if in.DeletionProtection != nil {
+		in, out := &in.DeletionProtection, &out.DeletionProtection
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TargetCluster != nil {
+		in, out := &in.TargetCluster, &out.TargetCluster
+		*out = new(string)
+		**out = **in
+	}
+	if in.WriteProbe != nil {
+		in, out := &in.WriteProbe, &out.WriteProbe
+		*out = new(WriteProbeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = new(ReceiveLimitsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TenantSelector != nil {
+		in, out := &in.TenantSelector, &out.TenantSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GRPCTLS != nil {
+		in, out := &in.GRPCTLS, &out.GRPCTLS
+		*out = new(ReceiveGRPCTLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(ReceiveNetworkPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Deletion != nil {
+		in, out := &in.Deletion, &out.Deletion
+		*out = new(ReceiveDeletionSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	in.StatefulSetFields.DeepCopyInto(&out.StatefulSetFields)
 }
 
@@ -1324,6 +3117,67 @@ func (in *ThanosReceiveStatus) DeepCopyInto(out *ThanosReceiveStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.RouterGroupStatus != nil {
+		in, out := &in.RouterGroupStatus, &out.RouterGroupStatus
+		*out = make(map[string]DeploymentStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.IngesterVolumes != nil {
+		in, out := &in.IngesterVolumes, &out.IngesterVolumes
+		*out = make(map[string][]IngesterVolumeStatus, len(*in))
+		for key, val := range *in {
+			var outVal []IngesterVolumeStatus
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]IngesterVolumeStatus, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.UploadLag != nil {
+		in, out := &in.UploadLag, &out.UploadLag
+		*out = make(map[string]v1.Duration, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.UploadLagCheckTime != nil {
+		in, out := &in.UploadLagCheckTime, &out.UploadLagCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.IngesterHealth != nil {
+		in, out := &in.IngesterHealth, &out.IngesterHealth
+		*out = make(map[string][]EndpointHealthStatus, len(*in))
+		for key, val := range *in {
+			var outVal []EndpointHealthStatus
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]EndpointHealthStatus, len(*in))
+				for i := range *in {
+					(*in)[i].DeepCopyInto(&(*out)[i])
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.HashringConfigUpdateTime != nil {
+		in, out := &in.HashringConfigUpdateTime, &out.HashringConfigUpdateTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(HashringRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosReceiveStatus.
@@ -1406,6 +3260,11 @@ func (in *ThanosRulerSpec) DeepCopyInto(out *ThanosRulerSpec) {
 		(*in).DeepCopyInto(*out)
 	}
 	in.ObjectStorageConfig.DeepCopyInto(&out.ObjectStorageConfig)
+	if in.VerifyObjectStorage != nil {
+		in, out := &in.VerifyObjectStorage, &out.VerifyObjectStorage
+		*out = new(bool)
+		**out = **in
+	}
 	in.RuleConfigSelector.DeepCopyInto(&out.RuleConfigSelector)
 	if in.ExternalLabels != nil {
 		in, out := &in.ExternalLabels, &out.ExternalLabels
@@ -1425,6 +3284,11 @@ func (in *ThanosRulerSpec) DeepCopyInto(out *ThanosRulerSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.TargetCluster != nil {
+		in, out := &in.TargetCluster, &out.TargetCluster
+		*out = new(string)
+		**out = **in
+	}
 	if in.RuleTenancyConfig != nil {
 		in, out := &in.RuleTenancyConfig, &out.RuleTenancyConfig
 		*out = new(RuleTenancyConfig)
@@ -1471,6 +3335,139 @@ func (in *ThanosRulerStatus) DeepCopy() *ThanosRulerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThanosStack) DeepCopyInto(out *ThanosStack) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosStack.
+func (in *ThanosStack) DeepCopy() *ThanosStack {
+	if in == nil {
+		return nil
+	}
+	out := new(ThanosStack)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ThanosStack) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThanosStackComponentStatus) DeepCopyInto(out *ThanosStackComponentStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosStackComponentStatus.
+func (in *ThanosStackComponentStatus) DeepCopy() *ThanosStackComponentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ThanosStackComponentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThanosStackList) DeepCopyInto(out *ThanosStackList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ThanosStack, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosStackList.
+func (in *ThanosStackList) DeepCopy() *ThanosStackList {
+	if in == nil {
+		return nil
+	}
+	out := new(ThanosStackList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ThanosStackList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThanosStackSpec) DeepCopyInto(out *ThanosStackSpec) {
+	*out = *in
+	in.CommonFields.DeepCopyInto(&out.CommonFields)
+	in.ObjectStorageConfig.DeepCopyInto(&out.ObjectStorageConfig)
+	in.Receive.DeepCopyInto(&out.Receive)
+	in.Query.DeepCopyInto(&out.Query)
+	in.Store.DeepCopyInto(&out.Store)
+	in.Compact.DeepCopyInto(&out.Compact)
+	in.Ruler.DeepCopyInto(&out.Ruler)
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosStackSpec.
+func (in *ThanosStackSpec) DeepCopy() *ThanosStackSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ThanosStackSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThanosStackStatus) DeepCopyInto(out *ThanosStackStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ThanosStackComponentStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosStackStatus.
+func (in *ThanosStackStatus) DeepCopy() *ThanosStackStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ThanosStackStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ThanosStore) DeepCopyInto(out *ThanosStore) {
 	*out = *in
@@ -1536,6 +3533,11 @@ func (in *ThanosStoreSpec) DeepCopyInto(out *ThanosStoreSpec) {
 	in.CommonFields.DeepCopyInto(&out.CommonFields)
 	in.StatefulSetFields.DeepCopyInto(&out.StatefulSetFields)
 	in.ObjectStorageConfig.DeepCopyInto(&out.ObjectStorageConfig)
+	if in.VerifyObjectStorage != nil {
+		in, out := &in.VerifyObjectStorage, &out.VerifyObjectStorage
+		*out = new(bool)
+		**out = **in
+	}
 	in.StorageConfiguration.DeepCopyInto(&out.StorageConfiguration)
 	if in.IndexCacheConfig != nil {
 		in, out := &in.IndexCacheConfig, &out.IndexCacheConfig
@@ -1547,7 +3549,12 @@ func (in *ThanosStoreSpec) DeepCopyInto(out *ThanosStoreSpec) {
 		*out = new(CacheConfig)
 		(*in).DeepCopyInto(*out)
 	}
-	out.ShardingStrategy = in.ShardingStrategy
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(ManagedCacheSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ShardingStrategy.DeepCopyInto(&out.ShardingStrategy)
 	if in.TimeRangeConfig != nil {
 		in, out := &in.TimeRangeConfig, &out.TimeRangeConfig
 		*out = new(TimeRangeConfig)
@@ -1573,6 +3580,11 @@ func (in *ThanosStoreSpec) DeepCopyInto(out *ThanosStoreSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.TargetCluster != nil {
+		in, out := &in.TargetCluster, &out.TargetCluster
+		*out = new(string)
+		**out = **in
+	}
 	in.Additional.DeepCopyInto(&out.Additional)
 }
 
@@ -1620,6 +3632,84 @@ func (in *ThanosStoreStatus) DeepCopy() *ThanosStoreStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThanosTenant) DeepCopyInto(out *ThanosTenant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosTenant.
+func (in *ThanosTenant) DeepCopy() *ThanosTenant {
+	if in == nil {
+		return nil
+	}
+	out := new(ThanosTenant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ThanosTenant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThanosTenantList) DeepCopyInto(out *ThanosTenantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ThanosTenant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosTenantList.
+func (in *ThanosTenantList) DeepCopy() *ThanosTenantList {
+	if in == nil {
+		return nil
+	}
+	out := new(ThanosTenantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ThanosTenantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThanosTenantSpec) DeepCopyInto(out *ThanosTenantSpec) {
+	*out = *in
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = new(WriteLimits)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosTenantSpec.
+func (in *ThanosTenantSpec) DeepCopy() *ThanosTenantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ThanosTenantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TimeRangeConfig) DeepCopyInto(out *TimeRangeConfig) {
 	*out = *in
@@ -1645,6 +3735,31 @@ func (in *TimeRangeConfig) DeepCopy() *TimeRangeConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TracingSpec) DeepCopyInto(out *TracingSpec) {
+	*out = *in
+	if in.Insecure != nil {
+		in, out := &in.Insecure, &out.Insecure
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SamplingRatio != nil {
+		in, out := &in.SamplingRatio, &out.SamplingRatio
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TracingSpec.
+func (in *TracingSpec) DeepCopy() *TracingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TracingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VerticalCompactionConfig) DeepCopyInto(out *VerticalCompactionConfig) {
 	*out = *in
@@ -1704,3 +3819,68 @@ func (in *WebConfig) DeepCopy() *WebConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WriteLimits) DeepCopyInto(out *WriteLimits) {
+	*out = *in
+	if in.SizeBytesLimit != nil {
+		in, out := &in.SizeBytesLimit, &out.SizeBytesLimit
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SeriesLimit != nil {
+		in, out := &in.SeriesLimit, &out.SeriesLimit
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SamplesLimit != nil {
+		in, out := &in.SamplesLimit, &out.SamplesLimit
+		*out = new(int64)
+		**out = **in
+	}
+	if in.HeadSeriesLimit != nil {
+		in, out := &in.HeadSeriesLimit, &out.HeadSeriesLimit
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WriteLimits.
+func (in *WriteLimits) DeepCopy() *WriteLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(WriteLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WriteProbeSpec) DeepCopyInto(out *WriteProbeSpec) {
+	*out = *in
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(string)
+		**out = **in
+	}
+	if in.DeadlineSeconds != nil {
+		in, out := &in.DeadlineSeconds, &out.DeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WriteProbeSpec.
+func (in *WriteProbeSpec) DeepCopy() *WriteProbeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WriteProbeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1beta1
+
+import (
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+)
+
+// convertCommonFieldsToHub converts the common fields to the hub version.
+func convertCommonFieldsToHub(in CommonFields) v1alpha1.CommonFields {
+	out := v1alpha1.CommonFields{
+		Version:                   in.Version,
+		Image:                     in.Image,
+		ImagePullPolicy:           in.ImagePullPolicy,
+		ImagePullSecrets:          in.ImagePullSecrets,
+		ServiceAccountAnnotations: in.ServiceAccountAnnotations,
+		ServiceAnnotations:        in.ServiceAnnotations,
+		ResourceRequirements:      in.ResourceRequirements,
+		LogLevel:                  in.LogLevel,
+		LogFormat:                 in.LogFormat,
+		NodeSelector:              in.NodeSelector,
+		Affinity:                  in.Affinity,
+		Tolerations:               in.Tolerations,
+		TopologySpreadConstraints: in.TopologySpreadConstraints,
+		SecurityContext:           in.SecurityContext,
+		ContainerSecurityContext:  in.ContainerSecurityContext,
+		PriorityClassName:         in.PriorityClassName,
+		TracingConfig:             in.TracingConfig,
+		Tracing:                   in.Tracing,
+		PodDisruptionBudgetConfig: in.PodDisruptionBudgetConfig,
+		MetricsService:            in.MetricsService,
+		Probes:                    in.Probes,
+		Labels:                    in.Labels,
+		Annotations:               in.Annotations,
+	}
+	return out
+}
+
+// convertCommonFieldsFromHub converts the common fields from the hub version.
+func convertCommonFieldsFromHub(in v1alpha1.CommonFields) CommonFields {
+	out := CommonFields{
+		Version:                   in.Version,
+		Image:                     in.Image,
+		ImagePullPolicy:           in.ImagePullPolicy,
+		ImagePullSecrets:          in.ImagePullSecrets,
+		ServiceAccountAnnotations: in.ServiceAccountAnnotations,
+		ServiceAnnotations:        in.ServiceAnnotations,
+		ResourceRequirements:      in.ResourceRequirements,
+		LogLevel:                  in.LogLevel,
+		LogFormat:                 in.LogFormat,
+		NodeSelector:              in.NodeSelector,
+		Affinity:                  in.Affinity,
+		Tolerations:               in.Tolerations,
+		TopologySpreadConstraints: in.TopologySpreadConstraints,
+		SecurityContext:           in.SecurityContext,
+		ContainerSecurityContext:  in.ContainerSecurityContext,
+		PriorityClassName:         in.PriorityClassName,
+		TracingConfig:             in.TracingConfig,
+		Tracing:                   in.Tracing,
+		PodDisruptionBudgetConfig: in.PodDisruptionBudgetConfig,
+		MetricsService:            in.MetricsService,
+		Probes:                    in.Probes,
+		Labels:                    in.Labels,
+		Annotations:               in.Annotations,
+	}
+	return out
+}
+
+// convertDeploymentFieldsToHub converts the Deployment fields to the hub version.
+func convertDeploymentFieldsToHub(in DeploymentFields) v1alpha1.DeploymentFields {
+	out := v1alpha1.DeploymentFields{
+		TerminationGracePeriodSeconds: in.TerminationGracePeriodSeconds,
+		Strategy:                      in.Strategy,
+	}
+	return out
+}
+
+// convertDeploymentFieldsFromHub converts the Deployment fields from the hub version.
+func convertDeploymentFieldsFromHub(in v1alpha1.DeploymentFields) DeploymentFields {
+	out := DeploymentFields{
+		TerminationGracePeriodSeconds: in.TerminationGracePeriodSeconds,
+		Strategy:                      in.Strategy,
+	}
+	return out
+}
+
+// convertStatefulSetFieldsToHub converts the StatefulSet fields to the hub version.
+func convertStatefulSetFieldsToHub(in StatefulSetFields) v1alpha1.StatefulSetFields {
+	out := v1alpha1.StatefulSetFields{
+		PodManagementPolicy:                  in.PodManagementPolicy,
+		PersistentVolumeClaimRetentionPolicy: in.PersistentVolumeClaimRetentionPolicy,
+		TerminationGracePeriodSeconds:        in.TerminationGracePeriodSeconds,
+		MinReadySeconds:                      in.MinReadySeconds,
+		UpdateStrategy:                       in.UpdateStrategy,
+	}
+	return out
+}
+
+// convertStatefulSetFieldsFromHub converts the StatefulSet fields from the hub version.
+func convertStatefulSetFieldsFromHub(in v1alpha1.StatefulSetFields) StatefulSetFields {
+	out := StatefulSetFields{
+		PodManagementPolicy:                  in.PodManagementPolicy,
+		PersistentVolumeClaimRetentionPolicy: in.PersistentVolumeClaimRetentionPolicy,
+		TerminationGracePeriodSeconds:        in.TerminationGracePeriodSeconds,
+		MinReadySeconds:                      in.MinReadySeconds,
+		UpdateStrategy:                       in.UpdateStrategy,
+	}
+	return out
+}
+
+// convertAdditionalToHub converts the additional configuration to the hub version.
+func convertAdditionalToHub(in Additional) v1alpha1.Additional {
+	out := v1alpha1.Additional{
+		Args:         in.Args,
+		Containers:   in.Containers,
+		Volumes:      in.Volumes,
+		VolumeMounts: in.VolumeMounts,
+		Ports:        in.Ports,
+		Env:          in.Env,
+		EnvFrom:      in.EnvFrom,
+		ServicePorts: in.ServicePorts,
+		ConfigMaps:   in.ConfigMaps,
+		Secrets:      in.Secrets,
+	}
+	return out
+}
+
+// convertAdditionalFromHub converts the additional configuration from the hub version.
+func convertAdditionalFromHub(in v1alpha1.Additional) Additional {
+	out := Additional{
+		Args:         in.Args,
+		Containers:   in.Containers,
+		Volumes:      in.Volumes,
+		VolumeMounts: in.VolumeMounts,
+		Ports:        in.Ports,
+		Env:          in.Env,
+		EnvFrom:      in.EnvFrom,
+		ServicePorts: in.ServicePorts,
+		ConfigMaps:   in.ConfigMaps,
+		Secrets:      in.Secrets,
+	}
+	return out
+}
@@ -0,0 +1,79 @@
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+	webhookconversion "sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+	"sigs.k8s.io/randfill"
+)
+
+func TestThanosReceiveRoundTrip(t *testing.T) {
+	testRoundTrip(t, func() *ThanosReceive { return &ThanosReceive{} }, func() *v1alpha1.ThanosReceive { return &v1alpha1.ThanosReceive{} })
+}
+
+func TestThanosQueryRoundTrip(t *testing.T) {
+	testRoundTrip(t, func() *ThanosQuery { return &ThanosQuery{} }, func() *v1alpha1.ThanosQuery { return &v1alpha1.ThanosQuery{} })
+}
+
+func TestIsConvertible(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	for _, obj := range []runtime.Object{&v1alpha1.ThanosReceive{}, &v1alpha1.ThanosQuery{}} {
+		ok, err := webhookconversion.IsConvertible(scheme, obj)
+		if err != nil || !ok {
+			t.Errorf("expected %T to be convertible, got %v", obj, err)
+		}
+	}
+}
+
+// testRoundTrip converts randomly filled objects to the other version and back, and expects them unchanged.
+// Every optional field is set, so that a field left out of the converters is detected.
+func testRoundTrip[S conversion.Convertible, H conversion.Hub](t *testing.T, newSpoke func() S, newHub func() H) {
+	t.Helper()
+	for seed := range int64(20) {
+		f := randfill.NewWithSeed(seed).NilChance(0).NumElements(1, 2)
+
+		spoke := newSpoke()
+		f.Fill(spoke)
+		// the TypeMeta is set by the conversion webhook, not by the converters
+		spoke.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{})
+		hub := newHub()
+		if err := spoke.ConvertTo(hub); err != nil {
+			t.Fatalf("seed %d: failed to convert to the hub: %v", seed, err)
+		}
+		gotSpoke := newSpoke()
+		if err := gotSpoke.ConvertFrom(hub); err != nil {
+			t.Fatalf("seed %d: failed to convert from the hub: %v", seed, err)
+		}
+		if !apiequality.Semantic.DeepEqual(spoke, gotSpoke) {
+			t.Errorf("seed %d: %T changed after a round-trip through the hub", seed, spoke)
+		}
+
+		hub = newHub()
+		f.Fill(hub)
+		hub.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{})
+		spoke = newSpoke()
+		if err := spoke.ConvertFrom(hub); err != nil {
+			t.Fatalf("seed %d: failed to convert from the hub: %v", seed, err)
+		}
+		gotHub := newHub()
+		if err := spoke.ConvertTo(gotHub); err != nil {
+			t.Fatalf("seed %d: failed to convert to the hub: %v", seed, err)
+		}
+		if !apiequality.Semantic.DeepEqual(hub, gotHub) {
+			t.Errorf("seed %d: %T changed after a round-trip through the spoke", seed, hub)
+		}
+	}
+}
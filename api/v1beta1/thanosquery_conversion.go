@@ -0,0 +1,208 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1beta1
+
+import (
+	"fmt"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// ConvertTo converts this ThanosQuery to the hub version.
+func (src *ThanosQuery) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha1.ThanosQuery)
+	if !ok {
+		return fmt.Errorf("unexpected hub type %T", dstRaw)
+	}
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.ThanosQuerySpec{
+		CommonFields:            convertCommonFieldsToHub(src.Spec.CommonFields),
+		DeploymentFields:        convertDeploymentFieldsToHub(src.Spec.DeploymentFields),
+		Replicas:                src.Spec.Replicas,
+		ReplicaLabels:           src.Spec.ReplicaLabels,
+		DiscoverReplicaLabels:   src.Spec.DiscoverReplicaLabels,
+		Timeout:                 src.Spec.Timeout,
+		LookbackDelta:           src.Spec.LookbackDelta,
+		MaxConcurrent:           src.Spec.MaxConcurrent,
+		AutoDownsampling:        src.Spec.AutoDownsampling,
+		PartialResponse:         src.Spec.PartialResponse,
+		DeduplicationFunc:       src.Spec.DeduplicationFunc,
+		DiscoveryMode:           src.Spec.DiscoveryMode,
+		EndpointDiscoveryScheme: src.Spec.EndpointDiscoveryScheme,
+		StoreLabelSelector:      src.Spec.StoreLabelSelector,
+		TelemetryQuantiles:      src.Spec.TelemetryQuantiles,
+		RequestLogging:          src.Spec.RequestLogging,
+		ActiveQueryTracking:     src.Spec.ActiveQueryTracking,
+		WebConfig:               src.Spec.WebConfig,
+		GRPCProxyStrategy:       src.Spec.GRPCProxyStrategy,
+		Paused:                  src.Spec.Paused,
+		DeletionProtection:      src.Spec.DeletionProtection,
+		TargetCluster:           src.Spec.TargetCluster,
+		ReadProbe:               src.Spec.ReadProbe,
+		HealthChecks:            src.Spec.HealthChecks,
+		ArgsFile:                src.Spec.ArgsFile,
+		GRPCServerTLS:           src.Spec.GRPCServerTLS,
+		GRPCClientTLS:           src.Spec.GRPCClientTLS,
+		ExternalEndpoints:       src.Spec.ExternalEndpoints,
+		Federation:              src.Spec.Federation,
+		Ingress:                 src.Spec.Ingress,
+		Additional:              convertAdditionalToHub(src.Spec.Additional),
+	}
+	if src.Spec.QueryFrontend != nil {
+		queryFrontend := convertQueryFrontendSpecToHub(*src.Spec.QueryFrontend)
+		dst.Spec.QueryFrontend = &queryFrontend
+	}
+	dst.Status = convertThanosQueryStatusToHub(src.Status)
+	return nil
+}
+
+// ConvertFrom converts the hub version to this ThanosQuery.
+func (dst *ThanosQuery) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1alpha1.ThanosQuery)
+	if !ok {
+		return fmt.Errorf("unexpected hub type %T", srcRaw)
+	}
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = ThanosQuerySpec{
+		CommonFields:            convertCommonFieldsFromHub(src.Spec.CommonFields),
+		DeploymentFields:        convertDeploymentFieldsFromHub(src.Spec.DeploymentFields),
+		Replicas:                src.Spec.Replicas,
+		ReplicaLabels:           src.Spec.ReplicaLabels,
+		DiscoverReplicaLabels:   src.Spec.DiscoverReplicaLabels,
+		Timeout:                 src.Spec.Timeout,
+		LookbackDelta:           src.Spec.LookbackDelta,
+		MaxConcurrent:           src.Spec.MaxConcurrent,
+		AutoDownsampling:        src.Spec.AutoDownsampling,
+		PartialResponse:         src.Spec.PartialResponse,
+		DeduplicationFunc:       src.Spec.DeduplicationFunc,
+		DiscoveryMode:           src.Spec.DiscoveryMode,
+		EndpointDiscoveryScheme: src.Spec.EndpointDiscoveryScheme,
+		StoreLabelSelector:      src.Spec.StoreLabelSelector,
+		TelemetryQuantiles:      src.Spec.TelemetryQuantiles,
+		RequestLogging:          src.Spec.RequestLogging,
+		ActiveQueryTracking:     src.Spec.ActiveQueryTracking,
+		WebConfig:               src.Spec.WebConfig,
+		GRPCProxyStrategy:       src.Spec.GRPCProxyStrategy,
+		Paused:                  src.Spec.Paused,
+		DeletionProtection:      src.Spec.DeletionProtection,
+		TargetCluster:           src.Spec.TargetCluster,
+		ReadProbe:               src.Spec.ReadProbe,
+		HealthChecks:            src.Spec.HealthChecks,
+		ArgsFile:                src.Spec.ArgsFile,
+		GRPCServerTLS:           src.Spec.GRPCServerTLS,
+		GRPCClientTLS:           src.Spec.GRPCClientTLS,
+		ExternalEndpoints:       src.Spec.ExternalEndpoints,
+		Federation:              src.Spec.Federation,
+		Ingress:                 src.Spec.Ingress,
+		Additional:              convertAdditionalFromHub(src.Spec.Additional),
+	}
+	if src.Spec.QueryFrontend != nil {
+		queryFrontend := convertQueryFrontendSpecFromHub(*src.Spec.QueryFrontend)
+		dst.Spec.QueryFrontend = &queryFrontend
+	}
+	dst.Status = convertThanosQueryStatusFromHub(src.Status)
+	return nil
+}
+
+// convertQueryFrontendSpecToHub converts the Query Frontend spec to the hub version.
+func convertQueryFrontendSpecToHub(in QueryFrontendSpec) v1alpha1.QueryFrontendSpec {
+	out := v1alpha1.QueryFrontendSpec{
+		Replicas:                      in.Replicas,
+		CompressResponses:             in.CompressResponses,
+		QueryLabelSelector:            in.QueryLabelSelector,
+		LogQueriesLongerThan:          in.LogQueriesLongerThan,
+		QueryRangeResponseCacheConfig: in.QueryRangeResponseCacheConfig,
+		Cache:                         in.Cache,
+		QueryRangeSplitInterval:       in.QueryRangeSplitInterval,
+		LabelsSplitInterval:           in.LabelsSplitInterval,
+		QueryRangeMaxRetries:          in.QueryRangeMaxRetries,
+		LabelsMaxRetries:              in.LabelsMaxRetries,
+		LabelsDefaultTimeRange:        in.LabelsDefaultTimeRange,
+		QueryRangeMaxQueryParallelism: in.QueryRangeMaxQueryParallelism,
+		LabelsMaxQueryParallelism:     in.LabelsMaxQueryParallelism,
+		QueryRangeMaxQueryLength:      in.QueryRangeMaxQueryLength,
+		DownstreamConfig:              in.DownstreamConfig,
+		SessionAffinity:               in.SessionAffinity,
+		Service:                       in.Service,
+		CommonFields:                  convertCommonFieldsToHub(in.CommonFields),
+		DeploymentFields:              convertDeploymentFieldsToHub(in.DeploymentFields),
+		Additional:                    convertAdditionalToHub(in.Additional),
+	}
+	return out
+}
+
+// convertQueryFrontendSpecFromHub converts the Query Frontend spec from the hub version.
+func convertQueryFrontendSpecFromHub(in v1alpha1.QueryFrontendSpec) QueryFrontendSpec {
+	out := QueryFrontendSpec{
+		Replicas:                      in.Replicas,
+		CompressResponses:             in.CompressResponses,
+		QueryLabelSelector:            in.QueryLabelSelector,
+		LogQueriesLongerThan:          in.LogQueriesLongerThan,
+		QueryRangeResponseCacheConfig: in.QueryRangeResponseCacheConfig,
+		Cache:                         in.Cache,
+		QueryRangeSplitInterval:       in.QueryRangeSplitInterval,
+		LabelsSplitInterval:           in.LabelsSplitInterval,
+		QueryRangeMaxRetries:          in.QueryRangeMaxRetries,
+		LabelsMaxRetries:              in.LabelsMaxRetries,
+		LabelsDefaultTimeRange:        in.LabelsDefaultTimeRange,
+		QueryRangeMaxQueryParallelism: in.QueryRangeMaxQueryParallelism,
+		LabelsMaxQueryParallelism:     in.LabelsMaxQueryParallelism,
+		QueryRangeMaxQueryLength:      in.QueryRangeMaxQueryLength,
+		DownstreamConfig:              in.DownstreamConfig,
+		SessionAffinity:               in.SessionAffinity,
+		Service:                       in.Service,
+		CommonFields:                  convertCommonFieldsFromHub(in.CommonFields),
+		DeploymentFields:              convertDeploymentFieldsFromHub(in.DeploymentFields),
+		Additional:                    convertAdditionalFromHub(in.Additional),
+	}
+	return out
+}
+
+// convertThanosQueryStatusToHub converts the ThanosQuery status to the hub version.
+func convertThanosQueryStatusToHub(in ThanosQueryStatus) v1alpha1.ThanosQueryStatus {
+	out := v1alpha1.ThanosQueryStatus{
+		Conditions:         in.Conditions,
+		Paused:             in.Paused,
+		Querier:            in.Querier,
+		QueryFrontend:      in.QueryFrontend,
+		EndpointCount:      in.EndpointCount,
+		Endpoints:          in.Endpoints,
+		ObservedGeneration: in.ObservedGeneration,
+		ReadProbe:          in.ReadProbe,
+		Remotes:            in.Remotes,
+		EndpointHealth:     in.EndpointHealth,
+	}
+	return out
+}
+
+// convertThanosQueryStatusFromHub converts the ThanosQuery status from the hub version.
+func convertThanosQueryStatusFromHub(in v1alpha1.ThanosQueryStatus) ThanosQueryStatus {
+	out := ThanosQueryStatus{
+		Conditions:         in.Conditions,
+		Paused:             in.Paused,
+		Querier:            in.Querier,
+		QueryFrontend:      in.QueryFrontend,
+		EndpointCount:      in.EndpointCount,
+		Endpoints:          in.Endpoints,
+		ObservedGeneration: in.ObservedGeneration,
+		ReadProbe:          in.ReadProbe,
+		Remotes:            in.Remotes,
+		EndpointHealth:     in.EndpointHealth,
+	}
+	return out
+}
@@ -0,0 +1,329 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1beta1
+
+import (
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ThanosQuerySpec defines the desired state of ThanosQuery
+// +kubebuilder:validation:XValidation:rule="!has(self.readProbe) || !has(self.targetCluster)", message="readProbe is not supported when targetCluster is set"
+// +kubebuilder:validation:XValidation:rule="!has(self.federation) || !has(self.grpcClientTLS) || self.federation.remotes.all(r, !has(r.tlsSecret))", message="the tlsSecret of federation remotes cannot be set together with grpcClientTLS"
+type ThanosQuerySpec struct {
+	CommonFields `json:",inline"`
+	// DeploymentFields are the options available to all Thanos components managed as Deployments.
+	DeploymentFields `json:",inline"`
+	// Replicas is the number of querier replicas. It is exposed through the scale subresource,
+	// so that a HorizontalPodAutoscaler can target the ThanosQuery.
+	// If unset, the replicas of the Querier Deployment are managed externally, for example by a HorizontalPodAutoscaler
+	// targeting the Deployment, and the operator leaves them unchanged.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	Replicas *int32 `json:"replicas,omitempty"`
+	// ReplicaLabels are labels to treat as a replica indicator along which data is deduplicated.
+	// Data can still be queried without deduplication using 'dedup=false' parameter.
+	// Data includes time series, recording rules, and alerting rules.
+	// Refer to https://thanos.io/tip/components/query.md/#deduplication-replica-labels
+	// +kubebuilder:default:={"replica"}
+	// +kubebuilder:validation:Optional
+	ReplicaLabels []string `json:"replicaLabels,omitempty"`
+	// DiscoverReplicaLabels adds the replica labels of the ThanosReceive resources whose ingesters are discovered
+	// as StoreAPI endpoints to ReplicaLabels, so that the series replicated by the ingesters are deduplicated.
+	// The replica labels of a ThanosReceive are the external labels of its hashrings whose values are derived from the pod name.
+	// Set to false to only use ReplicaLabels.
+	// +kubebuilder:default=true
+	// +kubebuilder:validation:Optional
+	DiscoverReplicaLabels *bool `json:"discoverReplicaLabels,omitempty"`
+	// Timeout is the maximum time to process a query by the Querier.
+	// +kubebuilder:default="15m"
+	// +kubebuilder:validation:Optional
+	Timeout *v1alpha1.Duration `json:"timeout,omitempty"`
+	// LookbackDelta is the maximum lookback duration for retrieving metrics during expression evaluations.
+	// +kubebuilder:default="5m"
+	// +kubebuilder:validation:Optional
+	LookbackDelta *v1alpha1.Duration `json:"lookbackDelta,omitempty"`
+	// MaxConcurrent is the maximum number of queries processed concurrently by each Querier.
+	// +kubebuilder:default=20
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	MaxConcurrent *int32 `json:"maxConcurrent,omitempty"`
+	// AutoDownsampling queries downsampled data when no max_source_resolution parameter is set,
+	// picking the resolution from the step of the query.
+	// +kubebuilder:default=true
+	// +kubebuilder:validation:Optional
+	AutoDownsampling *bool `json:"autoDownsampling,omitempty"`
+	// PartialResponse returns the results of the StoreAPI endpoints that answered when others fail,
+	// for queries without a partial_response parameter. If unset, the Thanos default is used.
+	// +kubebuilder:validation:Optional
+	PartialResponse *bool `json:"partialResponse,omitempty"`
+	// DeduplicationFunc is the function used to deduplicate the series of replicas along ReplicaLabels.
+	// penalty is suited to series scraped by Prometheus HA pairs, and chain to series ingested by
+	// replicated receivers. If unset, the Thanos default of penalty is used.
+	// Refer to https://thanos.io/tip/components/query.md/#deduplication-functions
+	// +kubebuilder:validation:Enum=penalty;chain
+	// +kubebuilder:validation:Optional
+	DeduplicationFunc *string `json:"deduplicationFunc,omitempty"`
+	// DiscoveryMode selects how the discovered StoreAPI Services are wired to the Querier.
+	// Label wires each Service according to its operator.thanos.io/endpoint* label, resolving the replicas behind
+	// Services without a group label with DNS SRV and querying all of them.
+	// EndpointGroup wires every Service as an endpoint group, resolved through gRPC DNS service discovery and
+	// load balanced round robin, so that each query reaches a single replica behind the Service. Strict Services stay strict.
+	// The ingesters of a ThanosReceive hold different series on each replica, so their Services are wired according
+	// to their label in both modes.
+	// +kubebuilder:validation:Enum=Label;EndpointGroup
+	// +kubebuilder:default=Label
+	// +kubebuilder:validation:Optional
+	DiscoveryMode *v1alpha1.QueryDiscoveryMode `json:"discoveryMode,omitempty"`
+	// EndpointDiscoveryScheme selects how the Querier resolves the addresses of the discovered StoreAPI Services.
+	// dns resolves the A and AAAA records of the Services, reaching every replica behind headless Services.
+	// dnssrv resolves the SRV records of the gRPC port of the Services, which must be named.
+	// plain dials the Service addresses as they are, leaving the resolution to gRPC.
+	// Endpoint groups are always resolved by gRPC, only plain changes their addresses.
+	// +kubebuilder:validation:Enum=dns;dnssrv;plain
+	// +kubebuilder:default=dns
+	// +kubebuilder:validation:Optional
+	EndpointDiscoveryScheme *v1alpha1.EndpointDiscoveryScheme `json:"endpointDiscoveryScheme,omitempty"`
+	// StoreLabelSelector enables adding additional labels to build a custom label selector
+	// for discoverable StoreAPIs. Values provided here will be appended to the default which are
+	// {"operator.thanos.io/store-api": "true", "app.kubernetes.io/part-of": "thanos"}.
+	// +kubebuilder:validation:Optional
+	StoreLabelSelector *metav1.LabelSelector `json:"customStoreLabelSelector,omitempty"`
+	// TelemetryQuantiles is the configuration for the request telemetry quantiles.
+	// +kubebuilder:validation:Optional
+	TelemetryQuantiles *v1alpha1.TelemetryQuantiles `json:"telemetryQuantiles,omitempty"`
+	// RequestLogging configures the logging of the HTTP and gRPC requests served by the Querier.
+	// If unset, requests are not logged.
+	// +kubebuilder:validation:Optional
+	RequestLogging *v1alpha1.RequestLoggingConfig `json:"requestLogging,omitempty"`
+	// ActiveQueryTracking records the queries in progress in a file on an emptyDir volume,
+	// so that the queries that were running when a Querier crashed, for example because it ran out of memory,
+	// are logged when it restarts.
+	// +kubebuilder:validation:Optional
+	ActiveQueryTracking *bool `json:"activeQueryTracking,omitempty"`
+	// WebConfig is the configuration for the Query UI and API web options.
+	// +kubebuilder:validation:Optional
+	WebConfig *v1alpha1.WebConfig `json:"webConfig,omitempty"`
+	// GRPCProxyStrategy is the strategy to use when proxying Series requests to leaf nodes.
+	// +kubebuilder:validation:Enum=eager;lazy
+	// +kubebuilder:default=eager
+	GRPCProxyStrategy string `json:"grpcProxyStrategy,omitempty"`
+	// QueryFrontend is the configuration for the Query Frontend
+	// If you specify this, the operator will create a Query Frontend in front of your query deployment.
+	// +kubebuilder:validation:Optional
+	QueryFrontend *QueryFrontendSpec `json:"queryFrontend,omitempty"`
+	// When a resource is paused, no actions except for deletion
+	// will be performed on the underlying objects.
+	// +kubebuilder:validation:Optional
+	Paused *bool `json:"paused,omitempty"`
+	// DeletionProtection rejects the deletion of the ThanosQuery by the validating webhook of the operator,
+	// guarding against accidental deletions. It must be unset or set to false before the ThanosQuery can be deleted.
+	// The operator.thanos.io/deletion-protection annotation set to "true" protects the ThanosQuery too.
+	// +kubebuilder:validation:Optional
+	DeletionProtection *bool `json:"deletionProtection,omitempty"`
+	// TargetCluster is the name of the workload cluster in which the child resources are created.
+	// The cluster must be registered with the operator using the --target-cluster flag.
+	// If unset, the child resources are created in the cluster of this resource.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="targetCluster is immutable"
+	TargetCluster *string `json:"targetCluster,omitempty"`
+	// ReadProbe runs a synthetic probe that periodically issues an instant and a range query for a canary series
+	// through the Query Frontend, or the Querier if no Query Frontend is deployed, and checks their latency.
+	// The probe is run by the operator, which must be able to reach the Services of the ThanosQuery.
+	// The result of the latest probe is recorded in the status and in the ReadProbeSucceeded condition.
+	// +kubebuilder:validation:Optional
+	ReadProbe *v1alpha1.ReadProbeSpec `json:"readProbe,omitempty"`
+	// HealthChecks enables health checks of the discovered StoreAPI endpoints by the operator, using the standard
+	// gRPC health service served by Thanos components. This catches endpoints that are reachable but not serving,
+	// which the readiness of the pods of the Querier does not reflect.
+	// Endpoints requiring client certificates are presented the client certificate of the Querier.
+	// The health of each endpoint is recorded in the status and in the EndpointsHealthy condition.
+	// Endpoints are not checked when the ThanosQuery is deployed to a target cluster.
+	// +kubebuilder:validation:Optional
+	HealthChecks *bool `json:"healthChecks,omitempty"`
+	// ArgsFile passes the flags of the Querier in an args file mounted from a ConfigMap instead of inline.
+	// This keeps the Deployment small and its diffs readable when there are many endpoints.
+	// Flags referencing environment variables are kept inline, as these are only expanded by Kubernetes.
+	// +kubebuilder:validation:Optional
+	ArgsFile *bool `json:"argsFile,omitempty"`
+	// GRPCServerTLS configures TLS for the gRPC server of the Querier, which serves the StoreAPI to other queriers.
+	// The Querier Service is labeled with operator.thanos.io/grpc-tls, so that other queriers connect to it over TLS.
+	// +kubebuilder:validation:Optional
+	GRPCServerTLS *v1alpha1.TLSConfig `json:"grpcServerTLS,omitempty"`
+	// GRPCClientTLS configures TLS for the connections of the Querier to its StoreAPI endpoints.
+	// The Querier also connects over TLS when one of its endpoints advertises TLS with the operator.thanos.io/grpc-tls
+	// label, verifying server certificates against the system roots if this is not set.
+	// Thanos uses the same TLS configuration for every endpoint of a Querier, so endpoints that do not serve TLS
+	// are unreachable once TLS is used.
+	// +kubebuilder:validation:Optional
+	GRPCClientTLS *v1alpha1.GRPCClientTLSConfig `json:"grpcClientTLS,omitempty"`
+	// ExternalEndpoints are StoreAPI endpoints outside of the cluster, such as Thanos sidecars of Prometheus servers
+	// running on virtual machines, as host:port.
+	// They are written to a file SD file mounted from a ConfigMap, which the Querier reloads when it changes,
+	// so that endpoints can be added and removed without rolling out the Querier.
+	// +kubebuilder:validation:items:Pattern=`^[^\s/:]+:[0-9]+$`
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	ExternalEndpoints []string `json:"externalEndpoints,omitempty"`
+	// Federation declares the StoreAPI endpoints of remote clusters, typically their Queriers, so that a global query
+	// layer federating several clusters is managed by a single ThanosQuery.
+	// The reachability of the remote endpoints from the operator is recorded in the status.
+	// +kubebuilder:validation:Optional
+	Federation *v1alpha1.QueryFederationSpec `json:"federation,omitempty"`
+	// Ingress exposes the query UI and API outside of the cluster through an Ingress or a Gateway API HTTPRoute
+	// routing the given hosts to the Query Frontend Service, or to the Querier Service if there is no Query Frontend.
+	// +kubebuilder:validation:Optional
+	Ingress *v1alpha1.IngressConfig `json:"ingress,omitempty"`
+	// Additional configuration for the Thanos components. Allows you to add
+	// additional args, containers, volumes, and volume mounts to Thanos Deployments,
+	// and StatefulSets. Ideal to use for things like sidecars.
+	// +kubebuilder:validation:Optional
+	Additional `json:",inline"`
+}
+
+// QueryFrontendSpec defines the desired state of ThanosQueryFrontend
+type QueryFrontendSpec struct {
+	CommonFields `json:",inline"`
+	// DeploymentFields are the options available to all Thanos components managed as Deployments.
+	DeploymentFields `json:",inline"`
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	Replicas int32 `json:"replicas,omitempty"`
+	// CompressResponses enables response compression
+	// +kubebuilder:default=true
+	CompressResponses bool `json:"compressResponses,omitempty"`
+	// By default, the operator will add the first discoverable Query API to the
+	// Query Frontend, if they have query labels. You can optionally choose to override default
+	// Query selector labels, to select a subset of QueryAPIs to query.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:={matchLabels:{"operator.thanos.io/query-api": "true"}}
+	QueryLabelSelector *metav1.LabelSelector `json:"queryLabelSelector,omitempty"`
+	// LogQueriesLongerThan sets the duration threshold for logging long queries
+	// +kubebuilder:validation:Optional
+	LogQueriesLongerThan *v1alpha1.Duration `json:"logQueriesLongerThan,omitempty"`
+	// QueryRangeResponseCacheConfig holds the configuration for the query range response cache
+	// +kubebuilder:validation:Optional
+	QueryRangeResponseCacheConfig *v1alpha1.CacheConfig `json:"queryRangeResponseCacheConfig,omitempty"`
+	// Cache deploys a memcached managed by the operator for the response cache.
+	// +kubebuilder:validation:Optional
+	Cache *v1alpha1.ManagedCacheSpec `json:"cache,omitempty"`
+	// QueryRangeSplitInterval sets the split interval for query range
+	// +kubebuilder:validation:Optional
+	QueryRangeSplitInterval *v1alpha1.Duration `json:"queryRangeSplitInterval,omitempty"`
+	// LabelsSplitInterval sets the split interval for labels
+	// +kubebuilder:validation:Optional
+	LabelsSplitInterval *v1alpha1.Duration `json:"labelsSplitInterval,omitempty"`
+	// QueryRangeMaxRetries sets the maximum number of retries for query range requests
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=5
+	QueryRangeMaxRetries int `json:"queryRangeMaxRetries,omitempty"`
+	// LabelsMaxRetries sets the maximum number of retries for label requests
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=5
+	LabelsMaxRetries int `json:"labelsMaxRetries,omitempty"`
+	// LabelsDefaultTimeRange sets the default time range for label queries
+	// +kubebuilder:validation:Optional
+	LabelsDefaultTimeRange *v1alpha1.Duration `json:"labelsDefaultTimeRange,omitempty"`
+	// QueryRangeMaxQueryParallelism sets the maximum number of split query range requests
+	// that are scheduled in parallel against the Queriers for a single incoming request.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	QueryRangeMaxQueryParallelism *int32 `json:"queryRangeMaxQueryParallelism,omitempty"`
+	// LabelsMaxQueryParallelism sets the maximum number of split label requests
+	// that are scheduled in parallel against the Queriers for a single incoming request.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	LabelsMaxQueryParallelism *int32 `json:"labelsMaxQueryParallelism,omitempty"`
+	// QueryRangeMaxQueryLength limits the time range of range queries. Longer queries are rejected.
+	// If unset, the time range of range queries is not limited.
+	// +kubebuilder:validation:Optional
+	QueryRangeMaxQueryLength *v1alpha1.Duration `json:"queryRangeMaxQueryLength,omitempty"`
+	// DownstreamConfig configures the connections from the Query Frontend to the Queriers.
+	// +kubebuilder:validation:Optional
+	DownstreamConfig *v1alpha1.QueryFrontendDownstreamConfig `json:"downstreamConfig,omitempty"`
+	// SessionAffinity routes the requests of a client to the same Query Frontend replica,
+	// so that long running UI sessions are not spread across replicas as they scale or roll.
+	// Requests are spread across replicas if not set.
+	// +kubebuilder:validation:Optional
+	SessionAffinity *v1alpha1.SessionAffinityConfig `json:"sessionAffinity,omitempty"`
+	// Service configures how the Query Frontend Service is exposed, for example through a cloud load balancer.
+	// +kubebuilder:validation:Optional
+	Service *v1alpha1.ServiceConfig `json:"service,omitempty"`
+	// Additional configuration for the Thanos components
+	Additional `json:",inline"`
+}
+
+// ThanosQueryStatus defines the observed state of ThanosQuery
+// Includes reconciliation state, deployment status, pod status, and last reconciled statistics.
+type ThanosQueryStatus struct {
+	// Conditions represent the latest available observations of the state of the Querier.
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+	// Paused is the flag to pause the Querier.
+	// +kubebuilder:validation:Optional
+	Paused *bool `json:"paused,omitempty"`
+	// Querier is the status of the Querier.
+	Querier v1alpha1.DeploymentStatus `json:"querierStatus,omitempty"`
+	// QueryFrontend is the status of the Query Frontend.
+	QueryFrontend v1alpha1.DeploymentStatus `json:"queryFrontendStatus,omitempty"`
+	// EndpointCount is the number of StoreAPI endpoints discovered for the Querier.
+	// +kubebuilder:validation:Optional
+	EndpointCount int32 `json:"endpointCount,omitempty"`
+	// Endpoints are the StoreAPI endpoints discovered for the Querier.
+	// +kubebuilder:validation:Optional
+	Endpoints []v1alpha1.QueryEndpointStatus `json:"endpoints,omitempty"`
+	// ObservedGeneration is the most recent generation of the ThanosQuery observed by the operator.
+	// +kubebuilder:validation:Optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// ReadProbe is the result of the latest read probe.
+	// +kubebuilder:validation:Optional
+	ReadProbe *v1alpha1.ReadProbeStatus `json:"readProbe,omitempty"`
+	// Remotes is the reachability of the remote endpoints declared in the federation.
+	// +kubebuilder:validation:Optional
+	Remotes []v1alpha1.RemoteEndpointStatus `json:"remotes,omitempty"`
+	// EndpointHealth is the health of the discovered StoreAPI endpoints at the last health check.
+	// +kubebuilder:validation:Optional
+	EndpointHealth []v1alpha1.EndpointHealthStatus `json:"endpointHealth,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.querierStatus.replicas,selectorpath=.status.querierStatus.selector
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ThanosQuery is the Schema for the thanosqueries API
+type ThanosQuery struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ThanosQuerySpec   `json:"spec,omitempty"`
+	Status ThanosQueryStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ThanosQueryList contains a list of ThanosQuery
+type ThanosQueryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ThanosQuery `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ThanosQuery{}, &ThanosQueryList{})
+}
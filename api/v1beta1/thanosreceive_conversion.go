@@ -0,0 +1,284 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1beta1
+
+import (
+	"fmt"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// ConvertTo converts this ThanosReceive to the hub version.
+func (src *ThanosReceive) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha1.ThanosReceive)
+	if !ok {
+		return fmt.Errorf("unexpected hub type %T", dstRaw)
+	}
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.ThanosReceiveSpec{
+		Router:              convertRouterSpecToHub(src.Spec.Router),
+		Ingester:            convertIngesterSpecToHub(src.Spec.Ingester),
+		VerifyObjectStorage: src.Spec.VerifyObjectStorage,
+		Paused:              src.Spec.Paused,
+		DeletionProtection:  src.Spec.DeletionProtection,
+		TargetCluster:       src.Spec.TargetCluster,
+		WriteProbe:          src.Spec.WriteProbe,
+		Limits:              src.Spec.Limits,
+		TenantSelector:      src.Spec.TenantSelector,
+		GRPCTLS:             src.Spec.GRPCTLS,
+		NetworkPolicy:       src.Spec.NetworkPolicy,
+		Deletion:            src.Spec.Deletion,
+		StatefulSetFields:   convertStatefulSetFieldsToHub(src.Spec.StatefulSetFields),
+	}
+	dst.Status = convertThanosReceiveStatusToHub(src.Status)
+	return nil
+}
+
+// ConvertFrom converts the hub version to this ThanosReceive.
+func (dst *ThanosReceive) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1alpha1.ThanosReceive)
+	if !ok {
+		return fmt.Errorf("unexpected hub type %T", srcRaw)
+	}
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = ThanosReceiveSpec{
+		Router:              convertRouterSpecFromHub(src.Spec.Router),
+		Ingester:            convertIngesterSpecFromHub(src.Spec.Ingester),
+		VerifyObjectStorage: src.Spec.VerifyObjectStorage,
+		Paused:              src.Spec.Paused,
+		DeletionProtection:  src.Spec.DeletionProtection,
+		TargetCluster:       src.Spec.TargetCluster,
+		WriteProbe:          src.Spec.WriteProbe,
+		Limits:              src.Spec.Limits,
+		TenantSelector:      src.Spec.TenantSelector,
+		GRPCTLS:             src.Spec.GRPCTLS,
+		NetworkPolicy:       src.Spec.NetworkPolicy,
+		Deletion:            src.Spec.Deletion,
+		StatefulSetFields:   convertStatefulSetFieldsFromHub(src.Spec.StatefulSetFields),
+	}
+	dst.Status = convertThanosReceiveStatusFromHub(src.Status)
+	return nil
+}
+
+// convertRouterSpecToHub converts the router spec to the hub version.
+func convertRouterSpecToHub(in RouterSpec) v1alpha1.RouterSpec {
+	out := v1alpha1.RouterSpec{
+		Replicas:                  in.Replicas,
+		ReplicationFactor:         in.ReplicationFactor,
+		ReplicationProtocol:       in.ReplicationProtocol,
+		AsyncForwardWorkerCount:   in.AsyncForwardWorkerCount,
+		ReplicationMaxRetries:     in.ReplicationMaxRetries,
+		HashringPolicy:            in.HashringPolicy,
+		ExternalLabels:            in.ExternalLabels,
+		Tenancy:                   in.Tenancy,
+		RemoteWriteTLS:            in.RemoteWriteTLS,
+		ServiceTraffic:            in.ServiceTraffic,
+		Service:                   in.Service,
+		ExistingService:           in.ExistingService,
+		ExistingHashringConfigMap: in.ExistingHashringConfigMap,
+		HashringConfigReload:      in.HashringConfigReload,
+		Ingress:                   in.Ingress,
+		RelabelConfigs:            in.RelabelConfigs,
+		Groups:                    in.Groups,
+		CommonFields:              convertCommonFieldsToHub(in.CommonFields),
+		DeploymentFields:          convertDeploymentFieldsToHub(in.DeploymentFields),
+		Additional:                convertAdditionalToHub(in.Additional),
+	}
+	return out
+}
+
+// convertRouterSpecFromHub converts the router spec from the hub version.
+func convertRouterSpecFromHub(in v1alpha1.RouterSpec) RouterSpec {
+	out := RouterSpec{
+		Replicas:                  in.Replicas,
+		ReplicationFactor:         in.ReplicationFactor,
+		ReplicationProtocol:       in.ReplicationProtocol,
+		AsyncForwardWorkerCount:   in.AsyncForwardWorkerCount,
+		ReplicationMaxRetries:     in.ReplicationMaxRetries,
+		HashringPolicy:            in.HashringPolicy,
+		ExternalLabels:            in.ExternalLabels,
+		Tenancy:                   in.Tenancy,
+		RemoteWriteTLS:            in.RemoteWriteTLS,
+		ServiceTraffic:            in.ServiceTraffic,
+		Service:                   in.Service,
+		ExistingService:           in.ExistingService,
+		ExistingHashringConfigMap: in.ExistingHashringConfigMap,
+		HashringConfigReload:      in.HashringConfigReload,
+		Ingress:                   in.Ingress,
+		RelabelConfigs:            in.RelabelConfigs,
+		Groups:                    in.Groups,
+		CommonFields:              convertCommonFieldsFromHub(in.CommonFields),
+		DeploymentFields:          convertDeploymentFieldsFromHub(in.DeploymentFields),
+		Additional:                convertAdditionalFromHub(in.Additional),
+	}
+	return out
+}
+
+// convertIngesterSpecToHub converts the ingester spec to the hub version.
+func convertIngesterSpecToHub(in IngesterSpec) v1alpha1.IngesterSpec {
+	out := v1alpha1.IngesterSpec{
+		DefaultObjectStorageConfig: in.DefaultObjectStorageConfig,
+		HashringUpdateInterval:     in.HashringUpdateInterval,
+		ShutdownDrainSeconds:       in.ShutdownDrainSeconds,
+		SpreadAcrossZones:          in.SpreadAcrossZones,
+		ScaleDownStrategy:          in.ScaleDownStrategy,
+		UploadLagChecks:            in.UploadLagChecks,
+		UploadLagThreshold:         in.UploadLagThreshold,
+		HealthChecks:               in.HealthChecks,
+		Rollout:                    in.Rollout,
+		Additional:                 convertAdditionalToHub(in.Additional),
+	}
+	if in.Hashrings != nil {
+		out.Hashrings = make([]v1alpha1.IngesterHashringSpec, 0, len(in.Hashrings))
+		for _, item := range in.Hashrings {
+			out.Hashrings = append(out.Hashrings, convertIngesterHashringSpecToHub(item))
+		}
+	}
+	return out
+}
+
+// convertIngesterSpecFromHub converts the ingester spec from the hub version.
+func convertIngesterSpecFromHub(in v1alpha1.IngesterSpec) IngesterSpec {
+	out := IngesterSpec{
+		DefaultObjectStorageConfig: in.DefaultObjectStorageConfig,
+		HashringUpdateInterval:     in.HashringUpdateInterval,
+		ShutdownDrainSeconds:       in.ShutdownDrainSeconds,
+		SpreadAcrossZones:          in.SpreadAcrossZones,
+		ScaleDownStrategy:          in.ScaleDownStrategy,
+		UploadLagChecks:            in.UploadLagChecks,
+		UploadLagThreshold:         in.UploadLagThreshold,
+		HealthChecks:               in.HealthChecks,
+		Rollout:                    in.Rollout,
+		Additional:                 convertAdditionalFromHub(in.Additional),
+	}
+	if in.Hashrings != nil {
+		out.Hashrings = make([]IngesterHashringSpec, 0, len(in.Hashrings))
+		for _, item := range in.Hashrings {
+			out.Hashrings = append(out.Hashrings, convertIngesterHashringSpecFromHub(item))
+		}
+	}
+	return out
+}
+
+// convertIngesterHashringSpecToHub converts the hashring spec to the hub version.
+func convertIngesterHashringSpecToHub(in IngesterHashringSpec) v1alpha1.IngesterHashringSpec {
+	out := v1alpha1.IngesterHashringSpec{
+		Name:                                 in.Name,
+		ExternalLabels:                       in.ExternalLabels,
+		Replicas:                             in.Replicas,
+		TSDBConfig:                           in.TSDBConfig,
+		ObjectStorageConfig:                  in.ObjectStorageConfig,
+		StorageConfiguration:                 in.StorageConfiguration,
+		PersistentVolumeClaimRetentionPolicy: in.PersistentVolumeClaimRetentionPolicy,
+		TenancyConfig:                        in.TenancyConfig,
+		AsyncForwardWorkerCount:              in.AsyncForwardWorkerCount,
+		StoreLimitsOptions:                   in.StoreLimitsOptions,
+		TooFarInFutureTimeWindow:             in.TooFarInFutureTimeWindow,
+		GRPCCompression:                      in.GRPCCompression,
+		HashingAlgorithm:                     in.HashingAlgorithm,
+		EndpointAddress:                      in.EndpointAddress,
+		ServiceAccount:                       in.ServiceAccount,
+		UpdateStrategy:                       in.UpdateStrategy,
+		EndpointPolicy:                       in.EndpointPolicy,
+		MinReadyReplicas:                     in.MinReadyReplicas,
+		ScaleDownGracePeriod:                 in.ScaleDownGracePeriod,
+		UploadConcurrency:                    in.UploadConcurrency,
+		Autoscaling:                          in.Autoscaling,
+		AdditionalArgs:                       in.AdditionalArgs,
+		AdditionalVolumes:                    in.AdditionalVolumes,
+		AdditionalVolumeMounts:               in.AdditionalVolumeMounts,
+		CommonFields:                         convertCommonFieldsToHub(in.CommonFields),
+	}
+	return out
+}
+
+// convertIngesterHashringSpecFromHub converts the hashring spec from the hub version.
+func convertIngesterHashringSpecFromHub(in v1alpha1.IngesterHashringSpec) IngesterHashringSpec {
+	out := IngesterHashringSpec{
+		Name:                                 in.Name,
+		ExternalLabels:                       in.ExternalLabels,
+		Replicas:                             in.Replicas,
+		TSDBConfig:                           in.TSDBConfig,
+		ObjectStorageConfig:                  in.ObjectStorageConfig,
+		StorageConfiguration:                 in.StorageConfiguration,
+		PersistentVolumeClaimRetentionPolicy: in.PersistentVolumeClaimRetentionPolicy,
+		TenancyConfig:                        in.TenancyConfig,
+		AsyncForwardWorkerCount:              in.AsyncForwardWorkerCount,
+		StoreLimitsOptions:                   in.StoreLimitsOptions,
+		TooFarInFutureTimeWindow:             in.TooFarInFutureTimeWindow,
+		GRPCCompression:                      in.GRPCCompression,
+		HashingAlgorithm:                     in.HashingAlgorithm,
+		EndpointAddress:                      in.EndpointAddress,
+		ServiceAccount:                       in.ServiceAccount,
+		UpdateStrategy:                       in.UpdateStrategy,
+		EndpointPolicy:                       in.EndpointPolicy,
+		MinReadyReplicas:                     in.MinReadyReplicas,
+		ScaleDownGracePeriod:                 in.ScaleDownGracePeriod,
+		UploadConcurrency:                    in.UploadConcurrency,
+		Autoscaling:                          in.Autoscaling,
+		AdditionalArgs:                       in.AdditionalArgs,
+		AdditionalVolumes:                    in.AdditionalVolumes,
+		AdditionalVolumeMounts:               in.AdditionalVolumeMounts,
+		CommonFields:                         convertCommonFieldsFromHub(in.CommonFields),
+	}
+	return out
+}
+
+// convertThanosReceiveStatusToHub converts the ThanosReceive status to the hub version.
+func convertThanosReceiveStatusToHub(in ThanosReceiveStatus) v1alpha1.ThanosReceiveStatus {
+	out := v1alpha1.ThanosReceiveStatus{
+		Conditions:               in.Conditions,
+		Paused:                   in.Paused,
+		Router:                   in.Router,
+		HashringStatus:           in.HashringStatus,
+		RouterGroupStatus:        in.RouterGroupStatus,
+		IngesterVolumes:          in.IngesterVolumes,
+		UploadLag:                in.UploadLag,
+		UploadLagCheckTime:       in.UploadLagCheckTime,
+		IngesterHealth:           in.IngesterHealth,
+		HashringConfigHash:       in.HashringConfigHash,
+		HashringConfigVersion:    in.HashringConfigVersion,
+		HashringConfigUpdateTime: in.HashringConfigUpdateTime,
+		RouterHashringConfigHash: in.RouterHashringConfigHash,
+		Rollout:                  in.Rollout,
+		ObservedGeneration:       in.ObservedGeneration,
+	}
+	return out
+}
+
+// convertThanosReceiveStatusFromHub converts the ThanosReceive status from the hub version.
+func convertThanosReceiveStatusFromHub(in v1alpha1.ThanosReceiveStatus) ThanosReceiveStatus {
+	out := ThanosReceiveStatus{
+		Conditions:               in.Conditions,
+		Paused:                   in.Paused,
+		Router:                   in.Router,
+		HashringStatus:           in.HashringStatus,
+		RouterGroupStatus:        in.RouterGroupStatus,
+		IngesterVolumes:          in.IngesterVolumes,
+		UploadLag:                in.UploadLag,
+		UploadLagCheckTime:       in.UploadLagCheckTime,
+		IngesterHealth:           in.IngesterHealth,
+		HashringConfigHash:       in.HashringConfigHash,
+		HashringConfigVersion:    in.HashringConfigVersion,
+		HashringConfigUpdateTime: in.HashringConfigUpdateTime,
+		RouterHashringConfigHash: in.RouterHashringConfigHash,
+		Rollout:                  in.Rollout,
+		ObservedGeneration:       in.ObservedGeneration,
+	}
+	return out
+}
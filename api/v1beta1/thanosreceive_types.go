@@ -0,0 +1,485 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1beta1
+
+import (
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ThanosReceiveSpec defines the desired state of ThanosReceive
+// +kubebuilder:validation:XValidation:rule="self.ingesterSpec.hashrings.all(h, h.replicas >= self.routerSpec.replicationFactor )", message=" Ingester replicas must be greater than or equal to the Router replicas"
+// +kubebuilder:validation:XValidation:rule="self.ingesterSpec.hashrings.all(h, !has(h.autoscaling) || h.autoscaling.minReplicas >= self.routerSpec.replicationFactor)", message="Ingester autoscaling minReplicas must be greater than or equal to the Router replicationFactor"
+// +kubebuilder:validation:XValidation:rule="!has(self.ingesterSpec.shutdownDrainSeconds) || !has(self.terminationGracePeriodSeconds) || self.ingesterSpec.shutdownDrainSeconds < self.terminationGracePeriodSeconds", message="Ingester shutdownDrainSeconds must be less than terminationGracePeriodSeconds"
+// +kubebuilder:validation:XValidation:rule="!has(self.writeProbe) || !has(self.routerSpec.remoteWriteTLS)", message="writeProbe is not supported when remoteWriteTLS is set"
+type ThanosReceiveSpec struct {
+	// Router is the configuration for the router.
+	// +kubebuilder:validation:Required
+	Router RouterSpec `json:"routerSpec,omitempty"`
+	// Ingester is the configuration for the ingestor.
+	// +kubebuilder:validation:Required
+	Ingester IngesterSpec `json:"ingesterSpec,omitempty"`
+	// VerifyObjectStorage runs a Job that lists the bucket to check that the object storage is reachable
+	// with the configuration of each hashring, and records the result in the ObjectStorageVerified condition.
+	// +kubebuilder:validation:Optional
+	VerifyObjectStorage *bool `json:"verifyObjectStorage,omitempty"`
+	// When a resource is paused, no actions except for deletion
+	// will be performed on the underlying objects.
+	// +kubebuilder:validation:Optional
+	Paused *bool `json:"paused,omitempty"`
+	// DeletionProtection rejects the deletion of the ThanosReceive by the validating webhook of the operator,
+	// guarding against accidental deletions. It must be unset or set to false before the ThanosReceive can be deleted.
+	// The operator.thanos.io/deletion-protection annotation set to "true" protects the ThanosReceive too.
+	// +kubebuilder:validation:Optional
+	DeletionProtection *bool `json:"deletionProtection,omitempty"`
+	// TargetCluster is the name of the workload cluster in which the child resources are created.
+	// The cluster must be registered with the operator using the --target-cluster flag.
+	// If unset, the child resources are created in the cluster of this resource.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="targetCluster is immutable"
+	TargetCluster *string `json:"targetCluster,omitempty"`
+	// WriteProbe deploys a synthetic probe that periodically remote writes a canary series through the router
+	// and verifies that it can be queried through a ThanosQuery within a deadline.
+	// The result of the latest probe is recorded in the WriteProbeSucceeded condition.
+	// +kubebuilder:validation:Optional
+	WriteProbe *v1alpha1.WriteProbeSpec `json:"writeProbe,omitempty"`
+	// Limits are the write limits enforced by the router, globally and per tenant.
+	// The limits are rendered into a ConfigMap that is mounted into the router and reloaded on change.
+	// See https://thanos.io/tip/components/receive.md/#limits--gates-experimental
+	// +kubebuilder:validation:Optional
+	Limits *v1alpha1.ReceiveLimitsSpec `json:"limits,omitempty"`
+	// TenantSelector selects the ThanosTenant resources in the namespace of the ThanosReceive whose tenants are
+	// added to the tenants of their hashring and whose limits are added to the limits of the tenants.
+	// ThanosTenant resources are not used if unset.
+	// +kubebuilder:validation:Optional
+	TenantSelector *metav1.LabelSelector `json:"tenantSelector,omitempty"`
+	// GRPCTLS configures TLS for the gRPC endpoints of the ingesters, which serve the StoreAPI to queriers
+	// and receive the writes forwarded by the router.
+	// The ingester Services are labeled with operator.thanos.io/grpc-tls, so that queriers connect to them over TLS.
+	// +kubebuilder:validation:Optional
+	GRPCTLS *v1alpha1.ReceiveGRPCTLSConfig `json:"grpcTLS,omitempty"`
+	// NetworkPolicy generates NetworkPolicies restricting the traffic of the routers and ingesters.
+	// The ingesters only accept connections from the routers and queriers, and the routers only accept
+	// remote writes from the configured sources. Metrics are scraped from any source.
+	// No NetworkPolicies are generated if unset.
+	// +kubebuilder:validation:Optional
+	NetworkPolicy *v1alpha1.ReceiveNetworkPolicySpec `json:"networkPolicy,omitempty"`
+	// Deletion configures the cleanup performed by the operator before the ThanosReceive is deleted.
+	// +kubebuilder:validation:Optional
+	Deletion *v1alpha1.ReceiveDeletionSpec `json:"deletion,omitempty"`
+	// StatefulSetFields are the options available to all Thanos stateful
+	// components.
+	StatefulSetFields `json:",inline"`
+}
+
+// RouterSpec represents the configuration for the router
+// +kubebuilder:validation:XValidation:rule="!has(self.existingService) || (!has(self.service) && !has(self.serviceTraffic))",message="service and serviceTraffic cannot be set with existingService"
+// +kubebuilder:validation:XValidation:rule="!has(self.existingHashringConfigMap) || !has(self.hashringConfigReload) || self.hashringConfigReload != 'Rollout'",message="hashringConfigReload cannot be Rollout with existingHashringConfigMap"
+type RouterSpec struct {
+	// CommonFields are the options available to all Thanos components.
+	// +kubebuilder:validation:Optional
+	CommonFields `json:",inline"`
+	// DeploymentFields are the options available to all Thanos components managed as Deployments.
+	DeploymentFields `json:",inline"`
+	// Replicas is the number of router replicas. It is exposed through the scale subresource of the ThanosReceive,
+	// so that a HorizontalPodAutoscaler can target the ThanosReceive to scale the routers.
+	// If unset, the replicas of the router Deployment are managed externally, for example by a HorizontalPodAutoscaler
+	// targeting the Deployment, and the operator leaves them unchanged.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	Replicas *int32 `json:"replicas,omitempty"`
+	// ReplicationFactor is the replication factor for the router.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Enum=1;3;5
+	// +kubebuilder:validation:Required
+	ReplicationFactor int32 `json:"replicationFactor,omitempty"`
+	// ReplicationProtocol is the protocol for remote write replication.
+	// The capnproto protocol requires Thanos v0.35.0 or later on the routers and the ingesters it is used with.
+	// +kubebuilder:default="grpc"
+	// +kubebuilder:validation:Enum=grpc;capnproto
+	// +kubebuilder:validation:Optional
+	ReplicationProtocol *v1alpha1.ReplicationProtocol `json:"replicationProtocol,omitempty"`
+	// AsyncForwardWorkerCount is the number of concurrent workers of each router forwarding remote write requests
+	// to the ingesters. The Thanos default is used if not set.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	AsyncForwardWorkerCount *uint64 `json:"asyncForwardWorkerCount,omitempty"`
+	// ReplicationMaxRetries is the number of times a router retries replicating a remote write request to an ingester
+	// that is unavailable. Retries only apply to the grpc replication protocol and are disabled when set to 0.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=4
+	// +kubebuilder:validation:Optional
+	ReplicationMaxRetries *int32 `json:"replicationMaxRetries,omitempty"`
+	// HashringPolicy defines the policy for how the hashring is built and maintained at runtime.
+	// +kubebuilder:default="static"
+	// +kubebuilder:validation:Enum=static;dynamic
+	// +kubebuilder:validation:Optional
+	HashringPolicy *v1alpha1.HashringPolicy `json:"hashringPolicy,omitempty"`
+	// ExternalLabels set and forwarded by the router to the ingesters.
+	// +kubebuilder:default={receive: "true"}
+	// +kubebuilder:validation:Required
+	ExternalLabels v1alpha1.ExternalLabels `json:"externalLabels,omitempty"`
+	// Tenancy configures how the routers determine the tenant of remote write requests.
+	// The Thanos defaults are used for the fields that are not set.
+	// +kubebuilder:validation:Optional
+	Tenancy *v1alpha1.RouterTenancyConfig `json:"tenancy,omitempty"`
+	// RemoteWriteTLS configures TLS for the remote write endpoint served by the router.
+	// When a client CA is set, producers must authenticate with a client certificate signed by that CA.
+	// +kubebuilder:validation:Optional
+	RemoteWriteTLS *v1alpha1.TLSConfig `json:"remoteWriteTLS,omitempty"`
+	// ServiceTraffic configures how in-cluster traffic is routed by the router Service.
+	// This allows in-cluster producers to prefer routers in their own zone, reducing the cross-zone
+	// network cost of the write path.
+	// +kubebuilder:validation:Optional
+	ServiceTraffic *v1alpha1.ServiceTrafficConfig `json:"serviceTraffic,omitempty"`
+	// Service configures how the router Service is exposed, for example to accept remote writes from outside
+	// of the cluster through a cloud load balancer.
+	// +kubebuilder:validation:Optional
+	Service *v1alpha1.ServiceConfig `json:"service,omitempty"`
+	// ExistingService is the name of an existing Service in the namespace of the resource that exposes the routers.
+	// If set, the operator does not create or update the router Service, and the write probe writes through
+	// the existing Service on port 19291.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Optional
+	ExistingService *string `json:"existingService,omitempty"`
+	// ExistingHashringConfigMap is the name of an existing ConfigMap in the namespace of the resource holding
+	// the hashring configuration of the routers under the hashrings.json key.
+	// If set, the routers read their hashrings from it and the operator does not create or update it,
+	// leaving the hashrings to be managed outside of the operator.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Optional
+	ExistingHashringConfigMap *string `json:"existingHashringConfigMap,omitempty"`
+	// HashringConfigReload defines how the routers pick up changes to the hashring configuration.
+	// With Watch, the routers re-read the mounted configuration file once the kubelet has synced the ConfigMap,
+	// which may take a few minutes. With Rollout, the hash of the configuration is set on the pod template of the
+	// routers, so that every change rolls the routers out and is in effect once the rollout completes.
+	// +kubebuilder:default=Watch
+	// +kubebuilder:validation:Enum=Watch;Rollout
+	// +kubebuilder:validation:Optional
+	HashringConfigReload *v1alpha1.HashringConfigReloadStrategy `json:"hashringConfigReload,omitempty"`
+	// Ingress exposes the remote write endpoint of the routers outside of the cluster through an Ingress or a
+	// Gateway API HTTPRoute routing the given hosts to the router Service.
+	// +kubebuilder:validation:Optional
+	Ingress *v1alpha1.IngressConfig `json:"ingress,omitempty"`
+	// RelabelConfigs are relabeling rules applied by the routers to the series of remote write requests
+	// before they are forwarded to the ingesters, for example to drop series or labels.
+	// The rules are applied in order, with the semantics of Prometheus relabeling.
+	// +kubebuilder:validation:MaxItems=100
+	// +kubebuilder:validation:Optional
+	RelabelConfigs []v1alpha1.RelabelConfig `json:"relabelConfigs,omitempty"`
+	// Groups are additional groups of routers, for example for a region or a class of tenants, so that
+	// routing load can be isolated without duplicating the ThanosReceive.
+	// Each group is a Deployment of its own with its own Service, configured like the routers of the routerSpec
+	// and sharing their hashring configuration and limits.
+	// +kubebuilder:validation:MaxItems=20
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=name
+	Groups []v1alpha1.RouterGroupSpec `json:"groups,omitempty"`
+	// Additional configuration for the Thanos components. Allows you to add
+	// additional args, containers, volumes, and volume mounts to Thanos Deployments,
+	// and StatefulSets. Ideal to use for things like sidecars.
+	// +kubebuilder:validation:Optional
+	Additional `json:",inline"`
+}
+
+// IngesterSpec represents the configuration for the ingestor
+type IngesterSpec struct {
+	// DefaultObjectStorageConfig is the secret that contains the object storage configuration for the ingest components.
+	// Can be overridden by the ObjectStorageConfig in the IngesterHashringSpec per hashring.
+	// +kubebuilder:validation:Required
+	DefaultObjectStorageConfig v1alpha1.ObjectStorageConfig `json:"defaultObjectStorageConfig,omitempty"`
+	// Hashrings is a list of hashrings to route to.
+	// +kubebuilder:validation:MaxItems=100
+	// +kubebuilder:validation:Required
+	// +listType=map
+	// +listMapKey=name
+	Hashrings []IngesterHashringSpec `json:"hashrings,omitempty"`
+	// HashringUpdateInterval is the minimum time between two updates of the hashring configuration of the routers
+	// that add or change endpoints. Endpoints joining the hashrings within the interval, such as during a rolling
+	// restart of the ingesters, and changes to the zones and Cap'n Proto addresses of the endpoints are applied in
+	// a single update once the interval has passed since the last update.
+	// Endpoints leaving the hashrings, and changes to the hashrings, tenants and algorithms in the spec, are
+	// always applied straight away.
+	// Every change is applied straight away if not set.
+	// +kubebuilder:validation:Optional
+	HashringUpdateInterval *v1alpha1.Duration `json:"hashringUpdateInterval,omitempty"`
+	// ShutdownDrainSeconds is the number of seconds an ingester keeps serving after it is asked to terminate.
+	// A terminating ingester is removed from the hashring as soon as it stops being ready, and the
+	// drain period gives the routers time to reload the hashring before the ingester shuts down,
+	// reducing write errors during voluntary restarts.
+	// The drain period counts towards terminationGracePeriodSeconds.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	ShutdownDrainSeconds *int64 `json:"shutdownDrainSeconds,omitempty"`
+	// SpreadAcrossZones requires the ingesters of each hashring to be spread evenly across the zones of the
+	// topology.kubernetes.io/zone node label. Ingesters that would skew the spread are left pending.
+	// With a storage class using the WaitForFirstConsumer volume binding mode, volumes are provisioned in the zone
+	// of their ingester, so the volumes of a hashring are spread across zones too.
+	// +kubebuilder:validation:Optional
+	SpreadAcrossZones *bool `json:"spreadAcrossZones,omitempty"`
+	// ScaleDownStrategy controls what happens to the StatefulSet, Services and ServiceAccount of a hashring
+	// that is removed from the spec.
+	// Delete deletes them, once the prune grace period of the operator has expired if one is set.
+	// Orphan removes their owner references and owner label, so that they are no longer managed
+	// nor garbage collected with the ThanosReceive, and keeps them for manual removal.
+	// +kubebuilder:default=Delete
+	// +kubebuilder:validation:Optional
+	ScaleDownStrategy *v1alpha1.ScaleDownStrategy `json:"scaleDownStrategy,omitempty"`
+	// UploadLagChecks enables checks of the upload lag of the running ingesters by the operator, which scrapes
+	// their shipper and TSDB metrics. The ingesters are scraped by Pod IP, so they are not checked when the
+	// ThanosReceive is deployed to a target cluster.
+	// The upload lag of each hashring is recorded in the status and in the UploadLagDegraded condition.
+	// +kubebuilder:validation:Optional
+	UploadLagChecks *bool `json:"uploadLagChecks,omitempty"`
+	// UploadLagThreshold is the upload lag of a hashring above which the UploadLagDegraded condition is set.
+	// Blocks that have not been uploaded to object storage only exist on the data volumes of the ingesters,
+	// so a high upload lag is the amount of data that would be lost if a volume disappeared.
+	// Ingesters cut a block every two hours, so the threshold should leave room for a block to be cut and uploaded.
+	// +kubebuilder:default="3h"
+	// +kubebuilder:validation:Optional
+	UploadLagThreshold *v1alpha1.Duration `json:"uploadLagThreshold,omitempty"`
+	// HealthChecks enables health checks of the running ingesters by the operator, using the standard gRPC health
+	// service served by Thanos. The ingesters are checked by Pod IP, so they are not checked when the ThanosReceive
+	// is deployed to a target cluster.
+	// Ingesters requiring client certificates are presented the certificate of grpcTLS.client.certSecret.
+	// The generated NetworkPolicies do not allow the operator to connect to the ingesters, so it must be allowed
+	// separately when they are used.
+	// The health of each ingester is recorded in the status and in the EndpointsHealthy condition.
+	// +kubebuilder:validation:Optional
+	HealthChecks *bool `json:"healthChecks,omitempty"`
+	// Rollout configures how changes to the pods of the ingesters, such as a new Thanos version, are rolled out
+	// across hashrings.
+	// +kubebuilder:validation:Optional
+	Rollout *v1alpha1.HashringRolloutSpec `json:"rollout,omitempty"`
+	// Additional configuration for the Thanos components. Allows you to add
+	// additional args, containers, volumes, and volume mounts to Thanos Deployments,
+	// and StatefulSets. Ideal to use for things like sidecars.
+	// +kubebuilder:validation:Optional
+	Additional `json:",inline"`
+}
+
+// IngesterHashringSpec represents the configuration for a hashring to be used by the Thanos Receive StatefulSet.
+// +kubebuilder:validation:XValidation:rule="!has(self.minReadyReplicas) || self.minReadyReplicas <= self.replicas",message="minReadyReplicas cannot be greater than replicas"
+// +kubebuilder:validation:XValidation:rule="!has(self.autoscaling) || !has(self.scaleDownGracePeriod)",message="scaleDownGracePeriod cannot be set on an autoscaled hashring"
+type IngesterHashringSpec struct {
+	// CommonFields are the options available to all Thanos components.
+	// +kubebuilder:validation:Optional
+	CommonFields `json:",inline"`
+	// Name is the name of the hashring.
+	// Name will be used to generate the names for the resources created for the hashring.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^$|^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	Name string `json:"name"`
+	// ExternalLabels to add to the ingesters tsdb blocks.
+	// +kubebuilder:default={replica: "$(POD_NAME)"}
+	// +kubebuilder:validation:Required
+	ExternalLabels v1alpha1.ExternalLabels `json:"externalLabels,omitempty"`
+	// Replicas is the number of replicas/members of the hashring to add to the Thanos Receive StatefulSet.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Required
+	Replicas int32 `json:"replicas,omitempty"`
+	// TSDB configuration for the ingestor.
+	// +kubebuilder:validation:Required
+	TSDBConfig v1alpha1.TSDBConfig `json:"tsdbConfig,omitempty"`
+	// ObjectStorageConfig is the secret that contains the object storage configuration for the hashring.
+	// +kubebuilder:validation:Optional
+	ObjectStorageConfig *v1alpha1.ObjectStorageConfig `json:"objectStorageConfig,omitempty"`
+	// StorageConfiguration represents the storage to be used by the Thanos Receive StatefulSets.
+	// Increasing the size expands the existing data volumes of the hashring, if their storage class allows
+	// volume expansion. Volumes cannot be shrunk.
+	// +kubebuilder:validation:Required
+	StorageConfiguration v1alpha1.StorageConfiguration `json:"storage"`
+	// PersistentVolumeClaimRetentionPolicy controls whether the data volumes of the hashring are deleted when
+	// the hashring is scaled down or its StatefulSet is deleted.
+	// It overrides the persistentVolumeClaimRetentionPolicy of the ThanosReceive.
+	// +kubebuilder:validation:Optional
+	PersistentVolumeClaimRetentionPolicy *v1alpha1.PersistentVolumeClaimRetentionPolicy `json:"persistentVolumeClaimRetentionPolicy,omitempty"`
+	// TenancyConfig is the configuration for the tenancy options.
+	// +kubebuilder:validation:Optional
+	TenancyConfig *v1alpha1.TenancyConfig `json:"tenancyConfig,omitempty"`
+	// AsyncForwardWorkerCount is the number of concurrent workers processing forwarding of remote-write requests.
+	// +kubebuilder:default:=5
+	// +kubebuilder:validation:Optional
+	AsyncForwardWorkerCount *uint64 `json:"asyncForwardWorkerCount,omitempty"`
+	// StoreLimitsOptions is the configuration for the store API limits options.
+	// +kubebuilder:validation:Optional
+	StoreLimitsOptions *v1alpha1.StoreLimitsOptions `json:"storeLimitsOptions,omitempty"`
+	// TooFarInFutureTimeWindow is the allowed time window for ingesting samples too far in the future.
+	// 0s means disabled.
+	// +kubebuilder:default:="0s"
+	// +kubebuilder:validation:Optional
+	TooFarInFutureTimeWindow *v1alpha1.Duration `json:"tooFarInFutureTimeWindow,omitempty"`
+	// GRPCCompression defines the compression algorithm for gRPC communication.
+	// +kubebuilder:default="snappy"
+	// +kubebuilder:validation:Enum=none;snappy
+	// +kubebuilder:validation:Optional
+	GRPCCompression *v1alpha1.GRPCCompression `json:"grpcCompression,omitempty"`
+	// HashingAlgorithm defines the hashing algorithm to use for the hashring.
+	// +kubebuilder:default="ketama"
+	// +kubebuilder:validation:Enum=ketama;hashmod
+	HashingAlgorithm *string `json:"hashingAlgorithm,omitempty"`
+	// EndpointAddress controls the addresses of the hashring members written to the hashring configuration.
+	// This allows hashrings running different Thanos versions or port layouts to coexist, for example during upgrades.
+	// +kubebuilder:validation:Optional
+	EndpointAddress *v1alpha1.EndpointAddressConfig `json:"endpointAddress,omitempty"`
+	// ServiceAccount configures the ServiceAccount of the ingesters of the hashring.
+	// Every hashring has its own ServiceAccount, so that hashrings writing to different buckets
+	// can be bound to different cloud IAM roles with workload identity.
+	// +kubebuilder:validation:Optional
+	ServiceAccount *v1alpha1.ServiceAccountConfig `json:"serviceAccount,omitempty"`
+	// UpdateStrategy is the strategy used to update the ingesters of the hashring.
+	// It overrides the updateStrategy of the ThanosReceive, so that upgrades can be staged hashring by hashring.
+	// Unless rollingUpdate.maxUnavailable is set, it is derived from the replication factor of the routers.
+	// +kubebuilder:validation:XValidation:rule="!has(self.rollingUpdate) || !has(self.type) || self.type == 'RollingUpdate'",message="rollingUpdate can only be set with the RollingUpdate strategy"
+	// +kubebuilder:validation:Optional
+	UpdateStrategy *appsv1.StatefulSetUpdateStrategy `json:"updateStrategy,omitempty"`
+	// EndpointPolicy defines which ingesters of the hashring are published in the hashring configuration.
+	// ReadyOnly publishes the ingesters that are ready and not terminating. All publishes every ingester with an
+	// endpoint, so that the members of the hashring do not change while ingesters restart.
+	// +kubebuilder:default=ReadyOnly
+	// +kubebuilder:validation:Enum=ReadyOnly;All
+	// +kubebuilder:validation:Optional
+	EndpointPolicy *v1alpha1.HashringEndpointPolicy `json:"endpointPolicy,omitempty"`
+	// MinReadyReplicas is the number of ready ingesters the hashring needs before its configuration is updated.
+	// While fewer ingesters are ready, the routers keep the last configuration of the hashring, and a new hashring
+	// is not added to the configuration. The hashring policy of the router applies on top of it.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	MinReadyReplicas *int32 `json:"minReadyReplicas,omitempty"`
+	// ScaleDownGracePeriod enables the graceful scale down of the hashring.
+	// When the replicas are decreased, the ingesters to be removed are first left out of the hashring configuration,
+	// and the StatefulSet is only scaled down once the grace period has passed, so that the routers have stopped
+	// forwarding writes to them before they flush and upload their blocks on shutdown.
+	// The StatefulSet is scaled down straight away if not set.
+	// +kubebuilder:validation:Optional
+	ScaleDownGracePeriod *v1alpha1.Duration `json:"scaleDownGracePeriod,omitempty"`
+	// UploadConcurrency is the number of goroutines the ingesters use to upload the files of a block to object storage.
+	// Lowering it limits the egress of the ingesters when many blocks are uploaded at once, such as after an outage
+	// of object storage. Thanos uploads the files of a block sequentially if not set.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	UploadConcurrency *int32 `json:"uploadConcurrency,omitempty"`
+	// Autoscaling hands the replicas of the hashring over to an external autoscaler.
+	// The operator keeps the replicas of the StatefulSet within the bounds and converges the hashring configuration
+	// to the ingesters created by the autoscaler. Replicas is then only used when the StatefulSet is created.
+	// +kubebuilder:validation:Optional
+	Autoscaling *v1alpha1.HashringAutoscalingSpec `json:"autoscaling,omitempty"`
+	// AdditionalArgs are additional arguments to pass to the ingesters of the hashring.
+	// They are applied after the additionalArgs of the ingesterSpec and override them if there is a conflict.
+	// +kubebuilder:validation:items:Pattern=`^--[a-z]`
+	// +kubebuilder:validation:Optional
+	AdditionalArgs []string `json:"additionalArgs,omitempty"`
+	// AdditionalVolumes are additional volumes to add to the ingesters of the hashring, such as the credentials
+	// of the object storage of the hashring. They are added after the additionalVolumes of the ingesterSpec.
+	// +kubebuilder:validation:Optional
+	AdditionalVolumes []corev1.Volume `json:"additionalVolumes,omitempty"`
+	// AdditionalVolumeMounts are additional volume mounts to add to the Thanos container of the ingesters of the hashring.
+	// They are added after the additionalVolumeMounts of the ingesterSpec.
+	// +kubebuilder:validation:Optional
+	AdditionalVolumeMounts []corev1.VolumeMount `json:"additionalVolumeMounts,omitempty"`
+}
+
+// ThanosReceiveStatus defines the observed state of ThanosReceive
+type ThanosReceiveStatus struct {
+	// Conditions represent the latest available observations of the state of the ThanosReceive CRD.
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+	// Paused is a flag that indicates if the ThanosReceive is paused.
+	// +kubebuilder:validation:Optional
+	Paused *bool `json:"paused,omitempty"`
+	// RouterStatus is the status of the Receive router.
+	Router v1alpha1.DeploymentStatus `json:"routerStatus,omitempty"`
+	// HashringStatus is a map of ingester statuses to hashring names.
+	HashringStatus map[string]v1alpha1.StatefulSetStatus `json:"hashringStatus,omitempty"`
+	// RouterGroupStatus is a map of the statuses of the router groups to group names.
+	// +kubebuilder:validation:Optional
+	RouterGroupStatus map[string]v1alpha1.DeploymentStatus `json:"routerGroupStatus,omitempty"`
+	// IngesterVolumes is the placement of the data volumes of the ingesters, keyed by hashring name.
+	// +kubebuilder:validation:Optional
+	IngesterVolumes map[string][]v1alpha1.IngesterVolumeStatus `json:"ingesterVolumes,omitempty"`
+	// UploadLag is the upload lag of the ingesters of each hashring at the last check, keyed by hashring name.
+	// It is the age of the oldest block of the ingesters of the hashring that has not been uploaded to object
+	// storage, as reported by their shipper and TSDB metrics, and zero when all their blocks have been uploaded.
+	// Hashrings whose ingesters could not be scraped are left out.
+	// +kubebuilder:validation:Optional
+	UploadLag map[string]metav1.Duration `json:"uploadLag,omitempty"`
+	// UploadLagCheckTime is the time of the last check of the upload lag.
+	// +kubebuilder:validation:Optional
+	UploadLagCheckTime *metav1.Time `json:"uploadLagCheckTime,omitempty"`
+	// IngesterHealth is the health of the running ingesters of each hashring at the last health check,
+	// keyed by hashring name.
+	// +kubebuilder:validation:Optional
+	IngesterHealth map[string][]v1alpha1.EndpointHealthStatus `json:"ingesterHealth,omitempty"`
+	// HashringConfigHash is the hash of the hashring configuration currently applied to the router.
+	// +kubebuilder:validation:Optional
+	HashringConfigHash string `json:"hashringConfigHash,omitempty"`
+	// HashringConfigVersion is the version of the hashring configuration applied to the router.
+	// It is increased every time the content of the configuration changes.
+	// +kubebuilder:validation:Optional
+	HashringConfigVersion int64 `json:"hashringConfigVersion,omitempty"`
+	// HashringConfigUpdateTime is when the hashring configuration applied to the router last changed.
+	// +kubebuilder:validation:Optional
+	HashringConfigUpdateTime *metav1.Time `json:"hashringConfigUpdateTime,omitempty"`
+	// RouterHashringConfigHash is the hash of the hashring configuration the routers have rolled out with.
+	// It is only set with the Rollout hashring configuration reload, once all the routers run with the same
+	// configuration, and matches hashringConfigHash once the latest configuration is in effect.
+	// +kubebuilder:validation:Optional
+	RouterHashringConfigHash string `json:"routerHashringConfigHash,omitempty"`
+	// Rollout is the progress of a Sequential rollout across hashrings. It is not set when no rollout is in progress.
+	// +kubebuilder:validation:Optional
+	Rollout *v1alpha1.HashringRolloutStatus `json:"rollout,omitempty"`
+	// ObservedGeneration is the most recent generation of the ThanosReceive observed by the operator.
+	// +kubebuilder:validation:Optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:subresource:scale:specpath=.spec.routerSpec.replicas,statuspath=.status.routerStatus.replicas,selectorpath=.status.routerStatus.selector
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ThanosReceive is the Schema for the thanosreceives API
+type ThanosReceive struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of ThanosReceive
+	Spec ThanosReceiveSpec `json:"spec,omitempty"`
+	// Status defines the observed state of ThanosReceive
+	Status ThanosReceiveStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ThanosReceiveList contains a list of ThanosReceive
+type ThanosReceiveList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ThanosReceive `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ThanosReceive{}, &ThanosReceiveList{})
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1beta1
+
+import (
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// CommonFields are the options available to all Thanos components.
+// These fields reflect runtime changes to managed StatefulSet and Deployment resources.
+// +kubebuilder:validation:Optional
+// +k8s:deepcopy-gen=true
+type CommonFields struct {
+	// Version of Thanos to be deployed. Could also be image tag in case of custom downstream image.
+	// If not specified, the operator assumes the latest upstream version of
+	// Thanos available at the time when the version of the operator was released.
+	// +kubebuilder:validation:Optional
+	Version *string `json:"version,omitempty"`
+	// Base container image (without tags) to use for the Thanos components deployed via operator.
+	// +kubebuilder:validation:Optional
+	Image *string `json:"baseImage,omitempty"`
+	// Image pull policy for the Thanos containers.
+	// See https://kubernetes.io/docs/concepts/containers/images/#image-pull-policy for more details.
+	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	// +kubebuilder:default:=IfNotPresent
+	// +kubebuilder:validation:Optional
+	ImagePullPolicy *corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+	// An optional list of references to Secrets in the same namespace
+	// to use for pulling images from registries.
+	// See http://kubernetes.io/docs/user-guide/images#specifying-imagepullsecrets-on-a-pod
+	// +kubebuilder:validation:Optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// ServiceAccountAnnotations are added to the ServiceAccount created by the operator for the component,
+	// for example to bind it to a cloud IAM role with workload identity, such as IRSA or GKE Workload Identity.
+	// +kubebuilder:validation:Optional
+	ServiceAccountAnnotations map[string]string `json:"serviceAccountAnnotations,omitempty"`
+	// ServiceAnnotations are added to the Services exposing the component, for example to configure ExternalDNS
+	// or the load balancer of the cloud provider. The dedicated metrics Service is left unchanged.
+	// +kubebuilder:validation:Optional
+	ServiceAnnotations map[string]string `json:"serviceAnnotations,omitempty"`
+	// ResourceRequirements for the Thanos component container.
+	// +kubebuilder:validation:Optional
+	ResourceRequirements *corev1.ResourceRequirements `json:"resourceRequirements,omitempty"`
+	// Log level for Thanos.
+	// +kubebuilder:validation:Enum=debug;info;warn;error
+	// +kubebuilder:validation:Optional
+	LogLevel *string `json:"logLevel,omitempty"`
+	// Log format for Thanos.
+	// +kubebuilder:validation:Enum=logfmt;json
+	// +kubebuilder:default:=logfmt
+	// +kubebuilder:validation:Optional
+	LogFormat *string `json:"logFormat,omitempty"`
+	// NodeSelector defines on which Nodes the workloads are scheduled.
+	// +kubebuilder:validation:Optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Affinity defines the workloads affinity scheduling rules if specified.
+	// +kubebuilder:validation:Optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// Tolerations defines the workloads tolerations if specified.
+	// +kubebuilder:validation:Optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// TopologySpreadConstraints defines how pods are spread across topology domains.
+	// +kubebuilder:validation:Optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// SecurityContext holds pod-level security attributes and common container settings.
+	// This allows setting the FSGroup, RunAsUser, RunAsGroup, etc. for the pod.
+	// If not specified, the operator will default to FSGroup=1001.
+	// +kubebuilder:validation:Optional
+	SecurityContext *corev1.PodSecurityContext `json:"securityContext,omitempty"`
+	// ContainerSecurityContext replaces the security context of the Thanos component container.
+	// If not specified, the container runs as non-root without privilege escalation, with all capabilities
+	// dropped and the RuntimeDefault seccomp profile, which complies with the restricted Pod Security Standard.
+	// +kubebuilder:validation:Optional
+	ContainerSecurityContext *corev1.SecurityContext `json:"containerSecurityContext,omitempty"`
+	// PriorityClassName is the name of the PriorityClass of the pods of the component.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	PriorityClassName *string `json:"priorityClassName,omitempty"`
+	// TracingConfig is the secret key that contains the tracing configuration for Thanos.
+	// See https://thanos.io/tip/thanos/tracing.md/#configuration for relevant documentation.
+	// +kubebuilder:validation:Optional
+	TracingConfig *corev1.SecretKeySelector `json:"tracingConfig,omitempty"`
+	// Tracing configures distributed tracing for Thanos without a tracing configuration secret.
+	// It is ignored if TracingConfig is set.
+	// +kubebuilder:validation:Optional
+	Tracing *v1alpha1.TracingSpec `json:"tracing,omitempty"`
+	// PodDisruptionBudgetConfig holds the configuration for the PodDisruptionBudget.
+	// This allows enabling or disabling the creation of a PodDisruptionBudget for the Thanos component.
+	// When enabled, a resource that has more than one replica will have a PodDisruptionBudget created
+	// that sets maxUnavailable to 1, unless minAvailable or maxUnavailable is set.
+	// For Thanos Receive ingesters, maxUnavailable is derived by default from the replication factor and the
+	// number of replicas in the hashring, so that write quorum is preserved during disruptions.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default={enable: true}
+	PodDisruptionBudgetConfig *v1alpha1.PodDisruptionBudgetConfig `json:"podDisruptionBudgetConfig,omitempty"`
+	// MetricsService holds the configuration for a dedicated Service exposing only the metrics of the component
+	// on a port named http-metrics. This allows scrape configs, ServiceMonitors and NetworkPolicies to target
+	// the metrics without exposing the gRPC or remote write ports.
+	// When enabled, the ServiceMonitor managed by the operator scrapes this Service.
+	// +kubebuilder:validation:Optional
+	MetricsService *v1alpha1.MetricsServiceConfig `json:"metricsService,omitempty"`
+	// Probes tunes the liveness, readiness and startup probes of the Thanos component container.
+	// +kubebuilder:validation:Optional
+	Probes *v1alpha1.ProbesSpec `json:"probes,omitempty"`
+	// Labels are additional labels to add to components.
+	// In case of conflicts, these labels take precedence.
+	// +kubebuilder:validation:Optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations are additional annotations to add to components.
+	// In case of conflicts, these annotations take precedence.
+	// +kubebuilder:validation:Optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// DeploymentFields are the options available to all Thanos components managed as Deployments.
+// These fields reflect runtime changes to managed Deployment resources.
+// +k8s:deepcopy-gen=true
+type DeploymentFields struct {
+	// TerminationGracePeriodSeconds is the duration in seconds the pod is allowed to terminate gracefully after SIGTERM.
+	// +kubebuilder:validation:Optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+	// Strategy is the strategy used to replace the pods of the Deployment.
+	// If not specified, the default strategy of the component is used.
+	// +kubebuilder:validation:XValidation:rule="!has(self.rollingUpdate) || !has(self.type) || self.type == 'RollingUpdate'",message="rollingUpdate can only be set with the RollingUpdate strategy"
+	// +kubebuilder:validation:Optional
+	Strategy *appsv1.DeploymentStrategy `json:"strategy,omitempty"`
+}
+
+// StatefulSetFields are the options available to all Thanos components.
+// These fields reflect runtime changes to managed StatefulSet resources.
+// +k8s:deepcopy-gen=true
+type StatefulSetFields struct {
+	// +kubebuilder:default:=OrderedReady
+	// +kubebuilder:validation:Optional
+	PodManagementPolicy *v1alpha1.PodManagementPolicyType `json:"podManagementPolicy,omitempty"`
+	// PersistentVolumeClaimRetentionPolicy specifies the policy for retaining PVCs created from StatefulSet VolumeClaimTemplates.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default={whenDeleted: Delete, whenScaled: Delete}
+	PersistentVolumeClaimRetentionPolicy *v1alpha1.PersistentVolumeClaimRetentionPolicy `json:"persistentVolumeClaimRetentionPolicy,omitempty"`
+	// TerminationGracePeriodSeconds is the duration in seconds the pod is allowed to terminate gracefully after SIGTERM.
+	// +kubebuilder:validation:Optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+	// MinReadySeconds is the minimum number of seconds for which a newly created pod should be ready without
+	// any of its container crashing, for it to be considered available.
+	// +kubebuilder:validation:Optional
+	MinReadySeconds *int32 `json:"minReadySeconds,omitempty"`
+	// UpdateStrategy is the strategy used to update the pods of the StatefulSet.
+	// A RollingUpdate with a partition only updates the pods with an ordinal greater than or equal to the
+	// partition, and OnDelete only updates pods once they are deleted, which allows staging upgrades.
+	// If not specified, pods are updated with a RollingUpdate.
+	// +kubebuilder:validation:XValidation:rule="!has(self.rollingUpdate) || !has(self.type) || self.type == 'RollingUpdate'",message="rollingUpdate can only be set with the RollingUpdate strategy"
+	// +kubebuilder:validation:Optional
+	UpdateStrategy *appsv1.StatefulSetUpdateStrategy `json:"updateStrategy,omitempty"`
+}
+
+// Additional holds additional configuration for the Thanos components.
+type Additional struct {
+	// Additional arguments to pass to the Thanos components.
+	// An additional argument will override an existing argument provided by the operator if there is a conflict.
+	// Arguments must be flags of the form --flag or --flag=value.
+	// +kubebuilder:validation:items:Pattern=`^--[a-z]`
+	// +kubebuilder:validation:Optional
+	Args []string `json:"additionalArgs,omitempty"`
+	// Additional containers to add to the Thanos components.
+	// +kubebuilder:validation:Optional
+	Containers []corev1.Container `json:"additionalContainers,omitempty"`
+	// Additional volumes to add to the Thanos components.
+	// +kubebuilder:validation:Optional
+	Volumes []corev1.Volume `json:"additionalVolumes,omitempty"`
+	// Additional volume mounts to add to the Thanos component container in a Deployment or StatefulSet
+	// controlled by the operator.
+	// +kubebuilder:validation:Optional
+	VolumeMounts []corev1.VolumeMount `json:"additionalVolumeMounts,omitempty"`
+	// Additional ports to expose on the Thanos component container in a Deployment or StatefulSet
+	// controlled by the operator.
+	// +kubebuilder:validation:Optional
+	Ports []corev1.ContainerPort `json:"additionalPorts,omitempty"`
+	// Additional environment variables to add to the Thanos component container in a Deployment or StatefulSet
+	// controlled by the operator.
+	// +kubebuilder:validation:Optional
+	Env []corev1.EnvVar `json:"additionalEnv,omitempty"`
+	// Additional sources of environment variables, such as ConfigMaps and Secrets, to add to the Thanos component
+	// container in a Deployment or StatefulSet controlled by the operator.
+	// Variables set in additionalEnv take precedence over those of the sources.
+	// +kubebuilder:validation:Optional
+	EnvFrom []corev1.EnvFromSource `json:"additionalEnvFrom,omitempty"`
+	// AdditionalServicePorts are additional ports to expose on the Service for the Thanos component.
+	// +kubebuilder:validation:Optional
+	ServicePorts []corev1.ServicePort `json:"additionalServicePorts,omitempty"`
+	// ConfigMaps defines a list of ConfigMaps in the same namespace as the Thanos components, which shall be mounted into the Thanos Pods.
+	// Each ConfigMap is added to the workload definition as a volume named configmap-<configmap-name>.
+	// The ConfigMaps are mounted into /etc/thanos/configmaps/ in the container.
+	// +kubebuilder:validation:Optional
+	ConfigMaps []string `json:"configMaps,omitempty"`
+	// Secrets defines a list of Secrets in the same namespace as the Thanos components, which shall be mounted into the Thanos Pods.
+	// Each Secret is added to the workload definition as a volume named secret-<secret-name>.
+	// The Secrets are mounted into /etc/thanos/secrets/ in the container.
+	// +kubebuilder:validation:Optional
+	Secrets []string `json:"secrets,omitempty"`
+}
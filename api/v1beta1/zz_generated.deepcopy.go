@@ -0,0 +1,1183 @@
+//go:build !ignore_autogenerated
+
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Additional) DeepCopyInto(out *Additional) {
+	*out = *in
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]corev1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]corev1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]corev1.ContainerPort, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]corev1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ServicePorts != nil {
+		in, out := &in.ServicePorts, &out.ServicePorts
+		*out = make([]corev1.ServicePort, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ConfigMaps != nil {
+		in, out := &in.ConfigMaps, &out.ConfigMaps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Additional.
+func (in *Additional) DeepCopy() *Additional {
+	if in == nil {
+		return nil
+	}
+	out := new(Additional)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommonFields) DeepCopyInto(out *CommonFields) {
+	*out = *in
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(string)
+		**out = **in
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(string)
+		**out = **in
+	}
+	if in.ImagePullPolicy != nil {
+		in, out := &in.ImagePullPolicy, &out.ImagePullPolicy
+		*out = new(corev1.PullPolicy)
+		**out = **in
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceAccountAnnotations != nil {
+		in, out := &in.ServiceAccountAnnotations, &out.ServiceAccountAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ServiceAnnotations != nil {
+		in, out := &in.ServiceAnnotations, &out.ServiceAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ResourceRequirements != nil {
+		in, out := &in.ResourceRequirements, &out.ResourceRequirements
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LogLevel != nil {
+		in, out := &in.LogLevel, &out.LogLevel
+		*out = new(string)
+		**out = **in
+	}
+	if in.LogFormat != nil {
+		in, out := &in.LogFormat, &out.LogFormat
+		*out = new(string)
+		**out = **in
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(corev1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ContainerSecurityContext != nil {
+		in, out := &in.ContainerSecurityContext, &out.ContainerSecurityContext
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PriorityClassName != nil {
+		in, out := &in.PriorityClassName, &out.PriorityClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.TracingConfig != nil {
+		in, out := &in.TracingConfig, &out.TracingConfig
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tracing != nil {
+		in, out := &in.Tracing, &out.Tracing
+		*out = new(v1alpha1.TracingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDisruptionBudgetConfig != nil {
+		in, out := &in.PodDisruptionBudgetConfig, &out.PodDisruptionBudgetConfig
+		*out = new(v1alpha1.PodDisruptionBudgetConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MetricsService != nil {
+		in, out := &in.MetricsService, &out.MetricsService
+		*out = new(v1alpha1.MetricsServiceConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = new(v1alpha1.ProbesSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommonFields.
+func (in *CommonFields) DeepCopy() *CommonFields {
+	if in == nil {
+		return nil
+	}
+	out := new(CommonFields)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeploymentFields) DeepCopyInto(out *DeploymentFields) {
+	*out = *in
+	if in.TerminationGracePeriodSeconds != nil {
+		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Strategy != nil {
+		in, out := &in.Strategy, &out.Strategy
+		*out = new(appsv1.DeploymentStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentFields.
+func (in *DeploymentFields) DeepCopy() *DeploymentFields {
+	if in == nil {
+		return nil
+	}
+	out := new(DeploymentFields)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngesterHashringSpec) DeepCopyInto(out *IngesterHashringSpec) {
+	*out = *in
+	in.CommonFields.DeepCopyInto(&out.CommonFields)
+	if in.ExternalLabels != nil {
+		in, out := &in.ExternalLabels, &out.ExternalLabels
+		*out = make(v1alpha1.ExternalLabels, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.TSDBConfig.DeepCopyInto(&out.TSDBConfig)
+	if in.ObjectStorageConfig != nil {
+		in, out := &in.ObjectStorageConfig, &out.ObjectStorageConfig
+		*out = new(v1alpha1.ObjectStorageConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	in.StorageConfiguration.DeepCopyInto(&out.StorageConfiguration)
+	if in.PersistentVolumeClaimRetentionPolicy != nil {
+		in, out := &in.PersistentVolumeClaimRetentionPolicy, &out.PersistentVolumeClaimRetentionPolicy
+		*out = new(v1alpha1.PersistentVolumeClaimRetentionPolicy)
+		**out = **in
+	}
+	if in.TenancyConfig != nil {
+		in, out := &in.TenancyConfig, &out.TenancyConfig
+		*out = new(v1alpha1.TenancyConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AsyncForwardWorkerCount != nil {
+		in, out := &in.AsyncForwardWorkerCount, &out.AsyncForwardWorkerCount
+		*out = new(uint64)
+		**out = **in
+	}
+	if in.StoreLimitsOptions != nil {
+		in, out := &in.StoreLimitsOptions, &out.StoreLimitsOptions
+		*out = new(v1alpha1.StoreLimitsOptions)
+		**out = **in
+	}
+	if in.TooFarInFutureTimeWindow != nil {
+		in, out := &in.TooFarInFutureTimeWindow, &out.TooFarInFutureTimeWindow
+		*out = new(v1alpha1.Duration)
+		**out = **in
+	}
+	if in.GRPCCompression != nil {
+		in, out := &in.GRPCCompression, &out.GRPCCompression
+		*out = new(v1alpha1.GRPCCompression)
+		**out = **in
+	}
+	if in.HashingAlgorithm != nil {
+		in, out := &in.HashingAlgorithm, &out.HashingAlgorithm
+		*out = new(string)
+		**out = **in
+	}
+	if in.EndpointAddress != nil {
+		in, out := &in.EndpointAddress, &out.EndpointAddress
+		*out = new(v1alpha1.EndpointAddressConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceAccount != nil {
+		in, out := &in.ServiceAccount, &out.ServiceAccount
+		*out = new(v1alpha1.ServiceAccountConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(appsv1.StatefulSetUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EndpointPolicy != nil {
+		in, out := &in.EndpointPolicy, &out.EndpointPolicy
+		*out = new(v1alpha1.HashringEndpointPolicy)
+		**out = **in
+	}
+	if in.MinReadyReplicas != nil {
+		in, out := &in.MinReadyReplicas, &out.MinReadyReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ScaleDownGracePeriod != nil {
+		in, out := &in.ScaleDownGracePeriod, &out.ScaleDownGracePeriod
+		*out = new(v1alpha1.Duration)
+		**out = **in
+	}
+	if in.UploadConcurrency != nil {
+		in, out := &in.UploadConcurrency, &out.UploadConcurrency
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(v1alpha1.HashringAutoscalingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalArgs != nil {
+		in, out := &in.AdditionalArgs, &out.AdditionalArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AdditionalVolumes != nil {
+		in, out := &in.AdditionalVolumes, &out.AdditionalVolumes
+		*out = make([]corev1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdditionalVolumeMounts != nil {
+		in, out := &in.AdditionalVolumeMounts, &out.AdditionalVolumeMounts
+		*out = make([]corev1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngesterHashringSpec.
+func (in *IngesterHashringSpec) DeepCopy() *IngesterHashringSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IngesterHashringSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngesterSpec) DeepCopyInto(out *IngesterSpec) {
+	*out = *in
+	in.DefaultObjectStorageConfig.DeepCopyInto(&out.DefaultObjectStorageConfig)
+	if in.Hashrings != nil {
+		in, out := &in.Hashrings, &out.Hashrings
+		*out = make([]IngesterHashringSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HashringUpdateInterval != nil {
+		in, out := &in.HashringUpdateInterval, &out.HashringUpdateInterval
+		*out = new(v1alpha1.Duration)
+		**out = **in
+	}
+	if in.ShutdownDrainSeconds != nil {
+		in, out := &in.ShutdownDrainSeconds, &out.ShutdownDrainSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SpreadAcrossZones != nil {
+		in, out := &in.SpreadAcrossZones, &out.SpreadAcrossZones
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ScaleDownStrategy != nil {
+		in, out := &in.ScaleDownStrategy, &out.ScaleDownStrategy
+		*out = new(v1alpha1.ScaleDownStrategy)
+		**out = **in
+	}
+	if in.UploadLagChecks != nil {
+		in, out := &in.UploadLagChecks, &out.UploadLagChecks
+		*out = new(bool)
+		**out = **in
+	}
+	if in.UploadLagThreshold != nil {
+		in, out := &in.UploadLagThreshold, &out.UploadLagThreshold
+		*out = new(v1alpha1.Duration)
+		**out = **in
+	}
+	if in.HealthChecks != nil {
+		in, out := &in.HealthChecks, &out.HealthChecks
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(v1alpha1.HashringRolloutSpec)
+		**out = **in
+	}
+	in.Additional.DeepCopyInto(&out.Additional)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngesterSpec.
+func (in *IngesterSpec) DeepCopy() *IngesterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IngesterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryFrontendSpec) DeepCopyInto(out *QueryFrontendSpec) {
+	*out = *in
+	in.CommonFields.DeepCopyInto(&out.CommonFields)
+	in.DeploymentFields.DeepCopyInto(&out.DeploymentFields)
+	if in.QueryLabelSelector != nil {
+		in, out := &in.QueryLabelSelector, &out.QueryLabelSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LogQueriesLongerThan != nil {
+		in, out := &in.LogQueriesLongerThan, &out.LogQueriesLongerThan
+		*out = new(v1alpha1.Duration)
+		**out = **in
+	}
+	if in.QueryRangeResponseCacheConfig != nil {
+		in, out := &in.QueryRangeResponseCacheConfig, &out.QueryRangeResponseCacheConfig
+		*out = new(v1alpha1.CacheConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(v1alpha1.ManagedCacheSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.QueryRangeSplitInterval != nil {
+		in, out := &in.QueryRangeSplitInterval, &out.QueryRangeSplitInterval
+		*out = new(v1alpha1.Duration)
+		**out = **in
+	}
+	if in.LabelsSplitInterval != nil {
+		in, out := &in.LabelsSplitInterval, &out.LabelsSplitInterval
+		*out = new(v1alpha1.Duration)
+		**out = **in
+	}
+	if in.LabelsDefaultTimeRange != nil {
+		in, out := &in.LabelsDefaultTimeRange, &out.LabelsDefaultTimeRange
+		*out = new(v1alpha1.Duration)
+		**out = **in
+	}
+	if in.QueryRangeMaxQueryParallelism != nil {
+		in, out := &in.QueryRangeMaxQueryParallelism, &out.QueryRangeMaxQueryParallelism
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LabelsMaxQueryParallelism != nil {
+		in, out := &in.LabelsMaxQueryParallelism, &out.LabelsMaxQueryParallelism
+		*out = new(int32)
+		**out = **in
+	}
+	if in.QueryRangeMaxQueryLength != nil {
+		in, out := &in.QueryRangeMaxQueryLength, &out.QueryRangeMaxQueryLength
+		*out = new(v1alpha1.Duration)
+		**out = **in
+	}
+	if in.DownstreamConfig != nil {
+		in, out := &in.DownstreamConfig, &out.DownstreamConfig
+		*out = new(v1alpha1.QueryFrontendDownstreamConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SessionAffinity != nil {
+		in, out := &in.SessionAffinity, &out.SessionAffinity
+		*out = new(v1alpha1.SessionAffinityConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(v1alpha1.ServiceConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Additional.DeepCopyInto(&out.Additional)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryFrontendSpec.
+func (in *QueryFrontendSpec) DeepCopy() *QueryFrontendSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryFrontendSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouterSpec) DeepCopyInto(out *RouterSpec) {
+	*out = *in
+	in.CommonFields.DeepCopyInto(&out.CommonFields)
+	in.DeploymentFields.DeepCopyInto(&out.DeploymentFields)
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ReplicationProtocol != nil {
+		in, out := &in.ReplicationProtocol, &out.ReplicationProtocol
+		*out = new(v1alpha1.ReplicationProtocol)
+		**out = **in
+	}
+	if in.AsyncForwardWorkerCount != nil {
+		in, out := &in.AsyncForwardWorkerCount, &out.AsyncForwardWorkerCount
+		*out = new(uint64)
+		**out = **in
+	}
+	if in.ReplicationMaxRetries != nil {
+		in, out := &in.ReplicationMaxRetries, &out.ReplicationMaxRetries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.HashringPolicy != nil {
+		in, out := &in.HashringPolicy, &out.HashringPolicy
+		*out = new(v1alpha1.HashringPolicy)
+		**out = **in
+	}
+	if in.ExternalLabels != nil {
+		in, out := &in.ExternalLabels, &out.ExternalLabels
+		*out = make(v1alpha1.ExternalLabels, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tenancy != nil {
+		in, out := &in.Tenancy, &out.Tenancy
+		*out = new(v1alpha1.RouterTenancyConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RemoteWriteTLS != nil {
+		in, out := &in.RemoteWriteTLS, &out.RemoteWriteTLS
+		*out = new(v1alpha1.TLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceTraffic != nil {
+		in, out := &in.ServiceTraffic, &out.ServiceTraffic
+		*out = new(v1alpha1.ServiceTrafficConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(v1alpha1.ServiceConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExistingService != nil {
+		in, out := &in.ExistingService, &out.ExistingService
+		*out = new(string)
+		**out = **in
+	}
+	if in.ExistingHashringConfigMap != nil {
+		in, out := &in.ExistingHashringConfigMap, &out.ExistingHashringConfigMap
+		*out = new(string)
+		**out = **in
+	}
+	if in.HashringConfigReload != nil {
+		in, out := &in.HashringConfigReload, &out.HashringConfigReload
+		*out = new(v1alpha1.HashringConfigReloadStrategy)
+		**out = **in
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(v1alpha1.IngressConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RelabelConfigs != nil {
+		in, out := &in.RelabelConfigs, &out.RelabelConfigs
+		*out = make([]v1alpha1.RelabelConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]v1alpha1.RouterGroupSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Additional.DeepCopyInto(&out.Additional)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouterSpec.
+func (in *RouterSpec) DeepCopy() *RouterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RouterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatefulSetFields) DeepCopyInto(out *StatefulSetFields) {
+	*out = *in
+	if in.PodManagementPolicy != nil {
+		in, out := &in.PodManagementPolicy, &out.PodManagementPolicy
+		*out = new(v1alpha1.PodManagementPolicyType)
+		**out = **in
+	}
+	if in.PersistentVolumeClaimRetentionPolicy != nil {
+		in, out := &in.PersistentVolumeClaimRetentionPolicy, &out.PersistentVolumeClaimRetentionPolicy
+		*out = new(v1alpha1.PersistentVolumeClaimRetentionPolicy)
+		**out = **in
+	}
+	if in.TerminationGracePeriodSeconds != nil {
+		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MinReadySeconds != nil {
+		in, out := &in.MinReadySeconds, &out.MinReadySeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(appsv1.StatefulSetUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatefulSetFields.
+func (in *StatefulSetFields) DeepCopy() *StatefulSetFields {
+	if in == nil {
+		return nil
+	}
+	out := new(StatefulSetFields)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThanosQuery) DeepCopyInto(out *ThanosQuery) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosQuery.
+func (in *ThanosQuery) DeepCopy() *ThanosQuery {
+	if in == nil {
+		return nil
+	}
+	out := new(ThanosQuery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ThanosQuery) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThanosQueryList) DeepCopyInto(out *ThanosQueryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ThanosQuery, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosQueryList.
+func (in *ThanosQueryList) DeepCopy() *ThanosQueryList {
+	if in == nil {
+		return nil
+	}
+	out := new(ThanosQueryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ThanosQueryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThanosQuerySpec) DeepCopyInto(out *ThanosQuerySpec) {
+	*out = *in
+	in.CommonFields.DeepCopyInto(&out.CommonFields)
+	in.DeploymentFields.DeepCopyInto(&out.DeploymentFields)
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ReplicaLabels != nil {
+		in, out := &in.ReplicaLabels, &out.ReplicaLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DiscoverReplicaLabels != nil {
+		in, out := &in.DiscoverReplicaLabels, &out.DiscoverReplicaLabels
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1alpha1.Duration)
+		**out = **in
+	}
+	if in.LookbackDelta != nil {
+		in, out := &in.LookbackDelta, &out.LookbackDelta
+		*out = new(v1alpha1.Duration)
+		**out = **in
+	}
+	if in.MaxConcurrent != nil {
+		in, out := &in.MaxConcurrent, &out.MaxConcurrent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AutoDownsampling != nil {
+		in, out := &in.AutoDownsampling, &out.AutoDownsampling
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PartialResponse != nil {
+		in, out := &in.PartialResponse, &out.PartialResponse
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DeduplicationFunc != nil {
+		in, out := &in.DeduplicationFunc, &out.DeduplicationFunc
+		*out = new(string)
+		**out = **in
+	}
+	if in.DiscoveryMode != nil {
+		in, out := &in.DiscoveryMode, &out.DiscoveryMode
+		*out = new(v1alpha1.QueryDiscoveryMode)
+		**out = **in
+	}
+	if in.EndpointDiscoveryScheme != nil {
+		in, out := &in.EndpointDiscoveryScheme, &out.EndpointDiscoveryScheme
+		*out = new(v1alpha1.EndpointDiscoveryScheme)
+		**out = **in
+	}
+	if in.StoreLabelSelector != nil {
+		in, out := &in.StoreLabelSelector, &out.StoreLabelSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TelemetryQuantiles != nil {
+		in, out := &in.TelemetryQuantiles, &out.TelemetryQuantiles
+		*out = new(v1alpha1.TelemetryQuantiles)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RequestLogging != nil {
+		in, out := &in.RequestLogging, &out.RequestLogging
+		*out = new(v1alpha1.RequestLoggingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ActiveQueryTracking != nil {
+		in, out := &in.ActiveQueryTracking, &out.ActiveQueryTracking
+		*out = new(bool)
+		**out = **in
+	}
+	if in.WebConfig != nil {
+		in, out := &in.WebConfig, &out.WebConfig
+		*out = new(v1alpha1.WebConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.QueryFrontend != nil {
+		in, out := &in.QueryFrontend, &out.QueryFrontend
+		*out = new(QueryFrontendSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DeletionProtection != nil {
+		in, out := &in.DeletionProtection, &out.DeletionProtection
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TargetCluster != nil {
+		in, out := &in.TargetCluster, &out.TargetCluster
+		*out = new(string)
+		**out = **in
+	}
+	if in.ReadProbe != nil {
+		in, out := &in.ReadProbe, &out.ReadProbe
+		*out = new(v1alpha1.ReadProbeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthChecks != nil {
+		in, out := &in.HealthChecks, &out.HealthChecks
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ArgsFile != nil {
+		in, out := &in.ArgsFile, &out.ArgsFile
+		*out = new(bool)
+		**out = **in
+	}
+	if in.GRPCServerTLS != nil {
+		in, out := &in.GRPCServerTLS, &out.GRPCServerTLS
+		*out = new(v1alpha1.TLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GRPCClientTLS != nil {
+		in, out := &in.GRPCClientTLS, &out.GRPCClientTLS
+		*out = new(v1alpha1.GRPCClientTLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalEndpoints != nil {
+		in, out := &in.ExternalEndpoints, &out.ExternalEndpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Federation != nil {
+		in, out := &in.Federation, &out.Federation
+		*out = new(v1alpha1.QueryFederationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(v1alpha1.IngressConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Additional.DeepCopyInto(&out.Additional)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosQuerySpec.
+func (in *ThanosQuerySpec) DeepCopy() *ThanosQuerySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ThanosQuerySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThanosQueryStatus) DeepCopyInto(out *ThanosQueryStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
+	out.Querier = in.Querier
+	out.QueryFrontend = in.QueryFrontend
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]v1alpha1.QueryEndpointStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReadProbe != nil {
+		in, out := &in.ReadProbe, &out.ReadProbe
+		*out = new(v1alpha1.ReadProbeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Remotes != nil {
+		in, out := &in.Remotes, &out.Remotes
+		*out = make([]v1alpha1.RemoteEndpointStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EndpointHealth != nil {
+		in, out := &in.EndpointHealth, &out.EndpointHealth
+		*out = make([]v1alpha1.EndpointHealthStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosQueryStatus.
+func (in *ThanosQueryStatus) DeepCopy() *ThanosQueryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ThanosQueryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThanosReceive) DeepCopyInto(out *ThanosReceive) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosReceive.
+func (in *ThanosReceive) DeepCopy() *ThanosReceive {
+	if in == nil {
+		return nil
+	}
+	out := new(ThanosReceive)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ThanosReceive) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThanosReceiveList) DeepCopyInto(out *ThanosReceiveList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ThanosReceive, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosReceiveList.
+func (in *ThanosReceiveList) DeepCopy() *ThanosReceiveList {
+	if in == nil {
+		return nil
+	}
+	out := new(ThanosReceiveList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ThanosReceiveList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThanosReceiveSpec) DeepCopyInto(out *ThanosReceiveSpec) {
+	*out = *in
+	in.Router.DeepCopyInto(&out.Router)
+	in.Ingester.DeepCopyInto(&out.Ingester)
+	if in.VerifyObjectStorage != nil {
+		in, out := &in.VerifyObjectStorage, &out.VerifyObjectStorage
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DeletionProtection != nil {
+		in, out := &in.DeletionProtection, &out.DeletionProtection
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TargetCluster != nil {
+		in, out := &in.TargetCluster, &out.TargetCluster
+		*out = new(string)
+		**out = **in
+	}
+	if in.WriteProbe != nil {
+		in, out := &in.WriteProbe, &out.WriteProbe
+		*out = new(v1alpha1.WriteProbeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = new(v1alpha1.ReceiveLimitsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TenantSelector != nil {
+		in, out := &in.TenantSelector, &out.TenantSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GRPCTLS != nil {
+		in, out := &in.GRPCTLS, &out.GRPCTLS
+		*out = new(v1alpha1.ReceiveGRPCTLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(v1alpha1.ReceiveNetworkPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Deletion != nil {
+		in, out := &in.Deletion, &out.Deletion
+		*out = new(v1alpha1.ReceiveDeletionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	in.StatefulSetFields.DeepCopyInto(&out.StatefulSetFields)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosReceiveSpec.
+func (in *ThanosReceiveSpec) DeepCopy() *ThanosReceiveSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ThanosReceiveSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThanosReceiveStatus) DeepCopyInto(out *ThanosReceiveStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
+	out.Router = in.Router
+	if in.HashringStatus != nil {
+		in, out := &in.HashringStatus, &out.HashringStatus
+		*out = make(map[string]v1alpha1.StatefulSetStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RouterGroupStatus != nil {
+		in, out := &in.RouterGroupStatus, &out.RouterGroupStatus
+		*out = make(map[string]v1alpha1.DeploymentStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.IngesterVolumes != nil {
+		in, out := &in.IngesterVolumes, &out.IngesterVolumes
+		*out = make(map[string][]v1alpha1.IngesterVolumeStatus, len(*in))
+		for key, val := range *in {
+			var outVal []v1alpha1.IngesterVolumeStatus
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]v1alpha1.IngesterVolumeStatus, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.UploadLag != nil {
+		in, out := &in.UploadLag, &out.UploadLag
+		*out = make(map[string]v1.Duration, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.UploadLagCheckTime != nil {
+		in, out := &in.UploadLagCheckTime, &out.UploadLagCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.IngesterHealth != nil {
+		in, out := &in.IngesterHealth, &out.IngesterHealth
+		*out = make(map[string][]v1alpha1.EndpointHealthStatus, len(*in))
+		for key, val := range *in {
+			var outVal []v1alpha1.EndpointHealthStatus
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]v1alpha1.EndpointHealthStatus, len(*in))
+				for i := range *in {
+					(*in)[i].DeepCopyInto(&(*out)[i])
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.HashringConfigUpdateTime != nil {
+		in, out := &in.HashringConfigUpdateTime, &out.HashringConfigUpdateTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(v1alpha1.HashringRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosReceiveStatus.
+func (in *ThanosReceiveStatus) DeepCopy() *ThanosReceiveStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ThanosReceiveStatus)
+	in.DeepCopyInto(out)
+	return out
+}
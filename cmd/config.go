@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"time"
+
+	"github.com/thanos-community/thanos-operator/internal/pkg/featuregate"
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+	"github.com/thanos-community/thanos-operator/internal/pkg/operatorconfig"
+)
+
+// configReloadInterval is the interval at which the configuration file is checked for changes.
+const configReloadInterval = 10 * time.Second
+
+// fileSettings are the settings of the operator that the configuration file can set.
+type fileSettings struct {
+	logLevel         *string
+	enabledFeatures  *featuregate.Flag
+	enableWebhooks   *bool
+	resyncInterval   *time.Duration
+	pruneGracePeriod *time.Duration
+}
+
+// setFlags returns the names of the flags set on the command line, which take precedence over the configuration file.
+func setFlags() map[string]bool {
+	set := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	return set
+}
+
+// applyConfigFile sets the settings from the configuration file, except for those whose flag is set on the
+// command line, and the defaults of the Thanos components.
+func applyConfigFile(conf operatorconfig.Config, settings fileSettings, set map[string]bool) {
+	if conf.LogLevel != "" && !set["log.level"] {
+		*settings.logLevel = conf.LogLevel
+	}
+	for _, feature := range conf.EnableFeatures {
+		if !settings.enabledFeatures.Contains(feature) {
+			*settings.enabledFeatures = append(*settings.enabledFeatures, feature)
+		}
+	}
+	if conf.EnableWebhooks != nil && !set["enable-webhooks"] {
+		*settings.enableWebhooks = *conf.EnableWebhooks
+	}
+	if conf.ResyncInterval != nil && !set["resync-interval"] {
+		*settings.resyncInterval = conf.ResyncInterval.Duration
+	}
+	if conf.PruneGracePeriod != nil && !set["prune-grace-period"] {
+		*settings.pruneGracePeriod = conf.PruneGracePeriod.Duration
+	}
+	applyComponentDefaults(conf)
+}
+
+// applyComponentDefaults sets the defaults of the Thanos components from the configuration file.
+func applyComponentDefaults(conf operatorconfig.Config) {
+	manifests.SetComponentDefaults(manifests.ComponentDefaults{
+		Image:    conf.Defaults.Image,
+		Version:  conf.Defaults.Version,
+		LogLevel: conf.Defaults.LogLevel,
+	})
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/thanos-community/thanos-operator/internal/pkg/fleet"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+// runFleet implements the fleet subcommand, which applies a maintenance action to all the Thanos resources
+// matching a label selector. It returns the exit code.
+func runFleet(args []string) int {
+	fset := flag.NewFlagSet("fleet", flag.ContinueOnError)
+	var selector, namespace, kinds, kubeconfig string
+	var allNamespaces bool
+	fset.StringVar(&selector, "l", "", "Label selector of the resources, for example env=prod. If unset, all resources are selected.")
+	fset.StringVar(&namespace, "n", "default", "Namespace of the resources.")
+	fset.BoolVar(&allNamespaces, "A", false, "Select resources in all namespaces.")
+	fset.StringVar(&kinds, "kinds", "", fmt.Sprintf("Comma-separated kinds of the resources, among %s. If unset, all kinds are selected.", strings.Join(fleet.Kinds(), ", ")))
+	fset.StringVar(&kubeconfig, "kubeconfig", "", "Path to a kubeconfig. If unset, the in-cluster configuration or the default kubeconfig is used.")
+	fset.Usage = func() {
+		actions := make([]string, 0, len(fleet.Actions))
+		for _, a := range fleet.Actions {
+			actions = append(actions, string(a))
+		}
+		fmt.Fprintf(fset.Output(), "Usage: %s fleet <%s> [flags]\n", os.Args[0], strings.Join(actions, "|"))
+		fset.PrintDefaults()
+	}
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fset.Usage()
+		return 2
+	}
+	action := fleet.Action(args[0])
+	if err := fset.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	sel := fleet.Selection{Namespace: namespace}
+	if allNamespaces {
+		sel.Namespace = ""
+	}
+	if selector != "" {
+		parsed, err := labels.Parse(selector)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid label selector: %v\n", err)
+			return 2
+		}
+		sel.Selector = parsed
+	}
+	if kinds != "" {
+		sel.Kinds = strings.Split(kinds, ",")
+	}
+
+	cfg, err := config.GetConfig()
+	if kubeconfig != "" {
+		cfg, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load kubeconfig: %v\n", err)
+		return 2
+	}
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create client: %v\n", err)
+		return 2
+	}
+
+	results, err := fleet.Apply(context.Background(), c, action, sel, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 2
+	}
+
+	code := 0
+	for _, result := range results {
+		if result.Err != nil {
+			fmt.Printf("%s: error: %v\n", result.Resource, result.Err)
+			code = 1
+			continue
+		}
+		fmt.Printf("%s: %s\n", result.Resource, action)
+	}
+	if len(results) == 0 {
+		fmt.Println("no resources matched")
+	}
+	return code
+}
@@ -0,0 +1,103 @@
+// Command kubectl-thanos is a kubectl plugin printing human-readable summaries of Thanos resources.
+// Installed on the PATH, it is run as kubectl thanos.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+	"github.com/thanos-community/thanos-operator/internal/pkg/describe"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run runs the plugin with the given arguments and returns the exit code.
+func run(args []string) int {
+	if len(args) == 0 || args[0] != "status" {
+		fmt.Fprintf(os.Stderr, "Usage: kubectl thanos status <%s> <name> [flags]\n", strings.Join(describe.Kinds(), "|"))
+		return 2
+	}
+	return runStatus(args[1:])
+}
+
+// runStatus implements the status subcommand, which prints a summary of a Thanos resource.
+func runStatus(args []string) int {
+	fset := flag.NewFlagSet("status", flag.ContinueOnError)
+	var namespace, kubeconfig, kubecontext string
+	fset.StringVar(&namespace, "n", "", "Namespace of the resource. If unset, the namespace of the current context is used.")
+	fset.StringVar(&namespace, "namespace", "", "Namespace of the resource. If unset, the namespace of the current context is used.")
+	fset.StringVar(&kubeconfig, "kubeconfig", "", "Path to a kubeconfig. If unset, the default kubeconfig is used.")
+	fset.StringVar(&kubecontext, "context", "", "Name of the kubeconfig context to use. If unset, the current context is used.")
+	fset.Usage = func() {
+		fmt.Fprintf(fset.Output(), "Usage: kubectl thanos status <%s> <name> [flags]\n", strings.Join(describe.Kinds(), "|"))
+		fset.PrintDefaults()
+	}
+
+	// flags may be set before, between or after the kind and the name, as with kubectl
+	var positional []string
+	for {
+		if err := fset.Parse(args); err != nil {
+			return 2
+		}
+		if fset.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fset.Arg(0))
+		args = fset.Args()[1:]
+	}
+	if len(positional) != 2 {
+		fset.Usage()
+		return 2
+	}
+
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = kubeconfig
+	loader := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: kubecontext})
+	cfg, err := loader.ClientConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load kubeconfig: %v\n", err)
+		return 2
+	}
+	if namespace == "" {
+		if namespace, _, err = loader.Namespace(); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to get the namespace of the current context: %v\n", err)
+			return 2
+		}
+	}
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 2
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 2
+	}
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create client: %v\n", err)
+		return 2
+	}
+
+	key := types.NamespacedName{Namespace: namespace, Name: positional[1]}
+	if err := describe.Describe(context.Background(), c, os.Stdout, positional[0], key, time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	return 0
+}
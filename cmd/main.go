@@ -17,15 +17,18 @@ limitations under the License.
 package main
 
 import (
+	"cmp"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"flag"
 	"fmt"
 	"net/http"
 	"net/http/pprof"
 	"net/url"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -39,8 +42,10 @@ import (
 	clientgometrics "k8s.io/client-go/tools/metrics"
 
 	monitoringthanosiov1alpha1 "github.com/thanos-community/thanos-operator/api/v1alpha1"
+	monitoringthanosiov1beta1 "github.com/thanos-community/thanos-operator/api/v1beta1"
 	"github.com/thanos-community/thanos-operator/internal/controller"
 	"github.com/thanos-community/thanos-operator/internal/pkg/featuregate"
+	"github.com/thanos-community/thanos-operator/internal/pkg/handlers"
 	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
 	manifestscompact "github.com/thanos-community/thanos-operator/internal/pkg/manifests/compact"
 	manifestquery "github.com/thanos-community/thanos-operator/internal/pkg/manifests/query"
@@ -48,20 +53,28 @@ import (
 	manifestruler "github.com/thanos-community/thanos-operator/internal/pkg/manifests/ruler"
 	manifestsstore "github.com/thanos-community/thanos-operator/internal/pkg/manifests/store"
 	"github.com/thanos-community/thanos-operator/internal/pkg/metrics"
+	"github.com/thanos-community/thanos-operator/internal/pkg/multicluster"
+	"github.com/thanos-community/thanos-operator/internal/pkg/operatorconfig"
+	webhookv1alpha1 "github.com/thanos-community/thanos-operator/internal/webhook/v1alpha1"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -74,8 +87,10 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(monitoringthanosiov1alpha1.AddToScheme(scheme))
+	utilruntime.Must(monitoringthanosiov1beta1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 	utilruntime.Must(monitoringv1.AddToScheme(scheme))
+	utilruntime.Must(gatewayv1.AddToScheme(scheme))
 }
 
 // registerClientGoMetrics registers client-go metrics adapters to expose
@@ -161,14 +176,48 @@ func (s *sizeAdapter) Observe(ctx context.Context, verb string, host string, siz
 	s.metric.WithLabelValues(verb, host).Observe(size)
 }
 
+const (
+	defaultKubeResourceSyncImage = "quay.io/philipgough/kube-resource-sync:0.1.0"
+	defaultConfigReloaderImage   = "quay.io/prometheus-operator/prometheus-config-reloader:v0.89.0"
+)
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		os.Exit(runValidate(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "fleet" {
+		os.Exit(runFleet(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "render" {
+		os.Exit(runRender(os.Args[2:]))
+	}
+
 	var metricsAddr string
 	var metricsCertPath, metricsCertName, metricsCertKey string
 	var metricsClientCAFile string
+	var metricsAuth string
 	var enableLeaderElection bool
+	var leaderElectionNamespace string
+	var leaseDuration, renewDeadline, retryPeriod time.Duration
+	var leaderElectionReleaseOnCancel bool
 	var probeAddr string
+	var pprofAddr string
+	var eventVerbosity controller.EventVerbosity
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var enableWebhooks bool
+	var controllerID string
+	var pruneGracePeriod time.Duration
+	var resyncInterval time.Duration
+	var maxConcurrentReconciles controller.ConcurrencyFlag
+	var workqueueConfig controller.WorkqueueConfig
+	var targetClusters multicluster.Flag
+	var mutationWebhookURL string
+	var mutationWebhookCAFile string
+	var mutationWebhookTimeout time.Duration
+	var nameTemplate string
+	var clusterDomain string
+	var configFile string
 
 	var enabledFeatures featuregate.Flag
 
@@ -177,9 +226,24 @@ func main() {
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&pprofAddr, "pprof-bind-address", "",
+		"The address the pprof endpoint binds to. If unset, pprof is served on the metrics endpoint under /debug/pprof/.")
+	flag.Var(&eventVerbosity, "event-verbosity",
+		"Which Kubernetes Events are emitted on the reconciled resources. One of: [normal, warning, none]. "+
+			"With warning, only the events reporting failures are emitted. (default normal)")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&leaderElectionNamespace, "leader-election-namespace", "",
+		"Namespace of the leader election lease. If unset, the namespace the operator runs in is used.")
+	flag.DurationVar(&leaseDuration, "leader-election-lease-duration", 15*time.Second,
+		"Duration non-leader candidates wait before forcing to acquire the leadership.")
+	flag.DurationVar(&renewDeadline, "leader-election-renew-deadline", 10*time.Second,
+		"Duration the leader retries refreshing the leadership before giving it up.")
+	flag.DurationVar(&retryPeriod, "leader-election-retry-period", 2*time.Second,
+		"Duration candidates wait between tries of acquiring or renewing the leadership.")
+	flag.BoolVar(&leaderElectionReleaseOnCancel, "leader-election-release-on-cancel", false,
+		"If set, the leader steps down when the operator stops, so that another replica takes over without waiting for the lease to expire.")
 	flag.BoolVar(&secureMetrics, "metrics-secure", false,
 		"If set the metrics endpoint is served securely")
 	flag.StringVar(&metricsCertPath, "metrics-cert-path", "",
@@ -187,15 +251,80 @@ func main() {
 	flag.StringVar(&metricsCertName, "metrics-cert-name", "tls.crt", "The name of the metrics server certificate file.")
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.StringVar(&metricsClientCAFile, "metrics-client-ca-file", "",
-		"The path to the client CA certificate file for mutual TLS authentication.")
+		"The path to the client CA certificate file for mutual TLS authentication. Requires --metrics-secure.")
+	flag.StringVar(&metricsAuth, "metrics-auth", metricsAuthToken,
+		fmt.Sprintf("How clients of the secure metrics endpoint are authenticated. One of: [%s]. ", strings.Join(metricsAuthModes, ", "))+
+			fmt.Sprintf("With %s, requests must carry a bearer token allowed to get /metrics. ", metricsAuthToken)+
+			fmt.Sprintf("With %s, only the client certificate verified against --metrics-client-ca-file is required.", metricsAuthClientCert))
 
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"If set, the validating admission webhooks and the conversion webhook of the v1beta1 API are served. "+
+			"The webhook server certificate must be mounted into /tmp/k8s-webhook-server/serving-certs.")
+	flag.StringVar(&controllerID, "controller-id", "",
+		"The ID of this operator instance. If set, only resources annotated with "+
+			fmt.Sprintf("%s=<controller-id> are reconciled. ", monitoringthanosiov1alpha1.ControllerIDAnnotation)+
+			"If unset, only resources without the annotation are reconciled.")
+	flag.DurationVar(&pruneGracePeriod, "prune-grace-period", 0,
+		"How long orphaned child objects are marked as pending deletion before they are deleted. "+
+			"If zero, orphaned child objects are deleted immediately.")
+	flag.DurationVar(&resyncInterval, "resync-interval", 10*time.Minute,
+		"Interval at which resources are reconciled again after a successful reconcile, with a jitter, "+
+			"to correct changes to their child objects that are not observed through watches. If zero, resources are only reconciled on changes.")
+	flag.Var(&maxConcurrentReconciles, "max-concurrent-reconciles",
+		"Number of resources reconciled concurrently, as [<controller>=]<n>, where the controller is one of thanos-compact, thanos-query, "+
+			"thanos-receive, thanos-ruler or thanos-store. Without a controller, it applies to all the controllers. Repeat for multiple controllers. (default 1)")
+	flag.DurationVar(&workqueueConfig.MinBackoff, "reconcile-min-backoff", controller.DefaultMinErrorBackoff,
+		"Delay before a resource that failed to reconcile is first retried. The delay doubles with each consecutive failure.")
+	flag.DurationVar(&workqueueConfig.MaxBackoff, "reconcile-max-backoff", controller.DefaultMaxErrorBackoff,
+		"Maximum delay before a resource that failed to reconcile is retried.")
+	flag.Float64Var(&workqueueConfig.QPS, "reconcile-qps", 0,
+		"Maximum rate per second at which each controller retries the resources that failed to reconcile. If zero, the rate is not limited.")
+	flag.IntVar(&workqueueConfig.Burst, "reconcile-burst", 100,
+		"Maximum burst of retries of the resources that failed to reconcile, when --reconcile-qps is set.")
+	flag.Var(&targetClusters, "target-cluster",
+		"Workload cluster that resources can select with spec.targetCluster, as <name>=<namespace>/<secret>. "+
+			fmt.Sprintf("The Secret must hold the kubeconfig of the cluster under the %q key. Repeat for multiple clusters.", multicluster.KubeconfigKey))
+	flag.StringVar(&mutationWebhookURL, "mutation-webhook-url", "",
+		"HTTPS URL of a webhook called with every generated object but Secrets before it is applied, to mutate it. "+
+			"If unset, generated objects are applied as built.")
+	flag.StringVar(&mutationWebhookCAFile, "mutation-webhook-ca-file", "",
+		"The path to the CA certificate file used to verify the mutation webhook. If unset, the system roots are used.")
+	flag.DurationVar(&mutationWebhookTimeout, "mutation-webhook-timeout", 10*time.Second,
+		"Timeout of the calls to the mutation webhook.")
+	flag.StringVar(&nameTemplate, "resource-name-template", manifests.DefaultNameTemplate,
+		fmt.Sprintf("Template of the names of the objects generated for the Thanos components, which must contain %s and %s. ", manifests.ComponentNamePlaceholder, manifests.OwnerNamePlaceholder)+
+			"Names longer than the Kubernetes limits are truncated and suffixed with a hash. Changing it renames all the generated objects.")
+	flag.StringVar(&clusterDomain, "cluster-domain", "",
+		"The DNS domain of the cluster, such as cluster.local, appended to the Service addresses generated for the Thanos components. "+
+			"If unset, the addresses end with .svc and are completed by the DNS search domains of the Pods.")
+	flag.StringVar(&configFile, "config-file", "",
+		"Path to a YAML configuration file of the operator, setting the log level, features, webhooks, resync interval, prune grace period and the defaults of the Thanos components. "+
+			"Flags set on the command line take precedence over the file. The file is reloaded when it changes, and the operator exits to be restarted when a setting other than the log levels and the defaults changes.")
 	flag.Var(&enabledFeatures, "enable-feature", fmt.Sprintf("Experimental feature to enable. Repeat for multiple features. Available features: %s.", strings.Join(featuregate.AllFeatures(), ", ")))
 	flag.StringVar(&logLevelStr, "log.level", "info", psflag.LevelFlagHelp)
 	flag.StringVar(&logFormatStr, "log.format", "logfmt", psflag.FormatFlagHelp)
 	flag.Parse()
 
+	var operatorConfig operatorconfig.Config
+	logLevelFlag := logLevelStr
+	set := setFlags()
+	if configFile != "" {
+		var err error
+		if operatorConfig, err = operatorconfig.Load(configFile); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		applyConfigFile(operatorConfig, fileSettings{
+			logLevel:         &logLevelStr,
+			enabledFeatures:  &enabledFeatures,
+			enableWebhooks:   &enableWebhooks,
+			resyncInterval:   &resyncInterval,
+			pruneGracePeriod: &pruneGracePeriod,
+		}, set)
+	}
+
 	logLevel := promslog.NewLevel()
 	if err := logLevel.Set(logLevelStr); err != nil {
 		setupLog.Error(err, "invalid log level")
@@ -219,6 +348,34 @@ func main() {
 		),
 	)
 	setupLog := ctrl.Log.WithName("setup")
+	if err := manifests.SetNameTemplate(nameTemplate); err != nil {
+		setupLog.Error(err, "invalid resource name template")
+		os.Exit(1)
+	}
+	if err := manifests.SetClusterDomain(clusterDomain); err != nil {
+		setupLog.Error(err, "invalid cluster domain")
+		os.Exit(1)
+	}
+	shard, err := controller.ShardFromEnv(os.LookupEnv)
+	if err != nil {
+		setupLog.Error(err, "invalid shard")
+		os.Exit(1)
+	}
+	if shard.Count > 1 {
+		setupLog.Info("reconciling a shard of the resources", "shard", shard.Index, "shards", shard.Count)
+	}
+	if workqueueConfig.MinBackoff > workqueueConfig.MaxBackoff {
+		setupLog.Error(fmt.Errorf("--reconcile-min-backoff must not be greater than --reconcile-max-backoff"), "invalid reconcile backoff")
+		os.Exit(1)
+	}
+	if err := validateMetricsAuth(metricsAuth, secureMetrics, metricsClientCAFile); err != nil {
+		setupLog.Error(err, "invalid metrics endpoint configuration")
+		os.Exit(1)
+	}
+	if err := validateMutationWebhookURL(mutationWebhookURL); err != nil {
+		setupLog.Error(err, "invalid mutation webhook configuration")
+		os.Exit(1)
+	}
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancelation and
@@ -266,13 +423,16 @@ func main() {
 		BindAddress:   metricsAddr,
 		SecureServing: secureMetrics,
 		TLSOpts:       tlsOpts,
-		ExtraHandlers: map[string]http.Handler{
+	}
+	// with a dedicated pprof endpoint, profiles are no longer served on the metrics endpoint
+	if pprofAddr == "" {
+		metricsServerOptions.ExtraHandlers = map[string]http.Handler{
 			"/debug/pprof/":        http.HandlerFunc(pprof.Index),
 			"/debug/pprof/cmdline": http.HandlerFunc(pprof.Cmdline),
 			"/debug/pprof/profile": http.HandlerFunc(pprof.Profile),
 			"/debug/pprof/symbol":  http.HandlerFunc(pprof.Symbol),
 			"/debug/pprof/trace":   http.HandlerFunc(pprof.Trace),
-		},
+		}
 	}
 
 	if len(metricsCertPath) > 0 {
@@ -282,9 +442,11 @@ func main() {
 		metricsServerOptions.CertDir = metricsCertPath
 		metricsServerOptions.CertName = metricsCertName
 		metricsServerOptions.KeyName = metricsCertKey
+	} else if secureMetrics {
+		setupLog.Info("serving metrics with a self-signed certificate generated at startup")
 	}
 
-	if secureMetrics {
+	if secureMetrics && metricsAuth == metricsAuthToken {
 		// FilterProvider is used to protect the metrics endpoint with authn/authz.
 		// These configurations ensure that only authorized users and service accounts
 		// can access the metrics endpoint. The RBAC are configured in 'config/rbac/kustomization.yaml'. More info:
@@ -297,19 +459,28 @@ func main() {
 		Metrics:                metricsServerOptions,
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
-		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "92ee6155.monitoring.thanos.io",
-		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
-		// when the Manager ends. This requires the binary to immediately end when the
-		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
-		// speeds up voluntary leader transitions as the new leader don't have to wait
-		// LeaseDuration time first.
-		//
-		// In the default scaffold provided, the program ends immediately after
-		// the manager stops, so would be fine to enable this option. However,
-		// if you are doing or is intended to do any operation such as perform cleanups
-		// after the manager stops then its usage might be unsafe.
-		// LeaderElectionReleaseOnCancel: true,
+		PprofBindAddress:       pprofAddr,
+		Cache: cache.Options{
+			// only the Pods and PersistentVolumeClaims of the workloads managed by the operator are cached,
+			// to report containers in CrashLoopBackOff and the placement of the ingester volumes
+			ByObject: map[client.Object]cache.ByObject{
+				&corev1.Pod{}:                   {Label: labels.SelectorFromSet(labels.Set{manifests.ManagedByLabel: manifests.DefaultManagedByLabel})},
+				&corev1.PersistentVolumeClaim{}: {Label: labels.SelectorFromSet(labels.Set{manifests.ManagedByLabel: manifests.DefaultManagedByLabel})},
+			},
+		},
+		// Secrets are read from the API server rather than cached, so that the operator does not hold the contents
+		// of every Secret in the cluster in memory. Only the metadata of Secrets is cached, to watch referenced Secrets.
+		Client: client.Options{
+			Cache: &client.CacheOptions{DisableFor: []client.Object{&corev1.Secret{}}},
+		},
+		LeaderElection:          enableLeaderElection,
+		LeaderElectionID:        leaderElectionID(controllerID, shard),
+		LeaderElectionNamespace: leaderElectionNamespace,
+		LeaseDuration:           &leaseDuration,
+		RenewDeadline:           &renewDeadline,
+		RetryPeriod:             &retryPeriod,
+		// stepping down when the manager stops is safe, since the program ends as soon as the manager stops
+		LeaderElectionReleaseOnCancel: leaderElectionReleaseOnCancel,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -329,11 +500,6 @@ func main() {
 	prometheus.DefaultRegisterer = ctrlmetrics.Registry
 	baseLogger := ctrl.Log.WithName(manifests.DefaultManagedByLabel)
 
-	const (
-		defaultKubeResourceSyncImage = "quay.io/philipgough/kube-resource-sync:0.1.0"
-		defaultConfigReloaderImage   = "quay.io/prometheus-operator/prometheus-config-reloader:v0.89.0"
-	)
-
 	commonMetrics := metrics.NewCommonMetrics(ctrlmetrics.Registry)
 	featureGateConfig := enabledFeatures.ToFeatureGate()
 	if featureGateConfig.ServiceMonitorEnabled() {
@@ -342,6 +508,9 @@ func main() {
 	if featureGateConfig.PrometheusRuleEnabled() {
 		commonMetrics.FeatureGatesInfo.WithLabelValues(featuregate.PrometheusRule).Set(1)
 	}
+	if featureGateConfig.GatewayAPIEnabled() {
+		commonMetrics.FeatureGatesInfo.WithLabelValues(featuregate.GatewayAPI).Set(1)
+	}
 	if featureGateConfig.KubeResourceSyncEnabled() {
 		featureGateConfig.KubeResourceSyncImage = defaultKubeResourceSyncImage
 		if image, ok := os.LookupEnv("KUBE_RESOURCE_SYNC_IMAGE"); ok {
@@ -355,12 +524,47 @@ func main() {
 		configReloaderImage = image
 	}
 
+	var clusters *multicluster.Clusters
+	if len(targetClusters) > 0 {
+		clusters = multicluster.NewClusters(mgr.GetAPIReader(), mgr.GetScheme(), targetClusters)
+		setupLog.Info("workload clusters registered", "clusters", targetClusters.String())
+	}
+
+	var mutators []handlers.ObjectMutator
+	if mutationWebhookURL != "" {
+		httpClient := &http.Client{Timeout: mutationWebhookTimeout}
+		if mutationWebhookCAFile != "" {
+			caCert, err := os.ReadFile(mutationWebhookCAFile)
+			if err != nil {
+				setupLog.Error(err, "unable to read mutation webhook CA file")
+				os.Exit(1)
+			}
+			caPool := x509.NewCertPool()
+			if !caPool.AppendCertsFromPEM(caCert) {
+				setupLog.Error(fmt.Errorf("failed to parse mutation webhook CA certificate"), "unable to add mutation webhook CA to pool")
+				os.Exit(1)
+			}
+			httpClient.Transport = &http.Transport{TLSClientConfig: &tls.Config{RootCAs: caPool, MinVersion: tls.VersionTLS12}}
+		}
+		mutators = append(mutators, handlers.NewWebhookMutator(mutationWebhookURL, httpClient, mgr.GetScheme()))
+		setupLog.Info("mutation webhook registered", "url", mutationWebhookURL)
+	}
+
 	buildConfig := func(component string) controller.Config {
+		workqueue := workqueueConfig
+		workqueue.MaxConcurrentReconciles = maxConcurrentReconciles.For(component)
 		return controller.Config{
-			FeatureGate: featureGateConfig,
+			ControllerID:     controllerID,
+			Shard:            shard,
+			FeatureGate:      featureGateConfig,
+			PruneGracePeriod: pruneGracePeriod,
+			ResyncInterval:   resyncInterval,
+			Workqueue:        workqueue,
+			TargetClusters:   clusters,
+			Mutators:         mutators,
 			InstrumentationConfig: controller.InstrumentationConfig{
 				Logger:          baseLogger.WithName(component),
-				EventRecorder:   mgr.GetEventRecorder(fmt.Sprintf("%s-controller", component)),
+				EventRecorder:   controller.NewEventRecorder(mgr.GetEventRecorder(fmt.Sprintf("%s-controller", component)), eventVerbosity),
 				MetricsRegistry: ctrlmetrics.Registry,
 				CommonMetrics:   commonMetrics,
 			},
@@ -413,6 +617,15 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = controller.NewThanosStackReconciler(
+		buildConfig("stack"),
+		mgr.GetClient(),
+		mgr.GetScheme(),
+	).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ThanosStack")
+		os.Exit(1)
+	}
+
 	if err = controller.NewObjectStatusReconciler(
 		buildConfig("object-status"),
 		mgr.GetClient(),
@@ -422,6 +635,17 @@ func main() {
 		os.Exit(1)
 	}
 
+	if enableWebhooks {
+		if err = webhookv1alpha1.SetupThanosReceiveWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ThanosReceive")
+			os.Exit(1)
+		}
+		if err = webhookv1alpha1.SetupThanosQueryWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ThanosQuery")
+			os.Exit(1)
+		}
+	}
+
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -432,10 +656,108 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	// the operator is only ready once its informers have synced, so that it does not act on a partial view of the cluster
+	if err := mgr.AddReadyzCheck("informers", cacheSyncCheck(mgr.GetCache())); err != nil {
+		setupLog.Error(err, "unable to set up informer ready check")
+		os.Exit(1)
+	}
+	if enableWebhooks {
+		if err := mgr.AddHealthzCheck("webhook", mgr.GetWebhookServer().StartedChecker()); err != nil {
+			setupLog.Error(err, "unable to set up webhook health check")
+			os.Exit(1)
+		}
+		if err := mgr.AddReadyzCheck("webhook", mgr.GetWebhookServer().StartedChecker()); err != nil {
+			setupLog.Error(err, "unable to set up webhook ready check")
+			os.Exit(1)
+		}
+	}
+
+	if configFile != "" {
+		reloader := operatorconfig.NewReloader(configFile, configReloadInterval, operatorConfig, func(conf operatorconfig.Config) {
+			if !set["log.level"] {
+				if err := logLevel.Set(cmp.Or(conf.LogLevel, logLevelFlag)); err != nil {
+					setupLog.Error(err, "invalid log level")
+				}
+			}
+			applyComponentDefaults(conf)
+		}, ctrl.Log.WithName("config"))
+		if err := mgr.Add(reloader); err != nil {
+			setupLog.Error(err, "unable to set up configuration file reloader")
+			os.Exit(1)
+		}
+	}
 
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+		if errors.Is(err, operatorconfig.ErrRestartRequired) {
+			os.Exit(0)
+		}
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// cacheSyncCheck returns a health check that fails until the informers of the cache have synced.
+func cacheSyncCheck(c cache.Cache) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), time.Second)
+		defer cancel()
+		if !c.WaitForCacheSync(ctx) {
+			return fmt.Errorf("informer caches are not synced")
+		}
+		return nil
+	}
+}
+
+// leaderElectionID returns the leader election lease name for the operator instance.
+// Instances with distinct controller IDs or shards must not compete for the same lease.
+func leaderElectionID(controllerID string, shard controller.Shard) string {
+	id := "92ee6155.monitoring.thanos.io"
+	if controllerID != "" {
+		id = controllerID + "." + id
+	}
+	if shard.Count > 1 {
+		id = fmt.Sprintf("shard-%d-of-%d.%s", shard.Index, shard.Count, id)
+	}
+	return id
+}
+
+const (
+	// metricsAuthToken authenticates and authorizes the clients of the metrics endpoint with their bearer token.
+	metricsAuthToken = "token"
+	// metricsAuthClientCert authenticates the clients of the metrics endpoint with their TLS certificate only.
+	metricsAuthClientCert = "client-cert"
+	// metricsAuthNone leaves the metrics endpoint unauthenticated, beyond an optional client certificate.
+	metricsAuthNone = "none"
+)
+
+var metricsAuthModes = []string{metricsAuthToken, metricsAuthClientCert, metricsAuthNone}
+
+// validateMetricsAuth validates the authentication mode of the metrics endpoint against the other metrics flags.
+func validateMetricsAuth(mode string, secure bool, clientCAFile string) error {
+	if !slices.Contains(metricsAuthModes, mode) {
+		return fmt.Errorf("unknown metrics authentication mode %q, must be one of: %s", mode, strings.Join(metricsAuthModes, ", "))
+	}
+	if !secure && (mode == metricsAuthClientCert || clientCAFile != "") {
+		return fmt.Errorf("client certificate authentication of the metrics endpoint requires --metrics-secure")
+	}
+	if mode == metricsAuthClientCert && clientCAFile == "" {
+		return fmt.Errorf("--metrics-auth=%s requires --metrics-client-ca-file", metricsAuthClientCert)
+	}
+	return nil
+}
+
+// validateMutationWebhookURL requires the mutation webhook, which receives the generated objects, to be called over HTTPS.
+func validateMutationWebhookURL(rawURL string) error {
+	if rawURL == "" {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid --mutation-webhook-url: %w", err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("--mutation-webhook-url must be an https URL, got %q", rawURL)
+	}
+	return nil
+}
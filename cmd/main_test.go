@@ -0,0 +1,43 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateMetricsAuth(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		mode      string
+		secure    bool
+		clientCA  string
+		expectErr string
+	}{
+		{name: "token", mode: metricsAuthToken, secure: true},
+		{name: "token insecure", mode: metricsAuthToken},
+		{name: "token with client CA", mode: metricsAuthToken, secure: true, clientCA: "ca.crt"},
+		{name: "token with client CA insecure", mode: metricsAuthToken, clientCA: "ca.crt", expectErr: "requires --metrics-secure"},
+		{name: "client cert", mode: metricsAuthClientCert, secure: true, clientCA: "ca.crt"},
+		{name: "client cert without client CA", mode: metricsAuthClientCert, secure: true, expectErr: "requires --metrics-client-ca-file"},
+		{name: "client cert insecure", mode: metricsAuthClientCert, clientCA: "ca.crt", expectErr: "requires --metrics-secure"},
+		{name: "client cert insecure without client CA", mode: metricsAuthClientCert, expectErr: "requires --metrics-secure"},
+		{name: "none", mode: metricsAuthNone, secure: true},
+		{name: "none insecure", mode: metricsAuthNone},
+		{name: "none with client CA", mode: metricsAuthNone, secure: true, clientCA: "ca.crt"},
+		{name: "none with client CA insecure", mode: metricsAuthNone, clientCA: "ca.crt", expectErr: "requires --metrics-secure"},
+		{name: "unknown mode", mode: "basic", secure: true, expectErr: "unknown metrics authentication mode"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateMetricsAuth(tc.mode, tc.secure, tc.clientCA)
+			if tc.expectErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.expectErr, err)
+			}
+		})
+	}
+}
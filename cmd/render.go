@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/thanos-community/thanos-operator/config/crd"
+	"github.com/thanos-community/thanos-operator/internal/controller"
+	"github.com/thanos-community/thanos-operator/internal/pkg/featuregate"
+	"github.com/thanos-community/thanos-operator/internal/pkg/validate"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// runRender implements the render subcommand, which prints the objects the operator generates for resources
+// without a cluster. It returns the exit code.
+func runRender(args []string) int {
+	fset := flag.NewFlagSet("render", flag.ContinueOnError)
+	var files fileFlag
+	var enabledFeatures featuregate.Flag
+	var namespace, configReloaderImage string
+	fset.Var(&files, "f", "File holding the resources to render, or - for stdin. Repeat for multiple files. "+
+		"Defaults to stdin. Secrets in the files are used to resolve the Secrets referenced by the resources.")
+	fset.StringVar(&namespace, "n", "default", "Namespace of the resources that do not set one.")
+	fset.Var(&enabledFeatures, "enable-feature", fmt.Sprintf("Experimental feature to render as enabled. Repeat for multiple features. Available features: %s.", strings.Join(featuregate.AllFeatures(), ", ")))
+	fset.StringVar(&configReloaderImage, "config-reloader-image", defaultConfigReloaderImage, "Image of the config reloader sidecar of the Thanos Ruler.")
+	fset.Usage = func() {
+		fmt.Fprintf(fset.Output(), "Usage: %s render [-f <file>...] [flags]\n", os.Args[0])
+		fset.PrintDefaults()
+	}
+	if err := fset.Parse(args); err != nil {
+		return 2
+	}
+	if len(files) == 0 {
+		files = fileFlag{"-"}
+	}
+
+	var decoded []client.Object
+	bases, err := fs.Sub(crd.Bases, "bases")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read CustomResourceDefinitions: %v\n", err)
+		return 2
+	}
+	validator, err := validate.NewValidator(bases, namespace, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load CustomResourceDefinitions: %v\n", err)
+		return 2
+	}
+	for _, file := range files {
+		objs, err := decodeFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read %s: %v\n", file, err)
+			return 2
+		}
+		results, err := validator.Validate(context.Background(), objs)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 2
+		}
+		invalid := false
+		for _, result := range results {
+			for _, e := range result.Errors {
+				fmt.Fprintf(os.Stderr, "%s: error: %s\n", result.Resource, e)
+				invalid = true
+			}
+		}
+		if invalid {
+			return 1
+		}
+
+		validator.Default(objs)
+		for _, obj := range objs {
+			typed, err := scheme.New(obj.GroupVersionKind())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s/%s: %v\n", obj.GetKind(), obj.GetName(), err)
+				return 1
+			}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, typed); err != nil {
+				fmt.Fprintf(os.Stderr, "%s/%s: %v\n", obj.GetKind(), obj.GetName(), err)
+				return 1
+			}
+			decoded = append(decoded, typed.(client.Object))
+		}
+	}
+
+	featureGateConfig := enabledFeatures.ToFeatureGate()
+	if featureGateConfig.KubeResourceSyncEnabled() {
+		featureGateConfig.KubeResourceSyncImage = defaultKubeResourceSyncImage
+	}
+	result, err := controller.Render(context.Background(), scheme, controller.RenderConfig{
+		FeatureGate:         featureGateConfig,
+		ConfigReloaderImage: configReloaderImage,
+	}, decoded)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to render: %v\n", err)
+		return 1
+	}
+
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+	}
+	for i, obj := range result.Objects {
+		b, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal %s/%s: %v\n", obj.GetKind(), obj.GetName(), err)
+			return 1
+		}
+		if i > 0 {
+			fmt.Println("---")
+		}
+		fmt.Print(string(b))
+	}
+	return 0
+}
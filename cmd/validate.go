@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/thanos-community/thanos-operator/config/crd"
+	"github.com/thanos-community/thanos-operator/internal/pkg/validate"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fileFlag is a repeatable flag collecting file names.
+type fileFlag []string
+
+func (f *fileFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *fileFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// runValidate implements the validate subcommand, which validates resources offline against the
+// schemas, CEL rules and admission webhooks of the operator. It returns the exit code.
+func runValidate(args []string) int {
+	fset := flag.NewFlagSet("validate", flag.ContinueOnError)
+	var files fileFlag
+	var kubeconfig, namespace string
+	fset.Var(&files, "f", "File holding the resources to validate, or - for stdin. Repeat for multiple files. "+
+		"Secrets in the files are used to resolve the Secrets referenced by the resources.")
+	fset.StringVar(&kubeconfig, "kubeconfig", "",
+		"Path to a kubeconfig used to resolve referenced Secrets that are not in the files. "+
+			"If unset, Secrets missing from the files are reported as warnings.")
+	fset.StringVar(&namespace, "n", "default", "Namespace of the resources that do not set one.")
+	fset.Usage = func() {
+		fmt.Fprintf(fset.Output(), "Usage: %s validate -f <file> [-f <file>...] [flags]\n", os.Args[0])
+		fset.PrintDefaults()
+	}
+	if err := fset.Parse(args); err != nil {
+		return 2
+	}
+	if len(files) == 0 {
+		fset.Usage()
+		return 2
+	}
+
+	var objs []*unstructured.Unstructured
+	for _, file := range files {
+		decoded, err := decodeFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read %s: %v\n", file, err)
+			return 2
+		}
+		objs = append(objs, decoded...)
+	}
+
+	var cluster client.Reader
+	if kubeconfig != "" {
+		cfg, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to load kubeconfig: %v\n", err)
+			return 2
+		}
+		c, err := client.New(cfg, client.Options{Scheme: scheme})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to create client: %v\n", err)
+			return 2
+		}
+		cluster = c
+	}
+
+	bases, err := fs.Sub(crd.Bases, "bases")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read CustomResourceDefinitions: %v\n", err)
+		return 2
+	}
+	validator, err := validate.NewValidator(bases, namespace, cluster)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load CustomResourceDefinitions: %v\n", err)
+		return 2
+	}
+	results, err := validator.Validate(context.Background(), objs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 2
+	}
+
+	code := 0
+	for _, result := range results {
+		for _, warning := range result.Warnings {
+			fmt.Printf("%s: warning: %s\n", result.Resource, warning)
+		}
+		for _, e := range result.Errors {
+			fmt.Printf("%s: error: %s\n", result.Resource, e)
+			code = 1
+		}
+		if len(result.Errors) == 0 {
+			fmt.Printf("%s: valid\n", result.Resource)
+		}
+	}
+	return code
+}
+
+func decodeFile(name string) ([]*unstructured.Unstructured, error) {
+	var r io.Reader = os.Stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = f.Close() }()
+		r = f
+	}
+	return validate.Decode(r)
+}
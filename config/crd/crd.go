@@ -0,0 +1,10 @@
+// Package crd embeds the CustomResourceDefinitions of the operator, so that resources can be validated
+// against their schemas without a cluster.
+package crd
+
+import "embed"
+
+// Bases holds the generated CustomResourceDefinitions.
+//
+//go:embed bases/*.yaml
+var Bases embed.FS
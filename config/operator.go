@@ -63,6 +63,24 @@ var CRDList = []CRD{
 		ShortName:   "thanosruler",
 		Description: "thanosrulers",
 	},
+	{
+		Kind:        "ThanosDefaults",
+		Plural:      "thanosdefaults",
+		ShortName:   "thanosdefaults",
+		Description: "thanosdefaults",
+	},
+	{
+		Kind:        "ThanosStack",
+		Plural:      "thanosstacks",
+		ShortName:   "thanosstack",
+		Description: "thanosstacks",
+	},
+	{
+		Kind:        "ThanosTenant",
+		Plural:      "thanostenants",
+		ShortName:   "thanostenant",
+		Description: "thanostenants",
+	},
 }
 
 var (
@@ -28,7 +28,7 @@ func SampleCR(crd CRD) any {
 						"some-label": "xyz",
 					},
 				},
-				Replicas: 1,
+				Replicas: ptr.To(int32(1)),
 				ReplicaLabels: []string{
 					"prometheus_replica",
 					"replica",
@@ -150,9 +150,14 @@ func SampleCR(crd CRD) any {
 					ExternalLabels: map[string]string{
 						"receive": "true",
 					},
-					Replicas:          3,
+					Replicas:          ptr.To(int32(3)),
 					ReplicationFactor: 1,
 				},
+				TenantSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"operator.thanos.io/receive": "example-receive",
+					},
+				},
 			},
 		}
 
@@ -238,6 +243,79 @@ func SampleCR(crd CRD) any {
 			},
 		}
 
+	case "ThanosDefaults":
+		return &thanosv1alpha1.ThanosDefaults{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: thanosv1alpha1.GroupVersion.String(),
+				Kind:       "ThanosDefaults",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "example-defaults",
+			},
+			Spec: thanosv1alpha1.ThanosDefaultsSpec{
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"operator.thanos.io/defaults": "example",
+					},
+				},
+				LogLevel: ptr.To("info"),
+				SecurityContext: &corev1.PodSecurityContext{
+					RunAsNonRoot: ptr.To(true),
+					FSGroup:      ptr.To(int64(1001)),
+				},
+			},
+		}
+
+	case "ThanosStack":
+		return &thanosv1alpha1.ThanosStack{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: thanosv1alpha1.GroupVersion.String(),
+				Kind:       "ThanosStack",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "example-stack",
+			},
+			Spec: thanosv1alpha1.ThanosStackSpec{
+				ObjectStorageConfig: thanosv1alpha1.ObjectStorageConfig{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: "thanos-object-storage",
+					},
+					Key: "thanos.yaml",
+				},
+				Receive: thanosv1alpha1.StackReceiveSpec{
+					IngesterReplicas: 3,
+					StorageConfiguration: &thanosv1alpha1.StorageConfiguration{
+						Size: "100Mi",
+					},
+				},
+				Ruler: thanosv1alpha1.StackRulerSpec{
+					AlertmanagerURL: "http://alertmanager.example.com:9093",
+				},
+			},
+		}
+
+	case "ThanosTenant":
+		return &thanosv1alpha1.ThanosTenant{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: thanosv1alpha1.GroupVersion.String(),
+				Kind:       "ThanosTenant",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "example-tenant",
+				Labels: map[string]string{
+					"operator.thanos.io/receive": "example-receive",
+				},
+			},
+			Spec: thanosv1alpha1.ThanosTenantSpec{
+				TenantID: "team-a",
+				Hashring: "blue",
+				Limits: &thanosv1alpha1.WriteLimits{
+					SeriesLimit:  ptr.To(int64(1000)),
+					SamplesLimit: ptr.To(int64(10000)),
+				},
+			},
+		}
+
 	default:
 		return nil
 	}
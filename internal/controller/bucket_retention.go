@@ -0,0 +1,332 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+	"github.com/thanos-community/thanos-operator/internal/pkg/objstore"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// rawDownsampleRange is the time range of raw blocks after which Thanos downsamples them to 5m blocks.
+	rawDownsampleRange = 40 * time.Hour
+	// fiveMinutesDownsampleRange is the time range of 5m blocks after which Thanos downsamples them to 1h blocks.
+	fiveMinutesDownsampleRange = 10 * 24 * time.Hour
+)
+
+// bucketCompactor is a ThanosCompact compacting a bucket, with the retention it applies to it.
+type bucketCompactor struct {
+	name      string
+	retention v1alpha1.RetentionResolutionConfig
+}
+
+// objectStorageBucket returns the identity of the bucket the object storage configuration points at, or an empty
+// string if the configuration cannot be read, such as when it is held in a target cluster.
+func objectStorageBucket(ctx context.Context, c client.Reader, namespace string, config v1alpha1.ObjectStorageConfig) string {
+	var data []byte
+	switch {
+	case config.Inline != nil:
+		data = []byte(*config.Inline)
+	case config.ConfigMap != nil:
+		cm := &corev1.ConfigMap{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: config.ConfigMap.Name}, cm); err != nil {
+			return ""
+		}
+		data = []byte(cm.Data[config.ConfigMap.Key])
+	default:
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: config.Name}, secret); err != nil {
+			return ""
+		}
+		data = secret.Data[config.Key]
+	}
+
+	conf, err := objstore.Parse(data)
+	if err != nil {
+		return ""
+	}
+	return conf.Bucket()
+}
+
+// retentionConfigOf returns the retention configuration of the ThanosCompact, taken from the resource named by its
+// retentionConfigFrom among compacts if set.
+func retentionConfigOf(compact v1alpha1.ThanosCompact, compacts []v1alpha1.ThanosCompact) v1alpha1.RetentionResolutionConfig {
+	if from := compact.Spec.RetentionConfigFrom; from != nil {
+		for _, source := range compacts {
+			if source.Namespace == compact.Namespace && source.Name == *from {
+				return source.Spec.RetentionConfig
+			}
+		}
+	}
+	return compact.Spec.RetentionConfig
+}
+
+// compactorsOfBucket returns the ThanosCompact resources in all namespaces, except the excluded one, whose object
+// storage configuration points at the bucket, sorted by namespace and name.
+func compactorsOfBucket(ctx context.Context, c client.Reader, bucket string, exclude types.UID) ([]bucketCompactor, error) {
+	list := &v1alpha1.ThanosCompactList{}
+	if err := c.List(ctx, list); err != nil {
+		return nil, fmt.Errorf("failed to list ThanosCompact resources: %w", err)
+	}
+
+	var compactors []bucketCompactor
+	for _, compact := range list.Items {
+		if compact.UID == exclude || compact.DeletionTimestamp != nil {
+			continue
+		}
+		if objectStorageBucket(ctx, c, compact.Namespace, compact.Spec.ObjectStorageConfig) != bucket {
+			continue
+		}
+		compactors = append(compactors, bucketCompactor{
+			name:      compact.Namespace + "/" + compact.Name,
+			retention: retentionConfigOf(compact, list.Items),
+		})
+	}
+	sort.Slice(compactors, func(i, j int) bool { return compactors[i].name < compactors[j].name })
+	return compactors, nil
+}
+
+// parseRetention returns the retention as a duration, 0 meaning that the blocks are kept forever.
+func parseRetention(d v1alpha1.Duration) time.Duration {
+	parsed, err := model.ParseDuration(string(d))
+	if err != nil {
+		return 0
+	}
+	return time.Duration(parsed)
+}
+
+// sameRetention returns true if the retention configurations keep the blocks of every resolution for as long.
+func sameRetention(a, b v1alpha1.RetentionResolutionConfig) bool {
+	return parseRetention(a.Raw) == parseRetention(b.Raw) &&
+		parseRetention(a.FiveMinutes) == parseRetention(b.FiveMinutes) &&
+		parseRetention(a.OneHour) == parseRetention(b.OneHour)
+}
+
+// compactorNames returns the names of the compactors, joined for a condition message.
+func compactorNames(compactors []bucketCompactor) string {
+	names := make([]string, 0, len(compactors))
+	for _, c := range compactors {
+		names = append(names, c.name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// compactRetentionCondition returns the BucketRetentionConsistent condition of a compactor applying the retention,
+// with downsampling enabled or not, given the other compactors of its bucket.
+// Thanos refuses to start when the retention of a resolution is shorter than the range after which it is downsampled.
+func compactRetentionCondition(retention v1alpha1.RetentionResolutionConfig, downsampling bool, others []bucketCompactor) metav1.Condition {
+	var conflicting []bucketCompactor
+	for _, other := range others {
+		if !sameRetention(retention, other.retention) {
+			conflicting = append(conflicting, other)
+		}
+	}
+	if len(conflicting) > 0 {
+		return metav1.Condition{
+			Type:    ConditionBucketRetentionConsistent,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonConflictingRetention,
+			Message: fmt.Sprintf("The retention differs from that of ThanosCompact %s, which compact the same bucket", compactorNames(conflicting)),
+		}
+	}
+
+	if downsampling {
+		if raw := parseRetention(retention.Raw); raw > 0 && raw < rawDownsampleRange {
+			return metav1.Condition{
+				Type:    ConditionBucketRetentionConsistent,
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonRetentionBelowDownsamplingRange,
+				Message: fmt.Sprintf("The raw retention %s is shorter than the 40h after which raw blocks are downsampled", retention.Raw),
+			}
+		}
+		if fiveMinutes := parseRetention(retention.FiveMinutes); fiveMinutes > 0 && fiveMinutes < fiveMinutesDownsampleRange {
+			return metav1.Condition{
+				Type:    ConditionBucketRetentionConsistent,
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonRetentionBelowDownsamplingRange,
+				Message: fmt.Sprintf("The 5m retention %s is shorter than the 10d after which 5m blocks are downsampled", retention.FiveMinutes),
+			}
+		}
+	}
+
+	message := "No other ThanosCompact compacts the same bucket"
+	if len(others) > 0 {
+		message = fmt.Sprintf("The retention is consistent with ThanosCompact %s, which compact the same bucket", compactorNames(others))
+	}
+	return metav1.Condition{
+		Type:    ConditionBucketRetentionConsistent,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonRetentionConsistent,
+		Message: message,
+	}
+}
+
+// receiveHashringRetention is the local retention of the ingesters of a hashring and the compactors of its bucket.
+type receiveHashringRetention struct {
+	hashring   string
+	retention  v1alpha1.Duration
+	compactors []bucketCompactor
+}
+
+// receiveRetentionCondition returns the BucketRetentionConsistent condition of a ThanosReceive given the compactors of
+// the bucket of each of its hashrings, or nil if none of the buckets is compacted.
+func receiveRetentionCondition(hashrings []receiveHashringRetention) *metav1.Condition {
+	var compacted []string
+	for _, h := range hashrings {
+		if len(h.compactors) == 0 {
+			continue
+		}
+		compacted = append(compacted, fmt.Sprintf("%s by %s", h.hashring, compactorNames(h.compactors)))
+
+		for _, other := range h.compactors[1:] {
+			if !sameRetention(h.compactors[0].retention, other.retention) {
+				return &metav1.Condition{
+					Type:    ConditionBucketRetentionConsistent,
+					Status:  metav1.ConditionFalse,
+					Reason:  ReasonConflictingRetention,
+					Message: fmt.Sprintf("ThanosCompact %s compact the bucket of hashring %s with different retentions", compactorNames(h.compactors), h.hashring),
+				}
+			}
+		}
+		compactor := h.compactors[0]
+		if raw := parseRetention(compactor.retention.Raw); raw > 0 && raw < parseRetention(h.retention) {
+			return &metav1.Condition{
+				Type:    ConditionBucketRetentionConsistent,
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonRawRetentionBelowLocalRetention,
+				Message: fmt.Sprintf("ThanosCompact %s deletes raw blocks after %s, before the ingesters of hashring %s stop serving them after %s", compactor.name, compactor.retention.Raw, h.hashring, h.retention),
+			}
+		}
+	}
+	if len(compacted) == 0 {
+		return nil
+	}
+	return &metav1.Condition{
+		Type:    ConditionBucketRetentionConsistent,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonRetentionConsistent,
+		Message: fmt.Sprintf("The buckets are compacted with a consistent retention, hashring %s", strings.Join(compacted, ", hashring ")),
+	}
+}
+
+// setBucketRetentionCondition sets the BucketRetentionConsistent condition of the owner, or removes it if nil.
+// A Warning event is emitted when the retention becomes inconsistent.
+func setBucketRetentionCondition(recorder events.EventRecorder, owner client.Object, conditions *[]metav1.Condition, condition *metav1.Condition) {
+	if condition == nil {
+		meta.RemoveStatusCondition(conditions, ConditionBucketRetentionConsistent)
+		return
+	}
+	if condition.Status == metav1.ConditionFalse && !meta.IsStatusConditionFalse(*conditions, ConditionBucketRetentionConsistent) {
+		recorder.Eventf(owner, nil, corev1.EventTypeWarning, "InconsistentBucketRetention", "Reconcile", "%s", condition.Message)
+	}
+	meta.SetStatusCondition(conditions, *condition)
+}
+
+// reportCompactBucketRetention sets the BucketRetentionConsistent condition of the ThanosCompact.
+// The condition is removed if the bucket of the compactor cannot be read.
+func reportCompactBucketRetention(ctx context.Context, c client.Reader, recorder events.EventRecorder, compact *v1alpha1.ThanosCompact) error {
+	bucket := objectStorageBucket(ctx, c, compact.Namespace, compact.Spec.ObjectStorageConfig)
+	if bucket == "" {
+		setBucketRetentionCondition(recorder, compact, &compact.Status.Conditions, nil)
+		return nil
+	}
+	others, err := compactorsOfBucket(ctx, c, bucket, compact.UID)
+	if err != nil {
+		return err
+	}
+	resolved := compact.DeepCopy()
+	if err := resolveRetentionConfig(ctx, c, resolved, &dependencies{}); err != nil {
+		return err
+	}
+	downsampling := compact.Spec.DownsamplingConfig == nil || !ptr.Deref(compact.Spec.DownsamplingConfig.Disable, false)
+	condition := compactRetentionCondition(resolved.Spec.RetentionConfig, downsampling, others)
+	setBucketRetentionCondition(recorder, compact, &compact.Status.Conditions, &condition)
+	return nil
+}
+
+// reportReceiveBucketRetention sets the BucketRetentionConsistent condition of the ThanosReceive from the compactors
+// of the buckets of its hashrings.
+func reportReceiveBucketRetention(ctx context.Context, c client.Reader, recorder events.EventRecorder, receiver *v1alpha1.ThanosReceive) error {
+	buckets := map[string][]bucketCompactor{}
+	var hashrings []receiveHashringRetention
+	for _, hashring := range receiver.Spec.Ingester.Hashrings {
+		config := receiver.Spec.Ingester.DefaultObjectStorageConfig
+		if hashring.ObjectStorageConfig != nil {
+			config = *hashring.ObjectStorageConfig
+		}
+		bucket := objectStorageBucket(ctx, c, receiver.Namespace, config)
+		if bucket == "" {
+			continue
+		}
+		compactors, ok := buckets[bucket]
+		if !ok {
+			var err error
+			if compactors, err = compactorsOfBucket(ctx, c, bucket, ""); err != nil {
+				return err
+			}
+			buckets[bucket] = compactors
+		}
+		hashrings = append(hashrings, receiveHashringRetention{
+			hashring:   hashring.Name,
+			retention:  hashring.TSDBConfig.Retention,
+			compactors: compactors,
+		})
+	}
+	setBucketRetentionCondition(recorder, receiver, &receiver.Status.Conditions, receiveRetentionCondition(hashrings))
+	return nil
+}
+
+// resolveRetentionConfig replaces the retention configuration of the ThanosCompact with that of the resource named by
+// its retentionConfigFrom, if set. A missing resource is recorded as a missing dependency.
+func resolveRetentionConfig(ctx context.Context, c client.Reader, compact *v1alpha1.ThanosCompact, deps *dependencies) error {
+	from := compact.Spec.RetentionConfigFrom
+	if from == nil {
+		return nil
+	}
+	source := &v1alpha1.ThanosCompact{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: compact.Namespace, Name: *from}, source); err != nil {
+		if apierrors.IsNotFound(err) {
+			deps.add("ThanosCompact/" + *from)
+			return nil
+		}
+		return fmt.Errorf("failed to get ThanosCompact %s in namespace %s: %w", *from, compact.Namespace, err)
+	}
+	compact.Spec.RetentionConfig = source.Spec.RetentionConfig
+	return nil
+}
+
+// enqueueForRetentionSource enqueues the ThanosCompact resources taking their retention configuration from the
+// changed ThanosCompact.
+func enqueueForRetentionSource(c client.Client) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+		list := &v1alpha1.ThanosCompactList{}
+		if err := c.List(ctx, list, client.InNamespace(obj.GetNamespace())); err != nil {
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, compact := range list.Items {
+			if ptr.Deref(compact.Spec.RetentionConfigFrom, "") == obj.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&compact)})
+			}
+		}
+		return requests
+	})
+}
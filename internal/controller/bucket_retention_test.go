@@ -0,0 +1,216 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func retentionConfig(raw, fiveMinutes, oneHour string) v1alpha1.RetentionResolutionConfig {
+	return v1alpha1.RetentionResolutionConfig{
+		Raw:         v1alpha1.Duration(raw),
+		FiveMinutes: v1alpha1.Duration(fiveMinutes),
+		OneHour:     v1alpha1.Duration(oneHour),
+	}
+}
+
+func TestCompactorsOfBucket(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	secret := func(ns, name, data string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+			Data:       map[string][]byte{"objstore.yaml": []byte(data)},
+		}
+	}
+	compact := func(ns, name string, uid types.UID, config v1alpha1.ObjectStorageConfig, retention v1alpha1.RetentionResolutionConfig, from *string) *v1alpha1.ThanosCompact {
+		return &v1alpha1.ThanosCompact{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name, UID: uid},
+			Spec: v1alpha1.ThanosCompactSpec{
+				ObjectStorageConfig: config,
+				RetentionConfig:     retention,
+				RetentionConfigFrom: from,
+			},
+		}
+	}
+	fromSecret := func(name string) v1alpha1.ObjectStorageConfig {
+		return v1alpha1.ObjectStorageConfig{LocalObjectReference: corev1.LocalObjectReference{Name: name}, Key: "objstore.yaml"}
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		secret("a", "objstore", "type: S3\nconfig:\n  bucket: thanos\n  endpoint: s3.example.com\n  access_key: a\n"),
+		secret("b", "objstore", "type: S3\nconfig:\n  bucket: thanos\n  endpoint: s3.example.com\n  access_key: b\n"),
+		secret("b", "other", "type: S3\nconfig:\n  bucket: other\n  endpoint: s3.example.com\n"),
+		compact("a", "tenant-a", "uid-a", fromSecret("objstore"), retentionConfig("30d", "90d", "1y"), nil),
+		compact("b", "tenant-b", "uid-b", fromSecret("objstore"), retentionConfig("0d", "0d", "0d"), ptr.To("source")),
+		compact("b", "source", "uid-source", fromSecret("other"), retentionConfig("14d", "30d", "1y"), nil),
+		compact("b", "inline", "uid-inline", v1alpha1.ObjectStorageConfig{Inline: ptr.To("type: S3\nconfig:\n  bucket: thanos\n  endpoint: s3.example.com\n")}, retentionConfig("30d", "90d", "1y"), nil),
+	).Build()
+
+	ctx := context.Background()
+	bucket := objectStorageBucket(ctx, c, "a", fromSecret("objstore"))
+	if bucket != "S3/s3.example.com/thanos" {
+		t.Fatalf("unexpected bucket %q", bucket)
+	}
+	if got := objectStorageBucket(ctx, c, "a", fromSecret("missing")); got != "" {
+		t.Errorf("expected no bucket for a missing secret, got %q", got)
+	}
+
+	compactors, err := compactorsOfBucket(ctx, c, bucket, "uid-a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := compactorNames(compactors); got != "b/inline, b/tenant-b" {
+		t.Fatalf("expected the other compactors of the bucket, got %q", got)
+	}
+	if got := compactors[1].retention; !sameRetention(got, retentionConfig("14d", "30d", "1y")) {
+		t.Errorf("expected the retention to be taken from retentionConfigFrom, got %+v", got)
+	}
+
+	tenantB := compact("b", "tenant-b", "uid-b", fromSecret("objstore"), retentionConfig("0d", "0d", "0d"), ptr.To("source"))
+	if err := resolveRetentionConfig(ctx, c, tenantB, &dependencies{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tenantB.Spec.RetentionConfig.Raw != "14d" {
+		t.Errorf("expected the retention of the source to be used, got %+v", tenantB.Spec.RetentionConfig)
+	}
+	deps := &dependencies{}
+	dangling := compact("b", "dangling", "uid-dangling", fromSecret("objstore"), retentionConfig("0d", "0d", "0d"), ptr.To("missing"))
+	if err := resolveRetentionConfig(ctx, c, dangling, deps); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !isMissingDependency(deps.err()) {
+		t.Errorf("expected the missing source to be a missing dependency, got %v", deps.err())
+	}
+}
+
+func TestCompactRetentionCondition(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		retention    v1alpha1.RetentionResolutionConfig
+		downsampling bool
+		others       []bucketCompactor
+		wantStatus   metav1.ConditionStatus
+		wantReason   string
+		wantMessage  string
+	}{
+		{
+			name:         "alone",
+			retention:    retentionConfig("30d", "90d", "0d"),
+			downsampling: true,
+			wantStatus:   metav1.ConditionTrue,
+			wantReason:   ReasonRetentionConsistent,
+			wantMessage:  "No other ThanosCompact",
+		},
+		{
+			name:        "same retention written differently",
+			retention:   retentionConfig("30d", "90d", "0d"),
+			others:      []bucketCompactor{{name: "ns/other", retention: retentionConfig("720h", "90d", "0s")}},
+			wantStatus:  metav1.ConditionTrue,
+			wantReason:  ReasonRetentionConsistent,
+			wantMessage: "consistent with ThanosCompact ns/other",
+		},
+		{
+			name:      "conflicting retention",
+			retention: retentionConfig("30d", "90d", "0d"),
+			others: []bucketCompactor{
+				{name: "ns/same", retention: retentionConfig("30d", "90d", "0d")},
+				{name: "ns/shorter", retention: retentionConfig("7d", "90d", "0d")},
+			},
+			wantStatus:  metav1.ConditionFalse,
+			wantReason:  ReasonConflictingRetention,
+			wantMessage: "that of ThanosCompact ns/shorter,",
+		},
+		{
+			name:         "raw retention below the downsampling range",
+			retention:    retentionConfig("1d", "0d", "0d"),
+			downsampling: true,
+			wantStatus:   metav1.ConditionFalse,
+			wantReason:   ReasonRetentionBelowDownsamplingRange,
+			wantMessage:  "raw retention 1d",
+		},
+		{
+			name:         "5m retention below the downsampling range",
+			retention:    retentionConfig("2d", "7d", "0d"),
+			downsampling: true,
+			wantStatus:   metav1.ConditionFalse,
+			wantReason:   ReasonRetentionBelowDownsamplingRange,
+			wantMessage:  "5m retention 7d",
+		},
+		{
+			name:       "short retention without downsampling",
+			retention:  retentionConfig("1d", "0d", "0d"),
+			wantStatus: metav1.ConditionTrue,
+			wantReason: ReasonRetentionConsistent,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := compactRetentionCondition(tc.retention, tc.downsampling, tc.others)
+			if got.Status != tc.wantStatus || got.Reason != tc.wantReason || !strings.Contains(got.Message, tc.wantMessage) {
+				t.Errorf("unexpected condition %+v", got)
+			}
+		})
+	}
+}
+
+func TestReceiveRetentionCondition(t *testing.T) {
+	month := bucketCompactor{name: "ns/month", retention: retentionConfig("30d", "0d", "0d")}
+	week := bucketCompactor{name: "ns/week", retention: retentionConfig("7d", "0d", "0d")}
+	halfDay := bucketCompactor{name: "ns/half-day", retention: retentionConfig("12h", "0d", "0d")}
+
+	if got := receiveRetentionCondition([]receiveHashringRetention{{hashring: "default", retention: "1d"}}); got != nil {
+		t.Errorf("expected no condition without compactors, got %+v", got)
+	}
+
+	for _, tc := range []struct {
+		name       string
+		hashrings  []receiveHashringRetention
+		wantStatus metav1.ConditionStatus
+		wantReason string
+	}{
+		{
+			name: "consistent",
+			hashrings: []receiveHashringRetention{
+				{hashring: "a", retention: "1d", compactors: []bucketCompactor{month}},
+				{hashring: "b", retention: "1d"},
+			},
+			wantStatus: metav1.ConditionTrue,
+			wantReason: ReasonRetentionConsistent,
+		},
+		{
+			name:       "conflicting compactors",
+			hashrings:  []receiveHashringRetention{{hashring: "a", retention: "1d", compactors: []bucketCompactor{month, week}}},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonConflictingRetention,
+		},
+		{
+			name:       "raw retention below the local retention",
+			hashrings:  []receiveHashringRetention{{hashring: "a", retention: "1d", compactors: []bucketCompactor{halfDay}}},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonRawRetentionBelowLocalRetention,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := receiveRetentionCondition(tc.hashrings)
+			if got == nil || got.Status != tc.wantStatus || got.Reason != tc.wantReason {
+				t.Errorf("unexpected condition %+v", got)
+			}
+		})
+	}
+}
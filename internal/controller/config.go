@@ -1,18 +1,41 @@
 package controller
 
 import (
+	"time"
+
 	"github.com/go-logr/logr"
 	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/client-go/tools/events"
 
 	"github.com/thanos-community/thanos-operator/internal/pkg/featuregate"
+	"github.com/thanos-community/thanos-operator/internal/pkg/handlers"
 	"github.com/thanos-community/thanos-operator/internal/pkg/metrics"
+	"github.com/thanos-community/thanos-operator/internal/pkg/multicluster"
 )
 
 // Config holds the configuration for all controllers.
 type Config struct {
+	// ControllerID is the ID of this operator instance.
+	// Only resources annotated with a matching v1alpha1.ControllerIDAnnotation are reconciled.
+	ControllerID string
+	// Shard is the share of the resources reconciled by this operator instance.
+	// Only resources assigned to the shard by the hash of their namespace and name are reconciled.
+	Shard Shard
 	// FeatureGate holds information about enabled features.
 	FeatureGate featuregate.Config
+	// PruneGracePeriod is the time orphaned child objects are marked as pending deletion before they are deleted.
+	// Zero deletes orphaned child objects immediately.
+	PruneGracePeriod time.Duration
+	// ResyncInterval is the interval at which resources are reconciled again after a successful reconcile,
+	// to correct the drift of child resources that is not observed through watches. Zero disables the resync.
+	ResyncInterval time.Duration
+	// Workqueue tunes how the requests of the controller are queued and processed.
+	Workqueue WorkqueueConfig
+	// TargetClusters resolves the workload clusters that resources can select to manage their child resources in.
+	// If nil, resources selecting a target cluster fail to reconcile.
+	TargetClusters *multicluster.Clusters
+	// Mutators are invoked with every generated object before it is applied.
+	Mutators []handlers.ObjectMutator
 	// InstrumentationConfig contains the common instrumentation configuration for all controllers.
 	InstrumentationConfig InstrumentationConfig
 }
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// Shard is the share of the resources reconciled by an operator instance, when several instances
+// split the reconciliation of the resources between them.
+type Shard struct {
+	// Index is the shard of this operator instance, from zero to Count-1.
+	Index int
+	// Count is the number of shards. Zero or one disables sharding.
+	Count int
+}
+
+const (
+	// ShardCountEnv is the environment variable holding the number of shards the resources are split into.
+	ShardCountEnv = "SHARD_COUNT"
+	// ShardIndexEnv is the environment variable holding the shard of the operator instance.
+	ShardIndexEnv = "SHARD_INDEX"
+)
+
+// ShardFromEnv returns the shard of the operator instance from the ShardCountEnv and ShardIndexEnv
+// environment variables, looked up with lookupEnv. Sharding is disabled if ShardCountEnv is unset.
+func ShardFromEnv(lookupEnv func(string) (string, bool)) (Shard, error) {
+	var shard Shard
+	count, ok := lookupEnv(ShardCountEnv)
+	if !ok || count == "" {
+		return shard, nil
+	}
+	var err error
+	if shard.Count, err = strconv.Atoi(count); err != nil {
+		return shard, fmt.Errorf("invalid %s %q: %w", ShardCountEnv, count, err)
+	}
+	if index, ok := lookupEnv(ShardIndexEnv); ok && index != "" {
+		if shard.Index, err = strconv.Atoi(index); err != nil {
+			return shard, fmt.Errorf("invalid %s %q: %w", ShardIndexEnv, index, err)
+		}
+	} else if shard.Count > 1 {
+		return shard, fmt.Errorf("%s must be set when %s is greater than one", ShardIndexEnv, ShardCountEnv)
+	}
+	return shard, shard.Validate()
+}
+
+// Validate returns an error if the index is out of the range of shards.
+func (s Shard) Validate() error {
+	if s.Count < 0 {
+		return fmt.Errorf("shard count must not be negative, got %d", s.Count)
+	}
+	if s.Count > 1 && (s.Index < 0 || s.Index >= s.Count) {
+		return fmt.Errorf("shard index must be between 0 and %d, got %d", s.Count-1, s.Index)
+	}
+	return nil
+}
+
+// owns returns true if the object is assigned to the shard.
+// Objects are assigned by the hash of their namespace and name modulo the shard count,
+// so that every shard reconciles a stable subset of the resources.
+func (s Shard) owns(obj client.Object) bool {
+	if s.Count <= 1 {
+		return true
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(obj.GetNamespace() + "/" + obj.GetName()))
+	return int(h.Sum32()%uint32(s.Count)) == s.Index
+}
+
+// controllerScope selects the resources reconciled by an operator instance.
+type controllerScope struct {
+	controllerID string
+	shard        Shard
+}
+
+func newControllerScope(conf Config) controllerScope {
+	return controllerScope{controllerID: conf.ControllerID, shard: conf.Shard}
+}
+
+// manages returns true if the given object is assigned to the operator instance, by controller ID and by shard.
+func (s controllerScope) manages(obj client.Object) bool {
+	return isManagedByController(obj, s.controllerID) && s.shard.owns(obj)
+}
+
+// predicate filters out events for objects that are assigned to another operator instance.
+func (s controllerScope) predicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(s.manages)
+}
+
+// isManagedByController returns true if the given object is assigned to the operator instance with the given ID.
+// Objects without the v1alpha1.ControllerIDAnnotation belong to the instance running without an ID.
+func isManagedByController(obj client.Object, controllerID string) bool {
+	return obj.GetAnnotations()[v1alpha1.ControllerIDAnnotation] == controllerID
+}
@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestShardFromEnv(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		env       map[string]string
+		expect    Shard
+		expectErr bool
+	}{
+		{name: "unset disables sharding"},
+		{name: "single shard without index", env: map[string]string{ShardCountEnv: "1"}, expect: Shard{Count: 1}},
+		{name: "shard", env: map[string]string{ShardCountEnv: "3", ShardIndexEnv: "2"}, expect: Shard{Index: 2, Count: 3}},
+		{name: "missing index", env: map[string]string{ShardCountEnv: "3"}, expectErr: true},
+		{name: "index out of range", env: map[string]string{ShardCountEnv: "3", ShardIndexEnv: "3"}, expectErr: true},
+		{name: "invalid count", env: map[string]string{ShardCountEnv: "three"}, expectErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			shard, err := ShardFromEnv(func(key string) (string, bool) {
+				v, ok := tc.env[key]
+				return v, ok
+			})
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("expected error %v, got %v", tc.expectErr, err)
+			}
+			if !tc.expectErr && shard != tc.expect {
+				t.Errorf("expected shard %+v, got %+v", tc.expect, shard)
+			}
+		})
+	}
+}
+
+func TestControllerScope(t *testing.T) {
+	const shards = 3
+	owners := make([]int, shards)
+	for i := range 30 {
+		obj := &v1alpha1.ThanosQuery{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("query-%d", i), Namespace: "ns"}}
+		var owned int
+		for index := range shards {
+			if (controllerScope{shard: Shard{Index: index, Count: shards}}).manages(obj) {
+				owners[index]++
+				owned++
+			}
+		}
+		if owned != 1 {
+			t.Errorf("expected %s to be managed by exactly one shard, got %d", obj.GetName(), owned)
+		}
+	}
+	for index, n := range owners {
+		if n == 0 {
+			t.Errorf("expected shard %d to manage some resources", index)
+		}
+	}
+
+	annotated := &v1alpha1.ThanosQuery{ObjectMeta: metav1.ObjectMeta{
+		Name:        "query",
+		Annotations: map[string]string{v1alpha1.ControllerIDAnnotation: "other"},
+	}}
+	if (controllerScope{}).manages(annotated) {
+		t.Error("expected resource assigned to another controller ID not to be managed")
+	}
+	if !(controllerScope{controllerID: "other"}).manages(annotated) {
+		t.Error("expected resource assigned to the controller ID to be managed")
+	}
+}
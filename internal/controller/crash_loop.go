@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// crashLoopBackOffReason is the reason of the waiting state of a container that keeps failing to start.
+	crashLoopBackOffReason = "CrashLoopBackOff"
+	// maxTerminationMessageLength bounds the length of the termination message recorded for a container.
+	maxTerminationMessageLength = 512
+)
+
+// crashLoopingContainers returns a message for each container of the given Pods that is in CrashLoopBackOff,
+// with the last line of the termination message of its previous run, such as a flag parse error.
+// Containers failing with the same message are reported once, so that a bad flag rolled out to every replica
+// of a workload does not repeat the same message for each Pod.
+func crashLoopingContainers(pods []corev1.Pod) []string {
+	type crash struct {
+		container string
+		message   string
+	}
+	failing := make(map[crash][]string)
+	for _, pod := range pods {
+		for _, status := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+			if status.State.Waiting == nil || status.State.Waiting.Reason != crashLoopBackOffReason {
+				continue
+			}
+			c := crash{container: status.Name, message: lastTerminationMessage(status)}
+			failing[c] = append(failing[c], pod.GetName())
+		}
+	}
+
+	messages := make([]string, 0, len(failing))
+	for c, podNames := range failing {
+		sort.Strings(podNames)
+		pod := "Pod/" + podNames[0]
+		if len(podNames) > 1 {
+			pod = fmt.Sprintf("%s and %d other Pods", pod, len(podNames)-1)
+		}
+		messages = append(messages, fmt.Sprintf("container %s of %s is in CrashLoopBackOff: %s", c.container, pod, c.message))
+	}
+	sort.Strings(messages)
+	return messages
+}
+
+// lastTerminationMessage returns the last line of the termination message of the previous run of the container.
+// Thanos containers fall back to their logs for the termination message, so this is the error that made it exit.
+func lastTerminationMessage(status corev1.ContainerStatus) string {
+	terminated := status.LastTerminationState.Terminated
+	if terminated == nil {
+		return "no termination recorded"
+	}
+
+	lines := strings.Split(strings.TrimSpace(terminated.Message), "\n")
+	if message := strings.TrimSpace(lines[len(lines)-1]); message != "" {
+		if utf8.RuneCountInString(message) > maxTerminationMessageLength {
+			message = string([]rune(message)[:maxTerminationMessageLength]) + "..."
+		}
+		return message
+	}
+	if terminated.Reason != "" {
+		return fmt.Sprintf("exited with code %d (%s)", terminated.ExitCode, terminated.Reason)
+	}
+	return fmt.Sprintf("exited with code %d", terminated.ExitCode)
+}
+
+// crashLoopCondition returns the CrashLooping condition for the messages returned by crashLoopingContainers.
+func crashLoopCondition(crashing []string) metav1.Condition {
+	if len(crashing) == 0 {
+		return metav1.Condition{
+			Type:    ConditionCrashLooping,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonNoContainersCrashLooping,
+			Message: "No containers are in CrashLoopBackOff",
+		}
+	}
+	return metav1.Condition{
+		Type:    ConditionCrashLooping,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonContainersCrashLooping,
+		Message: strings.Join(crashing, "; "),
+	}
+}
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCrashLoopingContainers(t *testing.T) {
+	crashing := func(name, message string, exitCode int32) corev1.ContainerStatus {
+		return corev1.ContainerStatus{
+			Name:  name,
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: crashLoopBackOffReason}},
+			LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				ExitCode: exitCode,
+				Reason:   "Error",
+				Message:  message,
+			}},
+		}
+	}
+	pod := func(name string, statuses ...corev1.ContainerStatus) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     corev1.PodStatus{ContainerStatuses: statuses},
+		}
+	}
+	running := corev1.ContainerStatus{Name: "thanos-receive", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}
+	flagError := "level=info msg=starting\nthanos: error: unknown long flag '--tsdb.too-new', try --help\n"
+
+	for _, tc := range []struct {
+		name   string
+		pods   []corev1.Pod
+		expect []string
+	}{
+		{
+			name: "no crash loop",
+			pods: []corev1.Pod{pod("ingester-0", running)},
+		},
+		{
+			name: "flag parse error",
+			pods: []corev1.Pod{pod("ingester-0", crashing("thanos-receive", flagError, 1))},
+			expect: []string{
+				"container thanos-receive of Pod/ingester-0 is in CrashLoopBackOff: thanos: error: unknown long flag '--tsdb.too-new', try --help",
+			},
+		},
+		{
+			name: "same error in every replica",
+			pods: []corev1.Pod{
+				pod("ingester-1", crashing("thanos-receive", flagError, 1)),
+				pod("ingester-0", crashing("thanos-receive", flagError, 1)),
+				pod("ingester-2", running),
+			},
+			expect: []string{
+				"container thanos-receive of Pod/ingester-0 and 1 other Pods is in CrashLoopBackOff: thanos: error: unknown long flag '--tsdb.too-new', try --help",
+			},
+		},
+		{
+			name: "no termination message",
+			pods: []corev1.Pod{pod("ingester-0", crashing("thanos-receive", "", 137))},
+			expect: []string{
+				"container thanos-receive of Pod/ingester-0 is in CrashLoopBackOff: exited with code 137 (Error)",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := crashLoopingContainers(tc.pods)
+			if !slices.Equal(got, tc.expect) {
+				t.Errorf("expected %q, got %q", tc.expect, got)
+			}
+		})
+	}
+}
+
+func TestLastTerminationMessageTruncated(t *testing.T) {
+	status := corev1.ContainerStatus{
+		LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			Message: strings.Repeat("é", 2*maxTerminationMessageLength),
+		}},
+	}
+	got := lastTerminationMessage(status)
+	if want := strings.Repeat("é", maxTerminationMessageLength) + "..."; got != want {
+		t.Errorf("expected message to be truncated to %d characters, got %d", maxTerminationMessageLength, len([]rune(got)))
+	}
+}
+
+func TestCrashLoopCondition(t *testing.T) {
+	condition := crashLoopCondition(nil)
+	if condition.Status != metav1.ConditionFalse || condition.Reason != ReasonNoContainersCrashLooping {
+		t.Errorf("expected no crash loop, got %+v", condition)
+	}
+
+	condition = crashLoopCondition([]string{"a", "b"})
+	if condition.Status != metav1.ConditionTrue || condition.Reason != ReasonContainersCrashLooping || condition.Message != "a; b" {
+		t.Errorf("expected crash loop, got %+v", condition)
+	}
+}
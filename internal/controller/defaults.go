@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+//+kubebuilder:rbac:groups=monitoring.thanos.io,resources=thanosdefaults,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// fleetDefaults resolves the ThanosDefaults inherited by Thanos resources.
+type fleetDefaults struct {
+	client client.Client
+}
+
+// apply sets the values of the ThanosDefaults that select the given namespace on the fields that are not set.
+// The fields are only changed in memory, so that the inherited values are not written back to the resource.
+func (d fleetDefaults) apply(ctx context.Context, namespace string, fields ...*v1alpha1.CommonFields) error {
+	defaults, err := d.forNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	if defaults == nil {
+		return nil
+	}
+
+	for _, f := range fields {
+		inheritDefaults(f, *defaults)
+	}
+	return nil
+}
+
+// forNamespace returns the merged ThanosDefaults that select the given namespace, or nil if none does.
+// ThanosDefaults are merged in the order of their names, so a later ThanosDefaults takes precedence
+// for the values it sets.
+func (d fleetDefaults) forNamespace(ctx context.Context, namespace string) (*v1alpha1.ThanosDefaultsSpec, error) {
+	list := &v1alpha1.ThanosDefaultsList{}
+	if err := d.client.List(ctx, list); err != nil {
+		return nil, fmt.Errorf("failed to list ThanosDefaults: %w", err)
+	}
+	if len(list.Items) == 0 {
+		return nil, nil
+	}
+	sort.Slice(list.Items, func(i, j int) bool {
+		return list.Items[i].Name < list.Items[j].Name
+	})
+
+	var nsLabels labels.Set
+	var merged *v1alpha1.ThanosDefaultsSpec
+	for _, defaults := range list.Items {
+		if defaults.Spec.NamespaceSelector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(defaults.Spec.NamespaceSelector)
+			if err != nil {
+				return nil, fmt.Errorf("invalid namespace selector in ThanosDefaults %s: %w", defaults.Name, err)
+			}
+			if nsLabels == nil {
+				ns := &corev1.Namespace{}
+				if err := d.client.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+					return nil, fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+				}
+				nsLabels = labels.Set(ns.GetLabels())
+			}
+			if !selector.Matches(nsLabels) {
+				continue
+			}
+		}
+
+		if merged == nil {
+			merged = &v1alpha1.ThanosDefaultsSpec{}
+		}
+		mergeDefaults(merged, defaults.Spec)
+	}
+	return merged, nil
+}
+
+// mergeDefaults overrides the values of dst with the values set in src.
+func mergeDefaults(dst *v1alpha1.ThanosDefaultsSpec, src v1alpha1.ThanosDefaultsSpec) {
+	if src.Version != nil {
+		dst.Version = src.Version
+	}
+	if src.Image != nil {
+		dst.Image = src.Image
+	}
+	if src.ResourceRequirements != nil {
+		dst.ResourceRequirements = src.ResourceRequirements
+	}
+	if src.LogLevel != nil {
+		dst.LogLevel = src.LogLevel
+	}
+	if src.SecurityContext != nil {
+		dst.SecurityContext = src.SecurityContext
+	}
+	if src.ContainerSecurityContext != nil {
+		dst.ContainerSecurityContext = src.ContainerSecurityContext
+	}
+	if src.PriorityClassName != nil {
+		dst.PriorityClassName = src.PriorityClassName
+	}
+	if src.TracingConfig != nil {
+		dst.TracingConfig = src.TracingConfig
+	}
+}
+
+// inheritDefaults sets the values of the defaults on the fields that are not set.
+func inheritDefaults(fields *v1alpha1.CommonFields, defaults v1alpha1.ThanosDefaultsSpec) {
+	if fields.Version == nil {
+		fields.Version = defaults.Version
+	}
+	if fields.Image == nil {
+		fields.Image = defaults.Image
+	}
+	if fields.ResourceRequirements == nil {
+		fields.ResourceRequirements = defaults.ResourceRequirements
+	}
+	if fields.LogLevel == nil {
+		fields.LogLevel = defaults.LogLevel
+	}
+	if fields.SecurityContext == nil {
+		fields.SecurityContext = defaults.SecurityContext
+	}
+	if fields.ContainerSecurityContext == nil {
+		fields.ContainerSecurityContext = defaults.ContainerSecurityContext
+	}
+	if fields.PriorityClassName == nil {
+		fields.PriorityClassName = defaults.PriorityClassName
+	}
+	if fields.TracingConfig == nil {
+		fields.TracingConfig = defaults.TracingConfig
+	}
+}
+
+// queryCommonFields returns the fields of the ThanosQuery that inherit ThanosDefaults.
+func queryCommonFields(query *v1alpha1.ThanosQuery) []*v1alpha1.CommonFields {
+	fields := []*v1alpha1.CommonFields{&query.Spec.CommonFields}
+	if query.Spec.QueryFrontend != nil {
+		fields = append(fields, &query.Spec.QueryFrontend.CommonFields)
+	}
+	return fields
+}
+
+// receiveCommonFields returns the fields of the ThanosReceive that inherit ThanosDefaults.
+func receiveCommonFields(receiver *v1alpha1.ThanosReceive) []*v1alpha1.CommonFields {
+	fields := []*v1alpha1.CommonFields{&receiver.Spec.Router.CommonFields}
+	for i := range receiver.Spec.Ingester.Hashrings {
+		fields = append(fields, &receiver.Spec.Ingester.Hashrings[i].CommonFields)
+	}
+	return fields
+}
+
+// enqueueForDefaults enqueues requests for all resources of the given list type when a ThanosDefaults changes,
+// since the resources a ThanosDefaults applies to depend on the labels of their namespace.
+func enqueueForDefaults(c client.Client, newList func() client.ObjectList) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, _ client.Object) []reconcile.Request {
+		list := newList()
+		if err := c.List(ctx, list); err != nil {
+			return nil
+		}
+		objs, err := meta.ExtractList(list)
+		if err != nil {
+			return nil
+		}
+
+		requests := make([]reconcile.Request, 0, len(objs))
+		for _, obj := range objs {
+			o, ok := obj.(client.Object)
+			if !ok {
+				continue
+			}
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(o)})
+		}
+		return requests
+	})
+}
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestFleetDefaultsApply(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	objs := []client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"team": "a"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}},
+		&v1alpha1.ThanosDefaults{
+			ObjectMeta: metav1.ObjectMeta{Name: "00-cluster"},
+			Spec: v1alpha1.ThanosDefaultsSpec{
+				Image:             ptr.To("registry.example.com/thanos"),
+				LogLevel:          ptr.To("warn"),
+				PriorityClassName: ptr.To("observability"),
+			},
+		},
+		&v1alpha1.ThanosDefaults{
+			ObjectMeta: metav1.ObjectMeta{Name: "10-team-a"},
+			Spec: v1alpha1.ThanosDefaultsSpec{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+				LogLevel:          ptr.To("debug"),
+				Version:           ptr.To("v0.40.0"),
+			},
+		},
+	}
+	d := fleetDefaults{client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()}
+
+	for _, tc := range []struct {
+		name      string
+		namespace string
+		fields    v1alpha1.CommonFields
+		expect    v1alpha1.CommonFields
+	}{
+		{
+			name:      "cluster defaults",
+			namespace: "team-b",
+			expect: v1alpha1.CommonFields{
+				Image:             ptr.To("registry.example.com/thanos"),
+				LogLevel:          ptr.To("warn"),
+				PriorityClassName: ptr.To("observability"),
+			},
+		},
+		{
+			name:      "namespace defaults take precedence",
+			namespace: "team-a",
+			expect: v1alpha1.CommonFields{
+				Image:             ptr.To("registry.example.com/thanos"),
+				LogLevel:          ptr.To("debug"),
+				Version:           ptr.To("v0.40.0"),
+				PriorityClassName: ptr.To("observability"),
+			},
+		},
+		{
+			name:      "resource values are not overridden",
+			namespace: "team-a",
+			fields:    v1alpha1.CommonFields{LogLevel: ptr.To("error"), PriorityClassName: ptr.To("critical")},
+			expect: v1alpha1.CommonFields{
+				Image:             ptr.To("registry.example.com/thanos"),
+				LogLevel:          ptr.To("error"),
+				Version:           ptr.To("v0.40.0"),
+				PriorityClassName: ptr.To("critical"),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fields := tc.fields
+			if err := d.apply(context.Background(), tc.namespace, &fields); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ptr.Deref(fields.Image, "") != ptr.Deref(tc.expect.Image, "") {
+				t.Errorf("expected image %q, got %q", ptr.Deref(tc.expect.Image, ""), ptr.Deref(fields.Image, ""))
+			}
+			if ptr.Deref(fields.LogLevel, "") != ptr.Deref(tc.expect.LogLevel, "") {
+				t.Errorf("expected log level %q, got %q", ptr.Deref(tc.expect.LogLevel, ""), ptr.Deref(fields.LogLevel, ""))
+			}
+			if ptr.Deref(fields.Version, "") != ptr.Deref(tc.expect.Version, "") {
+				t.Errorf("expected version %q, got %q", ptr.Deref(tc.expect.Version, ""), ptr.Deref(fields.Version, ""))
+			}
+			if ptr.Deref(fields.PriorityClassName, "") != ptr.Deref(tc.expect.PriorityClassName, "") {
+				t.Errorf("expected priority class %q, got %q", ptr.Deref(tc.expect.PriorityClassName, ""), ptr.Deref(fields.PriorityClassName, ""))
+			}
+		})
+	}
+}
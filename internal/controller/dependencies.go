@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// minDependencyBackoff is the delay before the first requeue of a resource waiting on a missing dependency.
+	minDependencyBackoff = 5 * time.Second
+	// maxDependencyBackoff caps the delay between requeues of a resource waiting on a missing dependency.
+	maxDependencyBackoff = 5 * time.Minute
+)
+
+// missingDependencyError is returned when objects referenced by a resource do not exist yet.
+// The generated resources are still applied, so that the workloads converge as soon as the
+// dependencies are created.
+type missingDependencyError struct {
+	dependencies []string
+}
+
+func (e *missingDependencyError) Error() string {
+	return fmt.Sprintf("waiting for missing dependencies: %s", strings.Join(e.dependencies, ", "))
+}
+
+// isMissingDependency returns true if the error is caused by missing dependencies only.
+func isMissingDependency(err error) bool {
+	var missing *missingDependencyError
+	return errors.As(err, &missing)
+}
+
+// dependencies collects the objects referenced by a resource that do not exist.
+type dependencies struct {
+	missing []string
+}
+
+// add records a missing dependency, described in a human readable way.
+func (d *dependencies) add(dep string) {
+	if !slices.Contains(d.missing, dep) {
+		d.missing = append(d.missing, dep)
+	}
+}
+
+// requireSecrets records the Secrets with the given names that do not exist in the namespace.
+// It returns an error if a Secret cannot be read for any other reason.
+func (d *dependencies) requireSecrets(ctx context.Context, c client.Reader, namespace string, names ...string) error {
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &corev1.Secret{})
+		if apierrors.IsNotFound(err) {
+			d.add("Secret/" + name)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get secret %s in namespace %s: %w", name, namespace, err)
+		}
+	}
+	return nil
+}
+
+// err returns a *missingDependencyError if any dependency is missing, nil otherwise.
+func (d *dependencies) err() error {
+	if len(d.missing) == 0 {
+		return nil
+	}
+	return &missingDependencyError{dependencies: d.missing}
+}
+
+// dependencyBackoff computes exponential requeue delays for resources waiting on missing dependencies.
+// Watches on the dependencies wake the resources up as soon as they are created,
+// the requeue is only a safety net for objects that are not watched.
+type dependencyBackoff struct {
+	mu       sync.Mutex
+	attempts map[types.NamespacedName]int
+}
+
+func newDependencyBackoff() *dependencyBackoff {
+	return &dependencyBackoff{attempts: make(map[types.NamespacedName]int)}
+}
+
+// next returns the delay before the next requeue of the given resource and records the attempt.
+func (b *dependencyBackoff) next(key types.NamespacedName) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delay := minDependencyBackoff
+	for i := 0; i < b.attempts[key] && delay < maxDependencyBackoff; i++ {
+		delay *= 2
+	}
+	b.attempts[key]++
+	return min(delay, maxDependencyBackoff)
+}
+
+// reset forgets the attempts recorded for the given resource.
+func (b *dependencyBackoff) reset(key types.NamespacedName) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.attempts, key)
+}
+
+// enqueueForReferencedSecret returns an event handler that enqueues the resources in the Secret's namespace
+// that reference it. newList returns an empty list of the resource kind, and referencedSecrets returns the
+// names of the Secrets referenced by a resource.
+func enqueueForReferencedSecret(c client.Client, logger logr.Logger, newList func() client.ObjectList, referencedSecrets func(obj client.Object) []string) handler.EventHandler {
+	return enqueueForReferencedObject(c, logger, "secret", newList, referencedSecrets)
+}
+
+// enqueueForReferencedConfigMap returns an event handler that enqueues the resources in the ConfigMap's namespace
+// that reference it. newList returns an empty list of the resource kind, and referencedConfigMaps returns the
+// names of the ConfigMaps referenced by a resource.
+func enqueueForReferencedConfigMap(c client.Client, logger logr.Logger, newList func() client.ObjectList, referencedConfigMaps func(obj client.Object) []string) handler.EventHandler {
+	return enqueueForReferencedObject(c, logger, "configmap", newList, referencedConfigMaps)
+}
+
+// enqueueForReferencedObject returns an event handler that enqueues the resources in the object's namespace
+// whose referenced names, of the given kind, include the object's name.
+func enqueueForReferencedObject(c client.Client, logger logr.Logger, kind string, newList func() client.ObjectList, referenced func(obj client.Object) []string) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+		list := newList()
+		if err := c.List(ctx, list, client.InNamespace(obj.GetNamespace())); err != nil {
+			logger.Error(err, "failed to list resources for "+kind, kind, obj.GetName())
+			return nil
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			logger.Error(err, "failed to extract list items")
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for _, item := range items {
+			o, ok := item.(client.Object)
+			if !ok || !slices.Contains(referenced(o), obj.GetName()) {
+				continue
+			}
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: o.GetNamespace(),
+					Name:      o.GetName(),
+				},
+			})
+		}
+		return requests
+	})
+}
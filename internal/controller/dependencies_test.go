@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDependencyBackoff(t *testing.T) {
+	b := newDependencyBackoff()
+	key := types.NamespacedName{Namespace: "ns", Name: "test"}
+	other := types.NamespacedName{Namespace: "ns", Name: "other"}
+
+	for i, expect := range []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 40 * time.Second} {
+		if got := b.next(key); got != expect {
+			t.Errorf("attempt %d: expected %s, got %s", i, expect, got)
+		}
+	}
+
+	if got := b.next(other); got != minDependencyBackoff {
+		t.Errorf("expected attempts to be tracked per resource, got %s", got)
+	}
+
+	for range 20 {
+		b.next(key)
+	}
+	if got := b.next(key); got != maxDependencyBackoff {
+		t.Errorf("expected backoff to be capped at %s, got %s", maxDependencyBackoff, got)
+	}
+
+	b.reset(key)
+	if got := b.next(key); got != minDependencyBackoff {
+		t.Errorf("expected backoff to restart after reset, got %s", got)
+	}
+}
+
+func TestDependencies(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewFakeClient(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "exists", Namespace: "ns"}})
+
+	deps := &dependencies{}
+	if err := deps.requireSecrets(ctx, c, "ns", "exists", "", "missing", "missing"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	deps.add("StoreAPI Service")
+
+	err := deps.err()
+	if !isMissingDependency(err) {
+		t.Fatalf("expected a missing dependency error, got %v", err)
+	}
+	if !isMissingDependency(fmt.Errorf("wrapped: %w", err)) {
+		t.Error("expected wrapped error to be detected as a missing dependency")
+	}
+
+	expect := "waiting for missing dependencies: Secret/missing, StoreAPI Service"
+	if err.Error() != expect {
+		t.Errorf("expected %q, got %q", expect, err.Error())
+	}
+
+	if err := (&dependencies{}).err(); err != nil {
+		t.Errorf("expected no error without missing dependencies, got %v", err)
+	}
+}
@@ -0,0 +1,245 @@
+package controller
+
+import (
+	"cmp"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+	manifestquery "github.com/thanos-community/thanos-operator/internal/pkg/manifests/query"
+	manifestreceive "github.com/thanos-community/thanos-operator/internal/pkg/manifests/receive"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// endpointHealthCheckInterval is the interval between two health checks of the endpoints of a resource.
+	endpointHealthCheckInterval = time.Minute
+	// endpointHealthCheckTimeout bounds the health check of an endpoint, so that an unresponsive endpoint
+	// does not block reconciles.
+	endpointHealthCheckTimeout = 5 * time.Second
+)
+
+// healthTarget is a gRPC endpoint whose health is checked by the operator.
+type healthTarget struct {
+	name    string
+	address string
+	// tls is true if the endpoint serves gRPC over TLS.
+	tls bool
+}
+
+// queryHealthTargets returns the health targets of the StoreAPI endpoints discovered for a Querier,
+// which are checked through their Service.
+func queryHealthTargets(endpoints []manifestquery.Endpoint) []healthTarget {
+	targets := make([]healthTarget, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		targets = append(targets, healthTarget{
+			name:    endpoint.ServiceName,
+			address: net.JoinHostPort(manifests.ServiceHost(endpoint.ServiceName, endpoint.Namespace), strconv.Itoa(int(endpoint.Port))),
+			tls:     endpoint.TLS,
+		})
+	}
+	return targets
+}
+
+// ingesterHealthTargets returns the health targets of the running ingesters of each hashring, keyed by hashring name,
+// which are checked by Pod IP on the gRPC port of their hashring.
+func ingesterHealthTargets(ctx context.Context, c client.Client, receiver v1alpha1.ThanosReceive) (map[string][]healthTarget, error) {
+	useTLS := receiver.Spec.GRPCTLS != nil
+	targets := make(map[string][]healthTarget, len(receiver.Spec.Ingester.Hashrings))
+	for _, hashring := range receiver.Spec.Ingester.Hashrings {
+		port := cmp.Or(endpointAddressOptions(receiver.Spec.Router, hashring).GRPCPort, manifestreceive.GRPCPort)
+		opts := manifestreceive.IngesterOptions{
+			Options:      manifests.Options{Owner: receiver.GetName()},
+			HashringName: hashring.Name,
+		}
+		pods := &corev1.PodList{}
+		if err := c.List(ctx, pods, client.MatchingLabels(manifestreceive.GetIngesterLabels(opts)), client.InNamespace(receiver.GetNamespace())); err != nil {
+			return nil, fmt.Errorf("failed to list the ingesters of hashring %s: %w", hashring.Name, err)
+		}
+		slices.SortFunc(pods.Items, func(a, b corev1.Pod) int {
+			return strings.Compare(a.GetName(), b.GetName())
+		})
+		for _, pod := range pods.Items {
+			if pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" || pod.GetDeletionTimestamp() != nil {
+				continue
+			}
+			targets[hashring.Name] = append(targets[hashring.Name], healthTarget{
+				name:    pod.GetName(),
+				address: net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(port))),
+				tls:     useTLS,
+			})
+		}
+	}
+	return targets, nil
+}
+
+// healthCheckCertificate returns the client certificate and key held in the tls.crt and tls.key keys of the given Secret,
+// which the operator presents to the endpoints that require client certificates, or nil if no Secret is given.
+func healthCheckCertificate(ctx context.Context, c client.Reader, namespace, name string) (*tls.Certificate, error) {
+	if name == "" {
+		return nil, nil
+	}
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get client certificate secret %s: %w", name, err)
+	}
+	cert, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil, fmt.Errorf("invalid client certificate in secret %s: %w", name, err)
+	}
+	return &cert, nil
+}
+
+// endpointHealthChecksDue returns true if the health of the targets must be checked, because the last check
+// is older than the check interval or the targets changed since.
+func endpointHealthChecksDue(statuses []v1alpha1.EndpointHealthStatus, targets []healthTarget, now time.Time) bool {
+	if len(statuses) != len(targets) {
+		return true
+	}
+	for i, target := range targets {
+		status := statuses[i]
+		if status.Name != target.name || status.Address != target.address || !now.Before(status.LastCheckTime.Add(endpointHealthCheckInterval)) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextEndpointHealthCheck returns the time until the next health check of the endpoints is due,
+// or zero if there are no endpoints to check.
+func nextEndpointHealthCheck(statuses []v1alpha1.EndpointHealthStatus, now time.Time) time.Duration {
+	if len(statuses) == 0 {
+		return 0
+	}
+	return max(statuses[0].LastCheckTime.Add(endpointHealthCheckInterval).Sub(now), time.Second)
+}
+
+// ingesterHealthChecksDue returns true if the health of the ingesters must be checked, because the last check
+// is older than the check interval or the ingesters of a hashring changed since.
+func ingesterHealthChecksDue(health map[string][]v1alpha1.EndpointHealthStatus, targets map[string][]healthTarget, now time.Time) bool {
+	if len(health) != len(targets) {
+		return true
+	}
+	for hashring, hashringTargets := range targets {
+		if endpointHealthChecksDue(health[hashring], hashringTargets, now) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextIngesterHealthCheck returns the time until the next health check of the ingesters is due,
+// or zero if there are no ingesters to check.
+func nextIngesterHealthCheck(health map[string][]v1alpha1.EndpointHealthStatus, now time.Time) time.Duration {
+	for _, statuses := range health {
+		if len(statuses) > 0 {
+			return nextEndpointHealthCheck(statuses, now)
+		}
+	}
+	return 0
+}
+
+// checkEndpointHealth checks the health of each target concurrently and returns it, in the order of the targets.
+// The client certificate, if any, is presented to the targets serving TLS.
+func checkEndpointHealth(ctx context.Context, targets []healthTarget, cert *tls.Certificate, now time.Time) []v1alpha1.EndpointHealthStatus {
+	statuses := make([]v1alpha1.EndpointHealthStatus, len(targets))
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Go(func() {
+			status := v1alpha1.EndpointHealthStatus{
+				Name:          target.name,
+				Address:       target.address,
+				LastCheckTime: metav1.NewTime(now),
+			}
+			if err := checkHealth(ctx, target, cert); err != nil {
+				status.Message = err.Error()
+			} else {
+				status.Healthy = true
+			}
+			statuses[i] = status
+		})
+	}
+	wg.Wait()
+	return statuses
+}
+
+// checkHealth calls the standard gRPC health service of the target, and returns an error if it is not serving.
+// Server certificates are not verified, as the check is about the health of the endpoint rather than its identity.
+// An endpoint that does not implement the health service is considered healthy, as it answered the call.
+func checkHealth(ctx context.Context, target healthTarget, cert *tls.Certificate) error {
+	creds := insecure.NewCredentials()
+	if target.tls {
+		config := &tls.Config{InsecureSkipVerify: true} //nolint:gosec // only the health of the endpoint is checked
+		if cert != nil {
+			config.Certificates = []tls.Certificate{*cert}
+		}
+		creds = credentials.NewTLS(config)
+	}
+	conn, err := grpc.NewClient(target.address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	ctx, cancel := context.WithTimeout(ctx, endpointHealthCheckTimeout)
+	defer cancel()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if status.Code(err) == codes.Unimplemented {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("endpoint is %s", resp.GetStatus())
+	}
+	return nil
+}
+
+// unhealthyEndpoints returns the names of the endpoints that were unhealthy at the last check.
+func unhealthyEndpoints(statuses []v1alpha1.EndpointHealthStatus) []string {
+	var unhealthy []string
+	for _, status := range statuses {
+		if !status.Healthy {
+			unhealthy = append(unhealthy, status.Name)
+		}
+	}
+	return unhealthy
+}
+
+// endpointHealthCondition returns the EndpointsHealthy condition for the given unhealthy endpoints.
+func endpointHealthCondition(unhealthy []string) metav1.Condition {
+	if len(unhealthy) > 0 {
+		return metav1.Condition{
+			Type:    ConditionEndpointsHealthy,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonEndpointsUnhealthy,
+			Message: fmt.Sprintf("Endpoints not serving: %s", strings.Join(unhealthy, ", ")),
+		}
+	}
+	return metav1.Condition{
+		Type:    ConditionEndpointsHealthy,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonEndpointsHealthy,
+		Message: "All endpoints are serving",
+	}
+}
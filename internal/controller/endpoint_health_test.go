@@ -0,0 +1,285 @@
+package controller
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+	manifestreceive "github.com/thanos-community/thanos-operator/internal/pkg/manifests/receive"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// startHealthServer starts a gRPC server serving the standard health service with the given status,
+// and returns its address.
+func startHealthServer(t *testing.T, status healthpb.HealthCheckResponse_ServingStatus) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", status)
+	healthpb.RegisterHealthServer(srv, healthServer)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String()
+}
+
+func TestCheckEndpointHealth(t *testing.T) {
+	serving := startHealthServer(t, healthpb.HealthCheckResponse_SERVING)
+	notServing := startHealthServer(t, healthpb.HealthCheckResponse_NOT_SERVING)
+
+	// a gRPC server without the health service answers the call, so it is considered healthy
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	// a closed port refuses the connection
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	refused := closed.Addr().String()
+	_ = closed.Close()
+
+	now := time.Now()
+	statuses := checkEndpointHealth(context.Background(), []healthTarget{
+		{name: "serving", address: serving},
+		{name: "not-serving", address: notServing},
+		{name: "unimplemented", address: lis.Addr().String()},
+		{name: "refused", address: refused},
+	}, nil, now)
+
+	for i, want := range []struct {
+		name    string
+		healthy bool
+	}{
+		{"serving", true},
+		{"not-serving", false},
+		{"unimplemented", true},
+		{"refused", false},
+	} {
+		got := statuses[i]
+		if got.Name != want.name || got.Healthy != want.healthy || !got.LastCheckTime.Equal(&metav1.Time{Time: now}) {
+			t.Errorf("unexpected status %+v, want %s healthy=%t", got, want.name, want.healthy)
+		}
+		if !got.Healthy && got.Message == "" {
+			t.Errorf("expected a message for unhealthy endpoint %s", got.Name)
+		}
+	}
+
+	condition := endpointHealthCondition(unhealthyEndpoints(statuses))
+	if condition.Status != metav1.ConditionFalse || condition.Reason != ReasonEndpointsUnhealthy || condition.Message != "Endpoints not serving: not-serving, refused" {
+		t.Errorf("unexpected condition %+v", condition)
+	}
+	if condition := endpointHealthCondition(nil); condition.Status != metav1.ConditionTrue || condition.Reason != ReasonEndpointsHealthy {
+		t.Errorf("unexpected condition %+v", condition)
+	}
+}
+
+// newCertificate returns a certificate and key for localhost in PEM, signed by the parent, or self-signed if nil.
+func newCertificate(t *testing.T, parent *tls.Certificate) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+	}
+	issuer, signer := template, any(key)
+	if parent != nil {
+		if issuer, err = x509.ParseCertificate(parent.Certificate[0]); err != nil {
+			t.Fatal(err)
+		}
+		signer = parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestCheckEndpointHealthClientCertificate(t *testing.T) {
+	caPEM, caKeyPEM := newCertificate(t, nil)
+	ca, err := tls.X509KeyPair(caPEM, caKeyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := tls.X509KeyPair(newCertificate(t, &ca))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(caPEM)
+
+	// a server requiring client certificates signed by the CA, as ingesters with a client CA do
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{server},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})))
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(srv, healthServer)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	certPEM, keyPEM := newCertificate(t, &ca)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "client"},
+		Data:       map[string][]byte{corev1.TLSCertKey: certPEM, corev1.TLSPrivateKeyKey: keyPEM},
+	}).Build()
+
+	ctx := context.Background()
+	cert, err := healthCheckCertificate(ctx, c, "ns", "client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := healthCheckCertificate(ctx, c, "ns", "missing"); err == nil {
+		t.Error("expected an error for a missing secret")
+	}
+	if cert, err := healthCheckCertificate(ctx, c, "ns", ""); cert != nil || err != nil {
+		t.Errorf("expected no certificate without a secret, got %v, %v", cert, err)
+	}
+
+	targets := []healthTarget{{name: "mtls", address: lis.Addr().String(), tls: true}}
+	if statuses := checkEndpointHealth(ctx, targets, nil, time.Now()); statuses[0].Healthy {
+		t.Error("expected the check to fail without a client certificate")
+	}
+	if statuses := checkEndpointHealth(ctx, targets, cert, time.Now()); !statuses[0].Healthy {
+		t.Errorf("expected the check to succeed with a client certificate, got %q", statuses[0].Message)
+	}
+}
+
+func TestIngesterHealthTargets(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	receiver := v1alpha1.ThanosReceive{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "receive"},
+		Spec: v1alpha1.ThanosReceiveSpec{
+			Ingester: v1alpha1.IngesterSpec{
+				Hashrings: []v1alpha1.IngesterHashringSpec{
+					{Name: "default"},
+					{Name: "custom", EndpointAddress: &v1alpha1.EndpointAddressConfig{GRPCPort: ptr.To[int32](11901)}},
+				},
+			},
+		},
+	}
+	pod := func(hashring, name, ip string) *corev1.Pod {
+		opts := manifestreceive.IngesterOptions{Options: manifests.Options{Owner: receiver.GetName()}, HashringName: hashring}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, Labels: manifestreceive.GetIngesterLabels(opts)},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: ip},
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		pod("default", "default-0", "10.0.0.1"),
+		pod("custom", "custom-0", "10.0.0.2"),
+	).Build()
+
+	targets, err := ingesterHealthTargets(context.Background(), c, receiver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string][]healthTarget{
+		"default": {{name: "default-0", address: "10.0.0.1:10901"}},
+		"custom":  {{name: "custom-0", address: "10.0.0.2:11901"}},
+	}
+	if !reflect.DeepEqual(targets, expect) {
+		t.Errorf("expected targets %+v, got %+v", expect, targets)
+	}
+}
+
+func TestEndpointHealthChecksDue(t *testing.T) {
+	now := time.Now()
+	targets := []healthTarget{{name: "a", address: "10.0.0.1:10901"}, {name: "b", address: "10.0.0.2:10901"}}
+	checked := func(at time.Time, targets ...healthTarget) []v1alpha1.EndpointHealthStatus {
+		statuses := make([]v1alpha1.EndpointHealthStatus, 0, len(targets))
+		for _, target := range targets {
+			statuses = append(statuses, v1alpha1.EndpointHealthStatus{Name: target.name, Address: target.address, LastCheckTime: metav1.NewTime(at)})
+		}
+		return statuses
+	}
+
+	if !endpointHealthChecksDue(nil, targets, now) {
+		t.Error("expected a check to be due without a previous check")
+	}
+	if endpointHealthChecksDue(checked(now.Add(-30*time.Second), targets...), targets, now) {
+		t.Error("expected no check to be due within the interval")
+	}
+	if !endpointHealthChecksDue(checked(now.Add(-endpointHealthCheckInterval), targets...), targets, now) {
+		t.Error("expected a check to be due once the interval has passed")
+	}
+	moved := []healthTarget{targets[0], {name: "b", address: "10.0.0.3:10901"}}
+	if !endpointHealthChecksDue(checked(now, targets...), moved, now) {
+		t.Error("expected a check to be due when an endpoint changed")
+	}
+
+	if got := nextEndpointHealthCheck(checked(now.Add(-20*time.Second), targets...), now); got != 40*time.Second {
+		t.Errorf("expected the next check in 40s, got %s", got)
+	}
+	if got := nextEndpointHealthCheck(nil, now); got != 0 {
+		t.Errorf("expected no next check without endpoints, got %s", got)
+	}
+
+	health := map[string][]v1alpha1.EndpointHealthStatus{"default": checked(now, targets...)}
+	if ingesterHealthChecksDue(health, map[string][]healthTarget{"default": targets}, now) {
+		t.Error("expected no ingester check to be due within the interval")
+	}
+	if !ingesterHealthChecksDue(health, map[string][]healthTarget{"default": targets, "other": targets[:1]}, now) {
+		t.Error("expected an ingester check to be due when a hashring is added")
+	}
+}
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+)
+
+// EventVerbosity controls which Kubernetes Events the controllers emit on the resources they reconcile.
+// It implements flag.Value.
+type EventVerbosity string
+
+const (
+	// EventVerbosityNormal emits all the events, including the Normal events reporting progress.
+	EventVerbosityNormal EventVerbosity = "normal"
+	// EventVerbosityWarning only emits the Warning events reporting failures.
+	EventVerbosityWarning EventVerbosity = "warning"
+	// EventVerbosityNone emits no events.
+	EventVerbosityNone EventVerbosity = "none"
+)
+
+var eventVerbosities = []string{string(EventVerbosityNormal), string(EventVerbosityWarning), string(EventVerbosityNone)}
+
+// String returns the verbosity, which defaults to EventVerbosityNormal.
+func (v *EventVerbosity) String() string {
+	if *v == "" {
+		return string(EventVerbosityNormal)
+	}
+	return string(*v)
+}
+
+// Set records the verbosity after validating the flag value.
+func (v *EventVerbosity) Set(value string) error {
+	if !slices.Contains(eventVerbosities, value) {
+		return fmt.Errorf("invalid event verbosity %q, must be one of: %s", value, strings.Join(eventVerbosities, ", "))
+	}
+	*v = EventVerbosity(value)
+	return nil
+}
+
+// NewEventRecorder returns an EventRecorder that drops the events below the given verbosity.
+func NewEventRecorder(recorder events.EventRecorder, verbosity EventVerbosity) events.EventRecorder {
+	return &verbosityRecorder{recorder: recorder, verbosity: verbosity}
+}
+
+type verbosityRecorder struct {
+	recorder  events.EventRecorder
+	verbosity EventVerbosity
+}
+
+func (r *verbosityRecorder) Eventf(regarding runtime.Object, related runtime.Object, eventtype, reason, action, note string, args ...interface{}) {
+	switch r.verbosity {
+	case EventVerbosityNone:
+		return
+	case EventVerbosityWarning:
+		if eventtype != corev1.EventTypeWarning {
+			return
+		}
+	}
+	r.recorder.Eventf(regarding, related, eventtype, reason, action, note, args...)
+}
@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/events"
+)
+
+func TestEventVerbosity(t *testing.T) {
+	for _, tc := range []struct {
+		verbosity EventVerbosity
+		expect    int
+	}{
+		{verbosity: "", expect: 2},
+		{verbosity: EventVerbosityNormal, expect: 2},
+		{verbosity: EventVerbosityWarning, expect: 1},
+		{verbosity: EventVerbosityNone, expect: 0},
+	} {
+		t.Run(tc.verbosity.String(), func(t *testing.T) {
+			fake := events.NewFakeRecorder(10)
+			recorder := NewEventRecorder(fake, tc.verbosity)
+			recorder.Eventf(&v1alpha1.ThanosQuery{}, nil, corev1.EventTypeNormal, "Paused", "Reconcile", "Reconciliation is paused")
+			recorder.Eventf(&v1alpha1.ThanosQuery{}, nil, corev1.EventTypeWarning, "SyncFailed", "Reconcile", "Failed to sync resources")
+			if got := len(fake.Events); got != tc.expect {
+				t.Errorf("expected %d events, got %d", tc.expect, got)
+			}
+		})
+	}
+
+	var verbosity EventVerbosity
+	if err := verbosity.Set("debug"); err == nil {
+		t.Error("expected an error for an unknown verbosity")
+	}
+	if err := verbosity.Set("warning"); err != nil || verbosity != EventVerbosityWarning {
+		t.Errorf("expected verbosity to be set to warning, got %q and error %v", verbosity, err)
+	}
+}
@@ -137,7 +137,7 @@ config:
 				Spec: monitoringthanosiov1alpha1.ThanosQuerySpec{
 					CommonFields:  monitoringthanosiov1alpha1.CommonFields{},
 					ReplicaLabels: []string{"replica"},
-					Replicas:      2,
+					Replicas:      ptr.To(int32(2)),
 				},
 			}
 			Expect(k8sClient.Create(context.Background(), resource)).Should(Succeed())
@@ -155,7 +155,7 @@ config:
 			})
 
 			By("removing PDB when scaled to 1", func() {
-				resource.Spec.Replicas = 1
+				resource.Spec.Replicas = ptr.To(int32(1))
 				Expect(k8sClient.Update(context.Background(), resource)).Should(Succeed())
 				Eventually(func() bool {
 					return utils.VerifyPodDisruptionBudgetExists(k8sClient, name, ns)
@@ -184,7 +184,7 @@ config:
 							Labels: map[string]string{"test": "my-router-test"},
 						},
 						ReplicationFactor: 1,
-						Replicas:          2,
+						Replicas:          ptr.To(int32(2)),
 					},
 					Ingester: monitoringthanosiov1alpha1.IngesterSpec{
 						DefaultObjectStorageConfig: monitoringthanosiov1alpha1.ObjectStorageConfig{
@@ -230,7 +230,7 @@ config:
 			})
 
 			By("removing PDB when scaled to 1", func() {
-				resource.Spec.Router.Replicas = 1
+				resource.Spec.Router.Replicas = ptr.To(int32(1))
 				Expect(k8sClient.Update(context.Background(), resource)).Should(Succeed())
 				for i := range resource.Spec.Ingester.Hashrings {
 					resource.Spec.Ingester.Hashrings[i].Replicas = 1
@@ -0,0 +1,29 @@
+package controller
+
+import (
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// getUnusedIngresses returns the Ingress and HTTPRoute named after a component that are not generated for the
+// given configuration, so that they are deleted when the ingress is removed or its type is changed.
+// The HTTPRoute carries its kind, so that it is skipped by the handler when the gateway-api feature is disabled.
+func getUnusedIngresses(name, namespace string, ingress *v1alpha1.IngressConfig) []client.Object {
+	meta := metav1.ObjectMeta{Name: name, Namespace: namespace}
+	var objs []client.Object
+	if ingress == nil || ingress.Type == v1alpha1.IngressTypeHTTPRoute {
+		objs = append(objs, &networkingv1.Ingress{ObjectMeta: meta})
+	}
+	if ingress == nil || ingress.Type != v1alpha1.IngressTypeHTTPRoute {
+		objs = append(objs, &gatewayv1.HTTPRoute{
+			TypeMeta:   metav1.TypeMeta{Kind: "HTTPRoute", APIVersion: gatewayv1.GroupVersion.String()},
+			ObjectMeta: meta,
+		})
+	}
+	return objs
+}
@@ -0,0 +1,21 @@
+package controller
+
+import (
+	manifestsmemcached "github.com/thanos-community/thanos-operator/internal/pkg/manifests/memcached"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// getUnmanagedCache returns the objects of the memcached managed for the component named parent,
+// which should be deleted when the cache is no longer managed by the operator.
+func getUnmanagedCache(parent, namespace string) []client.Object {
+	name := manifestsmemcached.Options{Parent: parent}.GetGeneratedResourceName()
+	return []client.Object{
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}},
+	}
+}
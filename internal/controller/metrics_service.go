@@ -0,0 +1,35 @@
+package controller
+
+import (
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// withMetricsServices returns the given resource names along with the names of their dedicated metrics Services.
+// It is used to build the list of resources to keep when pruning, disabled metrics Services are deleted
+// by getDisabledMetricsServices.
+func withMetricsServices(names []string) []string {
+	keep := make([]string, 0, 2*len(names))
+	for _, name := range names {
+		keep = append(keep, name, manifests.MetricsServiceName(name))
+	}
+	return keep
+}
+
+// getDisabledMetricsServices returns the dedicated metrics Services that should be deleted
+// for the given resource names when the metrics Service is disabled.
+func getDisabledMetricsServices(enabled bool, names []string, namespace string) []client.Object {
+	if enabled {
+		return nil
+	}
+
+	objs := make([]client.Object, 0, len(names))
+	for _, name := range names {
+		objs = append(objs, &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: manifests.MetricsServiceName(name), Namespace: namespace}})
+	}
+	return objs
+}
@@ -18,11 +18,15 @@ package controller
 
 import (
 	"context"
+	"slices"
 	"time"
 
 	"github.com/go-logr/logr"
 
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/events"
@@ -38,20 +42,72 @@ import (
 	storebldr "github.com/thanos-community/thanos-operator/internal/pkg/manifests/store"
 
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 // Define condition types and reasons
 const (
-	ConditionReconcileSuccess = "ReconcileSuccess"
-	ConditionReconcileFailed  = "ReconcileFailed"
-	ConditionPaused           = "Paused"
-
-	ReasonReconcileComplete = "ReconcileComplete"
-	ReasonReconcileError    = "ReconcileError"
-	ReasonPaused            = "Paused"
+	ConditionReconcileSuccess          = "ReconcileSuccess"
+	ConditionReconcileFailed           = "ReconcileFailed"
+	ConditionPaused                    = "Paused"
+	ConditionDependencyMissing         = "DependencyMissing"
+	ConditionReplicationDegraded       = "ReplicationDegraded"
+	ConditionAvailable                 = "Available"
+	ConditionProgressing               = "Progressing"
+	ConditionDegraded                  = "Degraded"
+	ConditionWriteProbeSucceeded       = "WriteProbeSucceeded"
+	ConditionReadProbeSucceeded        = "ReadProbeSucceeded"
+	ConditionReady                     = "Ready"
+	ConditionReconciling               = "Reconciling"
+	ConditionStalled                   = "Stalled"
+	ConditionCrashLooping              = "CrashLooping"
+	ConditionVolumeZonesDegraded       = "VolumeZonesDegraded"
+	ConditionUploadLagDegraded         = "UploadLagDegraded"
+	ConditionVolumeResizeBlocked       = "VolumeResizeBlocked"
+	ConditionObjectStorageVerified     = "ObjectStorageVerified"
+	ConditionRemotesReachable          = "RemotesReachable"
+	ConditionEndpointsHealthy          = "EndpointsHealthy"
+	ConditionBucketRetentionConsistent = "BucketRetentionConsistent"
+
+	ReasonReconcileComplete                   = "ReconcileComplete"
+	ReasonReconcileError                      = "ReconcileError"
+	ReasonPaused                              = "Paused"
+	ReasonDependencyNotFound                  = "DependencyNotFound"
+	ReasonReplicationHealthy                  = "ReplicationHealthy"
+	ReasonReadyReplicasBelowReplicationFactor = "ReadyReplicasBelowReplicationFactor"
+	ReasonMinimumReplicasAvailable            = "MinimumReplicasAvailable"
+	ReasonReplicasUnavailable                 = "ReplicasUnavailable"
+	ReasonRollingOut                          = "RollingOut"
+	ReasonRolloutComplete                     = "RolloutComplete"
+	ReasonReplicasNotReady                    = "ReplicasNotReady"
+	ReasonAllReplicasReady                    = "AllReplicasReady"
+	ReasonProbeSucceeded                      = "ProbeSucceeded"
+	ReasonProbeFailed                         = "ProbeFailed"
+	ReasonProbePending                        = "ProbePending"
+	ReasonContainersCrashLooping              = "ContainersCrashLooping"
+	ReasonNoContainersCrashLooping            = "NoContainersCrashLooping"
+	ReasonVolumesSpreadAcrossZones            = "VolumesSpreadAcrossZones"
+	ReasonVolumesInTooFewZones                = "VolumesInTooFewZones"
+	ReasonUploadLagWithinThreshold            = "UploadLagWithinThreshold"
+	ReasonUploadLagAboveThreshold             = "UploadLagAboveThreshold"
+	ReasonVolumeSizesApplied                  = "VolumeSizesApplied"
+	ReasonVolumeResizeUnsupported             = "VolumeResizeUnsupported"
+	ReasonComponentsReady                     = "ComponentsReady"
+	ReasonComponentsNotReady                  = "ComponentsNotReady"
+	ReasonInvalidObjectStorageConfig          = "InvalidObjectStorageConfig"
+	ReasonRemotesReachable                    = "RemotesReachable"
+	ReasonRemotesUnreachable                  = "RemotesUnreachable"
+	ReasonEndpointsHealthy                    = "EndpointsHealthy"
+	ReasonEndpointsUnhealthy                  = "EndpointsUnhealthy"
+	ReasonRetentionConsistent                 = "RetentionConsistent"
+	ReasonConflictingRetention                = "ConflictingRetention"
+	ReasonRetentionBelowDownsamplingRange     = "RetentionBelowDownsamplingRange"
+	ReasonRawRetentionBelowLocalRetention     = "RawRetentionBelowLocalRetention"
 )
 
 // ObjectStatusReconciler reconciles status fields of ThanosOperator objects object
@@ -64,6 +120,7 @@ type ObjectStatusReconciler struct {
 	recorder events.EventRecorder
 
 	handler *handlers.Handler
+	scope   controllerScope
 }
 
 // NewObjectStatusReconciler returns a reconciler for ThanosQuery resources.
@@ -74,6 +131,8 @@ func NewObjectStatusReconciler(conf Config, client client.Client, scheme *runtim
 		logger:   conf.InstrumentationConfig.Logger,
 		recorder: conf.InstrumentationConfig.EventRecorder,
 		handler:  handlers.NewHandler(client, scheme, conf.InstrumentationConfig.Logger).SetFeatureGates(conf.FeatureGate.ToGVK()),
+
+		scope: newControllerScope(conf),
 	}
 }
 
@@ -88,6 +147,7 @@ func NewObjectStatusReconciler(conf Config, client client.Client, scheme *runtim
 //+kubebuilder:rbac:groups=monitoring.thanos.io,resources=thanosrulers,verbs=get;list;watch
 //+kubebuilder:rbac:groups=monitoring.thanos.io,resources=thanosrulers/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=apps,resources=statefulsets;deployments,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -159,6 +219,17 @@ func (r *ObjectStatusReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				},
 			}
 		})).
+		// Pods are watched so that containers entering or leaving CrashLoopBackOff are reported straight away
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+			return []reconcile.Request{
+				{
+					NamespacedName: types.NamespacedName{
+						Name:      obj.GetLabels()[manifests.OwnerLabel],
+						Namespace: obj.GetNamespace(),
+					},
+				},
+			}
+		}), builder.WithPredicates(crashLoopChangedPredicate())).
 		Complete(r)
 
 	if err != nil {
@@ -178,6 +249,7 @@ func (r *ObjectStatusReconciler) updateStatus(ctx context.Context, object client
 
 type stats struct {
 	name                string
+	selector            *metav1.LabelSelector
 	labels              map[string]string
 	containerNames      []string
 	availableReplicas   int32
@@ -222,6 +294,7 @@ func (r *ObjectStatusReconciler) getDeploymentStatuses(ctx context.Context, obje
 
 		s = append(s, stats{
 			name:                deployment.Name,
+			selector:            deployment.Spec.Selector,
 			labels:              deployment.Labels,
 			containerNames:      containerNames,
 			availableReplicas:   deployment.Status.AvailableReplicas,
@@ -235,6 +308,15 @@ func (r *ObjectStatusReconciler) getDeploymentStatuses(ctx context.Context, obje
 	return s
 }
 
+// selectorString returns the label selector in the string form read by the scale subresource.
+func selectorString(selector *metav1.LabelSelector) string {
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return ""
+	}
+	return s.String()
+}
+
 func (r *ObjectStatusReconciler) getStatefulsetStatuses(ctx context.Context, object client.Object) []stats {
 	var statefulsetList appsv1.StatefulSetList
 	listOpts := []client.ListOption{
@@ -269,6 +351,7 @@ func (r *ObjectStatusReconciler) getStatefulsetStatuses(ctx context.Context, obj
 
 		s = append(s, stats{
 			name:              statefulset.Name,
+			selector:          statefulset.Spec.Selector,
 			labels:            statefulset.Labels,
 			containerNames:    containerNames,
 			availableReplicas: statefulset.Status.AvailableReplicas,
@@ -282,6 +365,47 @@ func (r *ObjectStatusReconciler) getStatefulsetStatuses(ctx context.Context, obj
 	return s
 }
 
+// getCrashLoopingContainers returns the containers in CrashLoopBackOff in the Pods of the given workloads,
+// as reported by crashLoopingContainers.
+func (r *ObjectStatusReconciler) getCrashLoopingContainers(ctx context.Context, namespace string, workloads []stats) []string {
+	var pods []corev1.Pod
+	for _, workload := range workloads {
+		selector, err := metav1.LabelSelectorAsSelector(workload.selector)
+		if err != nil {
+			r.logger.Error(err, "invalid selector for workload", "name", workload.name)
+			continue
+		}
+		var podList corev1.PodList
+		if err := r.List(ctx, &podList, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			r.logger.Error(err, "failed to list pods for workload", "name", workload.name)
+			continue
+		}
+		pods = append(pods, podList.Items...)
+	}
+	return crashLoopingContainers(pods)
+}
+
+// crashLoopChangedPredicate filters Pod events to the updates that change the containers in CrashLoopBackOff
+// or their termination message. Pod creations and deletions are picked up by the periodic status update.
+func crashLoopChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldPod, ok := e.ObjectOld.(*corev1.Pod)
+			if !ok {
+				return false
+			}
+			newPod, ok := e.ObjectNew.(*corev1.Pod)
+			if !ok {
+				return false
+			}
+			return !slices.Equal(crashLoopingContainers([]corev1.Pod{*oldPod}), crashLoopingContainers([]corev1.Pod{*newPod}))
+		},
+	}
+}
+
 // updateAllThanosQueryStatuses updates the status of all ThanosQuery resources.
 func (r *ObjectStatusReconciler) updateAllThanosQueryStatuses(ctx context.Context) {
 	var queryList monitoringthanosiov1alpha1.ThanosQueryList
@@ -292,6 +416,10 @@ func (r *ObjectStatusReconciler) updateAllThanosQueryStatuses(ctx context.Contex
 	}
 
 	for _, query := range queryList.Items {
+		if !r.scope.manages(&query) {
+			continue
+		}
+
 		deploymentStatuses := r.getDeploymentStatuses(ctx, &query)
 
 		for _, status := range deploymentStatuses {
@@ -302,6 +430,7 @@ func (r *ObjectStatusReconciler) updateAllThanosQueryStatuses(ctx context.Contex
 					query.Status.Querier.UpdatedReplicas = status.updatedReplicas
 					query.Status.Querier.UnavailableReplicas = status.unavailableReplicas
 					query.Status.Querier.ReadyReplicas = status.readyReplicas
+					query.Status.Querier.Selector = selectorString(status.selector)
 				}
 				if containerName == queryfrontendbldr.Name {
 					query.Status.QueryFrontend.AvailableReplicas = status.availableReplicas
@@ -309,9 +438,11 @@ func (r *ObjectStatusReconciler) updateAllThanosQueryStatuses(ctx context.Contex
 					query.Status.QueryFrontend.UpdatedReplicas = status.updatedReplicas
 					query.Status.QueryFrontend.UnavailableReplicas = status.unavailableReplicas
 					query.Status.QueryFrontend.ReadyReplicas = status.readyReplicas
+					query.Status.QueryFrontend.Selector = selectorString(status.selector)
 				}
 			}
 		}
+		meta.SetStatusCondition(&query.Status.Conditions, crashLoopCondition(r.getCrashLoopingContainers(ctx, query.GetNamespace(), deploymentStatuses)))
 		r.updateStatus(ctx, &query)
 	}
 }
@@ -326,6 +457,10 @@ func (r *ObjectStatusReconciler) updateAllThanosReceiveStatuses(ctx context.Cont
 	}
 
 	for _, receive := range receiveList.Items {
+		if !r.scope.manages(&receive) {
+			continue
+		}
+
 		deploymentStatuses := r.getDeploymentStatuses(ctx, &receive)
 		for _, status := range deploymentStatuses {
 			for _, containerName := range status.containerNames {
@@ -335,6 +470,7 @@ func (r *ObjectStatusReconciler) updateAllThanosReceiveStatuses(ctx context.Cont
 					receive.Status.Router.UpdatedReplicas = status.updatedReplicas
 					receive.Status.Router.UnavailableReplicas = status.unavailableReplicas
 					receive.Status.Router.ReadyReplicas = status.readyReplicas
+					receive.Status.Router.Selector = selectorString(status.selector)
 				}
 			}
 		}
@@ -360,6 +496,7 @@ func (r *ObjectStatusReconciler) updateAllThanosReceiveStatuses(ctx context.Cont
 			}
 		}
 
+		meta.SetStatusCondition(&receive.Status.Conditions, crashLoopCondition(r.getCrashLoopingContainers(ctx, receive.GetNamespace(), slices.Concat(deploymentStatuses, statefulsetStatuses))))
 		r.updateStatus(ctx, &receive)
 	}
 }
@@ -374,6 +511,10 @@ func (r *ObjectStatusReconciler) updateAllThanosCompactStatuses(ctx context.Cont
 	}
 
 	for _, compact := range compactList.Items {
+		if !r.scope.manages(&compact) {
+			continue
+		}
+
 		statefulsetStatuses := r.getStatefulsetStatuses(ctx, &compact)
 		compact.Status.ShardStatuses = make(map[string]monitoringthanosiov1alpha1.StatefulSetStatus)
 		for _, status := range statefulsetStatuses {
@@ -402,6 +543,7 @@ func (r *ObjectStatusReconciler) updateAllThanosCompactStatuses(ctx context.Cont
 			}
 		}
 
+		meta.SetStatusCondition(&compact.Status.Conditions, crashLoopCondition(r.getCrashLoopingContainers(ctx, compact.GetNamespace(), statefulsetStatuses)))
 		r.updateStatus(ctx, &compact)
 	}
 }
@@ -416,6 +558,10 @@ func (r *ObjectStatusReconciler) updateAllThanosRulerStatuses(ctx context.Contex
 	}
 
 	for _, ruler := range rulerList.Items {
+		if !r.scope.manages(&ruler) {
+			continue
+		}
+
 		statefulsetStatuses := r.getStatefulsetStatuses(ctx, &ruler)
 		for _, status := range statefulsetStatuses {
 			for _, containerName := range status.containerNames {
@@ -428,6 +574,7 @@ func (r *ObjectStatusReconciler) updateAllThanosRulerStatuses(ctx context.Contex
 				}
 			}
 		}
+		meta.SetStatusCondition(&ruler.Status.Conditions, crashLoopCondition(r.getCrashLoopingContainers(ctx, ruler.GetNamespace(), statefulsetStatuses)))
 		r.updateStatus(ctx, &ruler)
 	}
 }
@@ -442,6 +589,10 @@ func (r *ObjectStatusReconciler) updateAllThanosStoreStatuses(ctx context.Contex
 	}
 
 	for _, store := range storeList.Items {
+		if !r.scope.manages(&store) {
+			continue
+		}
+
 		statefulsetStatuses := r.getStatefulsetStatuses(ctx, &store)
 		store.Status.ShardStatuses = make(map[string]monitoringthanosiov1alpha1.StatefulSetStatus)
 		for _, status := range statefulsetStatuses {
@@ -469,6 +620,7 @@ func (r *ObjectStatusReconciler) updateAllThanosStoreStatuses(ctx context.Contex
 			}
 		}
 
+		meta.SetStatusCondition(&store.Status.Conditions, crashLoopCondition(r.getCrashLoopingContainers(ctx, store.GetNamespace(), statefulsetStatuses)))
 		r.updateStatus(ctx, &store)
 	}
 }
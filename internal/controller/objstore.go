@@ -0,0 +1,330 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+	"github.com/thanos-community/thanos-operator/internal/pkg/objstore"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// invalidObjectStorageConfigError is returned when the object storage configuration of a resource is invalid.
+// Thanos fails to start with an invalid configuration, so the workloads are not rolled out until it is fixed.
+type invalidObjectStorageConfigError struct {
+	reasons []string
+}
+
+func (e *invalidObjectStorageConfigError) Error() string {
+	return fmt.Sprintf("invalid object storage configuration: %s", strings.Join(e.reasons, "; "))
+}
+
+// isInvalidObjectStorageConfig returns true if the error is caused by an invalid object storage configuration.
+func isInvalidObjectStorageConfig(err error) bool {
+	var invalid *invalidObjectStorageConfigError
+	return errors.As(err, &invalid)
+}
+
+// validateObjectStorageConfigs parses the object storage configuration referenced by each config.
+// Secrets that do not exist are left to be reported as missing dependencies.
+// Configurations set inline or read from a ConfigMap must have been materialized into Secrets first.
+// It returns an *invalidObjectStorageConfigError if any configuration is invalid.
+func validateObjectStorageConfigs(ctx context.Context, c client.Reader, namespace string, configs ...v1alpha1.ObjectStorageConfig) error {
+	var reasons []string
+	for _, config := range configs {
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: config.Name}, secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to get secret %s in namespace %s: %w", config.Name, namespace, err)
+		}
+
+		data, ok := secret.Data[config.Key]
+		if !ok {
+			reasons = append(reasons, fmt.Sprintf("key %s not found in secret %s", config.Key, config.Name))
+			continue
+		}
+		if _, err := objstore.Parse(data); err != nil {
+			reasons = append(reasons, fmt.Sprintf("secret %s key %s: %v", config.Name, config.Key, err))
+		}
+	}
+
+	if len(reasons) == 0 {
+		return nil
+	}
+	return &invalidObjectStorageConfigError{reasons: reasons}
+}
+
+// materializeObjectStorageConfigs copies the object storage configurations of the owner that are set inline or read
+// from a ConfigMap into managed Secrets, and rewrites the configurations to reference them.
+// ConfigMaps that do not exist are recorded as missing dependencies.
+func materializeObjectStorageConfigs(ctx context.Context, cluster targetCluster, owner client.Object, deps *dependencies, configs ...*v1alpha1.ObjectStorageConfig) error {
+	var objs []client.Object
+	var names []string
+	for _, config := range configs {
+		opts, key, err := objStoreSecretOptions(ctx, cluster.client, owner, deps, *config)
+		if err != nil {
+			return err
+		}
+		if opts == nil {
+			continue
+		}
+
+		name := opts.GetGeneratedResourceName()
+		config.Name = name
+		config.Key = key
+		config.ConfigMap = nil
+		config.Inline = nil
+		// a Secret is only created once its ConfigMap exists
+		if opts.Data != nil && !slices.Contains(names, name) {
+			names = append(names, name)
+			objs = append(objs, opts.Build()...)
+		}
+	}
+
+	if len(objs) == 0 {
+		return nil
+	}
+	if errCount := cluster.handler.CreateOrUpdate(ctx, owner.GetNamespace(), owner, objs); errCount > 0 {
+		return fmt.Errorf("failed to create or update %d object storage secrets", errCount)
+	}
+	return nil
+}
+
+// objectStorageConfigMaps returns the names of the ConfigMaps the object storage configurations are read from.
+func objectStorageConfigMaps(configs ...*v1alpha1.ObjectStorageConfig) []string {
+	var names []string
+	for _, config := range configs {
+		if config != nil && config.ConfigMap != nil {
+			names = append(names, config.ConfigMap.Name)
+		}
+	}
+	return names
+}
+
+// objStoreSecretOptions returns the options of the managed Secret holding the object storage configuration, and the key
+// of the Secret holding the configuration. It returns nil options if the configuration is read from a user Secret.
+func objStoreSecretOptions(ctx context.Context, c client.Reader, owner client.Object, deps *dependencies, config v1alpha1.ObjectStorageConfig) (*manifests.ObjStoreSecretOptions, string, error) {
+	opts := &manifests.ObjStoreSecretOptions{
+		Options: manifests.Options{Owner: owner.GetName(), Namespace: owner.GetNamespace()},
+	}
+	switch {
+	case config.Inline != nil:
+		opts.Source = "inline/" + *config.Inline
+		opts.Data = map[string][]byte{manifests.ObjStoreSecretKey: []byte(*config.Inline)}
+		return opts, manifests.ObjStoreSecretKey, nil
+	case config.ConfigMap != nil:
+		// every key is copied so that the keys can be projected as env vars
+		opts.Source = fmt.Sprintf("configmap/%s/%s", config.ConfigMap.Name, config.ConfigMap.Key)
+		cm := &corev1.ConfigMap{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: owner.GetNamespace(), Name: config.ConfigMap.Name}, cm); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, "", fmt.Errorf("failed to get configmap %s in namespace %s: %w", config.ConfigMap.Name, owner.GetNamespace(), err)
+			}
+			deps.add("ConfigMap/" + config.ConfigMap.Name)
+			return opts, config.ConfigMap.Key, nil
+		}
+		opts.Data = make(map[string][]byte, len(cm.Data)+len(cm.BinaryData))
+		for k, v := range cm.Data {
+			opts.Data[k] = []byte(v)
+		}
+		for k, v := range cm.BinaryData {
+			opts.Data[k] = v
+		}
+		return opts, config.ConfigMap.Key, nil
+	default:
+		return nil, "", nil
+	}
+}
+
+// objectStorageDegradedCondition returns the Degraded condition reporting an invalid object storage configuration.
+func objectStorageDegradedCondition(err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    ConditionDegraded,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonInvalidObjectStorageConfig,
+		Message: err.Error(),
+	}
+}
+
+// clearObjectStorageDegraded removes the Degraded condition reporting an invalid object storage configuration
+// once the configuration is fixed. Degraded conditions set for other reasons are kept.
+func clearObjectStorageDegraded(conditions *[]metav1.Condition) {
+	if degraded := meta.FindStatusCondition(*conditions, ConditionDegraded); degraded != nil && degraded.Reason == ReasonInvalidObjectStorageConfig {
+		meta.RemoveStatusCondition(conditions, ConditionDegraded)
+	}
+}
+
+// objStoreVerifyOptions returns the options of the Jobs verifying each distinct object storage configuration of a resource.
+// The Jobs are scheduled like the workloads of the resource, described by opts, and get their additional volumes
+// and environment. It returns nil if verify is not set.
+func objStoreVerifyOptions(ctx context.Context, cluster targetCluster, verify *bool, opts manifests.Options, configs ...v1alpha1.ObjectStorageConfig) ([]manifests.ObjStoreVerifyOptions, error) {
+	if !ptr.Deref(verify, false) {
+		return nil, nil
+	}
+
+	var verifyOpts []manifests.ObjStoreVerifyOptions
+	var names []string
+	for _, config := range configs {
+		// a missing Secret is reported as a missing dependency, the Job is created once it exists
+		hash, err := cluster.handler.GetSecretHash(ctx, opts.Namespace, config.Name)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read object storage secret %s: %w", config.Name, err)
+		}
+
+		objStoreConfig := toManifestObjStoreConfig(config)
+		objStoreConfig.Hash = hash
+		o := manifests.ObjStoreVerifyOptions{
+			Options: manifests.Options{
+				Owner:            opts.Owner,
+				Namespace:        opts.Namespace,
+				Labels:           opts.Labels,
+				Image:            opts.Image,
+				Version:          opts.Version,
+				ImagePullSecrets: opts.ImagePullSecrets,
+				SecurityContext:  opts.SecurityContext,
+				PlacementConfig:  opts.PlacementConfig,
+				Additional: manifests.Additional{
+					Volumes:      opts.Additional.Volumes,
+					VolumeMounts: opts.Additional.VolumeMounts,
+					Env:          opts.Additional.Env,
+					EnvFrom:      opts.Additional.EnvFrom,
+				},
+			},
+			ObjStoreSecret: config.ToSecretKeySelector(),
+			ObjStoreConfig: objStoreConfig,
+		}
+		if name := o.GetGeneratedResourceName(); !slices.Contains(names, name) {
+			names = append(names, name)
+			verifyOpts = append(verifyOpts, o)
+		}
+	}
+	return verifyOpts, nil
+}
+
+// syncObjectStorageVerification creates the Jobs verifying the object storage configurations of the owner,
+// and deletes the Jobs that verified previous configurations, or all of them if verification is disabled.
+// It returns the number of errors encountered.
+func syncObjectStorageVerification(ctx context.Context, cluster targetCluster, owner client.Object, verifyOpts []manifests.ObjStoreVerifyOptions) int {
+	var errCount int
+	var names []string
+	for _, opts := range verifyOpts {
+		names = append(names, opts.GetGeneratedResourceName())
+		errCount += cluster.handler.CreateOrUpdate(ctx, owner.GetNamespace(), owner, opts.Build())
+	}
+
+	jobs, err := listObjStoreVerifyJobs(ctx, cluster.client, owner)
+	if err != nil {
+		return errCount + 1
+	}
+	for _, job := range jobs {
+		if slices.Contains(names, job.GetName()) {
+			continue
+		}
+		// Jobs orphan their pods unless their deletion is propagated
+		if err := cluster.client.Delete(ctx, &job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+			errCount++
+		}
+	}
+	return errCount
+}
+
+// listObjStoreVerifyJobs lists the Jobs verifying the object storage configurations of the owner.
+func listObjStoreVerifyJobs(ctx context.Context, c client.Reader, owner client.Object) ([]batchv1.Job, error) {
+	opts := manifests.ObjStoreVerifyOptions{Options: manifests.Options{Owner: owner.GetName()}}
+	jobs := &batchv1.JobList{}
+	if err := c.List(ctx, jobs, manifests.GetLabelSelectorForOwner(opts), client.InNamespace(owner.GetNamespace())); err != nil {
+		return nil, fmt.Errorf("failed to list object storage verification jobs: %w", err)
+	}
+	return jobs.Items, nil
+}
+
+// objectStorageVerifiedCondition returns the ObjectStorageVerified condition for the verification Jobs.
+// The object storage is verified once every Job has completed.
+func objectStorageVerifiedCondition(jobs []batchv1.Job) metav1.Condition {
+	var failed, pending, completed []string
+	for _, job := range jobs {
+		if job.GetDeletionTimestamp() != nil {
+			continue
+		}
+		switch {
+		case jobCondition(job, batchv1.JobFailed) != nil:
+			failed = append(failed, fmt.Sprintf("job %s failed: %s", job.GetName(), jobCondition(job, batchv1.JobFailed).Message))
+		case jobCondition(job, batchv1.JobComplete) != nil:
+			completed = append(completed, job.GetName())
+		default:
+			pending = append(pending, job.GetName())
+		}
+	}
+
+	switch {
+	case len(failed) > 0:
+		return metav1.Condition{
+			Type:    ConditionObjectStorageVerified,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonProbeFailed,
+			Message: fmt.Sprintf("Object storage verification %s", strings.Join(failed, "; ")),
+		}
+	case len(pending) > 0 || len(completed) == 0:
+		return metav1.Condition{
+			Type:    ConditionObjectStorageVerified,
+			Status:  metav1.ConditionUnknown,
+			Reason:  ReasonProbePending,
+			Message: "Waiting for the object storage verification to finish",
+		}
+	default:
+		return metav1.Condition{
+			Type:    ConditionObjectStorageVerified,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonProbeSucceeded,
+			Message: fmt.Sprintf("Object storage verified by job %s", strings.Join(completed, ", ")),
+		}
+	}
+}
+
+// jobCondition returns the condition of the given type of the Job if it is true, nil otherwise.
+func jobCondition(job batchv1.Job, conditionType batchv1.JobConditionType) *batchv1.JobCondition {
+	for _, c := range job.Status.Conditions {
+		if c.Type == conditionType && c.Status == corev1.ConditionTrue {
+			return &c
+		}
+	}
+	return nil
+}
+
+// reportObjectStorageVerification sets the ObjectStorageVerified condition of the owner from its verification Jobs,
+// or removes it if verification is disabled. A Warning event is emitted when the verification starts failing.
+func reportObjectStorageVerification(ctx context.Context, c client.Reader, recorder events.EventRecorder, owner client.Object, conditions *[]metav1.Condition, verify *bool) error {
+	if !ptr.Deref(verify, false) {
+		meta.RemoveStatusCondition(conditions, ConditionObjectStorageVerified)
+		return nil
+	}
+
+	jobs, err := listObjStoreVerifyJobs(ctx, c, owner)
+	if err != nil {
+		return err
+	}
+	condition := objectStorageVerifiedCondition(jobs)
+	if condition.Status == metav1.ConditionFalse && !meta.IsStatusConditionFalse(*conditions, ConditionObjectStorageVerified) {
+		recorder.Eventf(owner, nil, corev1.EventTypeWarning, "ObjectStorageVerificationFailed", "Reconcile", "%s", condition.Message)
+	}
+	meta.SetStatusCondition(conditions, condition)
+	return nil
+}
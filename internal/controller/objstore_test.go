@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+	"github.com/thanos-community/thanos-operator/internal/pkg/handlers"
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestValidateObjectStorageConfigs(t *testing.T) {
+	ctx := context.Background()
+	secret := func(name, data string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+			Data:       map[string][]byte{"objstore.yaml": []byte(data)},
+		}
+	}
+	config := func(name, key string) v1alpha1.ObjectStorageConfig {
+		return v1alpha1.ObjectStorageConfig{LocalObjectReference: corev1.LocalObjectReference{Name: name}, Key: key}
+	}
+	c := fake.NewFakeClient(
+		secret("valid", "type: S3\nconfig:\n  bucket: thanos\n"),
+		secret("unknown-type", "type: FTP\nconfig: {}\n"),
+		secret("no-bucket", "type: GCS\nconfig: {}\n"),
+	)
+
+	if err := validateObjectStorageConfigs(ctx, c, "ns", config("valid", "objstore.yaml"), config("missing", "objstore.yaml")); err != nil {
+		t.Fatalf("expected valid and missing configurations to pass, got %v", err)
+	}
+
+	err := validateObjectStorageConfigs(ctx, c, "ns",
+		config("valid", "objstore.yaml"),
+		config("valid", "other.yaml"),
+		config("unknown-type", "objstore.yaml"),
+		config("no-bucket", "objstore.yaml"),
+	)
+	if !isInvalidObjectStorageConfig(err) {
+		t.Fatalf("expected an invalid object storage configuration error, got %v", err)
+	}
+	for _, want := range []string{
+		"key other.yaml not found in secret valid",
+		`secret unknown-type key objstore.yaml: unsupported object storage type "FTP"`,
+		"secret no-bucket key objstore.yaml: GCS configuration is missing bucket",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to contain %q, got %q", want, err.Error())
+		}
+	}
+
+	condition := objectStorageDegradedCondition(err)
+	conditions := []metav1.Condition{condition}
+	clearObjectStorageDegraded(&conditions)
+	if len(conditions) != 0 {
+		t.Errorf("expected the Degraded condition to be cleared, got %v", conditions)
+	}
+	conditions = []metav1.Condition{{Type: ConditionDegraded, Status: metav1.ConditionTrue, Reason: ReasonReplicasNotReady}}
+	clearObjectStorageDegraded(&conditions)
+	if len(conditions) != 1 {
+		t.Error("expected Degraded conditions set for other reasons to be kept")
+	}
+}
+
+func TestMaterializeObjectStorageConfigs(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "objstore", Namespace: "ns"},
+		Data:       map[string]string{"thanos.yaml": "type: S3\nconfig:\n  bucket: thanos\n", "region": "eu"},
+	}).Build()
+	cluster := targetCluster{client: c, handler: handlers.NewHandler(c, scheme, logr.Discard())}
+	owner := &v1alpha1.ThanosCompact{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns", UID: "uid"}}
+
+	fromSecret := v1alpha1.ObjectStorageConfig{LocalObjectReference: corev1.LocalObjectReference{Name: "user"}, Key: "thanos.yaml"}
+	const content = "type: FILESYSTEM\nconfig:\n  directory: /data\n"
+	inline := v1alpha1.ObjectStorageConfig{Inline: ptr.To(content)}
+	sameInline := v1alpha1.ObjectStorageConfig{Inline: ptr.To(content)}
+	fromConfigMap := v1alpha1.ObjectStorageConfig{ConfigMap: &corev1.ConfigMapKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "objstore"},
+		Key:                  "thanos.yaml",
+	}}
+	missing := v1alpha1.ObjectStorageConfig{ConfigMap: &corev1.ConfigMapKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "missing"},
+		Key:                  "thanos.yaml",
+	}}
+
+	deps := &dependencies{}
+	if err := materializeObjectStorageConfigs(ctx, cluster, owner, deps, &fromSecret, &inline, &sameInline, &fromConfigMap, &missing); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if fromSecret.Name != "user" || fromSecret.Key != "thanos.yaml" {
+		t.Errorf("expected configurations read from a secret to be left as is, got %+v", fromSecret)
+	}
+	if !strings.HasPrefix(inline.Name, "thanos-objstore-test-") || inline.Key != manifests.ObjStoreSecretKey || inline.Inline != nil {
+		t.Errorf("expected the inline configuration to reference a managed secret, got %+v", inline)
+	}
+	if sameInline.Name != inline.Name {
+		t.Errorf("expected identical inline configurations to share a secret, got %s and %s", sameInline.Name, inline.Name)
+	}
+	if fromConfigMap.Name == inline.Name || fromConfigMap.Key != "thanos.yaml" || fromConfigMap.ConfigMap != nil {
+		t.Errorf("expected the configmap configuration to reference its own managed secret, got %+v", fromConfigMap)
+	}
+	if len(deps.missing) != 1 || deps.missing[0] != "ConfigMap/missing" {
+		t.Errorf("expected the missing configmap to be recorded as a dependency, got %v", deps.missing)
+	}
+
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "ns", Name: inline.Name}, secret); err != nil {
+		t.Fatalf("expected the inline configuration secret to be created: %v", err)
+	}
+	if string(secret.Data[manifests.ObjStoreSecretKey]) != content {
+		t.Errorf("unexpected inline configuration secret data %v", secret.Data)
+	}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "ns", Name: fromConfigMap.Name}, secret); err != nil {
+		t.Fatalf("expected the configmap configuration secret to be created: %v", err)
+	}
+	if string(secret.Data["region"]) != "eu" {
+		t.Errorf("expected every key of the configmap to be copied, got %v", secret.Data)
+	}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "ns", Name: missing.Name}, secret); err == nil {
+		t.Error("expected no secret to be created for a missing configmap")
+	}
+}
+
+func TestObjectStorageVerifiedCondition(t *testing.T) {
+	job := func(name string, conditionType batchv1.JobConditionType) batchv1.Job {
+		j := batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if conditionType != "" {
+			j.Status.Conditions = []batchv1.JobCondition{{Type: conditionType, Status: corev1.ConditionTrue, Message: "bucket unreachable"}}
+		}
+		return j
+	}
+	deleted := job("deleted", batchv1.JobFailed)
+	deleted.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+
+	for _, tc := range []struct {
+		name       string
+		jobs       []batchv1.Job
+		wantStatus metav1.ConditionStatus
+		wantReason string
+	}{
+		{
+			name:       "no jobs",
+			wantStatus: metav1.ConditionUnknown,
+			wantReason: ReasonProbePending,
+		},
+		{
+			name:       "running",
+			jobs:       []batchv1.Job{job("a", batchv1.JobComplete), job("b", "")},
+			wantStatus: metav1.ConditionUnknown,
+			wantReason: ReasonProbePending,
+		},
+		{
+			name:       "completed",
+			jobs:       []batchv1.Job{job("a", batchv1.JobComplete), job("b", batchv1.JobComplete), deleted},
+			wantStatus: metav1.ConditionTrue,
+			wantReason: ReasonProbeSucceeded,
+		},
+		{
+			name:       "failed",
+			jobs:       []batchv1.Job{job("a", batchv1.JobComplete), job("b", batchv1.JobFailed), job("c", "")},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonProbeFailed,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := objectStorageVerifiedCondition(tc.jobs)
+			if got.Status != tc.wantStatus || got.Reason != tc.wantReason {
+				t.Errorf("expected %s/%s, got %s/%s: %s", tc.wantStatus, tc.wantReason, got.Status, got.Reason, got.Message)
+			}
+		})
+	}
+}
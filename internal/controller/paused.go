@@ -0,0 +1,15 @@
+package controller
+
+import (
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// isPaused returns true if the reconciliation of the given object is paused, either through the paused field
+// of its spec or through the v1alpha1.PausedAnnotation.
+func isPaused(obj client.Object, paused *bool) bool {
+	return ptr.Deref(paused, false) || obj.GetAnnotations()[v1alpha1.PausedAnnotation] == "true"
+}
@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// pendingDeletions tracks, per resource, the time until the next pruned child object is due for deletion,
+// so that the resource is requeued once the prune grace period of its children expires.
+// It is also used to requeue a ThanosReceive once the scale down grace period of a hashring expires.
+type pendingDeletions struct {
+	mu    sync.Mutex
+	after map[types.NamespacedName]time.Duration
+}
+
+func newPendingDeletions() *pendingDeletions {
+	return &pendingDeletions{after: make(map[types.NamespacedName]time.Duration)}
+}
+
+// record keeps the shortest non-zero delay recorded for the given resource.
+func (p *pendingDeletions) record(key types.NamespacedName, after time.Duration) {
+	if after <= 0 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if current, ok := p.after[key]; !ok || after < current {
+		p.after[key] = after
+	}
+}
+
+// pop returns the delay recorded for the given resource and forgets it.
+// It returns zero if no child object of the resource is pending deletion.
+func (p *pendingDeletions) pop(key types.NamespacedName) time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	after := p.after[key]
+	delete(p.after, key)
+	return after
+}
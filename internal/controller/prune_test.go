@@ -0,0 +1,29 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestPendingDeletions(t *testing.T) {
+	p := newPendingDeletions()
+	key := types.NamespacedName{Namespace: "ns", Name: "test"}
+
+	if got := p.pop(key); got != 0 {
+		t.Errorf("expected no pending deletion, got %s", got)
+	}
+
+	p.record(key, 0)
+	p.record(key, 5*time.Minute)
+	p.record(key, time.Minute)
+	p.record(key, 10*time.Minute)
+	if got := p.pop(key); got != time.Minute {
+		t.Errorf("expected shortest delay to be kept, got %s", got)
+	}
+
+	if got := p.pop(key); got != 0 {
+		t.Errorf("expected pending deletion to be forgotten after pop, got %s", got)
+	}
+}
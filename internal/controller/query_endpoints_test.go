@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+	manifestreceive "github.com/thanos-community/thanos-operator/internal/pkg/manifests/receive"
+	"github.com/thanos-community/thanos-operator/internal/pkg/metrics"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDiscoverEndpointGroups(t *testing.T) {
+	service := func(name string, labels map[string]string) *corev1.Service {
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", Labels: map[string]string{}},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "grpc", Port: 10901}}},
+		}
+		for k, v := range requiredStoreServiceLabels {
+			svc.Labels[k] = v
+		}
+		for k, v := range labels {
+			svc.Labels[k] = v
+		}
+		return svc
+	}
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		service("ingester", map[string]string{manifests.ComponentLabel: manifestreceive.IngestComponentName}),
+		service("ruler", map[string]string{string(manifests.StrictLabel): "true"}),
+		service("store", nil),
+		func() *corev1.Service {
+			svc := service("sidecar", nil)
+			svc.Annotations = map[string]string{manifests.GRPCPortAnnotation: "10911"}
+			svc.Spec.Ports = []corev1.ServicePort{{Name: "store-api", Port: 10911}}
+			return svc
+		}(),
+		func() *corev1.Service {
+			svc := service("unnamed", nil)
+			svc.Spec.Ports = []corev1.ServicePort{{Name: "store-api", Port: 10901}}
+			return svc
+		}(),
+	).Build()
+	reg := prometheus.NewRegistry()
+	r := &ThanosQueryReconciler{logger: logr.Discard(), metrics: metrics.NewThanosQueryMetrics(reg, metrics.NewCommonMetrics(reg))}
+	cluster := targetCluster{client: c}
+
+	for _, tc := range []struct {
+		name string
+		mode *v1alpha1.QueryDiscoveryMode
+		want map[string]manifests.EndpointType
+	}{
+		{
+			name: "label",
+			want: map[string]manifests.EndpointType{
+				"ingester": manifests.RegularLabel,
+				"ruler":    manifests.StrictLabel,
+				"store":    manifests.RegularLabel,
+				"sidecar":  manifests.RegularLabel,
+			},
+		},
+		{
+			name: "endpoint group",
+			mode: ptr.To(v1alpha1.QueryDiscoveryModeEndpointGroup),
+			want: map[string]manifests.EndpointType{
+				"ingester": manifests.RegularLabel,
+				"ruler":    manifests.GroupStrictLabel,
+				"store":    manifests.GroupLabel,
+				"sidecar":  manifests.GroupLabel,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			query := v1alpha1.ThanosQuery{
+				ObjectMeta: metav1.ObjectMeta{Name: "query", Namespace: "ns"},
+				Spec:       v1alpha1.ThanosQuerySpec{DiscoveryMode: tc.mode},
+			}
+			endpoints, _, err := r.getStoreAPIServiceEndpoints(context.Background(), cluster, query, &dependencies{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(endpoints) != len(tc.want) {
+				t.Fatalf("expected %d endpoints, got %+v", len(tc.want), endpoints)
+			}
+			for _, ep := range endpoints {
+				if ep.ServiceName == "sidecar" && ep.Port != 10911 {
+					t.Errorf("expected the annotated port of sidecar, got %d", ep.Port)
+				}
+				if ep.Type != tc.want[ep.ServiceName] {
+					t.Errorf("expected %s to be wired as %s, got %s", ep.ServiceName, tc.want[ep.ServiceName], ep.Type)
+				}
+			}
+		})
+	}
+}
+
+func TestStoreAPIServiceDependency(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	cluster := targetCluster{client: fake.NewClientBuilder().WithScheme(scheme).Build()}
+	r := &ThanosQueryReconciler{logger: logr.Discard()}
+
+	for _, tc := range []struct {
+		name    string
+		spec    v1alpha1.ThanosQuerySpec
+		missing bool
+	}{
+		{name: "no endpoints", missing: true},
+		{name: "federation", spec: v1alpha1.ThanosQuerySpec{Federation: &v1alpha1.QueryFederationSpec{}}},
+		{name: "external endpoints", spec: v1alpha1.ThanosQuerySpec{ExternalEndpoints: []string{"sidecar.example.com:10901"}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			query := v1alpha1.ThanosQuery{ObjectMeta: metav1.ObjectMeta{Name: "query", Namespace: "ns"}, Spec: tc.spec}
+			deps := &dependencies{}
+			if _, _, err := r.getStoreAPIServiceEndpoints(context.Background(), cluster, query, deps); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if missing := deps.err() != nil; missing != tc.missing {
+				t.Errorf("expected missing dependency %v, got %v", tc.missing, deps.missing)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// remoteCheckInterval is the interval between two reachability checks of the federation remotes.
+	remoteCheckInterval = time.Minute
+	// remoteDialTimeout bounds the connection attempt to a remote, so that an unreachable remote does not block reconciles.
+	remoteDialTimeout = 5 * time.Second
+)
+
+// remotesToCheck returns the federation remotes whose reachability is checked by the operator, or nil if there are none.
+// Remotes of a ThanosQuery deployed to a target cluster are not checked, as they may not be reachable from the operator.
+func remotesToCheck(query v1alpha1.ThanosQuery) []v1alpha1.RemoteQueryEndpoint {
+	if query.Spec.Federation == nil || query.Spec.TargetCluster != nil {
+		return nil
+	}
+	return query.Spec.Federation.Remotes
+}
+
+// remoteChecksDue returns true if the reachability of the federation remotes must be checked, because the last check
+// is older than the check interval or the remotes changed since.
+func remoteChecksDue(query v1alpha1.ThanosQuery, now time.Time) bool {
+	remotes := remotesToCheck(query)
+	if len(remotes) != len(query.Status.Remotes) {
+		return true
+	}
+	for i, remote := range remotes {
+		status := query.Status.Remotes[i]
+		if status.Name != remote.Name || status.Address != remote.Address || !now.Before(status.LastCheckTime.Add(remoteCheckInterval)) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextRemoteCheck returns the time until the next reachability check of the federation remotes is due,
+// or zero if there are no remotes to check.
+func nextRemoteCheck(query v1alpha1.ThanosQuery, now time.Time) time.Duration {
+	if len(remotesToCheck(query)) == 0 {
+		return 0
+	}
+	if len(query.Status.Remotes) == 0 {
+		return remoteCheckInterval
+	}
+	return max(query.Status.Remotes[0].LastCheckTime.Add(remoteCheckInterval).Sub(now), time.Second)
+}
+
+// checkRemotes opens a TCP connection to each remote concurrently and returns their reachability, in the order of the remotes.
+func checkRemotes(ctx context.Context, remotes []v1alpha1.RemoteQueryEndpoint, now time.Time) []v1alpha1.RemoteEndpointStatus {
+	statuses := make([]v1alpha1.RemoteEndpointStatus, len(remotes))
+	var wg sync.WaitGroup
+	for i, remote := range remotes {
+		wg.Go(func() {
+			status := v1alpha1.RemoteEndpointStatus{
+				Name:          remote.Name,
+				Address:       remote.Address,
+				LastCheckTime: metav1.NewTime(now),
+			}
+			dialer := net.Dialer{Timeout: remoteDialTimeout}
+			conn, err := dialer.DialContext(ctx, "tcp", remote.Address)
+			if err != nil {
+				status.Message = err.Error()
+			} else {
+				status.Reachable = true
+				_ = conn.Close()
+			}
+			statuses[i] = status
+		})
+	}
+	wg.Wait()
+	return statuses
+}
+
+// remotesCondition returns the RemotesReachable condition for the reachability of the remotes.
+func remotesCondition(statuses []v1alpha1.RemoteEndpointStatus) metav1.Condition {
+	var unreachable []string
+	for _, status := range statuses {
+		if !status.Reachable {
+			unreachable = append(unreachable, status.Name)
+		}
+	}
+	if len(unreachable) > 0 {
+		return metav1.Condition{
+			Type:    ConditionRemotesReachable,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonRemotesUnreachable,
+			Message: fmt.Sprintf("Remotes unreachable from the operator: %s", strings.Join(unreachable, ", ")),
+		}
+	}
+	return metav1.Condition{
+		Type:    ConditionRemotesReachable,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonRemotesReachable,
+		Message: "All remotes are reachable from the operator",
+	}
+}
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestCheckRemotes(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	closedAddr := closed.Addr().String()
+	_ = closed.Close()
+
+	now := time.Now()
+	statuses := checkRemotes(context.Background(), []v1alpha1.RemoteQueryEndpoint{
+		{Name: "eu", Address: listener.Addr().String()},
+		{Name: "us", Address: closedAddr},
+	}, now)
+
+	if len(statuses) != 2 {
+		t.Fatalf("expected 2 statuses, got %d", len(statuses))
+	}
+	if !statuses[0].Reachable || statuses[0].Name != "eu" || statuses[0].Message != "" {
+		t.Errorf("expected eu to be reachable, got %+v", statuses[0])
+	}
+	if statuses[1].Reachable || statuses[1].Name != "us" || statuses[1].Message == "" {
+		t.Errorf("expected us to be unreachable with a message, got %+v", statuses[1])
+	}
+	if !statuses[1].LastCheckTime.Time.Equal(now) {
+		t.Errorf("expected the check time to be recorded, got %s", statuses[1].LastCheckTime)
+	}
+
+	condition := remotesCondition(statuses)
+	if condition.Status != metav1.ConditionFalse || condition.Reason != ReasonRemotesUnreachable {
+		t.Errorf("expected the RemotesReachable condition to be false, got %+v", condition)
+	}
+	if condition = remotesCondition(statuses[:1]); condition.Status != metav1.ConditionTrue {
+		t.Errorf("expected the RemotesReachable condition to be true, got %+v", condition)
+	}
+}
+
+func TestRemoteChecksDue(t *testing.T) {
+	now := time.Now()
+	query := v1alpha1.ThanosQuery{
+		Spec: v1alpha1.ThanosQuerySpec{
+			Federation: &v1alpha1.QueryFederationSpec{Remotes: []v1alpha1.RemoteQueryEndpoint{
+				{Name: "eu", Address: "query.eu.example.com:10901"},
+			}},
+		},
+	}
+	if !remoteChecksDue(query, now) {
+		t.Errorf("expected the remotes to be checked without a status")
+	}
+
+	query.Status.Remotes = []v1alpha1.RemoteEndpointStatus{
+		{Name: "eu", Address: "query.eu.example.com:10901", Reachable: true, LastCheckTime: metav1.NewTime(now.Add(-10 * time.Second))},
+	}
+	if remoteChecksDue(query, now) {
+		t.Errorf("expected no check before the check interval")
+	}
+	if next := nextRemoteCheck(query, now); next != 50*time.Second {
+		t.Errorf("expected the next check in 50s, got %s", next)
+	}
+	if !remoteChecksDue(query, now.Add(remoteCheckInterval)) {
+		t.Errorf("expected a check once the check interval elapsed")
+	}
+
+	changed := *query.DeepCopy()
+	changed.Spec.Federation.Remotes[0].Address = "query.eu.example.com:443"
+	if !remoteChecksDue(changed, now) {
+		t.Errorf("expected a check when the address of a remote changed")
+	}
+
+	query.Spec.TargetCluster = ptr.To("workload")
+	if len(remotesToCheck(query)) != 0 || nextRemoteCheck(query, now) != 0 {
+		t.Errorf("expected the remotes of a ThanosQuery deployed to a target cluster not to be checked")
+	}
+}
@@ -0,0 +1,189 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/api"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+	manifestquery "github.com/thanos-community/thanos-operator/internal/pkg/manifests/query"
+	manifestqueryfrontend "github.com/thanos-community/thanos-operator/internal/pkg/manifests/queryfrontend"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// readProbeTimeout bounds each query of the read probe, so that a hanging querier does not block reconciles.
+const readProbeTimeout = 30 * time.Second
+
+const (
+	readProbeInstantQuery = "instant"
+	readProbeRangeQuery   = "range"
+)
+
+// readProbeConfig is the read probe of a ThanosQuery with the defaults applied.
+type readProbeConfig struct {
+	url              string
+	query            string
+	interval         time.Duration
+	queryRange       time.Duration
+	instantThreshold time.Duration
+	rangeThreshold   time.Duration
+}
+
+// readProbeConfigFor returns the read probe configuration of the ThanosQuery.
+// The probe targets the Query Frontend if one is deployed, and the Querier otherwise.
+func readProbeConfigFor(query v1alpha1.ThanosQuery) readProbeConfig {
+	probe := query.Spec.ReadProbe
+	url := fmt.Sprintf("http://%s:%d", manifests.ServiceHost(QueryNameFromParent(query.GetName()), query.GetNamespace()), manifestquery.HTTPPort)
+	if query.Spec.QueryFrontend != nil {
+		url = fmt.Sprintf("http://%s:%d", manifests.ServiceHost(QueryFrontendNameFromParent(query.GetName()), query.GetNamespace()),
+			query.Spec.QueryFrontend.Service.PortOr(manifestqueryfrontend.HTTPPortName, manifestqueryfrontend.HTTPPort))
+	}
+
+	return readProbeConfig{
+		url:              url,
+		query:            ptr.Deref(probe.Query, "thanos_operator_write_probe"),
+		interval:         parseDurationOr(probe.Interval, time.Minute),
+		queryRange:       parseDurationOr(probe.Range, time.Hour),
+		instantThreshold: parseDurationOr(probe.InstantQueryLatencyThreshold, time.Second),
+		rangeThreshold:   parseDurationOr(probe.RangeQueryLatencyThreshold, 5*time.Second),
+	}
+}
+
+// parseDurationOr parses the duration, or returns def if it is not set or invalid.
+func parseDurationOr(d *v1alpha1.Duration, def time.Duration) time.Duration {
+	if d == nil {
+		return def
+	}
+	parsed, err := model.ParseDuration(string(*d))
+	if err != nil || parsed <= 0 {
+		return def
+	}
+	return time.Duration(parsed)
+}
+
+// nextReadProbe returns the time until the next read probe of the ThanosQuery is due,
+// or zero if the ThanosQuery has no read probe.
+func nextReadProbe(query v1alpha1.ThanosQuery, now time.Time) time.Duration {
+	if query.Spec.ReadProbe == nil {
+		return 0
+	}
+	interval := readProbeConfigFor(query).interval
+	if query.Status.ReadProbe == nil {
+		return interval
+	}
+	return max(query.Status.ReadProbe.LastProbeTime.Add(interval).Sub(now), time.Second)
+}
+
+// readProbeDue returns true if the read probe of the ThanosQuery must run.
+func readProbeDue(query v1alpha1.ThanosQuery, now time.Time) bool {
+	last := query.Status.ReadProbe
+	return last == nil || !now.Before(last.LastProbeTime.Add(readProbeConfigFor(query).interval))
+}
+
+// readProbeResult is the result of a read probe.
+type readProbeResult struct {
+	// instantLatency and rangeLatency are the latencies of the queries, or nil if they failed or did not run.
+	instantLatency *time.Duration
+	rangeLatency   *time.Duration
+	// err is the reason the probe failed, or nil if it succeeded.
+	err error
+}
+
+// runReadProbe runs an instant query and then a range query for the canary series.
+// The probe fails as soon as a query errors, returns no data or exceeds its latency threshold.
+func runReadProbe(ctx context.Context, config readProbeConfig, now time.Time) readProbeResult {
+	client, err := api.NewClient(api.Config{Address: config.url})
+	if err != nil {
+		return readProbeResult{err: err}
+	}
+	promAPI := promv1.NewAPI(client)
+
+	var result readProbeResult
+	result.instantLatency, result.err = timeReadProbeQuery(ctx, readProbeInstantQuery, config.instantThreshold, func(ctx context.Context) (model.Value, error) {
+		v, _, err := promAPI.Query(ctx, config.query, now)
+		return v, err
+	})
+	if result.err != nil {
+		return result
+	}
+
+	queryRange := promv1.Range{
+		Start: now.Add(-config.queryRange),
+		End:   now,
+		Step:  max(config.queryRange/60, time.Second),
+	}
+	result.rangeLatency, result.err = timeReadProbeQuery(ctx, readProbeRangeQuery, config.rangeThreshold, func(ctx context.Context) (model.Value, error) {
+		v, _, err := promAPI.QueryRange(ctx, config.query, queryRange)
+		return v, err
+	})
+	return result
+}
+
+// timeReadProbeQuery runs the query and returns its latency.
+// The latency is returned if the query succeeded, even if it exceeded the threshold.
+func timeReadProbeQuery(ctx context.Context, queryType string, threshold time.Duration, query func(context.Context) (model.Value, error)) (*time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, readProbeTimeout)
+	defer cancel()
+
+	start := time.Now()
+	v, err := query(ctx)
+	latency := time.Since(start)
+	if err != nil {
+		return nil, fmt.Errorf("%s query failed: %w", queryType, err)
+	}
+	if isEmptyQueryResult(v) {
+		return &latency, fmt.Errorf("%s query returned no data", queryType)
+	}
+	if latency > threshold {
+		return &latency, fmt.Errorf("%s query took %s, above the threshold of %s", queryType, latency.Round(time.Millisecond), threshold)
+	}
+	return &latency, nil
+}
+
+func isEmptyQueryResult(v model.Value) bool {
+	switch v := v.(type) {
+	case model.Vector:
+		return len(v) == 0
+	case model.Matrix:
+		return len(v) == 0
+	default:
+		return v == nil
+	}
+}
+
+// status returns the read probe status for the result.
+func (r readProbeResult) status(now time.Time) *v1alpha1.ReadProbeStatus {
+	status := &v1alpha1.ReadProbeStatus{LastProbeTime: metav1.NewTime(now)}
+	if r.instantLatency != nil {
+		status.InstantQueryLatency = &metav1.Duration{Duration: *r.instantLatency}
+	}
+	if r.rangeLatency != nil {
+		status.RangeQueryLatency = &metav1.Duration{Duration: *r.rangeLatency}
+	}
+	return status
+}
+
+// condition returns the ReadProbeSucceeded condition for the result.
+func (r readProbeResult) condition() metav1.Condition {
+	if r.err != nil {
+		return metav1.Condition{
+			Type:    ConditionReadProbeSucceeded,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonProbeFailed,
+			Message: fmt.Sprintf("Read probe failed: %v", r.err),
+		}
+	}
+	return metav1.Condition{
+		Type:    ConditionReadProbeSucceeded,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonProbeSucceeded,
+		Message: "Instant and range queries returned data within their latency thresholds",
+	}
+}
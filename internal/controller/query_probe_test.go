@@ -0,0 +1,170 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	readProbeVectorResponse = `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"__name__":"thanos_operator_write_probe"},"value":[1700000000,"1"]}]}}`
+	readProbeMatrixResponse = `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"__name__":"thanos_operator_write_probe"},"values":[[1700000000,"1"]]}]}}`
+	readProbeEmptyResponse  = `{"status":"success","data":{"resultType":"vector","result":[]}}`
+	readProbeErrorResponse  = `{"status":"error","errorType":"bad_data","error":"parse error"}`
+)
+
+func TestRunReadProbe(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		instant       string
+		instantStatus int
+		rangeBody     string
+		delay         time.Duration
+		expectErr     string
+		expectInstant bool
+		expectRange   bool
+	}{
+		{
+			name:          "success",
+			instant:       readProbeVectorResponse,
+			rangeBody:     readProbeMatrixResponse,
+			expectInstant: true,
+			expectRange:   true,
+		},
+		{
+			name:          "instant query returns no data",
+			instant:       readProbeEmptyResponse,
+			rangeBody:     readProbeMatrixResponse,
+			expectErr:     "instant query returned no data",
+			expectInstant: true,
+		},
+		{
+			name:          "instant query fails",
+			instant:       readProbeErrorResponse,
+			instantStatus: http.StatusBadRequest,
+			rangeBody:     readProbeMatrixResponse,
+			expectErr:     "instant query failed",
+		},
+		{
+			name:          "latency above threshold",
+			instant:       readProbeVectorResponse,
+			rangeBody:     readProbeMatrixResponse,
+			delay:         20 * time.Millisecond,
+			expectErr:     "above the threshold",
+			expectInstant: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(tc.delay)
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/api/v1/query":
+					if tc.instantStatus != 0 {
+						w.WriteHeader(tc.instantStatus)
+					}
+					_, _ = w.Write([]byte(tc.instant))
+				case "/api/v1/query_range":
+					_, _ = w.Write([]byte(tc.rangeBody))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			config := readProbeConfig{
+				url:              server.URL,
+				query:            "thanos_operator_write_probe",
+				queryRange:       time.Hour,
+				instantThreshold: 10 * time.Second,
+				rangeThreshold:   10 * time.Second,
+			}
+			if tc.delay > 0 {
+				config.instantThreshold = time.Millisecond
+			}
+
+			result := runReadProbe(context.Background(), config, time.Now())
+			if tc.expectErr == "" && result.err != nil {
+				t.Fatalf("unexpected error: %v", result.err)
+			}
+			if tc.expectErr != "" && (result.err == nil || !strings.Contains(result.err.Error(), tc.expectErr)) {
+				t.Fatalf("expected error containing %q, got %v", tc.expectErr, result.err)
+			}
+			if (result.instantLatency != nil) != tc.expectInstant {
+				t.Errorf("expected instant latency set to be %v, got %v", tc.expectInstant, result.instantLatency)
+			}
+			if (result.rangeLatency != nil) != tc.expectRange {
+				t.Errorf("expected range latency set to be %v, got %v", tc.expectRange, result.rangeLatency)
+			}
+
+			condition := result.condition()
+			expectStatus := metav1.ConditionTrue
+			if tc.expectErr != "" {
+				expectStatus = metav1.ConditionFalse
+			}
+			if condition.Status != expectStatus {
+				t.Errorf("expected condition status %s, got %s", expectStatus, condition.Status)
+			}
+		})
+	}
+}
+
+func TestReadProbeSchedule(t *testing.T) {
+	now := time.Now()
+	query := v1alpha1.ThanosQuery{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"},
+		Spec: v1alpha1.ThanosQuerySpec{
+			ReadProbe: &v1alpha1.ReadProbeSpec{Interval: ptr.To(v1alpha1.Duration("2m"))},
+		},
+	}
+
+	if !readProbeDue(query, now) {
+		t.Error("expected the first read probe to be due")
+	}
+
+	query.Status.ReadProbe = &v1alpha1.ReadProbeStatus{LastProbeTime: metav1.NewTime(now.Add(-time.Minute))}
+	if readProbeDue(query, now) {
+		t.Error("expected the read probe not to be due before the interval elapsed")
+	}
+	if next := nextReadProbe(query, now); next != time.Minute {
+		t.Errorf("expected next read probe in 1m, got %s", next)
+	}
+
+	query.Status.ReadProbe.LastProbeTime = metav1.NewTime(now.Add(-3 * time.Minute))
+	if !readProbeDue(query, now) {
+		t.Error("expected the read probe to be due after the interval elapsed")
+	}
+
+	query.Spec.ReadProbe = nil
+	if next := nextReadProbe(query, now); next != 0 {
+		t.Errorf("expected no next read probe when disabled, got %s", next)
+	}
+}
+
+func TestReadProbeConfigFor(t *testing.T) {
+	query := v1alpha1.ThanosQuery{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"},
+		Spec:       v1alpha1.ThanosQuerySpec{ReadProbe: &v1alpha1.ReadProbeSpec{}},
+	}
+	config := readProbeConfigFor(query)
+	if !strings.HasPrefix(config.url, "http://"+QueryNameFromParent("test")+".ns.svc:") {
+		t.Errorf("expected the querier to be probed, got %s", config.url)
+	}
+	if config.interval != time.Minute || config.queryRange != time.Hour {
+		t.Errorf("expected default interval and range, got %s and %s", config.interval, config.queryRange)
+	}
+
+	query.Spec.QueryFrontend = &v1alpha1.QueryFrontendSpec{}
+	config = readProbeConfigFor(query)
+	if !strings.HasPrefix(config.url, "http://"+QueryFrontendNameFromParent("test")+".ns.svc:") {
+		t.Errorf("expected the query frontend to be probed, got %s", config.url)
+	}
+}
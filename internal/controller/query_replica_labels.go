@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	monitoringthanosiov1alpha1 "github.com/thanos-community/thanos-operator/api/v1alpha1"
+	manifestreceive "github.com/thanos-community/thanos-operator/internal/pkg/manifests/receive"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// receiveReplicaLabels returns the replica labels of a ThanosReceive, which are the external labels of its ingesters
+// whose values are derived from the pod name.
+func receiveReplicaLabels(receiver monitoringthanosiov1alpha1.ThanosReceive) []string {
+	var labels []string
+	for _, hashring := range receiver.Spec.Ingester.Hashrings {
+		for name, value := range manifestreceive.IngesterExternalLabels(hashring.Name, hashring.ExternalLabels) {
+			if manifestreceive.IsReplicaLabelValue(value) && !slices.Contains(labels, name) {
+				labels = append(labels, name)
+			}
+		}
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// discoverReplicaLabels returns the replica labels of the ThanosReceive resources in the namespace, named receivers,
+// that are not already in replicaLabels. ThanosReceive resources that do not exist are ignored.
+func (r *ThanosQueryReconciler) discoverReplicaLabels(ctx context.Context, namespace string, receivers, replicaLabels []string) ([]string, error) {
+	var discovered []string
+	for _, name := range receivers {
+		receiver := &monitoringthanosiov1alpha1.ThanosReceive{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, receiver); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get ThanosReceive %s: %w", name, err)
+		}
+		for _, label := range receiveReplicaLabels(*receiver) {
+			if !slices.Contains(replicaLabels, label) && !slices.Contains(discovered, label) {
+				discovered = append(discovered, label)
+			}
+		}
+	}
+	return discovered, nil
+}
+
+// enqueueForReceive returns an EventHandler that enqueues the ThanosQuery resources in the namespace of a ThanosReceive,
+// so that they pick up changes to its replica labels.
+func (r *ThanosQueryReconciler) enqueueForReceive() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+		queries := &monitoringthanosiov1alpha1.ThanosQueryList{}
+		if err := r.List(ctx, queries, client.InNamespace(obj.GetNamespace())); err != nil {
+			r.logger.Error(err, "failed to list ThanosQuery resources", "namespace", obj.GetNamespace())
+			return nil
+		}
+		requests := make([]reconcile.Request, 0, len(queries.Items))
+		for _, query := range queries.Items {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&query)})
+		}
+		return requests
+	})
+}
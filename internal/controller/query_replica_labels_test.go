@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/go-logr/logr"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDiscoverReplicaLabels(t *testing.T) {
+	receiver := func(name string, labels ...v1alpha1.ExternalLabels) *v1alpha1.ThanosReceive {
+		r := &v1alpha1.ThanosReceive{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"}}
+		for _, l := range labels {
+			r.Spec.Ingester.Hashrings = append(r.Spec.Ingester.Hashrings, v1alpha1.IngesterHashringSpec{Name: name, ExternalLabels: l})
+		}
+		return r
+	}
+
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		receiver("a", v1alpha1.ExternalLabels{"replica": "$(POD_NAME)"}, v1alpha1.ExternalLabels{"replica": "$(POD_NAME)", "region": "eu"}),
+		receiver("b", v1alpha1.ExternalLabels{"receive_replica": "ingester-$(POD_NAME)"}),
+		receiver("c", v1alpha1.ExternalLabels{"region": "eu"}),
+	).Build()
+	r := &ThanosQueryReconciler{Client: c, logger: logr.Discard()}
+
+	for _, tc := range []struct {
+		name          string
+		receivers     []string
+		replicaLabels []string
+		want          []string
+	}{
+		{name: "labels derived from the pod name", receivers: []string{"a"}, want: []string{"replica"}},
+		{name: "labels of all receivers", receivers: []string{"a", "b"}, want: []string{"replica", "receive_replica"}},
+		{name: "labels already configured are skipped", receivers: []string{"a", "b"}, replicaLabels: []string{"replica"}, want: []string{"receive_replica"}},
+		{name: "injected replica label", receivers: []string{"c"}, want: []string{"receive_replica"}},
+		{name: "missing receivers are ignored", receivers: []string{"missing"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := r.discoverReplicaLabels(context.Background(), "ns", tc.receivers, tc.replicaLabels)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("discoverReplicaLabels() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
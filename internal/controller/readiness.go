@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// readinessConditions returns the Ready, Reconciling and Stalled conditions for the outcome of a reconcile.
+// The outcome is the condition recorded at the end of the reconcile, one of Paused, DependencyMissing,
+// ReconcileFailed or ReconcileSuccess. After a successful reconcile, the resource is only ready once the
+// Available and Progressing conditions, if set, report that the workloads are rolled out and available.
+//
+// Ready follows the convention of GitOps tools such as Flux and Argo CD, while Reconciling and Stalled
+// are the abnormal-true conditions read by kstatus.
+func readinessConditions(outcome metav1.Condition, conditions []metav1.Condition) []metav1.Condition {
+	ready := metav1.Condition{
+		Type:    ConditionReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  outcome.Reason,
+		Message: outcome.Message,
+	}
+	reconciling := metav1.Condition{
+		Type:    ConditionReconciling,
+		Status:  metav1.ConditionFalse,
+		Reason:  outcome.Reason,
+		Message: outcome.Message,
+	}
+	stalled := metav1.Condition{
+		Type:    ConditionStalled,
+		Status:  metav1.ConditionFalse,
+		Reason:  outcome.Reason,
+		Message: outcome.Message,
+	}
+
+	switch outcome.Type {
+	case ConditionPaused:
+	case ConditionDependencyMissing:
+		// the reconcile is retried until the dependency exists
+		reconciling.Status = metav1.ConditionTrue
+	case ConditionReconcileFailed:
+		stalled.Status = metav1.ConditionTrue
+	case ConditionReconcileSuccess:
+		if progressing := meta.FindStatusCondition(conditions, ConditionProgressing); progressing != nil && progressing.Status == metav1.ConditionTrue {
+			ready.Reason, ready.Message = progressing.Reason, progressing.Message
+			reconciling.Status, reconciling.Reason, reconciling.Message = metav1.ConditionTrue, progressing.Reason, progressing.Message
+			break
+		}
+		if available := meta.FindStatusCondition(conditions, ConditionAvailable); available != nil && available.Status != metav1.ConditionTrue {
+			ready.Reason, ready.Message = available.Reason, available.Message
+			reconciling.Status, reconciling.Reason, reconciling.Message = metav1.ConditionTrue, available.Reason, available.Message
+			break
+		}
+		ready.Status = metav1.ConditionTrue
+	default:
+		return nil
+	}
+
+	return []metav1.Condition{ready, reconciling, stalled}
+}
+
+// setReadinessConditions sets the Ready, Reconciling and Stalled conditions for the outcome of a reconcile.
+func setReadinessConditions(conditions *[]metav1.Condition, outcome metav1.Condition) {
+	for _, condition := range readinessConditions(outcome, *conditions) {
+		meta.SetStatusCondition(conditions, condition)
+	}
+}
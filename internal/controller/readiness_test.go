@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReadinessConditions(t *testing.T) {
+	success := metav1.Condition{Type: ConditionReconcileSuccess, Status: metav1.ConditionTrue, Reason: ReasonReconcileComplete}
+	rolledOut := []metav1.Condition{
+		{Type: ConditionAvailable, Status: metav1.ConditionTrue, Reason: ReasonMinimumReplicasAvailable},
+		{Type: ConditionProgressing, Status: metav1.ConditionFalse, Reason: ReasonRolloutComplete},
+	}
+
+	for _, tc := range []struct {
+		name              string
+		outcome           metav1.Condition
+		conditions        []metav1.Condition
+		expectReady       metav1.ConditionStatus
+		expectReason      string
+		expectReconciling metav1.ConditionStatus
+		expectStalled     metav1.ConditionStatus
+	}{
+		{
+			name:              "success without rollout conditions",
+			outcome:           success,
+			expectReady:       metav1.ConditionTrue,
+			expectReason:      ReasonReconcileComplete,
+			expectReconciling: metav1.ConditionFalse,
+			expectStalled:     metav1.ConditionFalse,
+		},
+		{
+			name:              "success and rolled out",
+			outcome:           success,
+			conditions:        rolledOut,
+			expectReady:       metav1.ConditionTrue,
+			expectReason:      ReasonReconcileComplete,
+			expectReconciling: metav1.ConditionFalse,
+			expectStalled:     metav1.ConditionFalse,
+		},
+		{
+			name:    "success while rolling out",
+			outcome: success,
+			conditions: []metav1.Condition{
+				{Type: ConditionAvailable, Status: metav1.ConditionTrue, Reason: ReasonMinimumReplicasAvailable},
+				{Type: ConditionProgressing, Status: metav1.ConditionTrue, Reason: ReasonRollingOut},
+			},
+			expectReady:       metav1.ConditionFalse,
+			expectReason:      ReasonRollingOut,
+			expectReconciling: metav1.ConditionTrue,
+			expectStalled:     metav1.ConditionFalse,
+		},
+		{
+			name:    "success without available replicas",
+			outcome: success,
+			conditions: []metav1.Condition{
+				{Type: ConditionAvailable, Status: metav1.ConditionFalse, Reason: ReasonReplicasUnavailable},
+				{Type: ConditionProgressing, Status: metav1.ConditionFalse, Reason: ReasonRolloutComplete},
+			},
+			expectReady:       metav1.ConditionFalse,
+			expectReason:      ReasonReplicasUnavailable,
+			expectReconciling: metav1.ConditionTrue,
+			expectStalled:     metav1.ConditionFalse,
+		},
+		{
+			name:              "missing dependency",
+			outcome:           metav1.Condition{Type: ConditionDependencyMissing, Status: metav1.ConditionTrue, Reason: ReasonDependencyNotFound},
+			conditions:        rolledOut,
+			expectReady:       metav1.ConditionFalse,
+			expectReason:      ReasonDependencyNotFound,
+			expectReconciling: metav1.ConditionTrue,
+			expectStalled:     metav1.ConditionFalse,
+		},
+		{
+			name:              "failed",
+			outcome:           metav1.Condition{Type: ConditionReconcileFailed, Status: metav1.ConditionTrue, Reason: ReasonReconcileError},
+			conditions:        rolledOut,
+			expectReady:       metav1.ConditionFalse,
+			expectReason:      ReasonReconcileError,
+			expectReconciling: metav1.ConditionFalse,
+			expectStalled:     metav1.ConditionTrue,
+		},
+		{
+			name:              "paused",
+			outcome:           metav1.Condition{Type: ConditionPaused, Status: metav1.ConditionTrue, Reason: ReasonPaused},
+			conditions:        rolledOut,
+			expectReady:       metav1.ConditionFalse,
+			expectReason:      ReasonPaused,
+			expectReconciling: metav1.ConditionFalse,
+			expectStalled:     metav1.ConditionFalse,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			conditions := append([]metav1.Condition{}, tc.conditions...)
+			setReadinessConditions(&conditions, tc.outcome)
+
+			ready := meta.FindStatusCondition(conditions, ConditionReady)
+			if ready == nil || ready.Status != tc.expectReady || ready.Reason != tc.expectReason {
+				t.Errorf("expected Ready %s with reason %s, got %+v", tc.expectReady, tc.expectReason, ready)
+			}
+			if c := meta.FindStatusCondition(conditions, ConditionReconciling); c == nil || c.Status != tc.expectReconciling {
+				t.Errorf("expected Reconciling %s, got %+v", tc.expectReconciling, c)
+			}
+			if c := meta.FindStatusCondition(conditions, ConditionStalled); c == nil || c.Status != tc.expectStalled {
+				t.Errorf("expected Stalled %s, got %+v", tc.expectStalled, c)
+			}
+		})
+	}
+
+	t.Run("other conditions are ignored", func(t *testing.T) {
+		var conditions []metav1.Condition
+		setReadinessConditions(&conditions, metav1.Condition{Type: ConditionReplicationDegraded, Status: metav1.ConditionTrue})
+		if len(conditions) != 0 {
+			t.Errorf("expected no conditions, got %+v", conditions)
+		}
+	})
+}
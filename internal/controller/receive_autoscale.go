@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	monitoringthanosiov1alpha1 "github.com/thanos-community/thanos-operator/api/v1alpha1"
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// defaultHashringConfigDebounce is the time new ingesters of an autoscaled hashring wait to join the hashring
+// configuration when no debounce is set.
+const defaultHashringConfigDebounce = time.Minute
+
+// hashringAutoscale is the state of an autoscaled hashring.
+type hashringAutoscale struct {
+	// replicas is the number of replicas to keep in the StatefulSet.
+	replicas int32
+	// configReplicas is the number of ingesters published in the hashring configuration.
+	configReplicas int32
+	// since is when the replicas started to differ from configReplicas. It is zero once they converged.
+	since time.Time
+	// remaining is the time left until the hashring configuration converges to the replicas.
+	remaining time.Duration
+}
+
+// nextHashringAutoscale returns the state of an autoscaled hashring whose StatefulSet has current replicas and whose
+// configuration was converged to configured replicas. The replicas are kept within the bounds of the hashring.
+// Ingesters added by a scale up only join the hashring configuration once the debounce has passed since the scale up,
+// while the configuration follows a scale down straight away since the removed ingesters can no longer receive writes.
+func nextHashringAutoscale(current, configured int32, since time.Time, spec monitoringthanosiov1alpha1.HashringAutoscalingSpec, debounce time.Duration, now time.Time) hashringAutoscale {
+	replicas := min(max(current, spec.MinReplicas), spec.MaxReplicas)
+	if configured <= 0 || replicas <= configured || debounce <= 0 {
+		return hashringAutoscale{replicas: replicas, configReplicas: replicas}
+	}
+	if since.IsZero() {
+		since = now
+	}
+	remaining := since.Add(debounce).Sub(now)
+	if remaining <= 0 {
+		return hashringAutoscale{replicas: replicas, configReplicas: replicas}
+	}
+	return hashringAutoscale{replicas: replicas, configReplicas: configured, since: since, remaining: remaining}
+}
+
+// autoscaleHashrings returns the state of the autoscaled hashrings of the ThanosReceive, by hashring name.
+// The replicas the hashring configuration converged to and the start of a pending scale up are recorded on the
+// StatefulSet so that they survive operator restarts.
+func (r *ThanosReceiveReconciler) autoscaleHashrings(ctx context.Context, cluster targetCluster, receiver monitoringthanosiov1alpha1.ThanosReceive) (map[string]hashringAutoscale, error) {
+	states := make(map[string]hashringAutoscale)
+	for _, hashring := range receiver.Spec.Ingester.Hashrings {
+		if hashring.Autoscaling == nil {
+			continue
+		}
+
+		name := ReceiveIngesterNameFromParent(receiver.GetName(), hashring.Name)
+		sts := &appsv1.StatefulSet{}
+		if err := cluster.client.Get(ctx, client.ObjectKey{Namespace: receiver.GetNamespace(), Name: name}, sts); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to get statefulset %s: %w", name, err)
+			}
+			states[hashring.Name] = nextHashringAutoscale(hashring.Replicas, 0, time.Time{}, *hashring.Autoscaling, 0, time.Now())
+			continue
+		}
+
+		annotations := sts.GetAnnotations()
+		configured, _ := strconv.ParseInt(annotations[manifests.HashringReplicasAnnotation], 10, 32)
+		since, _ := time.Parse(time.RFC3339, annotations[manifests.HashringReplicasSinceAnnotation])
+		debounce := parseDurationOr(hashring.Autoscaling.ConfigDebounce, defaultHashringConfigDebounce)
+		state := nextHashringAutoscale(ptr.Deref(sts.Spec.Replicas, 1), int32(configured), since, *hashring.Autoscaling, debounce, time.Now())
+
+		if state.configReplicas != int32(configured) || !state.since.Equal(since) {
+			patch := client.MergeFrom(sts.DeepCopy())
+			annotations = manifests.MergeMaps(annotations, map[string]string{
+				manifests.HashringReplicasAnnotation: strconv.Itoa(int(state.configReplicas)),
+			})
+			if state.since.IsZero() {
+				delete(annotations, manifests.HashringReplicasSinceAnnotation)
+			} else {
+				annotations[manifests.HashringReplicasSinceAnnotation] = state.since.UTC().Format(time.RFC3339)
+			}
+			sts.SetAnnotations(annotations)
+			if err := cluster.client.Patch(ctx, sts, patch); err != nil {
+				return nil, fmt.Errorf("failed to record replicas of statefulset %s: %w", name, err)
+			}
+
+			if configured > 0 && state.configReplicas != int32(configured) {
+				r.recorder.Eventf(&receiver, nil, corev1.EventTypeNormal, "HashringAutoscaled", "Reconcile",
+					"Converged the configuration of hashring %s from %d to %d replicas", hashring.Name, configured, state.configReplicas)
+			}
+		}
+
+		r.pendingDeletions.record(types.NamespacedName{Namespace: receiver.GetNamespace(), Name: receiver.GetName()}, state.remaining)
+		states[hashring.Name] = state
+	}
+	return states, nil
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	monitoringthanosiov1alpha1 "github.com/thanos-community/thanos-operator/api/v1alpha1"
+)
+
+func TestNextHashringAutoscale(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	bounds := monitoringthanosiov1alpha1.HashringAutoscalingSpec{MinReplicas: 2, MaxReplicas: 6}
+
+	for _, tc := range []struct {
+		name                string
+		current, configured int32
+		since               time.Time
+		expect              hashringAutoscale
+	}{
+		{
+			name:    "new hashring",
+			current: 3,
+			expect:  hashringAutoscale{replicas: 3, configReplicas: 3},
+		},
+		{
+			name:    "new hashring below bounds",
+			current: 1,
+			expect:  hashringAutoscale{replicas: 2, configReplicas: 2},
+		},
+		{
+			name:       "above bounds",
+			current:    8,
+			configured: 6,
+			expect:     hashringAutoscale{replicas: 6, configReplicas: 6},
+		},
+		{
+			name:       "converged",
+			current:    4,
+			configured: 4,
+			expect:     hashringAutoscale{replicas: 4, configReplicas: 4},
+		},
+		{
+			name:       "scale up starts",
+			current:    5,
+			configured: 3,
+			expect:     hashringAutoscale{replicas: 5, configReplicas: 3, since: now, remaining: time.Minute},
+		},
+		{
+			name:       "scale up in debounce",
+			current:    5,
+			configured: 3,
+			since:      now.Add(-40 * time.Second),
+			expect:     hashringAutoscale{replicas: 5, configReplicas: 3, since: now.Add(-40 * time.Second), remaining: 20 * time.Second},
+		},
+		{
+			name:       "scale up after debounce",
+			current:    5,
+			configured: 3,
+			since:      now.Add(-time.Minute),
+			expect:     hashringAutoscale{replicas: 5, configReplicas: 5},
+		},
+		{
+			name:       "scale down",
+			current:    3,
+			configured: 5,
+			since:      now.Add(-30 * time.Second),
+			expect:     hashringAutoscale{replicas: 3, configReplicas: 3},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := nextHashringAutoscale(tc.current, tc.configured, tc.since, bounds, time.Minute, now)
+			if got.replicas != tc.expect.replicas || got.configReplicas != tc.expect.configReplicas || !got.since.Equal(tc.expect.since) || got.remaining != tc.expect.remaining {
+				t.Errorf("expected %+v, got %+v", tc.expect, got)
+			}
+		})
+	}
+}
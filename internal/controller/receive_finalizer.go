@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	monitoringthanosiov1alpha1 "github.com/thanos-community/thanos-operator/api/v1alpha1"
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+	manifestreceive "github.com/thanos-community/thanos-operator/internal/pkg/manifests/receive"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// finalizeRequeueInterval is the interval at which a ThanosReceive being deleted is requeued
+// while it waits for its ingesters to terminate.
+const finalizeRequeueInterval = 10 * time.Second
+
+// finalize performs the cleanup of a ThanosReceive that is being deleted. It flushes the ingesters if configured,
+// deletes their data volumes if the retention policy allows it and, in a workload cluster where child resources
+// are not garbage collected, deletes the child resources. It returns true once the finalizer can be removed.
+func (r *ThanosReceiveReconciler) finalize(ctx context.Context, cluster targetCluster, receiver monitoringthanosiov1alpha1.ThanosReceive) (bool, error) {
+	ns := receiver.GetNamespace()
+	ingesters := []client.ListOption{
+		manifests.GetLabelSelectorForOwner(manifestreceive.IngesterOptions{Options: manifests.Options{Owner: receiver.GetName()}}),
+		client.InNamespace(ns),
+	}
+	deletion := ptr.Deref(receiver.Spec.Deletion, monitoringthanosiov1alpha1.ReceiveDeletionSpec{})
+
+	if ptr.Deref(deletion.FlushIngesters, false) {
+		terminated, err := scaleDownIngesters(ctx, cluster.client, ingesters)
+		if err != nil {
+			return false, err
+		}
+		if !terminated {
+			r.logger.V(1).Info("waiting for the ingesters to flush", "resource", receiver.GetName(), "namespace", ns)
+			return false, nil
+		}
+	}
+
+	if ptr.Deref(deletion.VolumeRetentionPolicy, monitoringthanosiov1alpha1.VolumeRetentionPolicyRetain) == monitoringthanosiov1alpha1.VolumeRetentionPolicyDelete {
+		claims := &corev1.PersistentVolumeClaimList{}
+		if err := cluster.client.List(ctx, claims, ingesters...); err != nil {
+			return false, fmt.Errorf("failed to list the data volumes of the ingesters: %w", err)
+		}
+		// claims of running ingesters are protected and only removed once the ingesters have terminated
+		for i := range claims.Items {
+			if err := cluster.client.Delete(ctx, &claims.Items[i]); client.IgnoreNotFound(err) != nil {
+				return false, fmt.Errorf("failed to delete the data volume %s: %w", claims.Items[i].GetName(), err)
+			}
+		}
+	}
+
+	if cluster.remote {
+		if errCount := r.deleteRemoteResources(ctx, cluster, receiver); errCount > 0 {
+			return false, fmt.Errorf("failed to delete %d resources in the target cluster", errCount)
+		}
+	}
+	return true, nil
+}
+
+// scaleDownIngesters scales the ingester StatefulSets down to zero and returns true once all ingester pods are gone.
+func scaleDownIngesters(ctx context.Context, c client.Client, ingesters []client.ListOption) (bool, error) {
+	statefulSets := &appsv1.StatefulSetList{}
+	if err := c.List(ctx, statefulSets, ingesters...); err != nil {
+		return false, fmt.Errorf("failed to list the ingesters: %w", err)
+	}
+	for i := range statefulSets.Items {
+		sts := &statefulSets.Items[i]
+		if ptr.Deref(sts.Spec.Replicas, 1) == 0 {
+			continue
+		}
+		patch := client.MergeFrom(sts.DeepCopy())
+		sts.Spec.Replicas = ptr.To(int32(0))
+		if err := c.Patch(ctx, sts, patch); err != nil {
+			return false, fmt.Errorf("failed to scale down statefulset %s: %w", sts.GetName(), err)
+		}
+	}
+
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, ingesters...); err != nil {
+		return false, fmt.Errorf("failed to list the ingester pods: %w", err)
+	}
+	return len(pods.Items) == 0, nil
+}
+
+// deleteRemoteResources deletes the child resources of a ThanosReceive managed in a workload cluster,
+// since owner references cannot cross clusters and they are not garbage collected with their owner.
+// It returns the number of errors encountered.
+func (r *ThanosReceiveReconciler) deleteRemoteResources(ctx context.Context, cluster targetCluster, receiver monitoringthanosiov1alpha1.ThanosReceive) int {
+	ns := receiver.GetNamespace()
+	owner := manifests.Options{Owner: receiver.GetName()}
+	routerName := ReceiveRouterNameFromParent(receiver.GetName())
+
+	var errCount int
+	for _, opts := range []manifests.Buildable{manifestreceive.IngesterOptions{Options: owner}, manifestreceive.RouterOptions{Options: owner}} {
+		pruner := cluster.handler.NewResourcePruner().
+			WithServiceAccount().WithService().WithStatefulSet().WithDeployment().WithConfigMap().
+			WithPodDisruptionBudget().WithNetworkPolicy()
+		if r.featureGate.ServiceMonitorEnabled() {
+			pruner = pruner.WithServiceMonitor()
+		}
+		if r.featureGate.MonitoringMixinEnabled() {
+			pruner = pruner.WithPrometheusRule()
+		}
+		errCount += pruner.Prune(ctx, nil, manifests.GetLabelSelectorForOwner(opts), client.InNamespace(ns))
+	}
+
+	meta := metav1.ObjectMeta{Name: routerName, Namespace: ns}
+	objs := []client.Object{&rbacv1.Role{ObjectMeta: meta}, &rbacv1.RoleBinding{ObjectMeta: meta}}
+	objs = append(objs, getUnusedIngresses(routerName, ns, nil)...)
+	objs = append(objs, &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: ReceiveWriteProbeNameFromParent(receiver.GetName()), Namespace: ns}})
+	return errCount + cluster.handler.DeleteResource(ctx, objs)
+}
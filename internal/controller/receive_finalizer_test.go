@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+	"github.com/thanos-community/thanos-operator/internal/pkg/handlers"
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+	manifestreceive "github.com/thanos-community/thanos-operator/internal/pkg/manifests/receive"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestFinalizeReceive(t *testing.T) {
+	ingester := manifestreceive.IngesterOptions{
+		Options:      manifests.Options{Owner: "test", Namespace: "ns", Replicas: 1},
+		HashringName: "default",
+	}
+	labels := manifestreceive.GetIngesterLabels(ingester)
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: ingester.GetGeneratedResourceName() + "-0", Namespace: "ns", Labels: labels}}
+	claim := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data-" + pod.GetName(), Namespace: "ns", Labels: labels}}
+
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(manifestreceive.NewIngestorStatefulSet(ingester), pod, claim).Build()
+	cluster := targetCluster{client: c, handler: handlers.NewHandler(c, scheme, logr.Discard())}
+	r := &ThanosReceiveReconciler{logger: logr.Discard()}
+
+	receiver := v1alpha1.ThanosReceive{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"},
+		Spec: v1alpha1.ThanosReceiveSpec{Deletion: &v1alpha1.ReceiveDeletionSpec{
+			FlushIngesters:        ptr.To(true),
+			VolumeRetentionPolicy: ptr.To(v1alpha1.VolumeRetentionPolicyDelete),
+		}},
+	}
+	done, err := r.finalize(context.Background(), cluster, receiver)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if done {
+		t.Fatal("expected the finalizer to wait for the ingesters to terminate")
+	}
+	sts := &appsv1.StatefulSet{}
+	if err := c.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: ingester.GetGeneratedResourceName()}, sts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ptr.Deref(sts.Spec.Replicas, 1) != 0 {
+		t.Errorf("expected the ingesters to be scaled down, got %d replicas", ptr.Deref(sts.Spec.Replicas, 1))
+	}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(claim), &corev1.PersistentVolumeClaim{}); err != nil {
+		t.Errorf("expected the data volume to be kept while the ingesters flush, got %v", err)
+	}
+
+	if err := c.Delete(context.Background(), pod); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	done, err = r.finalize(context.Background(), cluster, receiver)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !done {
+		t.Fatal("expected the finalizer to complete once the ingesters have terminated")
+	}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(claim), &corev1.PersistentVolumeClaim{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the data volume to be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// writeProbeResult is the result of the latest finished write probe Job.
+type writeProbeResult struct {
+	// finished is false if no write probe Job has finished yet.
+	finished  bool
+	succeeded bool
+	message   string
+	// lastSuccess is the completion time of the last successful write probe Job, if any.
+	lastSuccess *metav1.Time
+}
+
+// latestWriteProbeResult returns the result of the write probe Job that finished last.
+func latestWriteProbeResult(jobs []batchv1.Job) writeProbeResult {
+	var result writeProbeResult
+	var latest metav1.Time
+	for _, job := range jobs {
+		for _, c := range job.Status.Conditions {
+			if c.Status != corev1.ConditionTrue || (c.Type != batchv1.JobComplete && c.Type != batchv1.JobFailed) {
+				continue
+			}
+
+			if c.Type == batchv1.JobComplete && (result.lastSuccess == nil || result.lastSuccess.Before(&c.LastTransitionTime)) {
+				result.lastSuccess = &c.LastTransitionTime
+			}
+			if result.finished && !latest.Before(&c.LastTransitionTime) {
+				continue
+			}
+
+			latest = c.LastTransitionTime
+			result.finished = true
+			result.succeeded = c.Type == batchv1.JobComplete
+			result.message = fmt.Sprintf("Write probe job %s completed", job.GetName())
+			if !result.succeeded {
+				result.message = fmt.Sprintf("Write probe job %s failed: %s", job.GetName(), c.Message)
+			}
+		}
+	}
+	return result
+}
+
+// condition returns the WriteProbeSucceeded condition for the result.
+func (r writeProbeResult) condition() metav1.Condition {
+	switch {
+	case !r.finished:
+		return metav1.Condition{
+			Type:    ConditionWriteProbeSucceeded,
+			Status:  metav1.ConditionUnknown,
+			Reason:  ReasonProbePending,
+			Message: "Waiting for the first write probe to finish",
+		}
+	case r.succeeded:
+		return metav1.Condition{
+			Type:    ConditionWriteProbeSucceeded,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonProbeSucceeded,
+			Message: r.message,
+		}
+	default:
+		return metav1.Condition{
+			Type:    ConditionWriteProbeSucceeded,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonProbeFailed,
+			Message: r.message,
+		}
+	}
+}
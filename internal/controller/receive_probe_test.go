@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestLatestWriteProbeResult(t *testing.T) {
+	now := time.Now()
+	job := func(name string, condition batchv1.JobConditionType, finishedAgo time.Duration) batchv1.Job {
+		return batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: batchv1.JobStatus{
+				Conditions: []batchv1.JobCondition{
+					{
+						Type:               condition,
+						Status:             corev1.ConditionTrue,
+						LastTransitionTime: metav1.NewTime(now.Add(-finishedAgo)),
+						Message:            "deadline exceeded",
+					},
+				},
+			},
+		}
+	}
+	running := batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "running"}}
+
+	for _, tc := range []struct {
+		name              string
+		jobs              []batchv1.Job
+		expectStatus      metav1.ConditionStatus
+		expectReason      string
+		expectSuccess     bool
+		expectLastSuccess bool
+	}{
+		{
+			name:         "no jobs",
+			expectStatus: metav1.ConditionUnknown,
+			expectReason: ReasonProbePending,
+		},
+		{
+			name:         "only running jobs",
+			jobs:         []batchv1.Job{running},
+			expectStatus: metav1.ConditionUnknown,
+			expectReason: ReasonProbePending,
+		},
+		{
+			name:              "latest job succeeded",
+			jobs:              []batchv1.Job{job("failed", batchv1.JobFailed, 10*time.Minute), job("complete", batchv1.JobComplete, 5*time.Minute), running},
+			expectStatus:      metav1.ConditionTrue,
+			expectReason:      ReasonProbeSucceeded,
+			expectSuccess:     true,
+			expectLastSuccess: true,
+		},
+		{
+			name:              "latest job failed",
+			jobs:              []batchv1.Job{job("complete", batchv1.JobComplete, 10*time.Minute), job("failed", batchv1.JobFailed, 5*time.Minute)},
+			expectStatus:      metav1.ConditionFalse,
+			expectReason:      ReasonProbeFailed,
+			expectLastSuccess: true,
+		},
+		{
+			name:         "never succeeded",
+			jobs:         []batchv1.Job{job("failed", batchv1.JobFailed, 5*time.Minute)},
+			expectStatus: metav1.ConditionFalse,
+			expectReason: ReasonProbeFailed,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result := latestWriteProbeResult(tc.jobs)
+			condition := result.condition()
+			if condition.Status != tc.expectStatus || condition.Reason != tc.expectReason {
+				t.Errorf("expected %s/%s, got %s/%s", tc.expectStatus, tc.expectReason, condition.Status, condition.Reason)
+			}
+			if result.finished && result.succeeded != tc.expectSuccess {
+				t.Errorf("expected succeeded %v, got %v", tc.expectSuccess, result.succeeded)
+			}
+			if (result.lastSuccess != nil) != tc.expectLastSuccess {
+				t.Errorf("expected last success to be set: %v, got %v", tc.expectLastSuccess, result.lastSuccess)
+			}
+		})
+	}
+}
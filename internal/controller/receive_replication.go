@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	monitoringthanosiov1alpha1 "github.com/thanos-community/thanos-operator/api/v1alpha1"
+	manifestreceive "github.com/thanos-community/thanos-operator/internal/pkg/manifests/receive"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// hashringReplication is the replication state of a hashring observed when building the hashring configuration.
+type hashringReplication struct {
+	name          string
+	readyReplicas int32
+	replicas      int32
+}
+
+// receiveHashringState is the state of the hashrings observed while syncing a ThanosReceive.
+type receiveHashringState struct {
+	replication []hashringReplication
+	// configHash is the hash of the hashring configuration applied to the router.
+	configHash string
+	// configVersion is the version of the hashring configuration applied to the router and configUpdateTime
+	// when it last changed.
+	configVersion    int64
+	configUpdateTime time.Time
+	// rollout is the progress of a Sequential rollout across hashrings, nil if none is in progress.
+	rollout *monitoringthanosiov1alpha1.HashringRolloutStatus
+	// updateDelay is how long a change to the hashring configuration is held back by the hashring update interval,
+	// zero if no change is held back.
+	updateDelay time.Duration
+}
+
+// degradedHashrings returns a message with the exact counts for each hashring that has fewer ready replicas
+// than the replication factor. Writes to these hashrings can not be replicated to enough ingesters to succeed.
+func degradedHashrings(replicationFactor int32, hashrings []hashringReplication) []string {
+	var degraded []string
+	for _, h := range hashrings {
+		if h.readyReplicas >= replicationFactor {
+			continue
+		}
+		degraded = append(degraded, fmt.Sprintf("hashring %s has %d/%d ready replicas, below the replication factor of %d",
+			h.name, h.readyReplicas, h.replicas, replicationFactor))
+	}
+	return degraded
+}
+
+// replicationDegradedCondition returns the ReplicationDegraded condition for the messages returned by degradedHashrings.
+func replicationDegradedCondition(replicationFactor int32, degraded []string) metav1.Condition {
+	if len(degraded) == 0 {
+		return metav1.Condition{
+			Type:    ConditionReplicationDegraded,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonReplicationHealthy,
+			Message: fmt.Sprintf("All hashrings have at least %d ready replicas", replicationFactor),
+		}
+	}
+	return metav1.Condition{
+		Type:    ConditionReplicationDegraded,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonReadyReplicasBelowReplicationFactor,
+		Message: strings.Join(degraded, "; "),
+	}
+}
+
+// routerHashringConfigHash returns the hash of the hashring configuration the routers have rolled out with, given
+// the hash previously recorded in the status. The hash on the pod template of the routers is in effect once their
+// rollout completes, and the previous hash is kept while they roll out. It is empty unless the routers are rolled
+// out on hashring configuration changes, or while no router runs.
+func routerHashringConfigHash(receiver monitoringthanosiov1alpha1.ThanosReceive, router *appsv1.Deployment, previous string) string {
+	reload := ptr.Deref(receiver.Spec.Router.HashringConfigReload, monitoringthanosiov1alpha1.HashringConfigReloadWatch)
+	if reload != monitoringthanosiov1alpha1.HashringConfigReloadRollout || router == nil {
+		return ""
+	}
+	rollout := deploymentRollout("", router)
+	if rollout.desiredReplicas == 0 {
+		return ""
+	}
+	if rollout.progressing() {
+		return previous
+	}
+	return router.Spec.Template.Annotations[manifestreceive.HashringConfigHashAnnotation]
+}
@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"testing"
+
+	monitoringthanosiov1alpha1 "github.com/thanos-community/thanos-operator/api/v1alpha1"
+	manifestreceive "github.com/thanos-community/thanos-operator/internal/pkg/manifests/receive"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestReplicationDegraded(t *testing.T) {
+	hashrings := []hashringReplication{
+		{name: "healthy", readyReplicas: 3, replicas: 3},
+		{name: "tolerable", readyReplicas: 3, replicas: 4},
+		{name: "degraded", readyReplicas: 2, replicas: 3},
+		{name: "down", readyReplicas: 0, replicas: 3},
+	}
+
+	degraded := degradedHashrings(3, hashrings)
+	expect := []string{
+		"hashring degraded has 2/3 ready replicas, below the replication factor of 3",
+		"hashring down has 0/3 ready replicas, below the replication factor of 3",
+	}
+	if len(degraded) != len(expect) {
+		t.Fatalf("expected %d degraded hashrings, got %v", len(expect), degraded)
+	}
+	for i := range expect {
+		if degraded[i] != expect[i] {
+			t.Errorf("expected %q, got %q", expect[i], degraded[i])
+		}
+	}
+
+	cond := replicationDegradedCondition(3, degraded)
+	if cond.Status != metav1.ConditionTrue || cond.Reason != ReasonReadyReplicasBelowReplicationFactor {
+		t.Errorf("expected degraded condition, got %s/%s", cond.Status, cond.Reason)
+	}
+	if cond.Message != expect[0]+"; "+expect[1] {
+		t.Errorf("unexpected message %q", cond.Message)
+	}
+
+	cond = replicationDegradedCondition(1, degradedHashrings(1, hashrings[:2]))
+	if cond.Status != metav1.ConditionFalse || cond.Reason != ReasonReplicationHealthy {
+		t.Errorf("expected healthy condition, got %s/%s", cond.Status, cond.Reason)
+	}
+}
+
+func TestRouterHashringConfigHash(t *testing.T) {
+	receiver := monitoringthanosiov1alpha1.ThanosReceive{}
+	router := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Generation: 2},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To(int32(2)),
+			Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{manifestreceive.HashringConfigHashAnnotation: "new"},
+			}},
+		},
+		Status: appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 1},
+	}
+
+	if got := routerHashringConfigHash(receiver, router, "old"); got != "" {
+		t.Errorf("expected no hash when the routers watch the configuration, got %q", got)
+	}
+
+	receiver.Spec.Router.HashringConfigReload = ptr.To(monitoringthanosiov1alpha1.HashringConfigReloadRollout)
+	if got := routerHashringConfigHash(receiver, router, "old"); got != "old" {
+		t.Errorf("expected the previous hash while the routers roll out, got %q", got)
+	}
+
+	router.Status = appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2}
+	if got := routerHashringConfigHash(receiver, router, "old"); got != "new" {
+		t.Errorf("expected the hash of the pod template once the routers rolled out, got %q", got)
+	}
+
+	router.Spec.Replicas = ptr.To(int32(0))
+	if got := routerHashringConfigHash(receiver, router, "old"); got != "" {
+		t.Errorf("expected no hash while no router runs, got %q", got)
+	}
+}
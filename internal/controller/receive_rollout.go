@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	monitoringthanosiov1alpha1 "github.com/thanos-community/thanos-operator/api/v1alpha1"
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+	"github.com/thanos-community/thanos-operator/internal/pkg/receive"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// hashringRollout tracks the rollout of pod template changes across the hashrings of a ThanosReceive during a reconcile.
+// With the Sequential strategy, a hashring only rolls out its new pod template once the hashrings before it in the spec
+// have settled, and keeps its current pod template until then.
+type hashringRollout struct {
+	sequential bool
+	paused     bool
+	// current is the hashring rolling out, which holds back the hashrings after it.
+	current string
+	// pending are the hashrings holding back their new pod template.
+	pending []string
+}
+
+func newHashringRollout(spec *monitoringthanosiov1alpha1.HashringRolloutSpec) *hashringRollout {
+	if spec == nil {
+		return &hashringRollout{}
+	}
+	return &hashringRollout{
+		sequential: spec.Strategy == monitoringthanosiov1alpha1.HashringRolloutStrategySequential,
+		paused:     spec.Paused,
+	}
+}
+
+// next returns true if the hashring must keep its current pod template.
+// changed is true if the applied pod template differs from the desired one, and settled is true if
+// all the ingesters of the hashring are updated, ready and part of the hashring configuration.
+func (h *hashringRollout) next(name string, changed, settled bool) bool {
+	if !h.sequential {
+		return false
+	}
+	if changed && (h.current != "" || h.paused) {
+		h.pending = append(h.pending, name)
+		return true
+	}
+	if (changed || !settled) && h.current == "" {
+		h.current = name
+	}
+	return false
+}
+
+// status returns the progress of the rollout, or nil if no hashring is waiting to roll out.
+func (h *hashringRollout) status() *monitoringthanosiov1alpha1.HashringRolloutStatus {
+	if len(h.pending) == 0 {
+		return nil
+	}
+	return &monitoringthanosiov1alpha1.HashringRolloutStatus{
+		Hashring: h.current,
+		Pending:  h.pending,
+		Paused:   h.paused,
+	}
+}
+
+// pendingRolloutCondition reports the hashrings waiting to roll out on the Progressing condition,
+// since their workloads are rolled out with their previous pod template.
+func pendingRolloutCondition(condition metav1.Condition, rollout monitoringthanosiov1alpha1.HashringRolloutStatus) metav1.Condition {
+	message := fmt.Sprintf("hashrings %s are waiting to roll out", strings.Join(rollout.Pending, ", "))
+	if rollout.Paused {
+		message = fmt.Sprintf("rollout is paused, hashrings %s are waiting to roll out", strings.Join(rollout.Pending, ", "))
+	}
+	if condition.Status == metav1.ConditionTrue {
+		condition.Message = condition.Message + "; " + message
+		return condition
+	}
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = ReasonRollingOut
+	condition.Message = message
+	return condition
+}
+
+// podTemplateHash returns the hash of the pod template, which is recorded on the StatefulSet to detect changes
+// without comparing with the pod template defaulted by the API server.
+func podTemplateHash(template corev1.PodTemplateSpec) (string, error) {
+	b, err := json.Marshal(template)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])[:16], nil
+}
+
+// rolloutIngester records the hash of the desired pod template on the StatefulSet of the hashring, and replaces
+// the desired pod template with the current one when the hashring must wait for its turn to roll out.
+func (r *ThanosReceiveReconciler) rolloutIngester(
+	ctx context.Context,
+	cluster targetCluster,
+	receiver monitoringthanosiov1alpha1.ThanosReceive,
+	hashring monitoringthanosiov1alpha1.IngesterHashringSpec,
+	applied receive.Hashrings,
+	rollout *hashringRollout,
+	objs []client.Object,
+) error {
+	var desired *appsv1.StatefulSet
+	for _, obj := range objs {
+		if sts, ok := obj.(*appsv1.StatefulSet); ok {
+			desired = sts
+		}
+	}
+	if desired == nil {
+		return nil
+	}
+
+	hash, err := podTemplateHash(desired.Spec.Template)
+	if err != nil {
+		return fmt.Errorf("failed to hash pod template of hashring %s: %w", hashring.Name, err)
+	}
+	desired.SetAnnotations(manifests.MergeMaps(desired.GetAnnotations(), map[string]string{manifests.PodTemplateHashAnnotation: hash}))
+
+	current := &appsv1.StatefulSet{}
+	found, err := getWorkload(ctx, cluster.client, receiver.GetNamespace(), desired.GetName(), current)
+	if err != nil || !found {
+		// new hashrings are created straight away
+		return err
+	}
+
+	replicas := hashring.Replicas
+	if hashring.Autoscaling != nil {
+		replicas = ptr.Deref(current.Spec.Replicas, 1)
+	}
+	changed := current.GetAnnotations()[manifests.PodTemplateHashAnnotation] != hash
+	settled := !statefulSetRollout(desired.GetName(), current).progressing() &&
+		current.Status.ReadyReplicas >= ptr.Deref(current.Spec.Replicas, 1) &&
+		hashringJoined(applied, hashring.Name, int(replicas))
+
+	if rollout.next(hashring.Name, changed, settled) {
+		desired.Spec.Template = current.Spec.Template
+		annotations := desired.GetAnnotations()
+		if currentHash, ok := current.GetAnnotations()[manifests.PodTemplateHashAnnotation]; ok {
+			annotations[manifests.PodTemplateHashAnnotation] = currentHash
+		} else {
+			delete(annotations, manifests.PodTemplateHashAnnotation)
+		}
+		desired.SetAnnotations(annotations)
+		return nil
+	}
+
+	// StatefulSets rolled out before their pod template was recorded are adopted without an event
+	if _, recorded := current.GetAnnotations()[manifests.PodTemplateHashAnnotation]; changed && recorded && rollout.sequential {
+		r.recorder.Eventf(&receiver, nil, corev1.EventTypeNormal, "HashringRolloutStarted", "Reconcile",
+			"Rolling out the ingesters of hashring %s", hashring.Name)
+	}
+	return nil
+}
+
+// hashringJoined returns true if the hashring configuration applied to the router holds at least replicas endpoints
+// for the hashring.
+func hashringJoined(applied receive.Hashrings, name string, replicas int) bool {
+	if replicas == 0 {
+		return true
+	}
+	for _, h := range applied {
+		if h.Name == name {
+			return len(h.Endpoints) >= replicas
+		}
+	}
+	return false
+}
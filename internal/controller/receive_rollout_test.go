@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/go-logr/logr"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+	"github.com/thanos-community/thanos-operator/internal/pkg/handlers"
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+	"github.com/thanos-community/thanos-operator/internal/pkg/receive"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestHashringRollout(t *testing.T) {
+	type hashring struct {
+		name             string
+		changed, settled bool
+	}
+	for _, tc := range []struct {
+		name        string
+		spec        *v1alpha1.HashringRolloutSpec
+		hashrings   []hashring
+		expectHold  []string
+		expectState *v1alpha1.HashringRolloutStatus
+	}{
+		{
+			name:      "parallel rolls out all hashrings",
+			hashrings: []hashring{{name: "a", changed: true}, {name: "b", changed: true}},
+		},
+		{
+			name:        "sequential rolls out the first changed hashring",
+			spec:        &v1alpha1.HashringRolloutSpec{Strategy: v1alpha1.HashringRolloutStrategySequential},
+			hashrings:   []hashring{{name: "a", settled: true}, {name: "b", changed: true}, {name: "c", changed: true}},
+			expectHold:  []string{"c"},
+			expectState: &v1alpha1.HashringRolloutStatus{Hashring: "b", Pending: []string{"c"}},
+		},
+		{
+			name:        "sequential waits for the rolling hashring to settle",
+			spec:        &v1alpha1.HashringRolloutSpec{Strategy: v1alpha1.HashringRolloutStrategySequential},
+			hashrings:   []hashring{{name: "a"}, {name: "b", changed: true}, {name: "c", changed: true}},
+			expectHold:  []string{"b", "c"},
+			expectState: &v1alpha1.HashringRolloutStatus{Hashring: "a", Pending: []string{"b", "c"}},
+		},
+		{
+			name:      "sequential without changes",
+			spec:      &v1alpha1.HashringRolloutSpec{Strategy: v1alpha1.HashringRolloutStrategySequential},
+			hashrings: []hashring{{name: "a", settled: true}, {name: "b"}},
+		},
+		{
+			name:        "paused holds every changed hashring",
+			spec:        &v1alpha1.HashringRolloutSpec{Strategy: v1alpha1.HashringRolloutStrategySequential, Paused: true},
+			hashrings:   []hashring{{name: "a", changed: true}, {name: "b", settled: true}, {name: "c", changed: true}},
+			expectHold:  []string{"a", "c"},
+			expectState: &v1alpha1.HashringRolloutStatus{Pending: []string{"a", "c"}, Paused: true},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rollout := newHashringRollout(tc.spec)
+			var held []string
+			for _, h := range tc.hashrings {
+				if rollout.next(h.name, h.changed, h.settled) {
+					held = append(held, h.name)
+				}
+			}
+			if !slices.Equal(held, tc.expectHold) {
+				t.Errorf("expected hashrings %v to be held, got %v", tc.expectHold, held)
+			}
+			state := rollout.status()
+			if (state == nil) != (tc.expectState == nil) {
+				t.Fatalf("expected rollout status %v, got %v", tc.expectState, state)
+			}
+			if state != nil && (state.Hashring != tc.expectState.Hashring || state.Paused != tc.expectState.Paused || !slices.Equal(state.Pending, tc.expectState.Pending)) {
+				t.Errorf("expected rollout status %v, got %v", tc.expectState, state)
+			}
+		})
+	}
+}
+
+func TestRolloutIngester(t *testing.T) {
+	newStatefulSet := func(name, image string, ready int32) *appsv1.StatefulSet {
+		sts := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", Generation: 1},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: ptr.To(int32(1)),
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "thanos-receive", Image: image}}},
+				},
+			},
+			Status: appsv1.StatefulSetStatus{
+				ObservedGeneration: 1,
+				Replicas:           1,
+				UpdatedReplicas:    1,
+				ReadyReplicas:      ready,
+			},
+		}
+		hash, err := podTemplateHash(sts.Spec.Template)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sts.SetAnnotations(map[string]string{manifests.PodTemplateHashAnnotation: hash})
+		return sts
+	}
+
+	receiver := v1alpha1.ThanosReceive{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"},
+		Spec: v1alpha1.ThanosReceiveSpec{
+			Ingester: v1alpha1.IngesterSpec{
+				Rollout: &v1alpha1.HashringRolloutSpec{Strategy: v1alpha1.HashringRolloutStrategySequential},
+				Hashrings: []v1alpha1.IngesterHashringSpec{
+					{Name: "a", Replicas: 1},
+					{Name: "b", Replicas: 1},
+				},
+			},
+		},
+	}
+	names := []string{
+		ReceiveIngesterNameFromParent(receiver.GetName(), "a"),
+		ReceiveIngesterNameFromParent(receiver.GetName(), "b"),
+	}
+	applied := receive.Hashrings{
+		{Name: "a", Endpoints: []receive.Endpoint{{Address: "a-0"}}},
+		{Name: "b", Endpoints: []receive.Endpoint{{Address: "b-0"}}},
+	}
+
+	scheme := runtime.NewScheme()
+	_ = appsv1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newStatefulSet(names[0], "thanos:v0.38.0", 1),
+		newStatefulSet(names[1], "thanos:v0.38.0", 1),
+	).Build()
+	cluster := targetCluster{client: c, handler: handlers.NewHandler(c, scheme, logr.Discard())}
+	r := &ThanosReceiveReconciler{logger: logr.Discard(), recorder: events.NewFakeRecorder(10)}
+
+	rollout := newHashringRollout(receiver.Spec.Ingester.Rollout)
+	desired := make([]*appsv1.StatefulSet, len(names))
+	for i, hashring := range receiver.Spec.Ingester.Hashrings {
+		desired[i] = newStatefulSet(names[i], "thanos:v0.39.0", 1)
+		desired[i].SetAnnotations(nil)
+		if err := r.rolloutIngester(context.Background(), cluster, receiver, hashring, applied, rollout, []client.Object{desired[i]}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if got := desired[0].Spec.Template.Spec.Containers[0].Image; got != "thanos:v0.39.0" {
+		t.Errorf("expected the first hashring to roll out, got image %s", got)
+	}
+	if got := desired[1].Spec.Template.Spec.Containers[0].Image; got != "thanos:v0.38.0" {
+		t.Errorf("expected the second hashring to keep its pod template, got image %s", got)
+	}
+	current := newStatefulSet(names[1], "thanos:v0.38.0", 1)
+	if got := desired[1].GetAnnotations()[manifests.PodTemplateHashAnnotation]; got != current.GetAnnotations()[manifests.PodTemplateHashAnnotation] {
+		t.Errorf("expected the second hashring to keep the hash of its current pod template, got %s", got)
+	}
+	if state := rollout.status(); state == nil || state.Hashring != "a" || !slices.Equal(state.Pending, []string{"b"}) {
+		t.Errorf("expected hashring a to roll out and b to be pending, got %v", state)
+	}
+}
+
+func TestHashringJoined(t *testing.T) {
+	applied := receive.Hashrings{{Name: "a", Endpoints: []receive.Endpoint{{Address: "a-0"}, {Address: "a-1"}}}}
+	if !hashringJoined(applied, "a", 2) {
+		t.Error("expected hashring with all its endpoints to have joined")
+	}
+	if hashringJoined(applied, "a", 3) {
+		t.Error("expected hashring missing an endpoint to not have joined")
+	}
+	if hashringJoined(applied, "b", 1) {
+		t.Error("expected hashring missing from the configuration to not have joined")
+	}
+	if !hashringJoined(applied, "b", 0) {
+		t.Error("expected hashring without replicas to have joined")
+	}
+}
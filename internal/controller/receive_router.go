@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"cmp"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	manifestreceive "github.com/thanos-community/thanos-operator/internal/pkg/manifests/receive"
+	"github.com/thanos-community/thanos-operator/internal/pkg/receive"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// holdRouter returns true if the router must be kept at zero replicas because no hashring has ready ingesters yet.
+// Routers cannot forward any write until then, so they are only brought up once the hashring configuration has
+// members, rather than failing every write and their readiness checks. Routers that are already up are left running,
+// and routers reading an existing hashring ConfigMap are never held back, as their hashrings are not known.
+func holdRouter(hashringConfig string, existingHashringConfigMap bool, router *appsv1.Deployment) bool {
+	if hashringConfig != "" || existingHashringConfigMap {
+		return false
+	}
+	return router == nil || ptr.Deref(router.Spec.Replicas, 1) == 0
+}
+
+// hashringUpdateDelay returns how long a change to the hashring configuration is held back, so that the changes
+// made within the update interval since the last update are coalesced into a single update.
+func hashringUpdateDelay(lastUpdate *metav1.Time, interval time.Duration, now time.Time) time.Duration {
+	if lastUpdate == nil || interval <= 0 {
+		return 0
+	}
+	return max(lastUpdate.Add(interval).Sub(now), 0)
+}
+
+// holdBackHashringUpdate holds back the endpoints joining the hashrings of the current configuration while a hashring
+// configuration update is delayed by the given delay. Everything else, including the endpoints leaving the hashrings,
+// is applied straight away. It returns the configuration to apply, and the delay after which the held back endpoints
+// are due, zero if nothing is held back.
+func holdBackHashringUpdate(current, next receive.Hashrings, delay time.Duration, replicationFactor int) (receive.Hashrings, time.Duration) {
+	if delay <= 0 || len(current) == 0 {
+		return next, 0
+	}
+	held := receive.HoldBackEndpointAdditions(current, next, replicationFactor)
+	if len(receive.DiffHashrings(held, next)) == 0 {
+		return next, 0
+	}
+	return held, delay
+}
+
+// hashringConfigRevision is a hashring configuration together with its version and the time it last changed.
+type hashringConfigRevision struct {
+	config     string
+	version    int64
+	updateTime time.Time
+}
+
+// nextHashringConfigRevision returns the revision of the generated hashring configuration.
+// The applied revision is kept as is if its configuration is semantically equal to the generated one, so that the
+// ConfigMap is not rewritten when only the formatting differs. Otherwise, the version is increased.
+func nextHashringConfigRevision(applied hashringConfigRevision, generated string, now time.Time) hashringConfigRevision {
+	if applied.version > 0 && equalHashringConfigs(applied.config, generated) {
+		if generated == "" {
+			applied.config = generated
+		}
+		return applied
+	}
+	return hashringConfigRevision{config: generated, version: applied.version + 1, updateTime: now}
+}
+
+// equalHashringConfigs returns true if both hashring configurations decode to the same JSON value.
+func equalHashringConfigs(a, b string) bool {
+	var av, bv any
+	if err := json.Unmarshal([]byte(cmp.Or(a, manifestreceive.EmptyHashringConfig)), &av); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(cmp.Or(b, manifestreceive.EmptyHashringConfig)), &bv); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(av, bv)
+}
@@ -0,0 +1,179 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/thanos-community/thanos-operator/internal/pkg/receive"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestHoldRouter(t *testing.T) {
+	running := &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: ptr.To(int32(2))}}
+	held := &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: ptr.To(int32(0))}}
+	for _, tc := range []struct {
+		name              string
+		hashringConfig    string
+		existingConfigMap bool
+		router            *appsv1.Deployment
+		expect            bool
+	}{
+		{name: "new router without ready hashrings", expect: true},
+		{name: "held router without ready hashrings", router: held, expect: true},
+		{name: "running router without ready hashrings", router: running},
+		{name: "ready hashrings", hashringConfig: `[{"hashring":"default"}]`, router: held},
+		{name: "existing hashring configmap", existingConfigMap: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := holdRouter(tc.hashringConfig, tc.existingConfigMap, tc.router); got != tc.expect {
+				t.Errorf("expected holdRouter to return %v, got %v", tc.expect, got)
+			}
+		})
+	}
+}
+
+func TestHashringUpdateDelay(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name       string
+		lastUpdate *metav1.Time
+		interval   time.Duration
+		expect     time.Duration
+	}{
+		{name: "no interval", lastUpdate: &metav1.Time{Time: now}},
+		{name: "never updated", interval: time.Minute},
+		{name: "within interval", lastUpdate: &metav1.Time{Time: now.Add(-20 * time.Second)}, interval: time.Minute, expect: 40 * time.Second},
+		{name: "after interval", lastUpdate: &metav1.Time{Time: now.Add(-2 * time.Minute)}, interval: time.Minute},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := hashringUpdateDelay(tc.lastUpdate, tc.interval, now); got != tc.expect {
+				t.Errorf("expected a delay of %s, got %s", tc.expect, got)
+			}
+		})
+	}
+}
+
+func TestHoldBackHashringUpdate(t *testing.T) {
+	a, b, c := receive.Endpoint{Address: "a:10901"}, receive.Endpoint{Address: "b:10901"}, receive.Endpoint{Address: "c:10901"}
+	current := receive.Hashrings{
+		{Name: "one", Endpoints: []receive.Endpoint{a, b}},
+		{Name: "two", Endpoints: []receive.Endpoint{c}},
+	}
+	for _, tc := range []struct {
+		name        string
+		next        receive.Hashrings
+		delay       time.Duration
+		expect      receive.Hashrings
+		expectDelay time.Duration
+	}{
+		{
+			name:   "no delay",
+			next:   receive.Hashrings{{Name: "one", Endpoints: []receive.Endpoint{a, b, c}}},
+			expect: receive.Hashrings{{Name: "one", Endpoints: []receive.Endpoint{a, b, c}}},
+		},
+		{
+			name:        "added endpoint is held back",
+			next:        receive.Hashrings{{Name: "one", Endpoints: []receive.Endpoint{a, b, c}}, {Name: "two", Endpoints: []receive.Endpoint{c}}},
+			delay:       time.Minute,
+			expect:      current,
+			expectDelay: time.Minute,
+		},
+		{
+			name:        "changed zone is held back",
+			next:        receive.Hashrings{{Name: "one", Endpoints: []receive.Endpoint{a, {Address: "b:10901", AZ: "zone-a"}}}, {Name: "two", Endpoints: []receive.Endpoint{c}}},
+			delay:       time.Minute,
+			expect:      current,
+			expectDelay: time.Minute,
+		},
+		{
+			name:   "terminating endpoint is removed",
+			next:   receive.Hashrings{{Name: "one", Endpoints: []receive.Endpoint{a}}, {Name: "two", Endpoints: []receive.Endpoint{c}}},
+			delay:  time.Minute,
+			expect: receive.Hashrings{{Name: "one", Endpoints: []receive.Endpoint{a}}, {Name: "two", Endpoints: []receive.Endpoint{c}}},
+		},
+		{
+			name:   "removed hashring is removed",
+			next:   receive.Hashrings{{Name: "one", Endpoints: []receive.Endpoint{a, b}}},
+			delay:  time.Minute,
+			expect: receive.Hashrings{{Name: "one", Endpoints: []receive.Endpoint{a, b}}},
+		},
+		{
+			name:        "endpoint removed while another is added",
+			next:        receive.Hashrings{{Name: "one", Endpoints: []receive.Endpoint{b, c}}, {Name: "two", Endpoints: []receive.Endpoint{c}}},
+			delay:       time.Minute,
+			expect:      receive.Hashrings{{Name: "one", Endpoints: []receive.Endpoint{b}}, {Name: "two", Endpoints: []receive.Endpoint{c}}},
+			expectDelay: time.Minute,
+		},
+		{
+			name:   "tenants are changed",
+			next:   receive.Hashrings{{Name: "one", Tenants: []string{"foo"}, Endpoints: []receive.Endpoint{a, b}}, {Name: "two", Endpoints: []receive.Endpoint{c}}},
+			delay:  time.Minute,
+			expect: receive.Hashrings{{Name: "one", Tenants: []string{"foo"}, Endpoints: []receive.Endpoint{a, b}}, {Name: "two", Endpoints: []receive.Endpoint{c}}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, delay := holdBackHashringUpdate(current, tc.next, tc.delay, 1)
+			if !reflect.DeepEqual(got, tc.expect) {
+				t.Errorf("expected hashrings %v, got %v", tc.expect, got)
+			}
+			if delay != tc.expectDelay {
+				t.Errorf("expected a delay of %s, got %s", tc.expectDelay, delay)
+			}
+		})
+	}
+}
+
+func TestNextHashringConfigRevision(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	applied := hashringConfigRevision{
+		config:     `[{"hashring": "default", "endpoints": [{"address": "a:10901"}]}]`,
+		version:    3,
+		updateTime: now.Add(-time.Hour),
+	}
+	for _, tc := range []struct {
+		name      string
+		applied   hashringConfigRevision
+		generated string
+		expect    hashringConfigRevision
+	}{
+		{
+			name:      "first configuration",
+			generated: `[{"hashring":"default"}]`,
+			expect:    hashringConfigRevision{config: `[{"hashring":"default"}]`, version: 1, updateTime: now},
+		},
+		{
+			name:      "unversioned configuration",
+			applied:   hashringConfigRevision{config: `[{"hashring":"default"}]`},
+			generated: `[{"hashring":"default"}]`,
+			expect:    hashringConfigRevision{config: `[{"hashring":"default"}]`, version: 1, updateTime: now},
+		},
+		{
+			name:      "formatting change",
+			applied:   applied,
+			generated: "[\n    {\n        \"endpoints\": [{\"address\": \"a:10901\"}],\n        \"hashring\": \"default\"\n    }\n]",
+			expect:    applied,
+		},
+		{
+			name:      "endpoint change",
+			applied:   applied,
+			generated: `[{"hashring": "default", "endpoints": [{"address": "b:10901"}]}]`,
+			expect:    hashringConfigRevision{config: `[{"hashring": "default", "endpoints": [{"address": "b:10901"}]}]`, version: 4, updateTime: now},
+		},
+		{
+			name:    "empty configuration",
+			applied: hashringConfigRevision{config: "[{}]", version: 2, updateTime: now.Add(-time.Hour)},
+			expect:  hashringConfigRevision{version: 2, updateTime: now.Add(-time.Hour)},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := nextHashringConfigRevision(tc.applied, tc.generated, now)
+			if got.config != tc.expect.config || got.version != tc.expect.version || !got.updateTime.Equal(tc.expect.updateTime) {
+				t.Errorf("expected %+v, got %+v", tc.expect, got)
+			}
+		})
+	}
+}
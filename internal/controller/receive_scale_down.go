@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	monitoringthanosiov1alpha1 "github.com/thanos-community/thanos-operator/api/v1alpha1"
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ingesterScaleDown is the state of the graceful scale down of a hashring.
+type ingesterScaleDown struct {
+	// replicas is the number of replicas to keep in the StatefulSet.
+	replicas int32
+	// since is when the scale down started. It is zero if the hashring is not scaling down.
+	since time.Time
+	// remaining is the time left until the StatefulSet can be scaled down.
+	remaining time.Duration
+}
+
+// nextIngesterScaleDown returns the state of the scale down of a hashring from current to desired replicas.
+// The current replicas are kept until the grace period has passed since the scale down started.
+func nextIngesterScaleDown(current, desired int32, since time.Time, gracePeriod time.Duration, now time.Time) ingesterScaleDown {
+	if current <= desired || gracePeriod <= 0 {
+		return ingesterScaleDown{replicas: desired}
+	}
+	if since.IsZero() {
+		since = now
+	}
+	remaining := since.Add(gracePeriod).Sub(now)
+	if remaining <= 0 {
+		return ingesterScaleDown{replicas: desired}
+	}
+	return ingesterScaleDown{replicas: current, since: since, remaining: remaining}
+}
+
+// ingesterReplicas returns the number of replicas to set on the StatefulSet of the hashring.
+// When the replicas of a hashring with a scale down grace period are decreased, the StatefulSet keeps its current
+// replicas until the grace period has passed, while the ingesters being removed are left out of the hashring
+// configuration. The start of the scale down is recorded on the StatefulSet so that it survives operator restarts.
+func (r *ThanosReceiveReconciler) ingesterReplicas(ctx context.Context, cluster targetCluster, receiver monitoringthanosiov1alpha1.ThanosReceive, hashring monitoringthanosiov1alpha1.IngesterHashringSpec) (int32, error) {
+	if hashring.ScaleDownGracePeriod == nil {
+		return hashring.Replicas, nil
+	}
+
+	name := ReceiveIngesterNameFromParent(receiver.GetName(), hashring.Name)
+	sts := &appsv1.StatefulSet{}
+	if err := cluster.client.Get(ctx, client.ObjectKey{Namespace: receiver.GetNamespace(), Name: name}, sts); err != nil {
+		if apierrors.IsNotFound(err) {
+			return hashring.Replicas, nil
+		}
+		return 0, fmt.Errorf("failed to get statefulset %s: %w", name, err)
+	}
+
+	since, _ := time.Parse(time.RFC3339, sts.GetAnnotations()[manifests.ScaleDownSinceAnnotation])
+	state := nextIngesterScaleDown(ptr.Deref(sts.Spec.Replicas, 1), hashring.Replicas, since, parseDurationOr(hashring.ScaleDownGracePeriod, 0), time.Now())
+	if !state.since.Equal(since) {
+		patch := client.MergeFrom(sts.DeepCopy())
+		annotations := sts.GetAnnotations()
+		if state.since.IsZero() {
+			delete(annotations, manifests.ScaleDownSinceAnnotation)
+		} else {
+			annotations = manifests.MergeMaps(annotations, map[string]string{
+				manifests.ScaleDownSinceAnnotation: state.since.UTC().Format(time.RFC3339),
+			})
+		}
+		sts.SetAnnotations(annotations)
+		if err := cluster.client.Patch(ctx, sts, patch); err != nil {
+			return 0, fmt.Errorf("failed to record scale down of statefulset %s: %w", name, err)
+		}
+
+		if !state.since.IsZero() {
+			r.recorder.Eventf(&receiver, nil, corev1.EventTypeNormal, "ScaleDownStarted", "Reconcile",
+				"Removed ingesters of hashring %s from the hashring, scaling down from %d to %d replicas in %s",
+				hashring.Name, state.replicas, hashring.Replicas, state.remaining.String())
+		}
+	}
+
+	r.pendingDeletions.record(types.NamespacedName{Namespace: receiver.GetNamespace(), Name: receiver.GetName()}, state.remaining)
+	return state.replicas, nil
+}
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextIngesterScaleDown(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name             string
+		current, desired int32
+		since            time.Time
+		gracePeriod      time.Duration
+		expect           ingesterScaleDown
+	}{
+		{
+			name:        "scale up",
+			current:     3,
+			desired:     5,
+			gracePeriod: time.Minute,
+			expect:      ingesterScaleDown{replicas: 5},
+		},
+		{
+			name:        "scale up cancels scale down",
+			current:     3,
+			desired:     3,
+			since:       now.Add(-30 * time.Second),
+			gracePeriod: time.Minute,
+			expect:      ingesterScaleDown{replicas: 3},
+		},
+		{
+			name:    "scale down without grace period",
+			current: 5,
+			desired: 3,
+			expect:  ingesterScaleDown{replicas: 3},
+		},
+		{
+			name:        "scale down starts",
+			current:     5,
+			desired:     3,
+			gracePeriod: time.Minute,
+			expect:      ingesterScaleDown{replicas: 5, since: now, remaining: time.Minute},
+		},
+		{
+			name:        "scale down in grace period",
+			current:     5,
+			desired:     3,
+			since:       now.Add(-40 * time.Second),
+			gracePeriod: time.Minute,
+			expect:      ingesterScaleDown{replicas: 5, since: now.Add(-40 * time.Second), remaining: 20 * time.Second},
+		},
+		{
+			name:        "scale down after grace period",
+			current:     5,
+			desired:     3,
+			since:       now.Add(-time.Minute),
+			gracePeriod: time.Minute,
+			expect:      ingesterScaleDown{replicas: 3},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := nextIngesterScaleDown(tc.current, tc.desired, tc.since, tc.gracePeriod, now)
+			if got.replicas != tc.expect.replicas || !got.since.Equal(tc.expect.since) || got.remaining != tc.expect.remaining {
+				t.Errorf("expected %+v, got %+v", tc.expect, got)
+			}
+		})
+	}
+}
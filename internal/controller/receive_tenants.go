@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// selectedTenants returns the ThanosTenant resources selected by the tenantSelector of the ThanosReceive, ordered by name.
+func (r *ThanosReceiveReconciler) selectedTenants(ctx context.Context, receiver v1alpha1.ThanosReceive) ([]v1alpha1.ThanosTenant, error) {
+	if receiver.Spec.TenantSelector == nil {
+		return nil, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(receiver.Spec.TenantSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tenantSelector: %w", err)
+	}
+	tenants := &v1alpha1.ThanosTenantList{}
+	if err := r.List(ctx, tenants, client.InNamespace(receiver.GetNamespace()), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("failed to list ThanosTenants: %w", err)
+	}
+	slices.SortFunc(tenants.Items, func(a, b v1alpha1.ThanosTenant) int {
+		return strings.Compare(a.GetName(), b.GetName())
+	})
+	return tenants.Items, nil
+}
+
+// assignTenants adds the tenants to the tenants of their hashring and their limits to the limits of the ThanosReceive.
+// The hashrings and limits of the ThanosReceive are copied first, so that the cached object is not modified.
+// Tenants that cannot be assigned are left out, and a message is returned for each of them.
+func assignTenants(receiver *v1alpha1.ThanosReceive, tenants []v1alpha1.ThanosTenant) []string {
+	if len(tenants) == 0 {
+		return nil
+	}
+	receiver.Spec.Ingester.Hashrings = slices.Clone(receiver.Spec.Ingester.Hashrings)
+	for i := range receiver.Spec.Ingester.Hashrings {
+		receiver.Spec.Ingester.Hashrings[i].TenancyConfig = receiver.Spec.Ingester.Hashrings[i].TenancyConfig.DeepCopy()
+	}
+	receiver.Spec.Limits = receiver.Spec.Limits.DeepCopy()
+
+	// tenants listed by the ThanosReceive itself are owned by their hashring
+	listedBy := map[string]string{}
+	for _, hashring := range receiver.Spec.Ingester.Hashrings {
+		if hashring.TenancyConfig == nil || hashring.TenancyConfig.TenantMatcherType == "glob" {
+			continue
+		}
+		for _, tenant := range hashring.TenancyConfig.Tenants {
+			listedBy[tenant] = hashring.Name
+		}
+	}
+
+	var rejected []string
+	assignedBy := map[string]string{}
+	for _, tenant := range tenants {
+		id, name := tenant.Spec.TenantID, tenant.GetName()
+		if other, ok := assignedBy[id]; ok {
+			rejected = append(rejected, fmt.Sprintf("ThanosTenant %s: tenant %s is already declared by ThanosTenant %s", name, id, other))
+			continue
+		}
+		idx := slices.IndexFunc(receiver.Spec.Ingester.Hashrings, func(h v1alpha1.IngesterHashringSpec) bool {
+			return h.Name == tenant.Spec.Hashring
+		})
+		if idx < 0 {
+			rejected = append(rejected, fmt.Sprintf("ThanosTenant %s: hashring %s does not exist", name, tenant.Spec.Hashring))
+			continue
+		}
+		hashring := &receiver.Spec.Ingester.Hashrings[idx]
+		if other, ok := listedBy[id]; ok && other != hashring.Name {
+			rejected = append(rejected, fmt.Sprintf("ThanosTenant %s: tenant %s is routed to hashring %s by the ThanosReceive", name, id, other))
+			continue
+		}
+		if hashring.TenancyConfig != nil && slices.Contains(hashring.TenancyConfig.ExcludeTenants, id) {
+			rejected = append(rejected, fmt.Sprintf("ThanosTenant %s: tenant %s is excluded from hashring %s", name, id, hashring.Name))
+			continue
+		}
+		if tenant.Spec.Limits != nil && tenant.Spec.Limits.HeadSeriesLimit != nil &&
+			(receiver.Spec.Limits == nil || receiver.Spec.Limits.Global == nil || receiver.Spec.Limits.Global.MetaMonitoringURL == nil) {
+			rejected = append(rejected, fmt.Sprintf("ThanosTenant %s: a head series limit requires limits.global.metaMonitoringURL to be set on the ThanosReceive", name))
+			continue
+		}
+		assignedBy[id] = name
+
+		// a hashring without tenants already matches every tenant, listing one would restrict it to that tenant
+		if hashring.TenancyConfig != nil && len(hashring.TenancyConfig.Tenants) > 0 && !slices.Contains(hashring.TenancyConfig.Tenants, id) {
+			hashring.TenancyConfig.Tenants = append(hashring.TenancyConfig.Tenants, id)
+		}
+
+		if tenant.Spec.Limits == nil {
+			continue
+		}
+		if receiver.Spec.Limits == nil {
+			receiver.Spec.Limits = &v1alpha1.ReceiveLimitsSpec{}
+		}
+		if _, ok := receiver.Spec.Limits.Tenants[id]; ok {
+			// limits set by the ThanosReceive take precedence
+			continue
+		}
+		if receiver.Spec.Limits.Tenants == nil {
+			receiver.Spec.Limits.Tenants = map[string]v1alpha1.WriteLimits{}
+		}
+		receiver.Spec.Limits.Tenants[id] = *tenant.Spec.Limits
+	}
+	return rejected
+}
+
+// enqueueForTenant enqueues the ThanosReceive resources in the namespace of a ThanosTenant whose tenantSelector selects it.
+func enqueueForTenant(c client.Client) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+		list := &v1alpha1.ThanosReceiveList{}
+		if err := c.List(ctx, list, client.InNamespace(obj.GetNamespace())); err != nil {
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for _, receiver := range list.Items {
+			if receiver.Spec.TenantSelector == nil {
+				continue
+			}
+			selector, err := metav1.LabelSelectorAsSelector(receiver.Spec.TenantSelector)
+			if err != nil || !selector.Matches(labels.Set(obj.GetLabels())) {
+				continue
+			}
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&receiver)})
+		}
+		return requests
+	})
+}
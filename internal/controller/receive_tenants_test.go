@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestAssignTenants(t *testing.T) {
+	tenant := func(name, id, hashring string, limits *v1alpha1.WriteLimits) v1alpha1.ThanosTenant {
+		return v1alpha1.ThanosTenant{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       v1alpha1.ThanosTenantSpec{TenantID: id, Hashring: hashring, Limits: limits},
+		}
+	}
+	receiver := v1alpha1.ThanosReceive{
+		Spec: v1alpha1.ThanosReceiveSpec{
+			Ingester: v1alpha1.IngesterSpec{
+				Hashrings: []v1alpha1.IngesterHashringSpec{
+					{Name: "teams", TenancyConfig: &v1alpha1.TenancyConfig{Tenants: []string{"team-a"}, TenantMatcherType: "exact"}},
+					{Name: "isolated", TenancyConfig: &v1alpha1.TenancyConfig{Tenants: []string{"team-b"}, TenantMatcherType: "exact"}},
+					{Name: "default", TenancyConfig: &v1alpha1.TenancyConfig{ExcludeTenants: []string{"team-a", "team-b"}}},
+				},
+			},
+			Limits: &v1alpha1.ReceiveLimitsSpec{
+				Tenants: map[string]v1alpha1.WriteLimits{"team-a": {SeriesLimit: ptr.To[int64](10)}},
+			},
+		},
+	}
+	// a shallow copy shares the hashrings and limits with the cached object
+	original := receiver
+
+	rejected := assignTenants(&receiver, []v1alpha1.ThanosTenant{
+		tenant("a", "team-a", "teams", &v1alpha1.WriteLimits{SeriesLimit: ptr.To[int64](20)}),
+		tenant("c", "team-c", "teams", &v1alpha1.WriteLimits{SamplesLimit: ptr.To[int64](100)}),
+		tenant("c-dup", "team-c", "isolated", nil),
+		tenant("d", "team-d", "default", nil),
+		tenant("b", "team-b", "teams", nil),
+		tenant("e", "team-e", "missing", nil),
+		tenant("f", "team-f", "teams", &v1alpha1.WriteLimits{HeadSeriesLimit: ptr.To[int64](1000)}),
+	})
+
+	if len(rejected) != 4 {
+		t.Fatalf("expected 4 rejected tenants, got %d: %v", len(rejected), rejected)
+	}
+	if got := receiver.Spec.Ingester.Hashrings[0].TenancyConfig.Tenants; !slices.Equal(got, []string{"team-a", "team-c"}) {
+		t.Errorf("expected tenants team-a and team-c on hashring teams, got %v", got)
+	}
+	if got := receiver.Spec.Ingester.Hashrings[2].TenancyConfig.Tenants; len(got) != 0 {
+		t.Errorf("expected hashring default to keep matching all tenants, got %v", got)
+	}
+	if got := ptr.Deref(receiver.Spec.Limits.Tenants["team-a"].SeriesLimit, 0); got != 10 {
+		t.Errorf("expected the limits of the ThanosReceive to take precedence, got series limit %d", got)
+	}
+	if got := ptr.Deref(receiver.Spec.Limits.Tenants["team-c"].SamplesLimit, 0); got != 100 {
+		t.Errorf("expected the limits of team-c to be added, got samples limit %d", got)
+	}
+	if _, ok := receiver.Spec.Limits.Tenants["team-f"]; ok {
+		t.Errorf("expected the limits of the rejected team-f not to be added")
+	}
+	if got := original.Spec.Ingester.Hashrings[0].TenancyConfig.Tenants; !slices.Equal(got, []string{"team-a"}) {
+		t.Errorf("expected the original hashrings to be unchanged, got %v", got)
+	}
+	if _, ok := original.Spec.Limits.Tenants["team-c"]; ok {
+		t.Errorf("expected the original limits to be unchanged")
+	}
+}
@@ -0,0 +1,213 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+	manifestreceive "github.com/thanos-community/thanos-operator/internal/pkg/manifests/receive"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// uploadLagCheckInterval is the interval between two checks of the upload lag of the ingesters.
+	// Ingesters cut a block every two hours, so the upload lag does not need to be checked more often.
+	uploadLagCheckInterval = 5 * time.Minute
+	// uploadLagScrapeTimeout bounds the scrape of the metrics of an ingester, so that an unresponsive ingester
+	// does not block reconciles.
+	uploadLagScrapeTimeout = 5 * time.Second
+	// shipperLastUploadMetric is the time of the last successful upload of a block by the shipper of a tenant.
+	// It is zero until the shipper uploads its first block.
+	shipperLastUploadMetric = "thanos_shipper_last_successful_upload_time"
+	// headMinTimeMetric is the lower bound of the head of the TSDB of a tenant. Once the head has been compacted,
+	// it is the max time of the newest block of the tenant.
+	headMinTimeMetric = "prometheus_tsdb_head_min_time_seconds"
+	// lowestTimestampMetric is the oldest sample of the TSDB of a tenant, across its blocks and its head.
+	lowestTimestampMetric = "prometheus_tsdb_lowest_timestamp_seconds"
+)
+
+// uploadLagChecksDue returns true if the upload lag of the ThanosReceive must be checked.
+func uploadLagChecksDue(receiver v1alpha1.ThanosReceive, now time.Time) bool {
+	last := receiver.Status.UploadLagCheckTime
+	return last == nil || !now.Before(last.Add(uploadLagCheckInterval))
+}
+
+// nextUploadLagCheck returns the time until the next check of the upload lag of the ThanosReceive is due,
+// or zero if the upload lag is not checked.
+func nextUploadLagCheck(receiver v1alpha1.ThanosReceive, now time.Time) time.Duration {
+	last := receiver.Status.UploadLagCheckTime
+	if last == nil {
+		return 0
+	}
+	return max(last.Add(uploadLagCheckInterval).Sub(now), time.Second)
+}
+
+// readUploadLag scrapes the shipper and TSDB metrics of the running ingesters of each hashring on the given port,
+// and returns the upload lag of each hashring, which is the highest upload lag of its ingesters.
+// Hashrings whose ingesters could not be scraped are left out, and the scrape errors are returned alongside.
+func readUploadLag(ctx context.Context, c client.Client, httpClient *http.Client, receiver v1alpha1.ThanosReceive, port int32, now time.Time) (map[string]metav1.Duration, error) {
+	lags := make(map[string]metav1.Duration, len(receiver.Spec.Ingester.Hashrings))
+	var errs []error
+	for _, hashring := range receiver.Spec.Ingester.Hashrings {
+		opts := manifestreceive.IngesterOptions{
+			Options:      manifests.Options{Owner: receiver.GetName()},
+			HashringName: hashring.Name,
+		}
+		pods := &corev1.PodList{}
+		if err := c.List(ctx, pods, client.MatchingLabels(manifestreceive.GetIngesterLabels(opts)), client.InNamespace(receiver.GetNamespace())); err != nil {
+			return nil, fmt.Errorf("failed to list the ingesters of hashring %s: %w", hashring.Name, err)
+		}
+
+		var (
+			mtx     sync.Mutex
+			wg      sync.WaitGroup
+			lag     time.Duration
+			scraped bool
+		)
+		for _, pod := range pods.Items {
+			if pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" || pod.GetDeletionTimestamp() != nil {
+				continue
+			}
+			url := fmt.Sprintf("http://%s:%d/metrics", pod.Status.PodIP, port)
+			wg.Go(func() {
+				podLag, err := scrapeUploadLag(ctx, httpClient, url, now)
+				mtx.Lock()
+				defer mtx.Unlock()
+				if err != nil {
+					errs = append(errs, fmt.Errorf("pod %s: %w", pod.GetName(), err))
+					return
+				}
+				lag = max(lag, podLag)
+				scraped = true
+			})
+		}
+		wg.Wait()
+		if scraped {
+			lags[hashring.Name] = metav1.Duration{Duration: lag}
+		}
+	}
+	return lags, errors.Join(errs...)
+}
+
+// scrapeUploadLag scrapes the metrics of an ingester and returns its upload lag, which is the highest upload lag
+// of its tenants. An ingester without shipper metrics has no tenant, and so no block waiting to be uploaded.
+func scrapeUploadLag(ctx context.Context, httpClient *http.Client, url string, now time.Time) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, uploadLagScrapeTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", string(expfmt.NewFormat(expfmt.TypeTextPlain)))
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	parser := expfmt.NewTextParser(model.UTF8Validation)
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse the metrics: %w", err)
+	}
+
+	headMinTimes := tenantGauges(families[headMinTimeMetric])
+	lowestTimestamps := tenantGauges(families[lowestTimestampMetric])
+	var lag time.Duration
+	for tenant, lastUpload := range tenantGauges(families[shipperLastUploadMetric]) {
+		headMinTime, ok := headMinTimes[tenant]
+		if !ok {
+			continue
+		}
+		lowestTimestamp, ok := lowestTimestamps[tenant]
+		if !ok {
+			continue
+		}
+		lag = max(lag, tenantUploadLag(lastUpload, headMinTime, lowestTimestamp, now))
+	}
+	return lag, nil
+}
+
+// tenantGauges returns the value of the gauges of a metric family keyed by tenant.
+func tenantGauges(family *dto.MetricFamily) map[string]float64 {
+	gauges := make(map[string]float64, len(family.GetMetric()))
+	for _, m := range family.GetMetric() {
+		for _, label := range m.GetLabel() {
+			if label.GetName() == "tenant" {
+				gauges[label.GetValue()] = m.GetGauge().GetValue()
+			}
+		}
+	}
+	return gauges
+}
+
+// tenantUploadLag returns the age of the oldest block of a tenant that has not been uploaded, from the time in
+// seconds of its last upload, of the lower bound of its head and of its oldest sample.
+// The tenant has blocks when its oldest sample is older than its head, and its newest block ends where the head
+// starts. The shipper uploads all the pending blocks at once, so the blocks have all been uploaded when the last
+// upload is more recent than the newest block, and otherwise the oldest block that has not been uploaded holds the
+// samples since the last upload, or since the oldest sample if the tenant has not uploaded a block yet.
+// A tenant that stops receiving samples does not cut new blocks, so it does not lag once its blocks are uploaded.
+func tenantUploadLag(lastUpload, headMinTime, lowestTimestamp float64, now time.Time) time.Duration {
+	// the head of a TSDB without samples has no lower bound
+	if headMinTime <= lowestTimestamp || headMinTime > float64(now.Unix()) || lastUpload >= headMinTime {
+		return 0
+	}
+	oldest := time.Unix(0, int64(max(lastUpload, lowestTimestamp)*float64(time.Second)))
+	return max(now.Sub(oldest), 0)
+}
+
+// uploadLagThreshold returns the upload lag threshold of the ThanosReceive.
+func uploadLagThreshold(receiver v1alpha1.ThanosReceive) time.Duration {
+	return parseDurationOr(receiver.Spec.Ingester.UploadLagThreshold, 3*time.Hour)
+}
+
+// uploadLagDegraded returns a message for each hashring whose upload lag is above the threshold, in order.
+func uploadLagDegraded(threshold time.Duration, hashrings []string, lags map[string]metav1.Duration) []string {
+	var degraded []string
+	for _, hashring := range hashrings {
+		lag, ok := lags[hashring]
+		if !ok || lag.Duration <= threshold {
+			continue
+		}
+		degraded = append(degraded, fmt.Sprintf("the oldest block of the ingesters of hashring %s that has not been uploaded is %s old, above the threshold of %s",
+			hashring, lag.Round(time.Second), threshold))
+	}
+	return degraded
+}
+
+// uploadLagDegradedCondition returns the UploadLagDegraded condition for the messages returned by uploadLagDegraded.
+func uploadLagDegradedCondition(degraded []string) metav1.Condition {
+	if len(degraded) == 0 {
+		return metav1.Condition{
+			Type:    ConditionUploadLagDegraded,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonUploadLagWithinThreshold,
+			Message: "The upload lag of all hashrings is within the threshold",
+		}
+	}
+	return metav1.Condition{
+		Type:    ConditionUploadLagDegraded,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonUploadLagAboveThreshold,
+		Message: strings.Join(degraded, "; "),
+	}
+}
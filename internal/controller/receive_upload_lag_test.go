@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+	manifestreceive "github.com/thanos-community/thanos-operator/internal/pkg/manifests/receive"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// tenantTimes are the times in seconds of the last upload, the lower bound of the head and the oldest sample of a tenant.
+type tenantTimes struct {
+	lastUpload, headMinTime, lowestTimestamp float64
+}
+
+// writeTenantMetrics writes the shipper and TSDB metrics of the tenants.
+func writeTenantMetrics(w io.Writer, tenants map[string]tenantTimes) {
+	for _, metric := range []string{shipperLastUploadMetric, headMinTimeMetric, lowestTimestampMetric} {
+		_, _ = fmt.Fprintf(w, "# TYPE %s gauge\n", metric)
+		for tenant, times := range tenants {
+			v := map[string]float64{shipperLastUploadMetric: times.lastUpload, headMinTimeMetric: times.headMinTime, lowestTimestampMetric: times.lowestTimestamp}[metric]
+			_, _ = fmt.Fprintf(w, "%s{tenant=%q} %f\n", metric, tenant, v)
+		}
+	}
+}
+
+func TestReadUploadLag(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	ago := func(d time.Duration) float64 { return float64(now.Add(-d).Unix()) }
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeTenantMetrics(w, map[string]tenantTimes{
+			"shipped": {lastUpload: ago(90 * time.Minute), headMinTime: ago(2 * time.Hour), lowestTimestamp: ago(10 * time.Hour)},
+			"stalled": {lastUpload: ago(4 * time.Hour), headMinTime: ago(time.Hour), lowestTimestamp: ago(10 * time.Hour)},
+		})
+	}))
+	defer server.Close()
+	host, portStr, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	port, _ := strconv.Atoi(portStr)
+
+	ingesterPod := func(hashring, name string, phase corev1.PodPhase) client.Object {
+		opts := manifestreceive.IngesterOptions{Options: manifests.Options{Owner: "test"}, HashringName: hashring}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", Labels: manifestreceive.GetIngesterLabels(opts)},
+			Status:     corev1.PodStatus{Phase: phase, PodIP: host},
+		}
+	}
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		ingesterPod("default", "default-0", corev1.PodRunning),
+		ingesterPod("default", "default-1", corev1.PodRunning),
+		ingesterPod("pending", "pending-0", corev1.PodPending),
+	).Build()
+
+	receiver := v1alpha1.ThanosReceive{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"},
+		Spec: v1alpha1.ThanosReceiveSpec{Ingester: v1alpha1.IngesterSpec{Hashrings: []v1alpha1.IngesterHashringSpec{
+			{Name: "default"}, {Name: "pending"},
+		}}},
+	}
+	lags, err := readUploadLag(context.Background(), c, server.Client(), receiver, int32(port), now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the hashring lags as much as its tenant that is the furthest behind, and the hashring without running ingesters is left out
+	if len(lags) != 1 || lags["default"].Duration != 4*time.Hour {
+		t.Fatalf("expected an upload lag of 4h for hashring default only, got %v", lags)
+	}
+
+	lags["recent"] = metav1.Duration{Duration: time.Hour}
+	degraded := uploadLagDegraded(3*time.Hour, []string{"default", "recent", "pending"}, lags)
+	if len(degraded) != 1 {
+		t.Fatalf("expected hashring default to be degraded, got %v", degraded)
+	}
+	if condition := uploadLagDegradedCondition(degraded); condition.Status != metav1.ConditionTrue || condition.Reason != ReasonUploadLagAboveThreshold {
+		t.Errorf("expected the condition to be set, got %+v", condition)
+	}
+	if condition := uploadLagDegradedCondition(nil); condition.Status != metav1.ConditionFalse {
+		t.Errorf("expected the condition to be cleared, got %+v", condition)
+	}
+}
+
+func TestTenantUploadLag(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	ago := func(d time.Duration) float64 { return float64(now.Add(-d).Unix()) }
+	for _, tc := range []struct {
+		name                                     string
+		lastUpload, headMinTime, lowestTimestamp float64
+		expected                                 time.Duration
+	}{
+		{name: "newest block uploaded", lastUpload: ago(90 * time.Minute), headMinTime: ago(2 * time.Hour), lowestTimestamp: ago(10 * time.Hour)},
+		{name: "idle tenant with its blocks uploaded", lastUpload: ago(5 * time.Hour), headMinTime: ago(6 * time.Hour), lowestTimestamp: ago(10 * time.Hour)},
+		{name: "head without blocks", headMinTime: ago(30 * time.Minute), lowestTimestamp: ago(30 * time.Minute)},
+		{name: "empty head", lastUpload: ago(5 * time.Hour), headMinTime: math.MaxInt64 / 1000, lowestTimestamp: ago(10 * time.Hour)},
+		{name: "newest block not uploaded", lastUpload: ago(4 * time.Hour), headMinTime: ago(time.Hour), lowestTimestamp: ago(10 * time.Hour), expected: 4 * time.Hour},
+		{name: "no block uploaded yet", headMinTime: ago(time.Hour), lowestTimestamp: ago(3 * time.Hour), expected: 3 * time.Hour},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if lag := tenantUploadLag(tc.lastUpload, tc.headMinTime, tc.lowestTimestamp, now); lag != tc.expected {
+				t.Errorf("expected an upload lag of %s, got %s", tc.expected, lag)
+			}
+		})
+	}
+}
+
+func TestUploadLagChecksDue(t *testing.T) {
+	now := time.Now()
+	receiver := v1alpha1.ThanosReceive{}
+	if !uploadLagChecksDue(receiver, now) || nextUploadLagCheck(receiver, now) != 0 {
+		t.Error("expected the first check to be due")
+	}
+	receiver.Status.UploadLagCheckTime = &metav1.Time{Time: now.Add(-time.Minute)}
+	if uploadLagChecksDue(receiver, now) {
+		t.Error("expected no check to be due within the check interval")
+	}
+	if next := nextUploadLagCheck(receiver, now); next != uploadLagCheckInterval-time.Minute {
+		t.Errorf("expected the next check in %s, got %s", uploadLagCheckInterval-time.Minute, next)
+	}
+	receiver.Status.UploadLagCheckTime = &metav1.Time{Time: now.Add(-uploadLagCheckInterval)}
+	if !uploadLagChecksDue(receiver, now) {
+		t.Error("expected a check to be due once the check interval has passed")
+	}
+}
+
+func TestScrapeUploadLagErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/metrics" {
+			_, _ = w.Write([]byte("go_goroutines 10\n"))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	lag, err := scrapeUploadLag(context.Background(), server.Client(), server.URL+"/metrics", now)
+	if err != nil || lag != 0 {
+		t.Errorf("expected an ingester without shipper metrics to have no upload lag, got %s, %v", lag, err)
+	}
+	if _, err := scrapeUploadLag(context.Background(), server.Client(), server.URL+"/missing", now); err == nil {
+		t.Error("expected an error for a failed scrape")
+	}
+}
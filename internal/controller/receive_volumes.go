@@ -0,0 +1,237 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+	manifestreceive "github.com/thanos-community/thanos-operator/internal/pkg/manifests/receive"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// readIngesterVolumes returns the placement of the data volumes of the ingesters of each hashring,
+// read from the PersistentVolumes bound to their claims. Claims that do not exist yet are left out.
+func readIngesterVolumes(ctx context.Context, c client.Client, receiver v1alpha1.ThanosReceive) (map[string][]v1alpha1.IngesterVolumeStatus, error) {
+	volumes := make(map[string][]v1alpha1.IngesterVolumeStatus, len(receiver.Spec.Ingester.Hashrings))
+	for _, hashring := range receiver.Spec.Ingester.Hashrings {
+		name := ReceiveIngesterNameFromParent(receiver.GetName(), hashring.Name)
+		for i := range hashring.Replicas {
+			claim := &corev1.PersistentVolumeClaim{}
+			key := client.ObjectKey{Namespace: receiver.GetNamespace(), Name: manifestreceive.DataVolumeClaimName(name, i)}
+			if err := c.Get(ctx, key, claim); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return nil, err
+			}
+
+			status := v1alpha1.IngesterVolumeStatus{Claim: claim.GetName(), Volume: claim.Spec.VolumeName}
+			if claim.Spec.VolumeName != "" {
+				pv := &corev1.PersistentVolume{}
+				if err := c.Get(ctx, client.ObjectKey{Name: claim.Spec.VolumeName}, pv); err != nil && !apierrors.IsNotFound(err) {
+					return nil, err
+				} else if err == nil {
+					status.Zone, status.Node = volumePlacement(pv)
+				}
+			}
+			volumes[hashring.Name] = append(volumes[hashring.Name], status)
+		}
+	}
+	return volumes, nil
+}
+
+// volumePlacement returns the zone and node a PersistentVolume is pinned to, from its topology labels
+// or the required node affinity set by its provisioner. CSI drivers commonly use their own zone key,
+// such as topology.ebs.csi.aws.com/zone, so any key ending in /zone is considered.
+func volumePlacement(pv *corev1.PersistentVolume) (zone, node string) {
+	zone = pv.Labels[corev1.LabelTopologyZone]
+	if zone == "" {
+		zone = pv.Labels[corev1.LabelFailureDomainBetaZone]
+	}
+	if pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+		return zone, node
+	}
+	for _, term := range pv.Spec.NodeAffinity.Required.NodeSelectorTerms {
+		for _, expr := range term.MatchExpressions {
+			if expr.Operator != corev1.NodeSelectorOpIn || len(expr.Values) != 1 {
+				continue
+			}
+			switch {
+			case expr.Key == corev1.LabelHostname:
+				node = expr.Values[0]
+			case zone == "" && strings.HasSuffix(expr.Key, "/zone"):
+				zone = expr.Values[0]
+			}
+		}
+	}
+	return zone, node
+}
+
+// volumeZones returns the distinct zones of the bound volumes, in order, and false if a bound volume has no zone.
+func volumeZones(volumes []v1alpha1.IngesterVolumeStatus) ([]string, bool) {
+	var zones []string
+	for _, v := range volumes {
+		if v.Volume == "" {
+			continue
+		}
+		if v.Zone == "" {
+			return nil, false
+		}
+		if !slices.Contains(zones, v.Zone) {
+			zones = append(zones, v.Zone)
+		}
+	}
+	slices.Sort(zones)
+	return zones, true
+}
+
+// volumeZonesDegraded returns a message for each hashring whose bound volumes are pinned to fewer zones than
+// the replication factor, or than the number of volumes if that is lower. The ingesters are bound to the zones
+// of their volumes, so the replicas of a series cannot be spread across enough zones to survive the loss of one.
+// Hashrings with volumes that are not pinned to a zone are not checked.
+func volumeZonesDegraded(replicationFactor int32, hashrings []string, volumes map[string][]v1alpha1.IngesterVolumeStatus) []string {
+	var degraded []string
+	for _, hashring := range hashrings {
+		zones, ok := volumeZones(volumes[hashring])
+		if !ok || len(zones) == 0 {
+			continue
+		}
+		bound := 0
+		for _, v := range volumes[hashring] {
+			if v.Volume != "" {
+				bound++
+			}
+		}
+		if len(zones) >= min(int(replicationFactor), bound) {
+			continue
+		}
+		degraded = append(degraded, fmt.Sprintf("the %d bound volumes of hashring %s are in %d zones (%s), fewer than the replication factor of %d",
+			bound, hashring, len(zones), strings.Join(zones, ", "), replicationFactor))
+	}
+	return degraded
+}
+
+// volumeZonesDegradedCondition returns the VolumeZonesDegraded condition for the messages returned by volumeZonesDegraded.
+func volumeZonesDegradedCondition(degraded []string) metav1.Condition {
+	if len(degraded) == 0 {
+		return metav1.Condition{
+			Type:    ConditionVolumeZonesDegraded,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonVolumesSpreadAcrossZones,
+			Message: "The volumes of all hashrings are spread across enough zones",
+		}
+	}
+	return metav1.Condition{
+		Type:    ConditionVolumeZonesDegraded,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonVolumesInTooFewZones,
+		Message: strings.Join(degraded, "; "),
+	}
+}
+
+// expandIngesterVolumes grows the data volume claims of the ingesters of each hashring that are smaller than the
+// storage size of the hashring. The volume claim templates of a StatefulSet cannot be updated, so the existing claims,
+// and those later created from the templates for new ingesters, are expanded in place.
+// It returns the expanded claims, and a message for each hashring whose claims cannot be resized to its storage size.
+func expandIngesterVolumes(ctx context.Context, c client.Client, receiver v1alpha1.ThanosReceive) (expanded, blocked []string, err error) {
+	expandable := make(map[string]bool)
+	for _, hashring := range receiver.Spec.Ingester.Hashrings {
+		size := hashring.StorageConfiguration.Size.ToResourceQuantity()
+		name := ReceiveIngesterNameFromParent(receiver.GetName(), hashring.Name)
+		var shrunk, unexpandable []string
+		for i := range hashring.Replicas {
+			claim := &corev1.PersistentVolumeClaim{}
+			key := client.ObjectKey{Namespace: receiver.GetNamespace(), Name: manifestreceive.DataVolumeClaimName(name, i)}
+			if err := c.Get(ctx, key, claim); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return nil, nil, err
+			}
+
+			current := claim.Spec.Resources.Requests[corev1.ResourceStorage]
+			switch size.Cmp(current) {
+			case 0:
+				continue
+			case -1:
+				shrunk = append(shrunk, claim.GetName())
+				continue
+			}
+
+			class := ptr.Deref(claim.Spec.StorageClassName, "")
+			allowed, ok := expandable[class]
+			if !ok {
+				if allowed, err = storageClassAllowsExpansion(ctx, c, class); err != nil {
+					return nil, nil, err
+				}
+				expandable[class] = allowed
+			}
+			if !allowed {
+				unexpandable = append(unexpandable, claim.GetName())
+				continue
+			}
+
+			patch := client.MergeFrom(claim.DeepCopy())
+			if claim.Spec.Resources.Requests == nil {
+				claim.Spec.Resources.Requests = corev1.ResourceList{}
+			}
+			claim.Spec.Resources.Requests[corev1.ResourceStorage] = size
+			if err := c.Patch(ctx, claim, patch); err != nil {
+				return nil, nil, fmt.Errorf("failed to expand volume claim %s: %w", claim.GetName(), err)
+			}
+			expanded = append(expanded, claim.GetName())
+		}
+		if len(shrunk) > 0 {
+			blocked = append(blocked, fmt.Sprintf("the volumes %s of hashring %s are larger than its storage size of %s, and volumes cannot be shrunk",
+				strings.Join(shrunk, ", "), hashring.Name, size.String()))
+		}
+		if len(unexpandable) > 0 {
+			blocked = append(blocked, fmt.Sprintf("the volumes %s of hashring %s cannot be expanded to %s, as their storage class does not allow volume expansion",
+				strings.Join(unexpandable, ", "), hashring.Name, size.String()))
+		}
+	}
+	return expanded, blocked, nil
+}
+
+// storageClassAllowsExpansion returns true if the named storage class allows volume expansion.
+// Claims without a storage class, or whose storage class no longer exists, cannot be expanded.
+func storageClassAllowsExpansion(ctx context.Context, c client.Client, name string) (bool, error) {
+	if name == "" {
+		return false, nil
+	}
+	class := &storagev1.StorageClass{}
+	if err := c.Get(ctx, client.ObjectKey{Name: name}, class); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return ptr.Deref(class.AllowVolumeExpansion, false), nil
+}
+
+// volumeResizeBlockedCondition returns the VolumeResizeBlocked condition for the messages returned by expandIngesterVolumes.
+func volumeResizeBlockedCondition(blocked []string) metav1.Condition {
+	if len(blocked) == 0 {
+		return metav1.Condition{
+			Type:    ConditionVolumeResizeBlocked,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonVolumeSizesApplied,
+			Message: "The volumes of all hashrings match their storage size",
+		}
+	}
+	return metav1.Condition{
+		Type:    ConditionVolumeResizeBlocked,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonVolumeResizeUnsupported,
+		Message: strings.Join(blocked, "; "),
+	}
+}
@@ -0,0 +1,170 @@
+package controller
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReadIngesterVolumes(t *testing.T) {
+	claim := func(name, volume string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+			Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: volume},
+		}
+	}
+	zonal := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-0"},
+		Spec: corev1.PersistentVolumeSpec{NodeAffinity: &corev1.VolumeNodeAffinity{Required: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{
+				{Key: "topology.ebs.csi.aws.com/zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"eu-west-1a"}},
+			}}},
+		}}},
+	}
+	local := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-1", Labels: map[string]string{corev1.LabelTopologyZone: "eu-west-1b"}},
+		Spec: corev1.PersistentVolumeSpec{NodeAffinity: &corev1.VolumeNodeAffinity{Required: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{
+				{Key: corev1.LabelHostname, Operator: corev1.NodeSelectorOpIn, Values: []string{"node-1"}},
+			}}},
+		}}},
+	}
+
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		claim("data-thanos-receive-ingester-test-default-0", "pv-0"),
+		claim("data-thanos-receive-ingester-test-default-1", "pv-1"),
+		claim("data-thanos-receive-ingester-test-default-2", ""),
+		zonal, local,
+	).Build()
+
+	receiver := v1alpha1.ThanosReceive{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"},
+		Spec: v1alpha1.ThanosReceiveSpec{Ingester: v1alpha1.IngesterSpec{
+			Hashrings: []v1alpha1.IngesterHashringSpec{{Name: "default", Replicas: 4}},
+		}},
+	}
+	volumes, err := readIngesterVolumes(context.Background(), c, receiver)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []v1alpha1.IngesterVolumeStatus{
+		{Claim: "data-thanos-receive-ingester-test-default-0", Volume: "pv-0", Zone: "eu-west-1a"},
+		{Claim: "data-thanos-receive-ingester-test-default-1", Volume: "pv-1", Zone: "eu-west-1b", Node: "node-1"},
+		{Claim: "data-thanos-receive-ingester-test-default-2"},
+	}
+	if !slices.Equal(volumes["default"], want) {
+		t.Errorf("expected volumes %+v, got %+v", want, volumes["default"])
+	}
+}
+
+func TestVolumeZonesDegraded(t *testing.T) {
+	volume := func(zone string) v1alpha1.IngesterVolumeStatus {
+		return v1alpha1.IngesterVolumeStatus{Claim: "claim", Volume: "pv", Zone: zone}
+	}
+	volumes := map[string][]v1alpha1.IngesterVolumeStatus{
+		"spread":    {volume("a"), volume("b"), volume("c")},
+		"one-zone":  {volume("a"), volume("a"), volume("a")},
+		"no-zones":  {volume(""), volume(""), volume("")},
+		"unbound":   {volume("a"), {Claim: "claim"}},
+		"two-zones": {volume("a"), volume("b"), volume("a")},
+	}
+
+	got := volumeZonesDegraded(3, []string{"spread", "one-zone", "no-zones", "unbound", "two-zones"}, volumes)
+	want := []string{
+		"the 3 bound volumes of hashring one-zone are in 1 zones (a), fewer than the replication factor of 3",
+		"the 3 bound volumes of hashring two-zones are in 2 zones (a, b), fewer than the replication factor of 3",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	condition := volumeZonesDegradedCondition(got)
+	if condition.Status != metav1.ConditionTrue || condition.Reason != ReasonVolumesInTooFewZones {
+		t.Errorf("expected degraded condition, got %+v", condition)
+	}
+	condition = volumeZonesDegradedCondition(nil)
+	if condition.Status != metav1.ConditionFalse || condition.Reason != ReasonVolumesSpreadAcrossZones {
+		t.Errorf("expected healthy condition, got %+v", condition)
+	}
+}
+
+func TestExpandIngesterVolumes(t *testing.T) {
+	claim := func(name, class, size string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: ptr.To(class),
+				Resources: corev1.VolumeResourceRequirements{Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse(size),
+				}},
+			},
+		}
+	}
+	class := func(name string, allowExpansion bool) *storagev1.StorageClass {
+		return &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: name}, AllowVolumeExpansion: ptr.To(allowExpansion)}
+	}
+
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = storagev1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		claim("data-thanos-receive-ingester-test-default-0", "expandable", "10Gi"),
+		claim("data-thanos-receive-ingester-test-default-1", "expandable", "20Gi"),
+		claim("data-thanos-receive-ingester-test-fixed-0", "fixed", "10Gi"),
+		claim("data-thanos-receive-ingester-test-large-0", "expandable", "50Gi"),
+		class("expandable", true),
+		class("fixed", false),
+	).Build()
+
+	receiver := v1alpha1.ThanosReceive{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"},
+		Spec: v1alpha1.ThanosReceiveSpec{Ingester: v1alpha1.IngesterSpec{
+			Hashrings: []v1alpha1.IngesterHashringSpec{
+				{Name: "default", Replicas: 3, StorageConfiguration: v1alpha1.StorageConfiguration{Size: "20Gi"}},
+				{Name: "fixed", Replicas: 1, StorageConfiguration: v1alpha1.StorageConfiguration{Size: "20Gi"}},
+				{Name: "large", Replicas: 1, StorageConfiguration: v1alpha1.StorageConfiguration{Size: "20Gi"}},
+			},
+		}},
+	}
+	ctx := context.Background()
+	expanded, blocked, err := expandIngesterVolumes(ctx, c, receiver)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"data-thanos-receive-ingester-test-default-0"}; !slices.Equal(expanded, want) {
+		t.Errorf("expected expanded claims %v, got %v", want, expanded)
+	}
+	if len(blocked) != 2 || !strings.Contains(blocked[0], "hashring fixed cannot be expanded to 20Gi") || !strings.Contains(blocked[1], "volumes cannot be shrunk") {
+		t.Errorf("unexpected blocked messages %v", blocked)
+	}
+
+	got := &corev1.PersistentVolumeClaim{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "ns", Name: "data-thanos-receive-ingester-test-default-0"}, got); err != nil {
+		t.Fatal(err)
+	}
+	if size := got.Spec.Resources.Requests[corev1.ResourceStorage]; size.String() != "20Gi" {
+		t.Errorf("expected the claim to be expanded to 20Gi, got %s", size.String())
+	}
+
+	if condition := volumeResizeBlockedCondition(blocked); condition.Status != metav1.ConditionTrue || condition.Reason != ReasonVolumeResizeUnsupported {
+		t.Errorf("unexpected condition %+v", condition)
+	}
+	if condition := volumeResizeBlockedCondition(nil); condition.Status != metav1.ConditionFalse || condition.Reason != ReasonVolumeSizesApplied {
+		t.Errorf("unexpected condition %+v", condition)
+	}
+}
@@ -0,0 +1,281 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+
+	"github.com/go-logr/logr"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+	"github.com/thanos-community/thanos-operator/internal/pkg/featuregate"
+	"github.com/thanos-community/thanos-operator/internal/pkg/metrics"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// maxRenderPasses bounds the number of times the resources are reconciled while rendering.
+// Resources read the objects generated for other resources, such as the Services discovered by a ThanosQuery,
+// so they are reconciled until the generated objects no longer change.
+const maxRenderPasses = 5
+
+// RenderConfig configures the rendering of the objects generated by the operator.
+type RenderConfig struct {
+	// FeatureGate holds information about enabled features.
+	FeatureGate featuregate.Config
+	// ConfigReloaderImage is the image of the config reloader sidecar of the Thanos Ruler.
+	ConfigReloaderImage string
+}
+
+// RenderResult holds the objects generated by the operator for a set of resources.
+type RenderResult struct {
+	// Objects are the generated objects, sorted by kind, namespace and name.
+	Objects []*unstructured.Unstructured
+	// Warnings are the issues that would keep the generated workloads from running, such as missing Secrets
+	// or invalid object storage configurations.
+	Warnings []string
+}
+
+// renderedResourceLists are the kinds of resources of the operator that can be generated by a ThanosStack.
+var renderedResourceLists = []func() client.ObjectList{
+	func() client.ObjectList { return &v1alpha1.ThanosReceiveList{} },
+	func() client.ObjectList { return &v1alpha1.ThanosQueryList{} },
+	func() client.ObjectList { return &v1alpha1.ThanosStoreList{} },
+	func() client.ObjectList { return &v1alpha1.ThanosCompactList{} },
+	func() client.ObjectList { return &v1alpha1.ThanosRulerList{} },
+}
+
+// renderedObjectLists are the kinds of objects that can be generated for the resources of the operator.
+var renderedObjectLists = []func() client.ObjectList{
+	func() client.ObjectList { return &corev1.ConfigMapList{} },
+	func() client.ObjectList { return &corev1.SecretList{} },
+	func() client.ObjectList { return &corev1.ServiceList{} },
+	func() client.ObjectList { return &corev1.ServiceAccountList{} },
+	func() client.ObjectList { return &appsv1.DeploymentList{} },
+	func() client.ObjectList { return &appsv1.StatefulSetList{} },
+	func() client.ObjectList { return &batchv1.CronJobList{} },
+	func() client.ObjectList { return &policyv1.PodDisruptionBudgetList{} },
+	func() client.ObjectList { return &rbacv1.RoleList{} },
+	func() client.ObjectList { return &rbacv1.RoleBindingList{} },
+	func() client.ObjectList { return &networkingv1.IngressList{} },
+	func() client.ObjectList { return &networkingv1.NetworkPolicyList{} },
+	func() client.ObjectList { return &monitoringv1.ServiceMonitorList{} },
+	func() client.ObjectList { return &gatewayv1.HTTPRouteList{} },
+}
+
+// renderedResource is a kind of resource of the operator reconciled while rendering.
+type renderedResource struct {
+	list       func() client.ObjectList
+	reconciler reconcile.Reconciler
+}
+
+// Render generates the objects the operator manages for the resources in objs, without a cluster.
+// The resources are reconciled by the controllers of the operator against an in-memory client holding objs,
+// so other objects in objs, such as the Secrets referenced by the resources, are read as they would be from the cluster.
+// Objects that depend on the state of a cluster, such as the endpoints of a hashring, are rendered as they are before
+// the workloads start. The objects in objs are not part of the result.
+func Render(ctx context.Context, scheme *runtime.Scheme, conf RenderConfig, objs []client.Object) (*RenderResult, error) {
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(
+			&v1alpha1.ThanosStack{},
+			&v1alpha1.ThanosReceive{},
+			&v1alpha1.ThanosQuery{},
+			&v1alpha1.ThanosStore{},
+			&v1alpha1.ThanosCompact{},
+			&v1alpha1.ThanosRuler{},
+		).
+		Build()
+
+	reg := prometheus.NewRegistry()
+	controllerConf := Config{
+		FeatureGate: conf.FeatureGate,
+		InstrumentationConfig: InstrumentationConfig{
+			Logger:          logr.Discard(),
+			EventRecorder:   &events.FakeRecorder{},
+			MetricsRegistry: reg,
+			CommonMetrics:   metrics.NewCommonMetrics(reg),
+		},
+	}
+	// ThanosStack resources are reconciled first, since they create the other resources
+	resources := []renderedResource{
+		{list: func() client.ObjectList { return &v1alpha1.ThanosStackList{} }, reconciler: NewThanosStackReconciler(controllerConf, c, scheme)},
+		{list: func() client.ObjectList { return &v1alpha1.ThanosReceiveList{} }, reconciler: NewThanosReceiveReconciler(controllerConf, c, scheme)},
+		{list: func() client.ObjectList { return &v1alpha1.ThanosStoreList{} }, reconciler: NewThanosStoreReconciler(controllerConf, c, scheme)},
+		{list: func() client.ObjectList { return &v1alpha1.ThanosCompactList{} }, reconciler: NewThanosCompactReconciler(controllerConf, c, scheme)},
+		{list: func() client.ObjectList { return &v1alpha1.ThanosRulerList{} }, reconciler: NewThanosRulerReconciler(controllerConf, conf.ConfigReloaderImage, c, scheme)},
+		{list: func() client.ObjectList { return &v1alpha1.ThanosQueryList{} }, reconciler: NewThanosQueryReconciler(controllerConf, c, scheme)},
+	}
+
+	var (
+		errs     []error
+		previous map[string]string
+	)
+	for range maxRenderPasses {
+		errs = nil
+		for _, resource := range resources {
+			list := resource.list()
+			if err := c.List(ctx, list); err != nil {
+				return nil, err
+			}
+			items, err := meta.ExtractList(list)
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range items {
+				obj := item.(client.Object)
+				req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(obj)}
+				if _, err := resource.reconciler.Reconcile(ctx, req); err != nil {
+					errs = append(errs, fmt.Errorf("%s/%s: %w", kindOf(scheme, obj), obj.GetName(), err))
+				}
+			}
+		}
+
+		versions, err := renderedVersions(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		if previous != nil && maps.Equal(previous, versions) {
+			break
+		}
+		previous = versions
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	inputs := make(map[string]bool, len(objs))
+	for _, obj := range objs {
+		inputs[renderKey(kindOf(scheme, obj), obj)] = true
+	}
+	result := &RenderResult{}
+	for _, newList := range slices.Concat(renderedResourceLists, renderedObjectLists) {
+		list := newList()
+		if err := c.List(ctx, list); err != nil {
+			return nil, err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			obj := item.(client.Object)
+			kind := kindOf(scheme, obj)
+			if inputs[renderKey(kind, obj)] {
+				continue
+			}
+			out, err := renderedObject(scheme, obj)
+			if err != nil {
+				return nil, err
+			}
+			result.Objects = append(result.Objects, out)
+		}
+	}
+	sort.SliceStable(result.Objects, func(i, j int) bool {
+		return renderKey(result.Objects[i].GetKind(), result.Objects[i]) < renderKey(result.Objects[j].GetKind(), result.Objects[j])
+	})
+
+	for _, resource := range resources {
+		list := resource.list()
+		if err := c.List(ctx, list); err != nil {
+			return nil, err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			obj := item.(client.Object)
+			u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+			if err != nil {
+				return nil, err
+			}
+			conditions, _, _ := unstructured.NestedSlice(u, "status", "conditions")
+			for _, condition := range conditions {
+				condition, _ := condition.(map[string]any)
+				dependencyMissing := condition["type"] == ConditionDependencyMissing
+				invalidObjStore := condition["type"] == ConditionDegraded && condition["reason"] == ReasonInvalidObjectStorageConfig
+				if (dependencyMissing || invalidObjStore) && condition["status"] == string(metav1.ConditionTrue) {
+					result.Warnings = append(result.Warnings, fmt.Sprintf("%s/%s: %v", kindOf(scheme, obj), obj.GetName(), condition["message"]))
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// renderedVersions returns the resource versions of the objects generated for the resources of the operator,
+// by object. The resources of the operator are left out, since their status is updated on every reconcile.
+func renderedVersions(ctx context.Context, c client.Client) (map[string]string, error) {
+	versions := make(map[string]string)
+	for _, newList := range renderedObjectLists {
+		list := newList()
+		if err := c.List(ctx, list); err != nil {
+			return nil, err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			obj := item.(client.Object)
+			versions[fmt.Sprintf("%T/%s/%s", obj, obj.GetNamespace(), obj.GetName())] = obj.GetResourceVersion()
+		}
+	}
+	return versions, nil
+}
+
+// renderedObject converts a generated object to the form it is printed in, without the fields set by the client.
+// Owner references are dropped, since the owners have no UID until they are created.
+func renderedObject(scheme *runtime.Scheme, obj client.Object) (*unstructured.Unstructured, error) {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return nil, err
+	}
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	out := &unstructured.Unstructured{Object: u}
+	out.SetGroupVersionKind(gvk)
+	out.SetResourceVersion("")
+	out.SetOwnerReferences(nil)
+	unstructured.RemoveNestedField(out.Object, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(out.Object, "status")
+	return out, nil
+}
+
+func kindOf(scheme *runtime.Scheme, obj client.Object) string {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return fmt.Sprintf("%T", obj)
+	}
+	return gvk.Kind
+}
+
+func renderKey(kind string, obj metav1.Object) string {
+	return kind + "/" + obj.GetNamespace() + "/" + obj.GetName()
+}
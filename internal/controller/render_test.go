@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestRender(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	_ = monitoringv1.AddToScheme(scheme)
+	_ = gatewayv1.AddToScheme(scheme)
+
+	stack := &v1alpha1.ThanosStack{
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "ns"},
+		Spec: v1alpha1.ThanosStackSpec{
+			ObjectStorageConfig: v1alpha1.ObjectStorageConfig{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "objstore"},
+				Key:                  "thanos.yaml",
+			},
+			Receive: v1alpha1.StackReceiveSpec{
+				StackComponentSpec: v1alpha1.StackComponentSpec{Replicas: 1},
+				IngesterReplicas:   1,
+				ReplicationFactor:  1,
+				Retention:          "2h",
+			},
+			Query:   v1alpha1.StackQuerySpec{StackComponentSpec: v1alpha1.StackComponentSpec{Replicas: 1}, QueryFrontend: ptr.To(false)},
+			Store:   v1alpha1.StackStoreSpec{StackComponentSpec: v1alpha1.StackComponentSpec{Replicas: 1}},
+			Compact: v1alpha1.StackCompactSpec{Enabled: ptr.To(false)},
+		},
+	}
+	objstore := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "objstore", Namespace: "ns"},
+		Data:       map[string][]byte{"thanos.yaml": []byte("type: FILESYSTEM\nconfig:\n  directory: /data\n")},
+	}
+
+	result, err := Render(context.Background(), scheme, RenderConfig{}, []client.Object{stack, objstore})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rendered := make(map[string]bool)
+	for _, obj := range result.Objects {
+		rendered[renderKey(obj.GetKind(), obj)] = true
+		if len(obj.GetOwnerReferences()) > 0 || obj.GetResourceVersion() != "" {
+			t.Errorf("expected %s/%s to have no fields set by the client, got %v", obj.GetKind(), obj.GetName(), obj.Object["metadata"])
+		}
+	}
+	for _, key := range []string{
+		"ThanosReceive/ns/example",
+		"ThanosQuery/ns/example",
+		"ThanosStore/ns/example",
+		"StatefulSet/ns/thanos-store-example",
+		"Deployment/ns/thanos-query-example",
+		"ConfigMap/ns/thanos-receive-router-example",
+	} {
+		if !rendered[key] {
+			t.Errorf("expected %s to be rendered, got %v", key, rendered)
+		}
+	}
+	for _, key := range []string{"ThanosStack/ns/example", "Secret/ns/objstore", "ThanosCompact/ns/example"} {
+		if rendered[key] {
+			t.Errorf("expected %s not to be rendered", key)
+		}
+	}
+
+	// the hashring has no ready endpoints without a cluster
+	if len(result.Warnings) != 1 {
+		t.Errorf("expected a warning about the hashring, got %v", result.Warnings)
+	}
+	var sts appsv1.StatefulSet
+	for _, obj := range result.Objects {
+		if obj.GetKind() == "StatefulSet" && obj.GetName() == "thanos-store-example" {
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &sts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+	if ptr.Deref(sts.Spec.Replicas, 0) != 1 {
+		t.Errorf("expected the store to have 1 replica, got %v", sts.Spec.Replicas)
+	}
+}
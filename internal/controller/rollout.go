@@ -0,0 +1,149 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	monitoringthanosiov1alpha1 "github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// workloadRollout is the rollout state of a Deployment or StatefulSet managed for a resource.
+type workloadRollout struct {
+	// name identifies the workload in condition messages.
+	name string
+	// found is false if the workload does not exist yet.
+	found bool
+	// observed is true if the workload controller has observed the latest workload spec.
+	observed bool
+
+	desiredReplicas int32
+	replicas        int32
+	updatedReplicas int32
+	readyReplicas   int32
+}
+
+func deploymentRollout(name string, deployment *appsv1.Deployment) workloadRollout {
+	if deployment == nil {
+		return workloadRollout{name: name}
+	}
+	return workloadRollout{
+		name:            name,
+		found:           true,
+		observed:        deployment.Status.ObservedGeneration >= deployment.Generation,
+		desiredReplicas: ptr.Deref(deployment.Spec.Replicas, 1),
+		replicas:        deployment.Status.Replicas,
+		updatedReplicas: deployment.Status.UpdatedReplicas,
+		readyReplicas:   deployment.Status.ReadyReplicas,
+	}
+}
+
+func statefulSetRollout(name string, statefulSet *appsv1.StatefulSet) workloadRollout {
+	if statefulSet == nil {
+		return workloadRollout{name: name}
+	}
+	return workloadRollout{
+		name:            name,
+		found:           true,
+		observed:        statefulSet.Status.ObservedGeneration >= statefulSet.Generation,
+		desiredReplicas: ptr.Deref(statefulSet.Spec.Replicas, 1),
+		replicas:        statefulSet.Status.Replicas,
+		updatedReplicas: statefulSet.Status.UpdatedReplicas,
+		readyReplicas:   statefulSet.Status.ReadyReplicas,
+	}
+}
+
+// toDeploymentStatus returns the replica counts of the Deployment to record in the status of the resource that manages it.
+func toDeploymentStatus(deployment *appsv1.Deployment) monitoringthanosiov1alpha1.DeploymentStatus {
+	return monitoringthanosiov1alpha1.DeploymentStatus{
+		Replicas:            deployment.Status.Replicas,
+		UpdatedReplicas:     deployment.Status.UpdatedReplicas,
+		AvailableReplicas:   deployment.Status.AvailableReplicas,
+		UnavailableReplicas: deployment.Status.UnavailableReplicas,
+		ReadyReplicas:       deployment.Status.ReadyReplicas,
+	}
+}
+
+// getWorkload gets the workload with the given name into obj. It returns false if the workload does not exist.
+func getWorkload(ctx context.Context, c client.Client, namespace, name string, obj client.Object) (bool, error) {
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// progressing returns true if the latest workload spec is not rolled out to all desired replicas yet.
+func (w workloadRollout) progressing() bool {
+	return !w.found || !w.observed || w.updatedReplicas < w.desiredReplicas || w.replicas > w.desiredReplicas
+}
+
+// rolloutConditions returns the Available, Progressing and Degraded conditions for the given workloads.
+// A resource is available once every workload has at least one ready replica, and degraded
+// if a workload that finished rolling out has fewer ready replicas than desired.
+func rolloutConditions(workloads []workloadRollout) []metav1.Condition {
+	var unavailable, progressing, notReady []string
+	for _, w := range workloads {
+		switch {
+		case !w.found:
+			unavailable = append(unavailable, fmt.Sprintf("%s does not exist", w.name))
+			progressing = append(progressing, fmt.Sprintf("%s is being created", w.name))
+			continue
+		case w.readyReplicas == 0 && w.desiredReplicas > 0:
+			unavailable = append(unavailable, fmt.Sprintf("%s has no ready replicas", w.name))
+		}
+
+		if w.progressing() {
+			progressing = append(progressing, fmt.Sprintf("%s has %d/%d updated replicas", w.name, w.updatedReplicas, w.desiredReplicas))
+		} else if w.readyReplicas < w.desiredReplicas {
+			notReady = append(notReady, fmt.Sprintf("%s has %d/%d ready replicas", w.name, w.readyReplicas, w.desiredReplicas))
+		}
+	}
+
+	available := metav1.Condition{
+		Type:    ConditionAvailable,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonMinimumReplicasAvailable,
+		Message: "All workloads have ready replicas",
+	}
+	if len(unavailable) > 0 {
+		available.Status = metav1.ConditionFalse
+		available.Reason = ReasonReplicasUnavailable
+		available.Message = strings.Join(unavailable, "; ")
+	}
+
+	rollout := metav1.Condition{
+		Type:    ConditionProgressing,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonRolloutComplete,
+		Message: "All workloads are rolled out",
+	}
+	if len(progressing) > 0 {
+		rollout.Status = metav1.ConditionTrue
+		rollout.Reason = ReasonRollingOut
+		rollout.Message = strings.Join(progressing, "; ")
+	}
+
+	degraded := metav1.Condition{
+		Type:    ConditionDegraded,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonAllReplicasReady,
+		Message: "All rolled out workloads have all their replicas ready",
+	}
+	if len(notReady) > 0 {
+		degraded.Status = metav1.ConditionTrue
+		degraded.Reason = ReasonReplicasNotReady
+		degraded.Message = strings.Join(notReady, "; ")
+	}
+
+	return []metav1.Condition{available, rollout, degraded}
+}
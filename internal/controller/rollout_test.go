@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestRolloutConditions(t *testing.T) {
+	ready := func(name string) workloadRollout {
+		return statefulSetRollout(name, &appsv1.StatefulSet{
+			Spec:   appsv1.StatefulSetSpec{Replicas: ptr.To(int32(3))},
+			Status: appsv1.StatefulSetStatus{Replicas: 3, UpdatedReplicas: 3, ReadyReplicas: 3},
+		})
+	}
+
+	for _, tc := range []struct {
+		name      string
+		workloads []workloadRollout
+		expect    map[string]metav1.ConditionStatus
+		messages  map[string]string
+	}{
+		{
+			name:      "rolled out",
+			workloads: []workloadRollout{ready("StatefulSet/a"), ready("StatefulSet/b")},
+			expect: map[string]metav1.ConditionStatus{
+				ConditionAvailable:   metav1.ConditionTrue,
+				ConditionProgressing: metav1.ConditionFalse,
+				ConditionDegraded:    metav1.ConditionFalse,
+			},
+		},
+		{
+			name: "rolling out",
+			workloads: []workloadRollout{ready("StatefulSet/a"), deploymentRollout("Deployment/router", &appsv1.Deployment{
+				Spec:   appsv1.DeploymentSpec{Replicas: ptr.To(int32(2))},
+				Status: appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 1, ReadyReplicas: 2},
+			})},
+			expect: map[string]metav1.ConditionStatus{
+				ConditionAvailable:   metav1.ConditionTrue,
+				ConditionProgressing: metav1.ConditionTrue,
+				ConditionDegraded:    metav1.ConditionFalse,
+			},
+			messages: map[string]string{ConditionProgressing: "Deployment/router has 1/2 updated replicas"},
+		},
+		{
+			name:      "not created yet",
+			workloads: []workloadRollout{ready("StatefulSet/a"), statefulSetRollout("StatefulSet/b", nil)},
+			expect: map[string]metav1.ConditionStatus{
+				ConditionAvailable:   metav1.ConditionFalse,
+				ConditionProgressing: metav1.ConditionTrue,
+				ConditionDegraded:    metav1.ConditionFalse,
+			},
+			messages: map[string]string{ConditionAvailable: "StatefulSet/b does not exist"},
+		},
+		{
+			name: "rolled out with unready replicas",
+			workloads: []workloadRollout{statefulSetRollout("StatefulSet/a", &appsv1.StatefulSet{
+				Spec:   appsv1.StatefulSetSpec{Replicas: ptr.To(int32(3))},
+				Status: appsv1.StatefulSetStatus{Replicas: 3, UpdatedReplicas: 3, ReadyReplicas: 0},
+			})},
+			expect: map[string]metav1.ConditionStatus{
+				ConditionAvailable:   metav1.ConditionFalse,
+				ConditionProgressing: metav1.ConditionFalse,
+				ConditionDegraded:    metav1.ConditionTrue,
+			},
+			messages: map[string]string{
+				ConditionAvailable: "StatefulSet/a has no ready replicas",
+				ConditionDegraded:  "StatefulSet/a has 0/3 ready replicas",
+			},
+		},
+		{
+			name: "spec not observed",
+			workloads: []workloadRollout{statefulSetRollout("StatefulSet/a", &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To(int32(3))},
+				Status:     appsv1.StatefulSetStatus{ObservedGeneration: 1, Replicas: 3, UpdatedReplicas: 3, ReadyReplicas: 3},
+			})},
+			expect: map[string]metav1.ConditionStatus{
+				ConditionAvailable:   metav1.ConditionTrue,
+				ConditionProgressing: metav1.ConditionTrue,
+				ConditionDegraded:    metav1.ConditionFalse,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			conditions := rolloutConditions(tc.workloads)
+			if len(conditions) != len(tc.expect) {
+				t.Fatalf("expected %d conditions, got %d", len(tc.expect), len(conditions))
+			}
+			for _, c := range conditions {
+				if c.Status != tc.expect[c.Type] {
+					t.Errorf("expected %s to be %s, got %s: %s", c.Type, tc.expect[c.Type], c.Status, c.Message)
+				}
+				if msg, ok := tc.messages[c.Type]; ok && c.Message != msg {
+					t.Errorf("expected %s message %q, got %q", c.Type, msg, c.Message)
+				}
+			}
+		})
+	}
+}
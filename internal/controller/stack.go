@@ -0,0 +1,242 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// stackHashringName is the name of the hashring of the ThanosReceive managed by a ThanosStack.
+	stackHashringName = "default"
+	// stackIngesterReplicaLabel and stackRulerReplicaLabel are the replica labels of the ingesters and rulers
+	// managed by a ThanosStack, along which the ThanosQuery of the stack deduplicates.
+	stackIngesterReplicaLabel = "replica"
+	stackRulerReplicaLabel    = "rule_replica"
+)
+
+var (
+	// defaultStackStorage is the storage of the components of a ThanosStack that do not set one.
+	defaultStackStorage = v1alpha1.StorageConfiguration{Size: "10Gi"}
+	// defaultStackRetention is the retention of the blocks of a ThanosStack that does not set one.
+	defaultStackRetention = v1alpha1.RetentionResolutionConfig{Raw: "30d", FiveMinutes: "90d", OneHour: "1y"}
+)
+
+// stackComponent is a resource managed by a ThanosStack.
+type stackComponent struct {
+	// kind is the kind of the resource, reported in the status of the stack.
+	kind string
+	// desired is the resource as declared by the stack.
+	desired client.Object
+	// enabled is false if the resource must not exist.
+	enabled bool
+}
+
+// stackComponents returns the resources managed by the ThanosStack, in the order in which they are applied.
+func stackComponents(stack v1alpha1.ThanosStack) []stackComponent {
+	spec := stack.Spec
+	return []stackComponent{
+		{kind: "ThanosReceive", desired: stackReceive(stack), enabled: ptr.Deref(spec.Receive.Enabled, true)},
+		{kind: "ThanosStore", desired: stackStore(stack), enabled: ptr.Deref(spec.Store.Enabled, true)},
+		{kind: "ThanosCompact", desired: stackCompact(stack), enabled: ptr.Deref(spec.Compact.Enabled, true)},
+		{kind: "ThanosQuery", desired: stackQuery(stack), enabled: ptr.Deref(spec.Query.Enabled, true)},
+		{kind: "ThanosRuler", desired: stackRuler(stack), enabled: spec.Ruler.AlertmanagerURL != ""},
+	}
+}
+
+// stackObjectMeta returns the metadata of a resource managed by the ThanosStack.
+// The resource is named after the stack, labelled with the v1alpha1.StackLabel and assigned to the operator
+// instance of the stack.
+func stackObjectMeta(stack v1alpha1.ThanosStack) metav1.ObjectMeta {
+	meta := metav1.ObjectMeta{
+		Name:      stack.GetName(),
+		Namespace: stack.GetNamespace(),
+		Labels:    map[string]string{v1alpha1.StackLabel: stack.GetName()},
+	}
+	if id, ok := stack.GetAnnotations()[v1alpha1.ControllerIDAnnotation]; ok {
+		meta.Annotations = map[string]string{v1alpha1.ControllerIDAnnotation: id}
+	}
+	return meta
+}
+
+// stackSelector selects the StoreAPIs and QueryAPIs of the ThanosStack, since the labels of a resource
+// are set on the objects generated for it.
+func stackSelector(stack v1alpha1.ThanosStack) *metav1.LabelSelector {
+	return &metav1.LabelSelector{MatchLabels: map[string]string{v1alpha1.StackLabel: stack.GetName()}}
+}
+
+func stackReceive(stack v1alpha1.ThanosStack) *v1alpha1.ThanosReceive {
+	receive := stack.Spec.Receive
+	return &v1alpha1.ThanosReceive{
+		ObjectMeta: stackObjectMeta(stack),
+		Spec: v1alpha1.ThanosReceiveSpec{
+			Router: v1alpha1.RouterSpec{
+				CommonFields:      stack.Spec.CommonFields,
+				Replicas:          ptr.To(receive.Replicas),
+				ReplicationFactor: receive.ReplicationFactor,
+				ExternalLabels:    v1alpha1.ExternalLabels{"receive": "true"},
+			},
+			Ingester: v1alpha1.IngesterSpec{
+				DefaultObjectStorageConfig: stack.Spec.ObjectStorageConfig,
+				Hashrings: []v1alpha1.IngesterHashringSpec{
+					{
+						CommonFields:         stack.Spec.CommonFields,
+						Name:                 stackHashringName,
+						Replicas:             receive.IngesterReplicas,
+						ExternalLabels:       v1alpha1.ExternalLabels{stackIngesterReplicaLabel: "$(POD_NAME)"},
+						TSDBConfig:           v1alpha1.TSDBConfig{Retention: receive.Retention},
+						StorageConfiguration: ptr.Deref(receive.StorageConfiguration, defaultStackStorage),
+					},
+				},
+			},
+		},
+	}
+}
+
+func stackStore(stack v1alpha1.ThanosStack) *v1alpha1.ThanosStore {
+	store := stack.Spec.Store
+	return &v1alpha1.ThanosStore{
+		ObjectMeta: stackObjectMeta(stack),
+		Spec: v1alpha1.ThanosStoreSpec{
+			CommonFields:             stack.Spec.CommonFields,
+			Replicas:                 store.Replicas,
+			ObjectStorageConfig:      stack.Spec.ObjectStorageConfig,
+			StorageConfiguration:     ptr.Deref(store.StorageConfiguration, defaultStackStorage),
+			IgnoreDeletionMarksDelay: "24h",
+			ShardingStrategy:         v1alpha1.ShardingStrategy{Type: v1alpha1.Block, Shards: 1},
+		},
+	}
+}
+
+func stackCompact(stack v1alpha1.ThanosStack) *v1alpha1.ThanosCompact {
+	compact := stack.Spec.Compact
+	return &v1alpha1.ThanosCompact{
+		ObjectMeta: stackObjectMeta(stack),
+		Spec: v1alpha1.ThanosCompactSpec{
+			CommonFields:         stack.Spec.CommonFields,
+			ObjectStorageConfig:  stack.Spec.ObjectStorageConfig,
+			StorageConfiguration: ptr.Deref(compact.StorageConfiguration, defaultStackStorage),
+			RetentionConfig:      ptr.Deref(compact.RetentionConfig, defaultStackRetention),
+		},
+	}
+}
+
+func stackQuery(stack v1alpha1.ThanosStack) *v1alpha1.ThanosQuery {
+	query := stack.Spec.Query
+	out := &v1alpha1.ThanosQuery{
+		ObjectMeta: stackObjectMeta(stack),
+		Spec: v1alpha1.ThanosQuerySpec{
+			CommonFields:       stack.Spec.CommonFields,
+			Replicas:           ptr.To(query.Replicas),
+			ReplicaLabels:      []string{stackIngesterReplicaLabel, stackRulerReplicaLabel},
+			StoreLabelSelector: stackSelector(stack),
+		},
+	}
+	if ptr.Deref(query.QueryFrontend, true) {
+		selector := stackSelector(stack)
+		selector.MatchLabels[manifests.DefaultQueryAPILabel] = manifests.DefaultQueryAPIValue
+		out.Spec.QueryFrontend = &v1alpha1.QueryFrontendSpec{
+			CommonFields:       stack.Spec.CommonFields,
+			Replicas:           1,
+			QueryLabelSelector: selector,
+		}
+	}
+	return out
+}
+
+func stackRuler(stack v1alpha1.ThanosStack) *v1alpha1.ThanosRuler {
+	ruler := stack.Spec.Ruler
+	return &v1alpha1.ThanosRuler{
+		ObjectMeta: stackObjectMeta(stack),
+		Spec: v1alpha1.ThanosRulerSpec{
+			CommonFields:        stack.Spec.CommonFields,
+			Replicas:            max(ruler.Replicas, 1),
+			QueryLabelSelector:  stackSelector(stack),
+			ObjectStorageConfig: stack.Spec.ObjectStorageConfig,
+			RuleConfigSelector: metav1.LabelSelector{MatchLabels: map[string]string{
+				manifests.DefaultPrometheusRuleLabel: manifests.DefaultPrometheusRuleValue,
+			}},
+			AlertmanagerURL:      ruler.AlertmanagerURL,
+			ExternalLabels:       v1alpha1.ExternalLabels{stackRulerReplicaLabel: "$(NAME)"},
+			EvaluationInterval:   "1m",
+			Retention:            "2h",
+			StorageConfiguration: ptr.Deref(ruler.StorageConfiguration, defaultStackStorage),
+		},
+	}
+}
+
+// setStackSpec sets the spec of the existing resource to the spec of the desired resource of the same kind.
+func setStackSpec(existing, desired client.Object) error {
+	switch obj := existing.(type) {
+	case *v1alpha1.ThanosReceive:
+		obj.Spec = desired.(*v1alpha1.ThanosReceive).Spec
+	case *v1alpha1.ThanosStore:
+		obj.Spec = desired.(*v1alpha1.ThanosStore).Spec
+	case *v1alpha1.ThanosCompact:
+		obj.Spec = desired.(*v1alpha1.ThanosCompact).Spec
+	case *v1alpha1.ThanosQuery:
+		obj.Spec = desired.(*v1alpha1.ThanosQuery).Spec
+	case *v1alpha1.ThanosRuler:
+		obj.Spec = desired.(*v1alpha1.ThanosRuler).Spec
+	default:
+		return fmt.Errorf("unsupported stack component %T", existing)
+	}
+	return nil
+}
+
+// stackComponentStatus returns the status of a resource managed by a ThanosStack.
+func stackComponentStatus(kind string, obj client.Object) v1alpha1.ThanosStackComponentStatus {
+	var conditions []metav1.Condition
+	switch obj := obj.(type) {
+	case *v1alpha1.ThanosReceive:
+		conditions = obj.Status.Conditions
+	case *v1alpha1.ThanosStore:
+		conditions = obj.Status.Conditions
+	case *v1alpha1.ThanosCompact:
+		conditions = obj.Status.Conditions
+	case *v1alpha1.ThanosQuery:
+		conditions = obj.Status.Conditions
+	case *v1alpha1.ThanosRuler:
+		conditions = obj.Status.Conditions
+	}
+
+	status := v1alpha1.ThanosStackComponentStatus{Kind: kind, Name: obj.GetName(), Ready: metav1.ConditionUnknown}
+	for _, condition := range conditions {
+		if condition.Type == ConditionReady {
+			status.Ready = condition.Status
+		}
+	}
+	return status
+}
+
+// stackAvailableCondition returns the Available condition of a ThanosStack, which is true once all its
+// components are ready.
+func stackAvailableCondition(components []v1alpha1.ThanosStackComponentStatus) metav1.Condition {
+	var notReady []string
+	for _, component := range components {
+		if component.Ready != metav1.ConditionTrue {
+			notReady = append(notReady, component.Kind)
+		}
+	}
+	if len(notReady) > 0 {
+		return metav1.Condition{
+			Type:    ConditionAvailable,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonComponentsNotReady,
+			Message: "Waiting for the components to be ready: " + strings.Join(notReady, ", "),
+		}
+	}
+	return metav1.Condition{
+		Type:    ConditionAvailable,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonComponentsReady,
+		Message: "All the components are ready",
+	}
+}
@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestStackSyncResources(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	stack := &v1alpha1.ThanosStack{
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "ns", UID: "stack-uid"},
+		Spec: v1alpha1.ThanosStackSpec{
+			ObjectStorageConfig: v1alpha1.ObjectStorageConfig{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "objstore"},
+				Key:                  "thanos.yaml",
+			},
+			Receive: v1alpha1.StackReceiveSpec{
+				StackComponentSpec: v1alpha1.StackComponentSpec{Replicas: 2},
+				IngesterReplicas:   3,
+				ReplicationFactor:  3,
+				Retention:          "2h",
+			},
+			Query: v1alpha1.StackQuerySpec{StackComponentSpec: v1alpha1.StackComponentSpec{Replicas: 1}},
+			Store: v1alpha1.StackStoreSpec{StackComponentSpec: v1alpha1.StackComponentSpec{Replicas: 1}},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(stack).Build()
+	r := &ThanosStackReconciler{Client: c, Scheme: scheme, logger: logr.Discard()}
+
+	components, err := r.syncResources(context.Background(), *stack)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the ruler is not deployed without an alertmanager
+	if len(components) != 4 {
+		t.Fatalf("expected 4 components, got %v", components)
+	}
+	if err := c.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "example"}, &v1alpha1.ThanosRuler{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected no ThanosRuler, got %v", err)
+	}
+	if condition := stackAvailableCondition(components); condition.Status != metav1.ConditionFalse {
+		t.Errorf("expected the stack to wait for its components, got %+v", condition)
+	}
+
+	receive := &v1alpha1.ThanosReceive{}
+	if err := c.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "example"}, receive); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !metav1.IsControlledBy(receive, stack) {
+		t.Errorf("expected the ThanosReceive to be owned by the stack, got %v", receive.GetOwnerReferences())
+	}
+	if receive.GetLabels()[v1alpha1.StackLabel] != "example" {
+		t.Errorf("expected the ThanosReceive to be labelled with the stack, got %v", receive.GetLabels())
+	}
+	hashring := receive.Spec.Ingester.Hashrings[0]
+	if receive.Spec.Router.ReplicationFactor != 3 || hashring.Replicas != 3 || hashring.StorageConfiguration.Size != "10Gi" {
+		t.Errorf("unexpected ThanosReceive spec %+v", receive.Spec)
+	}
+	if receive.Spec.Ingester.DefaultObjectStorageConfig.Name != "objstore" {
+		t.Errorf("expected the ingesters to use the object storage of the stack, got %+v", receive.Spec.Ingester.DefaultObjectStorageConfig)
+	}
+
+	query := &v1alpha1.ThanosQuery{}
+	if err := c.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "example"}, query); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if query.Spec.StoreLabelSelector.MatchLabels[v1alpha1.StackLabel] != "example" {
+		t.Errorf("expected the ThanosQuery to only discover the stores of the stack, got %v", query.Spec.StoreLabelSelector)
+	}
+	if query.Spec.QueryFrontend == nil {
+		t.Error("expected a query frontend by default")
+	}
+
+	// disabling a component deletes its resource, and enabling the ruler deploys it
+	stack.Spec.Store.Enabled = ptr.To(false)
+	stack.Spec.Ruler = v1alpha1.StackRulerSpec{Replicas: 1, AlertmanagerURL: "http://alertmanager.example.com:9093"}
+	if _, err := r.syncResources(context.Background(), *stack); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "example"}, &v1alpha1.ThanosStore{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the ThanosStore to be deleted, got %v", err)
+	}
+	ruler := &v1alpha1.ThanosRuler{}
+	if err := c.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "example"}, ruler); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ruler.Spec.AlertmanagerURL != "http://alertmanager.example.com:9093" || ruler.Spec.ObjectStorageConfig.Name != "objstore" {
+		t.Errorf("unexpected ThanosRuler spec %+v", ruler.Spec)
+	}
+}
+
+func TestStackAvailableCondition(t *testing.T) {
+	components := []v1alpha1.ThanosStackComponentStatus{
+		{Kind: "ThanosReceive", Name: "example", Ready: metav1.ConditionTrue},
+		{Kind: "ThanosQuery", Name: "example", Ready: metav1.ConditionTrue},
+	}
+	if condition := stackAvailableCondition(components); condition.Status != metav1.ConditionTrue || condition.Reason != ReasonComponentsReady {
+		t.Errorf("expected the stack to be available, got %+v", condition)
+	}
+	components[1].Ready = metav1.ConditionUnknown
+	if condition := stackAvailableCondition(components); condition.Status != metav1.ConditionFalse || condition.Reason != ReasonComponentsNotReady {
+		t.Errorf("expected the stack to be unavailable, got %+v", condition)
+	}
+}
@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	"k8s.io/utils/ptr"
+)
+
+// storeShardTimeRanges returns the time range served by each time shard of the store.
+// The time ranges are either listed explicitly, or split from a single time range.
+func storeShardTimeRanges(strategy v1alpha1.ShardingStrategy) ([]v1alpha1.TimeRangeConfig, error) {
+	if strategy.TimeRange == nil {
+		return strategy.TimeRanges, nil
+	}
+	return splitTimeRange(*strategy.TimeRange, max(strategy.Shards, 1))
+}
+
+// splitTimeRange splits the time range into the given number of consecutive time ranges of the same length,
+// starting with the most recent one. The outer bounds are kept as they are, so that an unset MaxTime still follows
+// the current time. Adjacent ranges share their bound, blocks overlapping it are served by both shards and deduplicated
+// by the queriers.
+func splitTimeRange(timeRange v1alpha1.TimeRangeConfig, shards int32) ([]v1alpha1.TimeRangeConfig, error) {
+	if timeRange.MinTime == nil {
+		return nil, fmt.Errorf("minTime must be set to split the time range")
+	}
+	if shards == 1 {
+		return []v1alpha1.TimeRangeConfig{timeRange}, nil
+	}
+
+	bounds, err := timeRangeBounds(string(*timeRange.MinTime), string(ptr.Deref(timeRange.MaxTime, "0")), int(shards))
+	if err != nil {
+		return nil, err
+	}
+
+	ranges := make([]v1alpha1.TimeRangeConfig, shards)
+	for i := range ranges {
+		// the first shard serves the most recent data
+		lower, upper := len(bounds)-2-i, len(bounds)-1-i
+		ranges[i] = v1alpha1.TimeRangeConfig{
+			MinTime: ptr.To(v1alpha1.Duration(bounds[lower])),
+			MaxTime: ptr.To(v1alpha1.Duration(bounds[upper])),
+		}
+	}
+	ranges[0].MaxTime = timeRange.MaxTime
+	ranges[len(ranges)-1].MinTime = timeRange.MinTime
+	return ranges, nil
+}
+
+// timeRangeBounds returns the shards+1 bounds splitting the time range from minTime to maxTime, in ascending order.
+// minTime and maxTime must either both be relative durations or both be timestamps.
+func timeRangeBounds(minTime, maxTime string, shards int) ([]string, error) {
+	minAgo, minRelative, err := parseRelativeTime(minTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid minTime %s: %w", minTime, err)
+	}
+	maxAgo, maxRelative, err := parseRelativeTime(maxTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid maxTime %s: %w", maxTime, err)
+	}
+
+	bounds := make([]string, shards+1)
+	switch {
+	case minRelative && maxRelative:
+		if minAgo <= maxAgo {
+			return nil, fmt.Errorf("minTime %s must be before maxTime %s", minTime, maxTime)
+		}
+		step := (minAgo - maxAgo) / time.Duration(shards)
+		for i := range bounds {
+			bounds[i] = formatRelativeTime(minAgo - time.Duration(i)*step)
+		}
+	case !minRelative && !maxRelative:
+		from, _ := time.Parse(time.RFC3339, minTime)
+		to, _ := time.Parse(time.RFC3339, maxTime)
+		if !from.Before(to) {
+			return nil, fmt.Errorf("minTime %s must be before maxTime %s", minTime, maxTime)
+		}
+		step := to.Sub(from) / time.Duration(shards)
+		for i := range bounds {
+			bounds[i] = from.Add(time.Duration(i) * step).UTC().Format(time.RFC3339)
+		}
+	default:
+		return nil, fmt.Errorf("minTime %s and maxTime %s must both be relative durations or both be timestamps", minTime, maxTime)
+	}
+	return bounds, nil
+}
+
+// parseRelativeTime parses a time as accepted by the --min-time and --max-time flags of Thanos.
+// It returns how long ago a relative duration is and true, or false for a timestamp.
+func parseRelativeTime(s string) (time.Duration, bool, error) {
+	if s == "0" {
+		return 0, true, nil
+	}
+	if ago, ok := strings.CutPrefix(s, "-"); ok {
+		d, err := model.ParseDuration(ago)
+		return time.Duration(d), true, err
+	}
+	_, err := time.Parse(time.RFC3339, s)
+	return 0, false, err
+}
+
+// formatRelativeTime formats the time the given duration ago as a relative duration.
+func formatRelativeTime(ago time.Duration) string {
+	if ago <= 0 {
+		return "0"
+	}
+	return "-" + model.Duration(ago.Truncate(time.Second)).String()
+}
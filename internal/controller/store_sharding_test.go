@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	"k8s.io/utils/ptr"
+)
+
+func TestSplitTimeRange(t *testing.T) {
+	timeRange := func(minTime, maxTime string) v1alpha1.TimeRangeConfig {
+		var tr v1alpha1.TimeRangeConfig
+		if minTime != "" {
+			tr.MinTime = ptr.To(v1alpha1.Duration(minTime))
+		}
+		if maxTime != "" {
+			tr.MaxTime = ptr.To(v1alpha1.Duration(maxTime))
+		}
+		return tr
+	}
+
+	for _, tc := range []struct {
+		name      string
+		timeRange v1alpha1.TimeRangeConfig
+		shards    int32
+		expect    [][2]string
+		expectErr bool
+	}{
+		{
+			name:      "single shard",
+			timeRange: timeRange("-30d", ""),
+			shards:    1,
+			expect:    [][2]string{{"-30d", ""}},
+		},
+		{
+			name:      "relative up to now",
+			timeRange: timeRange("-30d", ""),
+			shards:    3,
+			expect:    [][2]string{{"-10d", ""}, {"-20d", "-10d"}, {"-30d", "-20d"}},
+		},
+		{
+			name:      "relative",
+			timeRange: timeRange("-1y", "-2w"),
+			shards:    2,
+			expect:    [][2]string{{"-189d12h", "-2w"}, {"-1y", "-189d12h"}},
+		},
+		{
+			name:      "timestamps",
+			timeRange: timeRange("2024-01-01T00:00:00Z", "2024-01-31T00:00:00Z"),
+			shards:    3,
+			expect: [][2]string{
+				{"2024-01-21T00:00:00Z", "2024-01-31T00:00:00Z"},
+				{"2024-01-11T00:00:00Z", "2024-01-21T00:00:00Z"},
+				{"2024-01-01T00:00:00Z", "2024-01-11T00:00:00Z"},
+			},
+		},
+		{
+			name:      "missing min time",
+			timeRange: timeRange("", "-2w"),
+			shards:    2,
+			expectErr: true,
+		},
+		{
+			name:      "timestamp up to now",
+			timeRange: timeRange("2024-01-01T00:00:00Z", ""),
+			shards:    2,
+			expectErr: true,
+		},
+		{
+			name:      "empty range",
+			timeRange: timeRange("-2w", "-4w"),
+			shards:    2,
+			expectErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ranges, err := splitTimeRange(tc.timeRange, tc.shards)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", ranges)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(ranges) != len(tc.expect) {
+				t.Fatalf("expected %d time ranges, got %d", len(tc.expect), len(ranges))
+			}
+			for i, expect := range tc.expect {
+				got := [2]string{string(ptr.Deref(ranges[i].MinTime, "")), string(ptr.Deref(ranges[i].MaxTime, ""))}
+				if got != expect {
+					t.Errorf("shard %d: expected time range %v, got %v", i, expect, got)
+				}
+			}
+		})
+	}
+}
@@ -42,6 +42,7 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -101,6 +102,9 @@ var _ = BeforeSuite(func() {
 	err = monitoringv1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
 
+	err = gatewayv1.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
+
 	//+kubebuilder:scaffold:scheme
 
 	k8sManager, err := ctrl.NewManager(cfg, ctrl.Options{
@@ -117,7 +121,7 @@ var _ = BeforeSuite(func() {
 			},
 			InstrumentationConfig: InstrumentationConfig{
 				Logger:          logger.WithName(component),
-				EventRecorder:   events.NewFakeRecorder(100).WithLogger(logger),
+				EventRecorder:   &events.FakeRecorder{}, // events are dropped, so that an undrained recorder never blocks
 				MetricsRegistry: ctrlmetrics.Registry,
 				CommonMetrics:   metrics.NewCommonMetrics(ctrlmetrics.Registry),
 			},
@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/thanos-community/thanos-operator/internal/pkg/featuregate"
+	"github.com/thanos-community/thanos-operator/internal/pkg/handlers"
+	controllermetrics "github.com/thanos-community/thanos-operator/internal/pkg/metrics"
+	"github.com/thanos-community/thanos-operator/internal/pkg/multicluster"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/events"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// targetClusterResyncInterval is the interval at which resources managed in a workload cluster are requeued.
+// Child resources in workload clusters are not watched, so changes to them are only observed on resync.
+const targetClusterResyncInterval = time.Minute
+
+// targetCluster is the cluster in which the child resources of a resource are managed.
+type targetCluster struct {
+	// client reads the child resources and the objects they depend on.
+	client  client.Client
+	handler *handlers.Handler
+	// remote is true if the child resources are managed in a workload cluster,
+	// rather than in the cluster of the resource that owns them.
+	remote bool
+	// resyncInterval is the interval at which the owning resource is requeued after a successful reconcile.
+	resyncInterval time.Duration
+}
+
+// requeueAfter returns the delay before the owning resource must be reconciled again,
+// given the delay requested by the reconciler. Zero means no requeue is needed.
+// The resync interval is jittered, so that resources created together are not resynced together.
+func (t targetCluster) requeueAfter(after time.Duration) time.Duration {
+	resync := t.resyncInterval
+	if resync > 0 {
+		resync = wait.Jitter(resync, resyncJitter)
+	}
+	if t.remote && (resync == 0 || resync > targetClusterResyncInterval) {
+		resync = targetClusterResyncInterval
+	}
+	if resync == 0 || (after > 0 && after < resync) {
+		return after
+	}
+	return resync
+}
+
+// targetClusters resolves the cluster selected by the targetCluster field of a resource.
+type targetClusters struct {
+	local    targetCluster
+	clusters *multicluster.Clusters
+
+	resyncInterval time.Duration
+	scheme         *runtime.Scheme
+	logger         logr.Logger
+	featureGate    featuregate.Config
+	mutators       []handlers.ObjectMutator
+	component      string
+	metrics        *controllermetrics.CommonMetrics
+	recorder       events.EventRecorder
+}
+
+func newTargetClusters(conf Config, component string, local client.Client, localHandler *handlers.Handler, scheme *runtime.Scheme) *targetClusters {
+	return &targetClusters{
+		local:          targetCluster{client: local, handler: localHandler, resyncInterval: conf.ResyncInterval},
+		clusters:       conf.TargetClusters,
+		resyncInterval: conf.ResyncInterval,
+		scheme:         scheme,
+		logger:         conf.InstrumentationConfig.Logger,
+		featureGate:    conf.FeatureGate,
+		mutators:       conf.Mutators,
+		component:      component,
+		metrics:        conf.InstrumentationConfig.CommonMetrics,
+		recorder:       conf.InstrumentationConfig.EventRecorder,
+	}
+}
+
+// get returns the cluster with the given name, or the local cluster if name is unset.
+func (t *targetClusters) get(ctx context.Context, name *string) (targetCluster, error) {
+	if name == nil || *name == "" {
+		return t.local, nil
+	}
+	if t.clusters == nil {
+		return targetCluster{}, fmt.Errorf("target cluster %s is not registered with the operator", *name)
+	}
+
+	c, err := t.clusters.Client(ctx, *name)
+	if err != nil {
+		return targetCluster{}, err
+	}
+
+	return targetCluster{
+		client: c,
+		handler: handlers.NewHandler(c, t.scheme, t.logger.WithValues("targetCluster", *name)).
+			SetFeatureGates(t.featureGate.ToGVK()).
+			WithMutators(t.mutators...).
+			WithMetrics(t.component, t.metrics).
+			WithEventRecorder(t.recorder).
+			DisableOwnerReferences(),
+		remote:         true,
+		resyncInterval: t.resyncInterval,
+	}, nil
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/utils/ptr"
+)
+
+func TestTargetClusterRequeueAfter(t *testing.T) {
+	local := targetCluster{}
+	remote := targetCluster{remote: true}
+
+	for _, tc := range []struct {
+		name    string
+		cluster targetCluster
+		after   time.Duration
+		want    time.Duration
+	}{
+		{name: "local without requeue", cluster: local, after: 0, want: 0},
+		{name: "local keeps requested delay", cluster: local, after: time.Hour, want: time.Hour},
+		{name: "remote resyncs without requeue", cluster: remote, after: 0, want: targetClusterResyncInterval},
+		{name: "remote caps requested delay", cluster: remote, after: time.Hour, want: targetClusterResyncInterval},
+		{name: "remote keeps shorter delay", cluster: remote, after: time.Second, want: time.Second},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.cluster.requeueAfter(tc.after); got != tc.want {
+				t.Errorf("requeueAfter(%s) = %s, want %s", tc.after, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTargetClusterResync(t *testing.T) {
+	local := targetCluster{resyncInterval: 5 * time.Minute}
+	remote := targetCluster{remote: true, resyncInterval: 30 * time.Second}
+
+	for _, tc := range []struct {
+		name    string
+		cluster targetCluster
+		after   time.Duration
+		want    time.Duration
+	}{
+		{name: "local resyncs without requeue", cluster: local, after: 0, want: 5 * time.Minute},
+		{name: "local caps requested delay", cluster: local, after: time.Hour, want: 5 * time.Minute},
+		{name: "remote resyncs at the shorter interval", cluster: remote, after: 0, want: 30 * time.Second},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.cluster.requeueAfter(tc.after)
+			if got < tc.want || got > tc.want+time.Duration(float64(tc.want)*resyncJitter) {
+				t.Errorf("requeueAfter(%s) = %s, want %s with jitter", tc.after, got, tc.want)
+			}
+		})
+	}
+
+	if got := local.requeueAfter(time.Second); got != time.Second {
+		t.Errorf("expected a shorter requested delay to be kept, got %s", got)
+	}
+}
+
+func TestTargetClustersGet(t *testing.T) {
+	clusters := newTargetClusters(Config{}, "", nil, nil, nil)
+
+	for _, name := range []*string{nil, ptr.To("")} {
+		cluster, err := clusters.get(context.Background(), name)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cluster.remote {
+			t.Errorf("expected the local cluster to be used when no target cluster is set")
+		}
+	}
+
+	if _, err := clusters.get(context.Background(), ptr.To("eu-1")); err == nil {
+		t.Errorf("expected error for a target cluster that is not registered")
+	}
+}
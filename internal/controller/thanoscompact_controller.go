@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
 
 	monitoringthanosiov1alpha1 "github.com/thanos-community/thanos-operator/api/v1alpha1"
 	"github.com/thanos-community/thanos-operator/internal/pkg/featuregate"
@@ -29,6 +30,7 @@ import (
 	manifestcompact "github.com/thanos-community/thanos-operator/internal/pkg/manifests/compact"
 	controllermetrics "github.com/thanos-community/thanos-operator/internal/pkg/metrics"
 
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -38,7 +40,9 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
 
 // ThanosCompactReconciler reconciles a ThanosCompact object
@@ -54,11 +58,20 @@ type ThanosCompactReconciler struct {
 	disableConditionUpdate bool
 
 	featureGate featuregate.Config
+	scope       controllerScope
+
+	dependencyBackoff *dependencyBackoff
+	workqueue         WorkqueueConfig
+	targetClusters    *targetClusters
+	defaults          fleetDefaults
 }
 
 //+kubebuilder:rbac:groups=monitoring.thanos.io,resources=thanoscompacts,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=monitoring.thanos.io,resources=thanoscompacts/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=monitoring.thanos.io,resources=thanoscompacts/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -70,11 +83,14 @@ type ThanosCompactReconciler struct {
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.17.3/pkg/reconcile
 func (r *ThanosCompactReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	defer prometheus.NewTimer(r.metrics.ReconcileDurationSeconds.WithLabelValues("compact")).ObserveDuration()
+
 	compact := &monitoringthanosiov1alpha1.ThanosCompact{}
 	err := r.Get(ctx, req.NamespacedName, compact)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			r.logger.Info("thanos compact resource not found. ignoring since object may be deleted")
+			r.dependencyBackoff.reset(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		r.logger.Error(err, "failed to get ThanosCompact")
@@ -82,7 +98,12 @@ func (r *ThanosCompactReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, err
 	}
 
-	if compact.Spec.Paused != nil && *compact.Spec.Paused {
+	if !r.scope.manages(compact) {
+		r.logger.V(1).Info("ThanosCompact resource is managed by another operator instance, skipping")
+		return ctrl.Result{}, nil
+	}
+
+	if isPaused(compact, compact.Spec.Paused) {
 		r.logger.Info("reconciliation is paused for ThanosCompact resource")
 		r.metrics.Paused.WithLabelValues("compact", compact.GetName(), compact.GetNamespace()).Set(1)
 		r.recorder.Eventf(compact, nil, corev1.EventTypeNormal, "Paused", "Reconcile", "Reconciliation is paused for ThanosCompact resource")
@@ -97,7 +118,37 @@ func (r *ThanosCompactReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 	r.metrics.Paused.WithLabelValues("compact", compact.GetName(), compact.GetNamespace()).Set(0)
 
-	err = r.syncResources(ctx, *compact)
+	cluster, err := r.targetClusters.get(ctx, compact.Spec.TargetCluster)
+	if err == nil {
+		err = r.defaults.apply(ctx, compact.GetNamespace(), &compact.Spec.CommonFields)
+	}
+	if err == nil {
+		err = r.syncResources(ctx, cluster, *compact)
+	}
+	if isInvalidObjectStorageConfig(err) {
+		r.logger.Info("invalid object storage configuration", "resource", compact.GetName(), "namespace", compact.GetNamespace(), "reason", err.Error())
+		r.recorder.Eventf(compact, nil, corev1.EventTypeWarning, "InvalidObjectStorageConfig", "Reconcile", "%v", err)
+		meta.SetStatusCondition(&compact.Status.Conditions, objectStorageDegradedCondition(err))
+		r.updateCondition(ctx, compact, metav1.Condition{
+			Type:    ConditionReconcileFailed,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonInvalidObjectStorageConfig,
+			Message: err.Error(),
+		})
+		// the Secret is watched, the resource is reconciled again once it is fixed
+		return ctrl.Result{}, nil
+	}
+	if isMissingDependency(err) {
+		r.logger.V(1).Info("waiting for dependencies", "resource", compact.GetName(), "namespace", compact.GetNamespace(), "reason", err.Error())
+		r.recorder.Eventf(compact, nil, corev1.EventTypeWarning, "DependencyMissing", "Reconcile", "%v", err)
+		r.updateCondition(ctx, compact, metav1.Condition{
+			Type:    ConditionDependencyMissing,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonDependencyNotFound,
+			Message: err.Error(),
+		})
+		return ctrl.Result{RequeueAfter: r.dependencyBackoff.next(req.NamespacedName)}, nil
+	}
 	if err != nil {
 		r.logger.Error(err, "failed to sync resources", "resource", compact.GetName(), "namespace", compact.GetNamespace())
 		r.recorder.Eventf(compact, nil, corev1.EventTypeWarning, "SyncFailed", "Reconcile", "Failed to sync resources: %v", err)
@@ -110,6 +161,15 @@ func (r *ThanosCompactReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, err
 	}
 
+	r.dependencyBackoff.reset(req.NamespacedName)
+	meta.RemoveStatusCondition(&compact.Status.Conditions, ConditionDependencyMissing)
+	clearObjectStorageDegraded(&compact.Status.Conditions)
+	if err := reportObjectStorageVerification(ctx, cluster.client, r.recorder, compact, &compact.Status.Conditions, compact.Spec.VerifyObjectStorage); err != nil {
+		r.logger.Error(err, "failed to report object storage verification", "resource", compact.GetName(), "namespace", compact.GetNamespace())
+	}
+	if err := reportCompactBucketRetention(ctx, r.Client, r.recorder, compact); err != nil {
+		r.logger.Error(err, "failed to report bucket retention", "resource", compact.GetName(), "namespace", compact.GetNamespace())
+	}
 	r.updateCondition(ctx, compact, metav1.Condition{
 		Type:    ConditionReconcileSuccess,
 		Status:  metav1.ConditionTrue,
@@ -117,7 +177,8 @@ func (r *ThanosCompactReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		Message: "Reconciliation completed successfully",
 	})
 
-	return ctrl.Result{}, nil
+	// child resources in a workload cluster are not watched, so they are resynced periodically
+	return ctrl.Result{RequeueAfter: cluster.requeueAfter(0)}, nil
 }
 
 // NewThanosCompactReconciler returns a reconciler for ThanosCompact resources.
@@ -129,8 +190,14 @@ func NewThanosCompactReconciler(conf Config, client client.Client, scheme *runti
 		metrics:     controllermetrics.NewThanosCompactMetrics(conf.InstrumentationConfig.MetricsRegistry, conf.InstrumentationConfig.CommonMetrics),
 		recorder:    conf.InstrumentationConfig.EventRecorder,
 		featureGate: conf.FeatureGate,
-		handler:     handlers.NewHandler(client, scheme, conf.InstrumentationConfig.Logger).SetFeatureGates(conf.FeatureGate.ToGVK()),
+		scope:       newControllerScope(conf),
+		handler:     handlers.NewHandler(client, scheme, conf.InstrumentationConfig.Logger).SetFeatureGates(conf.FeatureGate.ToGVK()).WithMutators(conf.Mutators...).WithMetrics("compact", conf.InstrumentationConfig.CommonMetrics).WithEventRecorder(conf.InstrumentationConfig.EventRecorder),
+
+		dependencyBackoff: newDependencyBackoff(),
+		workqueue:         conf.Workqueue,
 	}
+	reconciler.targetClusters = newTargetClusters(conf, "compact", client, reconciler.handler, scheme)
+	reconciler.defaults = fleetDefaults{client: client}
 
 	return reconciler
 }
@@ -138,12 +205,68 @@ func NewThanosCompactReconciler(conf Config, client client.Client, scheme *runti
 // SetupWithManager sets up the controller with the Manager.
 func (r *ThanosCompactReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&monitoringthanosiov1alpha1.ThanosCompact{}).
+		For(&monitoringthanosiov1alpha1.ThanosCompact{}, builder.WithPredicates(r.scope.predicate())).
+		WithOptions(r.workqueue.controllerOptions()).
+		Owns(&batchv1.Job{}).
+		Watches(
+			&corev1.Secret{},
+			enqueueForReferencedSecret(r.Client, r.logger, func() client.ObjectList {
+				return &monitoringthanosiov1alpha1.ThanosCompactList{}
+			}, func(obj client.Object) []string {
+				return []string{obj.(*monitoringthanosiov1alpha1.ThanosCompact).Spec.ObjectStorageConfig.Name}
+			}),
+			builder.OnlyMetadata, builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Watches(
+			&corev1.ConfigMap{},
+			enqueueForReferencedConfigMap(r.Client, r.logger, func() client.ObjectList {
+				return &monitoringthanosiov1alpha1.ThanosCompactList{}
+			}, func(obj client.Object) []string {
+				return objectStorageConfigMaps(&obj.(*monitoringthanosiov1alpha1.ThanosCompact).Spec.ObjectStorageConfig)
+			}),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Watches(
+			&monitoringthanosiov1alpha1.ThanosDefaults{},
+			enqueueForDefaults(r.Client, func() client.ObjectList {
+				return &monitoringthanosiov1alpha1.ThanosCompactList{}
+			}),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		Watches(
+			&monitoringthanosiov1alpha1.ThanosCompact{},
+			enqueueForRetentionSource(r.Client),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
 		Complete(r)
 }
 
-func (r *ThanosCompactReconciler) syncResources(ctx context.Context, compact monitoringthanosiov1alpha1.ThanosCompact) error {
+func (r *ThanosCompactReconciler) syncResources(ctx context.Context, cluster targetCluster, compact monitoringthanosiov1alpha1.ThanosCompact) error {
 	var errCount int
+	applied := manifests.NewObjectSet()
+	cluster.handler = cluster.handler.RecordApplied(applied)
+
+	deps := &dependencies{}
+	if err := deps.requireSecrets(ctx, cluster.client, compact.GetNamespace(), compact.Spec.ObjectStorageConfig.Name); err != nil {
+		return err
+	}
+	if err := materializeObjectStorageConfigs(ctx, cluster, &compact, deps, &compact.Spec.ObjectStorageConfig); err != nil {
+		return err
+	}
+	if err := validateObjectStorageConfigs(ctx, cluster.client, compact.GetNamespace(), compact.Spec.ObjectStorageConfig); err != nil {
+		return err
+	}
+	// the compactor is not applied with its own retention while the resource it takes it from is missing,
+	// as it would delete blocks the other compactors of the bucket keep
+	if err := resolveRetentionConfig(ctx, r.Client, &compact, deps); err != nil {
+		return err
+	}
+	if compact.Spec.RetentionConfigFrom != nil {
+		if err := deps.err(); err != nil {
+			return err
+		}
+	}
+
 	options := r.specToOptions(compact)
 	r.metrics.ShardsConfigured.WithLabelValues(compact.GetName(), compact.GetNamespace()).Set(float64(len(options)))
 
@@ -154,34 +277,53 @@ func (r *ThanosCompactReconciler) syncResources(ctx context.Context, compact mon
 		expectResources = append(expectResources, opt.GetGeneratedResourceName())
 	}
 
-	errCount = r.pruneOrphanedResources(ctx, compact.GetNamespace(), compact.GetName(), expectResources)
+	errCount = r.pruneOrphanedResources(ctx, cluster, compact.GetNamespace(), compact.GetName(), withMetricsServices(expectResources))
 	if errCount > 0 {
 		return fmt.Errorf("failed to prune %d orphaned resources for compact or compact shard(s)", errCount)
 	}
 
 	// now we can create what we expect to be built based on the spec
 	for _, opt := range options {
-		errCount += r.handler.CreateOrUpdate(ctx, compact.GetNamespace(), &compact, opt.Build())
+		errCount += cluster.handler.CreateOrUpdate(ctx, compact.GetNamespace(), &compact, opt.Build())
+	}
+
+	verifyOpts, err := objStoreVerifyOptions(ctx, cluster, compact.Spec.VerifyObjectStorage, compactV1Alpha1ToOptions(compactV1Alpha1TransformInput{CRD: compact, FeatureGate: r.featureGate}).Options, compact.Spec.ObjectStorageConfig)
+	if err != nil {
+		return err
 	}
+	errCount += syncObjectStorageVerification(ctx, cluster, &compact, verifyOpts)
 
 	if errCount > 0 {
 		r.metrics.ShardCreationUpdateFailures.WithLabelValues(compact.GetName(), compact.GetNamespace()).Add(float64(errCount))
 		return fmt.Errorf("failed to create or update %d resources for compact or compact shard(s)", errCount)
 	}
 
-	if errCount = r.handler.DeleteResource(ctx,
+	if errCount = cluster.handler.DeleteResource(ctx,
 		getDisabledFeatureGatedResources(r.featureGate, expectResources, compact.GetNamespace())); errCount > 0 {
 		return fmt.Errorf("failed to delete %d feature gated resources for the compactor", errCount)
 	}
 
-	return nil
+	if errCount = cluster.handler.DeleteResource(ctx,
+		getDisabledMetricsServices(metricsServiceEnabled(compact.Spec.MetricsService), expectResources, compact.GetNamespace())); errCount > 0 {
+		return fmt.Errorf("failed to delete %d metrics services for the compactor", errCount)
+	}
+
+	// stale resources are deleted without a grace period, like orphaned compactors
+	pruner := cluster.handler.NewResourcePruner().WithGeneratedResources().WithSecret()
+	if errCount = pruner.PruneStale(ctx, compact.GetNamespace(), compact.GetUID(), applied); errCount > 0 {
+		return fmt.Errorf("failed to prune %d stale resources for the compactor", errCount)
+	}
+
+	return deps.err()
 }
 
-func (r *ThanosCompactReconciler) pruneOrphanedResources(ctx context.Context, ns, owner string, expectShards []string) int {
+func (r *ThanosCompactReconciler) pruneOrphanedResources(ctx context.Context, cluster targetCluster, ns, owner string, expectShards []string) int {
 	listOpt := manifests.GetLabelSelectorForOwner(manifestcompact.Options{Options: manifests.Options{Owner: owner}})
 	listOpts := []client.ListOption{listOpt, client.InNamespace(ns)}
 
-	pruner := r.handler.NewResourcePruner().WithServiceAccount().WithService().WithStatefulSet().WithServiceMonitor()
+	// orphaned compactors are deleted without a grace period, since keeping them running
+	// alongside their replacements would compact the same blocks concurrently
+	pruner := cluster.handler.NewResourcePruner().WithServiceAccount().WithService().WithStatefulSet().WithServiceMonitor()
 	return pruner.Prune(ctx, expectShards, listOpts...)
 }
 
@@ -227,7 +369,9 @@ func (r *ThanosCompactReconciler) updateCondition(ctx context.Context, compact *
 	}
 	conditions := compact.Status.Conditions
 	meta.SetStatusCondition(&conditions, condition)
+	setReadinessConditions(&conditions, condition)
 	compact.Status.Conditions = conditions
+	compact.Status.ObservedGeneration = compact.GetGeneration()
 	if condition.Type == ConditionPaused {
 		compact.Status.Paused = ptr.To(true)
 	}
@@ -19,24 +19,30 @@ package controller
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 
 	manifestqueryfrontend "github.com/thanos-community/thanos-operator/internal/pkg/manifests/queryfrontend"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/client_golang/prometheus"
 
 	monitoringthanosiov1alpha1 "github.com/thanos-community/thanos-operator/api/v1alpha1"
 	"github.com/thanos-community/thanos-operator/internal/pkg/featuregate"
 	"github.com/thanos-community/thanos-operator/internal/pkg/handlers"
 	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
 	manifestquery "github.com/thanos-community/thanos-operator/internal/pkg/manifests/query"
+	manifestreceive "github.com/thanos-community/thanos-operator/internal/pkg/manifests/receive"
 	manifestsstore "github.com/thanos-community/thanos-operator/internal/pkg/manifests/store"
 	controllermetrics "github.com/thanos-community/thanos-operator/internal/pkg/metrics"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -53,6 +59,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 // ThanosQueryReconciler reconciles a ThanosQuery object
@@ -68,6 +75,14 @@ type ThanosQueryReconciler struct {
 	disableConditionUpdate bool
 
 	featureGate featuregate.Config
+	scope       controllerScope
+
+	dependencyBackoff *dependencyBackoff
+	workqueue         WorkqueueConfig
+	pruneGracePeriod  time.Duration
+	pendingDeletions  *pendingDeletions
+	targetClusters    *targetClusters
+	defaults          fleetDefaults
 }
 
 // NewThanosQueryReconciler returns a reconciler for ThanosQuery resources.
@@ -79,8 +94,16 @@ func NewThanosQueryReconciler(conf Config, client client.Client, scheme *runtime
 		metrics:     controllermetrics.NewThanosQueryMetrics(conf.InstrumentationConfig.MetricsRegistry, conf.InstrumentationConfig.CommonMetrics),
 		recorder:    conf.InstrumentationConfig.EventRecorder,
 		featureGate: conf.FeatureGate,
-		handler:     handlers.NewHandler(client, scheme, conf.InstrumentationConfig.Logger).SetFeatureGates(conf.FeatureGate.ToGVK()),
+		scope:       newControllerScope(conf),
+		handler:     handlers.NewHandler(client, scheme, conf.InstrumentationConfig.Logger).SetFeatureGates(conf.FeatureGate.ToGVK()).WithMutators(conf.Mutators...).WithMetrics("query", conf.InstrumentationConfig.CommonMetrics).WithEventRecorder(conf.InstrumentationConfig.EventRecorder),
+
+		dependencyBackoff: newDependencyBackoff(),
+		workqueue:         conf.Workqueue,
+		pruneGracePeriod:  conf.PruneGracePeriod,
+		pendingDeletions:  newPendingDeletions(),
 	}
+	reconciler.targetClusters = newTargetClusters(conf, "query", client, reconciler.handler, scheme)
+	reconciler.defaults = fleetDefaults{client: client}
 
 	return reconciler
 }
@@ -88,10 +111,15 @@ func NewThanosQueryReconciler(conf Config, client client.Client, scheme *runtime
 //+kubebuilder:rbac:groups=monitoring.thanos.io,resources=thanosqueries,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=monitoring.thanos.io,resources=thanosqueries/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=monitoring.thanos.io,resources=thanosqueries/finalizers,verbs=update
-//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=monitoring.thanos.io,resources=thanosreceives,verbs=get;list;watch
+//+kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=services;configmaps;serviceaccounts,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 //+kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -99,11 +127,14 @@ func NewThanosQueryReconciler(conf Config, client client.Client, scheme *runtime
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.17.0/pkg/reconcile
 func (r *ThanosQueryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	defer prometheus.NewTimer(r.metrics.ReconcileDurationSeconds.WithLabelValues("query")).ObserveDuration()
+
 	query := &monitoringthanosiov1alpha1.ThanosQuery{}
 	err := r.Get(ctx, req.NamespacedName, query)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			r.logger.Info("thanos query resource not found. ignoring since object may be deleted")
+			r.dependencyBackoff.reset(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		r.logger.Error(err, "failed to get ThanosQuery")
@@ -111,7 +142,12 @@ func (r *ThanosQueryReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
-	if query.Spec.Paused != nil && *query.Spec.Paused {
+	if !r.scope.manages(query) {
+		r.logger.V(1).Info("ThanosQuery resource is managed by another operator instance, skipping")
+		return ctrl.Result{}, nil
+	}
+
+	if isPaused(query, query.Spec.Paused) {
 		r.logger.Info("reconciliation is paused for ThanosQuery resource")
 		r.metrics.Paused.WithLabelValues("query", query.GetName(), query.GetNamespace()).Set(1)
 		r.recorder.Eventf(query, nil, corev1.EventTypeNormal, "Paused", "Reconcile", "Reconciliation is paused for ThanosQuery resource")
@@ -126,7 +162,26 @@ func (r *ThanosQueryReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	r.metrics.Paused.WithLabelValues("query", query.GetName(), query.GetNamespace()).Set(0)
 
-	err = r.syncResources(ctx, *query)
+	var endpoints []manifestquery.Endpoint
+	cluster, err := r.targetClusters.get(ctx, query.Spec.TargetCluster)
+	if err == nil {
+		err = r.defaults.apply(ctx, query.GetNamespace(), queryCommonFields(query)...)
+	}
+	if err == nil {
+		endpoints, err = r.syncResources(ctx, cluster, *query)
+		r.setStatus(ctx, cluster, query, endpoints)
+	}
+	if isMissingDependency(err) {
+		r.logger.V(1).Info("waiting for dependencies", "resource", query.GetName(), "namespace", query.GetNamespace(), "reason", err.Error())
+		r.recorder.Eventf(query, nil, corev1.EventTypeWarning, "DependencyMissing", "Reconcile", "%v", err)
+		r.updateCondition(ctx, query, metav1.Condition{
+			Type:    ConditionDependencyMissing,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonDependencyNotFound,
+			Message: err.Error(),
+		})
+		return ctrl.Result{RequeueAfter: r.dependencyBackoff.next(req.NamespacedName)}, nil
+	}
 	if err != nil {
 		r.logger.Error(err, "failed to sync resources", "resource", query.GetName(), "namespace", query.GetNamespace())
 		r.recorder.Eventf(query, nil, corev1.EventTypeWarning, "SyncFailed", "Reconcile", "Failed to sync resources: %v", err)
@@ -139,6 +194,11 @@ func (r *ThanosQueryReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
+	r.dependencyBackoff.reset(req.NamespacedName)
+	meta.RemoveStatusCondition(&query.Status.Conditions, ConditionDependencyMissing)
+	r.reportReadProbe(ctx, query)
+	r.reportRemotes(ctx, query)
+	r.reportEndpointHealth(ctx, cluster, query, endpoints)
 	r.updateCondition(ctx, query, metav1.Condition{
 		Type:    ConditionReconcileSuccess,
 		Status:  metav1.ConditionTrue,
@@ -146,15 +206,135 @@ func (r *ThanosQueryReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		Message: "Reconciliation completed successfully",
 	})
 
-	return ctrl.Result{}, nil
+	// requeue to delete orphaned resources once their prune grace period expires,
+	// or to run the next read probe, remote check or endpoint health check if it is due earlier
+	requeueAfter := r.pendingDeletions.pop(req.NamespacedName)
+	now := time.Now()
+	for _, next := range []time.Duration{nextReadProbe(*query, now), nextRemoteCheck(*query, now), nextEndpointHealthCheck(query.Status.EndpointHealth, now)} {
+		if next > 0 && (requeueAfter == 0 || next < requeueAfter) {
+			requeueAfter = next
+		}
+	}
+	return ctrl.Result{RequeueAfter: cluster.requeueAfter(requeueAfter)}, nil
+}
+
+// reportReadProbe runs the read probe of the ThanosQuery resource if it is due, and records its result
+// in the status, the ReadProbeSucceeded condition and the read probe metrics.
+// A Warning event is emitted when the probe starts failing. The status is persisted with the next condition update.
+func (r *ThanosQueryReconciler) reportReadProbe(ctx context.Context, query *monitoringthanosiov1alpha1.ThanosQuery) {
+	name, ns := query.GetName(), query.GetNamespace()
+	if query.Spec.ReadProbe == nil {
+		query.Status.ReadProbe = nil
+		meta.RemoveStatusCondition(&query.Status.Conditions, ConditionReadProbeSucceeded)
+		r.metrics.ReadProbeSuccess.DeleteLabelValues(name, ns)
+		r.metrics.ReadProbeLatencySeconds.DeletePartialMatch(prometheus.Labels{"resource": name, "namespace": ns})
+		return
+	}
+
+	now := time.Now()
+	if !readProbeDue(*query, now) {
+		return
+	}
+
+	result := runReadProbe(ctx, readProbeConfigFor(*query), now)
+	query.Status.ReadProbe = result.status(now)
+	condition := result.condition()
+	if condition.Status == metav1.ConditionFalse && !meta.IsStatusConditionFalse(query.Status.Conditions, ConditionReadProbeSucceeded) {
+		r.recorder.Eventf(query, nil, corev1.EventTypeWarning, "ReadProbeFailed", "Reconcile", "%s", condition.Message)
+	}
+	meta.SetStatusCondition(&query.Status.Conditions, condition)
+
+	var success float64
+	if result.err == nil {
+		success = 1
+	}
+	r.metrics.ReadProbeSuccess.WithLabelValues(name, ns).Set(success)
+	for queryType, latency := range map[string]*time.Duration{readProbeInstantQuery: result.instantLatency, readProbeRangeQuery: result.rangeLatency} {
+		if latency == nil {
+			r.metrics.ReadProbeLatencySeconds.DeleteLabelValues(name, ns, queryType)
+			continue
+		}
+		r.metrics.ReadProbeLatencySeconds.WithLabelValues(name, ns, queryType).Set(latency.Seconds())
+	}
+}
+
+// reportRemotes checks the reachability of the federation remotes if it is due, and records it in the status
+// and the RemotesReachable condition. A Warning event is emitted when remotes become unreachable.
+// The status is persisted with the next condition update.
+func (r *ThanosQueryReconciler) reportRemotes(ctx context.Context, query *monitoringthanosiov1alpha1.ThanosQuery) {
+	remotes := remotesToCheck(*query)
+	if len(remotes) == 0 {
+		query.Status.Remotes = nil
+		meta.RemoveStatusCondition(&query.Status.Conditions, ConditionRemotesReachable)
+		return
+	}
+
+	now := time.Now()
+	if !remoteChecksDue(*query, now) {
+		return
+	}
+
+	query.Status.Remotes = checkRemotes(ctx, remotes, now)
+	condition := remotesCondition(query.Status.Remotes)
+	if condition.Status == metav1.ConditionFalse && !meta.IsStatusConditionFalse(query.Status.Conditions, ConditionRemotesReachable) {
+		r.recorder.Eventf(query, nil, corev1.EventTypeWarning, "RemotesUnreachable", "Reconcile", "%s", condition.Message)
+	}
+	meta.SetStatusCondition(&query.Status.Conditions, condition)
+}
+
+// reportEndpointHealth checks the health of the discovered StoreAPI endpoints if it is due, and records it in the status,
+// the EndpointsHealthy condition and the endpoint health metric. The endpoints are checked through their Service,
+// which may not be reachable from the operator in a workload cluster, so they are only checked in the local cluster.
+// A Warning event is emitted when endpoints become unhealthy. The status is persisted with the next condition update.
+func (r *ThanosQueryReconciler) reportEndpointHealth(ctx context.Context, cluster targetCluster, query *monitoringthanosiov1alpha1.ThanosQuery, endpoints []manifestquery.Endpoint) {
+	labels := prometheus.Labels{"component": "query", "resource": query.GetName(), "namespace": query.GetNamespace()}
+	if !ptr.Deref(query.Spec.HealthChecks, false) || cluster.remote {
+		r.metrics.EndpointHealthy.DeletePartialMatch(labels)
+		query.Status.EndpointHealth = nil
+		meta.RemoveStatusCondition(&query.Status.Conditions, ConditionEndpointsHealthy)
+		return
+	}
+
+	now := time.Now()
+	targets := queryHealthTargets(endpoints)
+	if !endpointHealthChecksDue(query.Status.EndpointHealth, targets, now) {
+		return
+	}
+
+	cert, err := healthCheckCertificate(ctx, cluster.client, query.GetNamespace(), queryClientCertSecret(*query))
+	if err != nil {
+		r.logger.Error(err, "failed to load the client certificate for health checks", "resource", query.GetName(), "namespace", query.GetNamespace())
+		return
+	}
+	query.Status.EndpointHealth = checkEndpointHealth(ctx, targets, cert, now)
+	r.metrics.EndpointHealthy.DeletePartialMatch(labels)
+	for _, status := range query.Status.EndpointHealth {
+		var healthy float64
+		if status.Healthy {
+			healthy = 1
+		}
+		r.metrics.EndpointHealthy.WithLabelValues("query", query.GetName(), query.GetNamespace(), status.Name).Set(healthy)
+	}
+
+	condition := endpointHealthCondition(unhealthyEndpoints(query.Status.EndpointHealth))
+	if condition.Status == metav1.ConditionFalse && !meta.IsStatusConditionFalse(query.Status.Conditions, ConditionEndpointsHealthy) {
+		r.recorder.Eventf(query, nil, corev1.EventTypeWarning, "EndpointsUnhealthy", "Reconcile", "%s", condition.Message)
+	}
+	meta.SetStatusCondition(&query.Status.Conditions, condition)
 }
 
-func (r *ThanosQueryReconciler) syncResources(ctx context.Context, query monitoringthanosiov1alpha1.ThanosQuery) error {
+// syncResources creates or updates the resources for the querier and the query frontend.
+// It returns the StoreAPI endpoints discovered for the querier, or nil if they could not be discovered.
+func (r *ThanosQueryReconciler) syncResources(ctx context.Context, cluster targetCluster, query monitoringthanosiov1alpha1.ThanosQuery) ([]manifestquery.Endpoint, error) {
 	var objs []client.Object
+	applied := manifests.NewObjectSet()
+	cluster.handler = cluster.handler.RecordApplied(applied)
 
-	querier, err := r.buildQuery(ctx, query)
+	// the querier is still deployed without StoreAPIs, they are reported as a missing dependency
+	deps := &dependencies{}
+	querier, err := r.buildQuery(ctx, cluster, query, deps)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	expectedResources := []string{querier.GetGeneratedResourceName()}
@@ -167,22 +347,29 @@ func (r *ThanosQueryReconciler) syncResources(ctx context.Context, query monitor
 		expectedResources = append(expectedResources, frontend.GetGeneratedResourceName())
 		objs = append(objs, frontend.Build()...)
 	}
+	if cache := queryFrontendManagedCacheToOpts(query); cache != nil {
+		objs = append(objs, cache.Build()...)
+	}
 
-	if errCount := r.handler.CreateOrUpdate(ctx, query.GetNamespace(), &query, objs); errCount > 0 {
-		return fmt.Errorf("failed to create or update %d resources for the querier and query frontend", errCount)
+	if errCount := cluster.handler.CreateOrUpdate(ctx, query.GetNamespace(), &query, objs); errCount > 0 {
+		return querier.Endpoints, fmt.Errorf("failed to create or update %d resources for the querier and query frontend", errCount)
 	}
 
-	if cleanupErrCount := r.cleanup(ctx, query, expectedResources); cleanupErrCount > 0 {
-		return fmt.Errorf("failed to clean up %d resources for the query or query frontend", cleanupErrCount)
+	if cleanupErrCount := r.cleanup(ctx, cluster, query, expectedResources, applied); cleanupErrCount > 0 {
+		return querier.Endpoints, fmt.Errorf("failed to clean up %d resources for the query or query frontend", cleanupErrCount)
 	}
 
-	return nil
+	return querier.Endpoints, deps.err()
 }
 
-func (r *ThanosQueryReconciler) buildQuery(ctx context.Context, query monitoringthanosiov1alpha1.ThanosQuery) (manifests.Buildable, error) {
-	endpoints, err := r.getStoreAPIServiceEndpoints(ctx, query)
+func (r *ThanosQueryReconciler) buildQuery(ctx context.Context, cluster targetCluster, query monitoringthanosiov1alpha1.ThanosQuery, deps *dependencies) (manifestquery.Options, error) {
+	if err := deps.requireSecrets(ctx, cluster.client, query.GetNamespace(), queryReferencedSecrets(query)...); err != nil {
+		return manifestquery.Options{}, err
+	}
+
+	endpoints, receivers, err := r.getStoreAPIServiceEndpoints(ctx, cluster, query, deps)
 	if err != nil {
-		return nil, err
+		return manifestquery.Options{}, err
 	}
 
 	opts := queryV1Alpha1ToOptions(queryV1Alpha1TransformInput{
@@ -191,51 +378,151 @@ func (r *ThanosQueryReconciler) buildQuery(ctx context.Context, query monitoring
 	})
 	opts.Endpoints = endpoints
 
+	if ptr.Deref(query.Spec.DiscoverReplicaLabels, true) {
+		discovered, err := r.discoverReplicaLabels(ctx, query.GetNamespace(), receivers, opts.ReplicaLabels)
+		if err != nil {
+			return manifestquery.Options{}, err
+		}
+		if len(discovered) > 0 {
+			r.logger.V(1).Info("adding replica labels of the discovered receivers", "resource", query.GetName(), "namespace", query.GetNamespace(), "labels", discovered)
+			opts.ReplicaLabels = append(slices.Clone(opts.ReplicaLabels), discovered...)
+		}
+	}
+
+	if plaintext := plaintextEndpoints(endpoints); len(plaintext) > 0 && len(plaintext) < len(endpoints) {
+		r.recorder.Eventf(&query, nil, corev1.EventTypeWarning, "MixedEndpointTLS", "Build",
+			"Some endpoints require TLS, so the Querier cannot connect to the endpoints served without TLS: %s", strings.Join(plaintext, ", "))
+	}
+
+	// Thanos only reads the CAs at startup, so we track their contents on the pod template
+	// to roll the querier when they are rotated.
+	if opts.GRPCServerTLS != nil && opts.GRPCServerTLS.ClientCA != nil {
+		hash, err := cluster.handler.GetSecretKeyHash(ctx, query.GetNamespace(), *opts.GRPCServerTLS.ClientCA)
+		// a missing Secret is reported as a missing dependency, the hash is set once it is created
+		if err != nil && !apierrors.IsNotFound(err) {
+			return manifestquery.Options{}, fmt.Errorf("failed to read gRPC client CA: %w", err)
+		}
+		opts.GRPCServerTLS.ClientCAHash = hash
+	}
+	if opts.GRPCClientTLS != nil && opts.GRPCClientTLS.CA != nil {
+		hash, err := cluster.handler.GetSecretKeyHash(ctx, query.GetNamespace(), *opts.GRPCClientTLS.CA)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return manifestquery.Options{}, fmt.Errorf("failed to read gRPC CA of the endpoints: %w", err)
+		}
+		opts.GRPCClientTLS.CAHash = hash
+	}
+
 	return opts, nil
 }
 
-// getStoreAPIServiceEndpoints returns the list of endpoints for the StoreAPI services that match the ThanosQuery storeLabelSelector.
-func (r *ThanosQueryReconciler) getStoreAPIServiceEndpoints(ctx context.Context, query monitoringthanosiov1alpha1.ThanosQuery) ([]manifestquery.Endpoint, error) {
+// plaintextEndpoints returns the names of the Services of the endpoints that do not advertise TLS,
+// if any endpoint does.
+func plaintextEndpoints(endpoints []manifestquery.Endpoint) []string {
+	var plaintext []string
+	for _, ep := range endpoints {
+		if !ep.TLS {
+			plaintext = append(plaintext, ep.ServiceName)
+		}
+	}
+	return plaintext
+}
+
+// queryClientCertSecret returns the name of the Secret holding the client certificate the Querier presents to its
+// endpoints, or an empty string if it does not present one.
+func queryClientCertSecret(query monitoringthanosiov1alpha1.ThanosQuery) string {
+	if tls := query.Spec.GRPCClientTLS; tls != nil {
+		return ptr.Deref(tls.CertSecret, "")
+	}
+	if federation := query.Spec.Federation; federation != nil {
+		// the remotes share the same Secret, as Thanos uses a single TLS configuration for all endpoints
+		for _, remote := range federation.Remotes {
+			if remote.TLSSecret != nil {
+				return *remote.TLSSecret
+			}
+		}
+	}
+	return ""
+}
+
+// queryReferencedSecrets returns the names of the Secrets whose contents affect the generated resources.
+func queryReferencedSecrets(query monitoringthanosiov1alpha1.ThanosQuery) []string {
+	var secrets []string
+	if tls := query.Spec.GRPCServerTLS; tls != nil && tls.ClientCA != nil {
+		secrets = append(secrets, tls.ClientCA.Name)
+	}
+	if tls := query.Spec.GRPCClientTLS; tls != nil && tls.CA != nil {
+		secrets = append(secrets, tls.CA.Name)
+	}
+	if federation := query.Spec.Federation; federation != nil {
+		for _, remote := range federation.Remotes {
+			if remote.TLSSecret != nil && !slices.Contains(secrets, *remote.TLSSecret) {
+				secrets = append(secrets, *remote.TLSSecret)
+			}
+		}
+	}
+	return secrets
+}
+
+// getStoreAPIServiceEndpoints returns the list of endpoints for the StoreAPI services that match the ThanosQuery storeLabelSelector,
+// and the names of the ThanosReceive resources owning the discovered ingester services.
+// The services are discovered in the cluster the querier is deployed to.
+// If no StoreAPI service is found and the ThanosQuery neither federates remote clusters nor has external endpoints,
+// it is recorded in deps.
+func (r *ThanosQueryReconciler) getStoreAPIServiceEndpoints(ctx context.Context, cluster targetCluster, query monitoringthanosiov1alpha1.ThanosQuery, deps *dependencies) ([]manifestquery.Endpoint, []string, error) {
 	labelSelector, err := manifests.BuildLabelSelectorFrom(query.Spec.StoreLabelSelector, requiredStoreServiceLabels)
 	if err != nil {
-		return []manifestquery.Endpoint{}, err
+		return []manifestquery.Endpoint{}, nil, err
 	}
 	services := &corev1.ServiceList{}
 	listOpts := []client.ListOption{
 		client.MatchingLabelsSelector{Selector: labelSelector},
 		client.InNamespace(query.Namespace),
 	}
-	if err := r.List(ctx, services, listOpts...); err != nil {
-		return []manifestquery.Endpoint{}, err
+	if err := cluster.client.List(ctx, services, listOpts...); err != nil {
+		return []manifestquery.Endpoint{}, nil, err
 	}
 
 	if len(services.Items) == 0 {
-		r.recorder.Eventf(&query, nil, corev1.EventTypeWarning, "NoEndpointsFound", "Discovery", "No StoreAPI services found")
-		return []manifestquery.Endpoint{}, nil
+		// a global querier federating remote clusters, or querying external endpoints, does not need StoreAPIs in its own cluster
+		if query.Spec.Federation == nil && len(query.Spec.ExternalEndpoints) == 0 {
+			deps.add("StoreAPI Service")
+		}
+		return []manifestquery.Endpoint{}, nil, nil
 	}
 
 	endpointCountByType := make(map[manifests.EndpointType]int)
-	endpoints := make([]manifestquery.Endpoint, len(services.Items))
-	for i, svc := range services.Items {
+	endpoints := make([]manifestquery.Endpoint, 0, len(services.Items))
+	var receivers []string
+	for _, svc := range services.Items {
 
 		port, ok := manifests.IsGrpcServiceWithLabels(&svc, requiredStoreServiceLabels)
 		if !ok {
 			r.logger.Error(fmt.Errorf(
 				"service %s/%s is missing required gRPC port", svc.GetNamespace(), svc.GetName()),
-				"failed to get gRPC port for service",
+				"failed to get gRPC port for service, name the port grpc or set the "+manifests.GRPCPortAnnotation+" annotation",
 			)
 			continue
 		}
 
 		etype := r.getServiceTypeFromLabel(svc.ObjectMeta)
+		if ptr.Deref(query.Spec.DiscoveryMode, monitoringthanosiov1alpha1.QueryDiscoveryModeLabel) == monitoringthanosiov1alpha1.QueryDiscoveryModeEndpointGroup {
+			etype = endpointGroupType(etype, svc.ObjectMeta)
+		}
 
-		endpoints[i] = manifestquery.Endpoint{
+		endpoints = append(endpoints, manifestquery.Endpoint{
 			ServiceName: svc.GetName(),
 			Port:        port,
+			PortName:    servicePortName(svc, port),
 			Namespace:   svc.GetNamespace(),
 			Type:        etype,
-		}
+			TLS:         svc.GetLabels()[manifests.GRPCTLSLabel] == manifests.GRPCTLSLabelValue,
+		})
 		endpointCountByType[etype]++
+
+		owner := svc.GetLabels()[manifests.OwnerLabel]
+		if svc.GetLabels()[manifests.ComponentLabel] == manifestreceive.IngestComponentName && owner != "" && !slices.Contains(receivers, owner) {
+			receivers = append(receivers, owner)
+		}
 	}
 
 	for etype, count := range endpointCountByType {
@@ -245,7 +532,63 @@ func (r *ThanosQueryReconciler) getStoreAPIServiceEndpoints(ctx context.Context,
 	sort.Slice(endpoints, func(i, j int) bool {
 		return endpoints[i].ServiceName < endpoints[j].ServiceName
 	})
-	return endpoints, nil
+	sort.Strings(receivers)
+	return endpoints, receivers, nil
+}
+
+// setStatus records the discovered StoreAPI endpoints and the rollout state of the querier and the query frontend
+// on the ThanosQuery resource. The status is persisted with the next condition update.
+func (r *ThanosQueryReconciler) setStatus(ctx context.Context, cluster targetCluster, query *monitoringthanosiov1alpha1.ThanosQuery, endpoints []manifestquery.Endpoint) {
+	query.Status.ObservedGeneration = query.GetGeneration()
+	if endpoints != nil {
+		query.Status.EndpointCount = int32(len(endpoints))
+		query.Status.Endpoints = make([]monitoringthanosiov1alpha1.QueryEndpointStatus, 0, len(endpoints))
+		for _, endpoint := range endpoints {
+			query.Status.Endpoints = append(query.Status.Endpoints, monitoringthanosiov1alpha1.QueryEndpointStatus{
+				Name: endpoint.ServiceName,
+				Type: string(endpoint.Type),
+			})
+		}
+	}
+
+	ns := query.GetNamespace()
+	workloads := make([]workloadRollout, 0, 2)
+
+	querierName := QueryNameFromParent(query.GetName())
+	querier := &appsv1.Deployment{}
+	found, err := getWorkload(ctx, cluster.client, ns, querierName, querier)
+	if err != nil {
+		r.logger.Error(err, "failed to get querier for status update", "name", querierName)
+		return
+	}
+	if !found {
+		querier = nil
+	} else {
+		query.Status.Querier = toDeploymentStatus(querier)
+	}
+	workloads = append(workloads, deploymentRollout("Deployment/"+querierName, querier))
+
+	if query.Spec.QueryFrontend != nil {
+		frontendName := QueryFrontendNameFromParent(query.GetName())
+		frontend := &appsv1.Deployment{}
+		found, err := getWorkload(ctx, cluster.client, ns, frontendName, frontend)
+		if err != nil {
+			r.logger.Error(err, "failed to get query frontend for status update", "name", frontendName)
+			return
+		}
+		if !found {
+			frontend = nil
+		} else {
+			query.Status.QueryFrontend = toDeploymentStatus(frontend)
+		}
+		workloads = append(workloads, deploymentRollout("Deployment/"+frontendName, frontend))
+	} else {
+		query.Status.QueryFrontend = monitoringthanosiov1alpha1.DeploymentStatus{}
+	}
+
+	for _, condition := range rolloutConditions(workloads) {
+		meta.SetStatusCondition(&query.Status.Conditions, condition)
+	}
 }
 
 func (r *ThanosQueryReconciler) buildQueryFrontend(query monitoringthanosiov1alpha1.ThanosQuery) manifests.Buildable {
@@ -266,22 +609,54 @@ func (r *ThanosQueryReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	withLabelChangedPredicate := predicate.And(servicePredicate, predicate.LabelChangedPredicate{})
 	withGenerationChangePredicate := predicate.And(servicePredicate, predicate.GenerationChangedPredicate{}, servicePredicate)
-	withPredicate := predicate.Or(withLabelChangedPredicate, withGenerationChangePredicate)
+	withAnnotationChangedPredicate := predicate.And(servicePredicate, predicate.AnnotationChangedPredicate{})
+	withPredicate := predicate.Or(withLabelChangedPredicate, withGenerationChangePredicate, withAnnotationChangedPredicate)
 
-	err = ctrl.NewControllerManagedBy(mgr).
-		For(&monitoringthanosiov1alpha1.ThanosQuery{}).
+	bld := ctrl.NewControllerManagedBy(mgr).
+		For(&monitoringthanosiov1alpha1.ThanosQuery{}, builder.WithPredicates(r.scope.predicate())).
+		WithOptions(r.workqueue.controllerOptions()).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.ServiceAccount{}).
 		Owns(&corev1.Service{}).
 		Owns(&appsv1.Deployment{}).
+		Owns(&appsv1.StatefulSet{}).
 		Owns(&policyv1.PodDisruptionBudget{}).
 		Owns(&monitoringv1.ServiceMonitor{}).
+		Owns(&networkingv1.Ingress{}).
 		Watches(
 			&corev1.Service{},
 			r.enqueueForService(),
 			builder.WithPredicates(withPredicate),
 		).
-		Complete(r)
+		Watches(
+			&corev1.Secret{},
+			enqueueForReferencedSecret(r.Client, r.logger, func() client.ObjectList {
+				return &monitoringthanosiov1alpha1.ThanosQueryList{}
+			}, func(obj client.Object) []string {
+				return queryReferencedSecrets(*obj.(*monitoringthanosiov1alpha1.ThanosQuery))
+			}),
+			builder.OnlyMetadata, builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Watches(
+			&monitoringthanosiov1alpha1.ThanosDefaults{},
+			enqueueForDefaults(r.Client, func() client.ObjectList {
+				return &monitoringthanosiov1alpha1.ThanosQueryList{}
+			}),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		Watches(
+			&monitoringthanosiov1alpha1.ThanosReceive{},
+			r.enqueueForReceive(),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		)
+	if r.featureGate.GatewayAPIEnabled() {
+		bld.Owns(&gatewayv1.HTTPRoute{})
+	}
+	if r.featureGate.MonitoringMixinEnabled() {
+		bld.Owns(&monitoringv1.PrometheusRule{})
+	}
+
+	err = bld.Complete(r)
 
 	// if servicemonitor CRD exists in the cluster, watch for changes to ServiceMonitor resources
 	if err != nil {
@@ -352,6 +727,32 @@ func (r *ThanosQueryReconciler) getServiceTypeFromLabel(objMeta metav1.ObjectMet
 	return etype
 }
 
+// servicePortName returns the name of the port of the Service, or an empty string if the port is not named.
+func servicePortName(svc corev1.Service, port int32) string {
+	for _, p := range svc.Spec.Ports {
+		if p.Port == port {
+			return p.Name
+		}
+	}
+	return ""
+}
+
+// endpointGroupType returns the endpoint group type matching etype for the Service of a StoreAPI.
+// The Services of ingesters keep their type, since each ingester holds different series.
+func endpointGroupType(etype manifests.EndpointType, objMeta metav1.ObjectMeta) manifests.EndpointType {
+	if objMeta.GetLabels()[manifests.ComponentLabel] == manifestreceive.IngestComponentName {
+		return etype
+	}
+	switch etype {
+	case manifests.RegularLabel:
+		return manifests.GroupLabel
+	case manifests.StrictLabel:
+		return manifests.GroupStrictLabel
+	default:
+		return etype
+	}
+}
+
 var requiredStoreServiceLabels = manifestsstore.GetRequiredStoreServiceLabel()
 
 func (r *ThanosQueryReconciler) DisableConditionUpdate() *ThanosQueryReconciler {
@@ -366,7 +767,9 @@ func (r *ThanosQueryReconciler) updateCondition(ctx context.Context, query *moni
 	}
 	conditions := query.Status.Conditions
 	meta.SetStatusCondition(&conditions, condition)
+	setReadinessConditions(&conditions, condition)
 	query.Status.Conditions = conditions
+	query.Status.ObservedGeneration = query.GetGeneration()
 	if condition.Type == ConditionPaused {
 		query.Status.Paused = ptr.To(true)
 	}
@@ -375,18 +778,45 @@ func (r *ThanosQueryReconciler) updateCondition(ctx context.Context, query *moni
 	}
 }
 
-func (r *ThanosQueryReconciler) cleanup(ctx context.Context, resource monitoringthanosiov1alpha1.ThanosQuery, expectedResources []string) int {
+func (r *ThanosQueryReconciler) cleanup(ctx context.Context, cluster targetCluster, resource monitoringthanosiov1alpha1.ThanosQuery, expectedResources []string, applied *manifests.ObjectSet) int {
 	var errCount int
 	ns := resource.GetNamespace()
 	owner := resource.GetName()
 
-	errCount = r.pruneOrphanedResources(ctx, ns, owner, expectedResources)
+	errCount = r.pruneOrphanedResources(ctx, cluster, ns, owner, withMetricsServices(expectedResources))
 
 	name := manifestquery.Options{Options: manifests.Options{Owner: owner}}.GetGeneratedResourceName()
-	errCount += r.handler.DeleteResource(ctx, getDisabledFeatureGatedResources(r.featureGate, []string{name}, ns))
+	errCount += cluster.handler.DeleteResource(ctx, getDisabledFeatureGatedResources(r.featureGate, []string{name}, ns))
+	errCount += cluster.handler.DeleteResource(ctx, getDisabledMetricsServices(metricsServiceEnabled(resource.Spec.MetricsService), []string{name}, ns))
 
-	if resource.Spec.Replicas < 2 {
-		pruner := r.handler.NewResourcePruner().WithPodDisruptionBudget()
+	if !ptr.Deref(resource.Spec.ArgsFile, false) {
+		argsFile := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: manifests.ArgsFileConfigMapName(name), Namespace: ns}}
+		errCount += cluster.handler.DeleteResource(ctx, []client.Object{argsFile})
+	}
+	if len(resource.Spec.ExternalEndpoints) == 0 {
+		externalEndpoints := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: manifestquery.ExternalEndpointsConfigMapName(name), Namespace: ns}}
+		errCount += cluster.handler.DeleteResource(ctx, []client.Object{externalEndpoints})
+	}
+
+	frontendName := manifestqueryfrontend.Options{Options: manifests.Options{Owner: owner}}.GetGeneratedResourceName()
+	frontendMetricsService := resource.Spec.QueryFrontend != nil && metricsServiceEnabled(resource.Spec.QueryFrontend.MetricsService)
+	errCount += cluster.handler.DeleteResource(ctx, getDisabledMetricsServices(frontendMetricsService, []string{frontendName}, ns))
+	if queryFrontendManagedCacheToOpts(resource) == nil {
+		errCount += cluster.handler.DeleteResource(ctx, getUnmanagedCache(frontendName, ns))
+	}
+
+	// the ingress routes to the Query Frontend when there is one, and to the Querier otherwise
+	querierIngress, frontendIngress := resource.Spec.Ingress, resource.Spec.Ingress
+	if resource.Spec.QueryFrontend != nil {
+		querierIngress = nil
+	} else {
+		frontendIngress = nil
+	}
+	errCount += cluster.handler.DeleteResource(ctx, getUnusedIngresses(name, ns, querierIngress))
+	errCount += cluster.handler.DeleteResource(ctx, getUnusedIngresses(frontendName, ns, frontendIngress))
+
+	if deploymentReplicas(resource.Spec.Replicas, resource.Status.Querier) < 2 {
+		pruner := cluster.handler.NewResourcePruner().WithPodDisruptionBudget()
 		errCount += pruner.Prune(ctx, []string{},
 			manifests.GetLabelSelectorForOwner(manifestquery.Options{Options: manifests.Options{Owner: owner}}),
 			client.InNamespace(ns),
@@ -394,20 +824,29 @@ func (r *ThanosQueryReconciler) cleanup(ctx context.Context, resource monitoring
 	}
 
 	if resource.Spec.QueryFrontend != nil && resource.Spec.QueryFrontend.Replicas < 2 {
-		pruner := r.handler.NewResourcePruner().WithPodDisruptionBudget()
+		pruner := cluster.handler.NewResourcePruner().WithPodDisruptionBudget()
 		errCount += pruner.Prune(ctx, []string{},
 			manifests.GetLabelSelectorForOwner(manifestqueryfrontend.Options{Options: manifests.Options{Owner: owner}}),
 			client.InNamespace(ns),
 		)
 	}
 
+	pruner := cluster.handler.NewResourcePruner().WithGeneratedResources().WithGracePeriod(r.pruneGracePeriod)
+	if r.featureGate.MonitoringMixinEnabled() {
+		pruner = pruner.WithPrometheusRule()
+	}
+	errCount += pruner.PruneStale(ctx, ns, resource.GetUID(), applied)
+	r.pendingDeletions.record(types.NamespacedName{Namespace: ns, Name: owner}, pruner.RequeueAfter())
+
 	return errCount
 }
 
-func (r *ThanosQueryReconciler) pruneOrphanedResources(ctx context.Context, ns, owner string, expectedResources []string) int {
+func (r *ThanosQueryReconciler) pruneOrphanedResources(ctx context.Context, cluster targetCluster, ns, owner string, expectedResources []string) int {
 	listOpt := manifests.GetLabelSelectorForOwner(manifestquery.Options{Options: manifests.Options{Owner: owner}})
 	listOpts := []client.ListOption{listOpt, client.InNamespace(ns)}
 
-	pruner := r.handler.NewResourcePruner().WithServiceAccount().WithService().WithDeployment().WithPodDisruptionBudget().WithServiceMonitor()
-	return pruner.Prune(ctx, expectedResources, listOpts...)
+	pruner := cluster.handler.NewResourcePruner().WithServiceAccount().WithService().WithDeployment().WithPodDisruptionBudget().WithServiceMonitor().WithGracePeriod(r.pruneGracePeriod)
+	errCount := pruner.Prune(ctx, expectedResources, listOpts...)
+	r.pendingDeletions.record(types.NamespacedName{Namespace: ns, Name: owner}, pruner.RequeueAfter())
+	return errCount
 }
@@ -101,7 +101,7 @@ var _ = Describe("ThanosQuery Controller", Ordered, func() {
 					CommonFields: monitoringthanosiov1alpha1.CommonFields{
 						Labels: map[string]string{"some-label": "xyz"},
 					},
-					Replicas:      3,
+					Replicas:      ptr.To(int32(3)),
 					ReplicaLabels: []string{"replica"},
 					Additional: monitoringthanosiov1alpha1.Additional{
 						Containers: []corev1.Container{
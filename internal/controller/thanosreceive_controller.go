@@ -20,8 +20,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/client_golang/prometheus"
 	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/client-go/tools/events"
 
@@ -34,8 +41,10 @@ import (
 	"github.com/thanos-community/thanos-operator/internal/pkg/receive"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -50,18 +59,28 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 //+kubebuilder:rbac:groups=monitoring.thanos.io,resources=thanosreceives,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=monitoring.thanos.io,resources=thanosreceives/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=monitoring.thanos.io,resources=thanosreceives/finalizers,verbs=update
+//+kubebuilder:rbac:groups=monitoring.thanos.io,resources=thanostenants,verbs=get;list;watch
 //+kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=services;configmaps;serviceaccounts,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=persistentvolumeclaims;persistentvolumes,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=delete;patch
+//+kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
 
 const (
 	receiveFinalizer = "monitoring.thanos.io/receive-finalizer"
@@ -79,6 +98,14 @@ type ThanosReceiveReconciler struct {
 	handler                *handlers.Handler
 	disableConditionUpdate bool
 	featureGate            featuregate.Config
+	scope                  controllerScope
+
+	dependencyBackoff *dependencyBackoff
+	workqueue         WorkqueueConfig
+	pruneGracePeriod  time.Duration
+	pendingDeletions  *pendingDeletions
+	targetClusters    *targetClusters
+	defaults          fleetDefaults
 }
 
 // NewThanosReceiveReconciler returns a reconciler for ThanosReceive resources.
@@ -90,8 +117,16 @@ func NewThanosReceiveReconciler(conf Config, client client.Client, scheme *runti
 		metrics:     controllermetrics.NewThanosReceiveMetrics(conf.InstrumentationConfig.MetricsRegistry, conf.InstrumentationConfig.CommonMetrics),
 		recorder:    conf.InstrumentationConfig.EventRecorder,
 		featureGate: conf.FeatureGate,
-		handler:     handlers.NewHandler(client, scheme, conf.InstrumentationConfig.Logger).SetFeatureGates(conf.FeatureGate.ToGVK()),
+		scope:       newControllerScope(conf),
+		handler:     handlers.NewHandler(client, scheme, conf.InstrumentationConfig.Logger).SetFeatureGates(conf.FeatureGate.ToGVK()).WithMutators(conf.Mutators...).WithMetrics("receive", conf.InstrumentationConfig.CommonMetrics).WithEventRecorder(conf.InstrumentationConfig.EventRecorder),
+
+		dependencyBackoff: newDependencyBackoff(),
+		workqueue:         conf.Workqueue,
+		pruneGracePeriod:  conf.PruneGracePeriod,
+		pendingDeletions:  newPendingDeletions(),
 	}
+	reconciler.targetClusters = newTargetClusters(conf, "receive", client, reconciler.handler, scheme)
+	reconciler.defaults = fleetDefaults{client: client}
 
 	return reconciler
 }
@@ -101,11 +136,14 @@ func NewThanosReceiveReconciler(conf Config, client client.Client, scheme *runti
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.17.3/pkg/reconcile
 func (r *ThanosReceiveReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	defer prometheus.NewTimer(r.metrics.ReconcileDurationSeconds.WithLabelValues("receive")).ObserveDuration()
+
 	receiver := &monitoringthanosiov1alpha1.ThanosReceive{}
 	err := r.Get(ctx, req.NamespacedName, receiver)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			r.logger.Info("thanos receive resource not found. ignoring since object may be deleted")
+			r.dependencyBackoff.reset(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		r.logger.Error(err, "failed to get ThanosReceive")
@@ -113,7 +151,17 @@ func (r *ThanosReceiveReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, err
 	}
 
-	if receiver.Spec.Paused != nil && *receiver.Spec.Paused {
+	if !r.scope.manages(receiver) {
+		r.logger.V(1).Info("ThanosReceive resource is managed by another operator instance, skipping")
+		return ctrl.Result{}, nil
+	}
+
+	// deletion is handled even when paused, so that a paused resource can still be deleted
+	if !receiver.GetDeletionTimestamp().IsZero() {
+		return r.handleDeletionTimestamp(ctx, receiver)
+	}
+
+	if isPaused(receiver, receiver.Spec.Paused) {
 		r.logger.Info("receiver is paused")
 		r.recorder.Eventf(receiver, nil, corev1.EventTypeNormal, "Paused", "Reconcile",
 			"Reconciliation is paused for ThanosReceive resource")
@@ -129,11 +177,54 @@ func (r *ThanosReceiveReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 	r.metrics.Paused.WithLabelValues("receive", receiver.GetName(), receiver.GetNamespace()).Set(0)
 
-	if !receiver.GetDeletionTimestamp().IsZero() {
-		return r.handleDeletionTimestamp(receiver)
+	if controllerutil.AddFinalizer(receiver, receiveFinalizer) {
+		if err := r.Update(ctx, receiver); err != nil {
+			r.logger.Error(err, "failed to add finalizer", "resource", receiver.GetName(), "namespace", receiver.GetNamespace())
+			return ctrl.Result{}, err
+		}
 	}
 
-	err = r.syncResources(ctx, *receiver)
+	var hashrings *receiveHashringState
+	cluster, err := r.targetClusters.get(ctx, receiver.Spec.TargetCluster)
+	if err == nil {
+		err = r.defaults.apply(ctx, receiver.GetNamespace(), receiveCommonFields(receiver)...)
+	}
+	if err == nil {
+		hashrings, err = r.syncResources(ctx, cluster, *receiver)
+		r.setStatus(ctx, cluster, receiver, hashrings)
+		r.expandVolumes(ctx, cluster, receiver)
+		r.reportVolumeZones(ctx, cluster, receiver)
+		r.reportUploadLag(ctx, cluster, receiver)
+		r.reportIngesterHealth(ctx, cluster, receiver)
+		r.reportWriteProbe(ctx, cluster, receiver)
+	}
+	if hashrings != nil {
+		r.reportReplication(receiver, hashrings.replication)
+	}
+	if isInvalidObjectStorageConfig(err) {
+		r.logger.Info("invalid object storage configuration", "resource", receiver.GetName(), "namespace", receiver.GetNamespace(), "reason", err.Error())
+		r.recorder.Eventf(receiver, nil, corev1.EventTypeWarning, "InvalidObjectStorageConfig", "Reconcile", "%v", err)
+		meta.SetStatusCondition(&receiver.Status.Conditions, objectStorageDegradedCondition(err))
+		r.updateCondition(ctx, receiver, metav1.Condition{
+			Type:    ConditionReconcileFailed,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonInvalidObjectStorageConfig,
+			Message: err.Error(),
+		})
+		// the Secrets are watched, the resource is reconciled again once they are fixed
+		return ctrl.Result{}, nil
+	}
+	if isMissingDependency(err) {
+		r.logger.V(1).Info("waiting for dependencies", "resource", receiver.GetName(), "namespace", receiver.GetNamespace(), "reason", err.Error())
+		r.recorder.Eventf(receiver, nil, corev1.EventTypeWarning, "DependencyMissing", "Reconcile", "%v", err)
+		r.updateCondition(ctx, receiver, metav1.Condition{
+			Type:    ConditionDependencyMissing,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonDependencyNotFound,
+			Message: err.Error(),
+		})
+		return ctrl.Result{RequeueAfter: r.dependencyBackoff.next(req.NamespacedName)}, nil
+	}
 	if err != nil {
 		r.logger.Error(err, "failed to sync resources", "resource", receiver.GetName(), "namespace", receiver.GetNamespace())
 		r.recorder.Eventf(receiver, nil, corev1.EventTypeWarning, "SyncFailed", "Reconcile", "Failed to sync resources: %v", err)
@@ -146,6 +237,15 @@ func (r *ThanosReceiveReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, err
 	}
 
+	r.dependencyBackoff.reset(req.NamespacedName)
+	meta.RemoveStatusCondition(&receiver.Status.Conditions, ConditionDependencyMissing)
+	clearObjectStorageDegraded(&receiver.Status.Conditions)
+	if err := reportObjectStorageVerification(ctx, cluster.client, r.recorder, receiver, &receiver.Status.Conditions, receiver.Spec.VerifyObjectStorage); err != nil {
+		r.logger.Error(err, "failed to report object storage verification", "resource", receiver.GetName(), "namespace", receiver.GetNamespace())
+	}
+	if err := reportReceiveBucketRetention(ctx, r.Client, r.recorder, receiver); err != nil {
+		r.logger.Error(err, "failed to report bucket retention", "resource", receiver.GetName(), "namespace", receiver.GetNamespace())
+	}
 	r.updateCondition(ctx, receiver, metav1.Condition{
 		Type:    ConditionReconcileSuccess,
 		Status:  metav1.ConditionTrue,
@@ -153,7 +253,20 @@ func (r *ThanosReceiveReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		Message: "Reconciliation completed successfully",
 	})
 
-	return ctrl.Result{}, nil
+	// requeue to delete orphaned resources once their prune grace period expires, to apply a held back hashring
+	// configuration update, or to run the next ingester health or upload lag check if it is due earlier
+	requeueAfter := r.pendingDeletions.pop(req.NamespacedName)
+	var updateDelay time.Duration
+	if hashrings != nil {
+		updateDelay = hashrings.updateDelay
+	}
+	now := time.Now()
+	for _, next := range []time.Duration{updateDelay, nextIngesterHealthCheck(receiver.Status.IngesterHealth, now), nextUploadLagCheck(*receiver, now)} {
+		if next > 0 && (requeueAfter == 0 || next < requeueAfter) {
+			requeueAfter = next
+		}
+	}
+	return ctrl.Result{RequeueAfter: cluster.requeueAfter(requeueAfter)}, nil
 }
 
 // +kubebuilder:rbac:groups=monitoring.thanos.io,resources=thanosreceives,verbs=get;list;watch;create;update;patch;delete
@@ -163,6 +276,8 @@ func (r *ThanosReceiveReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 // +kubebuilder:rbac:groups="",resources=services;configmaps;serviceaccounts,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="discovery.k8s.io",resources=endpointslices,verbs=get;list;watch
 // +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;create;update;patch;delete
 // SetupWithManager sets up the controller with the Manager.
 func (r *ThanosReceiveReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	bld := ctrl.NewControllerManagedBy(mgr)
@@ -186,60 +301,208 @@ func (r *ThanosReceiveReconciler) buildController(bld builder.Builder) error {
 		return err
 	}
 
+	// add a selector to watch for the Jobs that are created by the write probe CronJob(s).
+	writeProbeJobPredicate, err := predicate.LabelSelectorPredicate(metav1.LabelSelector{
+		MatchLabels: manifestreceive.GetRequiredWriteProbeLabels(),
+	})
+	if err != nil {
+		return err
+	}
+
 	bld.
-		For(&monitoringthanosiov1alpha1.ThanosReceive{}).
+		For(&monitoringthanosiov1alpha1.ThanosReceive{}, builder.WithPredicates(r.scope.predicate())).
+		WithOptions(r.workqueue.controllerOptions()).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.ServiceAccount{}).
 		Owns(&corev1.Service{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&appsv1.StatefulSet{}).
+		Owns(&batchv1.CronJob{}).
+		Owns(&batchv1.Job{}).
+		Owns(&networkingv1.Ingress{}).
+		Owns(&networkingv1.NetworkPolicy{}).
+		Watches(
+			&batchv1.Job{},
+			r.enqueueForWriteProbeJob(r.Client),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}, writeProbeJobPredicate),
+		).
 		Watches(
 			&discoveryv1.EndpointSlice{},
 			r.enqueueForEndpointSlice(r.Client),
 			builder.WithPredicates(predicate.GenerationChangedPredicate{}, endpointSlicePredicate),
+		).
+		Watches(
+			&corev1.Secret{},
+			enqueueForReferencedSecret(r.Client, r.logger, func() client.ObjectList {
+				return &monitoringthanosiov1alpha1.ThanosReceiveList{}
+			}, func(obj client.Object) []string {
+				return receiveReferencedSecrets(*obj.(*monitoringthanosiov1alpha1.ThanosReceive))
+			}),
+			builder.OnlyMetadata, builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Watches(
+			&corev1.ConfigMap{},
+			enqueueForReferencedConfigMap(r.Client, r.logger, func() client.ObjectList {
+				return &monitoringthanosiov1alpha1.ThanosReceiveList{}
+			}, func(obj client.Object) []string {
+				receiver := obj.(*monitoringthanosiov1alpha1.ThanosReceive)
+				configs := []*monitoringthanosiov1alpha1.ObjectStorageConfig{&receiver.Spec.Ingester.DefaultObjectStorageConfig}
+				for _, hashring := range receiver.Spec.Ingester.Hashrings {
+					configs = append(configs, hashring.ObjectStorageConfig)
+				}
+				return objectStorageConfigMaps(configs...)
+			}),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Watches(
+			&monitoringthanosiov1alpha1.ThanosDefaults{},
+			enqueueForDefaults(r.Client, func() client.ObjectList {
+				return &monitoringthanosiov1alpha1.ThanosReceiveList{}
+			}),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		Watches(
+			&monitoringthanosiov1alpha1.ThanosTenant{},
+			enqueueForTenant(r.Client),
+			builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{})),
 		)
+	if r.featureGate.GatewayAPIEnabled() {
+		bld.Owns(&gatewayv1.HTTPRoute{})
+	}
+	if r.featureGate.MonitoringMixinEnabled() {
+		bld.Owns(&monitoringv1.PrometheusRule{})
+	}
 
 	return bld.Complete(r)
 }
 
 // syncResources syncs the resources for the ThanosReceive resource.
 // It creates or updates the resources for the hashrings and the router.
-func (r *ThanosReceiveReconciler) syncResources(ctx context.Context, receiver monitoringthanosiov1alpha1.ThanosReceive) error {
+// It returns the state of the hashrings observed while building the hashring configuration.
+func (r *ThanosReceiveReconciler) syncResources(ctx context.Context, cluster targetCluster, receiver monitoringthanosiov1alpha1.ThanosReceive) (*receiveHashringState, error) {
 	var errCount int
+	appliedObjects := manifests.NewObjectSet()
+	cluster.handler = cluster.handler.RecordApplied(appliedObjects)
+
+	// missing dependencies are reported once everything that can be applied has been applied
+	deps := &dependencies{}
+	if err := deps.requireSecrets(ctx, cluster.client, receiver.GetNamespace(), receiveReferencedSecrets(receiver)...); err != nil {
+		return nil, err
+	}
+	// the hashrings point to the object storage configurations of the cached object, which must not be rewritten
+	receiver.Spec.Ingester = *receiver.Spec.Ingester.DeepCopy()
+	tenants, err := r.selectedTenants(ctx, receiver)
+	if err != nil {
+		return nil, err
+	}
+	for _, msg := range assignTenants(&receiver, tenants) {
+		r.recorder.Eventf(&receiver, nil, corev1.EventTypeWarning, "TenantRejected", "Reconcile", "%s", msg)
+	}
+	objStoreConfigs := []*monitoringthanosiov1alpha1.ObjectStorageConfig{&receiver.Spec.Ingester.DefaultObjectStorageConfig}
+	for _, hashring := range receiver.Spec.Ingester.Hashrings {
+		if hashring.ObjectStorageConfig != nil {
+			objStoreConfigs = append(objStoreConfigs, hashring.ObjectStorageConfig)
+		}
+	}
+	if err := materializeObjectStorageConfigs(ctx, cluster, &receiver, deps, objStoreConfigs...); err != nil {
+		return nil, err
+	}
+	if err := validateObjectStorageConfigs(ctx, cluster.client, receiver.GetNamespace(), receiveObjectStorageConfigs(receiver)...); err != nil {
+		return nil, err
+	}
 
-	ingestOpts := r.specToIngestOptions(receiver)
+	autoscaled, err := r.autoscaleHashrings(ctx, cluster, receiver)
+	if err != nil {
+		return nil, err
+	}
+	ingestOpts, err := r.specToIngestOptions(ctx, cluster, receiver, autoscaled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build ingester options: %w", err)
+	}
+	applied, err := currentHashrings(ctx, cluster, receiver)
+	if err != nil {
+		return nil, err
+	}
+	rollout := newHashringRollout(receiver.Spec.Ingester.Rollout)
 	expectIngesters := make([]string, len(ingestOpts))
 	for i, opt := range ingestOpts {
 		expectIngesters[i] = opt.GetGeneratedResourceName()
-		errCount += r.handler.CreateOrUpdate(ctx, receiver.GetNamespace(), &receiver, opt.Build())
+		objs := opt.Build()
+		if err := r.rolloutIngester(ctx, cluster, receiver, receiver.Spec.Ingester.Hashrings[i], applied, rollout, objs); err != nil {
+			return nil, err
+		}
+		errCount += cluster.handler.CreateOrUpdate(ctx, receiver.GetNamespace(), &receiver, objs)
 	}
+	verifyOpts, err := r.objStoreVerifyOptions(ctx, cluster, receiver)
+	if err != nil {
+		return nil, err
+	}
+	errCount += syncObjectStorageVerification(ctx, cluster, &receiver, verifyOpts)
 	// we won't error out here yet as we don't want to delay updating the router configmap
 
-	hashringConfig, err := r.buildHashringConfig(ctx, receiver)
+	hashringConfig, replication, updateDelay, err := r.buildHashringConfig(ctx, cluster, receiver, autoscaled, deps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build hashring config: %w", err)
+	}
+	revision, err := currentHashringConfigRevision(ctx, cluster, receiver)
+	if err != nil {
+		return nil, err
+	}
+	if receiver.Spec.Router.ExistingHashringConfigMap == nil {
+		revision = nextHashringConfigRevision(revision, string(hashringConfig), time.Now())
+	} else {
+		// the configuration is not written by the operator, so it is not versioned
+		revision = hashringConfigRevision{config: string(hashringConfig)}
+	}
+	r.metrics.HashringConfigVersion.WithLabelValues(receiver.GetName(), receiver.GetNamespace()).Set(float64(revision.version))
+	state := &receiveHashringState{
+		replication:      replication,
+		rollout:          rollout.status(),
+		configVersion:    revision.version,
+		configUpdateTime: revision.updateTime,
+		updateDelay:      updateDelay,
+	}
+
+	routerOpts, err := r.specToRouterOptions(ctx, cluster, receiver, revision)
 	if err != nil {
-		return fmt.Errorf("failed to build hashring config: %w", err)
+		return state, fmt.Errorf("failed to build router options: %w", err)
 	}
-	routerOpts := r.specToRouterOptions(receiver, string(hashringConfig))
 
-	if errs := r.handler.CreateOrUpdate(ctx, receiver.GetNamespace(), &receiver, routerOpts.Build()); errs > 0 {
-		return fmt.Errorf("failed to create or update %d resources for the receive router", errs)
+	if errs := cluster.handler.CreateOrUpdate(ctx, receiver.GetNamespace(), &receiver, routerOpts.Build()); errs > 0 {
+		return state, fmt.Errorf("failed to create or update %d resources for the receive router", errs)
 	}
+	for _, group := range receiver.Spec.Router.Groups {
+		groupOpts := receiverV1Alpha1ToRouterGroupOptions(routerOpts, receiver.Spec.Router, group)
+		if routerOpts.Replicas == 0 {
+			// the groups are held back together with the routers of the ThanosReceive
+			groupOpts.Replicas = 0
+		}
+		if errs := cluster.handler.CreateOrUpdate(ctx, receiver.GetNamespace(), &receiver, groupOpts.Build()); errs > 0 {
+			return state, fmt.Errorf("failed to create or update %d resources for the receive router group %s", errs, group.Name)
+		}
+	}
+	r.recordHashringChanges(&receiver, applied, hashringConfig)
+
+	if err := r.syncWriteProbe(ctx, cluster, receiver); err != nil {
+		return state, err
+	}
+	state.configHash = manifestreceive.HashringConfigHash(revision.config)
 
 	// we go back and force a reconcile now on the original errors from the ingesters
 	if errCount > 0 {
-		return fmt.Errorf("failed to create or update %d resources for receive hashring(s)", errCount)
+		return state, fmt.Errorf("failed to create or update %d resources for receive hashring(s)", errCount)
 	}
 
-	cleanupErrCount := r.cleanup(ctx, receiver, expectIngesters, routerOpts.GetGeneratedResourceName())
+	cleanupErrCount := r.cleanup(ctx, cluster, receiver, expectIngesters, routerOpts.GetGeneratedResourceName(), appliedObjects)
 	if cleanupErrCount > 0 {
-		return fmt.Errorf("failed to clean up %d orphaned resources for the receiver", cleanupErrCount)
+		return state, fmt.Errorf("failed to clean up %d orphaned resources for the receiver", cleanupErrCount)
 	}
 
-	return nil
+	return state, deps.err()
 
 }
 
-func (r *ThanosReceiveReconciler) specToIngestOptions(receiver monitoringthanosiov1alpha1.ThanosReceive) []manifests.Buildable {
+func (r *ThanosReceiveReconciler) specToIngestOptions(ctx context.Context, cluster targetCluster, receiver monitoringthanosiov1alpha1.ThanosReceive, autoscaled map[string]hashringAutoscale) ([]manifests.Buildable, error) {
 	opts := make([]manifests.Buildable, len(receiver.Spec.Ingester.Hashrings))
 	for i, v := range receiver.Spec.Ingester.Hashrings {
 		opt := receiverV1Alpha1ToIngesterOptions(receiverV1Alpha1ToIngesterTransformInput{
@@ -248,50 +511,154 @@ func (r *ThanosReceiveReconciler) specToIngestOptions(receiver monitoringthanosi
 			FeatureGate: r.featureGate,
 		})
 		opt.HashringName = v.Name
+
+		if scale, ok := autoscaled[v.Name]; ok {
+			opt.Replicas = scale.replicas
+		} else {
+			replicas, err := r.ingesterReplicas(ctx, cluster, receiver, v)
+			if err != nil {
+				return nil, err
+			}
+			opt.Replicas = replicas
+		}
+
+		// Thanos only reads the object storage configuration at startup, so we track the contents
+		// of the Secret on the pod template to roll the ingesters when it is rotated.
+		hash, err := cluster.handler.GetSecretHash(ctx, receiver.GetNamespace(), opt.ObjStoreSecret.Name)
+		// a missing Secret is reported as a missing dependency, the hash is set once it is created
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to read object storage secret of hashring %s: %w", v.Name, err)
+		}
+		opt.ObjStoreConfig.Hash = hash
+
+		if opt.GRPCTLS != nil && opt.GRPCTLS.ClientCA != nil {
+			hash, err := cluster.handler.GetSecretKeyHash(ctx, receiver.GetNamespace(), *opt.GRPCTLS.ClientCA)
+			if err != nil && !apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to read gRPC client CA of hashring %s: %w", v.Name, err)
+			}
+			opt.GRPCTLS.ClientCAHash = hash
+		}
 		opts[i] = opt
 	}
-	return opts
+	return opts, nil
 }
 
-func (r *ThanosReceiveReconciler) specToRouterOptions(receiver monitoringthanosiov1alpha1.ThanosReceive, hashringConfig string) manifests.Buildable {
+func (r *ThanosReceiveReconciler) specToRouterOptions(ctx context.Context, cluster targetCluster, receiver monitoringthanosiov1alpha1.ThanosReceive, revision hashringConfigRevision) (manifestreceive.RouterOptions, error) {
 	opts := receiverV1Alpha1ToRouterOptions(receiverV1Alpha1ToRouterTransformInput{
 		CRD:         receiver,
 		FeatureGate: r.featureGate,
 	})
-	opts.HashringConfig = hashringConfig
-	return opts
+	opts.HashringConfig = revision.config
+	opts.HashringConfigVersion = revision.version
+	opts.HashringConfigUpdateTime = revision.updateTime
+
+	router := &appsv1.Deployment{}
+	found, err := getWorkload(ctx, cluster.client, receiver.GetNamespace(), opts.GetGeneratedResourceName(), router)
+	if err != nil {
+		return manifestreceive.RouterOptions{}, fmt.Errorf("failed to get the receive router: %w", err)
+	}
+	if !found {
+		router = nil
+	}
+	if holdRouter(revision.config, opts.ExistingHashringConfigMapName != "", router) {
+		r.logger.V(1).Info("holding the receive router back until a hashring has ready ingesters", "resource", receiver.GetName(), "namespace", receiver.GetNamespace())
+		opts.Replicas = 0
+	}
+
+	// Thanos only reads the client CA at startup, so we track its contents on the pod template
+	// to roll the router when the CA is rotated.
+	if opts.RemoteWriteTLS != nil && opts.RemoteWriteTLS.ClientCA != nil {
+		hash, err := cluster.handler.GetSecretKeyHash(ctx, receiver.GetNamespace(), *opts.RemoteWriteTLS.ClientCA)
+		// a missing Secret is reported as a missing dependency, the hash is set once it is created
+		if err != nil && !apierrors.IsNotFound(err) {
+			return manifestreceive.RouterOptions{}, fmt.Errorf("failed to read remote write client CA: %w", err)
+		}
+		opts.RemoteWriteTLS.ClientCAHash = hash
+	}
+	if opts.GRPCClientTLS != nil && opts.GRPCClientTLS.CA != nil {
+		hash, err := cluster.handler.GetSecretKeyHash(ctx, receiver.GetNamespace(), *opts.GRPCClientTLS.CA)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return manifestreceive.RouterOptions{}, fmt.Errorf("failed to read gRPC CA of the ingesters: %w", err)
+		}
+		opts.GRPCClientTLS.CAHash = hash
+	}
+	return opts, nil
 }
 
-// buildHashringConfig builds the hashring configuration for the ThanosReceive resource.
-func (r *ThanosReceiveReconciler) buildHashringConfig(ctx context.Context, receiver monitoringthanosiov1alpha1.ThanosReceive) ([]byte, error) {
+// currentHashringConfigRevision returns the revision of the hashring configuration currently applied to the router
+// of the ThanosReceive, read from the hashring ConfigMap and its annotations.
+func currentHashringConfigRevision(ctx context.Context, cluster targetCluster, receiver monitoringthanosiov1alpha1.ThanosReceive) (hashringConfigRevision, error) {
 	cm := &corev1.ConfigMap{}
 	name := ReceiveRouterNameFromParent(receiver.GetName())
-	err := r.Client.Get(ctx, client.ObjectKey{Namespace: receiver.GetNamespace(), Name: name}, cm)
+	err := cluster.client.Get(ctx, client.ObjectKey{Namespace: receiver.GetNamespace(), Name: name}, cm)
 	if err != nil {
 		if !apierrors.IsNotFound(err) {
-			return nil, fmt.Errorf("failed to get config map for resource %s: %w", name, err)
+			return hashringConfigRevision{}, fmt.Errorf("failed to get config map for resource %s: %w", name, err)
 		}
 	}
 
-	var currentHashringState receive.Hashrings
-	if cm.Data != nil && cm.Data[manifestreceive.HashringConfigKey] != "" {
-		if err := json.Unmarshal([]byte(cm.Data[manifestreceive.HashringConfigKey]), &currentHashringState); err != nil {
+	version, _ := strconv.ParseInt(cm.GetAnnotations()[manifestreceive.HashringConfigVersionAnnotation], 10, 64)
+	updateTime, _ := time.Parse(time.RFC3339, cm.GetAnnotations()[manifestreceive.HashringConfigUpdatedAnnotation])
+	return hashringConfigRevision{
+		config:     cm.Data[manifestreceive.HashringConfigKey],
+		version:    version,
+		updateTime: updateTime,
+	}, nil
+}
+
+// currentHashrings returns the hashring configuration currently applied to the router of the ThanosReceive.
+func currentHashrings(ctx context.Context, cluster targetCluster, receiver monitoringthanosiov1alpha1.ThanosReceive) (receive.Hashrings, error) {
+	revision, err := currentHashringConfigRevision(ctx, cluster, receiver)
+	if err != nil {
+		return nil, err
+	}
+
+	var hashrings receive.Hashrings
+	if revision.config != "" {
+		if err := json.Unmarshal([]byte(revision.config), &hashrings); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal current state from ConfigMap: %w", err)
 		}
 	}
+	return hashrings, nil
+}
+
+// buildHashringConfig builds the hashring configuration for the ThanosReceive resource.
+// Hashrings without ready endpoints are recorded in deps.
+// It also returns the number of ready endpoints observed for each hashring, and how long a change to the
+// configuration is held back by the hashring update interval, zero if no change is held back.
+func (r *ThanosReceiveReconciler) buildHashringConfig(ctx context.Context, cluster targetCluster, receiver monitoringthanosiov1alpha1.ThanosReceive, autoscaled map[string]hashringAutoscale, deps *dependencies) ([]byte, []hashringReplication, time.Duration, error) {
+	currentHashringState, err := currentHashrings(ctx, cluster, receiver)
+	if err != nil {
+		return nil, nil, 0, err
+	}
 
 	fetchedReadyState := make(receive.HashringState, len(receiver.Spec.Ingester.Hashrings))
+	replication := make([]hashringReplication, 0, len(receiver.Spec.Ingester.Hashrings))
 	for _, hashring := range receiver.Spec.Ingester.Hashrings {
-		filters := []receive.EndpointFilter{receive.FilterEndpointReady()}
+		var filters []receive.EndpointFilter
 		labelValue := ReceiveIngesterNameFromParent(receiver.GetName(), hashring.Name)
-		eps, err := r.handler.GetEndpointSlices(ctx, labelValue, receiver.GetNamespace())
+		replicas := hashring.Replicas
+		if hashring.ScaleDownGracePeriod != nil {
+			// ingesters being scaled down are removed from the hashring before the StatefulSet is scaled down
+			filters = append(filters, receive.FilterEndpointByOrdinal(labelValue, int(replicas)))
+		}
+		if scale, ok := autoscaled[hashring.Name]; ok {
+			// ingesters added by an autoscaler only join the hashring once their replicas have settled
+			replicas = scale.configReplicas
+			filters = append(filters, receive.FilterEndpointByOrdinal(labelValue, int(replicas)))
+		}
+		readyFilters := append(slices.Clone(filters), receive.FilterEndpointReady(), receive.FilterEndpointNotTerminating())
+		if ptr.Deref(hashring.EndpointPolicy, monitoringthanosiov1alpha1.HashringEndpointPolicyReadyOnly) == monitoringthanosiov1alpha1.HashringEndpointPolicyReadyOnly {
+			filters = readyFilters
+		}
+		eps, err := cluster.handler.GetEndpointSlices(ctx, labelValue, receiver.GetNamespace())
 		if err != nil {
-			return nil, fmt.Errorf("failed to get endpoint slices for resource %s: %w", receiver.GetName(), err)
+			return nil, nil, 0, fmt.Errorf("failed to get endpoint slices for resource %s: %w", receiver.GetName(), err)
 		}
 
-		converter := receive.DefaultEndpointConverter
-		if receiver.Spec.Router.ReplicationProtocol != nil && *receiver.Spec.Router.ReplicationProtocol == monitoringthanosiov1alpha1.ReplicationProtocolCapnProto {
-			converter = receive.CapnProtoEndpointConverter
+		converter, err := receive.NewEndpointConverter(endpointAddressOptions(receiver.Spec.Router, hashring))
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("invalid endpoint address configuration for hashring %s: %w", hashring.Name, err)
 		}
 
 		var hashingAlgo = receive.AlgorithmKetama
@@ -304,9 +671,18 @@ func (r *ThanosReceiveReconciler) buildHashringConfig(ctx context.Context, recei
 
 		hc := receive.HashringConfig{
 			Name:      hashring.Name,
-			Endpoints: receive.EndpointSliceListToEndpoints(converter, *eps, filters...),
+			Endpoints: receive.ZoneAwareEndpoints(receive.EndpointSliceListToEndpoints(converter, *eps, filters...), int(receiver.Spec.Router.ReplicationFactor)),
 			Algorithm: hashingAlgo,
 		}
+		readyReplicas := len(receive.EndpointSliceListToEndpoints(converter, *eps, readyFilters...))
+		if readyReplicas == 0 {
+			deps.add("ready endpoints for Service/" + labelValue)
+		}
+		replication = append(replication, hashringReplication{
+			name:          hashring.Name,
+			readyReplicas: int32(readyReplicas),
+			replicas:      replicas,
+		})
 
 		if hashring.TenancyConfig != nil {
 			hc.Tenants = hashring.TenancyConfig.Tenants
@@ -314,8 +690,10 @@ func (r *ThanosReceiveReconciler) buildHashringConfig(ctx context.Context, recei
 		}
 
 		fetchedReadyState[hashring.Name] = receive.HashringMeta{
-			DesiredReplicas: int(hashring.Replicas),
-			Config:          hc,
+			DesiredReplicas:  int(replicas),
+			ReadyReplicas:    readyReplicas,
+			MinReadyReplicas: int(ptr.Deref(hashring.MinReadyReplicas, 0)),
+			Config:           hc,
 		}
 	}
 
@@ -326,8 +704,21 @@ func (r *ThanosReceiveReconciler) buildHashringConfig(ctx context.Context, recei
 		out = receive.DynamicMerge(currentHashringState, fetchedReadyState, int(receiver.Spec.Router.ReplicationFactor))
 	}
 
+	// endpoints joining the hashrings within the update interval are added together once it has passed
+	delay := hashringUpdateDelay(receiver.Status.HashringConfigUpdateTime, parseDurationOr(receiver.Spec.Ingester.HashringUpdateInterval, 0), time.Now())
+	out, delay = holdBackHashringUpdate(currentHashringState, out, delay, int(receiver.Spec.Router.ReplicationFactor))
+	if delay > 0 {
+		r.logger.V(1).Info("holding back hashring endpoint additions", "resource", receiver.GetName(), "namespace", receiver.GetNamespace(), "delay", delay)
+	}
+
 	if len(out) == 0 {
-		return []byte(""), nil
+		return []byte(""), replication, delay, nil
+	}
+
+	out = receive.OrderByPriority(out, hashringPriorities(receiver.Spec.Ingester.Hashrings))
+	out, err = receive.OrderExcludedTenants(out, excludedTenants(receiver.Spec.Ingester.Hashrings))
+	if err != nil {
+		return nil, nil, 0, err
 	}
 
 	for _, hashring := range out {
@@ -337,23 +728,416 @@ func (r *ThanosReceiveReconciler) buildHashringConfig(ctx context.Context, recei
 
 	b, err := json.MarshalIndent(out, "", "    ")
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal hashring config: %w", err)
+		return nil, nil, 0, fmt.Errorf("failed to marshal hashring config: %w", err)
 	}
 
 	r.metrics.HashringHash.WithLabelValues(receiver.GetName(), receiver.GetNamespace()).Set(receive.HashAsMetricValue(b))
 	r.metrics.HashringsConfigured.WithLabelValues(receiver.GetName(), receiver.GetNamespace()).Set(float64(len(out)))
-	return b, nil
+	return b, replication, delay, nil
+}
+
+// maxEventNoteLength is the maximum length of the note of an Event accepted by the API server.
+const maxEventNoteLength = 1024
+
+// recordHashringChanges emits a Normal event on the ThanosReceive summarizing the changes from the previous
+// hashring configuration to the one applied to the routers.
+func (r *ThanosReceiveReconciler) recordHashringChanges(receiver *monitoringthanosiov1alpha1.ThanosReceive, previous receive.Hashrings, config []byte) {
+	var next receive.Hashrings
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, &next); err != nil {
+			r.logger.Error(err, "failed to unmarshal hashring config", "resource", receiver.GetName(), "namespace", receiver.GetNamespace())
+			return
+		}
+	}
+	diff := receive.DiffHashrings(previous, next)
+	if len(diff) == 0 {
+		return
+	}
+	note := "Hashring configuration changed: " + strings.Join(diff, "; ")
+	if len(note) > maxEventNoteLength {
+		note = note[:maxEventNoteLength-3] + "..."
+	}
+	r.recorder.Eventf(receiver, nil, corev1.EventTypeNormal, "HashringConfigChanged", "Reconcile", "%s", note)
+}
+
+// hashringPriorities returns the priority of each hashring that sets one, keyed by hashring name.
+func hashringPriorities(hashrings []monitoringthanosiov1alpha1.IngesterHashringSpec) map[string]int32 {
+	priorities := make(map[string]int32)
+	for _, hashring := range hashrings {
+		if hashring.TenancyConfig != nil && hashring.TenancyConfig.Priority != 0 {
+			priorities[hashring.Name] = hashring.TenancyConfig.Priority
+		}
+	}
+	return priorities
+}
+
+// excludedTenants returns the tenants excluded from each hashring, keyed by hashring name.
+func excludedTenants(hashrings []monitoringthanosiov1alpha1.IngesterHashringSpec) map[string][]string {
+	excluded := make(map[string][]string)
+	for _, hashring := range hashrings {
+		if hashring.TenancyConfig != nil && len(hashring.TenancyConfig.ExcludeTenants) > 0 {
+			excluded[hashring.Name] = hashring.TenancyConfig.ExcludeTenants
+		}
+	}
+	return excluded
+}
+
+// reportReplication sets the ReplicationDegraded condition and the replication capacity metric of each hashring
+// on the ThanosReceive resource, and emits a Warning event for each hashring that has fewer ready replicas than
+// the replication factor.
+// The condition is persisted with the next condition update.
+func (r *ThanosReceiveReconciler) reportReplication(receiver *monitoringthanosiov1alpha1.ThanosReceive, replication []hashringReplication) {
+	replicationFactor := receiver.Spec.Router.ReplicationFactor
+	// hashrings removed from the spec must not keep reporting their last capacity
+	r.metrics.ReplicationCapacityOK.DeletePartialMatch(prometheus.Labels{"resource": receiver.GetName(), "namespace": receiver.GetNamespace()})
+	for _, h := range replication {
+		capacityOK := 0.0
+		if h.readyReplicas >= replicationFactor {
+			capacityOK = 1
+		}
+		r.metrics.ReplicationCapacityOK.WithLabelValues(receiver.GetName(), receiver.GetNamespace(), h.name).Set(capacityOK)
+	}
+
+	degraded := degradedHashrings(replicationFactor, replication)
+	for _, msg := range degraded {
+		r.logger.Info("replication degraded", "resource", receiver.GetName(), "namespace", receiver.GetNamespace(), "reason", msg)
+		r.recorder.Eventf(receiver, nil, corev1.EventTypeWarning, "ReplicationDegraded", "Reconcile", "%s", msg)
+	}
+	meta.SetStatusCondition(&receiver.Status.Conditions, replicationDegradedCondition(replicationFactor, degraded))
+}
+
+// reportVolumeZones records the placement of the ingester volumes, and sets the VolumeZonesDegraded condition and
+// the volume zones metric of each hashring on the ThanosReceive resource.
+// The status is persisted with the next condition update.
+func (r *ThanosReceiveReconciler) reportVolumeZones(ctx context.Context, cluster targetCluster, receiver *monitoringthanosiov1alpha1.ThanosReceive) {
+	volumes, err := readIngesterVolumes(ctx, cluster.client, *receiver)
+	if err != nil {
+		r.logger.Error(err, "failed to read ingester volumes for status update", "resource", receiver.GetName(), "namespace", receiver.GetNamespace())
+		return
+	}
+	receiver.Status.IngesterVolumes = volumes
+
+	hashrings := make([]string, 0, len(receiver.Spec.Ingester.Hashrings))
+	r.metrics.IngesterVolumeZones.DeletePartialMatch(prometheus.Labels{"resource": receiver.GetName(), "namespace": receiver.GetNamespace()})
+	for _, hashring := range receiver.Spec.Ingester.Hashrings {
+		hashrings = append(hashrings, hashring.Name)
+		zones, _ := volumeZones(volumes[hashring.Name])
+		r.metrics.IngesterVolumeZones.WithLabelValues(receiver.GetName(), receiver.GetNamespace(), hashring.Name).Set(float64(len(zones)))
+	}
+
+	degraded := volumeZonesDegraded(receiver.Spec.Router.ReplicationFactor, hashrings, volumes)
+	for _, msg := range degraded {
+		r.recorder.Eventf(receiver, nil, corev1.EventTypeWarning, "VolumeZonesDegraded", "Reconcile", "%s", msg)
+	}
+	meta.SetStatusCondition(&receiver.Status.Conditions, volumeZonesDegradedCondition(degraded))
+}
+
+// expandVolumes expands the data volumes of the ingesters that are smaller than the storage size of their hashring,
+// and sets the VolumeResizeBlocked condition on the ThanosReceive resource. A Warning event is emitted when volumes
+// cannot be resized, for example because their storage class does not allow volume expansion.
+// The status is persisted with the next condition update.
+func (r *ThanosReceiveReconciler) expandVolumes(ctx context.Context, cluster targetCluster, receiver *monitoringthanosiov1alpha1.ThanosReceive) {
+	expanded, blocked, err := expandIngesterVolumes(ctx, cluster.client, *receiver)
+	if err != nil {
+		r.logger.Error(err, "failed to expand ingester volumes", "resource", receiver.GetName(), "namespace", receiver.GetNamespace())
+		return
+	}
+	if len(expanded) > 0 {
+		r.recorder.Eventf(receiver, nil, corev1.EventTypeNormal, "VolumesExpanded", "Reconcile", "Expanded volumes %s", strings.Join(expanded, ", "))
+	}
+
+	condition := volumeResizeBlockedCondition(blocked)
+	if condition.Status == metav1.ConditionTrue && !meta.IsStatusConditionTrue(receiver.Status.Conditions, ConditionVolumeResizeBlocked) {
+		r.recorder.Eventf(receiver, nil, corev1.EventTypeWarning, "VolumeResizeBlocked", "Reconcile", "%s", condition.Message)
+	}
+	meta.SetStatusCondition(&receiver.Status.Conditions, condition)
+}
+
+// reportUploadLag checks the upload lag of each hashring if it is due, and records it in the status, the
+// UploadLagDegraded condition and the upload lag metric of each hashring. The ingesters are scraped by Pod IP,
+// which is not reachable from the operator in a workload cluster, so the upload lag is only checked in the local
+// cluster. A Warning event is emitted when a hashring starts lagging. The status is persisted with the next
+// condition update.
+func (r *ThanosReceiveReconciler) reportUploadLag(ctx context.Context, cluster targetCluster, receiver *monitoringthanosiov1alpha1.ThanosReceive) {
+	name, ns := receiver.GetName(), receiver.GetNamespace()
+	if !ptr.Deref(receiver.Spec.Ingester.UploadLagChecks, false) || cluster.remote {
+		r.metrics.UploadLagSeconds.DeletePartialMatch(prometheus.Labels{"resource": name, "namespace": ns})
+		receiver.Status.UploadLag = nil
+		receiver.Status.UploadLagCheckTime = nil
+		meta.RemoveStatusCondition(&receiver.Status.Conditions, ConditionUploadLagDegraded)
+		return
+	}
+	now := time.Now()
+	if !uploadLagChecksDue(*receiver, now) {
+		return
+	}
+
+	lags, err := readUploadLag(ctx, cluster.client, http.DefaultClient, *receiver, manifestreceive.HTTPPort, now)
+	if lags == nil {
+		r.logger.Error(err, "failed to read the upload lag for status update", "resource", name, "namespace", ns)
+		return
+	}
+	if err != nil {
+		r.logger.V(1).Info("failed to scrape the upload lag of some ingesters", "resource", name, "namespace", ns, "error", err.Error())
+	}
+	receiver.Status.UploadLag = lags
+	receiver.Status.UploadLagCheckTime = &metav1.Time{Time: now}
+
+	r.metrics.UploadLagSeconds.DeletePartialMatch(prometheus.Labels{"resource": name, "namespace": ns})
+	hashrings := make([]string, 0, len(receiver.Spec.Ingester.Hashrings))
+	for _, hashring := range receiver.Spec.Ingester.Hashrings {
+		hashrings = append(hashrings, hashring.Name)
+		if lag, ok := lags[hashring.Name]; ok {
+			r.metrics.UploadLagSeconds.WithLabelValues(name, ns, hashring.Name).Set(lag.Seconds())
+		}
+	}
+
+	condition := uploadLagDegradedCondition(uploadLagDegraded(uploadLagThreshold(*receiver), hashrings, lags))
+	if condition.Status == metav1.ConditionTrue && !meta.IsStatusConditionTrue(receiver.Status.Conditions, ConditionUploadLagDegraded) {
+		r.recorder.Eventf(receiver, nil, corev1.EventTypeWarning, "UploadLagDegraded", "Reconcile", "%s", condition.Message)
+	}
+	meta.SetStatusCondition(&receiver.Status.Conditions, condition)
+}
+
+// reportIngesterHealth checks the health of the running ingesters if it is due, and records it in the status,
+// the EndpointsHealthy condition and the endpoint health metric. The ingesters are checked by Pod IP, which is not
+// reachable from the operator in a workload cluster, so they are only checked in the local cluster.
+// A Warning event is emitted when ingesters become unhealthy. The status is persisted with the next condition update.
+func (r *ThanosReceiveReconciler) reportIngesterHealth(ctx context.Context, cluster targetCluster, receiver *monitoringthanosiov1alpha1.ThanosReceive) {
+	name, ns := receiver.GetName(), receiver.GetNamespace()
+	labels := prometheus.Labels{"component": "receive", "resource": name, "namespace": ns}
+	if !ptr.Deref(receiver.Spec.Ingester.HealthChecks, false) || cluster.remote {
+		r.metrics.EndpointHealthy.DeletePartialMatch(labels)
+		receiver.Status.IngesterHealth = nil
+		meta.RemoveStatusCondition(&receiver.Status.Conditions, ConditionEndpointsHealthy)
+		return
+	}
+
+	targets, err := ingesterHealthTargets(ctx, cluster.client, *receiver)
+	if err != nil {
+		r.logger.Error(err, "failed to list the ingesters for health checks", "resource", name, "namespace", ns)
+		return
+	}
+	now := time.Now()
+	if !ingesterHealthChecksDue(receiver.Status.IngesterHealth, targets, now) {
+		return
+	}
+	// ingesters requiring client certificates accept the one presented by the routers
+	var certSecret string
+	if tls := receiver.Spec.GRPCTLS; tls != nil && tls.Client != nil {
+		certSecret = ptr.Deref(tls.Client.CertSecret, "")
+	}
+	cert, err := healthCheckCertificate(ctx, cluster.client, ns, certSecret)
+	if err != nil {
+		r.logger.Error(err, "failed to load the client certificate for health checks", "resource", name, "namespace", ns)
+		return
+	}
+
+	// the ingesters of all the hashrings are checked at once, so that an unresponsive hashring does not delay the others
+	var all []healthTarget
+	for _, hashring := range receiver.Spec.Ingester.Hashrings {
+		all = append(all, targets[hashring.Name]...)
+	}
+	statuses := checkEndpointHealth(ctx, all, cert, now)
+
+	r.metrics.EndpointHealthy.DeletePartialMatch(labels)
+	receiver.Status.IngesterHealth = make(map[string][]monitoringthanosiov1alpha1.EndpointHealthStatus, len(targets))
+	for _, hashring := range receiver.Spec.Ingester.Hashrings {
+		count := len(targets[hashring.Name])
+		if count == 0 {
+			continue
+		}
+		receiver.Status.IngesterHealth[hashring.Name] = statuses[:count]
+		statuses = statuses[count:]
+	}
+	var unhealthy []string
+	for _, hashring := range receiver.Spec.Ingester.Hashrings {
+		for _, status := range receiver.Status.IngesterHealth[hashring.Name] {
+			var healthy float64
+			if status.Healthy {
+				healthy = 1
+			}
+			r.metrics.EndpointHealthy.WithLabelValues("receive", name, ns, status.Name).Set(healthy)
+		}
+		unhealthy = append(unhealthy, unhealthyEndpoints(receiver.Status.IngesterHealth[hashring.Name])...)
+	}
+
+	condition := endpointHealthCondition(unhealthy)
+	if condition.Status == metav1.ConditionFalse && !meta.IsStatusConditionFalse(receiver.Status.Conditions, ConditionEndpointsHealthy) {
+		r.recorder.Eventf(receiver, nil, corev1.EventTypeWarning, "EndpointsUnhealthy", "Reconcile", "%s", condition.Message)
+	}
+	meta.SetStatusCondition(&receiver.Status.Conditions, condition)
+}
+
+// setStatus records the hashring configuration and the rollout state of the router and the ingesters
+// on the ThanosReceive resource. The status is persisted with the next condition update.
+func (r *ThanosReceiveReconciler) setStatus(ctx context.Context, cluster targetCluster, receiver *monitoringthanosiov1alpha1.ThanosReceive, hashrings *receiveHashringState) {
+	receiver.Status.ObservedGeneration = receiver.GetGeneration()
+	if hashrings != nil && hashrings.configHash != "" {
+		receiver.Status.HashringConfigHash = hashrings.configHash
+		receiver.Status.HashringConfigVersion = hashrings.configVersion
+		receiver.Status.HashringConfigUpdateTime = nil
+		if !hashrings.configUpdateTime.IsZero() {
+			receiver.Status.HashringConfigUpdateTime = &metav1.Time{Time: hashrings.configUpdateTime}
+		}
+	}
+	if hashrings != nil {
+		receiver.Status.Rollout = hashrings.rollout
+	}
+
+	ns := receiver.GetNamespace()
+	workloads := make([]workloadRollout, 0, len(receiver.Spec.Ingester.Hashrings)+1)
+
+	routerName := ReceiveRouterNameFromParent(receiver.GetName())
+	router := &appsv1.Deployment{}
+	found, err := getWorkload(ctx, cluster.client, ns, routerName, router)
+	if err != nil {
+		r.logger.Error(err, "failed to get receive router for status update", "name", routerName)
+		return
+	}
+	if !found {
+		router = nil
+	} else {
+		receiver.Status.Router = toDeploymentStatus(router)
+	}
+	receiver.Status.RouterHashringConfigHash = routerHashringConfigHash(*receiver, router, receiver.Status.RouterHashringConfigHash)
+	workloads = append(workloads, deploymentRollout("Deployment/"+routerName, router))
+
+	var groupStatus map[string]monitoringthanosiov1alpha1.DeploymentStatus
+	for _, group := range receiver.Spec.Router.Groups {
+		name := ReceiveRouterGroupNameFromParent(receiver.GetName(), group.Name)
+		deployment := &appsv1.Deployment{}
+		found, err := getWorkload(ctx, cluster.client, ns, name, deployment)
+		if err != nil {
+			r.logger.Error(err, "failed to get receive router group for status update", "name", name)
+			return
+		}
+		if !found {
+			deployment = nil
+		} else {
+			if groupStatus == nil {
+				groupStatus = make(map[string]monitoringthanosiov1alpha1.DeploymentStatus, len(receiver.Spec.Router.Groups))
+			}
+			groupStatus[group.Name] = toDeploymentStatus(deployment)
+		}
+		workloads = append(workloads, deploymentRollout("Deployment/"+name, deployment))
+	}
+	receiver.Status.RouterGroupStatus = groupStatus
+
+	hashringStatus := make(map[string]monitoringthanosiov1alpha1.StatefulSetStatus, len(receiver.Spec.Ingester.Hashrings))
+	for _, hashring := range receiver.Spec.Ingester.Hashrings {
+		name := ReceiveIngesterNameFromParent(receiver.GetName(), hashring.Name)
+		ingester := &appsv1.StatefulSet{}
+		found, err := getWorkload(ctx, cluster.client, ns, name, ingester)
+		if err != nil {
+			r.logger.Error(err, "failed to get receive ingester for status update", "name", name)
+			return
+		}
+		if !found {
+			ingester = nil
+		} else {
+			hashringStatus[hashring.Name] = monitoringthanosiov1alpha1.StatefulSetStatus{
+				Replicas:          ingester.Status.Replicas,
+				UpdatedReplicas:   ingester.Status.UpdatedReplicas,
+				AvailableReplicas: ingester.Status.AvailableReplicas,
+				ReadyReplicas:     ingester.Status.ReadyReplicas,
+				CurrentReplicas:   ingester.Status.CurrentReplicas,
+			}
+		}
+		workloads = append(workloads, statefulSetRollout("StatefulSet/"+name, ingester))
+	}
+	receiver.Status.HashringStatus = hashringStatus
+
+	for _, condition := range rolloutConditions(workloads) {
+		if condition.Type == ConditionProgressing && receiver.Status.Rollout != nil {
+			condition = pendingRolloutCondition(condition, *receiver.Status.Rollout)
+		}
+		meta.SetStatusCondition(&receiver.Status.Conditions, condition)
+	}
+}
+
+// syncWriteProbe creates or updates the write probe of the ThanosReceive resource, or deletes it if it is disabled.
+func (r *ThanosReceiveReconciler) syncWriteProbe(ctx context.Context, cluster targetCluster, receiver monitoringthanosiov1alpha1.ThanosReceive) error {
+	if receiver.Spec.WriteProbe == nil {
+		probe := &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: ReceiveWriteProbeNameFromParent(receiver.GetName()), Namespace: receiver.GetNamespace()}}
+		if errs := cluster.handler.DeleteResource(ctx, []client.Object{probe}); errs > 0 {
+			return fmt.Errorf("failed to delete the write probe")
+		}
+		return nil
+	}
+
+	opts := receiverV1Alpha1ToWriteProbeOptions(receiver)
+	if errs := cluster.handler.CreateOrUpdate(ctx, receiver.GetNamespace(), &receiver, opts.Build()); errs > 0 {
+		return fmt.Errorf("failed to create or update %d resources for the write probe", errs)
+	}
+	return nil
+}
+
+// reportWriteProbe sets the WriteProbeSucceeded condition and the write probe metrics from the latest finished
+// write probe Job, and emits a Warning event when the probe starts failing.
+// The condition is persisted with the next condition update.
+func (r *ThanosReceiveReconciler) reportWriteProbe(ctx context.Context, cluster targetCluster, receiver *monitoringthanosiov1alpha1.ThanosReceive) {
+	name, ns := receiver.GetName(), receiver.GetNamespace()
+	if receiver.Spec.WriteProbe == nil {
+		meta.RemoveStatusCondition(&receiver.Status.Conditions, ConditionWriteProbeSucceeded)
+		r.metrics.WriteProbeSuccess.DeleteLabelValues(name, ns)
+		r.metrics.WriteProbeLastSuccessTimestamp.DeleteLabelValues(name, ns)
+		return
+	}
+
+	opts := manifestreceive.WriteProbeOptions{Options: manifests.Options{Owner: name}}
+	jobs := &batchv1.JobList{}
+	if err := cluster.client.List(ctx, jobs, client.InNamespace(ns), client.MatchingLabels(opts.GetSelectorLabels())); err != nil {
+		r.logger.Error(err, "failed to list write probe jobs for status update", "resource", name, "namespace", ns)
+		return
+	}
+
+	result := latestWriteProbeResult(jobs.Items)
+	condition := result.condition()
+	if condition.Status == metav1.ConditionFalse && !meta.IsStatusConditionFalse(receiver.Status.Conditions, ConditionWriteProbeSucceeded) {
+		r.recorder.Eventf(receiver, nil, corev1.EventTypeWarning, "WriteProbeFailed", "Reconcile", "%s", condition.Message)
+	}
+	meta.SetStatusCondition(&receiver.Status.Conditions, condition)
+
+	if result.finished {
+		var success float64
+		if result.succeeded {
+			success = 1
+		}
+		r.metrics.WriteProbeSuccess.WithLabelValues(name, ns).Set(success)
+	}
+	if result.lastSuccess != nil {
+		r.metrics.WriteProbeLastSuccessTimestamp.WithLabelValues(name, ns).Set(float64(result.lastSuccess.Unix()))
+	}
 }
 
-func (r *ThanosReceiveReconciler) handleDeletionTimestamp(receiveHashring *monitoringthanosiov1alpha1.ThanosReceive) (ctrl.Result, error) {
-	if controllerutil.ContainsFinalizer(receiveHashring, receiveFinalizer) {
-		r.logger.Info("performing Finalizer Operations for ThanosReceiveHashring before delete CR")
+// handleDeletionTimestamp performs the cleanup of a ThanosReceive that is being deleted and removes its finalizer
+// once the cleanup is complete. The remaining child resources are then garbage collected with the ThanosReceive.
+func (r *ThanosReceiveReconciler) handleDeletionTimestamp(ctx context.Context, receiver *monitoringthanosiov1alpha1.ThanosReceive) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(receiver, receiveFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	r.logger.Info("performing finalizer operations for ThanosReceive", "resource", receiver.GetName(), "namespace", receiver.GetNamespace())
+	cluster, err := r.targetClusters.get(ctx, receiver.Spec.TargetCluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	done, err := r.finalize(ctx, cluster, *receiver)
+	if err != nil {
+		r.recorder.Eventf(receiver, nil, corev1.EventTypeWarning, "CleanupFailed", "Cleanup", "Failed to clean up before deletion: %v", err)
+		return ctrl.Result{}, err
+	}
+	if !done {
+		return ctrl.Result{RequeueAfter: finalizeRequeueInterval}, nil
+	}
 
-		r.recorder.Eventf(receiveHashring, nil, "Warning", "Deleting", "Cleanup",
-			fmt.Sprintf("Custom Resource %s is being deleted from the namespace %s",
-				receiveHashring.Name,
-				receiveHashring.Namespace))
+	controllerutil.RemoveFinalizer(receiver, receiveFinalizer)
+	if err := r.Update(ctx, receiver); err != nil {
+		return ctrl.Result{}, err
 	}
+	r.recorder.Eventf(receiver, nil, corev1.EventTypeNormal, "Deleting", "Cleanup",
+		"Custom Resource %s is being deleted from the namespace %s", receiver.GetName(), receiver.GetNamespace())
 	return ctrl.Result{}, nil
 }
 
@@ -386,18 +1170,137 @@ func (r *ThanosReceiveReconciler) enqueueForEndpointSlice(c client.Client) handl
 	})
 }
 
-func (r *ThanosReceiveReconciler) cleanup(ctx context.Context, resource monitoringthanosiov1alpha1.ThanosReceive, expectedIngesters []string, routerName string) int {
+// enqueueForWriteProbeJob enqueues requests for the ThanosReceive resource when a Job of its write probe changes.
+func (r *ThanosReceiveReconciler) enqueueForWriteProbeJob(c client.Client) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+		if len(obj.GetOwnerReferences()) != 1 || obj.GetOwnerReferences()[0].Kind != "CronJob" {
+			return nil
+		}
+
+		cronJob := &batchv1.CronJob{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetOwnerReferences()[0].Name}, cronJob); err != nil {
+			return nil
+		}
+
+		if len(cronJob.GetOwnerReferences()) != 1 || cronJob.GetOwnerReferences()[0].Kind != "ThanosReceive" {
+			return nil
+		}
+
+		return []reconcile.Request{
+			{
+				NamespacedName: types.NamespacedName{
+					Namespace: obj.GetNamespace(),
+					Name:      cronJob.GetOwnerReferences()[0].Name,
+				},
+			},
+		}
+	})
+}
+
+// receiveObjectStorageConfigs returns the default object storage configuration and those overriding it for a hashring.
+func receiveObjectStorageConfigs(receiver monitoringthanosiov1alpha1.ThanosReceive) []monitoringthanosiov1alpha1.ObjectStorageConfig {
+	configs := []monitoringthanosiov1alpha1.ObjectStorageConfig{receiver.Spec.Ingester.DefaultObjectStorageConfig}
+	for _, hashring := range receiver.Spec.Ingester.Hashrings {
+		if hashring.ObjectStorageConfig != nil {
+			configs = append(configs, *hashring.ObjectStorageConfig)
+		}
+	}
+	return configs
+}
+
+// objStoreVerifyOptions returns the options of the Jobs verifying the object storage configuration of each hashring.
+// Hashrings sharing a configuration and a placement share a Job.
+func (r *ThanosReceiveReconciler) objStoreVerifyOptions(ctx context.Context, cluster targetCluster, receiver monitoringthanosiov1alpha1.ThanosReceive) ([]manifests.ObjStoreVerifyOptions, error) {
+	var verifyOpts []manifests.ObjStoreVerifyOptions
+	for _, hashring := range receiver.Spec.Ingester.Hashrings {
+		opts := receiverV1Alpha1ToIngesterOptions(receiverV1Alpha1ToIngesterTransformInput{
+			CRD:         receiver,
+			Spec:        hashring,
+			FeatureGate: r.featureGate,
+		})
+		config := receiver.Spec.Ingester.DefaultObjectStorageConfig
+		if hashring.ObjectStorageConfig != nil {
+			config = *hashring.ObjectStorageConfig
+		}
+
+		hashringOpts, err := objStoreVerifyOptions(ctx, cluster, receiver.Spec.VerifyObjectStorage, opts.Options, config)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range hashringOpts {
+			if !slices.ContainsFunc(verifyOpts, func(v manifests.ObjStoreVerifyOptions) bool {
+				return v.GetGeneratedResourceName() == o.GetGeneratedResourceName()
+			}) {
+				verifyOpts = append(verifyOpts, o)
+			}
+		}
+	}
+	return verifyOpts, nil
+}
+
+// receiveReferencedSecrets returns the names of the Secrets whose contents affect the generated resources.
+func receiveReferencedSecrets(receiver monitoringthanosiov1alpha1.ThanosReceive) []string {
+	secrets := []string{receiver.Spec.Ingester.DefaultObjectStorageConfig.Name}
+	for _, hashring := range receiver.Spec.Ingester.Hashrings {
+		if hashring.ObjectStorageConfig != nil {
+			secrets = append(secrets, hashring.ObjectStorageConfig.Name)
+		}
+	}
+	if tls := receiver.Spec.Router.RemoteWriteTLS; tls != nil && tls.ClientCA != nil {
+		secrets = append(secrets, tls.ClientCA.Name)
+	}
+	if tls := receiver.Spec.GRPCTLS; tls != nil {
+		if tls.Server.ClientCA != nil {
+			secrets = append(secrets, tls.Server.ClientCA.Name)
+		}
+		if tls.Client != nil && tls.Client.CA != nil {
+			secrets = append(secrets, tls.Client.CA.Name)
+		}
+	}
+	return secrets
+}
+
+func (r *ThanosReceiveReconciler) cleanup(ctx context.Context, cluster targetCluster, resource monitoringthanosiov1alpha1.ThanosReceive, expectedIngesters []string, routerName string, applied *manifests.ObjectSet) int {
 	var errCount int
 	ns := resource.GetNamespace()
 	owner := resource.GetName()
 
-	errCount = r.pruneOrphanedResources(ctx, ns, owner, expectedIngesters)
-	errCount += r.handler.DeleteResource(ctx, getDisabledFeatureGatedResources(r.featureGate, append(expectedIngesters, routerName), ns))
+	orphan := ptr.Deref(resource.Spec.Ingester.ScaleDownStrategy, monitoringthanosiov1alpha1.ScaleDownStrategyDelete) == monitoringthanosiov1alpha1.ScaleDownStrategyOrphan
+	errCount = r.pruneOrphanedResources(ctx, cluster, ns, owner, withMetricsServices(expectedIngesters), orphan)
+	errCount += cluster.handler.DeleteResource(ctx, getDisabledFeatureGatedResources(r.featureGate, append(expectedIngesters, routerName), ns))
+	errCount += cluster.handler.DeleteResource(ctx, getDisabledMetricsServices(metricsServiceEnabled(resource.Spec.Router.MetricsService), []string{routerName}, ns))
+
+	for _, hashring := range resource.Spec.Ingester.Hashrings {
+		name := ReceiveIngesterNameFromParent(owner, hashring.Name)
+		if hashring.ServiceAccount == nil || ptr.Deref(hashring.ServiceAccount.Name, name) == name {
+			continue
+		}
+		// the hashring uses an existing ServiceAccount, so the one created by the operator is no longer needed
+		sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns}}
+		errCount += cluster.handler.DeleteResource(ctx, []client.Object{sa})
+	}
+
+	// the router uses an existing Service or hashring ConfigMap, so the ones created by the operator are no longer needed
+	if ptr.Deref(resource.Spec.Router.ExistingService, routerName) != routerName {
+		errCount += cluster.handler.DeleteResource(ctx, []client.Object{&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: routerName, Namespace: ns}}})
+	}
+	if ptr.Deref(resource.Spec.Router.ExistingHashringConfigMap, routerName) != routerName {
+		errCount += cluster.handler.DeleteResource(ctx, []client.Object{&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: routerName, Namespace: ns}}})
+	}
+	errCount += cluster.handler.DeleteResource(ctx, getUnusedIngresses(routerName, ns, resource.Spec.Router.Ingress))
+	if resource.Spec.NetworkPolicy == nil {
+		errCount += cluster.handler.DeleteResource(ctx, []client.Object{&networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: routerName, Namespace: ns}}})
+	}
+
+	if resource.Spec.Limits == nil {
+		limits := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: manifestreceive.LimitsConfigMapName(routerName), Namespace: ns}}
+		errCount += cluster.handler.DeleteResource(ctx, []client.Object{limits})
+	}
 
-	if resource.Spec.Router.Replicas < 2 {
-		listOpt := manifests.GetLabelSelectorForOwner(manifestreceive.RouterOptions{Options: manifests.Options{Owner: owner}})
-		listOpts := []client.ListOption{listOpt, client.InNamespace(ns)}
-		errCount += r.handler.NewResourcePruner().WithPodDisruptionBudget().Prune(ctx, []string{}, listOpts...)
+	// the router groups share the labels of the routers, so the budget is deleted by name
+	if deploymentReplicas(resource.Spec.Router.Replicas, resource.Status.Router) < 2 {
+		pdb := &policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Name: routerName, Namespace: ns}}
+		errCount += cluster.handler.DeleteResource(ctx, []client.Object{pdb})
 	}
 
 	for _, hashring := range resource.Spec.Ingester.Hashrings {
@@ -405,18 +1308,40 @@ func (r *ThanosReceiveReconciler) cleanup(ctx context.Context, resource monitori
 		if hashring.Replicas < 2 {
 			objs = append(objs, &policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Name: ReceiveIngesterNameFromParent(resource.GetName(), hashring.Name), Namespace: ns}})
 		}
-		errCount += r.handler.DeleteResource(ctx, objs)
+		objs = append(objs, getDisabledMetricsServices(metricsServiceEnabled(hashring.MetricsService), []string{ReceiveIngesterNameFromParent(resource.GetName(), hashring.Name)}, ns)...)
+		if resource.Spec.NetworkPolicy == nil {
+			objs = append(objs, &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: ReceiveIngesterNameFromParent(resource.GetName(), hashring.Name), Namespace: ns}})
+		}
+		errCount += cluster.handler.DeleteResource(ctx, objs)
 	}
 
+	// stale resources follow the scale down strategy of the hashrings they may belong to
+	pruner := cluster.handler.NewResourcePruner().WithGeneratedResources().WithSecret().WithGracePeriod(r.pruneGracePeriod)
+	if r.featureGate.MonitoringMixinEnabled() {
+		pruner = pruner.WithPrometheusRule()
+	}
+	if orphan {
+		pruner = pruner.WithOrphan()
+	}
+	errCount += pruner.PruneStale(ctx, ns, resource.GetUID(), applied)
+	r.pendingDeletions.record(types.NamespacedName{Namespace: ns, Name: owner}, pruner.RequeueAfter())
+
 	return errCount
 }
 
-func (r *ThanosReceiveReconciler) pruneOrphanedResources(ctx context.Context, ns, owner string, expectShards []string) int {
+// pruneOrphanedResources prunes the resources of hashrings that are no longer in the spec.
+// If orphan is set, the resources are released from the ThanosReceive instead of being deleted.
+func (r *ThanosReceiveReconciler) pruneOrphanedResources(ctx context.Context, cluster targetCluster, ns, owner string, expectShards []string, orphan bool) int {
 	listOpt := manifests.GetLabelSelectorForOwner(manifestreceive.IngesterOptions{Options: manifests.Options{Owner: owner}})
 	listOpts := []client.ListOption{listOpt, client.InNamespace(ns)}
 
-	pruner := r.handler.NewResourcePruner().WithServiceAccount().WithService().WithStatefulSet().WithPodDisruptionBudget().WithServiceMonitor()
-	return pruner.Prune(ctx, expectShards, listOpts...)
+	pruner := cluster.handler.NewResourcePruner().WithServiceAccount().WithService().WithStatefulSet().WithPodDisruptionBudget().WithServiceMonitor().WithNetworkPolicy().WithGracePeriod(r.pruneGracePeriod)
+	if orphan {
+		pruner = pruner.WithOrphan()
+	}
+	errCount := pruner.Prune(ctx, expectShards, listOpts...)
+	r.pendingDeletions.record(types.NamespacedName{Namespace: ns, Name: owner}, pruner.RequeueAfter())
+	return errCount
 }
 
 func (r *ThanosReceiveReconciler) DisableConditionUpdate() *ThanosReceiveReconciler {
@@ -431,7 +1356,9 @@ func (r *ThanosReceiveReconciler) updateCondition(ctx context.Context, receiver
 	}
 	conditions := receiver.Status.Conditions
 	meta.SetStatusCondition(&conditions, condition)
+	setReadinessConditions(&conditions, condition)
 	receiver.Status.Conditions = conditions
+	receiver.Status.ObservedGeneration = receiver.GetGeneration()
 	if condition.Type == ConditionPaused {
 		receiver.Status.Paused = ptr.To(true)
 	}
@@ -22,10 +22,12 @@ import (
 	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/prometheus-community/prom-label-proxy/injectproxy"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/client_golang/prometheus"
 	promlabels "github.com/prometheus/prometheus/model/labels"
 
 	monitoringthanosiov1alpha1 "github.com/thanos-community/thanos-operator/api/v1alpha1"
@@ -36,6 +38,7 @@ import (
 	controllermetrics "github.com/thanos-community/thanos-operator/internal/pkg/metrics"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -84,7 +87,15 @@ type ThanosRulerReconciler struct {
 	disableConditionUpdate bool
 
 	featureGate         featuregate.Config
+	scope               controllerScope
 	configReloaderImage string
+
+	dependencyBackoff *dependencyBackoff
+	workqueue         WorkqueueConfig
+	pruneGracePeriod  time.Duration
+	pendingDeletions  *pendingDeletions
+	targetClusters    *targetClusters
+	defaults          fleetDefaults
 }
 
 // NewThanosRulerReconciler returns a reconciler for ThanosRuler resources.
@@ -96,9 +107,17 @@ func NewThanosRulerReconciler(conf Config, configReloaderImage string, client cl
 		metrics:             controllermetrics.NewThanosRulerMetrics(conf.InstrumentationConfig.MetricsRegistry, conf.InstrumentationConfig.CommonMetrics),
 		recorder:            conf.InstrumentationConfig.EventRecorder,
 		featureGate:         conf.FeatureGate,
+		scope:               newControllerScope(conf),
 		configReloaderImage: configReloaderImage,
-		handler:             handlers.NewHandler(client, scheme, conf.InstrumentationConfig.Logger).SetFeatureGates(conf.FeatureGate.ToGVK()),
+		handler:             handlers.NewHandler(client, scheme, conf.InstrumentationConfig.Logger).SetFeatureGates(conf.FeatureGate.ToGVK()).WithMutators(conf.Mutators...).WithMetrics("ruler", conf.InstrumentationConfig.CommonMetrics).WithEventRecorder(conf.InstrumentationConfig.EventRecorder),
+
+		dependencyBackoff: newDependencyBackoff(),
+		workqueue:         conf.Workqueue,
+		pruneGracePeriod:  conf.PruneGracePeriod,
+		pendingDeletions:  newPendingDeletions(),
 	}
+	reconciler.targetClusters = newTargetClusters(conf, "ruler", client, reconciler.handler, scheme)
+	reconciler.defaults = fleetDefaults{client: client}
 
 	return reconciler
 }
@@ -108,7 +127,9 @@ func NewThanosRulerReconciler(conf Config, configReloaderImage string, client cl
 // +kubebuilder:rbac:groups=monitoring.thanos.io,resources=thanosrulers/finalizers,verbs=update
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=services;configmaps;serviceaccounts,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -117,11 +138,14 @@ func NewThanosRulerReconciler(conf Config, configReloaderImage string, client cl
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.17.0/pkg/reconcile
 func (r *ThanosRulerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	defer prometheus.NewTimer(r.metrics.ReconcileDurationSeconds.WithLabelValues("ruler")).ObserveDuration()
+
 	ruler := &monitoringthanosiov1alpha1.ThanosRuler{}
 	err := r.Get(ctx, req.NamespacedName, ruler)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			r.logger.Info("thanos ruler resource not found. ignoring since object may be deleted")
+			r.dependencyBackoff.reset(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		r.logger.Error(err, "failed to get ThanosRuler")
@@ -129,7 +153,12 @@ func (r *ThanosRulerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
-	if ruler.Spec.Paused != nil && *ruler.Spec.Paused {
+	if !r.scope.manages(ruler) {
+		r.logger.V(1).Info("ThanosRuler resource is managed by another operator instance, skipping")
+		return ctrl.Result{}, nil
+	}
+
+	if isPaused(ruler, ruler.Spec.Paused) {
 		r.logger.Info("reconciliation is paused for ThanosRuler resource")
 		r.metrics.Paused.WithLabelValues("ruler", ruler.GetName(), ruler.GetNamespace()).Set(1)
 		r.recorder.Eventf(ruler, nil, corev1.EventTypeNormal, "Paused", "Reconcile", "Reconciliation is paused for ThanosRuler resource")
@@ -144,7 +173,37 @@ func (r *ThanosRulerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	r.metrics.Paused.WithLabelValues("ruler", ruler.GetName(), ruler.GetNamespace()).Set(0)
 
-	err = r.syncResources(ctx, *ruler)
+	cluster, err := r.targetClusters.get(ctx, ruler.Spec.TargetCluster)
+	if err == nil {
+		err = r.defaults.apply(ctx, ruler.GetNamespace(), &ruler.Spec.CommonFields)
+	}
+	if err == nil {
+		err = r.syncResources(ctx, cluster, *ruler)
+	}
+	if isInvalidObjectStorageConfig(err) {
+		r.logger.Info("invalid object storage configuration", "resource", ruler.GetName(), "namespace", ruler.GetNamespace(), "reason", err.Error())
+		r.recorder.Eventf(ruler, nil, corev1.EventTypeWarning, "InvalidObjectStorageConfig", "Reconcile", "%v", err)
+		meta.SetStatusCondition(&ruler.Status.Conditions, objectStorageDegradedCondition(err))
+		r.updateCondition(ctx, ruler, metav1.Condition{
+			Type:    ConditionReconcileFailed,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonInvalidObjectStorageConfig,
+			Message: err.Error(),
+		})
+		// the Secret is watched, the resource is reconciled again once it is fixed
+		return ctrl.Result{}, nil
+	}
+	if isMissingDependency(err) {
+		r.logger.V(1).Info("waiting for dependencies", "resource", ruler.GetName(), "namespace", ruler.GetNamespace(), "reason", err.Error())
+		r.recorder.Eventf(ruler, nil, corev1.EventTypeWarning, "DependencyMissing", "Reconcile", "%v", err)
+		r.updateCondition(ctx, ruler, metav1.Condition{
+			Type:    ConditionDependencyMissing,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonDependencyNotFound,
+			Message: err.Error(),
+		})
+		return ctrl.Result{RequeueAfter: r.dependencyBackoff.next(req.NamespacedName)}, nil
+	}
 	if err != nil {
 		r.logger.Error(err, "failed to sync resources", "resource", ruler.GetName(), "namespace", ruler.GetNamespace())
 		r.recorder.Eventf(ruler, nil, corev1.EventTypeWarning, "SyncFailed", "Reconcile", "Failed to sync resources: %v", err)
@@ -157,6 +216,12 @@ func (r *ThanosRulerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
+	r.dependencyBackoff.reset(req.NamespacedName)
+	meta.RemoveStatusCondition(&ruler.Status.Conditions, ConditionDependencyMissing)
+	clearObjectStorageDegraded(&ruler.Status.Conditions)
+	if err := reportObjectStorageVerification(ctx, cluster.client, r.recorder, ruler, &ruler.Status.Conditions, ruler.Spec.VerifyObjectStorage); err != nil {
+		r.logger.Error(err, "failed to report object storage verification", "resource", ruler.GetName(), "namespace", ruler.GetNamespace())
+	}
 	r.updateCondition(ctx, ruler, metav1.Condition{
 		Type:    ConditionReconcileSuccess,
 		Status:  metav1.ConditionTrue,
@@ -164,13 +229,27 @@ func (r *ThanosRulerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		Message: "Reconciliation completed successfully",
 	})
 
-	return ctrl.Result{}, nil
+	// requeue to delete orphaned resources once their prune grace period expires
+	return ctrl.Result{RequeueAfter: cluster.requeueAfter(r.pendingDeletions.pop(req.NamespacedName))}, nil
 }
 
-func (r *ThanosRulerReconciler) syncResources(ctx context.Context, ruler monitoringthanosiov1alpha1.ThanosRuler) error {
+func (r *ThanosRulerReconciler) syncResources(ctx context.Context, cluster targetCluster, ruler monitoringthanosiov1alpha1.ThanosRuler) error {
 	var objs []client.Object
+	applied := manifests.NewObjectSet()
+	cluster.handler = cluster.handler.RecordApplied(applied)
+
+	deps := &dependencies{}
+	if err := deps.requireSecrets(ctx, cluster.client, ruler.GetNamespace(), ruler.Spec.ObjectStorageConfig.Name); err != nil {
+		return err
+	}
+	if err := materializeObjectStorageConfigs(ctx, cluster, &ruler, deps, &ruler.Spec.ObjectStorageConfig); err != nil {
+		return err
+	}
+	if err := validateObjectStorageConfigs(ctx, cluster.client, ruler.GetNamespace(), ruler.Spec.ObjectStorageConfig); err != nil {
+		return err
+	}
 
-	opts, expectedPromRuleConfigMaps, err := r.buildRuler(ctx, ruler)
+	opts, expectedPromRuleConfigMaps, err := r.buildRuler(ctx, cluster, ruler, deps)
 	if err != nil {
 		return err
 	}
@@ -178,30 +257,41 @@ func (r *ThanosRulerReconciler) syncResources(ctx context.Context, ruler monitor
 
 	objs = append(objs, opts.Build()...)
 
-	if errCount := r.handler.CreateOrUpdate(ctx, ruler.GetNamespace(), &ruler, objs); errCount > 0 {
+	verifyOpts, err := objStoreVerifyOptions(ctx, cluster, ruler.Spec.VerifyObjectStorage, rulerV1Alpha1ToOptions(rulerV1Alpha1TransformInput{CRD: ruler, FeatureGate: r.featureGate}).Options, ruler.Spec.ObjectStorageConfig)
+	if err != nil {
+		return err
+	}
+
+	errCount := cluster.handler.CreateOrUpdate(ctx, ruler.GetNamespace(), &ruler, objs)
+	errCount += syncObjectStorageVerification(ctx, cluster, &ruler, verifyOpts)
+	if errCount > 0 {
 		return fmt.Errorf("failed to create or update %d resources for the ruler", errCount)
 	}
 
-	cleanErrCount := r.cleanup(ctx, ruler, expectedResources, expectedPromRuleConfigMaps)
+	cleanErrCount := r.cleanup(ctx, cluster, ruler, expectedResources, expectedPromRuleConfigMaps, applied)
 	if cleanErrCount > 0 {
 		return fmt.Errorf("failed to clean up %d orphaned resources for the ruler", cleanErrCount)
 	}
 
-	return nil
+	return deps.err()
 }
 
-func (r *ThanosRulerReconciler) buildRuler(ctx context.Context, ruler monitoringthanosiov1alpha1.ThanosRuler) (manifests.Buildable, []string, error) {
-	endpoints, err := r.getQueryAPIServiceEndpoints(ctx, ruler)
+// buildRuler builds the Ruler options. It returns a *missingDependencyError, including the dependencies
+// already recorded in deps, if no QueryAPI is available.
+// Rules are read from the cluster of the ThanosRuler, while the QueryAPIs are discovered in the target cluster.
+func (r *ThanosRulerReconciler) buildRuler(ctx context.Context, cluster targetCluster, ruler monitoringthanosiov1alpha1.ThanosRuler, deps *dependencies) (manifests.Buildable, []string, error) {
+	endpoints, err := r.getQueryAPIServiceEndpoints(ctx, cluster, ruler)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	if len(endpoints) == 0 {
-		return nil, nil, fmt.Errorf("no query API services found")
+		deps.add("QueryAPI Service")
+		return nil, nil, deps.err()
 	}
 
 	// Get user-provided rule ConfigMaps.
-	userConfigMaps, err := r.getRuleConfigMaps(ctx, ruler)
+	userConfigMaps, err := r.getRuleConfigMaps(ctx, cluster, ruler)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -210,7 +300,7 @@ func (r *ThanosRulerReconciler) buildRuler(ctx context.Context, ruler monitoring
 	// Get PrometheusRule-based ConfigMaps
 	promRuleConfigMaps := ruleConfigMaps{}
 	if r.featureGate.PrometheusRuleEnabled() {
-		promRuleConfigMaps, err = r.getPrometheusRuleConfigMaps(ctx, ruler)
+		promRuleConfigMaps, err = r.getPrometheusRuleConfigMaps(ctx, cluster, ruler)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -252,16 +342,18 @@ func (r *ThanosRulerReconciler) buildRuler(ctx context.Context, ruler monitoring
 	return opts, expectedDerivedConfigMapNames, nil
 }
 
-func (r *ThanosRulerReconciler) pruneOrphanedResources(ctx context.Context, ns, owner string, expectedResources []string) int {
+func (r *ThanosRulerReconciler) pruneOrphanedResources(ctx context.Context, cluster targetCluster, ns, owner string, expectedResources []string) int {
 	listOpt := manifests.GetLabelSelectorForOwner(manifestruler.Options{Options: manifests.Options{Owner: owner}})
 	listOpts := []client.ListOption{listOpt, client.InNamespace(ns)}
 
-	pruner := r.handler.NewResourcePruner().WithServiceAccount().WithService().WithStatefulSet().WithPodDisruptionBudget().WithServiceMonitor()
-	return pruner.Prune(ctx, expectedResources, listOpts...)
+	pruner := cluster.handler.NewResourcePruner().WithServiceAccount().WithService().WithStatefulSet().WithPodDisruptionBudget().WithServiceMonitor().WithGracePeriod(r.pruneGracePeriod)
+	errCount := pruner.Prune(ctx, expectedResources, listOpts...)
+	r.pendingDeletions.record(types.NamespacedName{Namespace: ns, Name: owner}, pruner.RequeueAfter())
+	return errCount
 }
 
 // getStoreAPIServiceEndpoints returns the list of endpoints for the QueryAPI services that match the ThanosRuler queryLabelSelector.
-func (r *ThanosRulerReconciler) getQueryAPIServiceEndpoints(ctx context.Context, ruler monitoringthanosiov1alpha1.ThanosRuler) ([]manifestruler.Endpoint, error) {
+func (r *ThanosRulerReconciler) getQueryAPIServiceEndpoints(ctx context.Context, cluster targetCluster, ruler monitoringthanosiov1alpha1.ThanosRuler) ([]manifestruler.Endpoint, error) {
 	labelSelector, err := manifests.BuildLabelSelectorFrom(ruler.Spec.QueryLabelSelector, requiredQueryServiceLabels)
 	if err != nil {
 		return []manifestruler.Endpoint{}, err
@@ -270,28 +362,27 @@ func (r *ThanosRulerReconciler) getQueryAPIServiceEndpoints(ctx context.Context,
 	opts := []client.ListOption{client.MatchingLabelsSelector{Selector: labelSelector}, client.InNamespace(ruler.Namespace)}
 
 	services := &corev1.ServiceList{}
-	if err := r.List(ctx, services, opts...); err != nil {
+	if err := cluster.client.List(ctx, services, opts...); err != nil {
 		return nil, err
 	}
 
 	if len(services.Items) == 0 {
-		r.recorder.Eventf(&ruler, nil, corev1.EventTypeWarning, "NoEndpointsFound", "Discovery", "No QueryAPI services found")
 		return []manifestruler.Endpoint{}, nil
 	}
 
-	endpoints := make([]manifestruler.Endpoint, len(services.Items))
-	for i, svc := range services.Items {
+	endpoints := make([]manifestruler.Endpoint, 0, len(services.Items))
+	for _, svc := range services.Items {
 		port, ok := manifests.IsGrpcServiceWithLabels(&svc, requiredQueryServiceLabels)
 		if !ok {
 			r.logger.Info("service is not a gRPC service", "service", svc.GetName())
 			continue
 		}
 
-		endpoints[i] = manifestruler.Endpoint{
+		endpoints = append(endpoints, manifestruler.Endpoint{
 			Port:        port,
 			ServiceName: svc.GetName(),
 			Namespace:   svc.GetNamespace(),
-		}
+		})
 	}
 
 	r.metrics.EndpointsConfigured.WithLabelValues(ruler.GetName(), ruler.GetNamespace()).Set(float64(len(endpoints)))
@@ -315,7 +406,7 @@ func (rcm ruleConfigMaps) getNames() []string {
 
 // getRuleConfigMaps returns the list of ruler configmaps of rule files to set on ThanosRuler,
 // along with the names of the ConfigMaps created for cleanup purposes.
-func (r *ThanosRulerReconciler) getRuleConfigMaps(ctx context.Context, ruler monitoringthanosiov1alpha1.ThanosRuler) (ruleConfigMaps, error) {
+func (r *ThanosRulerReconciler) getRuleConfigMaps(ctx context.Context, cluster targetCluster, ruler monitoringthanosiov1alpha1.ThanosRuler) (ruleConfigMaps, error) {
 	result := ruleConfigMaps{}
 
 	if ruler.Spec.RuleConfigSelector.MatchLabels == nil {
@@ -445,12 +536,12 @@ func (r *ThanosRulerReconciler) getRuleConfigMaps(ctx context.Context, ruler mon
 	additionalLabels := map[string]string{
 		manifests.UserConfigMapSourceLabel: manifests.UserConfigMapSourceValue,
 	}
-	return r.createBucketedRuleConfigMaps(ctx, ruler, allRuleFiles, "usercfgmap", additionalLabels, result)
+	return r.createBucketedRuleConfigMaps(ctx, cluster, ruler, allRuleFiles, "usercfgmap", additionalLabels, result)
 }
 
 // getPrometheusRuleConfigMaps returns the list of ruler configmaps of rule files to set on ThanosRuler,
 // along with the names of the ConfigMaps created for cleanup purposes.
-func (r *ThanosRulerReconciler) getPrometheusRuleConfigMaps(ctx context.Context, ruler monitoringthanosiov1alpha1.ThanosRuler) (ruleConfigMaps, error) {
+func (r *ThanosRulerReconciler) getPrometheusRuleConfigMaps(ctx context.Context, cluster targetCluster, ruler monitoringthanosiov1alpha1.ThanosRuler) (ruleConfigMaps, error) {
 	result := ruleConfigMaps{}
 
 	if ruler.Spec.RuleConfigSelector.MatchLabels == nil {
@@ -549,7 +640,7 @@ func (r *ThanosRulerReconciler) getPrometheusRuleConfigMaps(ctx context.Context,
 	additionalLabels := map[string]string{
 		manifests.PromRuleDerivedConfigMapLabel: manifests.PromRuleDerivedConfigMapValue,
 	}
-	return r.createBucketedRuleConfigMaps(ctx, ruler, allRuleFiles, "promrule", additionalLabels, result)
+	return r.createBucketedRuleConfigMaps(ctx, cluster, ruler, allRuleFiles, "promrule", additionalLabels, result)
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -573,13 +664,15 @@ func (r *ThanosRulerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	}
 
 	bldr := ctrl.NewControllerManagedBy(mgr).
-		For(&monitoringthanosiov1alpha1.ThanosRuler{}).
+		For(&monitoringthanosiov1alpha1.ThanosRuler{}, builder.WithPredicates(r.scope.predicate())).
+		WithOptions(r.workqueue.controllerOptions()).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.ServiceAccount{}).
 		Owns(&corev1.Service{}).
 		Owns(&appsv1.StatefulSet{}).
 		Owns(&policyv1.PodDisruptionBudget{}).
 		Owns(&monitoringv1.ServiceMonitor{}).
+		Owns(&batchv1.Job{}).
 		Watches(
 			&corev1.Service{},
 			r.enqueueForService(),
@@ -589,6 +682,31 @@ func (r *ThanosRulerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			&corev1.ConfigMap{},
 			r.enqueueForConfigMap(),
 			builder.WithPredicates(configMapPredicate),
+		).
+		Watches(
+			&corev1.Secret{},
+			enqueueForReferencedSecret(r.Client, r.logger, func() client.ObjectList {
+				return &monitoringthanosiov1alpha1.ThanosRulerList{}
+			}, func(obj client.Object) []string {
+				return []string{obj.(*monitoringthanosiov1alpha1.ThanosRuler).Spec.ObjectStorageConfig.Name}
+			}),
+			builder.OnlyMetadata, builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Watches(
+			&corev1.ConfigMap{},
+			enqueueForReferencedConfigMap(r.Client, r.logger, func() client.ObjectList {
+				return &monitoringthanosiov1alpha1.ThanosRulerList{}
+			}, func(obj client.Object) []string {
+				return objectStorageConfigMaps(&obj.(*monitoringthanosiov1alpha1.ThanosRuler).Spec.ObjectStorageConfig)
+			}),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Watches(
+			&monitoringthanosiov1alpha1.ThanosDefaults{},
+			enqueueForDefaults(r.Client, func() client.ObjectList {
+				return &monitoringthanosiov1alpha1.ThanosRulerList{}
+			}),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
 		)
 
 	if !r.handler.IsFeatureGated(&monitoringv1.PrometheusRule{}) {
@@ -811,6 +929,7 @@ func (r *ThanosRulerReconciler) processRuleGroupsWithTenancy(
 // createBucketedRuleConfigMaps creates bucketed ConfigMaps from rule files.
 func (r *ThanosRulerReconciler) createBucketedRuleConfigMaps(
 	ctx context.Context,
+	cluster targetCluster,
 	ruler monitoringthanosiov1alpha1.ThanosRuler,
 	allRuleFiles map[string]string,
 	namePrefix string,
@@ -834,15 +953,6 @@ func (r *ThanosRulerReconciler) createBucketedRuleConfigMaps(
 				Name:      cmName,
 				Namespace: ruler.Namespace,
 				Labels:    manifests.MergeMaps(manifests.MergeMaps(ruler.Spec.RuleConfigSelector.MatchLabels, defaultRuleLabels), additionalLabels),
-				OwnerReferences: []metav1.OwnerReference{
-					{
-						APIVersion: ruler.APIVersion,
-						Kind:       ruler.Kind,
-						Name:       ruler.Name,
-						UID:        ruler.UID,
-						Controller: ptr.To(true),
-					},
-				},
 			},
 			Data: cm.Data,
 		}
@@ -863,7 +973,7 @@ func (r *ThanosRulerReconciler) createBucketedRuleConfigMaps(
 
 	r.metrics.ConfigMapsCreated.WithLabelValues(ruler.GetName(), ruler.GetNamespace()).Add(float64(len(configMaps)))
 
-	if errCount := r.handler.CreateOrUpdate(ctx, ruler.GetNamespace(), &ruler, objs); errCount > 0 {
+	if errCount := cluster.handler.CreateOrUpdate(ctx, ruler.GetNamespace(), &ruler, objs); errCount > 0 {
 		r.metrics.ConfigMapCreationFailures.WithLabelValues(ruler.GetName(), ruler.GetNamespace()).Add(float64(errCount))
 		return result, fmt.Errorf("failed to create or update %d ConfigMaps", errCount)
 	}
@@ -872,29 +982,34 @@ func (r *ThanosRulerReconciler) createBucketedRuleConfigMaps(
 	return result, nil
 }
 
-func (r *ThanosRulerReconciler) cleanup(ctx context.Context, resource monitoringthanosiov1alpha1.ThanosRuler, expectedResources []string, expectedDerivedConfigMaps []string) int {
+func (r *ThanosRulerReconciler) cleanup(ctx context.Context, cluster targetCluster, resource monitoringthanosiov1alpha1.ThanosRuler, expectedResources []string, expectedDerivedConfigMaps []string, applied *manifests.ObjectSet) int {
 	var cleanErrCount int
 	ns := resource.GetNamespace()
 	owner := resource.GetName()
 
-	cleanErrCount = r.pruneOrphanedResources(ctx, ns, owner, expectedResources)
+	cleanErrCount = r.pruneOrphanedResources(ctx, cluster, ns, owner, withMetricsServices(expectedResources))
 
-	cleanErrCount += r.handler.DeleteResource(ctx, getDisabledFeatureGatedResources(r.featureGate, []string{RulerNameFromParent(owner)}, ns))
+	cleanErrCount += cluster.handler.DeleteResource(ctx, getDisabledFeatureGatedResources(r.featureGate, []string{RulerNameFromParent(owner)}, ns))
+	cleanErrCount += cluster.handler.DeleteResource(ctx, getDisabledMetricsServices(metricsServiceEnabled(resource.Spec.MetricsService), expectedResources, ns))
 
 	if resource.Spec.Replicas < 2 {
 		listOpt := manifests.GetLabelSelectorForOwner(manifestruler.Options{Options: manifests.Options{Owner: owner}})
 		listOpts := []client.ListOption{listOpt, client.InNamespace(ns)}
-		cleanErrCount += r.handler.NewResourcePruner().WithPodDisruptionBudget().Prune(ctx, []string{}, listOpts...)
+		cleanErrCount += cluster.handler.NewResourcePruner().WithPodDisruptionBudget().Prune(ctx, []string{}, listOpts...)
 	}
 
-	cleanErrCount += r.pruneOrphanedDerivedConfigMaps(ctx, ns, expectedDerivedConfigMaps)
+	cleanErrCount += r.pruneOrphanedDerivedConfigMaps(ctx, cluster, ns, expectedDerivedConfigMaps)
+
+	pruner := cluster.handler.NewResourcePruner().WithGeneratedResources().WithSecret().WithGracePeriod(r.pruneGracePeriod)
+	cleanErrCount += pruner.PruneStale(ctx, ns, resource.GetUID(), applied)
+	r.pendingDeletions.record(types.NamespacedName{Namespace: ns, Name: owner}, pruner.RequeueAfter())
 
 	return cleanErrCount
 }
 
 // pruneOrphanedDerivedConfigMaps prunes orphaned derived ConfigMaps (both PrometheusRule and user ConfigMap sources).
-func (r *ThanosRulerReconciler) pruneOrphanedDerivedConfigMaps(ctx context.Context, ns string, expectedDerivedConfigMaps []string) int {
-	pruner := r.handler.NewResourcePruner().WithConfigMap()
+func (r *ThanosRulerReconciler) pruneOrphanedDerivedConfigMaps(ctx context.Context, cluster targetCluster, ns string, expectedDerivedConfigMaps []string) int {
+	pruner := cluster.handler.NewResourcePruner().WithConfigMap()
 
 	listOptsPromRuleDerivedConfigMaps := []client.ListOption{
 		client.InNamespace(ns),
@@ -927,7 +1042,9 @@ func (r *ThanosRulerReconciler) updateCondition(ctx context.Context, ruler *moni
 	}
 	conditions := ruler.Status.Conditions
 	meta.SetStatusCondition(&conditions, condition)
+	setReadinessConditions(&conditions, condition)
 	ruler.Status.Conditions = conditions
+	ruler.Status.ObservedGeneration = ruler.GetGeneration()
 	if condition.Type == ConditionPaused {
 		ruler.Status.Paused = ptr.To(true)
 	}
@@ -0,0 +1,258 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+
+	monitoringthanosiov1alpha1 "github.com/thanos-community/thanos-operator/api/v1alpha1"
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+	controllermetrics "github.com/thanos-community/thanos-operator/internal/pkg/metrics"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// ThanosStackReconciler reconciles a ThanosStack object
+type ThanosStackReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	logger   logr.Logger
+	metrics  *controllermetrics.CommonMetrics
+	recorder events.EventRecorder
+
+	disableConditionUpdate bool
+
+	scope     controllerScope
+	workqueue WorkqueueConfig
+}
+
+// NewThanosStackReconciler returns a reconciler for ThanosStack resources.
+func NewThanosStackReconciler(conf Config, client client.Client, scheme *runtime.Scheme) *ThanosStackReconciler {
+	return &ThanosStackReconciler{
+		Client:    client,
+		Scheme:    scheme,
+		logger:    conf.InstrumentationConfig.Logger,
+		metrics:   conf.InstrumentationConfig.CommonMetrics,
+		recorder:  conf.InstrumentationConfig.EventRecorder,
+		scope:     newControllerScope(conf),
+		workqueue: conf.Workqueue,
+	}
+}
+
+//+kubebuilder:rbac:groups=monitoring.thanos.io,resources=thanosstacks,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=monitoring.thanos.io,resources=thanosstacks/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=monitoring.thanos.io,resources=thanosstacks/finalizers,verbs=update
+//+kubebuilder:rbac:groups=monitoring.thanos.io,resources=thanosreceives;thanosqueries;thanosstores;thanoscompacts;thanosrulers,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.17.0/pkg/reconcile
+func (r *ThanosStackReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	defer prometheus.NewTimer(r.metrics.ReconcileDurationSeconds.WithLabelValues("stack")).ObserveDuration()
+
+	stack := &monitoringthanosiov1alpha1.ThanosStack{}
+	err := r.Get(ctx, req.NamespacedName, stack)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			r.logger.Info("thanos stack resource not found. ignoring since object may be deleted")
+			return ctrl.Result{}, nil
+		}
+		r.logger.Error(err, "failed to get ThanosStack")
+		r.recorder.Eventf(stack, nil, corev1.EventTypeWarning, "GetFailed", "Reconcile", "Failed to get ThanosStack resource")
+		return ctrl.Result{}, err
+	}
+
+	if !r.scope.manages(stack) {
+		r.logger.V(1).Info("ThanosStack resource is managed by another operator instance, skipping")
+		return ctrl.Result{}, nil
+	}
+
+	if isPaused(stack, stack.Spec.Paused) {
+		r.logger.Info("reconciliation is paused for ThanosStack")
+		r.metrics.Paused.WithLabelValues("stack", stack.GetName(), stack.GetNamespace()).Set(1)
+		r.recorder.Eventf(stack, nil, corev1.EventTypeNormal, "Paused", "Reconcile", "Reconciliation is paused for ThanosStack resource")
+		r.updateCondition(ctx, stack, metav1.Condition{
+			Type:    ConditionPaused,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonPaused,
+			Message: "Reconciliation is paused",
+		})
+		return ctrl.Result{}, nil
+	}
+
+	r.metrics.Paused.WithLabelValues("stack", stack.GetName(), stack.GetNamespace()).Set(0)
+
+	components, err := r.syncResources(ctx, *stack)
+	if err != nil {
+		r.logger.Error(err, "failed to sync resources", "resource", stack.GetName(), "namespace", stack.GetNamespace())
+		r.recorder.Eventf(stack, nil, corev1.EventTypeWarning, "SyncFailed", "Reconcile", "Failed to sync resources: %v", err)
+		r.updateCondition(ctx, stack, metav1.Condition{
+			Type:    ConditionReconcileFailed,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonReconcileError,
+			Message: err.Error(),
+		})
+		return ctrl.Result{}, err
+	}
+
+	stack.Status.Components = components
+	meta.SetStatusCondition(&stack.Status.Conditions, stackAvailableCondition(components))
+	r.updateCondition(ctx, stack, metav1.Condition{
+		Type:    ConditionReconcileSuccess,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonReconcileComplete,
+		Message: "Reconciliation completed successfully",
+	})
+
+	return ctrl.Result{}, nil
+}
+
+// syncResources creates or updates the resources of the enabled components of the stack and deletes the
+// resources of the disabled components. It returns the status of the resources of the enabled components.
+func (r *ThanosStackReconciler) syncResources(ctx context.Context, stack monitoringthanosiov1alpha1.ThanosStack) ([]monitoringthanosiov1alpha1.ThanosStackComponentStatus, error) {
+	var (
+		errCount   int
+		components []monitoringthanosiov1alpha1.ThanosStackComponentStatus
+	)
+	for _, component := range stackComponents(stack) {
+		logger := r.logger.WithValues("kind", component.kind, "name", component.desired.GetName(), "namespace", component.desired.GetNamespace())
+		if !component.enabled {
+			if err := r.deleteComponent(ctx, stack, component); err != nil {
+				logger.Error(err, "failed to delete disabled component")
+				errCount++
+			}
+			continue
+		}
+
+		obj, err := r.applyComponent(ctx, stack, component)
+		if err != nil {
+			logger.Error(err, "failed to create or update component")
+			errCount++
+			continue
+		}
+		components = append(components, stackComponentStatus(component.kind, obj))
+	}
+
+	if errCount > 0 {
+		return nil, fmt.Errorf("failed to create, update or delete %d component(s)", errCount)
+	}
+	return components, nil
+}
+
+// applyComponent creates or updates the resource of a component, owned by the stack.
+// The labels and annotations set on the resource by others are kept, but its spec is owned by the stack.
+func (r *ThanosStackReconciler) applyComponent(ctx context.Context, stack monitoringthanosiov1alpha1.ThanosStack, component stackComponent) (client.Object, error) {
+	desired := component.desired
+	obj := desired.DeepCopyObject().(client.Object)
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, obj, func() error {
+		obj.SetLabels(manifests.MergeMaps(obj.GetLabels(), desired.GetLabels()))
+		obj.SetAnnotations(manifests.MergeMaps(obj.GetAnnotations(), desired.GetAnnotations()))
+		if err := setStackSpec(obj, desired); err != nil {
+			return err
+		}
+		return ctrl.SetControllerReference(&stack, obj, r.Scheme)
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.logger.V(1).Info("component configured", "kind", component.kind, "name", obj.GetName(), "operation", op)
+	if r.metrics != nil {
+		r.metrics.ObjectOperationsTotal.WithLabelValues("stack", component.kind, string(op)).Inc()
+		r.metrics.AddManagedObject("stack", stack.GetName(), stack.GetNamespace(), component.kind, obj.GetName())
+	}
+	return obj, nil
+}
+
+// deleteComponent deletes the resource of a disabled component, if it is owned by the stack.
+func (r *ThanosStackReconciler) deleteComponent(ctx context.Context, stack monitoringthanosiov1alpha1.ThanosStack, component stackComponent) error {
+	obj := component.desired.DeepCopyObject().(client.Object)
+	if err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(obj, &stack) {
+		return nil
+	}
+	r.logger.Info("deleting disabled component", "kind", component.kind, "name", obj.GetName())
+	if err := r.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	if r.metrics != nil {
+		r.metrics.RemoveManagedObject("stack", stack.GetName(), stack.GetNamespace(), component.kind, obj.GetName())
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ThanosStackReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := ctrl.NewControllerManagedBy(mgr).
+		For(&monitoringthanosiov1alpha1.ThanosStack{}, builder.WithPredicates(r.scope.predicate())).
+		WithOptions(r.workqueue.controllerOptions()).
+		Owns(&monitoringthanosiov1alpha1.ThanosReceive{}).
+		Owns(&monitoringthanosiov1alpha1.ThanosStore{}).
+		Owns(&monitoringthanosiov1alpha1.ThanosCompact{}).
+		Owns(&monitoringthanosiov1alpha1.ThanosQuery{}).
+		Owns(&monitoringthanosiov1alpha1.ThanosRuler{}).
+		Complete(r)
+
+	if err != nil {
+		r.recorder.Eventf(&monitoringthanosiov1alpha1.ThanosStack{}, nil, corev1.EventTypeWarning, "SetupFailed", "Setup", "Failed to set up controller: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *ThanosStackReconciler) DisableConditionUpdate() *ThanosStackReconciler {
+	r.disableConditionUpdate = true
+	return r
+}
+
+// updateCondition updates the status conditions of the ThanosStack resource
+func (r *ThanosStackReconciler) updateCondition(ctx context.Context, stack *monitoringthanosiov1alpha1.ThanosStack, condition metav1.Condition) {
+	if r.disableConditionUpdate {
+		return
+	}
+	conditions := stack.Status.Conditions
+	meta.SetStatusCondition(&conditions, condition)
+	setReadinessConditions(&conditions, condition)
+	stack.Status.Conditions = conditions
+	stack.Status.ObservedGeneration = stack.GetGeneration()
+	if condition.Type == ConditionPaused {
+		stack.Status.Paused = ptr.To(true)
+	}
+	if err := r.Status().Update(ctx, stack); err != nil {
+		r.logger.Error(err, "failed to update status for ThanosStack", "name", stack.Name)
+	}
+}
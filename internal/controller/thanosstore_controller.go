@@ -19,9 +19,11 @@ package controller
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/client_golang/prometheus"
 
 	monitoringthanosiov1alpha1 "github.com/thanos-community/thanos-operator/api/v1alpha1"
 	"github.com/thanos-community/thanos-operator/internal/pkg/featuregate"
@@ -31,16 +33,20 @@ import (
 	controllermetrics "github.com/thanos-community/thanos-operator/internal/pkg/metrics"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/events"
 	"k8s.io/utils/ptr"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
 
 // ThanosStoreReconciler reconciles a ThanosStore object
@@ -56,6 +62,14 @@ type ThanosStoreReconciler struct {
 	disableConditionUpdate bool
 
 	featureGate featuregate.Config
+	scope       controllerScope
+
+	dependencyBackoff *dependencyBackoff
+	workqueue         WorkqueueConfig
+	pruneGracePeriod  time.Duration
+	pendingDeletions  *pendingDeletions
+	targetClusters    *targetClusters
+	defaults          fleetDefaults
 }
 
 // NewThanosStoreReconciler returns a reconciler for ThanosStore resources.
@@ -67,8 +81,16 @@ func NewThanosStoreReconciler(conf Config, client client.Client, scheme *runtime
 		metrics:     controllermetrics.NewThanosStoreMetrics(conf.InstrumentationConfig.MetricsRegistry, conf.InstrumentationConfig.CommonMetrics),
 		recorder:    conf.InstrumentationConfig.EventRecorder,
 		featureGate: conf.FeatureGate,
-		handler:     handlers.NewHandler(client, scheme, conf.InstrumentationConfig.Logger).SetFeatureGates(conf.FeatureGate.ToGVK()),
+		scope:       newControllerScope(conf),
+		handler:     handlers.NewHandler(client, scheme, conf.InstrumentationConfig.Logger).SetFeatureGates(conf.FeatureGate.ToGVK()).WithMutators(conf.Mutators...).WithMetrics("store", conf.InstrumentationConfig.CommonMetrics).WithEventRecorder(conf.InstrumentationConfig.EventRecorder),
+
+		dependencyBackoff: newDependencyBackoff(),
+		workqueue:         conf.Workqueue,
+		pruneGracePeriod:  conf.PruneGracePeriod,
+		pendingDeletions:  newPendingDeletions(),
 	}
+	reconciler.targetClusters = newTargetClusters(conf, "store", client, reconciler.handler, scheme)
+	reconciler.defaults = fleetDefaults{client: client}
 
 	return reconciler
 }
@@ -78,7 +100,9 @@ func NewThanosStoreReconciler(conf Config, client client.Client, scheme *runtime
 //+kubebuilder:rbac:groups=monitoring.thanos.io,resources=thanosstores/finalizers,verbs=update
 //+kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=services;configmaps;serviceaccounts,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -86,11 +110,14 @@ func NewThanosStoreReconciler(conf Config, client client.Client, scheme *runtime
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.17.0/pkg/reconcile
 func (r *ThanosStoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	defer prometheus.NewTimer(r.metrics.ReconcileDurationSeconds.WithLabelValues("store")).ObserveDuration()
+
 	store := &monitoringthanosiov1alpha1.ThanosStore{}
 	err := r.Get(ctx, req.NamespacedName, store)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			r.logger.Info("thanos store resource not found. ignoring since object may be deleted")
+			r.dependencyBackoff.reset(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		r.logger.Error(err, "failed to get ThanosStore")
@@ -98,7 +125,12 @@ func (r *ThanosStoreReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
-	if store.Spec.Paused != nil && *store.Spec.Paused {
+	if !r.scope.manages(store) {
+		r.logger.V(1).Info("ThanosStore resource is managed by another operator instance, skipping")
+		return ctrl.Result{}, nil
+	}
+
+	if isPaused(store, store.Spec.Paused) {
 		r.logger.Info("reconciliation is paused for ThanosStore")
 		r.metrics.Paused.WithLabelValues("store", store.GetName(), store.GetNamespace()).Set(1)
 		r.recorder.Eventf(store, nil, corev1.EventTypeNormal, "Paused", "Reconcile", "Reconciliation is paused for ThanosStore resource")
@@ -113,7 +145,37 @@ func (r *ThanosStoreReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	r.metrics.Paused.WithLabelValues("store", store.GetName(), store.GetNamespace()).Set(0)
 
-	err = r.syncResources(ctx, *store)
+	cluster, err := r.targetClusters.get(ctx, store.Spec.TargetCluster)
+	if err == nil {
+		err = r.defaults.apply(ctx, store.GetNamespace(), &store.Spec.CommonFields)
+	}
+	if err == nil {
+		err = r.syncResources(ctx, cluster, *store)
+	}
+	if isInvalidObjectStorageConfig(err) {
+		r.logger.Info("invalid object storage configuration", "resource", store.GetName(), "namespace", store.GetNamespace(), "reason", err.Error())
+		r.recorder.Eventf(store, nil, corev1.EventTypeWarning, "InvalidObjectStorageConfig", "Reconcile", "%v", err)
+		meta.SetStatusCondition(&store.Status.Conditions, objectStorageDegradedCondition(err))
+		r.updateCondition(ctx, store, metav1.Condition{
+			Type:    ConditionReconcileFailed,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonInvalidObjectStorageConfig,
+			Message: err.Error(),
+		})
+		// the Secret is watched, the resource is reconciled again once it is fixed
+		return ctrl.Result{}, nil
+	}
+	if isMissingDependency(err) {
+		r.logger.V(1).Info("waiting for dependencies", "resource", store.GetName(), "namespace", store.GetNamespace(), "reason", err.Error())
+		r.recorder.Eventf(store, nil, corev1.EventTypeWarning, "DependencyMissing", "Reconcile", "%v", err)
+		r.updateCondition(ctx, store, metav1.Condition{
+			Type:    ConditionDependencyMissing,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonDependencyNotFound,
+			Message: err.Error(),
+		})
+		return ctrl.Result{RequeueAfter: r.dependencyBackoff.next(req.NamespacedName)}, nil
+	}
 	if err != nil {
 		r.logger.Error(err, "failed to sync resources", "resource", store.GetName(), "namespace", store.GetNamespace())
 		r.recorder.Eventf(store, nil, corev1.EventTypeWarning, "SyncFailed", "Reconcile", "Failed to sync resources: %v", err)
@@ -126,6 +188,12 @@ func (r *ThanosStoreReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
+	r.dependencyBackoff.reset(req.NamespacedName)
+	meta.RemoveStatusCondition(&store.Status.Conditions, ConditionDependencyMissing)
+	clearObjectStorageDegraded(&store.Status.Conditions)
+	if err := reportObjectStorageVerification(ctx, cluster.client, r.recorder, store, &store.Status.Conditions, store.Spec.VerifyObjectStorage); err != nil {
+		r.logger.Error(err, "failed to report object storage verification", "resource", store.GetName(), "namespace", store.GetNamespace())
+	}
 	r.updateCondition(ctx, store, metav1.Condition{
 		Type:    ConditionReconcileSuccess,
 		Status:  metav1.ConditionTrue,
@@ -133,54 +201,109 @@ func (r *ThanosStoreReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		Message: "Reconciliation completed successfully",
 	})
 
-	return ctrl.Result{}, nil
+	// requeue to delete orphaned resources once their prune grace period expires
+	return ctrl.Result{RequeueAfter: cluster.requeueAfter(r.pendingDeletions.pop(req.NamespacedName))}, nil
 }
 
-func (r *ThanosStoreReconciler) syncResources(ctx context.Context, store monitoringthanosiov1alpha1.ThanosStore) error {
+func (r *ThanosStoreReconciler) syncResources(ctx context.Context, cluster targetCluster, store monitoringthanosiov1alpha1.ThanosStore) error {
 	var errCount int
-	opts := r.specToOptions(store)
+	applied := manifests.NewObjectSet()
+	cluster.handler = cluster.handler.RecordApplied(applied)
+
+	deps := &dependencies{}
+	if err := deps.requireSecrets(ctx, cluster.client, store.GetNamespace(), store.Spec.ObjectStorageConfig.Name); err != nil {
+		return err
+	}
+	if err := materializeObjectStorageConfigs(ctx, cluster, &store, deps, &store.Spec.ObjectStorageConfig); err != nil {
+		return err
+	}
+	if err := validateObjectStorageConfigs(ctx, cluster.client, store.GetNamespace(), store.Spec.ObjectStorageConfig); err != nil {
+		return err
+	}
+
+	opts, err := r.specToOptions(store)
+	if err != nil {
+		return err
+	}
 	r.metrics.ShardsConfigured.WithLabelValues(store.GetName(), store.GetNamespace()).Set(float64(len(opts)))
 
 	expectShards := make([]string, len(opts))
 	for i, opt := range opts {
 		expectShards[i] = opt.GetGeneratedResourceName()
-		errCount += r.handler.CreateOrUpdate(ctx, store.GetNamespace(), &store, opt.Build())
+		errCount += cluster.handler.CreateOrUpdate(ctx, store.GetNamespace(), &store, opt.Build())
+	}
+	if cache := storeManagedCacheToOpts(store); cache != nil {
+		errCount += cluster.handler.CreateOrUpdate(ctx, store.GetNamespace(), &store, cache.Build())
 	}
 
+	verifyOpts, err := objStoreVerifyOptions(ctx, cluster, store.Spec.VerifyObjectStorage, storeV1Alpha1ToOptions(storeV1Alpha1TransformInput{CRD: store, FeatureGate: r.featureGate}).Options, store.Spec.ObjectStorageConfig)
+	if err != nil {
+		return err
+	}
+	errCount += syncObjectStorageVerification(ctx, cluster, &store, verifyOpts)
+
 	if errCount > 0 {
 		r.metrics.ShardCreationUpdateFailures.WithLabelValues(store.GetName(), store.GetNamespace()).Add(float64(errCount))
 		return fmt.Errorf("failed to create or update %d resources for store or store shard(s)", errCount)
 	}
 
-	if cleanErrCount := r.cleanup(ctx, store, expectShards); cleanErrCount > 0 {
+	if cleanErrCount := r.cleanup(ctx, cluster, store, expectShards, applied); cleanErrCount > 0 {
 		return fmt.Errorf("failed to cleanup resources: %v", cleanErrCount)
 	}
 
-	return nil
+	return deps.err()
 }
 
-func (r *ThanosStoreReconciler) cleanup(ctx context.Context, store monitoringthanosiov1alpha1.ThanosStore, expectShards []string) int {
+func (r *ThanosStoreReconciler) cleanup(ctx context.Context, cluster targetCluster, store monitoringthanosiov1alpha1.ThanosStore, expectShards []string, applied *manifests.ObjectSet) int {
 	var cleanErrCount int
 
-	cleanErrCount = r.pruneOrphanedResources(ctx, store.GetNamespace(), store.GetName(), expectShards)
-	cleanErrCount += r.handler.DeleteResource(ctx, getDisabledFeatureGatedResources(r.featureGate, expectShards, store.GetNamespace()))
+	cleanErrCount = r.pruneOrphanedResources(ctx, cluster, store.GetNamespace(), store.GetName(), withMetricsServices(expectShards))
+	cleanErrCount += cluster.handler.DeleteResource(ctx, getDisabledFeatureGatedResources(r.featureGate, expectShards, store.GetNamespace()))
+	cleanErrCount += cluster.handler.DeleteResource(ctx, getDisabledMetricsServices(metricsServiceEnabled(store.Spec.MetricsService), expectShards, store.GetNamespace()))
+	if storeManagedCacheToOpts(store) == nil {
+		cleanErrCount += cluster.handler.DeleteResource(ctx, getUnmanagedCache(StoreNameFromParent(store.GetName(), nil), store.GetNamespace()))
+	}
 
 	if store.Spec.Replicas < 2 {
 		listOpt := manifests.GetLabelSelectorForOwner(manifestsstore.Options{Options: manifests.Options{Owner: store.GetName()}})
 		listOpts := []client.ListOption{listOpt, client.InNamespace(store.GetNamespace())}
-		cleanErrCount += r.handler.NewResourcePruner().WithPodDisruptionBudget().Prune(ctx, []string{}, listOpts...)
+		cleanErrCount += cluster.handler.NewResourcePruner().WithPodDisruptionBudget().Prune(ctx, []string{}, listOpts...)
 	}
 
+	pruner := cluster.handler.NewResourcePruner().WithGeneratedResources().WithSecret().WithGracePeriod(r.pruneGracePeriod)
+	cleanErrCount += pruner.PruneStale(ctx, store.GetNamespace(), store.GetUID(), applied)
+	r.pendingDeletions.record(client.ObjectKeyFromObject(&store), pruner.RequeueAfter())
+
 	return cleanErrCount
 }
 
-func (r *ThanosStoreReconciler) specToOptions(store monitoringthanosiov1alpha1.ThanosStore) []manifests.Buildable {
+func (r *ThanosStoreReconciler) specToOptions(store monitoringthanosiov1alpha1.ThanosStore) ([]manifests.Buildable, error) {
+	// time based sharding, return a store shard per time range
+	if store.Spec.ShardingStrategy.Type == monitoringthanosiov1alpha1.Time {
+		timeRanges, err := storeShardTimeRanges(store.Spec.ShardingStrategy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to shard the time range of the store: %w", err)
+		}
+		buildables := make([]manifests.Buildable, len(timeRanges))
+		for i, timeRange := range timeRanges {
+			storeShardOpts := storeV1Alpha1ToOptions(storeV1Alpha1TransformInput{
+				CRD:         store,
+				FeatureGate: r.featureGate,
+			})
+			storeShardOpts.Min = manifests.Duration(manifests.OptionalToString(timeRange.MinTime))
+			storeShardOpts.Max = manifests.Duration(manifests.OptionalToString(timeRange.MaxTime))
+			storeShardOpts.ShardIndex = ptr.To(int32(i))
+			buildables[i] = storeShardOpts
+		}
+		return buildables, nil
+	}
+
 	// no sharding strategy, or sharding strategy with 1 shard, return a single store
 	if store.Spec.ShardingStrategy.Shards == 0 || store.Spec.ShardingStrategy.Shards == 1 {
 		return []manifests.Buildable{storeV1Alpha1ToOptions(storeV1Alpha1TransformInput{
 			CRD:         store,
 			FeatureGate: r.featureGate,
-		})}
+		})}, nil
 	}
 
 	shardCount := int(store.Spec.ShardingStrategy.Shards)
@@ -206,26 +329,55 @@ func (r *ThanosStoreReconciler) specToOptions(store monitoringthanosiov1alpha1.T
 		storeShardOpts.ShardIndex = ptr.To(i)
 		buildables[i] = storeShardOpts
 	}
-	return buildables
+	return buildables, nil
 }
 
-func (r *ThanosStoreReconciler) pruneOrphanedResources(ctx context.Context, ns, owner string, expectShards []string) int {
+func (r *ThanosStoreReconciler) pruneOrphanedResources(ctx context.Context, cluster targetCluster, ns, owner string, expectShards []string) int {
 	listOpt := manifests.GetLabelSelectorForOwner(manifestsstore.Options{Options: manifests.Options{Owner: owner}})
 	listOpts := []client.ListOption{listOpt, client.InNamespace(ns)}
 
-	pruner := r.handler.NewResourcePruner().WithServiceAccount().WithService().WithStatefulSet().WithPodDisruptionBudget().WithServiceMonitor()
-	return pruner.Prune(ctx, expectShards, listOpts...)
+	pruner := cluster.handler.NewResourcePruner().WithServiceAccount().WithService().WithStatefulSet().WithPodDisruptionBudget().WithServiceMonitor().WithGracePeriod(r.pruneGracePeriod)
+	errCount := pruner.Prune(ctx, expectShards, listOpts...)
+	r.pendingDeletions.record(types.NamespacedName{Namespace: ns, Name: owner}, pruner.RequeueAfter())
+	return errCount
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ThanosStoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	err := ctrl.NewControllerManagedBy(mgr).
-		For(&monitoringthanosiov1alpha1.ThanosStore{}).
+		For(&monitoringthanosiov1alpha1.ThanosStore{}, builder.WithPredicates(r.scope.predicate())).
+		WithOptions(r.workqueue.controllerOptions()).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.ServiceAccount{}).
 		Owns(&corev1.Service{}).
 		Owns(&appsv1.StatefulSet{}).
 		Owns(&monitoringv1.ServiceMonitor{}).
+		Owns(&batchv1.Job{}).
+		Watches(
+			&corev1.Secret{},
+			enqueueForReferencedSecret(r.Client, r.logger, func() client.ObjectList {
+				return &monitoringthanosiov1alpha1.ThanosStoreList{}
+			}, func(obj client.Object) []string {
+				return []string{obj.(*monitoringthanosiov1alpha1.ThanosStore).Spec.ObjectStorageConfig.Name}
+			}),
+			builder.OnlyMetadata, builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Watches(
+			&corev1.ConfigMap{},
+			enqueueForReferencedConfigMap(r.Client, r.logger, func() client.ObjectList {
+				return &monitoringthanosiov1alpha1.ThanosStoreList{}
+			}, func(obj client.Object) []string {
+				return objectStorageConfigMaps(&obj.(*monitoringthanosiov1alpha1.ThanosStore).Spec.ObjectStorageConfig)
+			}),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Watches(
+			&monitoringthanosiov1alpha1.ThanosDefaults{},
+			enqueueForDefaults(r.Client, func() client.ObjectList {
+				return &monitoringthanosiov1alpha1.ThanosStoreList{}
+			}),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
 		Complete(r)
 
 	if err != nil {
@@ -248,7 +400,9 @@ func (r *ThanosStoreReconciler) updateCondition(ctx context.Context, store *moni
 	}
 	conditions := store.Status.Conditions
 	meta.SetStatusCondition(&conditions, condition)
+	setReadinessConditions(&conditions, condition)
 	store.Status.Conditions = conditions
+	store.Status.ObservedGeneration = store.GetGeneration()
 	if condition.Type == ConditionPaused {
 		store.Status.Paused = ptr.To(true)
 	}
@@ -1,21 +1,32 @@
 package controller
 
 import (
+	"cmp"
+	"fmt"
+	"slices"
+
 	"github.com/thanos-community/thanos-operator/api/v1alpha1"
 	"github.com/thanos-community/thanos-operator/internal/pkg/featuregate"
 	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
 	manifestscompact "github.com/thanos-community/thanos-operator/internal/pkg/manifests/compact"
+	manifestsmemcached "github.com/thanos-community/thanos-operator/internal/pkg/manifests/memcached"
 	manifestquery "github.com/thanos-community/thanos-operator/internal/pkg/manifests/query"
 	manifestqueryfrontend "github.com/thanos-community/thanos-operator/internal/pkg/manifests/queryfrontend"
 	manifestreceive "github.com/thanos-community/thanos-operator/internal/pkg/manifests/receive"
 	manifestruler "github.com/thanos-community/thanos-operator/internal/pkg/manifests/ruler"
 	manifestsstore "github.com/thanos-community/thanos-operator/internal/pkg/manifests/store"
+	"github.com/thanos-community/thanos-operator/internal/pkg/receive"
 
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// defaultSessionAffinityTimeoutSeconds is the Kubernetes default timeout of client IP session affinity.
+const defaultSessionAffinityTimeoutSeconds = 10800
+
 // QueryV1Alpha1TransformInput holds input for queryV1Alpha1ToOptions.
 type queryV1Alpha1TransformInput struct {
 	CRD         v1alpha1.ThanosQuery
@@ -61,7 +72,9 @@ type compactV1Alpha1TransformInput struct {
 }
 
 func queryV1Alpha1ToOptions(in queryV1Alpha1TransformInput) manifestquery.Options {
-	opts := commonToOpts(&in.CRD, in.CRD.Spec.Replicas, in.CRD.Spec.CommonFields, nil, in.FeatureGate, in.CRD.Spec.Additional)
+	opts := commonToOpts(&in.CRD, deploymentReplicas(in.CRD.Spec.Replicas, in.CRD.Status.Querier), in.CRD.Spec.CommonFields, nil, in.FeatureGate, in.CRD.Spec.Additional)
+	opts.ExternalReplicas = in.CRD.Spec.Replicas == nil
+	opts.Deployment = deploymentToOpts(in.CRD.Spec.DeploymentFields)
 	var webOptions manifestquery.WebOptions
 	if in.CRD.Spec.WebConfig != nil {
 		webOptions = manifestquery.WebOptions{
@@ -79,15 +92,38 @@ func queryV1Alpha1ToOptions(in queryV1Alpha1TransformInput) manifestquery.Option
 			Series:   in.CRD.Spec.TelemetryQuantiles.Series,
 		}
 	}
+	var ingress *manifests.IngressOptions
+	if in.CRD.Spec.QueryFrontend == nil {
+		// the query UI is served by the Query Frontend when there is one
+		ingress = ingressConfigToOpts(in.CRD.Spec.Ingress)
+	}
+	remoteEndpoints, remoteTLS := federationToOpts(in.CRD.Spec.Federation)
+	clientTLS := grpcClientTLSConfigToOpts(in.CRD.Spec.GRPCClientTLS)
+	if clientTLS == nil {
+		clientTLS = remoteTLS
+	}
 	return manifestquery.Options{
 		Options:            opts,
 		ReplicaLabels:      in.CRD.Spec.ReplicaLabels,
-		Timeout:            "15m",
-		LookbackDelta:      "5m",
-		MaxConcurrent:      20,
+		Timeout:            string(ptr.Deref(in.CRD.Spec.Timeout, "15m")),
+		LookbackDelta:      string(ptr.Deref(in.CRD.Spec.LookbackDelta, "5m")),
+		MaxConcurrent:      int(ptr.Deref(in.CRD.Spec.MaxConcurrent, 20)),
+		AutoDownsampling:   ptr.Deref(in.CRD.Spec.AutoDownsampling, true),
+		PartialResponse:    in.CRD.Spec.PartialResponse,
+		DeduplicationFunc:  ptr.Deref(in.CRD.Spec.DeduplicationFunc, ""),
 		WebOptions:         webOptions,
 		TelemetryQuantiles: telemetryQuantiles,
 		GRPCProxyStrategy:  in.CRD.Spec.GRPCProxyStrategy,
+		ArgsFile:           ptr.Deref(in.CRD.Spec.ArgsFile, false),
+		GRPCServerTLS:      tlsConfigToOpts(in.CRD.Spec.GRPCServerTLS),
+		GRPCClientTLS:      clientTLS,
+		ExternalEndpoints:  in.CRD.Spec.ExternalEndpoints,
+		Ingress:            ingress,
+
+		RequestLogging:      requestLoggingConfigToOpts(in.CRD.Spec.RequestLogging),
+		ActiveQueryTracking: ptr.Deref(in.CRD.Spec.ActiveQueryTracking, false),
+		RemoteEndpoints:     remoteEndpoints,
+		EndpointScheme:      manifestquery.EndpointScheme(ptr.Deref(in.CRD.Spec.EndpointDiscoveryScheme, v1alpha1.EndpointDiscoverySchemeDNS)),
 	}
 }
 
@@ -104,6 +140,12 @@ func QueryNameFromParent(resourceName string) string {
 func queryV1Alpha1ToQueryFrontEndOptions(in queryV1Alpha1ToQueryFrontEndTransformInput) manifestqueryfrontend.Options {
 	frontend := in.CRD.Spec.QueryFrontend
 	opts := commonToOpts(&in.CRD, frontend.Replicas, frontend.CommonFields, nil, in.FeatureGate, frontend.Additional)
+	opts.Deployment = deploymentToOpts(frontend.DeploymentFields)
+
+	var sessionAffinityTimeout int32
+	if frontend.SessionAffinity != nil {
+		sessionAffinityTimeout = ptr.Deref(frontend.SessionAffinity.TimeoutSeconds, defaultSessionAffinityTimeoutSeconds)
+	}
 
 	return manifestqueryfrontend.Options{
 		Options:                opts,
@@ -111,12 +153,85 @@ func queryV1Alpha1ToQueryFrontEndOptions(in queryV1Alpha1ToQueryFrontEndTransfor
 		QueryPort:              manifestquery.HTTPPort,
 		LogQueriesLongerThan:   manifests.Duration(manifests.OptionalToString(frontend.LogQueriesLongerThan)),
 		CompressResponses:      frontend.CompressResponses,
-		ResponseCacheConfig:    toManifestCacheConfig(frontend.QueryRangeResponseCacheConfig),
+		ResponseCacheConfig:    toManifestCacheConfig(frontend.QueryRangeResponseCacheConfig, managedCacheClientConfig(queryFrontendManagedCacheToOpts(in.CRD))),
 		RangeSplitInterval:     manifests.Duration(manifests.OptionalToString(frontend.QueryRangeSplitInterval)),
 		LabelsSplitInterval:    manifests.Duration(manifests.OptionalToString(frontend.LabelsSplitInterval)),
 		RangeMaxRetries:        frontend.QueryRangeMaxRetries,
 		LabelsMaxRetries:       frontend.LabelsMaxRetries,
 		LabelsDefaultTimeRange: manifests.Duration(manifests.OptionalToString(frontend.LabelsDefaultTimeRange)),
+
+		RangeMaxQueryParallelism:  ptr.Deref(frontend.QueryRangeMaxQueryParallelism, 0),
+		LabelsMaxQueryParallelism: ptr.Deref(frontend.LabelsMaxQueryParallelism, 0),
+		RangeMaxQueryLength:       manifests.Duration(manifests.OptionalToString(frontend.QueryRangeMaxQueryLength)),
+		DownstreamConfig:          queryFrontendDownstreamConfigToOpts(frontend.DownstreamConfig),
+
+		SessionAffinityTimeoutSeconds: sessionAffinityTimeout,
+		Service:                       serviceConfigToOpts(frontend.Service),
+		Ingress:                       ingressConfigToOpts(in.CRD.Spec.Ingress),
+	}
+}
+
+func queryFrontendDownstreamConfigToOpts(in *v1alpha1.QueryFrontendDownstreamConfig) *manifestqueryfrontend.DownstreamTripperConfig {
+	if in == nil {
+		return nil
+	}
+	return &manifestqueryfrontend.DownstreamTripperConfig{
+		ResponseHeaderTimeout: manifests.Duration(manifests.OptionalToString(in.ResponseHeaderTimeout)),
+		IdleConnTimeout:       manifests.Duration(manifests.OptionalToString(in.IdleConnectionTimeout)),
+		MaxConnsPerHost:       in.MaxConnectionsPerHost,
+		MaxIdleConnsPerHost:   in.MaxIdleConnectionsPerHost,
+	}
+}
+
+// federationToOpts returns the remote endpoints of the federation, and the TLS configuration of the connections
+// to the endpoints read from the TLS Secret of the remotes, or nil if the remotes are dialed without TLS.
+func federationToOpts(in *v1alpha1.QueryFederationSpec) ([]manifestquery.RemoteEndpoint, *manifests.GRPCClientTLSConfig) {
+	if in == nil {
+		return nil, nil
+	}
+	var tls *manifests.GRPCClientTLSConfig
+	endpoints := make([]manifestquery.RemoteEndpoint, 0, len(in.Remotes))
+	for _, remote := range in.Remotes {
+		etype := manifests.RegularLabel
+		switch {
+		case remote.Group && remote.Strict:
+			etype = manifests.GroupStrictLabel
+		case remote.Group:
+			etype = manifests.GroupLabel
+		case remote.Strict:
+			etype = manifests.StrictLabel
+		}
+		endpoints = append(endpoints, manifestquery.RemoteEndpoint{Address: remote.Address, Type: etype})
+		// the remotes share the same Secret, as Thanos uses a single TLS configuration for all endpoints
+		if remote.TLSSecret != nil {
+			tls = manifests.GRPCClientTLSFromSecret(*remote.TLSSecret)
+		}
+	}
+	return endpoints, tls
+}
+
+func requestLoggingConfigToOpts(in *v1alpha1.RequestLoggingConfig) *manifests.RequestLoggingOptions {
+	if in == nil {
+		return nil
+	}
+	return &manifests.RequestLoggingOptions{
+		HTTP: requestLoggingOptionsToOpts(in.HTTP),
+		GRPC: requestLoggingOptionsToOpts(in.GRPC),
+	}
+}
+
+func requestLoggingOptionsToOpts(in *v1alpha1.RequestLoggingOptions) *manifests.RequestLogging {
+	if in == nil {
+		return nil
+	}
+	level := in.Level
+	if level == "" {
+		level = "INFO"
+	}
+	return &manifests.RequestLogging{
+		Level:    level,
+		LogStart: in.LogStart,
+		LogEnd:   ptr.Deref(in.LogEnd, true),
 	}
 }
 
@@ -134,6 +249,7 @@ func rulerV1Alpha1ToOptions(in rulerV1Alpha1TransformInput) manifestruler.Option
 	return manifestruler.Options{
 		Options:         opts,
 		ObjStoreSecret:  in.CRD.Spec.ObjectStorageConfig.ToSecretKeySelector(),
+		ObjStoreConfig:  toManifestObjStoreConfig(in.CRD.Spec.ObjectStorageConfig),
 		Retention:       manifests.Duration(in.CRD.Spec.Retention),
 		AlertmanagerURL: in.CRD.Spec.AlertmanagerURL,
 		ExternalLabels:  in.CRD.Spec.ExternalLabels,
@@ -159,9 +275,13 @@ func RulerNameFromParent(resourceName string) string {
 func receiverV1Alpha1ToIngesterOptions(in receiverV1Alpha1ToIngesterTransformInput) manifestreceive.IngesterOptions {
 	common := in.Spec.CommonFields
 	additional := in.CRD.Spec.Ingester.Additional
-	secret := in.CRD.Spec.Ingester.DefaultObjectStorageConfig.ToSecretKeySelector()
+	// the arguments of the hashring come last, so that they override those shared by all hashrings
+	additional.Args = slices.Concat(additional.Args, in.Spec.AdditionalArgs)
+	additional.Volumes = slices.Concat(additional.Volumes, in.Spec.AdditionalVolumes)
+	additional.VolumeMounts = slices.Concat(additional.VolumeMounts, in.Spec.AdditionalVolumeMounts)
+	objStoreConfig := in.CRD.Spec.Ingester.DefaultObjectStorageConfig
 	if in.Spec.ObjectStorageConfig != nil {
-		secret = in.Spec.ObjectStorageConfig.ToSecretKeySelector()
+		objStoreConfig = *in.Spec.ObjectStorageConfig
 	}
 
 	if in.CRD.Spec.TerminationGracePeriodSeconds == nil {
@@ -169,34 +289,83 @@ func receiverV1Alpha1ToIngesterOptions(in receiverV1Alpha1ToIngesterTransformInp
 		// the data to the object storage before it gets forcefully terminated.
 		// This is especially important for large ingesters with a lot of data to flush.
 		// err on side of caution in absence of user input, since a too short termination grace period will lead to problems.
+		// the drain period is added on top, since it elapses before the ingester starts flushing.
 		var defaultIngestMaxGracePeriod int64 = 900
+		defaultIngestMaxGracePeriod += ptr.Deref(in.CRD.Spec.Ingester.ShutdownDrainSeconds, 0)
 		in.CRD.Spec.TerminationGracePeriodSeconds = &defaultIngestMaxGracePeriod
 	}
 
 	opts := commonToOpts(&in.CRD, in.Spec.Replicas, common, &in.CRD.Spec.StatefulSetFields, in.FeatureGate, additional)
+	if in.Spec.UpdateStrategy != nil {
+		opts.StatefulSet.UpdateStrategy = in.Spec.UpdateStrategy
+	}
+	if policy := in.Spec.PersistentVolumeClaimRetentionPolicy; policy != nil {
+		opts.StatefulSet.PVCRetentionPolicy = manifests.PVCRetentionPolicy{
+			OnScale:  string(policy.WhenScaled),
+			OnDelete: string(policy.WhenDeleted),
+		}
+	}
+	opts.StatefulSet.UpdateStrategy = ingesterUpdateStrategy(
+		opts.StatefulSet.UpdateStrategy,
+		ingesterMaxUnavailable(in.CRD.Spec.Router.ReplicationFactor, in.Spec.Replicas),
+	)
 	ingestOpts := manifestreceive.IngesterOptions{
 		Options:        opts,
-		ObjStoreSecret: secret,
+		ObjStoreSecret: objStoreConfig.ToSecretKeySelector(),
+		ObjStoreConfig: toManifestObjStoreConfig(objStoreConfig),
 		TSDBOpts: manifestreceive.TSDBOpts{
-			Retention: string(in.Spec.TSDBConfig.Retention),
+			Retention:            string(in.Spec.TSDBConfig.Retention),
+			OutOfOrderTimeWindow: manifests.Duration(manifests.OptionalToString(in.Spec.TSDBConfig.OutOfOrderTimeWindow)),
+			WALCompression:       in.Spec.TSDBConfig.WALCompression,
+			MinBlockDuration:     manifests.Duration(manifests.OptionalToString(in.Spec.TSDBConfig.MinBlockDuration)),
+			MaxBlockDuration:     manifests.Duration(manifests.OptionalToString(in.Spec.TSDBConfig.MaxBlockDuration)),
 		},
-		AsyncForwardWorkerCount:  manifests.OptionalToString(in.Spec.AsyncForwardWorkerCount),
-		TooFarInFutureTimeWindow: manifests.Duration(manifests.OptionalToString(in.Spec.TooFarInFutureTimeWindow)),
+		AsyncForwardWorkerCount: manifests.OptionalToString(in.Spec.AsyncForwardWorkerCount),
+		// the window of the TSDB configuration takes precedence over the field of the hashring
+		TooFarInFutureTimeWindow: manifests.Duration(manifests.OptionalToString(cmp.Or(in.Spec.TSDBConfig.TooFarInFutureTimeWindow, in.Spec.TooFarInFutureTimeWindow))),
 		StorageConfig: manifests.StorageConfig{
 			StorageSize:      in.Spec.StorageConfiguration.Size.ToResourceQuantity(),
 			StorageClassName: in.Spec.StorageConfiguration.StorageClass,
 		},
-		ExternalLabels: in.Spec.ExternalLabels,
+		ExternalLabels:       in.Spec.ExternalLabels,
+		ShutdownDrainSeconds: ptr.Deref(in.CRD.Spec.Ingester.ShutdownDrainSeconds, 0),
+		SpreadAcrossZones:    ptr.Deref(in.CRD.Spec.Ingester.SpreadAcrossZones, false),
+	}
+
+	// derive the budget from the replication factor unless it is set explicitly
+	if pdb := common.PodDisruptionBudgetConfig; ingestOpts.PodDisruptionConfig != nil && pdb.MinAvailable == nil && pdb.MaxUnavailable == nil {
+		ingestOpts.PodDisruptionConfig.MaxUnavailable = ptr.To(
+			ingesterMaxUnavailable(in.CRD.Spec.Router.ReplicationFactor, in.Spec.Replicas),
+		)
 	}
 
 	if in.CRD.Spec.Router.ReplicationProtocol != nil {
 		ingestOpts.ReplicationProtocol = string(*in.CRD.Spec.Router.ReplicationProtocol)
 	}
+	// the ingesters serve the ports and protocol of the addresses the routers forward to
+	address := endpointAddressOptions(in.CRD.Spec.Router, in.Spec)
+	ingestOpts.GRPCPort = address.GRPCPort
+	ingestOpts.CapnProtoPort = address.CapnProtoPort
+	if address.CapnProto {
+		ingestOpts.ReplicationProtocol = string(v1alpha1.ReplicationProtocolCapnProto)
+	}
 
 	if in.Spec.GRPCCompression != nil {
 		ingestOpts.GRPCCompression = string(*in.Spec.GRPCCompression)
 	}
 
+	if sa := in.Spec.ServiceAccount; sa != nil {
+		ingestOpts.ServiceAccountName = ptr.Deref(sa.Name, "")
+		ingestOpts.ServiceAccountAnnotations = manifests.MergeMaps(ingestOpts.ServiceAccountAnnotations, sa.Annotations)
+	}
+
+	ingestOpts.UploadConcurrency = ptr.Deref(in.Spec.UploadConcurrency, 0)
+
+	if tls := in.CRD.Spec.GRPCTLS; tls != nil {
+		ingestOpts.GRPCTLS = tlsConfigToOpts(&tls.Server)
+	}
+	ingestOpts.NetworkPolicy = receiveNetworkPolicyToOpts(in.CRD.Spec.NetworkPolicy)
+
 	if in.Spec.TenancyConfig != nil {
 		ingestOpts.TenancyOpts = manifestreceive.TenancyOpts{
 			TenantHeader:           in.Spec.TenancyConfig.TenantHeader,
@@ -205,6 +374,9 @@ func receiverV1Alpha1ToIngesterOptions(in receiverV1Alpha1ToIngesterTransformInp
 			SplitTenantLabelName:   manifests.OptionalToString(in.Spec.TenancyConfig.SplitTenantLabelName),
 			TenantLabelName:        in.Spec.TenancyConfig.TenantLabelName,
 		}
+	} else if tenancy := in.CRD.Spec.Router.Tenancy; tenancy != nil {
+		// ingesters of hashrings without a tenancy configuration announce tenants like the routers
+		ingestOpts.TenancyOpts = routerTenancyToOpts(tenancy)
 	}
 
 	if in.Spec.StoreLimitsOptions != nil {
@@ -219,12 +391,16 @@ func receiverV1Alpha1ToIngesterOptions(in receiverV1Alpha1ToIngesterTransformInp
 
 func receiverV1Alpha1ToRouterOptions(in receiverV1Alpha1ToRouterTransformInput) manifestreceive.RouterOptions {
 	router := in.CRD.Spec.Router
-	opts := commonToOpts(&in.CRD, router.Replicas, router.CommonFields, &in.CRD.Spec.StatefulSetFields, in.FeatureGate, router.Additional)
+	opts := commonToOpts(&in.CRD, deploymentReplicas(router.Replicas, in.CRD.Status.Router), router.CommonFields, &in.CRD.Spec.StatefulSetFields, in.FeatureGate, router.Additional)
+	opts.ExternalReplicas = router.Replicas == nil
+	opts.Deployment = deploymentToOpts(router.DeploymentFields)
 
 	ropts := manifestreceive.RouterOptions{
-		Options:           opts,
-		ReplicationFactor: router.ReplicationFactor,
-		ExternalLabels:    router.ExternalLabels,
+		Options:                 opts,
+		ReplicationFactor:       router.ReplicationFactor,
+		ExternalLabels:          router.ExternalLabels,
+		AsyncForwardWorkerCount: ptr.Deref(router.AsyncForwardWorkerCount, 0),
+		ReplicationMaxRetries:   ptr.Deref(router.ReplicationMaxRetries, 1),
 	}
 
 	if in.FeatureGate.KubeResourceSyncEnabled() {
@@ -238,9 +414,163 @@ func receiverV1Alpha1ToRouterOptions(in receiverV1Alpha1ToRouterTransformInput)
 		ropts.ReplicationProtocol = string(*router.ReplicationProtocol)
 	}
 
+	ropts.RemoteWriteTLS = tlsConfigToOpts(router.RemoteWriteTLS)
+	if tls := in.CRD.Spec.GRPCTLS; tls != nil {
+		// the ingesters only serve TLS, so the router verifies them against the system roots by default
+		ropts.GRPCClientTLS = ptr.To(ptr.Deref(grpcClientTLSConfigToOpts(tls.Client), manifests.GRPCClientTLSConfig{}))
+	}
+
+	if router.ServiceTraffic != nil {
+		ropts.ServiceTraffic = &manifestreceive.ServiceTrafficOptions{
+			TopologyMode:          string(ptr.Deref(router.ServiceTraffic.TopologyMode, "")),
+			InternalTrafficPolicy: router.ServiceTraffic.InternalTrafficPolicy,
+		}
+	}
+
+	ropts.Limits = receiveLimitsToOpts(in.CRD.Spec.Limits)
+	ropts.Service = serviceConfigToOpts(router.Service)
+	ropts.ExistingServiceName = ptr.Deref(router.ExistingService, "")
+	ropts.ExistingHashringConfigMapName = ptr.Deref(router.ExistingHashringConfigMap, "")
+	ropts.Ingress = ingressConfigToOpts(router.Ingress)
+	ropts.NetworkPolicy = receiveNetworkPolicyToOpts(in.CRD.Spec.NetworkPolicy)
+	ropts.RelabelConfigs = relabelConfigsToOpts(router.RelabelConfigs)
+	ropts.RolloutOnHashringChange = ptr.Deref(router.HashringConfigReload, v1alpha1.HashringConfigReloadWatch) == v1alpha1.HashringConfigReloadRollout
+	if router.Tenancy != nil {
+		ropts.Tenancy = routerTenancyToOpts(router.Tenancy)
+	}
+
 	return ropts
 }
 
+// receiverV1Alpha1ToRouterGroupOptions returns the options of a router group, which are the options of the routers
+// of the ThanosReceive with the replicas, tenancy and Service of the group.
+func receiverV1Alpha1ToRouterGroupOptions(router manifestreceive.RouterOptions, routerSpec v1alpha1.RouterSpec, group v1alpha1.RouterGroupSpec) manifestreceive.RouterOptions {
+	opts := router
+	opts.Group = group.Name
+	opts.Replicas = group.Replicas
+	opts.ExternalReplicas = false
+	opts.PodDisruptionConfig = podDisruptionBudgetConfigToOpts(group.Replicas, routerSpec.PodDisruptionBudgetConfig)
+	opts.Service = serviceConfigToOpts(group.Service)
+	opts.ExistingServiceName = ""
+	opts.Ingress = nil
+	if group.Tenancy != nil {
+		tenancy := routerTenancyToOpts(group.Tenancy)
+		opts.Tenancy.TenantHeader = cmp.Or(tenancy.TenantHeader, router.Tenancy.TenantHeader)
+		opts.Tenancy.DefaultTenantID = cmp.Or(tenancy.DefaultTenantID, router.Tenancy.DefaultTenantID)
+		opts.Tenancy.TenantLabelName = cmp.Or(tenancy.TenantLabelName, router.Tenancy.TenantLabelName)
+	}
+	return opts
+}
+
+func routerTenancyToOpts(in *v1alpha1.RouterTenancyConfig) manifestreceive.TenancyOpts {
+	return manifestreceive.TenancyOpts{
+		TenantHeader:    ptr.Deref(in.TenantHeader, ""),
+		DefaultTenantID: ptr.Deref(in.DefaultTenantID, ""),
+		TenantLabelName: ptr.Deref(in.TenantLabelName, ""),
+	}
+}
+
+func relabelConfigsToOpts(in []v1alpha1.RelabelConfig) manifestreceive.RelabelConfigsOptions {
+	if len(in) == 0 {
+		return nil
+	}
+	opts := make(manifestreceive.RelabelConfigsOptions, 0, len(in))
+	for _, r := range in {
+		opts = append(opts, manifestreceive.RelabelConfigOptions{
+			SourceLabels: r.SourceLabels,
+			Separator:    r.Separator,
+			TargetLabel:  ptr.Deref(r.TargetLabel, ""),
+			Regex:        r.Regex,
+			Modulus:      ptr.Deref(r.Modulus, 0),
+			Replacement:  r.Replacement,
+			Action:       string(r.Action),
+		})
+	}
+	return opts
+}
+
+func receiveNetworkPolicyToOpts(in *v1alpha1.ReceiveNetworkPolicySpec) *manifestreceive.NetworkPolicyOptions {
+	if in == nil {
+		return nil
+	}
+	return &manifestreceive.NetworkPolicyOptions{
+		RemoteWriteSources: in.RemoteWriteSources,
+		ObjectStorage:      in.ObjectStorage,
+	}
+}
+
+func serviceConfigToOpts(in *v1alpha1.ServiceConfig) *manifests.ServiceOptions {
+	if in == nil {
+		return nil
+	}
+	opts := &manifests.ServiceOptions{
+		Type:                     in.Type,
+		Annotations:              in.Annotations,
+		LoadBalancerSourceRanges: in.LoadBalancerSourceRanges,
+		ExternalTrafficPolicy:    ptr.Deref(in.ExternalTrafficPolicy, ""),
+		Hostname:                 ptr.Deref(in.Hostname, ""),
+	}
+	for _, p := range in.Ports {
+		opts.Ports = append(opts.Ports, manifests.ServicePortOptions{Name: p.Name, Port: p.Port, NodePort: ptr.Deref(p.NodePort, 0)})
+	}
+	return opts
+}
+
+func ingressConfigToOpts(in *v1alpha1.IngressConfig) *manifests.IngressOptions {
+	if in == nil {
+		return nil
+	}
+	opts := &manifests.IngressOptions{
+		HTTPRoute:   in.Type == v1alpha1.IngressTypeHTTPRoute,
+		Hosts:       in.Hosts,
+		Annotations: in.Annotations,
+		ClassName:   ptr.Deref(in.ClassName, ""),
+		TLSSecret:   ptr.Deref(in.TLSSecret, ""),
+	}
+	for _, ref := range in.ParentRefs {
+		opts.ParentRefs = append(opts.ParentRefs, manifests.GatewayReference{
+			Name:        ref.Name,
+			Namespace:   ptr.Deref(ref.Namespace, ""),
+			SectionName: ptr.Deref(ref.SectionName, ""),
+		})
+	}
+	return opts
+}
+
+func receiveLimitsToOpts(in *v1alpha1.ReceiveLimitsSpec) *manifestreceive.LimitsOptions {
+	if in == nil {
+		return nil
+	}
+
+	opts := &manifestreceive.LimitsOptions{}
+	if in.Global != nil {
+		opts.Global = manifestreceive.GlobalLimitsOptions{
+			MaxConcurrency:           in.Global.MaxConcurrency,
+			MetaMonitoringURL:        ptr.Deref(in.Global.MetaMonitoringURL, ""),
+			MetaMonitoringLimitQuery: ptr.Deref(in.Global.MetaMonitoringLimitQuery, ""),
+		}
+	}
+	if in.Default != nil {
+		opts.Default = ptr.To(writeLimitsToOpts(*in.Default))
+	}
+	if len(in.Tenants) > 0 {
+		opts.Tenants = make(map[string]manifestreceive.WriteLimitsOptions, len(in.Tenants))
+		for tenant, limits := range in.Tenants {
+			opts.Tenants[tenant] = writeLimitsToOpts(limits)
+		}
+	}
+	return opts
+}
+
+func writeLimitsToOpts(in v1alpha1.WriteLimits) manifestreceive.WriteLimitsOptions {
+	return manifestreceive.WriteLimitsOptions{
+		SizeBytesLimit:  in.SizeBytesLimit,
+		SeriesLimit:     in.SeriesLimit,
+		SamplesLimit:    in.SamplesLimit,
+		HeadSeriesLimit: in.HeadSeriesLimit,
+	}
+}
+
 // ReceiveIngesterNameFromParent returns the name of the Thanos Receive Ingester component.
 func ReceiveIngesterNameFromParent(resourceName, hashringName string) string {
 	opts := manifestreceive.IngesterOptions{Options: manifests.Options{Owner: resourceName}, HashringName: hashringName}
@@ -259,6 +589,51 @@ func ReceiveRouterNameFromParent(resourceName string) string {
 	return opts.GetGeneratedResourceName()
 }
 
+// ReceiveRouterGroupNameFromParent returns the name of the resources of a router group of a ThanosReceive.
+func ReceiveRouterGroupNameFromParent(resourceName, groupName string) string {
+	opts := manifestreceive.RouterOptions{Options: manifests.Options{Owner: resourceName}, Group: groupName}
+	if err := opts.Valid(); err != nil {
+		panic("invalid router options")
+	}
+	return opts.GetGeneratedResourceName()
+}
+
+// ReceiveWriteProbeNameFromParent returns the name of the Thanos Receive write probe component.
+func ReceiveWriteProbeNameFromParent(resourceName string) string {
+	opts := manifestreceive.WriteProbeOptions{Options: manifests.Options{Owner: resourceName}}
+	if err := opts.Valid(); err != nil {
+		panic("invalid write probe options")
+	}
+	return opts.GetGeneratedResourceName()
+}
+
+// receiverV1Alpha1ToWriteProbeOptions transforms the write probe of a ThanosReceive into options for the manifests.
+// The probe writes through the router Service and queries the Service of the referenced ThanosQuery.
+func receiverV1Alpha1ToWriteProbeOptions(in v1alpha1.ThanosReceive) manifestreceive.WriteProbeOptions {
+	probe := in.Spec.WriteProbe
+	ns := in.GetNamespace()
+	return manifestreceive.WriteProbeOptions{
+		Options: manifests.Options{
+			Owner:           in.GetName(),
+			Namespace:       ns,
+			Image:           probe.Image,
+			SecurityContext: in.Spec.Router.SecurityContext,
+			// the probe talks to the router, so it is scheduled like the router
+			PlacementConfig: &manifests.Placement{
+				NodeSelector:              in.Spec.Router.NodeSelector,
+				Affinity:                  in.Spec.Router.Affinity,
+				Tolerations:               in.Spec.Router.Tolerations,
+				TopologySpreadConstraints: in.Spec.Router.TopologySpreadConstraints,
+			},
+		},
+		Schedule:        ptr.Deref(probe.Schedule, "*/5 * * * *"),
+		DeadlineSeconds: ptr.Deref(probe.DeadlineSeconds, 120),
+		RemoteWriteURL: fmt.Sprintf("http://%s:%d/api/v1/receive",
+			manifests.ServiceHost(ptr.Deref(in.Spec.Router.ExistingService, ReceiveRouterNameFromParent(in.GetName())), ns), in.Spec.Router.Service.PortOr(manifestreceive.RemoteWritePortName, manifestreceive.RemoteWritePort)),
+		QueryURL: fmt.Sprintf("http://%s:%d", manifests.ServiceHost(QueryNameFromParent(probe.QueryName), ns), manifestquery.HTTPPort),
+	}
+}
+
 func storeV1Alpha1ToOptions(in storeV1Alpha1TransformInput) manifestsstore.Options {
 	opts := commonToOpts(&in.CRD, in.CRD.Spec.Replicas, in.CRD.Spec.CommonFields, &in.CRD.Spec.StatefulSetFields, in.FeatureGate, in.CRD.Spec.Additional)
 	var indexHeaderOpts *manifestsstore.IndexHeaderOptions
@@ -278,8 +653,9 @@ func storeV1Alpha1ToOptions(in storeV1Alpha1TransformInput) manifestsstore.Optio
 	}
 	sops := manifestsstore.Options{
 		ObjStoreSecret:           in.CRD.Spec.ObjectStorageConfig.ToSecretKeySelector(),
-		IndexCacheConfig:         toManifestCacheConfig(in.CRD.Spec.IndexCacheConfig),
-		CachingBucketConfig:      toManifestCacheConfig(in.CRD.Spec.CachingBucketConfig),
+		ObjStoreConfig:           toManifestObjStoreConfig(in.CRD.Spec.ObjectStorageConfig),
+		IndexCacheConfig:         toManifestCacheConfig(in.CRD.Spec.IndexCacheConfig, managedCacheClientConfig(storeManagedCacheToOpts(in.CRD))),
+		CachingBucketConfig:      toManifestCacheConfig(in.CRD.Spec.CachingBucketConfig, managedCacheClientConfig(storeManagedCacheToOpts(in.CRD))),
 		IgnoreDeletionMarksDelay: manifests.Duration(in.CRD.Spec.IgnoreDeletionMarksDelay),
 		IndexHeaderOptions:       indexHeaderOpts,
 		BlockConfigOptions:       blockConfigOpts,
@@ -391,6 +767,7 @@ func compactV1Alpha1ToOptions(in compactV1Alpha1TransformInput) manifestscompact
 			StorageClassName: in.CRD.Spec.StorageConfiguration.StorageClass,
 		},
 		ObjStoreSecret: in.CRD.Spec.ObjectStorageConfig.ToSecretKeySelector(),
+		ObjStoreConfig: toManifestObjStoreConfig(in.CRD.Spec.ObjectStorageConfig),
 	}
 
 	if in.CRD.Spec.TimeRangeConfig != nil {
@@ -430,33 +807,66 @@ func commonToOpts(
 	labels := manifests.MergeMaps(owner.GetLabels(), common.Labels)
 
 	return manifests.Options{
-		Owner:                owner.GetName(),
-		Namespace:            owner.GetNamespace(),
-		Replicas:             replicas,
-		Labels:               labels,
-		Annotations:          manifests.MergeMaps(owner.GetAnnotations(), common.Annotations),
-		Image:                common.Image,
-		Version:              common.Version,
-		ResourceRequirements: common.ResourceRequirements,
-		LogLevel:             common.LogLevel,
-		LogFormat:            common.LogFormat,
-		Additional:           additionalToOpts(additional),
-		ServiceMonitorConfig: serviceMonitorConfigToOptsGlobal(featureGate, labels),
-		PodDisruptionConfig:  podDisruptionBudgetConfigToOpts(replicas, common.PodDisruptionBudgetConfig),
+		Owner:                     owner.GetName(),
+		Namespace:                 owner.GetNamespace(),
+		Replicas:                  replicas,
+		Labels:                    labels,
+		Annotations:               manifests.MergeMaps(owner.GetAnnotations(), common.Annotations),
+		PodAnnotations:            restartedAtPodAnnotations(owner),
+		Image:                     common.Image,
+		Version:                   common.Version,
+		ResourceRequirements:      common.ResourceRequirements,
+		ImagePullSecrets:          common.ImagePullSecrets,
+		ServiceAccountAnnotations: common.ServiceAccountAnnotations,
+		ServiceAnnotations:        common.ServiceAnnotations,
+		LogLevel:                  common.LogLevel,
+		LogFormat:                 common.LogFormat,
+		Additional:                additionalToOpts(additional),
+		ServiceMonitorConfig:      serviceMonitorConfigToOptsGlobal(featureGate, labels),
+		MonitoringMixinConfig:     monitoringMixinConfigToOpts(featureGate, labels),
+		PodDisruptionConfig:       podDisruptionBudgetConfigToOpts(replicas, common.PodDisruptionBudgetConfig),
+		EnableMetricsService:      metricsServiceEnabled(common.MetricsService),
 		PlacementConfig: &manifests.Placement{
 			NodeSelector:              common.NodeSelector,
 			Affinity:                  common.Affinity,
 			Tolerations:               common.Tolerations,
 			TopologySpreadConstraints: common.TopologySpreadConstraints,
 		},
-		StatefulSet:     statefulSetToOpts(statefulSet),
-		SecurityContext: common.SecurityContext,
+		StatefulSet:              statefulSetToOpts(statefulSet),
+		SecurityContext:          common.SecurityContext,
+		ContainerSecurityContext: common.ContainerSecurityContext,
+		PriorityClassName:        ptr.Deref(common.PriorityClassName, ""),
+		TracingConfig:            common.TracingConfig,
+		Tracing:                  tracingToOpts(common.Tracing),
+		Probes:                   probesToOpts(common.Probes),
 		Features: manifests.Features{
 			EnableOtelSidecar: featureGate.OtelSidecarEnabled(),
 		},
 	}
 }
 
+func tracingToOpts(in *v1alpha1.TracingSpec) *manifests.TracingOptions {
+	if in == nil {
+		return nil
+	}
+	return &manifests.TracingOptions{
+		Type:          string(in.Type),
+		Endpoint:      in.Endpoint,
+		Insecure:      ptr.Deref(in.Insecure, false),
+		SamplingRatio: ptr.Deref(in.SamplingRatio, ""),
+	}
+}
+
+// restartedAtPodAnnotations returns the pod annotations restarting the pods of the owner
+// when its v1alpha1.RestartedAtAnnotation changes.
+func restartedAtPodAnnotations(owner client.Object) map[string]string {
+	restartedAt, ok := owner.GetAnnotations()[v1alpha1.RestartedAtAnnotation]
+	if !ok {
+		return nil
+	}
+	return map[string]string{v1alpha1.RestartedAtAnnotation: restartedAt}
+}
+
 func statefulSetToOpts(in *v1alpha1.StatefulSetFields) manifests.StatefulSet {
 	if in == nil {
 		return manifests.StatefulSet{}
@@ -475,10 +885,42 @@ func statefulSetToOpts(in *v1alpha1.StatefulSetFields) manifests.StatefulSet {
 
 	stsConfig.TerminationGracePeriodSeconds = in.TerminationGracePeriodSeconds
 	stsConfig.MinReadySeconds = in.MinReadySeconds
+	stsConfig.UpdateStrategy = in.UpdateStrategy
 
 	return stsConfig
 }
 
+func deploymentToOpts(in v1alpha1.DeploymentFields) manifests.Deployment {
+	return manifests.Deployment{
+		TerminationGracePeriodSeconds: in.TerminationGracePeriodSeconds,
+		Strategy:                      in.Strategy,
+	}
+}
+
+func probesToOpts(in *v1alpha1.ProbesSpec) *manifests.Probes {
+	if in == nil {
+		return nil
+	}
+	return &manifests.Probes{
+		Liveness:  probeToOpts(in.Liveness),
+		Readiness: probeToOpts(in.Readiness),
+		Startup:   probeToOpts(in.Startup),
+	}
+}
+
+func probeToOpts(in *v1alpha1.ProbeSpec) *manifests.Probe {
+	if in == nil {
+		return nil
+	}
+	return &manifests.Probe{
+		InitialDelaySeconds: in.InitialDelaySeconds,
+		PeriodSeconds:       in.PeriodSeconds,
+		TimeoutSeconds:      in.TimeoutSeconds,
+		SuccessThreshold:    in.SuccessThreshold,
+		FailureThreshold:    in.FailureThreshold,
+	}
+}
+
 func additionalToOpts(in v1alpha1.Additional) manifests.Additional {
 	return manifests.Additional{
 		Args:         in.Args,
@@ -487,6 +929,7 @@ func additionalToOpts(in v1alpha1.Additional) manifests.Additional {
 		VolumeMounts: in.VolumeMounts,
 		Ports:        in.Ports,
 		Env:          in.Env,
+		EnvFrom:      in.EnvFrom,
 		ServicePorts: in.ServicePorts,
 	}
 }
@@ -500,6 +943,65 @@ func serviceMonitorConfigToOptsGlobal(fg featuregate.Config, labels map[string]s
 	}
 }
 
+// deploymentReplicas returns the replicas of a Deployment. Replicas that are managed externally
+// are taken from the status of the Deployment, so that the objects sized from them follow its scaling.
+func deploymentReplicas(replicas *int32, status v1alpha1.DeploymentStatus) int32 {
+	if replicas != nil {
+		return *replicas
+	}
+	return max(status.Replicas, 1)
+}
+
+func monitoringMixinConfigToOpts(fg featuregate.Config, labels map[string]string) *manifests.MonitoringMixinConfig {
+	if !fg.MonitoringMixinEnabled() {
+		return nil
+	}
+	return &manifests.MonitoringMixinConfig{
+		Labels: labels,
+	}
+}
+
+// endpointAddressOptions returns the options used to build the addresses of the members of a hashring.
+// The hashring configuration takes precedence over the router replication protocol.
+func endpointAddressOptions(router v1alpha1.RouterSpec, hashring v1alpha1.IngesterHashringSpec) receive.EndpointAddressOptions {
+	protocol := ptr.Deref(router.ReplicationProtocol, v1alpha1.ReplicationProtocolGRPC)
+	var opts receive.EndpointAddressOptions
+	if ea := hashring.EndpointAddress; ea != nil {
+		protocol = ptr.Deref(ea.ReplicationProtocol, protocol)
+		opts.HostFormat = receive.EndpointHostFormat(ptr.Deref(ea.HostFormat, v1alpha1.EndpointHostFormatHostname))
+		opts.GRPCPort = ptr.Deref(ea.GRPCPort, 0)
+		opts.CapnProtoPort = ptr.Deref(ea.CapnProtoPort, 0)
+	}
+	opts.CapnProto = protocol == v1alpha1.ReplicationProtocolCapnProto
+	return opts
+}
+
+func tlsConfigToOpts(in *v1alpha1.TLSConfig) *manifests.TLSConfig {
+	if in == nil {
+		return nil
+	}
+	return &manifests.TLSConfig{
+		CertSecret: in.CertSecret,
+		ClientCA:   in.ClientCA,
+	}
+}
+
+func grpcClientTLSConfigToOpts(in *v1alpha1.GRPCClientTLSConfig) *manifests.GRPCClientTLSConfig {
+	if in == nil {
+		return nil
+	}
+	return &manifests.GRPCClientTLSConfig{
+		CertSecret: ptr.Deref(in.CertSecret, ""),
+		CA:         in.CA,
+		ServerName: ptr.Deref(in.ServerName, ""),
+	}
+}
+
+// metricsServiceEnabled returns true if a dedicated metrics Service should be created for the component.
+func metricsServiceEnabled(in *v1alpha1.MetricsServiceConfig) bool {
+	return in != nil && ptr.Deref(in.Enable, false)
+}
+
 func podDisruptionBudgetConfigToOpts(replicas int32, pdb *v1alpha1.PodDisruptionBudgetConfig) *manifests.PodDisruptionBudgetOptions {
 	if replicas < 2 || pdb == nil {
 		return nil
@@ -509,17 +1011,75 @@ func podDisruptionBudgetConfigToOpts(replicas int32, pdb *v1alpha1.PodDisruption
 		return nil
 	}
 
+	if pdb.MinAvailable != nil || pdb.MaxUnavailable != nil {
+		return &manifests.PodDisruptionBudgetOptions{
+			MinAvailable:   pdb.MinAvailable,
+			MaxUnavailable: pdb.MaxUnavailable,
+		}
+	}
+
 	// set the basic pdb config
 	return &manifests.PodDisruptionBudgetOptions{
 		MaxUnavailable: ptr.To(int32(1)),
 	}
 }
 
-func toManifestCacheConfig(config *v1alpha1.CacheConfig) manifests.CacheConfig {
+// ingesterMaxUnavailable returns the number of ingesters in a hashring that can be voluntarily disrupted
+// at the same time without the router losing write quorum.
+// Writes succeed as long as (replicationFactor+1)/2 replicas acknowledge them, so we can tolerate
+// (replicationFactor-1)/2 concurrent disruptions. The result is never lower than 1, so that rollouts
+// can make progress, and never higher than replicas-1.
+func ingesterMaxUnavailable(replicationFactor, replicas int32) int32 {
+	tolerated := (replicationFactor - 1) / 2
+	tolerated = min(tolerated, replicas-1)
+	return max(tolerated, 1)
+}
+
+// ingesterUpdateStrategy returns the update strategy of the ingesters of a hashring, with the rolling update
+// maxUnavailable derived from the replication factor unless it is set explicitly.
+// The strategy is copied before it is modified, since it may belong to the cached resource.
+// Strategies other than RollingUpdate and a maxUnavailable of 1, which is the Kubernetes default, are returned as is.
+func ingesterUpdateStrategy(strategy *appsv1.StatefulSetUpdateStrategy, maxUnavailable int32) *appsv1.StatefulSetUpdateStrategy {
+	if maxUnavailable <= 1 {
+		return strategy
+	}
+	if strategy != nil && strategy.Type != "" && strategy.Type != appsv1.RollingUpdateStatefulSetStrategyType {
+		return strategy
+	}
+	if strategy != nil && strategy.RollingUpdate != nil && strategy.RollingUpdate.MaxUnavailable != nil {
+		return strategy
+	}
+
+	strategy = strategy.DeepCopy()
+	if strategy == nil {
+		strategy = &appsv1.StatefulSetUpdateStrategy{}
+	}
+	strategy.Type = appsv1.RollingUpdateStatefulSetStrategyType
+	if strategy.RollingUpdate == nil {
+		strategy.RollingUpdate = &appsv1.RollingUpdateStatefulSetStrategy{}
+	}
+	strategy.RollingUpdate.MaxUnavailable = ptr.To(intstr.FromInt32(maxUnavailable))
+	return strategy
+}
+
+func toManifestObjStoreConfig(config v1alpha1.ObjectStorageConfig) manifests.ObjStoreConfig {
+	objStoreConfig := manifests.ObjStoreConfig{
+		FromFile: ptr.Deref(config.Mode, v1alpha1.ObjectStorageConfigModeInline) == v1alpha1.ObjectStorageConfigModeFile,
+	}
+	for _, env := range config.Env {
+		objStoreConfig.Env = append(objStoreConfig.Env, manifests.ObjStoreEnvVar{Name: env.Name, Key: env.Key})
+	}
+	return objStoreConfig
+}
+
+// toManifestCacheConfig converts a cache configuration, falling back to the managed memcached, if any,
+// when neither an in-memory nor an external cache is configured.
+func toManifestCacheConfig(config *v1alpha1.CacheConfig, managed *manifests.MemcachedConfig) manifests.CacheConfig {
 	if config == nil {
 		return manifests.CacheConfig{
 			InMemoryCacheConfig: nil,
 			FromSecret:          nil,
+			Memcached:           managed,
 		}
 	}
 
@@ -547,8 +1107,53 @@ func toManifestCacheConfig(config *v1alpha1.CacheConfig) manifests.CacheConfig {
 			}
 		}
 	}
+	if toInMemoryCacheConfig == nil {
+		return manifests.CacheConfig{Memcached: managed}
+	}
 	return manifests.CacheConfig{
 		InMemoryCacheConfig: toInMemoryCacheConfig,
 		FromSecret:          nil,
 	}
 }
+
+// storeManagedCacheToOpts returns the options of the memcached managed for a ThanosStore, or nil if there is none.
+func storeManagedCacheToOpts(store v1alpha1.ThanosStore) *manifestsmemcached.Options {
+	return managedCacheToOpts(store.Spec.Cache, store.GetName(), StoreNameFromParent(store.GetName(), nil), store.GetNamespace())
+}
+
+// queryFrontendManagedCacheToOpts returns the options of the memcached managed for the Query Frontend of a ThanosQuery,
+// or nil if there is none.
+func queryFrontendManagedCacheToOpts(query v1alpha1.ThanosQuery) *manifestsmemcached.Options {
+	if query.Spec.QueryFrontend == nil {
+		return nil
+	}
+	return managedCacheToOpts(query.Spec.QueryFrontend.Cache, query.GetName(), QueryFrontendNameFromParent(query.GetName()), query.GetNamespace())
+}
+
+// managedCacheToOpts returns the options of the memcached managed for the component named parent,
+// or nil if the cache is not managed.
+func managedCacheToOpts(spec *v1alpha1.ManagedCacheSpec, owner, parent, namespace string) *manifestsmemcached.Options {
+	if spec == nil || !ptr.Deref(spec.Managed, false) {
+		return nil
+	}
+	memoryLimit := ptr.Deref(spec.MemoryLimit, "1Gi").ToResourceQuantity()
+	maxItemSize := ptr.Deref(spec.MaxItemSize, "1Mi").ToResourceQuantity()
+	return &manifestsmemcached.Options{
+		Owner:                owner,
+		Parent:               parent,
+		Namespace:            namespace,
+		Replicas:             ptr.Deref(spec.Replicas, 1),
+		Image:                ptr.Deref(spec.Image, ""),
+		MemoryLimit:          memoryLimit.Value(),
+		MaxItemSize:          maxItemSize.Value(),
+		ResourceRequirements: spec.Resources,
+	}
+}
+
+// managedCacheClientConfig returns the client configuration of a managed memcached, or nil if there is none.
+func managedCacheClientConfig(opts *manifestsmemcached.Options) *manifests.MemcachedConfig {
+	if opts == nil {
+		return nil
+	}
+	return opts.ClientConfig()
+}
@@ -0,0 +1,768 @@
+package controller
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+	manifestquery "github.com/thanos-community/thanos-operator/internal/pkg/manifests/query"
+	manifestreceive "github.com/thanos-community/thanos-operator/internal/pkg/manifests/receive"
+	manifestsstore "github.com/thanos-community/thanos-operator/internal/pkg/manifests/store"
+	"github.com/thanos-community/thanos-operator/internal/pkg/receive"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+func TestIngesterMaxUnavailable(t *testing.T) {
+	for _, tc := range []struct {
+		name              string
+		replicationFactor int32
+		replicas          int32
+		expect            int32
+	}{
+		{name: "no replication", replicationFactor: 1, replicas: 3, expect: 1},
+		{name: "replication factor three", replicationFactor: 3, replicas: 3, expect: 1},
+		{name: "replication factor five", replicationFactor: 5, replicas: 6, expect: 2},
+		{name: "capped by replicas", replicationFactor: 5, replicas: 2, expect: 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ingesterMaxUnavailable(tc.replicationFactor, tc.replicas); got != tc.expect {
+				t.Errorf("expected %d, got %d", tc.expect, got)
+			}
+		})
+	}
+}
+
+func TestIngesterRollingUpdateMaxUnavailable(t *testing.T) {
+	onDelete := &appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType}
+	explicit := &appsv1.StatefulSetUpdateStrategy{
+		RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{MaxUnavailable: ptr.To(intstr.FromInt32(3))},
+	}
+	partitioned := &appsv1.StatefulSetUpdateStrategy{
+		Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: ptr.To(int32(4))},
+	}
+
+	if got := ingesterUpdateStrategy(nil, 1); got != nil {
+		t.Errorf("expected no strategy when a single ingester can be unavailable, got %v", got)
+	}
+	if got := ingesterUpdateStrategy(onDelete, 2); got != onDelete {
+		t.Errorf("expected the OnDelete strategy to be kept, got %v", got)
+	}
+	if got := ingesterUpdateStrategy(explicit, 2); got != explicit {
+		t.Errorf("expected an explicit maxUnavailable to be kept, got %v", got)
+	}
+
+	got := ingesterUpdateStrategy(nil, 2)
+	if got == nil || got.Type != appsv1.RollingUpdateStatefulSetStrategyType || got.RollingUpdate.MaxUnavailable.IntValue() != 2 {
+		t.Errorf("expected a rolling update with maxUnavailable 2, got %v", got)
+	}
+
+	got = ingesterUpdateStrategy(partitioned, 2)
+	if got.RollingUpdate.MaxUnavailable.IntValue() != 2 || ptr.Deref(got.RollingUpdate.Partition, 0) != 4 {
+		t.Errorf("expected maxUnavailable 2 with the partition kept, got %v", got.RollingUpdate)
+	}
+	if partitioned.RollingUpdate.MaxUnavailable != nil {
+		t.Errorf("expected the original strategy to be unchanged")
+	}
+}
+
+func TestPodDisruptionBudgetConfigToOpts(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		replicas int32
+		config   *v1alpha1.PodDisruptionBudgetConfig
+		expect   *manifests.PodDisruptionBudgetOptions
+	}{
+		{name: "single replica", replicas: 1, config: &v1alpha1.PodDisruptionBudgetConfig{Enable: ptr.To(true)}},
+		{name: "disabled", replicas: 3, config: &v1alpha1.PodDisruptionBudgetConfig{Enable: ptr.To(false)}},
+		{
+			name:     "default",
+			replicas: 3,
+			config:   &v1alpha1.PodDisruptionBudgetConfig{Enable: ptr.To(true)},
+			expect:   &manifests.PodDisruptionBudgetOptions{MaxUnavailable: ptr.To(int32(1))},
+		},
+		{
+			name:     "min available",
+			replicas: 3,
+			config:   &v1alpha1.PodDisruptionBudgetConfig{Enable: ptr.To(true), MinAvailable: ptr.To(int32(2))},
+			expect:   &manifests.PodDisruptionBudgetOptions{MinAvailable: ptr.To(int32(2))},
+		},
+		{
+			name:     "max unavailable",
+			replicas: 5,
+			config:   &v1alpha1.PodDisruptionBudgetConfig{Enable: ptr.To(true), MaxUnavailable: ptr.To(int32(2))},
+			expect:   &manifests.PodDisruptionBudgetOptions{MaxUnavailable: ptr.To(int32(2))},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := podDisruptionBudgetConfigToOpts(tc.replicas, tc.config)
+			if (got == nil) != (tc.expect == nil) {
+				t.Fatalf("expected %v, got %v", tc.expect, got)
+			}
+			if got == nil {
+				return
+			}
+			if ptr.Deref(got.MinAvailable, -1) != ptr.Deref(tc.expect.MinAvailable, -1) {
+				t.Errorf("expected minAvailable %v, got %v", ptr.Deref(tc.expect.MinAvailable, -1), ptr.Deref(got.MinAvailable, -1))
+			}
+			if ptr.Deref(got.MaxUnavailable, -1) != ptr.Deref(tc.expect.MaxUnavailable, -1) {
+				t.Errorf("expected maxUnavailable %v, got %v", ptr.Deref(tc.expect.MaxUnavailable, -1), ptr.Deref(got.MaxUnavailable, -1))
+			}
+		})
+	}
+}
+
+func TestEndpointAddressOptions(t *testing.T) {
+	capnproto := v1alpha1.ReplicationProtocolCapnProto
+	grpc := v1alpha1.ReplicationProtocolGRPC
+
+	for _, tc := range []struct {
+		name     string
+		router   v1alpha1.RouterSpec
+		hashring v1alpha1.IngesterHashringSpec
+		expect   receive.EndpointAddressOptions
+	}{
+		{
+			name:   "defaults to grpc",
+			expect: receive.EndpointAddressOptions{},
+		},
+		{
+			name:   "inherits router protocol",
+			router: v1alpha1.RouterSpec{ReplicationProtocol: &capnproto},
+			expect: receive.EndpointAddressOptions{CapnProto: true},
+		},
+		{
+			name:   "hashring overrides router protocol",
+			router: v1alpha1.RouterSpec{ReplicationProtocol: &capnproto},
+			hashring: v1alpha1.IngesterHashringSpec{
+				EndpointAddress: &v1alpha1.EndpointAddressConfig{
+					ReplicationProtocol: &grpc,
+					GRPCPort:            ptr.To(int32(10907)),
+				},
+			},
+			expect: receive.EndpointAddressOptions{HostFormat: receive.EndpointHostFormatHostname, GRPCPort: 10907},
+		},
+		{
+			name: "ip host format",
+			hashring: v1alpha1.IngesterHashringSpec{
+				EndpointAddress: &v1alpha1.EndpointAddressConfig{
+					HostFormat: ptr.To(v1alpha1.EndpointHostFormatIP),
+				},
+			},
+			expect: receive.EndpointAddressOptions{HostFormat: receive.EndpointHostFormatIP},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := endpointAddressOptions(tc.router, tc.hashring); got != tc.expect {
+				t.Errorf("expected %+v, got %+v", tc.expect, got)
+			}
+		})
+	}
+}
+
+func TestIngesterEndpointAddressPorts(t *testing.T) {
+	crd := v1alpha1.ThanosReceive{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"}}
+	opts := receiverV1Alpha1ToIngesterOptions(receiverV1Alpha1ToIngesterTransformInput{
+		CRD: crd,
+		Spec: v1alpha1.IngesterHashringSpec{
+			Name:                 "hashring",
+			Replicas:             1,
+			StorageConfiguration: v1alpha1.StorageConfiguration{Size: "1Gi"},
+			EndpointAddress: &v1alpha1.EndpointAddressConfig{
+				ReplicationProtocol: ptr.To(v1alpha1.ReplicationProtocolCapnProto),
+				GRPCPort:            ptr.To(int32(10911)),
+				CapnProtoPort:       ptr.To(int32(19392)),
+			},
+		},
+	})
+
+	container := manifestreceive.NewIngestorStatefulSet(opts).Spec.Template.Spec.Containers[0]
+	for _, arg := range []string{"--grpc-address=0.0.0.0:10911", "--receive.capnproto-address=0.0.0.0:19392"} {
+		if !slices.Contains(container.Args, arg) {
+			t.Errorf("expected arg %q, got %q", arg, container.Args)
+		}
+	}
+	ports := map[string]int32{}
+	for _, port := range container.Ports {
+		ports[port.Name] = port.ContainerPort
+	}
+	for _, port := range manifestreceive.NewIngestorService(opts).Spec.Ports {
+		if port.Port != port.TargetPort.IntVal || port.Port != ports[port.Name] {
+			t.Errorf("expected service port %s to match container port %d, got %d", port.Name, ports[port.Name], port.Port)
+		}
+	}
+	if ports[manifestreceive.GRPCPortName] != 10911 || ports[manifestreceive.CapnProtoPortName] != 19392 {
+		t.Errorf("expected the ingesters to serve the endpoint address ports, got %v", ports)
+	}
+}
+
+func TestStoreTimeRangeSharding(t *testing.T) {
+	store := v1alpha1.ThanosStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"},
+		Spec: v1alpha1.ThanosStoreSpec{
+			ShardingStrategy: v1alpha1.ShardingStrategy{
+				Type: v1alpha1.Time,
+				TimeRanges: []v1alpha1.TimeRangeConfig{
+					{MinTime: ptr.To(v1alpha1.Duration("-2w"))},
+					{MinTime: ptr.To(v1alpha1.Duration("-1y")), MaxTime: ptr.To(v1alpha1.Duration("-2w"))},
+				},
+			},
+			StorageConfiguration: v1alpha1.StorageConfiguration{Size: "1Gi"},
+		},
+	}
+
+	buildables, err := (&ThanosStoreReconciler{}).specToOptions(store)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(buildables) != 2 {
+		t.Fatalf("expected a shard per time range, got %d", len(buildables))
+	}
+
+	for i, expect := range []struct {
+		name     string
+		min, max manifests.Duration
+	}{
+		{name: "thanos-store-test-shard-0", min: "-2w"},
+		{name: "thanos-store-test-shard-1", min: "-1y", max: "-2w"},
+	} {
+		opts := buildables[i].(manifestsstore.Options)
+		if opts.GetGeneratedResourceName() != expect.name {
+			t.Errorf("shard %d: expected name %s, got %s", i, expect.name, opts.GetGeneratedResourceName())
+		}
+		if opts.Min != expect.min || opts.Max != expect.max {
+			t.Errorf("shard %d: expected time range [%s, %s], got [%s, %s]", i, expect.min, expect.max, opts.Min, opts.Max)
+		}
+		if len(opts.RelabelConfigs) != 0 {
+			t.Errorf("shard %d: expected no block relabeling, got %v", i, opts.RelabelConfigs)
+		}
+	}
+}
+
+func TestIngesterShutdownDrain(t *testing.T) {
+	for _, tc := range []struct {
+		name              string
+		drainSeconds      *int64
+		gracePeriod       *int64
+		expectDrain       int64
+		expectGracePeriod int64
+	}{
+		{name: "no drain", expectGracePeriod: 900},
+		{name: "drain extends default grace period", drainSeconds: ptr.To(int64(60)), expectDrain: 60, expectGracePeriod: 960},
+		{name: "explicit grace period", drainSeconds: ptr.To(int64(60)), gracePeriod: ptr.To(int64(300)), expectDrain: 60, expectGracePeriod: 300},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			crd := v1alpha1.ThanosReceive{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"},
+				Spec: v1alpha1.ThanosReceiveSpec{
+					Ingester: v1alpha1.IngesterSpec{ShutdownDrainSeconds: tc.drainSeconds},
+					StatefulSetFields: v1alpha1.StatefulSetFields{
+						TerminationGracePeriodSeconds: tc.gracePeriod,
+					},
+				},
+			}
+
+			opts := receiverV1Alpha1ToIngesterOptions(receiverV1Alpha1ToIngesterTransformInput{
+				CRD: crd,
+				Spec: v1alpha1.IngesterHashringSpec{
+					Name:                 "hashring",
+					Replicas:             1,
+					StorageConfiguration: v1alpha1.StorageConfiguration{Size: "1Gi"},
+				},
+			})
+			if opts.ShutdownDrainSeconds != tc.expectDrain {
+				t.Errorf("expected drain of %d seconds, got %d", tc.expectDrain, opts.ShutdownDrainSeconds)
+			}
+			if got := ptr.Deref(opts.StatefulSet.TerminationGracePeriodSeconds, 0); got != tc.expectGracePeriod {
+				t.Errorf("expected grace period of %d seconds, got %d", tc.expectGracePeriod, got)
+			}
+		})
+	}
+}
+
+func TestIngesterHashringAdditionalArgs(t *testing.T) {
+	shared := []string{"--tsdb.max-exemplars=100"}
+	crd := v1alpha1.ThanosReceive{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"},
+		Spec: v1alpha1.ThanosReceiveSpec{
+			Ingester: v1alpha1.IngesterSpec{Additional: v1alpha1.Additional{Args: shared}},
+		},
+	}
+
+	opts := receiverV1Alpha1ToIngesterOptions(receiverV1Alpha1ToIngesterTransformInput{
+		CRD: crd,
+		Spec: v1alpha1.IngesterHashringSpec{
+			Name:                 "hashring",
+			Replicas:             1,
+			StorageConfiguration: v1alpha1.StorageConfiguration{Size: "1Gi"},
+			AdditionalArgs:       []string{"--tsdb.max-exemplars=200"},
+		},
+	})
+	if want := []string{"--tsdb.max-exemplars=100", "--tsdb.max-exemplars=200"}; !slices.Equal(opts.Additional.Args, want) {
+		t.Errorf("expected args %q, got %q", want, opts.Additional.Args)
+	}
+	if len(shared) != 1 || len(crd.Spec.Ingester.Args) != 1 {
+		t.Errorf("expected the args of the ingester spec to be left unchanged, got %q", crd.Spec.Ingester.Args)
+	}
+
+	args := manifestreceive.NewIngestorStatefulSet(opts).Spec.Template.Spec.Containers[0].Args
+	if !slices.Contains(args, "--tsdb.max-exemplars=200") || slices.Contains(args, "--tsdb.max-exemplars=100") {
+		t.Errorf("expected the args of the hashring to override those of the ingester spec, got %q", args)
+	}
+}
+
+func TestIngesterHashringAdditionalVolumes(t *testing.T) {
+	volume := func(name string) corev1.Volume {
+		return corev1.Volume{Name: name, VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: name}}}
+	}
+	crd := v1alpha1.ThanosReceive{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"},
+		Spec: v1alpha1.ThanosReceiveSpec{
+			Ingester: v1alpha1.IngesterSpec{Additional: v1alpha1.Additional{
+				Volumes:      []corev1.Volume{volume("ca-bundle")},
+				VolumeMounts: []corev1.VolumeMount{{Name: "ca-bundle", MountPath: "/etc/ssl/certs/corp"}},
+			}},
+		},
+	}
+
+	sts := manifestreceive.NewIngestorStatefulSet(receiverV1Alpha1ToIngesterOptions(receiverV1Alpha1ToIngesterTransformInput{
+		CRD: crd,
+		Spec: v1alpha1.IngesterHashringSpec{
+			Name:                   "hashring",
+			Replicas:               1,
+			StorageConfiguration:   v1alpha1.StorageConfiguration{Size: "1Gi"},
+			AdditionalVolumes:      []corev1.Volume{volume("s3-credentials")},
+			AdditionalVolumeMounts: []corev1.VolumeMount{{Name: "s3-credentials", MountPath: "/etc/s3"}},
+		},
+	}))
+	var volumes, mounts []string
+	for _, v := range sts.Spec.Template.Spec.Volumes {
+		volumes = append(volumes, v.Name)
+	}
+	for _, m := range sts.Spec.Template.Spec.Containers[0].VolumeMounts {
+		mounts = append(mounts, m.Name)
+	}
+	for _, name := range []string{"ca-bundle", "s3-credentials"} {
+		if !slices.Contains(volumes, name) || !slices.Contains(mounts, name) {
+			t.Errorf("expected volume %s to be mounted, got volumes %q and mounts %q", name, volumes, mounts)
+		}
+	}
+	if len(crd.Spec.Ingester.Volumes) != 1 {
+		t.Errorf("expected the volumes of the ingester spec to be left unchanged, got %v", crd.Spec.Ingester.Volumes)
+	}
+}
+
+func TestRouterGroupOptions(t *testing.T) {
+	routerSpec := v1alpha1.RouterSpec{
+		Replicas: ptr.To(int32(3)),
+		Tenancy: &v1alpha1.RouterTenancyConfig{
+			TenantHeader:    ptr.To("X-Scope-OrgID"),
+			DefaultTenantID: ptr.To("anonymous"),
+		},
+		ExistingService: ptr.To("remote-write"),
+		Ingress:         &v1alpha1.IngressConfig{Hosts: []string{"remote-write.example.com"}},
+	}
+	routerSpec.PodDisruptionBudgetConfig = &v1alpha1.PodDisruptionBudgetConfig{Enable: ptr.To(true)}
+	crd := v1alpha1.ThanosReceive{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"},
+		Spec:       v1alpha1.ThanosReceiveSpec{Router: routerSpec},
+	}
+	router := receiverV1Alpha1ToRouterOptions(receiverV1Alpha1ToRouterTransformInput{CRD: crd})
+
+	group := receiverV1Alpha1ToRouterGroupOptions(router, routerSpec, v1alpha1.RouterGroupSpec{Name: "batch", Replicas: 1})
+	if group.Replicas != 1 || group.PodDisruptionConfig != nil {
+		t.Errorf("expected a single router without a budget, got %d replicas and budget %v", group.Replicas, group.PodDisruptionConfig)
+	}
+	if group.ExistingServiceName != "" || group.Ingress != nil {
+		t.Errorf("expected the group to have a Service of its own and no Ingress")
+	}
+	if group.Tenancy != router.Tenancy {
+		t.Errorf("expected the tenancy of the routers, got %v", group.Tenancy)
+	}
+
+	group = receiverV1Alpha1ToRouterGroupOptions(router, routerSpec, v1alpha1.RouterGroupSpec{
+		Name:     "eu",
+		Replicas: 2,
+		Tenancy:  &v1alpha1.RouterTenancyConfig{DefaultTenantID: ptr.To("eu")},
+		Service:  &v1alpha1.ServiceConfig{Type: corev1.ServiceTypeLoadBalancer},
+	})
+	if group.GetGeneratedResourceName() != ReceiveRouterGroupNameFromParent("test", "eu") {
+		t.Errorf("unexpected group name %s", group.GetGeneratedResourceName())
+	}
+	if group.Tenancy.TenantHeader != "X-Scope-OrgID" || group.Tenancy.DefaultTenantID != "eu" {
+		t.Errorf("expected the tenancy of the group to override the routers, got %v", group.Tenancy)
+	}
+	if group.PodDisruptionConfig == nil || group.Service == nil || group.Service.Type != corev1.ServiceTypeLoadBalancer {
+		t.Errorf("expected a budget and the Service of the group, got %v and %v", group.PodDisruptionConfig, group.Service)
+	}
+	if router.Tenancy.DefaultTenantID != "anonymous" {
+		t.Errorf("expected the options of the routers to be unchanged, got %v", router.Tenancy)
+	}
+}
+
+func TestRouterTenancyOptions(t *testing.T) {
+	crd := v1alpha1.ThanosReceive{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"},
+		Spec: v1alpha1.ThanosReceiveSpec{
+			Router: v1alpha1.RouterSpec{
+				Replicas: ptr.To(int32(1)),
+				Tenancy: &v1alpha1.RouterTenancyConfig{
+					TenantHeader:    ptr.To("X-Scope-OrgID"),
+					TenantLabelName: ptr.To("tenant"),
+				},
+			},
+		},
+	}
+
+	router := receiverV1Alpha1ToRouterOptions(receiverV1Alpha1ToRouterTransformInput{CRD: crd})
+	var routerArgs []string
+	for _, obj := range router.Build() {
+		if d, ok := obj.(*appsv1.Deployment); ok {
+			routerArgs = d.Spec.Template.Spec.Containers[0].Args
+		}
+	}
+	if !slices.Contains(routerArgs, "--receive.tenant-header=X-Scope-OrgID") || !slices.Contains(routerArgs, "--receive.tenant-label-name=tenant") {
+		t.Errorf("expected the router to use the tenancy configuration, got %q", routerArgs)
+	}
+	if slices.ContainsFunc(routerArgs, func(arg string) bool { return strings.HasPrefix(arg, "--receive.default-tenant-id") }) {
+		t.Errorf("expected the Thanos default tenant to be kept, got %q", routerArgs)
+	}
+
+	hashring := v1alpha1.IngesterHashringSpec{Name: "hashring", Replicas: 1, StorageConfiguration: v1alpha1.StorageConfiguration{Size: "1Gi"}}
+	ingester := receiverV1Alpha1ToIngesterOptions(receiverV1Alpha1ToIngesterTransformInput{CRD: crd, Spec: hashring})
+	if ingester.TenancyOpts.TenantLabelName != "tenant" {
+		t.Errorf("expected an ingester without tenancy configuration to inherit the tenant label, got %q", ingester.TenancyOpts.TenantLabelName)
+	}
+
+	hashring.TenancyConfig = &v1alpha1.TenancyConfig{TenantLabelName: "tenant_id"}
+	ingester = receiverV1Alpha1ToIngesterOptions(receiverV1Alpha1ToIngesterTransformInput{CRD: crd, Spec: hashring})
+	if ingester.TenancyOpts.TenantLabelName != "tenant_id" {
+		t.Errorf("expected the tenancy configuration of the hashring to be used, got %q", ingester.TenancyOpts.TenantLabelName)
+	}
+}
+
+func TestReceiveLimitsToOpts(t *testing.T) {
+	if got := receiveLimitsToOpts(nil); got != nil {
+		t.Fatalf("expected no limits, got %v", got)
+	}
+
+	got := receiveLimitsToOpts(&v1alpha1.ReceiveLimitsSpec{
+		Global: &v1alpha1.GlobalWriteLimits{
+			MaxConcurrency:    ptr.To(int64(30)),
+			MetaMonitoringURL: ptr.To("http://prometheus:9090"),
+		},
+		Default: &v1alpha1.WriteLimits{SeriesLimit: ptr.To(int64(1000))},
+		Tenants: map[string]v1alpha1.WriteLimits{
+			"acme": {HeadSeriesLimit: ptr.To(int64(2000))},
+		},
+	})
+	if ptr.Deref(got.Global.MaxConcurrency, 0) != 30 || got.Global.MetaMonitoringURL != "http://prometheus:9090" {
+		t.Errorf("unexpected global limits %+v", got.Global)
+	}
+	if got.Default == nil || ptr.Deref(got.Default.SeriesLimit, 0) != 1000 {
+		t.Errorf("unexpected default limits %+v", got.Default)
+	}
+	if ptr.Deref(got.Tenants["acme"].HeadSeriesLimit, 0) != 2000 || got.Tenants["acme"].SeriesLimit != nil {
+		t.Errorf("unexpected tenant limits %+v", got.Tenants["acme"])
+	}
+}
+
+func TestQueryIngressOptions(t *testing.T) {
+	crd := v1alpha1.ThanosQuery{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"},
+		Spec: v1alpha1.ThanosQuerySpec{
+			Ingress: &v1alpha1.IngressConfig{Type: v1alpha1.IngressTypeIngress, Hosts: []string{"thanos.example.com"}},
+		},
+	}
+	querier := queryV1Alpha1ToOptions(queryV1Alpha1TransformInput{CRD: crd})
+	if querier.Ingress == nil || !slices.Equal(querier.Ingress.Hosts, crd.Spec.Ingress.Hosts) {
+		t.Errorf("expected the Querier to be exposed without a Query Frontend, got %+v", querier.Ingress)
+	}
+
+	crd.Spec.QueryFrontend = &v1alpha1.QueryFrontendSpec{Replicas: 1}
+	querier = queryV1Alpha1ToOptions(queryV1Alpha1TransformInput{CRD: crd})
+	frontend := queryV1Alpha1ToQueryFrontEndOptions(queryV1Alpha1ToQueryFrontEndTransformInput{CRD: crd})
+	if querier.Ingress != nil {
+		t.Errorf("expected the Querier not to be exposed with a Query Frontend, got %+v", querier.Ingress)
+	}
+	if frontend.Ingress == nil || frontend.Ingress.HTTPRoute {
+		t.Errorf("expected the Query Frontend to be exposed through an Ingress, got %+v", frontend.Ingress)
+	}
+}
+
+func TestQueryFederationOptions(t *testing.T) {
+	crd := v1alpha1.ThanosQuery{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"},
+		Spec: v1alpha1.ThanosQuerySpec{
+			Federation: &v1alpha1.QueryFederationSpec{Remotes: []v1alpha1.RemoteQueryEndpoint{
+				{Name: "eu", Address: "query.eu.example.com:10901", TLSSecret: ptr.To("federation-tls")},
+				{Name: "us", Address: "query.us.example.com:10901", TLSSecret: ptr.To("federation-tls"), Strict: true, Group: true},
+			}},
+		},
+	}
+	querier := queryV1Alpha1ToOptions(queryV1Alpha1TransformInput{CRD: crd})
+	expected := []manifestquery.RemoteEndpoint{
+		{Address: "query.eu.example.com:10901", Type: manifests.RegularLabel},
+		{Address: "query.us.example.com:10901", Type: manifests.GroupStrictLabel},
+	}
+	if !slices.Equal(querier.RemoteEndpoints, expected) {
+		t.Errorf("expected remote endpoints %v, got %v", expected, querier.RemoteEndpoints)
+	}
+	if tls := querier.GRPCClientTLS; tls == nil || tls.CertSecret != "federation-tls" || tls.CA == nil || tls.CA.Name != "federation-tls" || tls.CA.Key != "ca.crt" {
+		t.Errorf("expected the client certificate and CA to be read from the TLS Secret of the remotes, got %+v", tls)
+	}
+	if secrets := queryReferencedSecrets(crd); !slices.Equal(secrets, []string{"federation-tls"}) {
+		t.Errorf("expected the TLS Secret of the remotes to be referenced once, got %v", secrets)
+	}
+
+	crd.Spec.Federation.Remotes[0].TLSSecret = nil
+	crd.Spec.Federation.Remotes[1].TLSSecret = nil
+	if querier = queryV1Alpha1ToOptions(queryV1Alpha1TransformInput{CRD: crd}); querier.GRPCClientTLS != nil {
+		t.Errorf("expected the remotes to be dialed without TLS, got %+v", querier.GRPCClientTLS)
+	}
+}
+
+func TestQueryTuningOptions(t *testing.T) {
+	crd := v1alpha1.ThanosQuery{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"}}
+	querier := queryV1Alpha1ToOptions(queryV1Alpha1TransformInput{CRD: crd})
+	if querier.Timeout != "15m" || querier.LookbackDelta != "5m" || querier.MaxConcurrent != 20 || !querier.AutoDownsampling {
+		t.Errorf("expected the default query options, got %+v", querier)
+	}
+	if querier.PartialResponse != nil || querier.DeduplicationFunc != "" {
+		t.Errorf("expected the Thanos defaults for partial response and deduplication, got %+v", querier)
+	}
+
+	crd.Spec.Timeout = ptr.To(v1alpha1.Duration("2m"))
+	crd.Spec.LookbackDelta = ptr.To(v1alpha1.Duration("15m"))
+	crd.Spec.MaxConcurrent = ptr.To(int32(50))
+	crd.Spec.AutoDownsampling = ptr.To(false)
+	crd.Spec.PartialResponse = ptr.To(true)
+	crd.Spec.DeduplicationFunc = ptr.To("chain")
+	querier = queryV1Alpha1ToOptions(queryV1Alpha1TransformInput{CRD: crd})
+	if querier.Timeout != "2m" || querier.LookbackDelta != "15m" || querier.MaxConcurrent != 50 || querier.AutoDownsampling {
+		t.Errorf("expected the query options of the spec, got %+v", querier)
+	}
+	if !ptr.Deref(querier.PartialResponse, false) || querier.DeduplicationFunc != "chain" {
+		t.Errorf("expected partial response and chain deduplication, got %+v", querier)
+	}
+}
+
+func TestManagedCacheOptions(t *testing.T) {
+	store := v1alpha1.ThanosStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "ns"},
+		Spec: v1alpha1.ThanosStoreSpec{
+			StorageConfiguration: v1alpha1.StorageConfiguration{Size: "1Gi"},
+			IndexCacheConfig: &v1alpha1.CacheConfig{
+				InMemoryCacheConfig: &v1alpha1.InMemoryCacheConfig{MaxSize: ptr.To(v1alpha1.StorageSize("512MiB"))},
+			},
+		},
+	}
+	opts := storeV1Alpha1ToOptions(storeV1Alpha1TransformInput{CRD: store})
+	if opts.CachingBucketConfig.Memcached != nil || storeManagedCacheToOpts(store) != nil {
+		t.Errorf("expected no managed cache, got %+v", opts.CachingBucketConfig)
+	}
+
+	store.Spec.Cache = &v1alpha1.ManagedCacheSpec{Managed: ptr.To(true)}
+	cache := storeManagedCacheToOpts(store)
+	if cache == nil {
+		t.Fatal("expected a managed cache")
+	}
+	if cache.GetGeneratedResourceName() != "thanos-store-example-memcached" || cache.Replicas != 1 || cache.MemoryLimit != 1<<30 || cache.MaxItemSize != 1<<20 {
+		t.Errorf("expected the default managed cache options, got %+v", cache)
+	}
+	opts = storeV1Alpha1ToOptions(storeV1Alpha1TransformInput{CRD: store})
+	if opts.IndexCacheConfig.InMemoryCacheConfig == nil || opts.IndexCacheConfig.Memcached != nil {
+		t.Errorf("expected the in-memory index cache to be kept, got %+v", opts.IndexCacheConfig)
+	}
+	if opts.CachingBucketConfig.Memcached == nil || opts.CachingBucketConfig.Memcached.Addresses[0] != "dnssrv+_memcached._tcp.thanos-store-example-memcached.ns.svc" {
+		t.Errorf("expected the caching bucket to use the managed cache, got %+v", opts.CachingBucketConfig)
+	}
+
+	query := v1alpha1.ThanosQuery{
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "ns"},
+		Spec: v1alpha1.ThanosQuerySpec{
+			QueryFrontend: &v1alpha1.QueryFrontendSpec{Cache: &v1alpha1.ManagedCacheSpec{Managed: ptr.To(true)}},
+		},
+	}
+	frontend := queryV1Alpha1ToQueryFrontEndOptions(queryV1Alpha1ToQueryFrontEndTransformInput{CRD: query})
+	if frontend.ResponseCacheConfig.Memcached == nil || frontend.ResponseCacheConfig.Memcached.Addresses[0] != "dnssrv+_memcached._tcp.thanos-query-frontend-example-memcached.ns.svc" {
+		t.Errorf("expected the response cache to use the managed cache, got %+v", frontend.ResponseCacheConfig)
+	}
+}
+
+func TestReceiveNetworkPolicyOptions(t *testing.T) {
+	crd := v1alpha1.ThanosReceive{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"},
+		Spec: v1alpha1.ThanosReceiveSpec{
+			NetworkPolicy: &v1alpha1.ReceiveNetworkPolicySpec{
+				ObjectStorage: []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/8"}}},
+			},
+		},
+	}
+	ingester := receiverV1Alpha1ToIngesterOptions(receiverV1Alpha1ToIngesterTransformInput{CRD: crd, Spec: v1alpha1.IngesterHashringSpec{
+		Name:                 "default",
+		StorageConfiguration: v1alpha1.StorageConfiguration{Size: "1Gi"},
+	}})
+	if ingester.NetworkPolicy == nil || len(ingester.NetworkPolicy.ObjectStorage) != 1 {
+		t.Errorf("expected the ingester NetworkPolicy to allow egress to object storage, got %+v", ingester.NetworkPolicy)
+	}
+	router := receiverV1Alpha1ToRouterOptions(receiverV1Alpha1ToRouterTransformInput{CRD: crd})
+	if router.NetworkPolicy == nil || len(router.NetworkPolicy.RemoteWriteSources) != 0 {
+		t.Errorf("expected the router NetworkPolicy to allow remote writes from any source, got %+v", router.NetworkPolicy)
+	}
+
+	crd.Spec.NetworkPolicy = nil
+	if router := receiverV1Alpha1ToRouterOptions(receiverV1Alpha1ToRouterTransformInput{CRD: crd}); router.NetworkPolicy != nil {
+		t.Errorf("expected no router NetworkPolicy, got %+v", router.NetworkPolicy)
+	}
+}
+
+func TestRestartedAtOptions(t *testing.T) {
+	crd := v1alpha1.ThanosQuery{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"}}
+	if opts := queryV1Alpha1ToOptions(queryV1Alpha1TransformInput{CRD: crd}); opts.PodAnnotations != nil {
+		t.Errorf("expected no pod annotations, got %v", opts.PodAnnotations)
+	}
+
+	crd.Annotations = map[string]string{v1alpha1.RestartedAtAnnotation: "2026-01-02T03:04:05Z", "other": "value"}
+	opts := queryV1Alpha1ToOptions(queryV1Alpha1TransformInput{CRD: crd})
+	if len(opts.PodAnnotations) != 1 || opts.PodAnnotations[v1alpha1.RestartedAtAnnotation] != "2026-01-02T03:04:05Z" {
+		t.Errorf("expected the restart time to be added to the pod template, got %v", opts.PodAnnotations)
+	}
+	deployment := manifestquery.NewQueryDeployment(opts)
+	if got := deployment.Spec.Template.Annotations[v1alpha1.RestartedAtAnnotation]; got != "2026-01-02T03:04:05Z" {
+		t.Errorf("expected the pod template to carry the restart time, got %q", got)
+	}
+}
+
+func TestIngesterServiceAccountAnnotations(t *testing.T) {
+	crd := v1alpha1.ThanosReceive{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"}}
+	opts := receiverV1Alpha1ToIngesterOptions(receiverV1Alpha1ToIngesterTransformInput{
+		CRD: crd,
+		Spec: v1alpha1.IngesterHashringSpec{
+			CommonFields: v1alpha1.CommonFields{
+				ImagePullSecrets:          []corev1.LocalObjectReference{{Name: "registry"}},
+				ServiceAccountAnnotations: map[string]string{"a": "common", "b": "common"},
+			},
+			Name:                 "hashring",
+			Replicas:             1,
+			StorageConfiguration: v1alpha1.StorageConfiguration{Size: "1Gi"},
+			ServiceAccount:       &v1alpha1.ServiceAccountConfig{Annotations: map[string]string{"b": "hashring"}},
+		},
+	})
+
+	if got := opts.ServiceAccountAnnotations; got["a"] != "common" || got["b"] != "hashring" {
+		t.Errorf("expected the hashring ServiceAccount annotations to take precedence, got %v", got)
+	}
+	if len(opts.ImagePullSecrets) != 1 || opts.ImagePullSecrets[0].Name != "registry" {
+		t.Errorf("expected the image pull secrets to be set, got %v", opts.ImagePullSecrets)
+	}
+}
+
+func TestIngesterUpdateStrategy(t *testing.T) {
+	onDelete := &appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType}
+	crd := v1alpha1.ThanosReceive{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"},
+		Spec: v1alpha1.ThanosReceiveSpec{
+			StatefulSetFields: v1alpha1.StatefulSetFields{UpdateStrategy: onDelete},
+		},
+	}
+	hashring := v1alpha1.IngesterHashringSpec{
+		Name:                 "hashring",
+		Replicas:             3,
+		StorageConfiguration: v1alpha1.StorageConfiguration{Size: "1Gi"},
+	}
+
+	opts := receiverV1Alpha1ToIngesterOptions(receiverV1Alpha1ToIngesterTransformInput{CRD: crd, Spec: hashring})
+	if opts.StatefulSet.UpdateStrategy != onDelete {
+		t.Errorf("expected the update strategy of the ThanosReceive, got %v", opts.StatefulSet.UpdateStrategy)
+	}
+
+	hashring.UpdateStrategy = &appsv1.StatefulSetUpdateStrategy{
+		Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: ptr.To(int32(2))},
+	}
+	opts = receiverV1Alpha1ToIngesterOptions(receiverV1Alpha1ToIngesterTransformInput{CRD: crd, Spec: hashring})
+	sts := manifestreceive.NewIngestorStatefulSet(opts)
+	if sts.Spec.UpdateStrategy.RollingUpdate == nil || ptr.Deref(sts.Spec.UpdateStrategy.RollingUpdate.Partition, 0) != 2 {
+		t.Errorf("expected the update strategy of the hashring, got %v", sts.Spec.UpdateStrategy)
+	}
+
+	crd.Spec.Router.ReplicationFactor = 5
+	hashring.Replicas = 6
+	sts = manifestreceive.NewIngestorStatefulSet(receiverV1Alpha1ToIngesterOptions(receiverV1Alpha1ToIngesterTransformInput{CRD: crd, Spec: hashring}))
+	if got := sts.Spec.UpdateStrategy.RollingUpdate; got.MaxUnavailable == nil || got.MaxUnavailable.IntValue() != 2 || ptr.Deref(got.Partition, 0) != 2 {
+		t.Errorf("expected maxUnavailable derived from the replication factor, got %v", got)
+	}
+	if hashring.UpdateStrategy.RollingUpdate.MaxUnavailable != nil {
+		t.Errorf("expected the update strategy of the hashring to be unchanged")
+	}
+}
+
+func TestIngesterPVCRetentionPolicy(t *testing.T) {
+	crd := v1alpha1.ThanosReceive{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"},
+		Spec: v1alpha1.ThanosReceiveSpec{
+			StatefulSetFields: v1alpha1.StatefulSetFields{PersistentVolumeClaimRetentionPolicy: &v1alpha1.PersistentVolumeClaimRetentionPolicy{
+				WhenDeleted: v1alpha1.RetainPersistentVolumeClaimRetentionPolicyType,
+				WhenScaled:  v1alpha1.RetainPersistentVolumeClaimRetentionPolicyType,
+			}},
+		},
+	}
+	hashring := v1alpha1.IngesterHashringSpec{
+		Name:                 "hashring",
+		Replicas:             3,
+		StorageConfiguration: v1alpha1.StorageConfiguration{Size: "1Gi"},
+	}
+
+	sts := manifestreceive.NewIngestorStatefulSet(receiverV1Alpha1ToIngesterOptions(receiverV1Alpha1ToIngesterTransformInput{CRD: crd, Spec: hashring}))
+	if got := sts.Spec.PersistentVolumeClaimRetentionPolicy; got == nil || got.WhenScaled != appsv1.RetainPersistentVolumeClaimRetentionPolicyType {
+		t.Errorf("expected the retention policy of the ThanosReceive, got %v", got)
+	}
+
+	hashring.PersistentVolumeClaimRetentionPolicy = &v1alpha1.PersistentVolumeClaimRetentionPolicy{
+		WhenDeleted: v1alpha1.RetainPersistentVolumeClaimRetentionPolicyType,
+		WhenScaled:  v1alpha1.DeletePersistentVolumeClaimRetentionPolicyType,
+	}
+	sts = manifestreceive.NewIngestorStatefulSet(receiverV1Alpha1ToIngesterOptions(receiverV1Alpha1ToIngesterTransformInput{CRD: crd, Spec: hashring}))
+	if got := sts.Spec.PersistentVolumeClaimRetentionPolicy; got == nil || got.WhenScaled != appsv1.DeletePersistentVolumeClaimRetentionPolicyType || got.WhenDeleted != appsv1.RetainPersistentVolumeClaimRetentionPolicyType {
+		t.Errorf("expected the retention policy of the hashring, got %v", got)
+	}
+}
+
+func TestExternalReplicas(t *testing.T) {
+	crd := v1alpha1.ThanosQuery{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"},
+		Status: v1alpha1.ThanosQueryStatus{
+			Querier: v1alpha1.DeploymentStatus{Replicas: 4},
+		},
+	}
+	opts := queryV1Alpha1ToOptions(queryV1Alpha1TransformInput{CRD: crd})
+	if !opts.ExternalReplicas || opts.Replicas != 4 {
+		t.Errorf("expected externally managed replicas taken from the status, got %d", opts.Replicas)
+	}
+	for _, obj := range opts.Build() {
+		if d, ok := obj.(*appsv1.Deployment); ok && d.Spec.Replicas != nil {
+			t.Errorf("expected the Deployment replicas to be left unset, got %d", *d.Spec.Replicas)
+		}
+	}
+
+	crd.Spec.Replicas = ptr.To(int32(2))
+	opts = queryV1Alpha1ToOptions(queryV1Alpha1TransformInput{CRD: crd})
+	if opts.ExternalReplicas || opts.Replicas != 2 {
+		t.Errorf("expected the replicas of the spec, got %d", opts.Replicas)
+	}
+
+	receive := v1alpha1.ThanosReceive{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"}}
+	router := receiverV1Alpha1ToRouterOptions(receiverV1Alpha1ToRouterTransformInput{CRD: receive})
+	if !router.ExternalReplicas || router.Replicas != 1 {
+		t.Errorf("expected externally managed router replicas defaulting to one, got %d", router.Replicas)
+	}
+}
@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	manifestcompact "github.com/thanos-community/thanos-operator/internal/pkg/manifests/compact"
+	manifestquery "github.com/thanos-community/thanos-operator/internal/pkg/manifests/query"
+	manifestreceive "github.com/thanos-community/thanos-operator/internal/pkg/manifests/receive"
+	manifestruler "github.com/thanos-community/thanos-operator/internal/pkg/manifests/ruler"
+	manifestsstore "github.com/thanos-community/thanos-operator/internal/pkg/manifests/store"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// DefaultMinErrorBackoff and DefaultMaxErrorBackoff bound the delay before a resource that failed to reconcile
+	// is retried, unless tuned in the WorkqueueConfig.
+	DefaultMinErrorBackoff = 500 * time.Millisecond
+	DefaultMaxErrorBackoff = 5 * time.Minute
+	// errorBackoffJitter is the maximum fraction of the error backoff added as jitter.
+	errorBackoffJitter = 0.2
+	// resyncJitter is the maximum fraction of the resync interval added as jitter.
+	resyncJitter = 0.1
+)
+
+// WorkqueueConfig tunes how the requests of a controller are queued and processed.
+type WorkqueueConfig struct {
+	// MaxConcurrentReconciles is the number of resources reconciled concurrently. Defaults to 1.
+	MaxConcurrentReconciles int
+	// MinBackoff and MaxBackoff bound the exponential delay before a resource that failed to reconcile is retried.
+	// Default to DefaultMinErrorBackoff and DefaultMaxErrorBackoff.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// QPS and Burst limit the overall rate at which the resources that failed to reconcile are retried.
+	// Zero QPS disables the limit.
+	QPS   float64
+	Burst int
+}
+
+// controllerOptions returns the options of a controller of the Thanos resources.
+func (c WorkqueueConfig) controllerOptions() controller.Options {
+	return controller.Options{
+		MaxConcurrentReconciles: c.MaxConcurrentReconciles,
+		RateLimiter:             c.newRateLimiter(),
+	}
+}
+
+// newRateLimiter returns the rate limiter of the requests that failed to reconcile.
+func (c WorkqueueConfig) newRateLimiter() workqueue.TypedRateLimiter[reconcile.Request] {
+	minBackoff, maxBackoff := c.MinBackoff, c.MaxBackoff
+	if minBackoff <= 0 {
+		minBackoff = DefaultMinErrorBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultMaxErrorBackoff
+	}
+	var limiter workqueue.TypedRateLimiter[reconcile.Request] = &jitteredRateLimiter{
+		TypedRateLimiter: workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](minBackoff, maxBackoff),
+	}
+	if c.QPS > 0 {
+		limiter = workqueue.NewTypedMaxOfRateLimiter(limiter, &workqueue.TypedBucketRateLimiter[reconcile.Request]{
+			Limiter: rate.NewLimiter(rate.Limit(c.QPS), max(c.Burst, 1)),
+		})
+	}
+	return limiter
+}
+
+// jitteredRateLimiter retries the resources that failed to reconcile with an exponential backoff and a jitter,
+// so that resources failing together, for example during an API server outage, are not retried together.
+type jitteredRateLimiter struct {
+	workqueue.TypedRateLimiter[reconcile.Request]
+}
+
+// When returns the delay before the resource is retried and records the failure.
+func (l *jitteredRateLimiter) When(item reconcile.Request) time.Duration {
+	return wait.Jitter(l.TypedRateLimiter.When(item), errorBackoffJitter)
+}
+
+// concurrencyControllers are the names of the controllers whose concurrency can be set with a ConcurrencyFlag.
+var concurrencyControllers = []string{manifestcompact.Name, manifestquery.Name, manifestreceive.Name, manifestruler.Name, manifestsstore.Name}
+
+// ConcurrencyFlag implements flag.Value for repeatable flags of the form [<controller>=]<n>, setting the number of
+// resources reconciled concurrently by the named controller, or by all the controllers if the name is omitted.
+type ConcurrencyFlag struct {
+	all           int
+	perController map[string]int
+}
+
+// String returns a comma-separated list of the configured concurrencies.
+func (f *ConcurrencyFlag) String() string {
+	var values []string
+	if f.all > 0 {
+		values = append(values, strconv.Itoa(f.all))
+	}
+	for name, n := range f.perController {
+		values = append(values, fmt.Sprintf("%s=%d", name, n))
+	}
+	sort.Strings(values)
+	return strings.Join(values, ",")
+}
+
+// Set records a concurrency after validating the flag value.
+func (f *ConcurrencyFlag) Set(value string) error {
+	name, count, ok := strings.Cut(value, "=")
+	if !ok {
+		name, count = "", value
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 1 {
+		return fmt.Errorf("invalid concurrency %q, expected a positive integer", count)
+	}
+	if name == "" {
+		f.all = n
+		return nil
+	}
+	if !slices.Contains(concurrencyControllers, name) {
+		return fmt.Errorf("unknown controller %q, must be one of: %s", name, strings.Join(concurrencyControllers, ", "))
+	}
+	if f.perController == nil {
+		f.perController = map[string]int{}
+	}
+	f.perController[name] = n
+	return nil
+}
+
+// For returns the number of resources reconciled concurrently by the named controller.
+func (f *ConcurrencyFlag) For(name string) int {
+	if n, ok := f.perController[name]; ok {
+		return n
+	}
+	return max(f.all, 1)
+}
@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestErrorRateLimiter(t *testing.T) {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "test"}}
+	within := func(got, base time.Duration) bool {
+		return got >= base && got <= base+time.Duration(float64(base)*errorBackoffJitter)
+	}
+
+	limiter := WorkqueueConfig{}.newRateLimiter()
+	if got := limiter.When(req); !within(got, DefaultMinErrorBackoff) {
+		t.Errorf("expected the first retry after %s with jitter, got %s", DefaultMinErrorBackoff, got)
+	}
+	if got := limiter.When(req); !within(got, 2*DefaultMinErrorBackoff) {
+		t.Errorf("expected the second retry after %s with jitter, got %s", 2*DefaultMinErrorBackoff, got)
+	}
+	for range 20 {
+		limiter.When(req)
+	}
+	if got := limiter.When(req); !within(got, DefaultMaxErrorBackoff) {
+		t.Errorf("expected the backoff to be capped at %s with jitter, got %s", DefaultMaxErrorBackoff, got)
+	}
+
+	limiter.Forget(req)
+	if limiter.NumRequeues(req) != 0 {
+		t.Errorf("expected the failures to be forgotten")
+	}
+	if got := limiter.When(req); !within(got, DefaultMinErrorBackoff) {
+		t.Errorf("expected the backoff to restart after a success, got %s", got)
+	}
+
+	limiter = WorkqueueConfig{MinBackoff: time.Second, MaxBackoff: 2 * time.Second}.newRateLimiter()
+	limiter.When(req)
+	limiter.When(req)
+	if got := limiter.When(req); !within(got, 2*time.Second) {
+		t.Errorf("expected the tuned backoff to be capped at 2s with jitter, got %s", got)
+	}
+
+	limiter = WorkqueueConfig{QPS: 1, Burst: 1}.newRateLimiter()
+	limiter.When(req)
+	other := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "other"}}
+	if got := limiter.When(other); got < 500*time.Millisecond {
+		t.Errorf("expected the retries to be limited to 1 per second, got a delay of %s", got)
+	}
+}
+
+func TestConcurrencyFlag(t *testing.T) {
+	f := ConcurrencyFlag{}
+	if got := f.For("thanos-receive"); got != 1 {
+		t.Errorf("expected a default concurrency of 1, got %d", got)
+	}
+
+	for _, v := range []string{"4", "thanos-receive" + "=8"} {
+		if err := f.Set(v); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if got := f.For("thanos-receive"); got != 8 {
+		t.Errorf("expected the controller concurrency to be used, got %d", got)
+	}
+	if got := f.For("thanos-query"); got != 4 {
+		t.Errorf("expected the concurrency of all controllers to be used, got %d", got)
+	}
+	if got, want := f.String(), "4,thanos-receive=8"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	for value, errContains := range map[string]string{
+		"0":                "invalid concurrency",
+		"thanos-receive=x": "invalid concurrency",
+		"unknown=2":        "unknown controller",
+	} {
+		if err := f.Set(value); err == nil || !strings.Contains(err.Error(), errContains) {
+			t.Errorf("Set(%q): expected error containing %q, got %v", value, errContains, err)
+		}
+	}
+}
@@ -0,0 +1,355 @@
+// Package describe prints human-readable summaries of Thanos resources from their status, their generated
+// objects and their events, for the kubectl thanos plugin.
+package describe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+	manifestreceive "github.com/thanos-community/thanos-operator/internal/pkg/manifests/receive"
+	"github.com/thanos-community/thanos-operator/internal/pkg/receive"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MaxEvents is the number of most recent events printed for a resource.
+const MaxEvents = 10
+
+// kinds maps the names a kind can be given by to the function describing its resources.
+var kinds = map[string]func(context.Context, client.Client, io.Writer, types.NamespacedName, time.Time) error{
+	"receive":       describeReceive,
+	"thanosreceive": describeReceive,
+	"query":         describeQuery,
+	"thanosquery":   describeQuery,
+}
+
+// Kinds returns the names of the kinds that can be described.
+func Kinds() []string {
+	names := make([]string, 0, len(kinds))
+	for name := range kinds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Describe writes a summary of the resource of the given kind to w. Ages are relative to now.
+func Describe(ctx context.Context, c client.Client, w io.Writer, kind string, key types.NamespacedName, now time.Time) error {
+	describe, ok := kinds[strings.ToLower(kind)]
+	if !ok {
+		return fmt.Errorf("unknown kind %q, available kinds: %s", kind, strings.Join(Kinds(), ", "))
+	}
+	return describe(ctx, c, w, key, now)
+}
+
+func describeReceive(ctx context.Context, c client.Client, w io.Writer, key types.NamespacedName, now time.Time) error {
+	obj := &v1alpha1.ThanosReceive{}
+	if err := c.Get(ctx, key, obj); err != nil {
+		return fmt.Errorf("failed to get ThanosReceive %s: %w", key, err)
+	}
+	hashrings, err := getHashrings(ctx, c, obj)
+	if err != nil {
+		return err
+	}
+	events, err := getEvents(ctx, c, obj)
+	if err != nil {
+		return err
+	}
+
+	status := obj.Status
+	p := newPrinter(w, now)
+	p.header("ThanosReceive", &obj.ObjectMeta, status.ObservedGeneration, status.Paused)
+
+	p.section("Router")
+	p.row("", "READY", "UPDATED", "AVAILABLE")
+	p.deployment("router", status.Router)
+	groups := sortedKeys(status.RouterGroupStatus)
+	for _, group := range groups {
+		p.deployment(group, status.RouterGroupStatus[group])
+	}
+	p.flush()
+
+	p.section("Hashring Configuration")
+	p.field("Version", fmt.Sprint(status.HashringConfigVersion))
+	p.field("Hash", orNone(status.HashringConfigHash))
+	p.field("Updated", p.age(status.HashringConfigUpdateTime))
+	if status.RouterHashringConfigHash != "" {
+		p.field("Rolled Out Hash", status.RouterHashringConfigHash)
+	}
+	if rollout := status.Rollout; rollout != nil {
+		progress := fmt.Sprintf("%s, pending %s", rollout.Hashring, orNone(strings.Join(rollout.Pending, ", ")))
+		if rollout.Paused {
+			progress += " (paused)"
+		}
+		p.field("Rollout", progress)
+	}
+	p.flush()
+
+	p.section("Hashrings")
+	p.row("NAME", "READY", "UPDATED", "UPLOAD LAG", "TENANTS", "ENDPOINTS")
+	names := sortedKeys(status.HashringStatus)
+	for _, h := range hashrings {
+		if _, ok := status.HashringStatus[h.Name]; !ok {
+			names = append(names, h.Name)
+		}
+	}
+	for _, name := range names {
+		ss := status.HashringStatus[name]
+		lag := "<unknown>"
+		if d, ok := status.UploadLag[name]; ok {
+			lag = duration.HumanDuration(d.Duration)
+		}
+		config := findHashring(hashrings, name)
+		tenants := "*"
+		if len(config.Tenants) > 0 {
+			tenants = strings.Join(config.Tenants, ",")
+		}
+		p.row(name, fmt.Sprintf("%d/%d", ss.ReadyReplicas, ss.Replicas), fmt.Sprint(ss.UpdatedReplicas), lag, tenants, fmt.Sprint(len(config.Endpoints)))
+	}
+	p.flush()
+
+	p.section("Hashring Endpoints")
+	p.row("HASHRING", "ADDRESS", "AZ")
+	for _, h := range hashrings {
+		for i, ep := range h.Endpoints {
+			name := h.Name
+			if i > 0 {
+				name = ""
+			}
+			p.row(name, ep.Address, ep.AZ)
+		}
+	}
+	p.flush()
+
+	p.conditions(status.Conditions)
+	p.events(events)
+	return p.err
+}
+
+func describeQuery(ctx context.Context, c client.Client, w io.Writer, key types.NamespacedName, now time.Time) error {
+	obj := &v1alpha1.ThanosQuery{}
+	if err := c.Get(ctx, key, obj); err != nil {
+		return fmt.Errorf("failed to get ThanosQuery %s: %w", key, err)
+	}
+	events, err := getEvents(ctx, c, obj)
+	if err != nil {
+		return err
+	}
+
+	status := obj.Status
+	p := newPrinter(w, now)
+	p.header("ThanosQuery", &obj.ObjectMeta, status.ObservedGeneration, status.Paused)
+
+	p.section("Workloads")
+	p.row("", "READY", "UPDATED", "AVAILABLE")
+	p.deployment("querier", status.Querier)
+	if obj.Spec.QueryFrontend != nil {
+		p.deployment("query-frontend", status.QueryFrontend)
+	}
+	p.flush()
+
+	p.section(fmt.Sprintf("StoreAPI Endpoints (%d)", status.EndpointCount))
+	p.row("SERVICE", "TYPE")
+	for _, ep := range status.Endpoints {
+		p.row(ep.Name, ep.Type)
+	}
+	p.flush()
+
+	if len(status.Remotes) > 0 {
+		p.section("Remotes")
+		p.row("NAME", "ADDRESS", "REACHABLE", "CHECKED", "MESSAGE")
+		for _, remote := range status.Remotes {
+			p.row(remote.Name, remote.Address, fmt.Sprint(remote.Reachable), p.age(&remote.LastCheckTime), remote.Message)
+		}
+		p.flush()
+	}
+
+	p.conditions(status.Conditions)
+	p.events(events)
+	return p.err
+}
+
+// getHashrings returns the hashrings the routers of the ThanosReceive read, from the ConfigMap set in the spec
+// or otherwise from the ConfigMap generated for the resource. It returns no hashrings if the ConfigMap is missing.
+func getHashrings(ctx context.Context, c client.Client, obj *v1alpha1.ThanosReceive) (receive.Hashrings, error) {
+	var data string
+	if name := obj.Spec.Router.ExistingHashringConfigMap; name != nil {
+		cm := &corev1.ConfigMap{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: obj.Namespace, Name: *name}, cm); client.IgnoreNotFound(err) != nil {
+			return nil, fmt.Errorf("failed to get the hashring ConfigMap: %w", err)
+		}
+		data = cm.Data[manifestreceive.HashringConfigKey]
+	} else {
+		cms := &corev1.ConfigMapList{}
+		if err := c.List(ctx, cms, client.InNamespace(obj.Namespace), manifests.GetLabelSelectorForOwnerUID(obj.UID)); err != nil {
+			return nil, fmt.Errorf("failed to list the ConfigMaps of the ThanosReceive: %w", err)
+		}
+		for _, cm := range cms.Items {
+			if d, ok := cm.Data[manifestreceive.HashringConfigKey]; ok {
+				data = d
+				break
+			}
+		}
+	}
+	if data == "" {
+		return nil, nil
+	}
+
+	var hashrings receive.Hashrings
+	if err := json.Unmarshal([]byte(data), &hashrings); err != nil {
+		return nil, fmt.Errorf("invalid hashring configuration: %w", err)
+	}
+	return hashrings, nil
+}
+
+func findHashring(hashrings receive.Hashrings, name string) receive.HashringConfig {
+	for _, h := range hashrings {
+		if h.Name == name {
+			return h
+		}
+	}
+	return receive.HashringConfig{}
+}
+
+// getEvents returns the most recent events regarding the object, oldest first.
+func getEvents(ctx context.Context, c client.Client, obj client.Object) ([]eventsv1.Event, error) {
+	list := &eventsv1.EventList{}
+	if err := c.List(ctx, list, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	events := slices.DeleteFunc(list.Items, func(e eventsv1.Event) bool {
+		return e.Regarding.UID != obj.GetUID()
+	})
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTime(events[i]).Before(eventTime(events[j]))
+	})
+	if len(events) > MaxEvents {
+		events = events[len(events)-MaxEvents:]
+	}
+	return events, nil
+}
+
+// eventTime returns when the event was last observed.
+func eventTime(e eventsv1.Event) time.Time {
+	switch {
+	case e.Series != nil:
+		return e.Series.LastObservedTime.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	case !e.DeprecatedLastTimestamp.IsZero():
+		return e.DeprecatedLastTimestamp.Time
+	}
+	return e.CreationTimestamp.Time
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
+
+// printer writes the sections of a summary, keeping the first write error.
+type printer struct {
+	tw  *tabwriter.Writer
+	now time.Time
+	err error
+}
+
+func newPrinter(w io.Writer, now time.Time) *printer {
+	return &printer{tw: tabwriter.NewWriter(w, 0, 8, 2, ' ', 0), now: now}
+}
+
+func (p *printer) printf(format string, args ...any) {
+	if p.err == nil {
+		_, p.err = fmt.Fprintf(p.tw, format, args...)
+	}
+}
+
+func (p *printer) flush() {
+	if p.err == nil {
+		p.err = p.tw.Flush()
+	}
+}
+
+func (p *printer) header(kind string, meta *metav1.ObjectMeta, observedGeneration int64, paused *bool) {
+	p.printf("Name:\t%s\n", meta.Name)
+	p.printf("Namespace:\t%s\n", meta.Namespace)
+	p.printf("Kind:\t%s\n", kind)
+	p.printf("Generation:\t%d (observed %d)\n", meta.Generation, observedGeneration)
+	if paused != nil && *paused {
+		p.printf("Paused:\ttrue\n")
+	}
+	p.flush()
+}
+
+func (p *printer) section(title string) {
+	p.printf("\n%s:\n", title)
+}
+
+func (p *printer) field(name, value string) {
+	p.printf("  %s:\t%s\n", name, value)
+}
+
+func (p *printer) row(columns ...string) {
+	p.printf("  %s\n", strings.Join(columns, "\t"))
+}
+
+func (p *printer) deployment(name string, status v1alpha1.DeploymentStatus) {
+	p.row(name, fmt.Sprintf("%d/%d", status.ReadyReplicas, status.Replicas), fmt.Sprint(status.UpdatedReplicas), fmt.Sprint(status.AvailableReplicas))
+}
+
+func (p *printer) conditions(conditions []metav1.Condition) {
+	p.section("Conditions")
+	p.row("TYPE", "STATUS", "REASON", "AGE", "MESSAGE")
+	for _, cond := range conditions {
+		p.row(cond.Type, string(cond.Status), cond.Reason, p.age(&cond.LastTransitionTime), cond.Message)
+	}
+	p.flush()
+}
+
+func (p *printer) events(events []eventsv1.Event) {
+	p.section("Events")
+	if len(events) == 0 {
+		p.printf("  <none>\n")
+		return
+	}
+	p.row("LAST SEEN", "TYPE", "REASON", "MESSAGE")
+	for _, e := range events {
+		t := metav1.NewTime(eventTime(e))
+		p.row(p.age(&t), e.Type, e.Reason, e.Note)
+	}
+	p.flush()
+}
+
+// age returns how long ago the time was, or <unknown> if it is not set.
+func (p *printer) age(t *metav1.Time) string {
+	if t == nil || t.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(p.now.Sub(t.Time)) + " ago"
+}
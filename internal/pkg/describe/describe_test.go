@@ -0,0 +1,138 @@
+package describe
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+	manifestreceive "github.com/thanos-community/thanos-operator/internal/pkg/manifests/receive"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func event(name string, regarding types.UID, at time.Time, reason string) *eventsv1.Event {
+	return &eventsv1.Event{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+		Regarding:  corev1.ObjectReference{UID: regarding},
+		EventTime:  metav1.NewMicroTime(at),
+		Type:       corev1.EventTypeNormal,
+		Reason:     reason,
+		Note:       reason + " note",
+	}
+}
+
+func TestDescribeReceive(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	receive := &v1alpha1.ThanosReceive{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "example", UID: "uid", Generation: 2},
+		Status: v1alpha1.ThanosReceiveStatus{
+			ObservedGeneration:       2,
+			Router:                   v1alpha1.DeploymentStatus{Replicas: 2, ReadyReplicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2},
+			RouterGroupStatus:        map[string]v1alpha1.DeploymentStatus{"team-a": {Replicas: 1}},
+			HashringStatus:           map[string]v1alpha1.StatefulSetStatus{"default": {Replicas: 2, ReadyReplicas: 1, UpdatedReplicas: 2}},
+			UploadLag:                map[string]metav1.Duration{"default": {Duration: 5 * time.Minute}},
+			HashringConfigHash:       "abc123",
+			HashringConfigVersion:    4,
+			HashringConfigUpdateTime: &metav1.Time{Time: now.Add(-time.Hour)},
+			Conditions: []metav1.Condition{
+				{Type: "Ready", Status: metav1.ConditionFalse, Reason: "HashringNotReady", Message: "1 of 2 ingesters ready", LastTransitionTime: metav1.NewTime(now.Add(-2 * time.Minute))},
+			},
+		},
+	}
+	hashrings := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "hashrings", Labels: map[string]string{manifests.OwnerUIDLabel: "uid"}},
+		Data: map[string]string{
+			manifestreceive.HashringConfigKey: `[{"hashring":"default","tenants":["a","b"],"endpoints":[{"address":"ingester-0:10901"},{"address":"ingester-1:10901"}]}]`,
+		},
+	}
+	c := newClient(t, receive, hashrings,
+		event("old", "uid", now.Add(-3*time.Minute), "HashringUpdated"),
+		event("new", "uid", now.Add(-time.Minute), "RolloutComplete"),
+		event("other", "other-uid", now, "Unrelated"),
+	)
+
+	var out bytes.Buffer
+	if err := Describe(context.Background(), c, &out, "ThanosReceive", types.NamespacedName{Namespace: "ns", Name: "example"}, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"Generation:  2 (observed 2)",
+		"team-a",
+		"Version:  4",
+		"Hash:     abc123",
+		"Updated:  60m ago",
+		"default  1/2    2        5m          a,b      2",
+		"default   ingester-0:10901",
+		"          ingester-1:10901",
+		"HashringNotReady",
+		"1 of 2 ingesters ready",
+		"RolloutComplete note",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected the summary to contain %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Unrelated") {
+		t.Errorf("expected only the events of the resource, got:\n%s", got)
+	}
+	if strings.Index(got, "HashringUpdated") > strings.Index(got, "RolloutComplete") {
+		t.Errorf("expected the events oldest first, got:\n%s", got)
+	}
+}
+
+func TestDescribeQuery(t *testing.T) {
+	query := &v1alpha1.ThanosQuery{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "example", UID: "uid"},
+		Status: v1alpha1.ThanosQueryStatus{
+			EndpointCount: 2,
+			Endpoints: []v1alpha1.QueryEndpointStatus{
+				{Name: "thanos-store-example", Type: "strict"},
+				{Name: "thanos-receive-ingester-example-default", Type: "dynamic"},
+			},
+		},
+	}
+	c := newClient(t, query)
+
+	var out bytes.Buffer
+	if err := Describe(context.Background(), c, &out, "query", types.NamespacedName{Namespace: "ns", Name: "example"}, time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{"StoreAPI Endpoints (2)", "thanos-store-example", "strict", "Events:\n  <none>"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected the summary to contain %q, got:\n%s", want, got)
+		}
+	}
+
+	if err := Describe(context.Background(), c, &out, "store", types.NamespacedName{Namespace: "ns", Name: "example"}, time.Now()); err == nil {
+		t.Error("expected an error for an unsupported kind")
+	}
+	if err := Describe(context.Background(), c, &out, "query", types.NamespacedName{Namespace: "ns", Name: "missing"}, time.Now()); err == nil {
+		t.Error("expected an error for a missing resource")
+	}
+}
@@ -22,6 +22,14 @@ const (
 	// KubeResourceSync enables the kube-resource-sync sidecar for immediate ConfigMap/Secret synchronization.
 	// See https://github.com/philipgough/kube-resource-sync
 	KubeResourceSync = "kube-resource-sync"
+
+	// GatewayAPI enables management of Gateway API HTTPRoute objects exposing Thanos components.
+	// See https://gateway-api.sigs.k8s.io/api-types/httproute/
+	GatewayAPI = "gateway-api"
+
+	// MonitoringMixin enables publishing of Grafana dashboard ConfigMaps and PrometheusRule alerts
+	// for the Thanos components managed by the operator.
+	MonitoringMixin = "monitoring-mixin"
 )
 
 // AllFeatures returns a slice of all available feature flag names.
@@ -32,6 +40,8 @@ func AllFeatures() []string {
 		PrometheusRule,
 		KubeResourceSync,
 		OtelSidecar,
+		GatewayAPI,
+		MonitoringMixin,
 	}
 }
 
@@ -58,6 +68,10 @@ type Config struct {
 	EnableKubeResourceSync bool
 	// KubeResourceSyncImage specifies the image to use for the kube-resource-sync sidecar.
 	KubeResourceSyncImage string
+	// EnableGatewayAPI enables the management of Gateway API HTTPRoute objects.
+	EnableGatewayAPI bool
+	// EnableMonitoringMixin enables the publishing of Grafana dashboards and PrometheusRule alerts.
+	EnableMonitoringMixin bool
 }
 
 // ServiceMonitorEnabled returns true if ServiceMonitor management is enabled.
@@ -85,6 +99,16 @@ func (c Config) GetKubeResourceSyncImage() string {
 	return c.KubeResourceSyncImage
 }
 
+// GatewayAPIEnabled returns true if HTTPRoute management is enabled.
+func (c Config) GatewayAPIEnabled() bool {
+	return c.EnableGatewayAPI
+}
+
+// MonitoringMixinEnabled returns true if dashboards and alerts are published.
+func (c Config) MonitoringMixinEnabled() bool {
+	return c.EnableMonitoringMixin
+}
+
 // ToFeatureGate converts a Flag to a Config struct for use by controllers.
 func (f *Flag) ToFeatureGate() Config {
 	return Config{
@@ -92,6 +116,8 @@ func (f *Flag) ToFeatureGate() Config {
 		EnablePrometheusRuleDiscovery: f.EnablesPrometheusRule(),
 		EnableOtelSidecar:             f.EnablesOtelSidecar(),
 		EnableKubeResourceSync:        f.EnablesKubeResourceSync(),
+		EnableGatewayAPI:              f.EnablesGatewayAPI(),
+		EnableMonitoringMixin:         f.EnablesMonitoringMixin(),
 	}
 }
 
@@ -105,12 +131,20 @@ func (c Config) ToGVK() []schema.GroupVersionKind {
 			Kind:    "ServiceMonitor",
 		})
 	}
-	if !c.EnablePrometheusRuleDiscovery {
+	// PrometheusRules are both discovered by the ruler and published by the monitoring mixin
+	if !c.EnablePrometheusRuleDiscovery && !c.EnableMonitoringMixin {
 		gvk = append(gvk, schema.GroupVersionKind{
 			Group:   "monitoring.coreos.com",
 			Version: "v1",
 			Kind:    "PrometheusRule",
 		})
 	}
+	if !c.EnableGatewayAPI {
+		gvk = append(gvk, schema.GroupVersionKind{
+			Group:   "gateway.networking.k8s.io",
+			Version: "v1",
+			Kind:    "HTTPRoute",
+		})
+	}
 	return gvk
 }
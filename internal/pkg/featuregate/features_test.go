@@ -14,6 +14,8 @@ func TestAllFeatures(t *testing.T) {
 		PrometheusRule,
 		OtelSidecar,
 		KubeResourceSync,
+		GatewayAPI,
+		MonitoringMixin,
 	}
 	got := AllFeatures()
 	slices.Sort(expected)
@@ -108,11 +110,13 @@ func TestFlag_ToFeatureGate(t *testing.T) {
 		},
 		{
 			name:     "all features enabled",
-			features: []string{ServiceMonitor, PrometheusRule, OtelSidecar},
+			features: []string{ServiceMonitor, PrometheusRule, OtelSidecar, GatewayAPI, MonitoringMixin},
 			want: Config{
 				EnableServiceMonitor:          true,
 				EnablePrometheusRuleDiscovery: true,
 				EnableOtelSidecar:             true,
+				EnableGatewayAPI:              true,
+				EnableMonitoringMixin:         true,
 			},
 		},
 	}
@@ -52,3 +52,13 @@ func (f *Flag) EnablesOtelSidecar() bool {
 func (f *Flag) EnablesKubeResourceSync() bool {
 	return f.Contains(KubeResourceSync)
 }
+
+// EnablesGatewayAPI returns true if Gateway API features should be enabled.
+func (f *Flag) EnablesGatewayAPI() bool {
+	return f.Contains(GatewayAPI)
+}
+
+// EnablesMonitoringMixin returns true if dashboards and alerts should be published.
+func (f *Flag) EnablesMonitoringMixin() bool {
+	return f.Contains(MonitoringMixin)
+}
@@ -201,6 +201,80 @@ func TestFlag_EnablesOtelSidecar(t *testing.T) {
 	}
 }
 
+func TestFlag_EnablesGatewayAPI(t *testing.T) {
+	tests := []struct {
+		name     string
+		features []string
+		want     bool
+	}{
+		{
+			name:     "no features",
+			features: []string{},
+			want:     false,
+		},
+		{
+			name:     "gateway-api enables gateway api",
+			features: []string{GatewayAPI},
+			want:     true,
+		},
+		{
+			name:     "service-monitor does not enable gateway api",
+			features: []string{ServiceMonitor},
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Flag{}
+			for _, feature := range tt.features {
+				_ = f.Set(feature)
+			}
+
+			if got := f.EnablesGatewayAPI(); got != tt.want {
+				t.Errorf("Flag.EnablesGatewayAPI() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlag_EnablesMonitoringMixin(t *testing.T) {
+	tests := []struct {
+		name     string
+		features []string
+		want     bool
+	}{
+		{
+			name:     "no features",
+			features: []string{},
+			want:     false,
+		},
+		{
+			name:     "monitoring-mixin enables monitoring mixin",
+			features: []string{MonitoringMixin},
+			want:     true,
+		},
+		{
+			name:     "service-monitor does not enable monitoring mixin",
+			features: []string{ServiceMonitor},
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Flag{}
+			for _, feature := range tt.features {
+				_ = f.Set(feature)
+			}
+
+			if got := f.EnablesMonitoringMixin(); got != tt.want {
+				t.Errorf("Flag.EnablesMonitoringMixin() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestFlag_String(t *testing.T) {
 	f := &Flag{}
 	_ = f.Set(ServiceMonitor)
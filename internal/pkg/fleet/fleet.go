@@ -0,0 +1,134 @@
+// Package fleet applies maintenance actions to a fleet of Thanos resources selected by labels,
+// through the annotations the controllers act upon.
+package fleet
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Action is a maintenance action applied to Thanos resources.
+type Action string
+
+const (
+	// ActionRestart restarts the pods of the resources by setting v1alpha1.RestartedAtAnnotation.
+	ActionRestart Action = "restart"
+	// ActionPause pauses the reconciliation of the resources by setting v1alpha1.PausedAnnotation.
+	ActionPause Action = "pause"
+	// ActionResume resumes the reconciliation of the resources paused with ActionPause.
+	ActionResume Action = "resume"
+	// ActionResync reconciles the resources by setting v1alpha1.ResyncRequestedAtAnnotation.
+	ActionResync Action = "resync"
+)
+
+// Actions are the supported actions.
+var Actions = []Action{ActionRestart, ActionPause, ActionResume, ActionResync}
+
+// kinds maps the lowercase kinds of the Thanos resources to constructors of their lists.
+var kinds = map[string]func() client.ObjectList{
+	"thanoscompact": func() client.ObjectList { return &v1alpha1.ThanosCompactList{} },
+	"thanosquery":   func() client.ObjectList { return &v1alpha1.ThanosQueryList{} },
+	"thanosreceive": func() client.ObjectList { return &v1alpha1.ThanosReceiveList{} },
+	"thanosruler":   func() client.ObjectList { return &v1alpha1.ThanosRulerList{} },
+	"thanosstore":   func() client.ObjectList { return &v1alpha1.ThanosStoreList{} },
+}
+
+// Kinds returns the lowercase kinds of the Thanos resources that actions apply to.
+func Kinds() []string {
+	names := make([]string, 0, len(kinds))
+	for name := range kinds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Selection selects the resources an action is applied to.
+type Selection struct {
+	// Selector selects the resources by label. All resources are selected if nil.
+	Selector labels.Selector
+	// Namespace restricts the selection to a namespace. Resources in all namespaces are selected if empty.
+	Namespace string
+	// Kinds restricts the selection to the given lowercase kinds. All the kinds are selected if empty.
+	Kinds []string
+}
+
+// Result is the outcome of an action on a resource.
+type Result struct {
+	// Resource identifies the resource as <kind>/<namespace>/<name>.
+	Resource string
+	// Err is the reason the action failed, if it did.
+	Err error
+}
+
+// Apply applies the action to the selected resources. The time is recorded in the annotations of the actions
+// that are triggered by a change of value. It returns an error if the selection is invalid or cannot be listed.
+func Apply(ctx context.Context, c client.Client, action Action, sel Selection, now time.Time) ([]Result, error) {
+	if !slices.Contains(Actions, action) {
+		return nil, fmt.Errorf("unknown action %q", action)
+	}
+	selected := sel.Kinds
+	if len(selected) == 0 {
+		selected = Kinds()
+	}
+
+	listOpts := []client.ListOption{client.InNamespace(sel.Namespace)}
+	if sel.Selector != nil {
+		listOpts = append(listOpts, client.MatchingLabelsSelector{Selector: sel.Selector})
+	}
+
+	var results []Result
+	for _, kind := range selected {
+		newList, ok := kinds[strings.ToLower(kind)]
+		if !ok {
+			return nil, fmt.Errorf("unknown kind %q, must be one of: %s", kind, strings.Join(Kinds(), ", "))
+		}
+		list := newList()
+		if err := c.List(ctx, list, listOpts...); err != nil {
+			return nil, fmt.Errorf("failed to list %s resources: %w", kind, err)
+		}
+		objs, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range objs {
+			obj := o.(client.Object)
+			results = append(results, Result{
+				Resource: fmt.Sprintf("%s/%s/%s", strings.ToLower(kind), obj.GetNamespace(), obj.GetName()),
+				Err:      apply(ctx, c, action, obj, now),
+			})
+		}
+	}
+	return results, nil
+}
+
+func apply(ctx context.Context, c client.Client, action Action, obj client.Object, now time.Time) error {
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	switch action {
+	case ActionRestart:
+		annotations[v1alpha1.RestartedAtAnnotation] = now.UTC().Format(time.RFC3339)
+	case ActionPause:
+		annotations[v1alpha1.PausedAnnotation] = "true"
+	case ActionResume:
+		delete(annotations, v1alpha1.PausedAnnotation)
+	case ActionResync:
+		annotations[v1alpha1.ResyncRequestedAtAnnotation] = now.UTC().Format(time.RFC3339)
+	}
+	obj.SetAnnotations(annotations)
+	return c.Patch(ctx, obj, patch)
+}
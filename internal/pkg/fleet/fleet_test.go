@@ -0,0 +1,81 @@
+package fleet
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestApply(t *testing.T) {
+	meta := func(ns, name, env string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Namespace: ns, Name: name, Labels: map[string]string{"env": env}}
+	}
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&v1alpha1.ThanosReceive{ObjectMeta: meta("a", "receive", "prod")},
+		&v1alpha1.ThanosQuery{ObjectMeta: meta("b", "query", "prod")},
+		&v1alpha1.ThanosStore{ObjectMeta: meta("a", "store", "dev")},
+	).Build()
+
+	ctx := context.Background()
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	prod := Selection{Selector: labels.SelectorFromSet(labels.Set{"env": "prod"})}
+
+	results, err := Apply(ctx, c, ActionRestart, prod, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected the action to apply to the 2 selected resources, got %v", results)
+	}
+	for _, res := range results {
+		if res.Err != nil {
+			t.Errorf("%s: unexpected error: %v", res.Resource, res.Err)
+		}
+	}
+
+	annotations := func(obj client.Object) map[string]string {
+		if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return obj.GetAnnotations()
+	}
+	receive := &v1alpha1.ThanosReceive{ObjectMeta: meta("a", "receive", "")}
+	if got := annotations(receive)[v1alpha1.RestartedAtAnnotation]; got != "2026-01-02T03:04:05Z" {
+		t.Errorf("expected the restart time to be recorded, got %q", got)
+	}
+	store := &v1alpha1.ThanosStore{ObjectMeta: meta("a", "store", "")}
+	if _, ok := annotations(store)[v1alpha1.RestartedAtAnnotation]; ok {
+		t.Errorf("expected the resources not matching the selector to be left unchanged")
+	}
+
+	if _, err := Apply(ctx, c, ActionPause, Selection{Namespace: "a", Kinds: []string{"thanosreceive"}}, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := annotations(receive)[v1alpha1.PausedAnnotation]; got != "true" {
+		t.Errorf("expected the resource to be paused, got %q", got)
+	}
+	if _, err := Apply(ctx, c, ActionResume, Selection{Namespace: "a"}, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := annotations(receive)[v1alpha1.PausedAnnotation]; ok {
+		t.Errorf("expected the resource to be resumed")
+	}
+
+	if _, err := Apply(ctx, c, "delete", prod, now); err == nil {
+		t.Errorf("expected an error for an unknown action")
+	}
+	if _, err := Apply(ctx, c, ActionResync, Selection{Kinds: []string{"thanosfoo"}}, now); err == nil {
+		t.Errorf("expected an error for an unknown kind")
+	}
+}
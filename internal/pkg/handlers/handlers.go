@@ -2,26 +2,35 @@ package handlers
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
+	"maps"
 	slices0 "slices"
+	"time"
 
 	"github.com/go-logr/logr"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 
 	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+	"github.com/thanos-community/thanos-operator/internal/pkg/metrics"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	"k8s.io/utils/strings/slices"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 type Handler struct {
@@ -34,12 +43,33 @@ type handler struct {
 	logger logr.Logger
 
 	gatedGVK []schema.GroupVersionKind
+
+	// disableOwnerReferences is true if the owner of the objects lives in another cluster.
+	disableOwnerReferences bool
+	// mutators are invoked with every object before it is created or updated.
+	mutators []ObjectMutator
+
+	// component is the component the objects are applied for, in the metrics.
+	component string
+	// metrics records the objects applied and deleted by the handler, if set.
+	metrics *metrics.CommonMetrics
+	// applied records the objects applied by the handler, if set.
+	applied *manifests.ObjectSet
+	// recorder emits an Event on the owner of each object created, updated or failing to apply, if set.
+	recorder events.EventRecorder
 }
 
 // resourcePruner creates an object that prunes resources in the Kubernetes cluster.
 type resourcePruner struct {
 	*handler
-	sa, svc, sts, dep, cm, secret, pdb, svcMon bool
+	sa, svc, sts, dep, cm, secret, pdb, svcMon, netpol, promRule bool
+
+	// orphan releases orphaned resources instead of deleting them.
+	orphan bool
+	// gracePeriod is the time orphaned resources are marked as pending deletion before they are deleted.
+	gracePeriod time.Duration
+	// requeueAfter is the time until the next resource marked as pending deletion is due for deletion.
+	requeueAfter time.Duration
 }
 
 // NewHandler creates a new Handler.
@@ -60,8 +90,47 @@ func (h *Handler) SetFeatureGates(gvk []schema.GroupVersionKind) *Handler {
 	return h
 }
 
+// DisableOwnerReferences stops the handler from setting owner references on the objects it creates.
+// It must be used when the owner lives in another cluster, since owner references cannot cross clusters.
+// Such objects are not garbage collected by Kubernetes when their owner is deleted.
+func (h *Handler) DisableOwnerReferences() *Handler {
+	h.disableOwnerReferences = true
+	return h
+}
+
+// WithMutators sets the mutators invoked with every object before it is created or updated.
+func (h *Handler) WithMutators(mutators ...ObjectMutator) *Handler {
+	h.mutators = mutators
+	return h
+}
+
+// WithMetrics records the operations on the objects applied by the handler, and the objects it manages for each
+// owner, in the metrics of the given component.
+func (h *Handler) WithMetrics(component string, m *metrics.CommonMetrics) *Handler {
+	h.component = component
+	h.metrics = m
+	return h
+}
+
+// WithEventRecorder emits a Normal Event on the owner of each object the handler creates or updates,
+// and a Warning Event for each object that fails to apply.
+func (h *Handler) WithEventRecorder(recorder events.EventRecorder) *Handler {
+	h.recorder = recorder
+	return h
+}
+
+// RecordApplied returns a copy of the handler that adds the objects it creates or updates to the given set,
+// so that the objects no longer generated for their owner can be pruned with PruneStale.
+func (h *Handler) RecordApplied(applied *manifests.ObjectSet) *Handler {
+	c := *h.handler
+	c.applied = applied
+	return &Handler{handler: &c}
+}
+
 // CreateOrUpdate creates or updates the given objects in the Kubernetes cluster.
-// It sets the owner reference of each object to the given owner.
+// It sets the owner reference of each object to the given owner, unless owner references are disabled,
+// and labels each object with the UID of the owner.
+// The registered mutators are invoked with each object before it is applied.
 // It logs the operation and any errors encountered.
 // It returns the number of errors encountered.
 func (h *Handler) CreateOrUpdate(ctx context.Context, namespace string, owner client.Object, objs []client.Object) int {
@@ -75,28 +144,107 @@ func (h *Handler) CreateOrUpdate(ctx context.Context, namespace string, owner cl
 
 		if manifests.IsNamespacedResource(obj) {
 			obj.SetNamespace(namespace)
-			if err := ctrl.SetControllerReference(owner, obj, h.scheme); err != nil {
-				logger.Error(err, "failed to set controller owner reference to resource")
-				errCount++
-				continue
+			if !h.disableOwnerReferences {
+				if err := ctrl.SetControllerReference(owner, obj, h.scheme); err != nil {
+					logger.Error(err, "failed to set controller owner reference to resource")
+					errCount++
+					continue
+				}
 			}
 		}
+		manifests.SetOwnerUIDLabel(obj, owner.GetUID())
+		if h.applied != nil {
+			// objects that fail to apply are recorded too, so that they are not pruned
+			h.applied.Add(obj)
+		}
+
+		if err := h.mutate(ctx, owner, obj); err != nil {
+			logger.Error(err, "failed to mutate resource")
+			h.recordEvent(owner, corev1.EventTypeWarning, "ApplyFailed", "Failed to mutate %s %s: %v", h.kindOf(obj), obj.GetName(), err)
+			errCount++
+			continue
+		}
 
 		desired := obj.DeepCopyObject().(client.Object)
 		mutateFn := manifests.MutateFuncFor(obj, desired)
 
-		op, err := ctrl.CreateOrUpdate(ctx, h.client, obj, mutateFn)
+		op, err := ctrl.CreateOrUpdate(ctx, h.client, obj, func() error {
+			if err := mutateFn(); err != nil {
+				return err
+			}
+			// the resource is expected again, so it must not be pruned once the grace period expires
+			unmarkPendingDeletion(obj)
+			return nil
+		})
 
 		if err != nil {
 			logger.Error(err, "failed to create or update resource")
+			h.recordEvent(owner, corev1.EventTypeWarning, "ApplyFailed", "Failed to create or update %s %s: %v", h.kindOf(obj), obj.GetName(), err)
 			errCount++
 			continue
 		}
 		logger.V(1).Info("resource configured", "operation", op)
+		h.recordApplied(obj, op)
+		switch op {
+		case controllerutil.OperationResultCreated:
+			h.recordEvent(owner, corev1.EventTypeNormal, "Created", "Created %s %s", h.kindOf(obj), obj.GetName())
+		case controllerutil.OperationResultUpdated:
+			h.recordEvent(owner, corev1.EventTypeNormal, "Updated", "Updated %s %s", h.kindOf(obj), obj.GetName())
+		}
 	}
 	return errCount
 }
 
+// recordApplied records the operation on an applied object, and that it is managed for its owner.
+func (h *handler) recordApplied(obj client.Object, op controllerutil.OperationResult) {
+	if h.metrics == nil {
+		return
+	}
+	kind := h.kindOf(obj)
+	h.metrics.ObjectOperationsTotal.WithLabelValues(h.component, kind, string(op)).Inc()
+	if owner := obj.GetLabels()[manifests.OwnerLabel]; owner != "" {
+		h.metrics.AddManagedObject(h.component, owner, obj.GetNamespace(), kind, obj.GetName())
+	}
+}
+
+// recordEvent emits an Event on the owner of the applied objects, if an event recorder is set.
+func (h *handler) recordEvent(owner client.Object, eventtype, reason, note string, args ...any) {
+	if h.recorder == nil {
+		return
+	}
+	h.recorder.Eventf(owner, nil, eventtype, reason, "Apply", note, args...)
+}
+
+// recordRemoved records that an object deleted or released by the handler is no longer managed for the given owner.
+func (h *handler) recordRemoved(obj client.Object, owner string) {
+	if h.metrics == nil || owner == "" {
+		return
+	}
+	h.metrics.RemoveManagedObject(h.component, owner, obj.GetNamespace(), h.kindOf(obj), obj.GetName())
+}
+
+// kindOf returns the kind of the object, which typed objects do not always set.
+func (h *handler) kindOf(obj client.Object) string {
+	if kind := obj.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+		return kind
+	}
+	gvk, err := apiutil.GVKForObject(obj, h.scheme)
+	if err != nil {
+		return fmt.Sprintf("%T", obj)
+	}
+	return gvk.Kind
+}
+
+// mutate invokes the registered mutators with the object, in order.
+func (h *handler) mutate(ctx context.Context, owner, obj client.Object) error {
+	for _, mutator := range h.mutators {
+		if err := mutator.Mutate(ctx, owner, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // IsFeatureGated returns true if the given object is feature gated.
 func (h *handler) IsFeatureGated(obj client.Object) bool {
 	gvk := obj.GetObjectKind().GroupVersionKind()
@@ -150,6 +298,7 @@ func (h *handler) deleteResource(ctx context.Context, obj client.Object) error {
 	}
 
 	logger.V(1).Info("resource deleted")
+	h.recordRemoved(obj, obj.GetLabels()[manifests.OwnerLabel])
 	return nil
 }
 
@@ -164,6 +313,38 @@ func (h *Handler) GetEndpointSlices(ctx context.Context, serviceName string, nam
 	return &eps, nil
 }
 
+// GetSecretKeyHash returns a hash of the value stored under the given key of the Secret in the given namespace.
+// It returns an error if the Secret or the key does not exist.
+func (h *Handler) GetSecretKeyHash(ctx context.Context, namespace string, selector corev1.SecretKeySelector) (string, error) {
+	secret := &corev1.Secret{}
+	if err := h.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: selector.Name}, secret); err != nil {
+		return "", fmt.Errorf("failed to get secret %s in namespace %s: %w", selector.Name, namespace, err)
+	}
+
+	data, ok := secret.Data[selector.Key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in secret %s in namespace %s", selector.Key, selector.Name, namespace)
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
+
+// GetSecretHash returns a hash of all the data stored in the Secret in the given namespace.
+// It returns an error if the Secret does not exist.
+func (h *Handler) GetSecretHash(ctx context.Context, namespace, name string) (string, error) {
+	secret := &corev1.Secret{}
+	if err := h.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, secret); err != nil {
+		return "", fmt.Errorf("failed to get secret %s in namespace %s: %w", name, namespace, err)
+	}
+
+	hash := sha256.New()
+	for _, key := range slices0.Sorted(maps.Keys(secret.Data)) {
+		// keys and values are separated so that moving bytes between them changes the hash
+		fmt.Fprintf(hash, "%s=%d:", key, len(secret.Data[key]))
+		hash.Write(secret.Data[key])
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
 // NewResourcePruner creates a new resourcePruner.
 func (h *Handler) NewResourcePruner() *resourcePruner {
 	return &resourcePruner{
@@ -219,17 +400,87 @@ func (r *resourcePruner) WithPodDisruptionBudget() *resourcePruner {
 	return r
 }
 
+// WithNetworkPolicy returns a resourcePruner with NetworkPolicy enabled.
+func (r *resourcePruner) WithNetworkPolicy() *resourcePruner {
+	r.netpol = true
+	return r
+}
+
+// WithPrometheusRule returns a resourcePruner with PrometheusRule enabled.
+func (r *resourcePruner) WithPrometheusRule() *resourcePruner {
+	r.promRule = true
+	return r
+}
+
+// WithGeneratedResources returns a resourcePruner with every resource kind generated by the operator enabled.
+func (r *resourcePruner) WithGeneratedResources() *resourcePruner {
+	return r.WithServiceAccount().WithService().WithStatefulSet().WithDeployment().WithConfigMap().
+		WithPodDisruptionBudget().WithServiceMonitor().WithNetworkPolicy()
+}
+
+// WithGracePeriod returns a resourcePruner that marks orphaned resources as pending deletion
+// and only deletes them once they have been orphaned for the given duration.
+// Resources that are expected again before the grace period expires are kept.
+// A zero duration deletes orphaned resources immediately.
+func (r *resourcePruner) WithGracePeriod(gracePeriod time.Duration) *resourcePruner {
+	r.gracePeriod = gracePeriod
+	return r
+}
+
+// WithOrphan returns a resourcePruner that releases orphaned resources instead of deleting them.
+// Released resources lose their owner references and owner labels, so that they are neither pruned again
+// nor garbage collected with their owner. The grace period does not apply to released resources.
+func (r *resourcePruner) WithOrphan() *resourcePruner {
+	r.orphan = true
+	return r
+}
+
+// RequeueAfter returns the time until the next resource marked as pending deletion by Prune is due for deletion.
+// It returns zero if no resource is pending deletion.
+func (r *resourcePruner) RequeueAfter() time.Duration {
+	return r.requeueAfter
+}
+
 // Prune deletes resources that are not in the keepResourceNames list.
 // It acts on the resources enabled in the resourcePruner.
 // It logs the operation and any errors encountered.
 // It returns the number of errors encountered.
 func (r *resourcePruner) Prune(ctx context.Context, keepResourceNames []string, listOpts ...client.ListOption) int {
+	return r.prune(ctx, func(objs []client.Object) []client.Object {
+		var orphaned []client.Object
+		for _, obj := range objs {
+			if !slices.Contains(keepResourceNames, obj.GetName()) {
+				orphaned = append(orphaned, obj)
+			}
+		}
+		return orphaned
+	}, listOpts...)
+}
+
+// PruneStale deletes the resources in the namespace labelled with the UID of the owner that are not in the applied set.
+// Unlike Prune, it catches the resources left behind when the names or the labels of the generated resources change.
+// It acts on the resources enabled in the resourcePruner, honouring the grace period and orphaning.
+// It returns the number of errors encountered.
+func (r *resourcePruner) PruneStale(ctx context.Context, namespace string, owner types.UID, applied *manifests.ObjectSet) int {
+	if owner == "" || applied == nil {
+		return 0
+	}
+	return r.prune(ctx, func(objs []client.Object) []client.Object {
+		return applied.Stale(owner, objs)
+	}, manifests.GetLabelSelectorForOwnerUID(owner), client.InNamespace(namespace))
+}
+
+// prune deletes the resources selected by orphaned among the listed resources enabled in the resourcePruner.
+func (r *resourcePruner) prune(ctx context.Context, orphaned func([]client.Object) []client.Object, listOpts ...client.ListOption) int {
 	var errCount int
 	deleteOrphanedResources := func(obj client.Object) error {
-		if !slices.Contains(keepResourceNames, obj.GetName()) {
-			return r.deleteResource(ctx, obj)
+		if r.orphan {
+			return r.releaseResource(ctx, obj)
 		}
-		return nil
+		if r.gracePeriod > 0 {
+			return r.deleteAfterGracePeriod(ctx, obj)
+		}
+		return r.deleteResource(ctx, obj)
 	}
 
 	resourceTypes := []struct {
@@ -244,6 +495,8 @@ func (r *resourcePruner) Prune(ctx context.Context, keepResourceNames []string,
 		{r.secret, &corev1.SecretList{}},
 		{r.pdb, &policyv1.PodDisruptionBudgetList{}},
 		{r.svcMon, &monitoringv1.ServiceMonitorList{}},
+		{r.netpol, &networkingv1.NetworkPolicyList{}},
+		{r.promRule, &monitoringv1.PrometheusRuleList{}},
 	}
 
 	for _, rt := range resourceTypes {
@@ -258,8 +511,12 @@ func (r *resourcePruner) Prune(ctx context.Context, keepResourceNames []string,
 				errCount++
 				continue
 			}
-			for _, item := range items {
-				if err := deleteOrphanedResources(item.(client.Object)); err != nil {
+			objs := make([]client.Object, len(items))
+			for i, item := range items {
+				objs[i] = item.(client.Object)
+			}
+			for _, obj := range orphaned(objs) {
+				if err := deleteOrphanedResources(obj); err != nil {
 					errCount++
 					continue
 				}
@@ -269,6 +526,80 @@ func (r *resourcePruner) Prune(ctx context.Context, keepResourceNames []string,
 	return errCount
 }
 
+// deleteAfterGracePeriod marks the resource as pending deletion if it is not marked yet,
+// and deletes it once the grace period has expired.
+func (r *resourcePruner) deleteAfterGracePeriod(ctx context.Context, obj client.Object) error {
+	logger := loggerForObj(r.logger, obj)
+
+	since, err := time.Parse(time.RFC3339, obj.GetAnnotations()[manifests.PendingDeletionSinceAnnotation])
+	if err != nil || obj.GetLabels()[manifests.PendingDeletionLabel] != manifests.PendingDeletionValue {
+		patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+		obj.SetLabels(manifests.MergeMaps(obj.GetLabels(), map[string]string{
+			manifests.PendingDeletionLabel: manifests.PendingDeletionValue,
+		}))
+		obj.SetAnnotations(manifests.MergeMaps(obj.GetAnnotations(), map[string]string{
+			manifests.PendingDeletionSinceAnnotation: time.Now().UTC().Format(time.RFC3339),
+		}))
+		if err := r.client.Patch(ctx, obj, patch); err != nil && !errors.IsNotFound(err) {
+			logger.Error(err, "failed to mark resource as pending deletion")
+			return err
+		}
+
+		logger.Info("resource marked as pending deletion", "gracePeriod", r.gracePeriod.String())
+		r.recordPendingDeletion(r.gracePeriod)
+		return nil
+	}
+
+	if remaining := time.Until(since.Add(r.gracePeriod)); remaining > 0 {
+		logger.V(1).Info("resource pending deletion", "remaining", remaining.String())
+		r.recordPendingDeletion(remaining)
+		return nil
+	}
+
+	return r.deleteResource(ctx, obj)
+}
+
+// releaseResource removes the owner references and owner label of the resource, so that it is no longer managed.
+func (r *resourcePruner) releaseResource(ctx context.Context, obj client.Object) error {
+	logger := loggerForObj(r.logger, obj)
+
+	owner := obj.GetLabels()[manifests.OwnerLabel]
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	obj.SetOwnerReferences(nil)
+	unmarkPendingDeletion(obj)
+	if labels := obj.GetLabels(); labels != nil {
+		delete(labels, manifests.OwnerLabel)
+		delete(labels, manifests.OwnerUIDLabel)
+		obj.SetLabels(labels)
+	}
+	if err := r.client.Patch(ctx, obj, patch); err != nil && !errors.IsNotFound(err) {
+		logger.Error(err, "failed to release resource")
+		return err
+	}
+
+	logger.Info("resource released from its owner")
+	r.recordRemoved(obj, owner)
+	return nil
+}
+
+func (r *resourcePruner) recordPendingDeletion(after time.Duration) {
+	if r.requeueAfter == 0 || after < r.requeueAfter {
+		r.requeueAfter = after
+	}
+}
+
+// unmarkPendingDeletion removes the pending deletion marker from the resource.
+func unmarkPendingDeletion(obj client.Object) {
+	if labels := obj.GetLabels(); labels != nil {
+		delete(labels, manifests.PendingDeletionLabel)
+		obj.SetLabels(labels)
+	}
+	if annotations := obj.GetAnnotations(); annotations != nil {
+		delete(annotations, manifests.PendingDeletionSinceAnnotation)
+		obj.SetAnnotations(annotations)
+	}
+}
+
 func loggerForObj(logger logr.Logger, obj client.Object) logr.Logger {
 	return logger.WithValues("name", obj.GetName(), "namespace", obj.GetNamespace(), "kind", obj.GetObjectKind().GroupVersionKind().Kind)
 }
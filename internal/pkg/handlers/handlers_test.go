@@ -3,17 +3,26 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"maps"
 	"slices"
 	"testing"
+	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+	"github.com/thanos-community/thanos-operator/internal/pkg/metrics"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/events"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -195,6 +204,127 @@ func TestHandler_GetEndpointSlices(t *testing.T) {
 	}
 }
 
+func TestHandler_GetSecretKeyHash(t *testing.T) {
+	ctx := context.Background()
+	const namespace = "test"
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ca",
+			Namespace: namespace,
+		},
+		Data: map[string][]byte{
+			"ca.crt": []byte("some-ca"),
+		},
+	}
+
+	h := &Handler{
+		handler: &handler{
+			client: fake.NewFakeClient(secret),
+			scheme: scheme.Scheme,
+			logger: logr.New(log.NullLogSink{}),
+		},
+	}
+
+	hash, err := h.GetSecretKeyHash(ctx, namespace, corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "ca"},
+		Key:                  "ca.crt",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hash == "" {
+		t.Fatal("expected a non-empty hash")
+	}
+
+	secret.Data["ca.crt"] = []byte("rotated-ca")
+	if err := h.client.Update(ctx, secret); err != nil {
+		t.Fatalf("failed to update secret: %v", err)
+	}
+
+	rotated, err := h.GetSecretKeyHash(ctx, namespace, corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "ca"},
+		Key:                  "ca.crt",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rotated == hash {
+		t.Error("expected hash to change when the secret is rotated")
+	}
+
+	if _, err := h.GetSecretKeyHash(ctx, namespace, corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "ca"},
+		Key:                  "missing",
+	}); err == nil {
+		t.Error("expected error for missing key")
+	}
+
+	if _, err := h.GetSecretKeyHash(ctx, namespace, corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "missing"},
+		Key:                  "ca.crt",
+	}); err == nil {
+		t.Error("expected error for missing secret")
+	}
+}
+
+func TestHandler_GetSecretHash(t *testing.T) {
+	ctx := context.Background()
+	const namespace = "test"
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "objstore",
+			Namespace: namespace,
+		},
+		Data: map[string][]byte{
+			"thanos.yaml": []byte("type: S3"),
+			"client-id":   []byte("id"),
+		},
+	}
+
+	h := &Handler{
+		handler: &handler{
+			client: fake.NewFakeClient(secret),
+			scheme: scheme.Scheme,
+			logger: logr.New(log.NullLogSink{}),
+		},
+	}
+
+	hash, err := h.GetSecretHash(ctx, namespace, "objstore")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hash == "" {
+		t.Fatal("expected a non-empty hash")
+	}
+
+	again, err := h.GetSecretHash(ctx, namespace, "objstore")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again != hash {
+		t.Error("expected hash to be stable")
+	}
+
+	secret.Data["client-id"] = []byte("rotated-id")
+	if err := h.client.Update(ctx, secret); err != nil {
+		t.Fatalf("failed to update secret: %v", err)
+	}
+
+	rotated, err := h.GetSecretHash(ctx, namespace, "objstore")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rotated == hash {
+		t.Error("expected hash to change when any key of the secret is rotated")
+	}
+
+	if _, err := h.GetSecretHash(ctx, namespace, "missing"); !apierrors.IsNotFound(err) {
+		t.Errorf("expected not found error for missing secret, got %v", err)
+	}
+}
+
 func TestPrune(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -298,3 +428,413 @@ func TestPrune(t *testing.T) {
 		})
 	}
 }
+
+func TestPruneWithGracePeriod(t *testing.T) {
+	const ns = "test-namespace"
+	expired := time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+
+	r := &resourcePruner{
+		handler: &handler{
+			client: fake.NewFakeClient(
+				&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "keep-me", Namespace: ns}},
+				&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "mark-me", Namespace: ns}},
+				&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+					Name:        "delete-me",
+					Namespace:   ns,
+					Labels:      map[string]string{manifests.PendingDeletionLabel: manifests.PendingDeletionValue},
+					Annotations: map[string]string{manifests.PendingDeletionSinceAnnotation: expired},
+				}},
+			),
+			scheme: scheme.Scheme,
+			logger: logr.New(log.NullLogSink{}),
+		},
+		sa: true,
+	}
+	r.WithGracePeriod(time.Hour)
+
+	if errs := r.Prune(context.Background(), []string{"keep-me"}, client.InNamespace(ns)); errs != 0 {
+		t.Fatalf("unexpected error count: %v", errs)
+	}
+
+	if r.RequeueAfter() != time.Hour {
+		t.Errorf("expected requeue after %s, got %s", time.Hour, r.RequeueAfter())
+	}
+
+	saList := &corev1.ServiceAccountList{}
+	if err := r.client.List(context.Background(), saList, client.InNamespace(ns)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(saList.Items) != 2 {
+		t.Fatalf("expected 2 remaining resources, got %d", len(saList.Items))
+	}
+
+	for _, sa := range saList.Items {
+		marked := sa.Labels[manifests.PendingDeletionLabel] == manifests.PendingDeletionValue
+		switch sa.Name {
+		case "keep-me":
+			if marked {
+				t.Errorf("expected %s not to be marked as pending deletion", sa.Name)
+			}
+		case "mark-me":
+			if !marked || sa.Annotations[manifests.PendingDeletionSinceAnnotation] == "" {
+				t.Errorf("expected %s to be marked as pending deletion", sa.Name)
+			}
+		default:
+			t.Errorf("unexpected remaining resource %s", sa.Name)
+		}
+	}
+}
+
+func TestPruneWithOrphan(t *testing.T) {
+	const ns = "test-namespace"
+	ownerLabels := map[string]string{manifests.OwnerLabel: "owner", "app": "test"}
+	ownerRefs := []metav1.OwnerReference{{APIVersion: "v1", Kind: "ConfigMap", Name: "owner", UID: "owner-uid"}}
+
+	r := &resourcePruner{
+		handler: &handler{
+			client: fake.NewFakeClient(
+				&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "keep-me", Namespace: ns, Labels: ownerLabels, OwnerReferences: ownerRefs}},
+				&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "release-me", Namespace: ns, Labels: ownerLabels, OwnerReferences: ownerRefs}},
+			),
+			scheme: scheme.Scheme,
+			logger: logr.New(log.NullLogSink{}),
+		},
+		sa: true,
+	}
+	r.WithOrphan().WithGracePeriod(time.Hour)
+
+	if errs := r.Prune(context.Background(), []string{"keep-me"}, client.InNamespace(ns), client.MatchingLabels{manifests.OwnerLabel: "owner"}); errs != 0 {
+		t.Fatalf("unexpected error count: %v", errs)
+	}
+
+	saList := &corev1.ServiceAccountList{}
+	if err := r.client.List(context.Background(), saList, client.InNamespace(ns)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(saList.Items) != 2 {
+		t.Fatalf("expected 2 remaining resources, got %d", len(saList.Items))
+	}
+
+	for _, sa := range saList.Items {
+		_, owned := sa.Labels[manifests.OwnerLabel]
+		switch sa.Name {
+		case "keep-me":
+			if !owned || len(sa.OwnerReferences) != 1 {
+				t.Errorf("expected %s to keep its owner", sa.Name)
+			}
+		case "release-me":
+			if owned || len(sa.OwnerReferences) != 0 {
+				t.Errorf("expected %s to be released from its owner, got labels %v and owner references %v", sa.Name, sa.Labels, sa.OwnerReferences)
+			}
+			if sa.Labels["app"] != "test" {
+				t.Errorf("expected %s to keep its other labels, got %v", sa.Name, sa.Labels)
+			}
+		default:
+			t.Errorf("unexpected remaining resource %s", sa.Name)
+		}
+	}
+}
+
+func TestPruneStale(t *testing.T) {
+	const ns = "test-namespace"
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: ns, UID: "owner-uid"}}
+	c := fake.NewFakeClient(
+		// generated by a previous incarnation of the owner, which must be left alone
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: ns, Labels: map[string]string{manifests.OwnerUIDLabel: "other-uid"}}},
+	)
+	h := NewHandler(c, scheme.Scheme, logr.New(log.NullLogSink{}))
+
+	generate := func(names ...string) *manifests.ObjectSet {
+		applied := manifests.NewObjectSet()
+		var objs []client.Object
+		for _, name := range names {
+			objs = append(objs,
+				&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: name}},
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name}},
+			)
+		}
+		if errs := h.RecordApplied(applied).CreateOrUpdate(context.Background(), ns, owner, objs); errs != 0 {
+			t.Fatalf("unexpected error count: %v", errs)
+		}
+		return applied
+	}
+
+	generate("old", "kept")
+	applied := generate("new", "kept")
+	if errs := h.NewResourcePruner().WithServiceAccount().WithConfigMap().PruneStale(context.Background(), ns, owner.GetUID(), applied); errs != 0 {
+		t.Fatalf("unexpected error count: %v", errs)
+	}
+
+	saList := &corev1.ServiceAccountList{}
+	if err := c.List(context.Background(), saList, client.InNamespace(ns)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var remaining []string
+	for _, sa := range saList.Items {
+		remaining = append(remaining, sa.Name)
+	}
+	cmList := &corev1.ConfigMapList{}
+	if err := c.List(context.Background(), cmList, client.InNamespace(ns)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, cm := range cmList.Items {
+		remaining = append(remaining, "cm/"+cm.Name)
+	}
+	slices.Sort(remaining)
+	if expect := []string{"cm/kept", "cm/new", "kept", "new", "other"}; !slices.Equal(remaining, expect) {
+		t.Errorf("expected remaining resources %v, got %v", expect, remaining)
+	}
+}
+
+func TestHandler_CreateOrUpdateUnmarksPendingDeletion(t *testing.T) {
+	const ns = "test-namespace"
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: ns, UID: "owner-uid"}}
+	existing := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+		Name:        "revived",
+		Namespace:   ns,
+		Labels:      map[string]string{"app": "test", manifests.PendingDeletionLabel: manifests.PendingDeletionValue},
+		Annotations: map[string]string{manifests.PendingDeletionSinceAnnotation: time.Now().UTC().Format(time.RFC3339)},
+	}}
+
+	h := &Handler{
+		handler: &handler{
+			client: fake.NewFakeClient(existing),
+			scheme: scheme.Scheme,
+			logger: logr.New(log.NullLogSink{}),
+		},
+	}
+
+	desired := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+		Name:      "revived",
+		Namespace: ns,
+		Labels:    map[string]string{"app": "test"},
+	}}
+	if errs := h.CreateOrUpdate(context.Background(), ns, owner, []client.Object{desired}); errs != 0 {
+		t.Fatalf("unexpected error count: %v", errs)
+	}
+
+	got := &corev1.ServiceAccount{}
+	if err := h.client.Get(context.Background(), client.ObjectKeyFromObject(existing), got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := got.Labels[manifests.PendingDeletionLabel]; ok {
+		t.Errorf("expected pending deletion label to be removed, got labels %v", got.Labels)
+	}
+	if _, ok := got.Annotations[manifests.PendingDeletionSinceAnnotation]; ok {
+		t.Errorf("expected pending deletion annotation to be removed, got annotations %v", got.Annotations)
+	}
+}
+
+func TestHandler_CreateOrUpdateWithMutators(t *testing.T) {
+	const ns = "test-namespace"
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: ns, UID: "owner-uid"}}
+
+	h := NewHandler(fake.NewFakeClient(), scheme.Scheme, logr.New(log.NullLogSink{})).WithMutators(
+		ObjectMutatorFunc(func(_ context.Context, owner, obj client.Object) error {
+			obj.SetAnnotations(manifests.MergeMaps(obj.GetAnnotations(), map[string]string{"org.example/owner": owner.GetName()}))
+			return nil
+		}),
+		ObjectMutatorFunc(func(_ context.Context, _, obj client.Object) error {
+			if obj.GetName() == "rejected" {
+				return fmt.Errorf("rejected")
+			}
+			return nil
+		}),
+	)
+
+	objs := []client.Object{
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "mutated", Namespace: ns}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "rejected", Namespace: ns}},
+	}
+	if errs := h.CreateOrUpdate(context.Background(), ns, owner, objs); errs != 1 {
+		t.Fatalf("expected 1 error, got %d", errs)
+	}
+
+	got := &corev1.ServiceAccount{}
+	if err := h.client.Get(context.Background(), client.ObjectKey{Namespace: ns, Name: "mutated"}, got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Annotations["org.example/owner"] != "owner" {
+		t.Errorf("expected mutated annotation, got annotations %v", got.Annotations)
+	}
+	err := h.client.Get(context.Background(), client.ObjectKey{Namespace: ns, Name: "rejected"}, &corev1.ServiceAccount{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected object failing mutation not to be created, got %v", err)
+	}
+}
+
+func TestHandler_CreateOrUpdateWithMetrics(t *testing.T) {
+	const ns = "test-namespace"
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: ns, UID: "owner-uid"}}
+	m := metrics.NewCommonMetrics(prometheus.NewRegistry())
+	h := NewHandler(fake.NewFakeClient(), scheme.Scheme, logr.New(log.NullLogSink{})).WithMetrics("test", m)
+
+	newObjs := func() []client.Object {
+		return []client.Object{
+			&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: ns, Labels: map[string]string{manifests.OwnerLabel: "owner"}}},
+			&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: ns, Labels: map[string]string{manifests.OwnerLabel: "owner"}}},
+		}
+	}
+	for range 2 {
+		if errs := h.CreateOrUpdate(context.Background(), ns, owner, newObjs()); errs != 0 {
+			t.Fatalf("unexpected error count: %v", errs)
+		}
+	}
+
+	if got := testutil.ToFloat64(m.ObjectOperationsTotal.WithLabelValues("test", "ServiceAccount", "created")); got != 2 {
+		t.Errorf("expected 2 created objects, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.ObjectOperationsTotal.WithLabelValues("test", "ServiceAccount", "unchanged")); got != 2 {
+		t.Errorf("expected 2 unchanged objects, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.ManagedObjects.WithLabelValues("test", "owner", ns, "ServiceAccount")); got != 2 {
+		t.Errorf("expected 2 managed objects, got %v", got)
+	}
+
+	if errs := h.NewResourcePruner().WithServiceAccount().Prune(context.Background(), []string{"first"}, client.InNamespace(ns)); errs != 0 {
+		t.Fatalf("unexpected error count: %v", errs)
+	}
+	if got := testutil.ToFloat64(m.ManagedObjects.WithLabelValues("test", "owner", ns, "ServiceAccount")); got != 1 {
+		t.Errorf("expected 1 managed object after pruning, got %v", got)
+	}
+}
+
+func TestHandler_CreateOrUpdateWithEventRecorder(t *testing.T) {
+	const ns = "test-namespace"
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: ns, UID: "owner-uid"}}
+	recorder := events.NewFakeRecorder(10)
+
+	h := NewHandler(fake.NewFakeClient(), scheme.Scheme, logr.New(log.NullLogSink{})).WithEventRecorder(recorder).WithMutators(
+		ObjectMutatorFunc(func(_ context.Context, _, obj client.Object) error {
+			if obj.GetName() == "rejected" {
+				return fmt.Errorf("rejected")
+			}
+			return nil
+		}),
+	)
+
+	apply := func(labels map[string]string) {
+		objs := []client.Object{
+			&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "applied", Namespace: ns, Labels: labels}},
+			&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "rejected", Namespace: ns}},
+		}
+		h.CreateOrUpdate(context.Background(), ns, owner, objs)
+	}
+	apply(map[string]string{"app": "test"})
+	apply(map[string]string{"app": "test"})
+	apply(map[string]string{"app": "updated"})
+
+	close(recorder.Events)
+	var got []string
+	for event := range recorder.Events {
+		got = append(got, event)
+	}
+	expect := []string{
+		"Normal Created Created ServiceAccount applied",
+		"Warning ApplyFailed Failed to mutate ServiceAccount rejected: rejected",
+		"Warning ApplyFailed Failed to mutate ServiceAccount rejected: rejected",
+		"Normal Updated Updated ServiceAccount applied",
+		"Warning ApplyFailed Failed to mutate ServiceAccount rejected: rejected",
+	}
+	if !slices.Equal(got, expect) {
+		t.Errorf("expected events %q, got %q", expect, got)
+	}
+}
+
+func TestHandler_CreateOrUpdateRevertsDrift(t *testing.T) {
+	const ns = "test-namespace"
+	ctx := context.Background()
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: ns, UID: "owner-uid"}}
+	h := NewHandler(fake.NewFakeClient(), scheme.Scheme, logr.New(log.NullLogSink{}))
+
+	// the objects are built from scratch on every reconcile, like the manifests of the components
+	desired := func() []client.Object {
+		labels := map[string]string{"app": "test"}
+		return []client.Object{
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "workload", Namespace: ns, Labels: labels},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: labels},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: labels},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "thanos", Image: "thanos:latest", Args: []string{"query"}}},
+						},
+					},
+				},
+			},
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: ns, Labels: labels},
+				Data:       map[string]string{"config.yaml": "managed"},
+			},
+		}
+	}
+	apply := func() {
+		t.Helper()
+		if errs := h.CreateOrUpdate(ctx, ns, owner, desired()); errs != 0 {
+			t.Fatalf("unexpected error count: %v", errs)
+		}
+	}
+	apply()
+
+	// edit the objects out of band, as kubectl edit would
+	deployment := &appsv1.Deployment{}
+	if err := h.client.Get(ctx, client.ObjectKey{Namespace: ns, Name: "workload"}, deployment); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	deployment.Labels["app"] = "edited"
+	deployment.Spec.Template.Labels["edited"] = "true"
+	deployment.Spec.Template.Spec.Containers[0].Args = append(deployment.Spec.Template.Spec.Containers[0].Args, "--log.level=debug")
+	deployment.Spec.Template.Spec.Volumes = append(deployment.Spec.Template.Spec.Volumes, corev1.Volume{Name: "edited"})
+	deployment.Spec.Template.Spec.DNSPolicy = corev1.DNSDefault
+	deployment.Spec.Template.Spec.HostNetwork = true
+	if err := h.client.Update(ctx, deployment); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	config := &corev1.ConfigMap{}
+	if err := h.client.Get(ctx, client.ObjectKey{Namespace: ns, Name: "config"}, config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	config.Data["config.yaml"] = "edited"
+	if err := h.client.Update(ctx, config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the next reconcile, triggered by the watch on the owned objects or by the resync, reverts the edits
+	apply()
+	if err := h.client.Get(ctx, client.ObjectKeyFromObject(deployment), deployment); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := desired()[0].(*appsv1.Deployment)
+	if got := deployment.Labels["app"]; got != "test" {
+		t.Errorf("expected the app label to be reverted, got %q", got)
+	}
+	if got := deployment.Spec.Template.Labels; !maps.Equal(got, want.Spec.Template.Labels) {
+		t.Errorf("expected the pod labels to be reverted, got %v", got)
+	}
+	if got := deployment.Spec.Template.Spec.Containers[0].Args; !slices.Equal(got, want.Spec.Template.Spec.Containers[0].Args) {
+		t.Errorf("expected the args to be reverted, got %v", got)
+	}
+	if got := deployment.Spec.Template.Spec.Volumes; len(got) != 0 {
+		t.Errorf("expected the volumes to be reverted, got %v", got)
+	}
+	if deployment.Spec.Template.Spec.DNSPolicy == corev1.DNSDefault || deployment.Spec.Template.Spec.HostNetwork {
+		t.Errorf("expected the DNS policy and host network to be reverted")
+	}
+	if err := h.client.Get(ctx, client.ObjectKeyFromObject(config), config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := config.Data["config.yaml"]; got != "managed" {
+		t.Errorf("expected the ConfigMap data to be reverted, got %q", got)
+	}
+
+	// once reverted, reconciles leave the objects untouched
+	resourceVersion := deployment.ResourceVersion
+	apply()
+	if err := h.client.Get(ctx, client.ObjectKeyFromObject(deployment), deployment); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deployment.ResourceVersion != resourceVersion {
+		t.Errorf("expected no update once the drift is reverted")
+	}
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// ObjectMutator mutates the objects generated by the operator before they are applied.
+// It allows mandatory changes, such as annotations or sidecars, to be injected without changing the manifest builders.
+// Mutators must be idempotent, since they are invoked on every reconcile.
+type ObjectMutator interface {
+	// Mutate mutates obj in place. The owner is the resource the object is generated for.
+	Mutate(ctx context.Context, owner, obj client.Object) error
+}
+
+// ObjectMutatorFunc is a function that implements ObjectMutator.
+type ObjectMutatorFunc func(ctx context.Context, owner, obj client.Object) error
+
+// Mutate implements ObjectMutator.
+func (f ObjectMutatorFunc) Mutate(ctx context.Context, owner, obj client.Object) error {
+	return f(ctx, owner, obj)
+}
+
+// MutationRequest is the body sent to a mutation webhook for each generated object.
+type MutationRequest struct {
+	// Owner identifies the resource the object is generated for.
+	Owner MutationOwner `json:"owner"`
+	// Object is the generated object.
+	Object client.Object `json:"object"`
+}
+
+// MutationOwner identifies the resource a generated object belongs to.
+type MutationOwner struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+}
+
+// webhookMutator is an ObjectMutator that calls out to an HTTP endpoint.
+type webhookMutator struct {
+	url    string
+	client *http.Client
+	scheme *runtime.Scheme
+}
+
+// NewWebhookMutator returns an ObjectMutator that POSTs a MutationRequest for each generated object to the given URL.
+// The webhook responds with 200 and the mutated object, or with 204 if the object is left unchanged.
+// The kind, name and namespace of the object cannot be changed, and its labels and owner references must be kept.
+// Secrets are not sent to the webhook, so that the credentials they hold do not leave the operator.
+func NewWebhookMutator(url string, httpClient *http.Client, scheme *runtime.Scheme) ObjectMutator {
+	return &webhookMutator{url: url, client: httpClient, scheme: scheme}
+}
+
+// Mutate implements ObjectMutator.
+func (w *webhookMutator) Mutate(ctx context.Context, owner, obj client.Object) error {
+	if _, ok := obj.(*corev1.Secret); ok {
+		return nil
+	}
+	ownerGVK, err := apiutil.GVKForObject(owner, w.scheme)
+	if err != nil {
+		return fmt.Errorf("failed to get kind of owner: %w", err)
+	}
+	gvk, err := apiutil.GVKForObject(obj, w.scheme)
+	if err != nil {
+		return fmt.Errorf("failed to get kind of object: %w", err)
+	}
+	// generated objects do not always have their type set, but the webhook needs it to decode them
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+
+	body, err := json.Marshal(MutationRequest{
+		Owner: MutationOwner{
+			APIVersion: ownerGVK.GroupVersion().String(),
+			Kind:       ownerGVK.Kind,
+			Name:       owner.GetName(),
+			Namespace:  owner.GetNamespace(),
+		},
+		Object: obj,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal mutation request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create mutation request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call mutation webhook: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusNoContent:
+		return nil
+	case http.StatusOK:
+	default:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("mutation webhook returned %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+
+	mutated := reflect.New(reflect.TypeOf(obj).Elem()).Interface().(client.Object)
+	if err := json.NewDecoder(resp.Body).Decode(mutated); err != nil {
+		return fmt.Errorf("failed to decode mutated object: %w", err)
+	}
+	if mutated.GetObjectKind().GroupVersionKind() != gvk || mutated.GetName() != obj.GetName() || mutated.GetNamespace() != obj.GetNamespace() {
+		return fmt.Errorf("mutation webhook must not change the kind, name or namespace of %s %s", gvk.Kind, obj.GetName())
+	}
+	// the labels select the objects of the components and identify those managed by the operator
+	for key, value := range obj.GetLabels() {
+		if got, ok := mutated.GetLabels()[key]; !ok || got != value {
+			return fmt.Errorf("mutation webhook must not change or remove the label %s of %s %s", key, gvk.Kind, obj.GetName())
+		}
+	}
+	if !equality.Semantic.DeepEqual(mutated.GetOwnerReferences(), obj.GetOwnerReferences()) {
+		return fmt.Errorf("mutation webhook must not change the owner references of %s %s", gvk.Kind, obj.GetName())
+	}
+	reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(mutated).Elem())
+	return nil
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+func TestWebhookMutator(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1alpha1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	owner := &v1alpha1.ThanosReceive{ObjectMeta: metav1.ObjectMeta{Name: "receive", Namespace: "ns"}}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Owner  MutationOwner    `json:"owner"`
+			Object corev1.ConfigMap `json:"object"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.Owner.Kind != "ThanosReceive" || req.Owner.APIVersion != v1alpha1.GroupVersion.String() || req.Owner.Name != "receive" {
+			http.Error(w, "unexpected owner", http.StatusBadRequest)
+			return
+		}
+		if req.Object.Kind != "ConfigMap" {
+			http.Error(w, "unexpected kind", http.StatusBadRequest)
+			return
+		}
+
+		switch req.Object.Name {
+		case "unchanged":
+			w.WriteHeader(http.StatusNoContent)
+			return
+		case "renamed":
+			req.Object.Name = "other"
+		case "relabeled":
+			req.Object.Labels = nil
+		case "disowned":
+			req.Object.OwnerReferences = nil
+		case "failed":
+			http.Error(w, "denied by policy", http.StatusForbidden)
+			return
+		}
+		req.Object.Annotations = map[string]string{"org.example/cost-center": "observability"}
+		_ = json.NewEncoder(w).Encode(req.Object)
+	}))
+	defer srv.Close()
+
+	m := NewWebhookMutator(srv.URL, srv.Client(), s)
+	for _, tc := range []struct {
+		name       string
+		expectErr  string
+		expectAnno string
+	}{
+		{name: "mutated", expectAnno: "observability"},
+		{name: "unchanged"},
+		{name: "renamed", expectErr: "must not change the kind, name or namespace"},
+		{name: "relabeled", expectErr: "must not change or remove the label operator.thanos.io/owner-uid"},
+		{name: "disowned", expectErr: "must not change the owner references"},
+		{name: "failed", expectErr: "denied by policy"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            tc.name,
+					Namespace:       "ns",
+					Labels:          map[string]string{manifests.OwnerUIDLabel: "uid"},
+					OwnerReferences: []metav1.OwnerReference{{APIVersion: v1alpha1.GroupVersion.String(), Kind: "ThanosReceive", Name: "receive", UID: "uid"}},
+				},
+				Data: map[string]string{"key": "value"},
+			}
+			err := m.Mutate(context.Background(), owner, obj)
+			if tc.expectErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if obj.Annotations["org.example/cost-center"] != tc.expectAnno {
+				t.Errorf("expected annotation %q, got annotations %v", tc.expectAnno, obj.Annotations)
+			}
+			if obj.Data["key"] != "value" {
+				t.Errorf("expected data to be kept, got %v", obj.Data)
+			}
+		})
+	}
+
+	// Secrets are not sent to the webhook, which rejects any object but a ConfigMap
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "ns"}}
+	if err := m.Mutate(context.Background(), owner, secret); err != nil {
+		t.Errorf("expected secrets to be skipped, got %v", err)
+	}
+}
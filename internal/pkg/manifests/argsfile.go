@@ -0,0 +1,96 @@
+package manifests
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// ArgsFileKey is the key in the ConfigMap holding the args file.
+	ArgsFileKey = "args"
+	// ArgsFileHashAnnotation is the pod template annotation used to trigger a rollout
+	// when the contents of the args file change.
+	ArgsFileHashAnnotation = "operator.thanos.io/args-file-hash"
+
+	argsFileVolumeName = "args-file"
+	argsFileMountPath  = "/etc/thanos/args"
+)
+
+// ArgsFileConfigMapName returns the name of the ConfigMap holding the args file of the named component.
+func ArgsFileConfigMapName(name string) string {
+	return name + "-args"
+}
+
+// SplitArgsFile splits container args into the args kept inline and the contents of the args file.
+// Thanos reads the args file, one argument per line, when it is passed as @<path>.
+// The subcommand, args referencing env vars, which Kubernetes only expands inline, and multi-line args are kept inline.
+func SplitArgsFile(args []string) ([]string, string) {
+	var (
+		inline []string
+		file   strings.Builder
+	)
+	for i, arg := range args {
+		if i == 0 || strings.Contains(arg, "$(") || strings.ContainsAny(arg, "\r\n") || strings.HasPrefix(arg, "#") {
+			inline = append(inline, arg)
+			continue
+		}
+		file.WriteString(arg)
+		file.WriteString("\n")
+	}
+	return inline, file.String()
+}
+
+// NewArgsFileConfigMap creates the ConfigMap holding the args file for the given container args.
+func NewArgsFileConfigMap(name, namespace string, args []string, labels, annotations map[string]string) *corev1.ConfigMap {
+	_, contents := SplitArgsFile(args)
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: corev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        ArgsFileConfigMapName(name),
+			Namespace:   namespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Data: map[string]string{
+			ArgsFileKey: contents,
+		},
+	}
+}
+
+// MountArgsFile moves the args of the first container of the pod template into the args file of the named component
+// and mounts the ConfigMap created by NewArgsFileConfigMap. Thanos only reads the args file at startup,
+// so a hash of its contents is recorded on the pod template to roll the pods when it changes.
+func MountArgsFile(pt *corev1.PodTemplateSpec, name string) {
+	c := &pt.Spec.Containers[0]
+	inline, contents := SplitArgsFile(c.Args)
+	c.Args = append(inline, fmt.Sprintf("@%s/%s", argsFileMountPath, ArgsFileKey))
+	c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+		Name:      argsFileVolumeName,
+		ReadOnly:  true,
+		MountPath: argsFileMountPath,
+	})
+	pt.Spec.Volumes = append(pt.Spec.Volumes, corev1.Volume{
+		Name: argsFileVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: ArgsFileConfigMapName(name),
+				},
+				DefaultMode: ptr.To(int32(420)),
+			},
+		},
+	})
+
+	if pt.Annotations == nil {
+		pt.Annotations = make(map[string]string)
+	}
+	pt.Annotations[ArgsFileHashAnnotation] = fmt.Sprintf("%x", sha256.Sum256([]byte(contents)))
+}
@@ -0,0 +1,51 @@
+package manifests
+
+import (
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSplitArgsFile(t *testing.T) {
+	args := []string{
+		"receive",
+		"--log.level=info",
+		"--label=replica=\"$(POD_NAME)\"",
+		"--tracing.config=type: OTLP\nconfig: {}",
+		"--endpoint=a",
+	}
+
+	inline, contents := SplitArgsFile(args)
+	wantInline := []string{"receive", "--label=replica=\"$(POD_NAME)\"", "--tracing.config=type: OTLP\nconfig: {}"}
+	if !slices.Equal(inline, wantInline) {
+		t.Errorf("expected inline args %q, got %q", wantInline, inline)
+	}
+	if want := "--log.level=info\n--endpoint=a\n"; contents != want {
+		t.Errorf("expected args file %q, got %q", want, contents)
+	}
+}
+
+func TestMountArgsFile(t *testing.T) {
+	newTemplate := func(args ...string) *corev1.PodTemplateSpec {
+		return &corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "thanos", Args: args}}}}
+	}
+
+	pt := newTemplate("query", "--endpoint=a")
+	MountArgsFile(pt, "thanos-query")
+	if want := []string{"query", "@/etc/thanos/args/args"}; !slices.Equal(pt.Spec.Containers[0].Args, want) {
+		t.Errorf("expected args %q, got %q", want, pt.Spec.Containers[0].Args)
+	}
+	if len(pt.Spec.Volumes) != 1 || pt.Spec.Volumes[0].ConfigMap.Name != ArgsFileConfigMapName("thanos-query") {
+		t.Errorf("expected args file ConfigMap volume, got %+v", pt.Spec.Volumes)
+	}
+	if len(pt.Spec.Containers[0].VolumeMounts) != 1 {
+		t.Errorf("expected args file to be mounted, got %+v", pt.Spec.Containers[0].VolumeMounts)
+	}
+
+	changed := newTemplate("query", "--endpoint=b")
+	MountArgsFile(changed, "thanos-query")
+	if pt.Annotations[ArgsFileHashAnnotation] == changed.Annotations[ArgsFileHashAnnotation] {
+		t.Error("expected hash annotation to change with the args file")
+	}
+}
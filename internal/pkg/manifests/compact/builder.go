@@ -44,6 +44,7 @@ type Options struct {
 	RelabelConfigs manifests.RelabelConfigs
 	StorageConfig  manifests.StorageConfig
 	ObjStoreSecret corev1.SecretKeySelector
+	ObjStoreConfig manifests.ObjStoreConfig
 	// Min and Max time for the compactor
 	Min, Max  *manifests.Duration
 	ShardName *string
@@ -59,12 +60,16 @@ func (opts Options) Build() []client.Object {
 	objectMetaLabels := manifests.MergeMaps(opts.Labels, selectorLabels)
 	name := opts.GetGeneratedResourceName()
 
-	objs = append(objs, manifests.BuildServiceAccount(opts.GetGeneratedResourceName(), opts.Namespace, selectorLabels, opts.Annotations))
+	objs = append(objs, manifests.BuildServiceAccount(opts.GetGeneratedResourceName(), opts.Namespace, selectorLabels, manifests.MergeMaps(opts.Annotations, opts.ServiceAccountAnnotations)))
 	objs = append(objs, newShardStatefulSet(opts, selectorLabels, objectMetaLabels))
 	objs = append(objs, NewService(opts))
 
+	if opts.EnableMetricsService {
+		objs = append(objs, manifests.BuildMetricsService(name, opts.Namespace, objectMetaLabels, selectorLabels, opts.Annotations, HTTPPort))
+	}
+
 	if opts.ServiceMonitorConfig != nil {
-		objs = append(objs, manifests.BuildServiceMonitor(name, opts.Namespace, objectMetaLabels, selectorLabels, serviceMonitorOpts(opts.ServiceMonitorConfig)))
+		objs = append(objs, manifests.BuildServiceMonitor(name, opts.Namespace, objectMetaLabels, selectorLabels, serviceMonitorOpts(opts.ServiceMonitorConfig, opts.EnableMetricsService)))
 	}
 
 	return objs
@@ -81,11 +86,11 @@ func (opts Options) Valid() error {
 // If no sharding is configured, the name will be generated from the Options.Owner.
 // If sharding is configured, the name will be generated from the Options.Owner, ShardName, and ShardIndex.
 func (opts Options) GetGeneratedResourceName() string {
-	name := fmt.Sprintf("%s-%s", Name, opts.getOwner())
+	name := manifests.GeneratedName(Name, opts.getOwner())
 	if opts.ShardName != nil {
-		name = fmt.Sprintf("%s-%s", name, *opts.ShardName)
+		name = manifests.GeneratedName(Name, opts.getOwner(), *opts.ShardName)
 	}
-	return manifests.ValidateAndSanitizeResourceName(name)
+	return manifests.ValidateAndSanitizeResourceNameToLength(name, manifests.StatefulSetNameMaxLength)
 }
 
 func (opts Options) getOwner() string {
@@ -121,20 +126,7 @@ func newShardStatefulSet(opts Options, selectorLabels map[string]string, metaLab
 		},
 	}
 
-	envVars := []corev1.EnvVar{
-		{
-			Name: objectStoreEnvVarName,
-			ValueFrom: &corev1.EnvVarSource{
-				SecretKeyRef: &corev1.SecretKeySelector{
-					LocalObjectReference: corev1.LocalObjectReference{
-						Name: opts.ObjStoreSecret.Name,
-					},
-					Key:      opts.ObjStoreSecret.Key,
-					Optional: ptr.To(false),
-				},
-			},
-		},
-	}
+	envVars := opts.ObjStoreConfig.EnvVars(objectStoreEnvVarName, opts.ObjStoreSecret)
 
 	sts := &appsv1.StatefulSet{
 		TypeMeta: metav1.TypeMeta{
@@ -180,6 +172,9 @@ func newShardStatefulSet(opts Options, selectorLabels map[string]string, metaLab
 										"ALL",
 									},
 								},
+								SeccompProfile: &corev1.SeccompProfile{
+									Type: corev1.SeccompProfileTypeRuntimeDefault,
+								},
 							},
 							ReadinessProbe: &corev1.Probe{
 								ProbeHandler: corev1.ProbeHandler{
@@ -229,6 +224,7 @@ func newShardStatefulSet(opts Options, selectorLabels map[string]string, metaLab
 			},
 		},
 	}
+	manifests.MountObjStore(&sts.Spec.Template, opts.ObjStoreSecret, opts.ObjStoreConfig)
 	manifests.AugmentWithOptions(sts, opts.Options)
 	return sts
 }
@@ -265,7 +261,7 @@ func newService(opts Options, selectorLabels, objectMetaLabels map[string]string
 			Name:        opts.GetGeneratedResourceName(),
 			Namespace:   opts.Namespace,
 			Labels:      objectMetaLabels,
-			Annotations: opts.Annotations,
+			Annotations: opts.GetServiceAnnotations(),
 		},
 		Spec: corev1.ServiceSpec{
 			Selector: selectorLabels,
@@ -281,7 +277,7 @@ func compactorArgsFrom(opts Options) []string {
 	args = append(args,
 		"--wait",
 		fmt.Sprintf("--http-address=0.0.0.0:%d", HTTPPort),
-		fmt.Sprintf("--objstore.config=$(%s)", objectStoreEnvVarName),
+		opts.ObjStoreConfig.Flag(objectStoreEnvVarName),
 		fmt.Sprintf("--data-dir=%s", dataVolumeMountPath),
 	)
 
@@ -503,9 +499,10 @@ func (dc *DebugConfigOptions) toArgs() []string {
 	return args
 }
 
-func serviceMonitorOpts(from *manifests.ServiceMonitorConfig) manifests.ServiceMonitorOptions {
+func serviceMonitorOpts(from *manifests.ServiceMonitorConfig, metricsService bool) manifests.ServiceMonitorOptions {
 	return manifests.ServiceMonitorOptions{
-		Port:     ptr.To(HTTPPortName),
-		Interval: from.Interval,
+		Port:           ptr.To(HTTPPortName),
+		Interval:       from.Interval,
+		MetricsService: metricsService,
 	}
 }
@@ -0,0 +1,31 @@
+package manifests
+
+import (
+	"sync/atomic"
+)
+
+// ComponentDefaults are the defaults of the operator for the Thanos components, used when neither the resource
+// nor a ThanosDefaults sets the value. Empty fields keep the built-in defaults.
+type ComponentDefaults struct {
+	// Image is the container image of the Thanos components, without a tag.
+	Image string
+	// Version is the version of Thanos, used as the tag of the container image.
+	Version string
+	// LogLevel is the log level of the Thanos components.
+	LogLevel string
+}
+
+var componentDefaults atomic.Pointer[ComponentDefaults]
+
+// SetComponentDefaults sets the defaults of the operator for the Thanos components.
+// It may be called while the operator runs, the defaults then apply from the next reconcile of each resource.
+func SetComponentDefaults(defaults ComponentDefaults) {
+	componentDefaults.Store(&defaults)
+}
+
+func getComponentDefaults() ComponentDefaults {
+	if defaults := componentDefaults.Load(); defaults != nil {
+		return *defaults
+	}
+	return ComponentDefaults{}
+}
@@ -0,0 +1,39 @@
+package manifests
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+var clusterDomain string
+
+// SetClusterDomain sets the DNS domain of the cluster, such as cluster.local, appended to the generated Service addresses.
+// If empty, the addresses end with .svc and are completed by the DNS search domains of the Pods.
+// It is set once when the operator starts.
+func SetClusterDomain(domain string) error {
+	domain = strings.TrimSuffix(domain, ".")
+	if domain != "" {
+		if errs := validation.IsDNS1123Subdomain(domain); len(errs) > 0 {
+			return fmt.Errorf("invalid cluster domain %q: %s", domain, strings.Join(errs, ", "))
+		}
+	}
+	clusterDomain = domain
+	return nil
+}
+
+// ServiceHost returns the DNS name of a Service, <service>.<namespace>.svc followed by the cluster domain if set.
+func ServiceHost(service, namespace string) string {
+	host := fmt.Sprintf("%s.%s.svc", service, namespace)
+	if clusterDomain != "" {
+		host += "." + clusterDomain
+	}
+	return host
+}
+
+// PodHost returns the DNS name of a Pod with a hostname behind a headless Service, <hostname>.<service>.<namespace>.svc
+// followed by the cluster domain if set.
+func PodHost(hostname, service, namespace string) string {
+	return hostname + "." + ServiceHost(service, namespace)
+}
@@ -0,0 +1,26 @@
+package manifests
+
+import (
+	"testing"
+)
+
+func TestServiceHost(t *testing.T) {
+	if got := ServiceHost("thanos-query-example", "monitoring"); got != "thanos-query-example.monitoring.svc" {
+		t.Errorf("unexpected default host %q", got)
+	}
+
+	if err := SetClusterDomain("Not_A_Domain"); err == nil {
+		t.Error("expected an error for an invalid cluster domain")
+	}
+	if err := SetClusterDomain("cluster.example.com."); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { clusterDomain = "" })
+
+	if got := ServiceHost("thanos-query-example", "monitoring"); got != "thanos-query-example.monitoring.svc.cluster.example.com" {
+		t.Errorf("unexpected host with cluster domain %q", got)
+	}
+	if got := PodHost("ingester-0", "ingester", "monitoring"); got != "ingester-0.ingester.monitoring.svc.cluster.example.com" {
+		t.Errorf("unexpected pod host with cluster domain %q", got)
+	}
+}
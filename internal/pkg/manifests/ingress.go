@@ -0,0 +1,144 @@
+package manifests
+
+import (
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// IngressOptions configures the Ingress or HTTPRoute exposing a component outside of the cluster.
+type IngressOptions struct {
+	// HTTPRoute generates a Gateway API HTTPRoute instead of an Ingress.
+	HTTPRoute bool
+	// Hosts are the host names routed to the component.
+	Hosts []string
+	// Annotations are added to the generated object.
+	Annotations map[string]string
+	// ClassName is the IngressClass of the Ingress. The default IngressClass is used if empty.
+	ClassName string
+	// TLSSecret is the Secret holding the certificate served by the Ingress. TLS is not configured if empty.
+	TLSSecret string
+	// ParentRefs are the Gateways the HTTPRoute is attached to.
+	ParentRefs []GatewayReference
+}
+
+// GatewayReference references a listener of a Gateway.
+type GatewayReference struct {
+	// Name is the name of the Gateway.
+	Name string
+	// Namespace is the namespace of the Gateway. The namespace of the HTTPRoute is used if empty.
+	Namespace string
+	// SectionName is the listener of the Gateway. All listeners are used if empty.
+	SectionName string
+}
+
+// BuildIngress builds an Ingress, or an HTTPRoute if set in the options, routing all the paths of the hosts
+// to the given port of the Service.
+func BuildIngress(name, namespace string, objectMetaLabels map[string]string, opts IngressOptions, serviceName string, port int32) client.Object {
+	meta := metav1.ObjectMeta{
+		Name:        name,
+		Namespace:   namespace,
+		Labels:      objectMetaLabels,
+		Annotations: opts.Annotations,
+	}
+	if opts.HTTPRoute {
+		return buildHTTPRoute(meta, opts, serviceName, port)
+	}
+
+	backend := networkingv1.IngressBackend{
+		Service: &networkingv1.IngressServiceBackend{
+			Name: serviceName,
+			Port: networkingv1.ServiceBackendPort{Number: port},
+		},
+	}
+	ingress := &networkingv1.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Ingress",
+			APIVersion: networkingv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: meta,
+	}
+	if opts.ClassName != "" {
+		ingress.Spec.IngressClassName = ptr.To(opts.ClassName)
+	}
+	for _, host := range opts.Hosts {
+		ingress.Spec.Rules = append(ingress.Spec.Rules, networkingv1.IngressRule{
+			Host: host,
+			IngressRuleValue: networkingv1.IngressRuleValue{
+				HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{
+						{
+							Path:     "/",
+							PathType: ptr.To(networkingv1.PathTypePrefix),
+							Backend:  backend,
+						},
+					},
+				},
+			},
+		})
+	}
+	if opts.TLSSecret != "" {
+		ingress.Spec.TLS = []networkingv1.IngressTLS{{Hosts: opts.Hosts, SecretName: opts.TLSSecret}}
+	}
+	return ingress
+}
+
+// buildHTTPRoute builds an HTTPRoute. The fields defaulted by the API server are set explicitly,
+// so that updating the HTTPRoute leaves it unchanged.
+func buildHTTPRoute(meta metav1.ObjectMeta, opts IngressOptions, serviceName string, port int32) *gatewayv1.HTTPRoute {
+	route := &gatewayv1.HTTPRoute{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "HTTPRoute",
+			APIVersion: gatewayv1.GroupVersion.String(),
+		},
+		ObjectMeta: meta,
+		Spec: gatewayv1.HTTPRouteSpec{
+			Rules: []gatewayv1.HTTPRouteRule{
+				{
+					Matches: []gatewayv1.HTTPRouteMatch{
+						{
+							Path: &gatewayv1.HTTPPathMatch{
+								Type:  ptr.To(gatewayv1.PathMatchPathPrefix),
+								Value: ptr.To("/"),
+							},
+						},
+					},
+					BackendRefs: []gatewayv1.HTTPBackendRef{
+						{
+							BackendRef: gatewayv1.BackendRef{
+								BackendObjectReference: gatewayv1.BackendObjectReference{
+									Group: ptr.To(gatewayv1.Group("")),
+									Kind:  ptr.To(gatewayv1.Kind("Service")),
+									Name:  gatewayv1.ObjectName(serviceName),
+									Port:  ptr.To(gatewayv1.PortNumber(port)),
+								},
+								Weight: ptr.To(int32(1)),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, host := range opts.Hosts {
+		route.Spec.Hostnames = append(route.Spec.Hostnames, gatewayv1.Hostname(host))
+	}
+	for _, ref := range opts.ParentRefs {
+		parent := gatewayv1.ParentReference{
+			Group: ptr.To(gatewayv1.Group(gatewayv1.GroupName)),
+			Kind:  ptr.To(gatewayv1.Kind("Gateway")),
+			Name:  gatewayv1.ObjectName(ref.Name),
+		}
+		if ref.Namespace != "" {
+			parent.Namespace = ptr.To(gatewayv1.Namespace(ref.Namespace))
+		}
+		if ref.SectionName != "" {
+			parent.SectionName = ptr.To(gatewayv1.SectionName(ref.SectionName))
+		}
+		route.Spec.ParentRefs = append(route.Spec.ParentRefs, parent)
+	}
+	return route
+}
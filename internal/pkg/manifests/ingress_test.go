@@ -0,0 +1,46 @@
+package manifests
+
+import (
+	"testing"
+
+	"gotest.tools/v3/golden"
+	"sigs.k8s.io/yaml"
+)
+
+func TestBuildIngress(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		opts   IngressOptions
+		golden string
+	}{
+		{
+			name: "ingress",
+			opts: IngressOptions{
+				Hosts:       []string{"thanos.example.com"},
+				Annotations: map[string]string{"cert-manager.io/cluster-issuer": "letsencrypt"},
+				ClassName:   "nginx",
+				TLSSecret:   "thanos-tls",
+			},
+			golden: "ingress.golden.yaml",
+		},
+		{
+			name: "httproute",
+			opts: IngressOptions{
+				HTTPRoute:  true,
+				Hosts:      []string{"thanos.example.com"},
+				ParentRefs: []GatewayReference{{Name: "gateway", Namespace: "gateway-system", SectionName: "https"}},
+			},
+			golden: "httproute.golden.yaml",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := BuildIngress("thanos-query-frontend-example", "ns", map[string]string{NameLabel: "thanos-query-frontend"}, tc.opts, "thanos-query-frontend-example", 9090)
+
+			yamlBytes, err := yaml.Marshal(obj)
+			if err != nil {
+				t.Fatalf("failed to marshal object to YAML: %v", err)
+			}
+			golden.Assert(t, string(yamlBytes), tc.golden)
+		})
+	}
+}
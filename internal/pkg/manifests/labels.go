@@ -48,9 +48,36 @@ const (
 	// OwnerLabel is the label used to identify the owner of the object.
 	// This relates to the CustomResource or entity that created the object.
 	OwnerLabel = "operator.thanos.io/owner"
+	// OwnerUIDLabel is the label used to identify the UID of the owner of the object.
+	// It is set on every object applied by the operator, and used to prune the objects the owner no longer generates.
+	OwnerUIDLabel = "operator.thanos.io/owner-uid"
 
 	HashringLabel = "operator.thanos.io/hashring"
 	ShardLabel    = "operator.thanos.io/shard"
+
+	// GRPCTLSLabel is set on the Services of StoreAPI servers that require TLS, so that queriers connect to them over TLS.
+	GRPCTLSLabel      = "operator.thanos.io/grpc-tls"
+	GRPCTLSLabelValue = "true"
+
+	// GRPCPortAnnotation names the gRPC port of a StoreAPI Service whose gRPC port is not named "grpc",
+	// either by port name or by port number.
+	GRPCPortAnnotation = "monitoring.thanos.io/grpc-port"
+
+	// PendingDeletionLabel marks orphaned objects that will be deleted once the prune grace period expires.
+	PendingDeletionLabel = "operator.thanos.io/pending-deletion"
+	PendingDeletionValue = "true"
+	// PendingDeletionSinceAnnotation records when an orphaned object was marked for deletion, in RFC 3339 format.
+	PendingDeletionSinceAnnotation = "operator.thanos.io/pending-deletion-since"
+	// ScaleDownSinceAnnotation records when the scale down of a StatefulSet started, in RFC 3339 format.
+	ScaleDownSinceAnnotation = "operator.thanos.io/scale-down-since"
+	// HashringReplicasAnnotation records the replicas of an autoscaled StatefulSet that the hashring configuration
+	// was converged to.
+	HashringReplicasAnnotation = "operator.thanos.io/hashring-replicas"
+	// HashringReplicasSinceAnnotation records when the replicas of an autoscaled StatefulSet started to differ from
+	// those of the hashring configuration, in RFC 3339 format.
+	HashringReplicasSinceAnnotation = "operator.thanos.io/hashring-replicas-since"
+	// PodTemplateHashAnnotation records the hash of the pod template applied to a StatefulSet by the operator.
+	PodTemplateHashAnnotation = "operator.thanos.io/pod-template-hash"
 )
 
 // MergeMaps merges the provided labels with the default labels for a component.
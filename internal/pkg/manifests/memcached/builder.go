@@ -0,0 +1,238 @@
+package memcached
+
+import (
+	"fmt"
+
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Name is the name of the memcached component.
+	Name = "memcached"
+
+	// ComponentName is the name of the memcached component.
+	ComponentName = "cache"
+
+	Port     = 11211
+	PortName = "memcached"
+
+	// DefaultImage is the memcached image used if none is set.
+	DefaultImage = "docker.io/library/memcached:1.6.38-alpine"
+
+	// memoryOverhead is added to the memory used for items when sizing the container,
+	// to account for the connections and the hash table of memcached.
+	memoryOverhead = 64 << 20
+)
+
+// Options for a memcached managed by the operator for a Thanos component.
+type Options struct {
+	// Owner is the name of the resource the memcached is deployed for.
+	Owner string
+	// Parent is the generated name of the component using the memcached. It prefixes the name of the memcached,
+	// so that the caches of the components of resources sharing a name do not collide.
+	Parent    string
+	Namespace string
+	// Labels are merged with the default labels of the memcached.
+	Labels      map[string]string
+	Annotations map[string]string
+	Replicas    int32
+	// Image is the memcached image. DefaultImage is used if empty.
+	Image string
+	// MemoryLimit is the memory used for items by each replica, in bytes.
+	MemoryLimit int64
+	// MaxItemSize is the maximum size of an item, in bytes.
+	MaxItemSize int64
+	// ResourceRequirements of the memcached container.
+	// If not set, the memory is requested and limited to MemoryLimit plus an overhead.
+	ResourceRequirements *corev1.ResourceRequirements
+}
+
+// Build the memcached StatefulSet and its headless Service.
+func (opts Options) Build() []client.Object {
+	selectorLabels := opts.GetSelectorLabels()
+	objectMetaLabels := GetLabels(opts)
+	return []client.Object{
+		newMemcachedStatefulSet(opts, selectorLabels, objectMetaLabels),
+		newMemcachedService(opts, selectorLabels, objectMetaLabels),
+	}
+}
+
+func (opts Options) Valid() error {
+	if opts.Owner == "" {
+		return fmt.Errorf("owner cannot be empty")
+	}
+	if opts.Parent == "" {
+		return fmt.Errorf("parent cannot be empty")
+	}
+	return nil
+}
+
+// GetGeneratedResourceName returns the name of the memcached objects, which is the name of the parent suffixed with memcached.
+func (opts Options) GetGeneratedResourceName() string {
+	return manifests.ValidateAndSanitizeResourceNameToLength(opts.Parent+"-"+Name, manifests.StatefulSetNameMaxLength)
+}
+
+// ClientConfig returns the configuration of the memcached client of the component using the memcached.
+func (opts Options) ClientConfig() *manifests.MemcachedConfig {
+	return &manifests.MemcachedConfig{
+		Addresses:   []string{fmt.Sprintf("dnssrv+_%s._tcp.%s", PortName, manifests.ServiceHost(opts.GetGeneratedResourceName(), opts.Namespace))},
+		MaxItemSize: opts.MaxItemSize,
+	}
+}
+
+func newMemcachedStatefulSet(opts Options, selectorLabels, objectMetaLabels map[string]string) *appsv1.StatefulSet {
+	name := opts.GetGeneratedResourceName()
+	image := opts.Image
+	if image == "" {
+		image = DefaultImage
+	}
+	resources := corev1.ResourceRequirements{}
+	if opts.ResourceRequirements != nil {
+		resources = *opts.ResourceRequirements
+	} else if opts.MemoryLimit > 0 {
+		memory := *resource.NewQuantity(opts.MemoryLimit+memoryOverhead, resource.BinarySI)
+		resources = corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceMemory: memory},
+			Limits:   corev1.ResourceList{corev1.ResourceMemory: memory},
+		}
+	}
+
+	return &appsv1.StatefulSet{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "StatefulSet",
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   opts.Namespace,
+			Labels:      objectMetaLabels,
+			Annotations: opts.Annotations,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:    ptr.To(opts.Replicas),
+			ServiceName: name,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: selectorLabels,
+			},
+			// the replicas hold independent items, so they can be started and stopped in any order
+			PodManagementPolicy: appsv1.ParallelPodManagement,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: objectMetaLabels,
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:      Name,
+							Image:     image,
+							Args:      memcachedArgs(opts),
+							Resources: resources,
+							Ports: []corev1.ContainerPort{
+								{
+									Name:          PortName,
+									ContainerPort: Port,
+									Protocol:      corev1.ProtocolTCP,
+								},
+							},
+							SecurityContext: &corev1.SecurityContext{
+								AllowPrivilegeEscalation: ptr.To(false),
+								RunAsNonRoot:             ptr.To(true),
+								RunAsUser:                ptr.To(int64(11211)),
+								Capabilities: &corev1.Capabilities{
+									Drop: []corev1.Capability{
+										"ALL",
+									},
+								},
+								SeccompProfile: &corev1.SeccompProfile{
+									Type: corev1.SeccompProfileTypeRuntimeDefault,
+								},
+							},
+							LivenessProbe: &corev1.Probe{
+								ProbeHandler: corev1.ProbeHandler{
+									TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(Port)},
+								},
+							},
+							ReadinessProbe: &corev1.Probe{
+								ProbeHandler: corev1.ProbeHandler{
+									TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(Port)},
+								},
+							},
+							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newMemcachedService(opts Options, selectorLabels, objectMetaLabels map[string]string) *corev1.Service {
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Service",
+			APIVersion: corev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        opts.GetGeneratedResourceName(),
+			Namespace:   opts.Namespace,
+			Labels:      objectMetaLabels,
+			Annotations: opts.Annotations,
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{
+					Name:       PortName,
+					Port:       Port,
+					TargetPort: intstr.FromInt32(Port),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+			Selector:  selectorLabels,
+			ClusterIP: corev1.ClusterIPNone,
+		},
+	}
+}
+
+func memcachedArgs(opts Options) []string {
+	args := []string{fmt.Sprintf("--port=%d", Port)}
+	if opts.MemoryLimit > 0 {
+		args = append(args, fmt.Sprintf("--memory-limit=%d", opts.MemoryLimit>>20))
+	}
+	if opts.MaxItemSize > 0 {
+		args = append(args, fmt.Sprintf("--max-item-size=%d", opts.MaxItemSize))
+	}
+	return args
+}
+
+// GetRequiredLabels returns a map of labels that can be used to look up memcached resources.
+// These labels are guaranteed to be present on all resources created by this package.
+func GetRequiredLabels() map[string]string {
+	return map[string]string{
+		manifests.NameLabel:      Name,
+		manifests.ComponentLabel: ComponentName,
+		manifests.PartOfLabel:    manifests.DefaultPartOfLabel,
+		manifests.ManagedByLabel: manifests.DefaultManagedByLabel,
+	}
+}
+
+// GetSelectorLabels returns a map of labels that can be used to look up memcached resources.
+func (opts Options) GetSelectorLabels() map[string]string {
+	labels := GetRequiredLabels()
+	labels[manifests.InstanceLabel] = manifests.ValidateAndSanitizeNameToValidLabelValue(opts.GetGeneratedResourceName())
+	labels[manifests.OwnerLabel] = manifests.ValidateAndSanitizeNameToValidLabelValue(opts.Owner)
+	return labels
+}
+
+// GetLabels returns a map of labels that can be used to look up memcached resources.
+func GetLabels(opts Options) map[string]string {
+	return manifests.MergeMaps(opts.Labels, opts.GetSelectorLabels())
+}
@@ -0,0 +1,76 @@
+package memcached
+
+import (
+	"testing"
+
+	"gotest.tools/v3/golden"
+
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"sigs.k8s.io/yaml"
+)
+
+func TestBuildMemcached(t *testing.T) {
+	opts := Options{
+		Owner:       "example",
+		Parent:      "thanos-store-example",
+		Namespace:   "ns",
+		Replicas:    2,
+		MemoryLimit: 1 << 30,
+		MaxItemSize: 1 << 20,
+	}
+
+	objs := opts.Build()
+	if len(objs) != 2 {
+		t.Fatalf("expected 2 objects, got %d", len(objs))
+	}
+	for _, obj := range objs {
+		if obj.GetName() != "thanos-store-example-memcached" {
+			t.Errorf("expected the objects to be named after the parent, got %s", obj.GetName())
+		}
+		if obj.GetLabels()[manifests.OwnerLabel] != "example" {
+			t.Errorf("expected the objects to be owned by example, got %v", obj.GetLabels())
+		}
+	}
+
+	yamlBytes, err := yaml.Marshal(objs[0])
+	if err != nil {
+		t.Fatalf("failed to marshal the StatefulSet: %v", err)
+	}
+	golden.Assert(t, string(yamlBytes), "statefulset-basic.golden.yaml")
+
+	svc := objs[1].(*corev1.Service)
+	if svc.Spec.ClusterIP != corev1.ClusterIPNone {
+		t.Errorf("expected a headless Service, got %q", svc.Spec.ClusterIP)
+	}
+
+	config := opts.ClientConfig()
+	if len(config.Addresses) != 1 || config.Addresses[0] != "dnssrv+_memcached._tcp.thanos-store-example-memcached.ns.svc" {
+		t.Errorf("expected the Service to be discovered through DNS SRV, got %v", config.Addresses)
+	}
+	if config.MaxItemSize != opts.MaxItemSize {
+		t.Errorf("expected the max item size of the servers, got %d", config.MaxItemSize)
+	}
+}
+
+func TestMemcachedResources(t *testing.T) {
+	opts := Options{Owner: "example", Parent: "thanos-store-example", Namespace: "ns", Replicas: 1, MemoryLimit: 1 << 30}
+	sts := opts.Build()[0].(*appsv1.StatefulSet)
+	memory := sts.Spec.Template.Spec.Containers[0].Resources.Limits[corev1.ResourceMemory]
+	if want := resource.NewQuantity(1<<30+memoryOverhead, resource.BinarySI); memory.Cmp(*want) != 0 {
+		t.Errorf("expected a memory limit of %s, got %s", want, &memory)
+	}
+
+	opts.ResourceRequirements = &corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+	}
+	sts = opts.Build()[0].(*appsv1.StatefulSet)
+	memory = sts.Spec.Template.Spec.Containers[0].Resources.Limits[corev1.ResourceMemory]
+	if memory.String() != "4Gi" {
+		t.Errorf("expected the configured memory limit, got %s", &memory)
+	}
+}
@@ -0,0 +1,69 @@
+package manifests
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// MetricsPortName is the name of the port exposing metrics on the dedicated metrics Service.
+	MetricsPortName = "http-metrics"
+
+	// MetricsServiceLabel is used to identify the dedicated metrics Services created by the operator.
+	MetricsServiceLabel = "operator.thanos.io/metrics-service"
+	MetricsServiceValue = "true"
+
+	metricsServiceSuffix = "-metrics"
+)
+
+// MetricsServiceName returns the name of the dedicated metrics Service for the resource with the given name.
+func MetricsServiceName(name string) string {
+	return ValidateAndSanitizeResourceName(name + metricsServiceSuffix)
+}
+
+// MetricsServiceLabels returns a copy of the given labels that identifies a dedicated metrics Service.
+// The labels used to discover StoreAPIs and QueryAPIs are removed, so that the metrics Service
+// is never registered as an endpoint of a Thanos Query.
+func MetricsServiceLabels(labels map[string]string) map[string]string {
+	metricsLabels := MergeMaps(labels, map[string]string{MetricsServiceLabel: MetricsServiceValue})
+	for _, label := range []string{
+		DefaultStoreAPILabel,
+		DefaultQueryAPILabel,
+		string(RegularLabel),
+		string(StrictLabel),
+		string(GroupLabel),
+		string(GroupStrictLabel),
+	} {
+		delete(metricsLabels, label)
+	}
+	return metricsLabels
+}
+
+// BuildMetricsService builds a headless Service that only exposes the HTTP port of the pods matching
+// selectorLabels, on a port named MetricsPortName.
+// Thanos serves its metrics on the HTTP port, so port must be the HTTP port of the component.
+func BuildMetricsService(name, namespace string, objectMetaLabels, selectorLabels, annotations map[string]string, port int32) *corev1.Service {
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        MetricsServiceName(name),
+			Namespace:   namespace,
+			Labels:      MetricsServiceLabels(objectMetaLabels),
+			Annotations: annotations,
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: corev1.ClusterIPNone,
+			Selector:  selectorLabels,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       MetricsPortName,
+					Port:       port,
+					TargetPort: intstr.FromInt32(port),
+				},
+			},
+		},
+	}
+}
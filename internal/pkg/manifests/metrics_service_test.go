@@ -0,0 +1,39 @@
+package manifests
+
+import (
+	"testing"
+
+	"gotest.tools/v3/golden"
+	"sigs.k8s.io/yaml"
+)
+
+func TestBuildMetricsService(t *testing.T) {
+	const (
+		name = "thanos-stack"
+		ns   = "ns"
+	)
+
+	objectMetaLabels := map[string]string{
+		NameLabel:            "thanos-store",
+		InstanceLabel:        "thanos-stack",
+		DefaultStoreAPILabel: DefaultStoreAPIValue,
+		string(GroupLabel):   "true",
+	}
+	selectorLabels := map[string]string{
+		NameLabel:            "thanos-store",
+		InstanceLabel:        "thanos-stack",
+		DefaultStoreAPILabel: DefaultStoreAPIValue,
+	}
+
+	svc := BuildMetricsService(name, ns, objectMetaLabels, selectorLabels, map[string]string{"test": "annotation"}, 10902)
+
+	yamlBytes, err := yaml.Marshal(svc)
+	if err != nil {
+		t.Fatalf("failed to marshal service to YAML: %v", err)
+	}
+	golden.Assert(t, string(yamlBytes), "metrics-service-basic.golden.yaml")
+
+	if _, ok := objectMetaLabels[DefaultStoreAPILabel]; !ok {
+		t.Errorf("expected labels passed to BuildMetricsService to be left unchanged")
+	}
+}
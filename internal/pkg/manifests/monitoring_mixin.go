@@ -0,0 +1,199 @@
+package manifests
+
+import (
+	"encoding/json"
+	"fmt"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// GrafanaDashboardLabel is the label the Grafana dashboard sidecar discovers dashboard ConfigMaps by.
+	GrafanaDashboardLabel = "grafana_dashboard"
+	GrafanaDashboardValue = "1"
+
+	dashboardSuffix = "-dashboard"
+	// dashboardUIDLength keeps the UID of the dashboards within the 40 characters allowed by Grafana.
+	dashboardUIDLength = 32
+)
+
+// MonitoringMixinConfig is the configuration of the dashboards and alerting rules built for a component.
+type MonitoringMixinConfig struct {
+	// Labels are added to the dashboard ConfigMaps and the PrometheusRules.
+	Labels map[string]string
+}
+
+// Dashboard is a Grafana dashboard of time series panels.
+type Dashboard struct {
+	Title  string
+	Panels []DashboardPanel
+}
+
+// DashboardPanel is a time series panel of a Dashboard.
+type DashboardPanel struct {
+	Title string
+	// Unit is the Grafana unit of the values, such as reqps, s or percentunit.
+	Unit    string
+	Queries []DashboardQuery
+}
+
+// DashboardQuery is a PromQL query of a DashboardPanel.
+type DashboardQuery struct {
+	Expr   string
+	Legend string
+}
+
+// DashboardName returns the name of the dashboard ConfigMap for the resource with the given name.
+func DashboardName(name string) string {
+	return ValidateAndSanitizeResourceName(name + dashboardSuffix)
+}
+
+// ScrapeJobSelector returns the PromQL label matchers selecting the metrics scraped from the Service with the given name,
+// or from its dedicated metrics Service, by the ServiceMonitors of the operator.
+func ScrapeJobSelector(namespace, service string) string {
+	return fmt.Sprintf(`namespace="%s", job=~"%s|%s"`, namespace, service, MetricsServiceName(service))
+}
+
+// BuildDashboardConfigMap builds the ConfigMap holding the dashboard, labelled to be discovered by the Grafana sidecar.
+func BuildDashboardConfigMap(name, namespace string, objectMetaLabels map[string]string, dashboard Dashboard) *corev1.ConfigMap {
+	configMapName := DashboardName(name)
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: corev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      configMapName,
+			Namespace: namespace,
+			Labels:    MergeMaps(objectMetaLabels, map[string]string{GrafanaDashboardLabel: GrafanaDashboardValue}),
+		},
+		Data: map[string]string{
+			configMapName + ".json": dashboard.json(hashString(namespace+"/"+name, dashboardUIDLength)),
+		},
+	}
+}
+
+// BuildPrometheusRule builds the PrometheusRule holding the alerting rules of a component.
+func BuildPrometheusRule(name, namespace string, objectMetaLabels map[string]string, groups []monitoringv1.RuleGroup) *monitoringv1.PrometheusRule {
+	return &monitoringv1.PrometheusRule{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       monitoringv1.PrometheusRuleKind,
+			APIVersion: monitoringv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    objectMetaLabels,
+		},
+		Spec: monitoringv1.PrometheusRuleSpec{
+			Groups: groups,
+		},
+	}
+}
+
+type grafanaDashboard struct {
+	UID           string            `json:"uid"`
+	Title         string            `json:"title"`
+	Tags          []string          `json:"tags"`
+	Editable      bool              `json:"editable"`
+	SchemaVersion int               `json:"schemaVersion"`
+	Refresh       string            `json:"refresh"`
+	Time          map[string]string `json:"time"`
+	Templating    grafanaTemplating `json:"templating"`
+	Panels        []grafanaPanel    `json:"panels"`
+}
+
+type grafanaTemplating struct {
+	List []grafanaVariable `json:"list"`
+}
+
+type grafanaVariable struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+	Type  string `json:"type"`
+	Query string `json:"query"`
+}
+
+type grafanaPanel struct {
+	ID          int                `json:"id"`
+	Type        string             `json:"type"`
+	Title       string             `json:"title"`
+	Datasource  grafanaDatasource  `json:"datasource"`
+	GridPos     grafanaGridPos     `json:"gridPos"`
+	FieldConfig grafanaFieldConfig `json:"fieldConfig"`
+	Targets     []grafanaTarget    `json:"targets"`
+}
+
+type grafanaDatasource struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+type grafanaGridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type grafanaFieldConfig struct {
+	Defaults  grafanaFieldDefaults `json:"defaults"`
+	Overrides []any                `json:"overrides"`
+}
+
+type grafanaFieldDefaults struct {
+	Unit string `json:"unit,omitempty"`
+}
+
+type grafanaTarget struct {
+	RefID        string            `json:"refId"`
+	Datasource   grafanaDatasource `json:"datasource"`
+	Expr         string            `json:"expr"`
+	LegendFormat string            `json:"legendFormat,omitempty"`
+}
+
+// json returns the Grafana JSON model of the dashboard, laying the panels out two per row.
+func (d Dashboard) json(uid string) string {
+	datasource := grafanaDatasource{Type: "prometheus", UID: "${datasource}"}
+	model := grafanaDashboard{
+		UID:           uid,
+		Title:         d.Title,
+		Tags:          []string{"thanos", "thanos-operator"},
+		SchemaVersion: 39,
+		Refresh:       "30s",
+		Time:          map[string]string{"from": "now-6h", "to": "now"},
+		Templating: grafanaTemplating{List: []grafanaVariable{
+			{Name: "datasource", Label: "Data source", Type: "datasource", Query: "prometheus"},
+		}},
+		Panels: make([]grafanaPanel, 0, len(d.Panels)),
+	}
+	for i, panel := range d.Panels {
+		p := grafanaPanel{
+			ID:          i + 1,
+			Type:        "timeseries",
+			Title:       panel.Title,
+			Datasource:  datasource,
+			GridPos:     grafanaGridPos{H: 8, W: 12, X: (i % 2) * 12, Y: (i / 2) * 8},
+			FieldConfig: grafanaFieldConfig{Defaults: grafanaFieldDefaults{Unit: panel.Unit}, Overrides: []any{}},
+			Targets:     make([]grafanaTarget, 0, len(panel.Queries)),
+		}
+		for j, query := range panel.Queries {
+			p.Targets = append(p.Targets, grafanaTarget{
+				RefID:        string(rune('A' + j)),
+				Datasource:   datasource,
+				Expr:         query.Expr,
+				LegendFormat: query.Legend,
+			})
+		}
+		model.Panels = append(model.Panels, p)
+	}
+
+	out, err := json.MarshalIndent(model, "", "  ")
+	if err != nil {
+		panic(fmt.Sprintf("failed to marshal dashboard %s: %v", d.Title, err))
+	}
+	return string(out)
+}
@@ -0,0 +1,41 @@
+package manifests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestBuildDashboardConfigMap(t *testing.T) {
+	dashboard := Dashboard{
+		Title: "Thanos",
+		Panels: []DashboardPanel{
+			{Title: "a", Unit: "reqps", Queries: []DashboardQuery{{Expr: "up", Legend: "{{pod}}"}, {Expr: "vector(1)"}}},
+			{Title: "b"},
+			{Title: "c"},
+		},
+	}
+	cm := BuildDashboardConfigMap("thanos-query-any", "ns", map[string]string{"team": "observability"}, dashboard)
+
+	assert.Equal(t, cm.GetName(), "thanos-query-any-dashboard")
+	assert.Equal(t, cm.GetLabels()[GrafanaDashboardLabel], GrafanaDashboardValue)
+	assert.Equal(t, cm.GetLabels()["team"], "observability")
+
+	var model grafanaDashboard
+	assert.NilError(t, json.Unmarshal([]byte(cm.Data["thanos-query-any-dashboard.json"]), &model))
+	assert.Equal(t, model.Title, "Thanos")
+	assert.Equal(t, len(model.UID), dashboardUIDLength)
+	assert.Equal(t, len(model.Panels), 3)
+	assert.Equal(t, model.Panels[0].Targets[1].RefID, "B")
+	assert.Equal(t, model.Panels[0].FieldConfig.Defaults.Unit, "reqps")
+	assert.DeepEqual(t, model.Panels[1].GridPos, grafanaGridPos{H: 8, W: 12, X: 12, Y: 0})
+	assert.DeepEqual(t, model.Panels[2].GridPos, grafanaGridPos{H: 8, W: 12, X: 0, Y: 8})
+
+	other := BuildDashboardConfigMap("thanos-query-any", "other", nil, dashboard)
+	assert.Assert(t, other.Data["thanos-query-any-dashboard.json"] != cm.Data["thanos-query-any-dashboard.json"], "expected dashboards in different namespaces to have distinct UIDs")
+}
+
+func TestScrapeJobSelector(t *testing.T) {
+	assert.Equal(t, ScrapeJobSelector("ns", "thanos-query-any"), `namespace="ns", job=~"thanos-query-any|thanos-query-any-metrics"`)
+}
@@ -9,12 +9,15 @@ import (
 	"dario.cat/mergo"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 // MutateFuncFor returns a mutate function based on the existing resource's concrete type.
@@ -28,8 +31,12 @@ import (
 //   - StatefulSet
 //   - ServiceMonitor
 //   - PodDisruptionBudget
+//   - CronJob
+//   - Job
 //   - Role
 //   - RoleBinding
+//   - Ingress
+//   - HTTPRoute
 func MutateFuncFor(existing, desired client.Object) controllerutil.MutateFn {
 	return func() error {
 		existingAnnotations := existing.GetAnnotations()
@@ -91,6 +98,14 @@ func MutateFuncFor(existing, desired client.Object) controllerutil.MutateFn {
 			wantPdb := desired.(*policyv1.PodDisruptionBudget)
 			mutatePodDisruptionBudget(pdb, wantPdb)
 
+		case *batchv1.CronJob:
+			cj := existing.(*batchv1.CronJob)
+			wantCj := desired.(*batchv1.CronJob)
+			mutateCronJob(cj, wantCj)
+
+		case *batchv1.Job:
+			// the spec of a Job is immutable, generated Jobs are renamed instead when it changes
+
 		case *rbacv1.Role:
 			role := existing.(*rbacv1.Role)
 			wantRole := desired.(*rbacv1.Role)
@@ -100,6 +115,21 @@ func MutateFuncFor(existing, desired client.Object) controllerutil.MutateFn {
 			rb := existing.(*rbacv1.RoleBinding)
 			wantRb := desired.(*rbacv1.RoleBinding)
 			mutateRoleBinding(rb, wantRb)
+
+		case *networkingv1.Ingress:
+			ing := existing.(*networkingv1.Ingress)
+			wantIng := desired.(*networkingv1.Ingress)
+			mutateIngress(ing, wantIng)
+
+		case *gatewayv1.HTTPRoute:
+			route := existing.(*gatewayv1.HTTPRoute)
+			wantRoute := desired.(*gatewayv1.HTTPRoute)
+			mutateHTTPRoute(route, wantRoute)
+
+		case *networkingv1.NetworkPolicy:
+			policy := existing.(*networkingv1.NetworkPolicy)
+			wantPolicy := desired.(*networkingv1.NetworkPolicy)
+			mutateNetworkPolicy(policy, wantPolicy)
 		default:
 			t := reflect.TypeOf(existing).String()
 			return fmt.Errorf("missing mutate implementation for resource type %v", t)
@@ -139,6 +169,14 @@ func mutateServiceAccount(existing, desired *corev1.ServiceAccount) {
 func mutateService(existing, desired *corev1.Service) {
 	existing.Spec.Ports = desired.Spec.Ports
 	existing.Spec.Selector = desired.Spec.Selector
+	existing.Spec.SessionAffinity = desired.Spec.SessionAffinity
+	existing.Spec.SessionAffinityConfig = desired.Spec.SessionAffinityConfig
+	existing.Spec.Type = desired.Spec.Type
+	existing.Spec.LoadBalancerSourceRanges = desired.Spec.LoadBalancerSourceRanges
+	existing.Spec.ExternalTrafficPolicy = desired.Spec.ExternalTrafficPolicy
+	existing.Spec.InternalTrafficPolicy = desired.Spec.InternalTrafficPolicy
+	existing.Spec.PublishNotReadyAddresses = desired.Spec.PublishNotReadyAddresses
+	existing.Spec.TrafficDistribution = desired.Spec.TrafficDistribution
 	existing.Labels = desired.Labels
 }
 
@@ -148,8 +186,14 @@ func mutateDeployment(existing, desired *appsv1.Deployment) {
 	if existing.CreationTimestamp.IsZero() {
 		existing.Spec.Selector = desired.Spec.Selector
 	}
-	existing.Spec.Replicas = desired.Spec.Replicas
+	// replicas left unset are managed externally, such as by a HorizontalPodAutoscaler
+	if desired.Spec.Replicas != nil {
+		existing.Spec.Replicas = desired.Spec.Replicas
+	}
 	existing.Spec.Strategy = desired.Spec.Strategy
+	existing.Spec.MinReadySeconds = desired.Spec.MinReadySeconds
+	existing.Spec.RevisionHistoryLimit = defaultedPtr(existing.Spec.RevisionHistoryLimit, desired.Spec.RevisionHistoryLimit, 10)
+	existing.Spec.ProgressDeadlineSeconds = defaultedPtr(existing.Spec.ProgressDeadlineSeconds, desired.Spec.ProgressDeadlineSeconds, 600)
 	mutatePodTemplate(&existing.Spec.Template, &desired.Spec.Template)
 }
 
@@ -163,6 +207,8 @@ func mutateStatefulSet(existing, desired *appsv1.StatefulSet) {
 	existing.Spec.MinReadySeconds = desired.Spec.MinReadySeconds
 	existing.Spec.PodManagementPolicy = desired.Spec.PodManagementPolicy
 	existing.Spec.PersistentVolumeClaimRetentionPolicy = desired.Spec.PersistentVolumeClaimRetentionPolicy
+	existing.Spec.UpdateStrategy = desired.Spec.UpdateStrategy
+	existing.Spec.RevisionHistoryLimit = defaultedPtr(existing.Spec.RevisionHistoryLimit, desired.Spec.RevisionHistoryLimit, 10)
 	mutatePodTemplate(&existing.Spec.Template, &desired.Spec.Template)
 }
 
@@ -183,14 +229,49 @@ func mutatePodSpec(existing *corev1.PodSpec, desired *corev1.PodSpec) {
 	existing.TerminationGracePeriodSeconds = desired.TerminationGracePeriodSeconds
 	existing.SecurityContext = desired.SecurityContext
 	existing.ServiceAccountName = desired.ServiceAccountName
+	existing.ImagePullSecrets = desired.ImagePullSecrets
+	existing.PriorityClassName = desired.PriorityClassName
+	existing.AutomountServiceAccountToken = desired.AutomountServiceAccountToken
+	existing.HostNetwork = desired.HostNetwork
+	existing.HostPID = desired.HostPID
+	existing.HostIPC = desired.HostIPC
+	existing.ShareProcessNamespace = desired.ShareProcessNamespace
+	existing.HostAliases = desired.HostAliases
+	existing.DNSConfig = desired.DNSConfig
+	existing.RuntimeClassName = desired.RuntimeClassName
+	existing.ReadinessGates = desired.ReadinessGates
+	existing.DNSPolicy = defaulted(existing.DNSPolicy, desired.DNSPolicy, corev1.DNSClusterFirst)
+	existing.SchedulerName = defaulted(existing.SchedulerName, desired.SchedulerName, corev1.DefaultSchedulerName)
+	existing.EnableServiceLinks = defaultedPtr(existing.EnableServiceLinks, desired.EnableServiceLinks, corev1.DefaultEnableServiceLinks)
+}
+
+// defaulted returns the value of a field defaulted by the API server. A field left unset by the desired object keeps
+// its default, so that applying the desired object does not update it on every reconcile, and is reset otherwise.
+func defaulted[T comparable](existing, desired, def T) T {
+	var zero T
+	if desired == zero && existing == def {
+		return existing
+	}
+	return desired
+}
+
+// defaultedPtr is defaulted for pointer fields.
+func defaultedPtr[T comparable](existing, desired *T, def T) *T {
+	if desired == nil && existing != nil && *existing == def {
+		return existing
+	}
+	return desired
 }
 
 func mutateServiceMonitor(existing, desired *monitoringv1.ServiceMonitor) {
 	existing.Labels = desired.Labels
 	existing.Annotations = desired.Annotations
-	existing.Spec.Selector.MatchLabels = desired.Spec.Selector.MatchLabels
+	existing.Spec.Selector = desired.Spec.Selector
 	existing.Spec.NamespaceSelector = desired.Spec.NamespaceSelector
 	existing.Spec.Endpoints = desired.Spec.Endpoints
+	existing.Spec.JobLabel = desired.Spec.JobLabel
+	existing.Spec.TargetLabels = desired.Spec.TargetLabels
+	existing.Spec.PodTargetLabels = desired.Spec.PodTargetLabels
 }
 
 func mutatePodDisruptionBudget(existing, desired *policyv1.PodDisruptionBudget) {
@@ -199,6 +280,25 @@ func mutatePodDisruptionBudget(existing, desired *policyv1.PodDisruptionBudget)
 	existing.Spec = desired.Spec
 }
 
+func mutateCronJob(existing, desired *batchv1.CronJob) {
+	existing.Annotations = desired.Annotations
+	existing.Labels = desired.Labels
+	existing.Spec.Schedule = desired.Spec.Schedule
+	existing.Spec.ConcurrencyPolicy = desired.Spec.ConcurrencyPolicy
+	existing.Spec.SuccessfulJobsHistoryLimit = desired.Spec.SuccessfulJobsHistoryLimit
+	existing.Spec.FailedJobsHistoryLimit = desired.Spec.FailedJobsHistoryLimit
+	existing.Spec.StartingDeadlineSeconds = desired.Spec.StartingDeadlineSeconds
+	existing.Spec.TimeZone = desired.Spec.TimeZone
+	existing.Spec.JobTemplate.Labels = desired.Spec.JobTemplate.Labels
+	existing.Spec.JobTemplate.Annotations = desired.Spec.JobTemplate.Annotations
+	existing.Spec.JobTemplate.Spec.TTLSecondsAfterFinished = desired.Spec.JobTemplate.Spec.TTLSecondsAfterFinished
+	existing.Spec.JobTemplate.Spec.BackoffLimit = desired.Spec.JobTemplate.Spec.BackoffLimit
+	existing.Spec.JobTemplate.Spec.ActiveDeadlineSeconds = desired.Spec.JobTemplate.Spec.ActiveDeadlineSeconds
+	mutatePodTemplate(&existing.Spec.JobTemplate.Spec.Template, &desired.Spec.JobTemplate.Spec.Template)
+	existing.Spec.JobTemplate.Spec.Template.Spec.RestartPolicy = desired.Spec.JobTemplate.Spec.Template.Spec.RestartPolicy
+	existing.Spec.JobTemplate.Spec.Template.Spec.AutomountServiceAccountToken = desired.Spec.JobTemplate.Spec.Template.Spec.AutomountServiceAccountToken
+}
+
 func mutateRole(existing, desired *rbacv1.Role) {
 	existing.Annotations = desired.Annotations
 	existing.Labels = desired.Labels
@@ -211,3 +311,20 @@ func mutateRoleBinding(existing, desired *rbacv1.RoleBinding) {
 	existing.Subjects = desired.Subjects
 	existing.RoleRef = desired.RoleRef
 }
+
+func mutateIngress(existing, desired *networkingv1.Ingress) {
+	existing.Annotations = desired.Annotations
+	existing.Labels = desired.Labels
+	existing.Spec = desired.Spec
+}
+
+func mutateHTTPRoute(existing, desired *gatewayv1.HTTPRoute) {
+	existing.Annotations = desired.Annotations
+	existing.Labels = desired.Labels
+	existing.Spec = desired.Spec
+}
+
+func mutateNetworkPolicy(existing, desired *networkingv1.NetworkPolicy) {
+	existing.Labels = desired.Labels
+	existing.Spec = desired.Spec
+}
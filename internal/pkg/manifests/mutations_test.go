@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	discoveryv1 "k8s.io/api/discovery/v1"
 	policyv1 "k8s.io/api/policy/v1"
@@ -112,6 +113,13 @@ func TestGetMutateFunc_MutateServiceSpec(t *testing.T) {
 				"select": "that",
 				"and":    "other",
 			},
+			SessionAffinity: corev1.ServiceAffinityClientIP,
+			SessionAffinityConfig: &corev1.SessionAffinityConfig{
+				ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: ptr.To(int32(3600))},
+			},
+			Type:                     corev1.ServiceTypeLoadBalancer,
+			LoadBalancerSourceRanges: []string{"10.0.0.0/8"},
+			ExternalTrafficPolicy:    corev1.ServiceExternalTrafficPolicyLocal,
 		},
 	}
 
@@ -122,6 +130,11 @@ func TestGetMutateFunc_MutateServiceSpec(t *testing.T) {
 	// Ensure partial mutation applied
 	require.ElementsMatch(t, got.Spec.Ports, want.Spec.Ports)
 	require.Exactly(t, got.Spec.Selector, want.Spec.Selector)
+	require.Equal(t, got.Spec.SessionAffinity, want.Spec.SessionAffinity)
+	require.Exactly(t, got.Spec.SessionAffinityConfig, want.Spec.SessionAffinityConfig)
+	require.Equal(t, got.Spec.Type, want.Spec.Type)
+	require.Exactly(t, got.Spec.LoadBalancerSourceRanges, want.Spec.LoadBalancerSourceRanges)
+	require.Equal(t, got.Spec.ExternalTrafficPolicy, want.Spec.ExternalTrafficPolicy)
 
 	// Ensure not mutated
 	require.Equal(t, got.Spec.ClusterIP, "none")
@@ -406,6 +419,31 @@ func TestMutateFuncFor_MutateDeploymentSpec(t *testing.T) {
 	}
 }
 
+func TestMutateFuncFor_MutateDeploymentExternalReplicas(t *testing.T) {
+	got := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.Now()},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To[int32](5),
+		},
+	}
+	want := &appsv1.Deployment{
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "test"}},
+				},
+			},
+		},
+	}
+
+	f := MutateFuncFor(got, want)
+	require.NoError(t, f())
+
+	// Replicas set by an autoscaler are kept
+	require.Equal(t, ptr.To[int32](5), got.Spec.Replicas)
+	require.Equal(t, want.Spec.Template, got.Spec.Template)
+}
+
 func TestMutateFuncFor_MutateStatefulSetSpec(t *testing.T) {
 	type test struct {
 		name string
@@ -688,6 +726,89 @@ func TestMutateFuncFor_MutatePodDisruptionBudget(t *testing.T) {
 	require.Exactly(t, got.Spec, want.Spec)
 }
 
+func TestMutateFuncFor_MutateCronJob(t *testing.T) {
+	got := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				"test":  "test",
+				"other": "label",
+			},
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule: "*/5 * * * *",
+			Suspend:  ptr.To(true),
+		},
+	}
+
+	want := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				"other": "label",
+				"new":   "label",
+			},
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:          "*/10 * * * *",
+			ConcurrencyPolicy: batchv1.ForbidConcurrent,
+			JobTemplate: batchv1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					BackoffLimit: ptr.To(int32(0)),
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							RestartPolicy: corev1.RestartPolicyNever,
+							Containers:    []corev1.Container{{Name: "probe", Image: "probe:latest"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	f := MutateFuncFor(got, want)
+	err := f()
+
+	require.NoError(t, err)
+	require.Exactly(t, got.Labels, want.Labels)
+	require.Exactly(t, got.Spec.Schedule, want.Spec.Schedule)
+	require.Exactly(t, got.Spec.ConcurrencyPolicy, want.Spec.ConcurrencyPolicy)
+	require.Exactly(t, got.Spec.JobTemplate, want.Spec.JobTemplate)
+	// fields not managed by the operator are left untouched
+	require.Exactly(t, got.Spec.Suspend, ptr.To(true))
+}
+
+func TestMutateFuncFor_MutateJob(t *testing.T) {
+	got := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"test": "test"},
+		},
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"batch.kubernetes.io/controller-uid": "uid"},
+				},
+			},
+		},
+	}
+
+	want := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"new": "label"},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: ptr.To(int32(0)),
+		},
+	}
+	spec := got.Spec.DeepCopy()
+
+	f := MutateFuncFor(got, want)
+	err := f()
+
+	require.NoError(t, err)
+	require.Exactly(t, map[string]string{"test": "test", "new": "label"}, got.Labels)
+	// the spec of a Job is immutable
+	require.Exactly(t, *spec, got.Spec)
+}
+
 func TestMutateFuncFor_ServiceMonitor(t *testing.T) {
 	got := &monitoringv1.ServiceMonitor{
 		Spec: monitoringv1.ServiceMonitorSpec{
@@ -723,3 +844,42 @@ func TestMutateFuncFor_ServiceMonitor(t *testing.T) {
 	require.Exactly(t, got.Spec.NamespaceSelector, want.Spec.NamespaceSelector)
 	require.Exactly(t, got.Spec.Selector, want.Spec.Selector)
 }
+
+func TestMutateFuncFor_ServerDefaultedPodSpec(t *testing.T) {
+	defaulted := func() *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.Now()},
+			Spec: appsv1.DeploymentSpec{
+				RevisionHistoryLimit:    ptr.To[int32](10),
+				ProgressDeadlineSeconds: ptr.To[int32](600),
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						DNSPolicy:          corev1.DNSClusterFirst,
+						SchedulerName:      corev1.DefaultSchedulerName,
+						EnableServiceLinks: ptr.To(true),
+					},
+				},
+			},
+		}
+	}
+	want := &appsv1.Deployment{}
+
+	// values defaulted by the API server are kept, so that the object is not updated on every reconcile
+	got := defaulted()
+	require.NoError(t, MutateFuncFor(got, want)())
+	require.Exactly(t, defaulted().Spec, got.Spec)
+
+	// values edited out of band are reverted
+	got = defaulted()
+	got.Spec.RevisionHistoryLimit = ptr.To[int32](1)
+	got.Spec.Template.Spec.DNSPolicy = corev1.DNSNone
+	got.Spec.Template.Spec.DNSConfig = &corev1.PodDNSConfig{Nameservers: []string{"1.1.1.1"}}
+	got.Spec.Template.Spec.EnableServiceLinks = ptr.To(false)
+	got.Spec.Template.Spec.AutomountServiceAccountToken = ptr.To(false)
+	require.NoError(t, MutateFuncFor(got, want)())
+	require.Nil(t, got.Spec.RevisionHistoryLimit)
+	require.Empty(t, got.Spec.Template.Spec.DNSPolicy)
+	require.Nil(t, got.Spec.Template.Spec.DNSConfig)
+	require.Nil(t, got.Spec.Template.Spec.EnableServiceLinks)
+	require.Nil(t, got.Spec.Template.Spec.AutomountServiceAccountToken)
+}
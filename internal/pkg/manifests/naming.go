@@ -0,0 +1,45 @@
+package manifests
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultNameTemplate is the default template of the names of the objects generated for a component.
+	DefaultNameTemplate = ComponentNamePlaceholder + "-" + OwnerNamePlaceholder
+
+	// ComponentNamePlaceholder is replaced with the name of the component, such as thanos-query, in a name template.
+	ComponentNamePlaceholder = "{component}"
+	// OwnerNamePlaceholder is replaced with the name of the owning resource, followed by the name of the hashring
+	// or shard if any, in a name template.
+	OwnerNamePlaceholder = "{name}"
+
+	// StatefulSetNameMaxLength is the maximum length of the name of a StatefulSet.
+	// The StatefulSet controller labels the pods with controller-revision-hash=<name>-<hash>,
+	// which must be a valid label value of at most 63 characters.
+	StatefulSetNameMaxLength = 52
+)
+
+var nameTemplate = DefaultNameTemplate
+
+// SetNameTemplate sets the template of the names of the generated objects, such as thanos-{component}-{name}-prod.
+// It must contain both the component and name placeholders, so that the generated names are unique.
+// It is set once when the operator starts, changing it renames all the generated objects.
+func SetNameTemplate(template string) error {
+	if !strings.Contains(template, ComponentNamePlaceholder) || !strings.Contains(template, OwnerNamePlaceholder) {
+		return fmt.Errorf("name template %q must contain %s and %s", template, ComponentNamePlaceholder, OwnerNamePlaceholder)
+	}
+	nameTemplate = template
+	return nil
+}
+
+// GeneratedName renders the name template for the component and the name parts, joined with dashes.
+// The name is not sanitized, callers must pass it to ValidateAndSanitizeResourceName or
+// ValidateAndSanitizeResourceNameToLength.
+func GeneratedName(component string, parts ...string) string {
+	return strings.NewReplacer(
+		ComponentNamePlaceholder, component,
+		OwnerNamePlaceholder, strings.Join(parts, "-"),
+	).Replace(nameTemplate)
+}
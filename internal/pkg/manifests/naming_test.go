@@ -0,0 +1,55 @@
+package manifests
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func TestGeneratedName(t *testing.T) {
+	if got := GeneratedName("thanos-receive-ingester", "example", "default"); got != "thanos-receive-ingester-example-default" {
+		t.Errorf("unexpected default name %q", got)
+	}
+
+	if err := SetNameTemplate("{component}-prod"); err == nil {
+		t.Error("expected an error for a template without the name placeholder")
+	}
+	if err := SetNameTemplate("obs-{name}-{component}"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { nameTemplate = DefaultNameTemplate })
+
+	if got := GeneratedName("thanos-query", "example"); got != "obs-example-thanos-query" {
+		t.Errorf("unexpected templated name %q", got)
+	}
+}
+
+func TestGeneratedNameLength(t *testing.T) {
+	owner := strings.Repeat("a", 40)
+	for _, tc := range []struct {
+		name   string
+		length int
+	}{
+		{name: "service", length: validation.DNS1123LabelMaxLength},
+		{name: "statefulset", length: StatefulSetNameMaxLength},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			full := GeneratedName("thanos-receive-ingester", owner, "hashring")
+			got := ValidateAndSanitizeResourceNameToLength(full, tc.length)
+			if len(got) > tc.length {
+				t.Errorf("expected at most %d characters, got %d: %q", tc.length, len(got), got)
+			}
+			if errs := validation.IsValidLabelValue(got); len(errs) > 0 {
+				t.Errorf("expected a valid label value, got %q: %v", got, errs)
+			}
+			if got != ValidateAndSanitizeResourceNameToLength(full, tc.length) {
+				t.Error("expected a deterministic name")
+			}
+			other := ValidateAndSanitizeResourceNameToLength(GeneratedName("thanos-receive-ingester", owner, "hashring-2"), tc.length)
+			if got == other {
+				t.Errorf("expected truncated names of different hashrings to differ, got %q", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package manifests
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	objStoreVolumeName = "objstore-config"
+	objStoreMountPath  = "/etc/thanos/objstore"
+	objStoreConfigFile = "objstore.yaml"
+
+	// ObjStoreHashAnnotation is the pod template annotation used to trigger a rollout
+	// when the object storage Secret changes.
+	ObjStoreHashAnnotation = "operator.thanos.io/objstore-hash"
+)
+
+// ObjStoreConfig controls how the object storage Secret is projected into a Thanos component.
+type ObjStoreConfig struct {
+	// FromFile mounts the object storage configuration as a file and passes it with --objstore.config-file,
+	// instead of passing it inline from an env var with --objstore.config.
+	FromFile bool
+	// Env projects keys of the object storage Secret as env vars of the Thanos container.
+	Env []ObjStoreEnvVar
+	// Hash is a hash of the object storage Secret contents.
+	// Thanos only reads the configuration at startup, so a change in the hash triggers a rollout.
+	Hash string
+}
+
+// ObjStoreEnvVar projects a key of the object storage Secret as an env var.
+type ObjStoreEnvVar struct {
+	Name string
+	Key  string
+}
+
+// Flag returns the flag passing the object storage configuration to Thanos.
+// envVarName is the name of the env var holding the configuration when it is not read from a file.
+func (c ObjStoreConfig) Flag(envVarName string) string {
+	if c.FromFile {
+		return fmt.Sprintf("--objstore.config-file=%s/%s", objStoreMountPath, objStoreConfigFile)
+	}
+	return fmt.Sprintf("--objstore.config=$(%s)", envVarName)
+}
+
+// EnvVars returns the env vars projected from the object storage Secret.
+// envVarName is the name of the env var holding the configuration when it is not read from a file.
+func (c ObjStoreConfig) EnvVars(envVarName string, secret corev1.SecretKeySelector) []corev1.EnvVar {
+	var envVars []corev1.EnvVar
+	if !c.FromFile {
+		envVars = append(envVars, secretKeyEnvVar(envVarName, secret.Name, secret.Key))
+	}
+	for _, env := range c.Env {
+		envVars = append(envVars, secretKeyEnvVar(env.Name, secret.Name, env.Key))
+	}
+	return envVars
+}
+
+// MountObjStore mounts the object storage configuration into the first container of the pod template
+// if it is read from a file. The Secret is mounted without subPath so that updates are propagated to the running pods.
+// If the Hash is set, it is recorded on the pod template.
+func MountObjStore(pt *corev1.PodTemplateSpec, secret corev1.SecretKeySelector, c ObjStoreConfig) {
+	if c.Hash != "" {
+		if pt.Annotations == nil {
+			pt.Annotations = make(map[string]string)
+		}
+		pt.Annotations[ObjStoreHashAnnotation] = c.Hash
+	}
+
+	if !c.FromFile {
+		return
+	}
+
+	pt.Spec.Containers[0].VolumeMounts = append(pt.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      objStoreVolumeName,
+		ReadOnly:  true,
+		MountPath: objStoreMountPath,
+	})
+	pt.Spec.Volumes = append(pt.Spec.Volumes, corev1.Volume{
+		Name: objStoreVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: secret.Name,
+				Items: []corev1.KeyToPath{
+					{
+						Key:  secret.Key,
+						Path: objStoreConfigFile,
+					},
+				},
+			},
+		},
+	})
+}
+
+func secretKeyEnvVar(name, secret, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: secret,
+				},
+				Key:      key,
+				Optional: ptr.To(false),
+			},
+		},
+	}
+}
@@ -0,0 +1,86 @@
+package manifests
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ObjStoreSecretComponentName is the name of the Secrets holding object storage configurations
+	// that are not read from a user Secret.
+	ObjStoreSecretComponentName = "thanos-objstore"
+	// ObjStoreSecretKey is the key of the managed Secret holding an inline object storage configuration.
+	ObjStoreSecretKey = objStoreConfigFile
+)
+
+// ObjStoreSecretOptions for the Secret holding an object storage configuration set inline or read from a ConfigMap.
+// The Owner of the embedded Options is the name of the resource the configuration belongs to.
+type ObjStoreSecretOptions struct {
+	Options
+	// Source identifies where the configuration is read from. Configurations of the same owner with different sources
+	// are written to different Secrets.
+	Source string
+	// Data is the data of the Secret.
+	Data map[string][]byte
+}
+
+// Build builds the Secret holding the object storage configuration.
+func (opts ObjStoreSecretOptions) Build() []client.Object {
+	return []client.Object{NewObjStoreSecret(opts)}
+}
+
+func (opts ObjStoreSecretOptions) Valid() error {
+	if opts.Owner == "" {
+		return fmt.Errorf("owner cannot be empty")
+	}
+	if opts.Source == "" {
+		return fmt.Errorf("source cannot be empty")
+	}
+	return nil
+}
+
+// GetGeneratedResourceName returns the name of the Secret, suffixed with a hash of its source.
+func (opts ObjStoreSecretOptions) GetGeneratedResourceName() string {
+	hash := hashString(opts.Source, hashSuffixLength)
+	return ValidateAndSanitizeResourceNameToLength(GeneratedName(ObjStoreSecretComponentName, opts.Owner, hash), validation.DNS1123LabelMaxLength)
+}
+
+// GetRequiredObjStoreSecretLabels returns a map of labels that can be used to look up the managed object storage Secrets.
+func GetRequiredObjStoreSecretLabels() map[string]string {
+	return map[string]string{
+		NameLabel:      ObjStoreSecretComponentName,
+		ComponentLabel: ObjStoreSecretComponentName,
+		PartOfLabel:    DefaultPartOfLabel,
+		ManagedByLabel: DefaultManagedByLabel,
+	}
+}
+
+func (opts ObjStoreSecretOptions) GetSelectorLabels() map[string]string {
+	l := GetRequiredObjStoreSecretLabels()
+	l[InstanceLabel] = ValidateAndSanitizeNameToValidLabelValue(opts.GetGeneratedResourceName())
+	l[OwnerLabel] = ValidateAndSanitizeNameToValidLabelValue(opts.Owner)
+	return l
+}
+
+// NewObjStoreSecret creates the Secret holding the object storage configuration.
+func NewObjStoreSecret(opts ObjStoreSecretOptions) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: corev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        opts.GetGeneratedResourceName(),
+			Namespace:   opts.Namespace,
+			Labels:      MergeMaps(opts.Labels, opts.GetSelectorLabels()),
+			Annotations: opts.Annotations,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: opts.Data,
+	}
+}
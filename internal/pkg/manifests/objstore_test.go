@@ -0,0 +1,173 @@
+package manifests
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestObjStoreConfig(t *testing.T) {
+	secret := corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "objstore"},
+		Key:                  "thanos.yaml",
+	}
+	env := []ObjStoreEnvVar{{Name: "AZURE_CLIENT_ID", Key: "client-id"}}
+
+	for _, tc := range []struct {
+		name       string
+		conf       ObjStoreConfig
+		wantFlag   string
+		wantEnv    map[string]string
+		wantVolume bool
+		wantHash   string
+	}{
+		{
+			name:     "inline",
+			conf:     ObjStoreConfig{},
+			wantFlag: "--objstore.config=$(OBJSTORE_CONFIG)",
+			wantEnv:  map[string]string{"OBJSTORE_CONFIG": "thanos.yaml"},
+		},
+		{
+			name:     "inline with env projection",
+			conf:     ObjStoreConfig{Env: env},
+			wantFlag: "--objstore.config=$(OBJSTORE_CONFIG)",
+			wantEnv:  map[string]string{"OBJSTORE_CONFIG": "thanos.yaml", "AZURE_CLIENT_ID": "client-id"},
+		},
+		{
+			name:       "file with env projection",
+			conf:       ObjStoreConfig{FromFile: true, Env: env},
+			wantFlag:   "--objstore.config-file=/etc/thanos/objstore/objstore.yaml",
+			wantEnv:    map[string]string{"AZURE_CLIENT_ID": "client-id"},
+			wantVolume: true,
+		},
+		{
+			name:     "inline with hash",
+			conf:     ObjStoreConfig{Hash: "abc"},
+			wantFlag: "--objstore.config=$(OBJSTORE_CONFIG)",
+			wantEnv:  map[string]string{"OBJSTORE_CONFIG": "thanos.yaml"},
+			wantHash: "abc",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.conf.Flag("OBJSTORE_CONFIG"); got != tc.wantFlag {
+				t.Errorf("expected flag %q, got %q", tc.wantFlag, got)
+			}
+
+			envVars := tc.conf.EnvVars("OBJSTORE_CONFIG", secret)
+			if len(envVars) != len(tc.wantEnv) {
+				t.Fatalf("expected %d env vars, got %d", len(tc.wantEnv), len(envVars))
+			}
+			for _, envVar := range envVars {
+				if envVar.ValueFrom.SecretKeyRef.Name != secret.Name {
+					t.Errorf("expected env var %s to reference secret %s, got %s", envVar.Name, secret.Name, envVar.ValueFrom.SecretKeyRef.Name)
+				}
+				if envVar.ValueFrom.SecretKeyRef.Key != tc.wantEnv[envVar.Name] {
+					t.Errorf("expected env var %s to reference key %q, got %q", envVar.Name, tc.wantEnv[envVar.Name], envVar.ValueFrom.SecretKeyRef.Key)
+				}
+			}
+
+			pt := &corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "thanos"}}}}
+			MountObjStore(pt, secret, tc.conf)
+			if got := len(pt.Spec.Volumes) == 1 && len(pt.Spec.Containers[0].VolumeMounts) == 1; got != tc.wantVolume {
+				t.Errorf("expected objstore volume mounted to be %t, got %t", tc.wantVolume, got)
+			}
+			if got := pt.Annotations[ObjStoreHashAnnotation]; got != tc.wantHash {
+				t.Errorf("expected objstore hash annotation %q, got %q", tc.wantHash, got)
+			}
+		})
+	}
+}
+
+func TestNewObjStoreVerifyJob(t *testing.T) {
+	opts := ObjStoreVerifyOptions{
+		Options: Options{Owner: "example", Namespace: "ns", Labels: map[string]string{"team": "a"}},
+		ObjStoreSecret: corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "objstore"},
+			Key:                  "thanos.yaml",
+		},
+		ObjStoreConfig: ObjStoreConfig{FromFile: true, Hash: "abc"},
+	}
+
+	job := NewObjStoreVerifyJob(opts)
+	if !strings.HasPrefix(job.GetName(), "thanos-objstore-verify-example-") {
+		t.Errorf("unexpected job name %s", job.GetName())
+	}
+	if job.Labels["team"] != "a" || job.Spec.Template.Labels["team"] != "" {
+		t.Errorf("expected additional labels on the job only, got %v and %v", job.Labels, job.Spec.Template.Labels)
+	}
+	args := job.Spec.Template.Spec.Containers[0].Args
+	if want := []string{"tools", "bucket", "ls", "--objstore.config-file=/etc/thanos/objstore/objstore.yaml"}; !slices.Equal(args, want) {
+		t.Errorf("expected args %v, got %v", want, args)
+	}
+
+	mounted := opts
+	mounted.Additional = Additional{
+		Volumes:      []corev1.Volume{{Name: "ca-bundle"}},
+		VolumeMounts: []corev1.VolumeMount{{Name: "ca-bundle", MountPath: "/etc/ssl/certs/corp"}},
+		Env:          []corev1.EnvVar{{Name: "AWS_CA_BUNDLE", Value: "/etc/ssl/certs/corp/ca.crt"}},
+	}
+	spec := NewObjStoreVerifyJob(mounted).Spec.Template.Spec
+	if !slices.ContainsFunc(spec.Volumes, func(v corev1.Volume) bool { return v.Name == "ca-bundle" }) ||
+		!slices.ContainsFunc(spec.Containers[0].VolumeMounts, func(m corev1.VolumeMount) bool { return m.Name == "ca-bundle" }) ||
+		!slices.ContainsFunc(spec.Containers[0].Env, func(e corev1.EnvVar) bool { return e.Name == "AWS_CA_BUNDLE" }) {
+		t.Errorf("expected the additional volumes and environment of the component, got %v", spec)
+	}
+
+	labelled := opts
+	labelled.Labels = map[string]string{"team": "b"}
+	if NewObjStoreVerifyJob(labelled).GetName() != job.GetName() {
+		t.Error("expected the job name not to change with the additional labels")
+	}
+	for name, changed := range map[string]ObjStoreVerifyOptions{
+		"config":  func() ObjStoreVerifyOptions { o := opts; o.ObjStoreConfig.Hash = "def"; return o }(),
+		"image":   func() ObjStoreVerifyOptions { o := opts; o.Version = ptr.To("v0.1.0"); return o }(),
+		"mode":    func() ObjStoreVerifyOptions { o := opts; o.ObjStoreConfig.FromFile = false; return o }(),
+		"volumes": mounted,
+		"secret":  func() ObjStoreVerifyOptions { o := opts; o.ObjStoreSecret.Key = "other.yaml"; return o }(),
+		"placing": func() ObjStoreVerifyOptions {
+			o := opts
+			o.PlacementConfig = &Placement{NodeSelector: map[string]string{"a": "b"}}
+			return o
+		}(),
+	} {
+		if NewObjStoreVerifyJob(changed).GetName() == job.GetName() {
+			t.Errorf("expected the job name to change with the %s", name)
+		}
+	}
+}
+
+func TestNewObjStoreSecret(t *testing.T) {
+	opts := ObjStoreSecretOptions{
+		Options: Options{Owner: "example", Namespace: "ns", Labels: map[string]string{"team": "a"}},
+		Source:  "configmap/objstore/thanos.yaml",
+		Data:    map[string][]byte{"thanos.yaml": []byte("type: FILESYSTEM")},
+	}
+
+	secret := NewObjStoreSecret(opts)
+	if !strings.HasPrefix(secret.GetName(), "thanos-objstore-example-") {
+		t.Errorf("unexpected secret name %s", secret.GetName())
+	}
+	if secret.Labels["team"] != "a" || secret.Labels[ComponentLabel] != ObjStoreSecretComponentName {
+		t.Errorf("unexpected labels %v", secret.Labels)
+	}
+	if string(secret.Data["thanos.yaml"]) != "type: FILESYSTEM" {
+		t.Errorf("unexpected data %v", secret.Data)
+	}
+
+	updated := opts
+	updated.Data = map[string][]byte{"thanos.yaml": []byte("type: S3")}
+	if NewObjStoreSecret(updated).GetName() != secret.GetName() {
+		t.Error("expected the secret name not to change with the data")
+	}
+	moved := opts
+	moved.Source = "configmap/other/thanos.yaml"
+	if NewObjStoreSecret(moved).GetName() == secret.GetName() {
+		t.Error("expected the secret name to change with the source")
+	}
+	if err := (ObjStoreSecretOptions{Options: Options{Owner: "example"}}).Valid(); err == nil {
+		t.Error("expected an error without a source")
+	}
+}
@@ -0,0 +1,157 @@
+package manifests
+
+import (
+	"encoding/json"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ObjStoreVerifyComponentName is the name of the Job verifying the object storage configuration.
+	ObjStoreVerifyComponentName = "thanos-objstore-verify"
+
+	objStoreVerifyContainerName = "objstore-verify"
+	objStoreVerifyEnvVarName    = "OBJSTORE_CONFIG"
+	// objStoreVerifyDeadlineSeconds bounds the time the Job may take to list the bucket.
+	objStoreVerifyDeadlineSeconds = 300
+	// objStoreVerifyTTLSeconds is the time finished Jobs are kept for, so that their result can be read.
+	objStoreVerifyTTLSeconds = 24 * 60 * 60
+)
+
+// ObjStoreVerifyOptions for the Job verifying that the object storage is reachable with a configuration.
+// The Owner of the embedded Options is the name of the resource the configuration belongs to.
+type ObjStoreVerifyOptions struct {
+	Options
+	ObjStoreSecret corev1.SecretKeySelector
+	// ObjStoreConfig must have its Hash set, so that a new Job verifies every change of the configuration.
+	ObjStoreConfig ObjStoreConfig
+}
+
+// Build builds the Job verifying the object storage configuration.
+func (opts ObjStoreVerifyOptions) Build() []client.Object {
+	return []client.Object{NewObjStoreVerifyJob(opts)}
+}
+
+func (opts ObjStoreVerifyOptions) Valid() error {
+	if opts.Owner == "" {
+		return fmt.Errorf("owner cannot be empty")
+	}
+	return nil
+}
+
+// GetGeneratedResourceName returns the name of the Job. The pod template of a Job cannot be updated, so the name
+// is suffixed with a hash of the configuration and the pod spec, and a new Job is created whenever either changes.
+func (opts ObjStoreVerifyOptions) GetGeneratedResourceName() string {
+	spec, err := json.Marshal(opts.podSpec())
+	if err != nil {
+		panic(fmt.Sprintf("failed to marshal object storage verification pod spec: %v", err))
+	}
+	hash := hashString(opts.ObjStoreConfig.Hash+string(spec), hashSuffixLength)
+	return ValidateAndSanitizeResourceNameToLength(GeneratedName(ObjStoreVerifyComponentName, opts.Owner, hash), validation.DNS1123LabelMaxLength)
+}
+
+// GetRequiredObjStoreVerifyLabels returns a map of labels that can be used to look up the object storage verification Jobs.
+func GetRequiredObjStoreVerifyLabels() map[string]string {
+	return map[string]string{
+		NameLabel:      ObjStoreVerifyComponentName,
+		ComponentLabel: ObjStoreVerifyComponentName,
+		PartOfLabel:    DefaultPartOfLabel,
+		ManagedByLabel: DefaultManagedByLabel,
+	}
+}
+
+func (opts ObjStoreVerifyOptions) GetSelectorLabels() map[string]string {
+	l := GetRequiredObjStoreVerifyLabels()
+	l[InstanceLabel] = ValidateAndSanitizeNameToValidLabelValue(opts.GetGeneratedResourceName())
+	l[OwnerLabel] = ValidateAndSanitizeNameToValidLabelValue(opts.Owner)
+	return l
+}
+
+// NewObjStoreVerifyJob creates a Job that lists the bucket with the object storage configuration,
+// and fails if the object storage cannot be reached.
+func NewObjStoreVerifyJob(opts ObjStoreVerifyOptions) *batchv1.Job {
+	selectorLabels := opts.GetSelectorLabels()
+
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Job",
+			APIVersion: batchv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        opts.GetGeneratedResourceName(),
+			Namespace:   opts.Namespace,
+			Labels:      MergeMaps(opts.Labels, selectorLabels),
+			Annotations: opts.Annotations,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            ptr.To(int32(0)),
+			ActiveDeadlineSeconds:   ptr.To(int64(objStoreVerifyDeadlineSeconds)),
+			TTLSecondsAfterFinished: ptr.To(int32(objStoreVerifyTTLSeconds)),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					// the additional labels are left out, so that changing them does not update the immutable template
+					Labels: selectorLabels,
+				},
+				Spec: opts.podSpec(),
+			},
+		},
+	}
+}
+
+func (opts ObjStoreVerifyOptions) podSpec() corev1.PodSpec {
+	pt := corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			RestartPolicy:                corev1.RestartPolicyNever,
+			AutomountServiceAccountToken: ptr.To(false),
+			SecurityContext:              opts.SecurityContext,
+			ImagePullSecrets:             opts.ImagePullSecrets,
+			Containers: []corev1.Container{
+				{
+					Name:            objStoreVerifyContainerName,
+					Image:           opts.GetContainerImage(),
+					ImagePullPolicy: corev1.PullIfNotPresent,
+					Args:            []string{"tools", "bucket", "ls", opts.ObjStoreConfig.Flag(objStoreVerifyEnvVarName)},
+					Env:             opts.ObjStoreConfig.EnvVars(objStoreVerifyEnvVarName, opts.ObjStoreSecret),
+					SecurityContext: &corev1.SecurityContext{
+						AllowPrivilegeEscalation: ptr.To(false),
+						RunAsNonRoot:             ptr.To(true),
+						Capabilities: &corev1.Capabilities{
+							Drop: []corev1.Capability{
+								"ALL",
+							},
+						},
+						SeccompProfile: &corev1.SeccompProfile{
+							Type: corev1.SeccompProfileTypeRuntimeDefault,
+						},
+					},
+					TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+				},
+			},
+		},
+	}
+	// the hash is part of the name of the Job, recording it on the pod template would only duplicate it
+	MountObjStore(&pt, opts.ObjStoreSecret, ObjStoreConfig{FromFile: opts.ObjStoreConfig.FromFile})
+
+	// files and variables the object storage client may rely on, such as CA bundles and credentials,
+	// are made available as they are to the component
+	c := &pt.Spec.Containers[0]
+	pt.Spec.Volumes = append(pt.Spec.Volumes, opts.Additional.Volumes...)
+	c.VolumeMounts = append(c.VolumeMounts, opts.Additional.VolumeMounts...)
+	c.Env = append(c.Env, opts.Additional.Env...)
+	c.EnvFrom = append(c.EnvFrom, opts.Additional.EnvFrom...)
+
+	if opts.PlacementConfig != nil {
+		pt.Spec.NodeSelector = opts.PlacementConfig.NodeSelector
+		pt.Spec.Affinity = opts.PlacementConfig.Affinity
+		pt.Spec.Tolerations = opts.PlacementConfig.Tolerations
+		pt.Spec.TopologySpreadConstraints = opts.PlacementConfig.TopologySpreadConstraints
+	}
+	return pt.Spec
+}
@@ -1,12 +1,15 @@
 package manifests
 
 import (
+	"cmp"
 	"crypto/sha256"
 	"fmt"
 	"regexp"
 	"slices"
 	"strings"
 
+	"golang.org/x/mod/semver"
+
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -23,6 +26,8 @@ const (
 	defaultLogLevel  = "info"
 	defaultLogFormat = "logfmt"
 
+	tracingConfigEnvVarName = "TRACING_CONFIG"
+
 	// DefaultFSGroup is the default FSGroup to use for pod security context
 	// when none is specified by the user.
 	DefaultFSGroup = int64(1001)
@@ -90,6 +95,9 @@ type Options struct {
 	// Replicas is the number of replicas for the object.
 	// Specific build functions may override this value.
 	Replicas int32
+	// ExternalReplicas leaves the replicas of the Deployment to be managed externally, such as by a HorizontalPodAutoscaler.
+	// Replicas is then only used to size the objects that depend on the number of replicas.
+	ExternalReplicas bool
 	// Labels is the labels for the object
 	// Labels will be merged with the default labels for the component.
 	// The builders should ensure that the default labels are set on the object.
@@ -97,14 +105,23 @@ type Options struct {
 	Labels map[string]string
 	// Annotations is the annotations for the object
 	Annotations map[string]string
+	// PodAnnotations are added to the pod template of the workload.
+	// Changing them rolls out the pods.
+	PodAnnotations map[string]string
 	// Image is the image to use for the component
-	// If not set, DefaultThanosImage will be used
+	// If not set, the default image of the operator or DefaultThanosImage will be used
 	Image *string
 	// Version is the version of Thanos
-	// If not set, DefaultThanosVersion will be used
+	// If not set, the default version of the operator or DefaultThanosVersion will be used
 	Version *string
 	// ResourceRequirements for the component
 	ResourceRequirements *corev1.ResourceRequirements
+	// ImagePullSecrets are the Secrets used to pull the images of the pods.
+	ImagePullSecrets []corev1.LocalObjectReference
+	// ServiceAccountAnnotations are added to the ServiceAccount built for the component.
+	ServiceAccountAnnotations map[string]string
+	// ServiceAnnotations are added to the Services exposing the component.
+	ServiceAnnotations map[string]string
 	// LogLevel is the log level for the component
 	LogLevel *string
 	// LogFormat is the log format for the component
@@ -112,13 +129,35 @@ type Options struct {
 	//ServiceMonitorConfig is the configuration for the ServiceMonitor
 	// If not set, the ServiceMonitor will not be created.
 	ServiceMonitorConfig *ServiceMonitorConfig
+	// MonitoringMixinConfig is the configuration of the dashboards and alerting rules of the component.
+	// If not set, they are not created. Only some components have dashboards and alerting rules.
+	MonitoringMixinConfig *MonitoringMixinConfig
 	// PodDisruptionConfig is the configuration for the PodDisruptionBudget
 	// If not set, the PodDisruptionBudget will not be created.
 	PodDisruptionConfig *PodDisruptionBudgetOptions
-	PlacementConfig     *Placement
+	// EnableMetricsService creates a dedicated Service exposing only the metrics of the component.
+	// When set, the ServiceMonitor scrapes this Service.
+	EnableMetricsService bool
+	PlacementConfig      *Placement
 	// SecurityContext holds pod-level security attributes and common container settings.
 	// Default is set via kubebuilder in CommonFields with FSGroup=1001.
 	SecurityContext *corev1.PodSecurityContext
+	// ContainerSecurityContext replaces the security context of the component container.
+	// If not set, the security context of the builder is used.
+	ContainerSecurityContext *corev1.SecurityContext
+	// PriorityClassName is the name of the PriorityClass of the pods.
+	PriorityClassName string
+	// TracingConfig is the reference to the tracing configuration of the component.
+	// If not set, tracing is disabled.
+	TracingConfig *corev1.SecretKeySelector
+	// Tracing is the tracing configuration of the component, rendered inline.
+	// It is ignored if TracingConfig is set.
+	Tracing *TracingOptions
+	// Probes tunes the probes of the component container.
+	// If not set, the probes of the builders are used as is.
+	Probes *Probes
+	// Deployment holds the options applied when the component is a Deployment.
+	Deployment Deployment
 	// Features holds feature flags for the component
 	Features Features
 }
@@ -135,14 +174,22 @@ type Features struct {
 	EnableOtelSidecar bool
 }
 
-// ValidateAndSanitizeResourceName sanitizes the provided name to a valid DNS-1123 subdomain.
+// ValidateAndSanitizeResourceName sanitizes the provided name to a valid DNS-1123 subdomain of at most 63 characters.
+// Longer names are truncated and suffixed with a hash of the full name, so that they stay unique and deterministic.
+// The length is limited to that of a DNS-1123 label because the names are used for Services and label values.
 func ValidateAndSanitizeResourceName(name string) string {
-	if n := validation.IsDNS1123Subdomain(name); len(n) == 0 {
+	return ValidateAndSanitizeResourceNameToLength(name, validation.DNS1123LabelMaxLength)
+}
+
+// ValidateAndSanitizeResourceNameToLength sanitizes the provided name to a valid DNS-1123 subdomain
+// of at most length characters. This is required for objects whose names are used to derive the names of other objects,
+// such as CronJobs.
+func ValidateAndSanitizeResourceNameToLength(name string, length int) string {
+	if n := validation.IsDNS1123Subdomain(name); len(n) == 0 && len(name) <= length {
 		return name
 	}
 
-	// we can create a longer name here if we wish, it will be obfuscated at this point regardless
-	return sanitizeToLength(ensureAlphaNumericTrailingChars(name), validation.DNS1123LabelMaxLength)
+	return sanitizeToLength(ensureAlphaNumericTrailingChars(name), length)
 }
 
 // ValidateAndSanitizeNameToValidLabelValue sanitizes the provided name to a valid label value.
@@ -221,37 +268,74 @@ func hashString(s string, length int) string {
 // ToFlags returns the flags for the Options
 func (o Options) ToFlags() []string {
 	if o.LogLevel == nil || *o.LogLevel == "" {
-		o.LogLevel = ptr.To(defaultLogLevel)
+		o.LogLevel = ptr.To(cmp.Or(getComponentDefaults().LogLevel, defaultLogLevel))
 	}
 
 	if o.LogFormat == nil || *o.LogFormat == "" {
 		o.LogFormat = ptr.To(defaultLogFormat)
 	}
 
-	return []string{
+	flags := []string{
 		fmt.Sprintf("--log.level=%s", *o.LogLevel),
 		fmt.Sprintf("--log.format=%s", *o.LogFormat),
 	}
+	if o.TracingConfig != nil {
+		flags = append(flags, fmt.Sprintf("--tracing.config=$(%s)", tracingConfigEnvVarName))
+	} else if o.Tracing != nil {
+		flags = append(flags, fmt.Sprintf("--tracing.config=%s", o.Tracing.String()))
+	}
+	return flags
+}
+
+// DeploymentReplicas returns the replicas of the Deployment, or nil if they are managed externally.
+func (o Options) DeploymentReplicas() *int32 {
+	if o.ExternalReplicas {
+		return nil
+	}
+	return ptr.To(o.Replicas)
+}
+
+// GetServiceAnnotations returns the annotations of the Services exposing the component.
+func (o Options) GetServiceAnnotations() map[string]string {
+	return MergeMaps(o.Annotations, o.ServiceAnnotations)
 }
 
 // GetContainerImage for the Options
 func (o Options) GetContainerImage() string {
 	if o.Image == nil || *o.Image == "" {
-		o.Image = ptr.To(DefaultThanosImage)
+		o.Image = ptr.To(cmp.Or(getComponentDefaults().Image, DefaultThanosImage))
 	}
 
-	// If the image already contains a tag (colon), return it as is
-	if strings.Contains(*o.Image, ":") {
+	// If the image already contains a tag or digest, return it as is. A colon before the last slash is a registry port.
+	if strings.Contains(*o.Image, "@") || strings.Contains((*o.Image)[strings.LastIndex(*o.Image, "/")+1:], ":") {
 		return *o.Image
 	}
 
 	// Otherwise, append the version
 	if o.Version == nil || *o.Version == "" {
-		o.Version = ptr.To(DefaultThanosVersion)
+		o.Version = ptr.To(cmp.Or(getComponentDefaults().Version, DefaultThanosVersion))
 	}
 	return fmt.Sprintf("%s:%s", *o.Image, *o.Version)
 }
 
+// VersionAtLeast returns false if the tag of the container image is a semantic version lower than minVersion.
+// Tags that are not semantic versions, such as those of custom downstream images, are assumed to be recent enough.
+func (o Options) VersionAtLeast(minVersion string) bool {
+	image := o.GetContainerImage()
+	idx := strings.LastIndex(image, ":")
+	if idx < 0 || strings.Contains(image, "@") {
+		return true
+	}
+	version := image[idx+1:]
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	if !semver.IsValid(version) {
+		return true
+	}
+	return semver.Compare(version, minVersion) >= 0
+}
+
 // AugmentWithOptions augments the object with the options.
 // Supported objects are Deployment and StatefulSet.
 func AugmentWithOptions(obj client.Object, opts Options) {
@@ -259,6 +343,12 @@ func AugmentWithOptions(obj client.Object, opts Options) {
 	case *appsv1.Deployment:
 		augmentPodTemplate(&o.Spec.Template, opts)
 
+		o.Spec.Template.Spec.TerminationGracePeriodSeconds = opts.Deployment.TerminationGracePeriodSeconds
+
+		if opts.Deployment.Strategy != nil {
+			o.Spec.Strategy = *opts.Deployment.Strategy
+		}
+
 		if opts.SecurityContext != nil {
 			o.Spec.Template.Spec.SecurityContext = opts.SecurityContext
 		}
@@ -285,6 +375,10 @@ func AugmentWithOptions(obj client.Object, opts Options) {
 
 		o.Spec.Template.Spec.TerminationGracePeriodSeconds = opts.StatefulSet.TerminationGracePeriodSeconds
 
+		if opts.StatefulSet.UpdateStrategy != nil {
+			o.Spec.UpdateStrategy = *opts.StatefulSet.UpdateStrategy
+		}
+
 		if opts.StatefulSet.MinReadySeconds != nil {
 			minReadySeconds := *opts.StatefulSet.MinReadySeconds
 			if minReadySeconds >= 0 {
@@ -316,10 +410,26 @@ func augmentPodTemplate(tl *corev1.PodTemplateSpec, opts Options) {
 	c := &tl.Spec.Containers[0]
 	c.Image = opts.GetContainerImage()
 
+	if len(opts.PodAnnotations) > 0 {
+		tl.Annotations = MergeMaps(tl.Annotations, opts.PodAnnotations)
+	}
+
 	if opts.ResourceRequirements != nil {
 		c.Resources = *opts.ResourceRequirements
 	}
 
+	if len(opts.ImagePullSecrets) > 0 {
+		tl.Spec.ImagePullSecrets = opts.ImagePullSecrets
+	}
+
+	if opts.ContainerSecurityContext != nil {
+		c.SecurityContext = opts.ContainerSecurityContext
+	}
+
+	if opts.PriorityClassName != "" {
+		tl.Spec.PriorityClassName = opts.PriorityClassName
+	}
+
 	if opts.Additional.VolumeMounts != nil {
 		c.VolumeMounts = append(c.VolumeMounts, opts.Additional.VolumeMounts...)
 	}
@@ -332,10 +442,18 @@ func augmentPodTemplate(tl *corev1.PodTemplateSpec, opts Options) {
 		c.Ports = append(c.Ports, opts.Additional.Ports...)
 	}
 
+	if opts.TracingConfig != nil {
+		c.Env = append(c.Env, secretKeyEnvVar(tracingConfigEnvVarName, opts.TracingConfig.Name, opts.TracingConfig.Key))
+	}
+
 	if opts.Additional.Env != nil {
 		c.Env = append(c.Env, opts.Additional.Env...)
 	}
 
+	if opts.Additional.EnvFrom != nil {
+		c.EnvFrom = append(c.EnvFrom, opts.Additional.EnvFrom...)
+	}
+
 	if opts.Additional.Args != nil {
 		c.Args = MergeArgs(c.Args, opts.Additional.Args)
 	}
@@ -347,11 +465,51 @@ func augmentPodTemplate(tl *corev1.PodTemplateSpec, opts Options) {
 		tl.Spec.TopologySpreadConstraints = opts.PlacementConfig.TopologySpreadConstraints
 	}
 
+	if opts.Probes != nil {
+		augmentProbes(c, *opts.Probes)
+	}
+
 	if opts.Additional.Containers != nil {
 		tl.Spec.Containers = append(tl.Spec.Containers, opts.Additional.Containers...)
 	}
 }
 
+// augmentProbes tunes the probes of the container.
+// The startup probe is added with the handler of the liveness probe, so it is skipped for containers without one.
+func augmentProbes(c *corev1.Container, probes Probes) {
+	if c.LivenessProbe != nil {
+		tuneProbe(c.LivenessProbe, probes.Liveness)
+	}
+	if c.ReadinessProbe != nil {
+		tuneProbe(c.ReadinessProbe, probes.Readiness)
+	}
+	if c.LivenessProbe != nil && probes.Startup != nil {
+		c.StartupProbe = &corev1.Probe{ProbeHandler: c.LivenessProbe.ProbeHandler}
+		tuneProbe(c.StartupProbe, probes.Startup)
+	}
+}
+
+func tuneProbe(probe *corev1.Probe, p *Probe) {
+	if p == nil {
+		return
+	}
+	if p.InitialDelaySeconds != nil {
+		probe.InitialDelaySeconds = *p.InitialDelaySeconds
+	}
+	if p.PeriodSeconds != nil {
+		probe.PeriodSeconds = *p.PeriodSeconds
+	}
+	if p.TimeoutSeconds != nil {
+		probe.TimeoutSeconds = *p.TimeoutSeconds
+	}
+	if p.SuccessThreshold != nil {
+		probe.SuccessThreshold = *p.SuccessThreshold
+	}
+	if p.FailureThreshold != nil {
+		probe.FailureThreshold = *p.FailureThreshold
+	}
+}
+
 func augmentOtel(tl *corev1.PodTemplateSpec) {
 	if tl.ObjectMeta.Annotations == nil {
 		tl.ObjectMeta.Annotations = make(map[string]string)
@@ -387,6 +545,9 @@ type Additional struct {
 	// Additional environment variables to add to the Thanos component container in a Deployment or StatefulSet
 	// controlled by the operator.
 	Env []corev1.EnvVar
+	// Additional sources of environment variables to add to the Thanos component container in a Deployment or
+	// StatefulSet controlled by the operator.
+	EnvFrom []corev1.EnvFromSource
 	// AdditionalServicePorts are additional ports to expose on the Service for the Thanos component.
 	ServicePorts []corev1.ServicePort
 	// Additional ConfigMaps to mount onto the Thanos components.
@@ -395,12 +556,37 @@ type Additional struct {
 	Secrets []string
 }
 
+// Probes holds the tuning of the probes of a component container.
+type Probes struct {
+	Liveness  *Probe
+	Readiness *Probe
+	// Startup adds a startup probe using the handler of the liveness probe.
+	Startup *Probe
+}
+
+// Probe holds the timings and thresholds of a probe. Unset values keep those of the builder.
+type Probe struct {
+	InitialDelaySeconds *int32
+	PeriodSeconds       *int32
+	TimeoutSeconds      *int32
+	SuccessThreshold    *int32
+	FailureThreshold    *int32
+}
+
+type Deployment struct {
+	TerminationGracePeriodSeconds *int64
+	// Strategy replaces the strategy of the builder if set.
+	Strategy *appsv1.DeploymentStrategy
+}
+
 type StatefulSet struct {
 	// Pod management policy of the statefulset.
 	PodManagementPolicy           string
 	PVCRetentionPolicy            PVCRetentionPolicy
 	TerminationGracePeriodSeconds *int64
 	MinReadySeconds               *int32
+	// UpdateStrategy replaces the update strategy of the builder if set.
+	UpdateStrategy *appsv1.StatefulSetUpdateStrategy
 }
 
 // PVCRetentionPolicy defines the retention policy for PVCs created by the operator.
@@ -466,6 +652,31 @@ type Duration string
 type CacheConfig struct {
 	InMemoryCacheConfig *InMemoryCacheConfig
 	FromSecret          *corev1.SecretKeySelector
+	// Memcached is the configuration of a memcached managed by the operator.
+	// It is used if neither InMemoryCacheConfig nor FromSecret are set.
+	Memcached *MemcachedConfig
+}
+
+// MemcachedConfig is the configuration of the memcached client of a component.
+type MemcachedConfig struct {
+	// Addresses are the addresses of the memcached servers, in the Thanos service discovery format.
+	Addresses []string
+	// MaxItemSize is the maximum size of an item in bytes. It must not exceed the maximum item size of the servers.
+	MaxItemSize int64
+}
+
+func (mc MemcachedConfig) String() string {
+	base := `type: MEMCACHED
+config:
+  addresses:
+`
+	for _, address := range mc.Addresses {
+		base += fmt.Sprintf("    - %s\n", address)
+	}
+	if mc.MaxItemSize > 0 {
+		base += fmt.Sprintf("  max_item_size: %dB\n", mc.MaxItemSize)
+	}
+	return base
 }
 
 type InMemoryCacheConfig struct {
@@ -3,6 +3,7 @@ package manifests
 import (
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 	"testing"
 
@@ -13,6 +14,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/yaml"
@@ -37,6 +39,13 @@ func TestOptions_GetContainerImage(t *testing.T) {
 			},
 			want: "quay.io/thanos/thanos:latest",
 		},
+		{
+			name: "keep registry port",
+			o: Options{
+				Image: ptr.To("registry.example.com:5000/thanos/thanos"),
+			},
+			want: "registry.example.com:5000/thanos/thanos:" + DefaultThanosVersion,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -47,6 +56,64 @@ func TestOptions_GetContainerImage(t *testing.T) {
 	}
 }
 
+func TestComponentDefaults(t *testing.T) {
+	SetComponentDefaults(ComponentDefaults{Image: "registry.example.com/thanos", Version: "v0.40.0", LogLevel: "warn"})
+	t.Cleanup(func() { SetComponentDefaults(ComponentDefaults{}) })
+
+	if got := (Options{}).GetContainerImage(); got != "registry.example.com/thanos:v0.40.0" {
+		t.Errorf("expected the default image, got %q", got)
+	}
+	if got := (Options{Version: ptr.To("v0.39.0")}).GetContainerImage(); got != "registry.example.com/thanos:v0.39.0" {
+		t.Errorf("expected the version of the resource to take precedence, got %q", got)
+	}
+	if got := (Options{}).ToFlags(); !slices.Contains(got, "--log.level=warn") {
+		t.Errorf("expected the default log level, got %v", got)
+	}
+	if got := (Options{LogLevel: ptr.To("debug")}).ToFlags(); !slices.Contains(got, "--log.level=debug") {
+		t.Errorf("expected the log level of the resource to take precedence, got %v", got)
+	}
+}
+
+func TestOptions_VersionAtLeast(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		o    Options
+		want bool
+	}{
+		{name: "default version", o: Options{}, want: true},
+		{name: "older version", o: Options{Version: ptr.To("v0.34.1")}, want: false},
+		{name: "newer version without prefix", o: Options{Version: ptr.To("0.36.0")}, want: true},
+		{name: "release candidate", o: Options{Version: ptr.To("v0.35.0-rc.0")}, want: false},
+		{name: "tag in image", o: Options{Image: ptr.To("localhost:5000/thanos:v0.30.0")}, want: false},
+		{name: "custom tag", o: Options{Version: ptr.To("main-2024-01-01-abc123")}, want: true},
+		{name: "registry port without tag", o: Options{Image: ptr.To("localhost:5000/thanos")}, want: true},
+		{name: "digest", o: Options{Image: ptr.To("quay.io/thanos/thanos@sha256:abc")}, want: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.o.VersionAtLeast("v0.35.0"); got != tc.want {
+				t.Errorf("Options.VersionAtLeast() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOptions_DeploymentReplicas(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		o    Options
+		want *int32
+	}{
+		{name: "managed replicas", o: Options{Replicas: 3}, want: ptr.To(int32(3))},
+		{name: "external replicas", o: Options{Replicas: 3, ExternalReplicas: true}, want: nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.o.DeploymentReplicas(); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Options.DeploymentReplicas() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
 func TestOptions_ToFlags(t *testing.T) {
 	tests := []struct {
 		name string
@@ -72,6 +139,40 @@ func TestOptions_ToFlags(t *testing.T) {
 				"--log.format=json",
 			},
 		},
+		{
+			name: "get tracing flag",
+			o: Options{
+				TracingConfig: &corev1.SecretKeySelector{Key: "tracing.yaml"},
+			},
+			want: []string{
+				fmt.Sprintf("--log.level=%s", defaultLogLevel),
+				fmt.Sprintf("--log.format=%s", defaultLogFormat),
+				"--tracing.config=$(TRACING_CONFIG)",
+			},
+		},
+		{
+			name: "get inline tracing flag",
+			o: Options{
+				Tracing: &TracingOptions{Endpoint: "otel-collector:4317", Insecure: true, SamplingRatio: "0.1"},
+			},
+			want: []string{
+				fmt.Sprintf("--log.level=%s", defaultLogLevel),
+				fmt.Sprintf("--log.format=%s", defaultLogFormat),
+				"--tracing.config=config:\n  client_type: grpc\n  endpoint: otel-collector:4317\n  insecure: true\n  sampler_param: \"0.1\"\n  sampler_type: traceidratiobased\ntype: OTLP\n",
+			},
+		},
+		{
+			name: "tracing config secret takes precedence",
+			o: Options{
+				TracingConfig: &corev1.SecretKeySelector{Key: "tracing.yaml"},
+				Tracing:       &TracingOptions{Endpoint: "otel-collector:4317"},
+			},
+			want: []string{
+				fmt.Sprintf("--log.level=%s", defaultLogLevel),
+				fmt.Sprintf("--log.format=%s", defaultLogFormat),
+				"--tracing.config=$(TRACING_CONFIG)",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -217,6 +318,22 @@ config:
 	}
 }
 
+func TestMemcachedConfig_String(t *testing.T) {
+	config := MemcachedConfig{
+		Addresses:   []string{"dnssrv+_memcached._tcp.cache.ns.svc"},
+		MaxItemSize: 1 << 20,
+	}
+	expected := `type: MEMCACHED
+config:
+  addresses:
+    - dnssrv+_memcached._tcp.cache.ns.svc
+  max_item_size: 1048576B
+`
+	if got := config.String(); got != expected {
+		t.Errorf("MemcachedConfig.String() = %v, want %v", got, expected)
+	}
+}
+
 type mockOptionsForGolden struct {
 	Options
 }
@@ -505,6 +622,72 @@ func TestAugmentWithOptions_Deployment_Golden(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "deployment-augmented-env-from",
+			objInit: func() *appsv1.Deployment {
+				return &appsv1.Deployment{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "Deployment",
+						APIVersion: "apps/v1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "thanos-query",
+						Namespace: "test-ns",
+					},
+					Spec: appsv1.DeploymentSpec{
+						Selector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								"app": "thanos-query",
+							},
+						},
+						Template: corev1.PodTemplateSpec{
+							ObjectMeta: metav1.ObjectMeta{
+								Labels: map[string]string{
+									"app": "thanos-query",
+								},
+							},
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{
+									{
+										Name: "thanos",
+									},
+								},
+							},
+						},
+					},
+				}
+			},
+			opts: mockOptionsForGolden{
+				Options: Options{
+					Owner:     "thanos-controller",
+					Namespace: "test-ns",
+					Replicas:  2,
+					Labels: map[string]string{
+						"app.kubernetes.io/name": "thanos-query",
+					},
+					Annotations: map[string]string{
+						"prometheus.io/scrape": "true",
+					},
+					Additional: Additional{
+						Env: []corev1.EnvVar{
+							{
+								Name:  "HTTPS_PROXY",
+								Value: "http://proxy.example.com:3128",
+							},
+						},
+						EnvFrom: []corev1.EnvFromSource{
+							{
+								SecretRef: &corev1.SecretEnvSource{
+									LocalObjectReference: corev1.LocalObjectReference{
+										Name: "aws-credentials",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "deployment-augmented-mount-configmap",
 			objInit: func() *appsv1.Deployment {
@@ -1305,6 +1488,14 @@ func TestValidateAndSanitizeResourceName(t *testing.T) {
 	}
 }
 
+func TestValidateAndSanitizeResourceNameToLength(t *testing.T) {
+	assert.Equal(t, "foo-bar", ValidateAndSanitizeResourceNameToLength("foo-bar", 52))
+
+	name := ValidateAndSanitizeResourceNameToLength("thanos-receive-write-probe-a-very-long-thanos-receive-name", 52)
+	assert.Len(t, name, 52)
+	assert.Len(t, validation.IsDNS1123Subdomain(name), 0)
+}
+
 func TestValidateAndSanitizeNameToValidLabelValue(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -1586,3 +1777,191 @@ func TestStatefulSet_MinReadySeconds(t *testing.T) {
 		})
 	}
 }
+
+func TestDeployment_TerminationGracePeriodSeconds(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "test"},
+					},
+				},
+			},
+		},
+	}
+
+	opts := Options{
+		Owner:     "test",
+		Namespace: "default",
+		StatefulSet: StatefulSet{
+			TerminationGracePeriodSeconds: ptr.To(int64(900)),
+		},
+		Deployment: Deployment{
+			TerminationGracePeriodSeconds: ptr.To(int64(60)),
+		},
+	}
+
+	AugmentWithOptions(deployment, opts)
+	assert.Equal(t, ptr.To(int64(60)), deployment.Spec.Template.Spec.TerminationGracePeriodSeconds)
+}
+
+func TestAugmentWithOptions_Probes(t *testing.T) {
+	newStatefulSet := func() *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			Spec: appsv1.StatefulSetSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{
+								Name: "test",
+								LivenessProbe: &corev1.Probe{
+									ProbeHandler: corev1.ProbeHandler{
+										HTTPGet: &corev1.HTTPGetAction{Path: "/-/healthy"},
+									},
+									PeriodSeconds:    30,
+									FailureThreshold: 8,
+								},
+								ReadinessProbe: &corev1.Probe{
+									ProbeHandler: corev1.ProbeHandler{
+										HTTPGet: &corev1.HTTPGetAction{Path: "/-/ready"},
+									},
+									PeriodSeconds:    5,
+									FailureThreshold: 20,
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("no probes keeps the builder probes", func(t *testing.T) {
+		sts := newStatefulSet()
+		AugmentWithOptions(sts, Options{Owner: "test"})
+		c := sts.Spec.Template.Spec.Containers[0]
+		assert.Equal(t, newStatefulSet().Spec.Template.Spec.Containers[0].LivenessProbe, c.LivenessProbe)
+		assert.Equal(t, newStatefulSet().Spec.Template.Spec.Containers[0].ReadinessProbe, c.ReadinessProbe)
+		assert.Nil(t, c.StartupProbe)
+	})
+
+	t.Run("probes are tuned and a startup probe is added", func(t *testing.T) {
+		sts := newStatefulSet()
+		AugmentWithOptions(sts, Options{
+			Owner: "test",
+			Probes: &Probes{
+				Liveness: &Probe{
+					InitialDelaySeconds: ptr.To(int32(10)),
+				},
+				Readiness: &Probe{
+					PeriodSeconds:  ptr.To(int32(15)),
+					TimeoutSeconds: ptr.To(int32(3)),
+				},
+				Startup: &Probe{
+					PeriodSeconds:    ptr.To(int32(10)),
+					FailureThreshold: ptr.To(int32(180)),
+				},
+			},
+		})
+		c := sts.Spec.Template.Spec.Containers[0]
+
+		assert.Equal(t, int32(10), c.LivenessProbe.InitialDelaySeconds)
+		assert.Equal(t, int32(30), c.LivenessProbe.PeriodSeconds)
+		assert.Equal(t, int32(8), c.LivenessProbe.FailureThreshold)
+
+		assert.Equal(t, int32(15), c.ReadinessProbe.PeriodSeconds)
+		assert.Equal(t, int32(3), c.ReadinessProbe.TimeoutSeconds)
+		assert.Equal(t, int32(20), c.ReadinessProbe.FailureThreshold)
+
+		if !assert.NotNil(t, c.StartupProbe) {
+			return
+		}
+		assert.Equal(t, "/-/healthy", c.StartupProbe.HTTPGet.Path)
+		assert.Equal(t, int32(10), c.StartupProbe.PeriodSeconds)
+		assert.Equal(t, int32(180), c.StartupProbe.FailureThreshold)
+	})
+}
+
+func TestAugmentWithOptions_SecurityContextAndPriorityClass(t *testing.T) {
+	builderSecurityContext := &corev1.SecurityContext{
+		RunAsNonRoot: ptr.To(true),
+	}
+	newDeployment := func() *appsv1.Deployment {
+		return &appsv1.Deployment{
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{Name: "test", SecurityContext: builderSecurityContext},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	deployment := newDeployment()
+	AugmentWithOptions(deployment, Options{Owner: "test"})
+	assert.Equal(t, builderSecurityContext, deployment.Spec.Template.Spec.Containers[0].SecurityContext)
+	assert.Empty(t, deployment.Spec.Template.Spec.PriorityClassName)
+
+	containerSecurityContext := &corev1.SecurityContext{
+		RunAsNonRoot:           ptr.To(true),
+		RunAsUser:              ptr.To(int64(65534)),
+		ReadOnlyRootFilesystem: ptr.To(true),
+	}
+	deployment = newDeployment()
+	AugmentWithOptions(deployment, Options{
+		Owner:                    "test",
+		ContainerSecurityContext: containerSecurityContext,
+		PriorityClassName:        "observability-critical",
+	})
+	assert.Equal(t, containerSecurityContext, deployment.Spec.Template.Spec.Containers[0].SecurityContext)
+	assert.Equal(t, "observability-critical", deployment.Spec.Template.Spec.PriorityClassName)
+}
+
+func TestAugmentWithOptions_UpdateStrategy(t *testing.T) {
+	statefulSet := &appsv1.StatefulSet{
+		Spec: appsv1.StatefulSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "test"}}},
+			},
+		},
+	}
+	updateStrategy := &appsv1.StatefulSetUpdateStrategy{
+		Type: appsv1.RollingUpdateStatefulSetStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
+			Partition: ptr.To(int32(2)),
+		},
+	}
+	AugmentWithOptions(statefulSet, Options{Owner: "test", StatefulSet: StatefulSet{UpdateStrategy: updateStrategy}})
+	assert.Equal(t, *updateStrategy, statefulSet.Spec.UpdateStrategy)
+
+	builderStrategy := appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+	newDeployment := func() *appsv1.Deployment {
+		return &appsv1.Deployment{
+			Spec: appsv1.DeploymentSpec{
+				Strategy: builderStrategy,
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "test"}}},
+				},
+			},
+		}
+	}
+
+	deployment := newDeployment()
+	AugmentWithOptions(deployment, Options{Owner: "test"})
+	assert.Equal(t, builderStrategy, deployment.Spec.Strategy)
+
+	strategy := &appsv1.DeploymentStrategy{
+		Type: appsv1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDeployment{
+			MaxSurge:       ptr.To(intstr.FromString("25%")),
+			MaxUnavailable: ptr.To(intstr.FromInt32(0)),
+		},
+	}
+	deployment = newDeployment()
+	AugmentWithOptions(deployment, Options{Owner: "test", Deployment: Deployment{Strategy: strategy}})
+	assert.Equal(t, *strategy, deployment.Spec.Strategy)
+}
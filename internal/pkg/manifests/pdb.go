@@ -10,17 +10,17 @@ import (
 // PodDisruptionBudgetOptions defines the available options for creating a PodDisruptionBudget object.
 type PodDisruptionBudgetOptions struct {
 	// MaxUnavailable is the maximum number of pods that can be unavailable during the disruption.
-	// Defaults to 1 if not specified.
+	// Defaults to 1 if neither MaxUnavailable nor MinAvailable is specified.
 	MaxUnavailable *int32
 	// MinAvailable is the minimum number of pods that must still be available during the disruption.
-	// Defaults to nil if not specified.
+	// Defaults to nil if not specified. Takes precedence over MaxUnavailable.
 	MinAvailable *int32
 }
 
 // NewPodDisruptionBudget creates a new PodDisruptionBudget object.
 // It sets the object name, namespace, selector labels, object meta labels, and maxUnavailable.
-// The maxUnavailable is a pointer to an int32 value.
-// If the maxUnavailable is nil, it defaults to 1.
+// If minAvailable is set, maxUnavailable is not set on the PodDisruptionBudget.
+// Otherwise, if the maxUnavailable is nil, it defaults to 1.
 func NewPodDisruptionBudget(name, namespace string, selectorLabels, objectMetaLabels, annotations map[string]string, opts PodDisruptionBudgetOptions) *policyv1.PodDisruptionBudget {
 	minValue, maxValue := opts.getMinAndMax()
 	return &policyv1.PodDisruptionBudget{
@@ -50,6 +50,11 @@ func (opts PodDisruptionBudgetOptions) getMinAndMax() (min *intstr.IntOrString,
 		min = &minValue
 	}
 
+	// A PodDisruptionBudget only accepts one of minAvailable and maxUnavailable.
+	if opts.MinAvailable != nil {
+		return min, nil
+	}
+
 	if opts.MaxUnavailable == nil {
 		opts.MaxUnavailable = ptr.To(int32(1))
 	}
@@ -4,6 +4,9 @@ import (
 	"testing"
 
 	"gotest.tools/v3/golden"
+
+	"k8s.io/utils/ptr"
+
 	"sigs.k8s.io/yaml"
 )
 
@@ -33,6 +36,18 @@ func TestNewPodDisruptionBudget(t *testing.T) {
 				conf:             PodDisruptionBudgetOptions{},
 			},
 		},
+		{
+			name:   "Test NewPodDisruptionBudget with minAvailable",
+			golden: "pdb-min-available.golden.yaml",
+			args: args{
+				name:             "test-name",
+				namespace:        "test-namespace",
+				selectorLabels:   map[string]string{"test": "label"},
+				objectMetaLabels: map[string]string{"test": "label"},
+				annotations:      map[string]string{"test": "annotation"},
+				conf:             PodDisruptionBudgetOptions{MinAvailable: ptr.To(int32(2))},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,78 @@
+package manifests
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SetOwnerUIDLabel labels the object with the UID of the resource that owns it.
+// Unlike the OwnerLabel, the UID survives neither the renaming nor the recreation of the owner,
+// so that objects left behind by a previous incarnation of the owner are told apart.
+func SetOwnerUIDLabel(obj client.Object, owner types.UID) {
+	if owner == "" {
+		return
+	}
+	obj.SetLabels(MergeMaps(obj.GetLabels(), map[string]string{OwnerUIDLabel: string(owner)}))
+}
+
+// GetLabelSelectorForOwnerUID returns a ListOption that selects the objects labelled with the UID of the given owner.
+func GetLabelSelectorForOwnerUID(owner types.UID) client.ListOption {
+	return client.MatchingLabels{OwnerUIDLabel: string(owner)}
+}
+
+// ObjectSet is the set of objects applied for an owner.
+// Objects are identified by their type, namespace and name. It is safe for concurrent use.
+type ObjectSet struct {
+	mu      sync.Mutex
+	objects map[objectKey]struct{}
+}
+
+type objectKey struct {
+	kind, namespace, name string
+}
+
+func keyFor(obj client.Object) objectKey {
+	// typed objects do not always set their kind, so they are told apart by their type
+	return objectKey{kind: fmt.Sprintf("%T", obj), namespace: obj.GetNamespace(), name: obj.GetName()}
+}
+
+// NewObjectSet returns an empty ObjectSet.
+func NewObjectSet() *ObjectSet {
+	return &ObjectSet{objects: make(map[objectKey]struct{})}
+}
+
+// Add adds the objects to the set.
+func (s *ObjectSet) Add(objs ...client.Object) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, obj := range objs {
+		s.objects[keyFor(obj)] = struct{}{}
+	}
+}
+
+// Has returns true if the object is in the set.
+func (s *ObjectSet) Has(obj client.Object) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.objects[keyFor(obj)]
+	return ok
+}
+
+// Stale returns the objects labelled with the UID of the owner that are not in the set.
+// These are the objects the owner generated before, but no longer generates.
+func (s *ObjectSet) Stale(owner types.UID, objs []client.Object) []client.Object {
+	var stale []client.Object
+	for _, obj := range objs {
+		if owner == "" || obj.GetLabels()[OwnerUIDLabel] != string(owner) {
+			continue
+		}
+		if !s.Has(obj) {
+			stale = append(stale, obj)
+		}
+	}
+	return stale
+}
@@ -0,0 +1,49 @@
+package manifests
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestObjectSet_Stale(t *testing.T) {
+	owned := func(obj client.Object, owner types.UID) client.Object {
+		obj.SetNamespace("ns")
+		SetOwnerUIDLabel(obj, owner)
+		return obj
+	}
+
+	applied := NewObjectSet()
+	applied.Add(
+		owned(&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "a"}}, "owner-uid"),
+		owned(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "b"}}, "owner-uid"),
+	)
+
+	existing := []client.Object{
+		owned(&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "a"}}, "owner-uid"),
+		// same name, different kind
+		owned(&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "a"}}, "owner-uid"),
+		owned(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "b"}}, "owner-uid"),
+		owned(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "renamed"}}, "owner-uid"),
+		owned(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "c"}}, "other-uid"),
+	}
+
+	stale := applied.Stale("owner-uid", existing)
+	if len(stale) != 2 {
+		t.Fatalf("expected 2 stale objects, got %d", len(stale))
+	}
+	if _, ok := stale[0].(*appsv1.StatefulSet); !ok || stale[0].GetName() != "a" {
+		t.Errorf("expected StatefulSet a to be stale, got %T %s", stale[0], stale[0].GetName())
+	}
+	if stale[1].GetName() != "renamed" {
+		t.Errorf("expected ConfigMap renamed to be stale, got %s", stale[1].GetName())
+	}
+	if len(applied.Stale("", existing)) != 0 {
+		t.Error("expected no stale objects without an owner UID")
+	}
+}
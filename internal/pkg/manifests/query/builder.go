@@ -26,19 +26,50 @@ const (
 
 	HTTPPort     = 9090
 	HTTPPortName = "http"
+
+	activeQueryVolumeName = "active-queries"
+	activeQueryMountPath  = "/var/thanos/query"
 )
 
 // Options for Thanos Query
 type Options struct {
 	manifests.Options
-	ReplicaLabels      []string
-	Timeout            string
-	LookbackDelta      string
-	MaxConcurrent      int
+	ReplicaLabels []string
+	Timeout       string
+	LookbackDelta string
+	MaxConcurrent int
+	// AutoDownsampling queries downsampled data when no max_source_resolution parameter is set.
+	AutoDownsampling bool
+	// PartialResponse enables or disables partial responses for queries without a partial_response parameter.
+	// If nil, the Thanos default is used.
+	PartialResponse *bool
+	// DeduplicationFunc is the function used to deduplicate series. If empty, the Thanos default is used.
+	DeduplicationFunc  string
 	WebOptions         WebOptions
 	TelemetryQuantiles TelemetryQuantiles
 	GRPCProxyStrategy  string
 	Endpoints          []Endpoint
+	// ExternalEndpoints are StoreAPI endpoints outside of the cluster, as host:port.
+	// They are passed to the Querier in a file SD file mounted from a ConfigMap, so that changes do not roll out the Deployment.
+	ExternalEndpoints []string
+	// ArgsFile passes the flags of the Querier in an args file mounted from a ConfigMap instead of inline.
+	ArgsFile bool
+	// GRPCServerTLS is the TLS configuration for the gRPC server.
+	// If not set, the gRPC server is served without TLS.
+	GRPCServerTLS *manifests.TLSConfig
+	// GRPCClientTLS is the TLS configuration for the connections to the endpoints.
+	// If not set, TLS is only used if an endpoint requires it.
+	GRPCClientTLS *manifests.GRPCClientTLSConfig
+	// Ingress exposes the query UI and API outside of the cluster. Not built if nil.
+	Ingress *manifests.IngressOptions
+	// RequestLogging is the request logging configuration. Requests are not logged if nil.
+	RequestLogging *manifests.RequestLoggingOptions
+	// ActiveQueryTracking records the queries in progress in a file on an emptyDir volume.
+	ActiveQueryTracking bool
+	// RemoteEndpoints are the StoreAPI endpoints of remote clusters federated by the Querier.
+	RemoteEndpoints []RemoteEndpoint
+	// EndpointScheme sets how the Querier resolves the addresses of the Endpoints. Defaults to EndpointSchemeDNS.
+	EndpointScheme EndpointScheme
 }
 
 type WebOptions struct {
@@ -61,6 +92,63 @@ type Endpoint struct {
 	Namespace   string
 	Type        manifests.EndpointType
 	Port        int32
+	// PortName is the name of the gRPC port of the Service, used to resolve its SRV records.
+	PortName string
+	// TLS is true if the endpoint serves gRPC over TLS.
+	TLS bool
+}
+
+// EndpointScheme is how the Querier resolves the addresses of the StoreAPI Services.
+type EndpointScheme string
+
+const (
+	// EndpointSchemeDNS resolves the A and AAAA records of the Service, with the dns+ prefix.
+	EndpointSchemeDNS EndpointScheme = "dns"
+	// EndpointSchemeDNSSRV resolves the SRV records of the named gRPC port of the Service, with the dnssrv+ prefix.
+	// Endpoints without a port name fall back to EndpointSchemeDNS.
+	EndpointSchemeDNSSRV EndpointScheme = "dnssrv"
+	// EndpointSchemePlain dials the address of the Service as it is.
+	EndpointSchemePlain EndpointScheme = "plain"
+)
+
+// address returns the address of the endpoint for the given scheme.
+// Endpoint groups are resolved by the gRPC DNS resolver unless the scheme is plain.
+func (ep Endpoint) address(scheme EndpointScheme) string {
+	host := manifests.ServiceHost(ep.ServiceName, ep.Namespace)
+	group := ep.Type == manifests.GroupLabel || ep.Type == manifests.GroupStrictLabel
+	switch {
+	case scheme == EndpointSchemePlain:
+		return fmt.Sprintf("%s:%d", host, ep.Port)
+	case group:
+		return fmt.Sprintf("dns:///%s:%d", host, ep.Port)
+	case scheme == EndpointSchemeDNSSRV && ep.PortName != "":
+		return fmt.Sprintf("dnssrv+_%s._tcp.%s", ep.PortName, host)
+	default:
+		return fmt.Sprintf("dns+%s:%d", host, ep.Port)
+	}
+}
+
+// RemoteEndpoint is the StoreAPI endpoint of a remote cluster, dialed at its address.
+type RemoteEndpoint struct {
+	// Address is the address of the endpoint, as host:port.
+	Address string
+	// Type sets how the Querier connects to the endpoint.
+	Type manifests.EndpointType
+}
+
+// clientTLS returns the TLS configuration for the connections to the endpoints.
+// Thanos uses a single TLS configuration for all endpoints, so TLS is enabled for all of them
+// as soon as one endpoint requires it, verifying server certificates against the system roots by default.
+func (opts Options) clientTLS() *manifests.GRPCClientTLSConfig {
+	if opts.GRPCClientTLS != nil {
+		return opts.GRPCClientTLS
+	}
+	for _, ep := range opts.Endpoints {
+		if ep.TLS {
+			return &manifests.GRPCClientTLSConfig{}
+		}
+	}
+	return nil
 }
 
 func (opts Options) Build() []client.Object {
@@ -69,16 +157,36 @@ func (opts Options) Build() []client.Object {
 	objectMetaLabels := GetLabels(opts)
 	name := opts.GetGeneratedResourceName()
 
-	objs = append(objs, manifests.BuildServiceAccount(opts.GetGeneratedResourceName(), opts.Namespace, selectorLabels, opts.Annotations))
+	objs = append(objs, manifests.BuildServiceAccount(opts.GetGeneratedResourceName(), opts.Namespace, selectorLabels, manifests.MergeMaps(opts.Annotations, opts.ServiceAccountAnnotations)))
 	objs = append(objs, newQueryDeployment(opts, selectorLabels, objectMetaLabels))
 	objs = append(objs, newQueryService(opts, selectorLabels, objectMetaLabels))
 
+	if opts.ArgsFile {
+		objs = append(objs, newQueryArgsFile(opts, selectorLabels, objectMetaLabels))
+	}
+
+	if len(opts.ExternalEndpoints) > 0 {
+		objs = append(objs, newExternalEndpointsConfigMap(name, opts.Namespace, opts.ExternalEndpoints, objectMetaLabels))
+	}
+
+	if opts.Ingress != nil {
+		objs = append(objs, manifests.BuildIngress(name, opts.Namespace, objectMetaLabels, *opts.Ingress, name, HTTPPort))
+	}
+
 	if opts.PodDisruptionConfig != nil {
 		objs = append(objs, manifests.NewPodDisruptionBudget(name, opts.Namespace, selectorLabels, objectMetaLabels, opts.Annotations, *opts.PodDisruptionConfig))
 	}
 
+	if opts.EnableMetricsService {
+		objs = append(objs, manifests.BuildMetricsService(name, opts.Namespace, objectMetaLabels, selectorLabels, opts.Annotations, HTTPPort))
+	}
+
 	if opts.ServiceMonitorConfig != nil {
-		objs = append(objs, manifests.BuildServiceMonitor(name, opts.Namespace, objectMetaLabels, selectorLabels, serviceMonitorOpts(opts.ServiceMonitorConfig)))
+		objs = append(objs, manifests.BuildServiceMonitor(name, opts.Namespace, objectMetaLabels, selectorLabels, serviceMonitorOpts(opts.ServiceMonitorConfig, opts.EnableMetricsService)))
+	}
+
+	if opts.MonitoringMixinConfig != nil {
+		objs = append(objs, newQueryMixin(opts, objectMetaLabels)...)
 	}
 	return objs
 }
@@ -91,8 +199,7 @@ func (opts Options) Valid() error {
 }
 
 func (opts Options) GetGeneratedResourceName() string {
-	name := fmt.Sprintf("%s-%s", Name, opts.getOwner())
-	return manifests.ValidateAndSanitizeResourceName(name)
+	return manifests.ValidateAndSanitizeResourceName(manifests.GeneratedName(Name, opts.getOwner()))
 }
 
 func (opts Options) getOwner() string {
@@ -140,6 +247,9 @@ func newQueryDeployment(opts Options, selectorLabels, objectMetaLabels map[strin
 					"ALL",
 				},
 			},
+			SeccompProfile: &corev1.SeccompProfile{
+				Type: corev1.SeccompProfileTypeRuntimeDefault,
+			},
 		},
 		ReadinessProbe: &corev1.Probe{
 			ProbeHandler: corev1.ProbeHandler{
@@ -195,7 +305,7 @@ func newQueryDeployment(opts Options, selectorLabels, objectMetaLabels map[strin
 			Annotations: opts.Annotations,
 		},
 		Spec: appsv1.DeploymentSpec{
-			Replicas: &opts.Replicas,
+			Replicas: opts.DeploymentReplicas(),
 			Selector: &metav1.LabelSelector{
 				MatchLabels: selectorLabels,
 			},
@@ -212,15 +322,67 @@ func newQueryDeployment(opts Options, selectorLabels, objectMetaLabels map[strin
 		},
 	}
 
+	if opts.GRPCServerTLS != nil {
+		manifests.MountTLS(&deployment.Spec.Template, manifests.GRPCTLSServerName, *opts.GRPCServerTLS)
+	}
+	if tls := opts.clientTLS(); tls != nil {
+		manifests.MountGRPCClientTLS(&deployment.Spec.Template, *tls)
+	}
+	if len(opts.ExternalEndpoints) > 0 {
+		mountExternalEndpoints(&deployment.Spec.Template, name)
+	}
+	if opts.ActiveQueryTracking {
+		mountActiveQueryTracker(&deployment.Spec.Template)
+	}
+
 	manifests.AugmentWithOptions(deployment, opts.Options)
+	if opts.ArgsFile {
+		manifests.MountArgsFile(&deployment.Spec.Template, name)
+	}
 	return deployment
 }
 
+// mountActiveQueryTracker mounts the emptyDir volume holding the file of the queries in progress into the Querier container.
+// The volume outlives container restarts, so that the queries that were running when the Querier crashed are logged.
+func mountActiveQueryTracker(tpl *corev1.PodTemplateSpec) {
+	tpl.Spec.Volumes = append(tpl.Spec.Volumes, corev1.Volume{
+		Name:         activeQueryVolumeName,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	})
+	for i, c := range tpl.Spec.Containers {
+		if c.Name != Name {
+			continue
+		}
+		tpl.Spec.Containers[i].VolumeMounts = append(tpl.Spec.Containers[i].VolumeMounts, corev1.VolumeMount{
+			Name:      activeQueryVolumeName,
+			MountPath: activeQueryMountPath,
+		})
+	}
+}
+
+// newQueryArgsFile creates the ConfigMap holding the args file of the Querier.
+// The args are taken from the Deployment after it is augmented, so that they include the additional args.
+func newQueryArgsFile(opts Options, selectorLabels, objectMetaLabels map[string]string) *corev1.ConfigMap {
+	inline := opts
+	inline.ArgsFile = false
+	deployment := newQueryDeployment(inline, selectorLabels, objectMetaLabels)
+	return manifests.NewArgsFileConfigMap(opts.GetGeneratedResourceName(), opts.Namespace, deployment.Spec.Template.Spec.Containers[0].Args, objectMetaLabels, opts.Annotations)
+}
+
 func NewQueryService(opts Options) *corev1.Service {
 	selectorLabels := opts.GetSelectorLabels()
 	return newQueryService(opts, selectorLabels, manifests.MergeMaps(opts.Labels, selectorLabels))
 }
 
+// serviceLabels returns the labels of the Querier Service.
+// Services of queriers serving gRPC over TLS are labeled so that other queriers connect to them over TLS.
+func serviceLabels(opts Options, objectMetaLabels map[string]string) map[string]string {
+	if opts.GRPCServerTLS == nil {
+		return objectMetaLabels
+	}
+	return manifests.MergeMaps(objectMetaLabels, map[string]string{manifests.GRPCTLSLabel: manifests.GRPCTLSLabelValue})
+}
+
 func newQueryService(opts Options, selectorLabels, objectMetaLabels map[string]string) *corev1.Service {
 	servicePorts := []corev1.ServicePort{
 		{
@@ -247,8 +409,8 @@ func newQueryService(opts Options, selectorLabels, objectMetaLabels map[string]s
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        opts.GetGeneratedResourceName(),
 			Namespace:   opts.Namespace,
-			Labels:      objectMetaLabels,
-			Annotations: opts.Annotations,
+			Labels:      serviceLabels(opts, objectMetaLabels),
+			Annotations: opts.GetServiceAnnotations(),
 		},
 		Spec: corev1.ServiceSpec{
 			Selector:  selectorLabels,
@@ -266,15 +428,26 @@ func queryArgs(opts Options) []string {
 		fmt.Sprintf("--http-address=0.0.0.0:%d", HTTPPort),
 		fmt.Sprintf("--query.timeout=%s", opts.Timeout),
 		fmt.Sprintf("--query.lookback-delta=%s", opts.LookbackDelta),
-		"--query.auto-downsampling",
 		"--query.promql-engine=thanos",
 		fmt.Sprintf("--query.max-concurrent=%d", opts.MaxConcurrent),
 		fmt.Sprintf("--web.route-prefix=%s", opts.WebOptions.RoutePrefix),
 		fmt.Sprintf("--web.external-prefix=%s", opts.WebOptions.ExternalPrefix),
 		fmt.Sprintf("--web.prefix-header=%s", opts.WebOptions.PrefixHeader),
 		fmt.Sprintf("--grpc.proxy-strategy=%s", opts.GRPCProxyStrategy),
+		fmt.Sprintf("--deduplication.func=%s", opts.DeduplicationFunc),
 	)
 
+	if opts.AutoDownsampling {
+		args = append(args, "--query.auto-downsampling")
+	}
+	if opts.PartialResponse != nil {
+		if *opts.PartialResponse {
+			args = append(args, "--query.partial-response")
+		} else {
+			args = append(args, "--no-query.partial-response")
+		}
+	}
+
 	for _, duration := range opts.TelemetryQuantiles.Duration {
 		args = append(args, fmt.Sprintf("--query.telemetry.request-duration-seconds-quantiles=%s", duration))
 	}
@@ -285,6 +458,13 @@ func queryArgs(opts Options) []string {
 		args = append(args, fmt.Sprintf("--query.telemetry.request-series-seconds-quantiles=%s", series))
 	}
 
+	if opts.RequestLogging != nil {
+		args = append(args, fmt.Sprintf("--request.logging-config=%s", opts.RequestLogging.String()))
+	}
+	if opts.ActiveQueryTracking {
+		args = append(args, fmt.Sprintf("--query.active-query-path=%s", activeQueryMountPath))
+	}
+
 	if opts.WebOptions.DisableCORS {
 		args = append(args, "--web.disable-cors")
 	}
@@ -297,17 +477,41 @@ func queryArgs(opts Options) []string {
 		switch ep.Type {
 		case manifests.RegularLabel:
 			// TODO(saswatamcode): For regular probably use SD file.
-			args = append(args, fmt.Sprintf("--endpoint=dnssrv+_grpc._tcp.%s.%s.svc", ep.ServiceName, ep.Namespace))
+			args = append(args, fmt.Sprintf("--endpoint=%s", ep.address(opts.EndpointScheme)))
+		case manifests.StrictLabel:
+			args = append(args, fmt.Sprintf("--endpoint-strict=%s", ep.address(opts.EndpointScheme)))
+		case manifests.GroupLabel:
+			args = append(args, fmt.Sprintf("--endpoint-group=%s", ep.address(opts.EndpointScheme)))
+		case manifests.GroupStrictLabel:
+			args = append(args, fmt.Sprintf("--endpoint-group-strict=%s", ep.address(opts.EndpointScheme)))
+		default:
+			panic("unknown endpoint type")
+		}
+	}
+	for _, ep := range opts.RemoteEndpoints {
+		switch ep.Type {
+		case manifests.RegularLabel:
+			args = append(args, fmt.Sprintf("--endpoint=%s", ep.Address))
 		case manifests.StrictLabel:
-			args = append(args, fmt.Sprintf("--endpoint-strict=dnssrv+_grpc._tcp.%s.%s.svc", ep.ServiceName, ep.Namespace))
+			args = append(args, fmt.Sprintf("--endpoint-strict=%s", ep.Address))
 		case manifests.GroupLabel:
-			args = append(args, fmt.Sprintf("--endpoint-group=%s.%s.svc:%d", ep.ServiceName, ep.Namespace, ep.Port))
+			args = append(args, fmt.Sprintf("--endpoint-group=dns:///%s", ep.Address))
 		case manifests.GroupStrictLabel:
-			args = append(args, fmt.Sprintf("--endpoint-group-strict=%s.%s.svc:%d", ep.ServiceName, ep.Namespace, ep.Port))
+			args = append(args, fmt.Sprintf("--endpoint-group-strict=dns:///%s", ep.Address))
 		default:
 			panic("unknown endpoint type")
 		}
 	}
+	if len(opts.ExternalEndpoints) > 0 {
+		args = append(args, fmt.Sprintf("--store.sd-files=%s/%s", externalEndpointsMountPath, ExternalEndpointsKey))
+	}
+
+	if opts.GRPCServerTLS != nil {
+		args = append(args, manifests.GRPCServerTLSArgs(*opts.GRPCServerTLS)...)
+	}
+	if tls := opts.clientTLS(); tls != nil {
+		args = append(args, manifests.GRPCClientTLSArgs("--grpc-client", *tls)...)
+	}
 
 	return manifests.PruneEmptyArgs(args)
 }
@@ -337,9 +541,10 @@ func GetLabels(opts Options) map[string]string {
 	return manifests.MergeMaps(opts.Labels, opts.GetSelectorLabels())
 }
 
-func serviceMonitorOpts(from *manifests.ServiceMonitorConfig) manifests.ServiceMonitorOptions {
+func serviceMonitorOpts(from *manifests.ServiceMonitorConfig, metricsService bool) manifests.ServiceMonitorOptions {
 	return manifests.ServiceMonitorOptions{
-		Port:     ptr.To(HTTPPortName),
-		Interval: from.Interval,
+		Port:           ptr.To(HTTPPortName),
+		Interval:       from.Interval,
+		MetricsService: metricsService,
 	}
 }
@@ -1,8 +1,11 @@
 package query
 
 import (
+	"slices"
+	"strings"
 	"testing"
 
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"gotest.tools/v3/assert"
 
 	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
@@ -38,9 +41,10 @@ func TestBuildQuery(t *testing.T) {
 			},
 			PodDisruptionConfig: &manifests.PodDisruptionBudgetOptions{},
 		},
-		Timeout:       "15m",
-		LookbackDelta: "5m",
-		MaxConcurrent: 20,
+		Timeout:          "15m",
+		LookbackDelta:    "5m",
+		MaxConcurrent:    20,
+		AutoDownsampling: true,
 	}
 
 	objs := opts.Build()
@@ -63,6 +67,31 @@ func TestBuildQuery(t *testing.T) {
 	utils.ValidateObjectLabelsEqual(t, wantLabels, []client.Object{objs[1], objs[2]}...)
 }
 
+func TestBuildQueryMonitoringMixin(t *testing.T) {
+	opts := Options{
+		Options: manifests.Options{
+			Owner:                 "any",
+			Namespace:             "ns",
+			MonitoringMixinConfig: &manifests.MonitoringMixinConfig{Labels: map[string]string{"team": "observability"}},
+		},
+	}
+
+	objs := opts.Build()
+	dashboard, ok := objs[len(objs)-2].(*corev1.ConfigMap)
+	assert.Assert(t, ok, "expected a dashboard ConfigMap to be built")
+	assert.Equal(t, dashboard.GetLabels()[manifests.GrafanaDashboardLabel], manifests.GrafanaDashboardValue)
+	rule, ok := objs[len(objs)-1].(*monitoringv1.PrometheusRule)
+	assert.Assert(t, ok, "expected a PrometheusRule to be built")
+	assert.Equal(t, rule.GetLabels()["team"], "observability")
+
+	var alerts []string
+	for _, r := range rule.Spec.Groups[0].Rules {
+		alerts = append(alerts, r.Alert)
+		assert.Assert(t, strings.Contains(r.Expr.String(), `namespace="ns", job=~"thanos-query-any|thanos-query-any-metrics"`), r.Expr.String())
+	}
+	assert.DeepEqual(t, alerts, []string{"ThanosQueryInstantLatencyHigh", "ThanosQueryRangeLatencyHigh"})
+}
+
 func TestNewQueryDeployment(t *testing.T) {
 
 	for _, tc := range []struct {
@@ -88,9 +117,10 @@ func TestNewQueryDeployment(t *testing.T) {
 						"another": "annotation",
 					},
 				},
-				Timeout:       "15m",
-				LookbackDelta: "5m",
-				MaxConcurrent: 20,
+				Timeout:          "15m",
+				LookbackDelta:    "5m",
+				MaxConcurrent:    20,
+				AutoDownsampling: true,
 			},
 		},
 		{
@@ -119,9 +149,10 @@ func TestNewQueryDeployment(t *testing.T) {
 						},
 					},
 				},
-				Timeout:       "15m",
-				LookbackDelta: "5m",
-				MaxConcurrent: 20,
+				Timeout:          "15m",
+				LookbackDelta:    "5m",
+				MaxConcurrent:    20,
+				AutoDownsampling: true,
 			},
 		},
 		{
@@ -155,9 +186,10 @@ func TestNewQueryDeployment(t *testing.T) {
 						},
 					},
 				},
-				Timeout:       "15m",
-				LookbackDelta: "5m",
-				MaxConcurrent: 20,
+				Timeout:          "15m",
+				LookbackDelta:    "5m",
+				MaxConcurrent:    20,
+				AutoDownsampling: true,
 			},
 		},
 		{
@@ -180,9 +212,10 @@ func TestNewQueryDeployment(t *testing.T) {
 						EnableOtelSidecar: true,
 					},
 				},
-				Timeout:       "15m",
-				LookbackDelta: "5m",
-				MaxConcurrent: 20,
+				Timeout:          "15m",
+				LookbackDelta:    "5m",
+				MaxConcurrent:    20,
+				AutoDownsampling: true,
 			},
 		},
 	} {
@@ -215,9 +248,10 @@ func TestNewQueryService(t *testing.T) {
 				"another": "annotation",
 			},
 		},
-		Timeout:       "15m",
-		LookbackDelta: "5m",
-		MaxConcurrent: 20,
+		Timeout:          "15m",
+		LookbackDelta:    "5m",
+		MaxConcurrent:    20,
+		AutoDownsampling: true,
 	}
 
 	for _, tc := range []struct {
@@ -245,6 +279,112 @@ func TestNewQueryService(t *testing.T) {
 }
 
 // TestBuildQueryGolden uses golden files to validate complete manifest generation
+func TestQueryGRPCTLS(t *testing.T) {
+	opts := Options{
+		Options: manifests.Options{
+			Owner:     "any",
+			Namespace: "ns",
+			Image:     ptr.To("some-custom-image"),
+		},
+		Endpoints: []Endpoint{
+			{ServiceName: "ingester", Namespace: "ns", Type: manifests.RegularLabel, TLS: true},
+		},
+	}
+
+	deployment := NewQueryDeployment(opts)
+	args := deployment.Spec.Template.Spec.Containers[0].Args
+	assert.Assert(t, slices.Contains(args, "--grpc-client-tls-secure"), "expected TLS for an endpoint requiring it")
+	assert.Assert(t, !slices.ContainsFunc(args, func(arg string) bool { return strings.HasPrefix(arg, "--grpc-client-tls-ca") }))
+	_, ok := NewQueryService(opts).GetLabels()[manifests.GRPCTLSLabel]
+	assert.Assert(t, !ok, "expected no TLS label without a server TLS configuration")
+
+	opts.GRPCServerTLS = &manifests.TLSConfig{
+		CertSecret: "query-tls",
+		ClientCA:   &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "ca"}, Key: "ca.crt"},
+	}
+	opts.GRPCClientTLS = &manifests.GRPCClientTLSConfig{
+		CA:     &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "ca"}, Key: "ca.crt"},
+		CAHash: "abc",
+	}
+	deployment = NewQueryDeployment(opts)
+	args = deployment.Spec.Template.Spec.Containers[0].Args
+	assert.Assert(t, slices.Contains(args, "--grpc-server-tls-cert=/etc/thanos/tls/grpc/tls.crt"))
+	assert.Assert(t, slices.Contains(args, "--grpc-server-tls-client-ca=/etc/thanos/tls/grpc-client-ca/ca.crt"))
+	assert.Assert(t, slices.Contains(args, "--grpc-client-tls-ca=/etc/thanos/tls/grpc-client-server-ca/ca.crt"))
+	assert.Equal(t, deployment.Spec.Template.Annotations[manifests.GRPCClientCAHashAnnotation], "abc")
+	assert.Equal(t, NewQueryService(opts).GetLabels()[manifests.GRPCTLSLabel], manifests.GRPCTLSLabelValue)
+
+	mounts := make(map[string]string)
+	for _, m := range deployment.Spec.Template.Spec.Containers[0].VolumeMounts {
+		_, exists := mounts[m.MountPath]
+		assert.Assert(t, !exists, "expected a single volume mounted at %s", m.MountPath)
+		mounts[m.MountPath] = m.Name
+	}
+}
+
+func TestQueryRequestDebugging(t *testing.T) {
+	opts := Options{
+		Options: manifests.Options{
+			Owner:     "any",
+			Namespace: "ns",
+			Image:     ptr.To("some-custom-image"),
+		},
+	}
+
+	deployment := NewQueryDeployment(opts)
+	args := deployment.Spec.Template.Spec.Containers[0].Args
+	assert.Assert(t, !slices.ContainsFunc(args, func(arg string) bool { return strings.HasPrefix(arg, "--request.logging-config") }))
+	assert.Assert(t, !slices.ContainsFunc(args, func(arg string) bool { return strings.HasPrefix(arg, "--query.active-query-path") }))
+	assert.Equal(t, len(deployment.Spec.Template.Spec.Volumes), 0)
+
+	opts.RequestLogging = &manifests.RequestLoggingOptions{HTTP: &manifests.RequestLogging{Level: "INFO", LogEnd: true}}
+	opts.ActiveQueryTracking = true
+	deployment = NewQueryDeployment(opts)
+	args = deployment.Spec.Template.Spec.Containers[0].Args
+	assert.Assert(t, slices.Contains(args, "--request.logging-config="+opts.RequestLogging.String()))
+	assert.Assert(t, slices.Contains(args, "--query.active-query-path=/var/thanos/query"))
+	assert.Equal(t, deployment.Spec.Template.Spec.Volumes[0].Name, "active-queries")
+	assert.Assert(t, deployment.Spec.Template.Spec.Volumes[0].EmptyDir != nil)
+	assert.Equal(t, deployment.Spec.Template.Spec.Containers[0].VolumeMounts[0].MountPath, "/var/thanos/query")
+}
+
+func TestQueryEndpointScheme(t *testing.T) {
+	endpoints := []Endpoint{
+		{ServiceName: "store", Namespace: "ns", Type: manifests.RegularLabel, Port: 10901, PortName: "grpc"},
+		{ServiceName: "sidecar", Namespace: "ns", Type: manifests.StrictLabel, Port: 10901},
+		{ServiceName: "query", Namespace: "ns", Type: manifests.GroupLabel, Port: 10901, PortName: "grpc"},
+	}
+	for _, tc := range []struct {
+		scheme EndpointScheme
+		expect []string
+	}{
+		{
+			scheme: "",
+			expect: []string{"--endpoint=dns+store.ns.svc:10901", "--endpoint-strict=dns+sidecar.ns.svc:10901", "--endpoint-group=dns:///query.ns.svc:10901"},
+		},
+		{
+			scheme: EndpointSchemeDNSSRV,
+			expect: []string{"--endpoint=dnssrv+_grpc._tcp.store.ns.svc", "--endpoint-strict=dns+sidecar.ns.svc:10901", "--endpoint-group=dns:///query.ns.svc:10901"},
+		},
+		{
+			scheme: EndpointSchemePlain,
+			expect: []string{"--endpoint=store.ns.svc:10901", "--endpoint-strict=sidecar.ns.svc:10901", "--endpoint-group=query.ns.svc:10901"},
+		},
+	} {
+		t.Run(string(tc.scheme), func(t *testing.T) {
+			opts := Options{
+				Options:        manifests.Options{Owner: "any", Namespace: "ns"},
+				Endpoints:      endpoints,
+				EndpointScheme: tc.scheme,
+			}
+			args := NewQueryDeployment(opts).Spec.Template.Spec.Containers[0].Args
+			for _, arg := range tc.expect {
+				assert.Assert(t, slices.Contains(args, arg), "expected %s in %v", arg, args)
+			}
+		})
+	}
+}
+
 func TestBuildQueryGolden(t *testing.T) {
 	tests := []struct {
 		name string
@@ -266,9 +406,10 @@ func TestBuildQueryGolden(t *testing.T) {
 					},
 					PodDisruptionConfig: &manifests.PodDisruptionBudgetOptions{},
 				},
-				Timeout:       "15m",
-				LookbackDelta: "5m",
-				MaxConcurrent: 20,
+				Timeout:          "15m",
+				LookbackDelta:    "5m",
+				MaxConcurrent:    20,
+				AutoDownsampling: true,
 			},
 		},
 		{
@@ -316,9 +457,72 @@ func TestBuildQueryGolden(t *testing.T) {
 					},
 					PodDisruptionConfig: &manifests.PodDisruptionBudgetOptions{},
 				},
-				Timeout:       "30m",
-				LookbackDelta: "10m",
-				MaxConcurrent: 50,
+				Timeout:           "30m",
+				LookbackDelta:     "10m",
+				MaxConcurrent:     50,
+				PartialResponse:   ptr.To(false),
+				DeduplicationFunc: "chain",
+			},
+		},
+		{
+			name: "query-args-file",
+			opts: Options{
+				Options: manifests.Options{
+					Owner:     "test-owner",
+					Namespace: "test-namespace",
+					Image:     ptr.To("quay.io/thanos/thanos:v0.40.1"),
+					Additional: manifests.Additional{
+						Args: []string{"--query.timeout=30m"},
+					},
+				},
+				Timeout:          "15m",
+				LookbackDelta:    "5m",
+				MaxConcurrent:    20,
+				AutoDownsampling: true,
+				Endpoints: []Endpoint{
+					{ServiceName: "store", Namespace: "test-namespace", Type: manifests.RegularLabel},
+					{ServiceName: "receive", Namespace: "test-namespace", Type: manifests.GroupLabel, Port: 10901},
+				},
+				ArgsFile: true,
+			},
+		},
+		{
+			name: "query-external-endpoints",
+			opts: Options{
+				Options: manifests.Options{
+					Owner:     "test-owner",
+					Namespace: "test-namespace",
+					Image:     ptr.To("quay.io/thanos/thanos:v0.40.1"),
+				},
+				Timeout:          "15m",
+				LookbackDelta:    "5m",
+				MaxConcurrent:    20,
+				AutoDownsampling: true,
+				Endpoints: []Endpoint{
+					{ServiceName: "store", Namespace: "test-namespace", Type: manifests.RegularLabel},
+				},
+				ExternalEndpoints: []string{"store-0.example.com:10901", "10.0.0.1:10901"},
+			},
+		},
+		{
+			name: "query-federation",
+			opts: Options{
+				Options: manifests.Options{
+					Owner:     "test-owner",
+					Namespace: "test-namespace",
+					Image:     ptr.To("quay.io/thanos/thanos:v0.40.1"),
+				},
+				Timeout:          "15m",
+				LookbackDelta:    "5m",
+				MaxConcurrent:    20,
+				AutoDownsampling: true,
+				RemoteEndpoints: []RemoteEndpoint{
+					{Address: "query.eu.example.com:10901", Type: manifests.RegularLabel},
+					{Address: "query.us.example.com:10901", Type: manifests.StrictLabel},
+					{Address: "query.ap.example.com:10901", Type: manifests.GroupLabel},
+					{Address: "query.sa.example.com:10901", Type: manifests.GroupStrictLabel},
+				},
+				GRPCClientTLS: manifests.GRPCClientTLSFromSecret("federation-tls"),
 			},
 		},
 	}
@@ -0,0 +1,78 @@
+package query
+
+import (
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// ExternalEndpointsKey is the key in the ConfigMap for the file SD file of the external endpoints.
+	ExternalEndpointsKey = "endpoints.json"
+
+	externalEndpointsVolumeName = "external-endpoints"
+	externalEndpointsMountPath  = "/etc/thanos/sd"
+)
+
+// fileSDGroup is a target group of a file SD file, as read by the Querier with --store.sd-files.
+type fileSDGroup struct {
+	Targets []string `json:"targets"`
+}
+
+// externalEndpointsFile renders the file SD file of the external endpoints.
+func externalEndpointsFile(endpoints []string) string {
+	// a slice of strings always marshals
+	b, _ := json.Marshal([]fileSDGroup{{Targets: endpoints}})
+	return string(b)
+}
+
+// ExternalEndpointsConfigMapName returns the name of the ConfigMap holding the external endpoints of the Querier.
+func ExternalEndpointsConfigMapName(queryName string) string {
+	return queryName + "-external-endpoints"
+}
+
+// newExternalEndpointsConfigMap creates the ConfigMap holding the file SD file of the external endpoints.
+func newExternalEndpointsConfigMap(queryName, namespace string, endpoints []string, objectMetaLabels map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: corev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ExternalEndpointsConfigMapName(queryName),
+			Labels:    objectMetaLabels,
+			Namespace: namespace,
+		},
+		Data: map[string]string{
+			ExternalEndpointsKey: externalEndpointsFile(endpoints),
+		},
+	}
+}
+
+// mountExternalEndpoints mounts the external endpoints ConfigMap into the Querier container of the pod template.
+// The kubelet updates the mounted file when the ConfigMap changes, and the Querier picks up the change
+// without being restarted.
+func mountExternalEndpoints(tpl *corev1.PodTemplateSpec, queryName string) {
+	tpl.Spec.Volumes = append(tpl.Spec.Volumes, corev1.Volume{
+		Name: externalEndpointsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: ExternalEndpointsConfigMapName(queryName),
+				},
+				DefaultMode: ptr.To(int32(420)),
+			},
+		},
+	})
+	for i, c := range tpl.Spec.Containers {
+		if c.Name != Name {
+			continue
+		}
+		tpl.Spec.Containers[i].VolumeMounts = append(tpl.Spec.Containers[i].VolumeMounts, corev1.VolumeMount{
+			Name:      externalEndpointsVolumeName,
+			MountPath: externalEndpointsMountPath,
+		})
+	}
+}
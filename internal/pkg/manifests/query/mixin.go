@@ -0,0 +1,106 @@
+package query
+
+import (
+	"fmt"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// instantQueryLatencyThreshold and rangeQueryLatencyThreshold are the p99 latencies in seconds
+	// above which an alert fires, as in the Thanos mixin.
+	instantQueryLatencyThreshold = 40
+	rangeQueryLatencyThreshold   = 90
+)
+
+// newQueryMixin builds the dashboard and the alerting rules of the queriers.
+func newQueryMixin(opts Options, objectMetaLabels map[string]string) []client.Object {
+	name := opts.GetGeneratedResourceName()
+	selector := manifests.ScrapeJobSelector(opts.Namespace, name)
+	labels := manifests.MergeMaps(opts.MonitoringMixinConfig.Labels, objectMetaLabels)
+
+	dashboard := manifests.Dashboard{
+		Title: fmt.Sprintf("Thanos Query / %s / %s", opts.Namespace, opts.Owner),
+		Panels: []manifests.DashboardPanel{
+			{
+				Title: "Instant queries",
+				Unit:  "reqps",
+				Queries: []manifests.DashboardQuery{{
+					Expr:   fmt.Sprintf(`sum by (code) (rate(http_requests_total{%s, handler="query"}[5m]))`, selector),
+					Legend: "{{code}}",
+				}},
+			},
+			{
+				Title: "Instant query latency",
+				Unit:  "s",
+				Queries: []manifests.DashboardQuery{{
+					Expr:   queryLatency(selector, "query"),
+					Legend: "p99",
+				}},
+			},
+			{
+				Title: "Range queries",
+				Unit:  "reqps",
+				Queries: []manifests.DashboardQuery{{
+					Expr:   fmt.Sprintf(`sum by (code) (rate(http_requests_total{%s, handler="query_range"}[5m]))`, selector),
+					Legend: "{{code}}",
+				}},
+			},
+			{
+				Title: "Range query latency",
+				Unit:  "s",
+				Queries: []manifests.DashboardQuery{{
+					Expr:   queryLatency(selector, "query_range"),
+					Legend: "p99",
+				}},
+			},
+			{
+				Title: "Query errors",
+				Unit:  "percentunit",
+				Queries: []manifests.DashboardQuery{{
+					Expr:   fmt.Sprintf(`sum by (handler) (rate(http_requests_total{%[1]s, handler=~"query|query_range", code=~"5.."}[5m])) / sum by (handler) (rate(http_requests_total{%[1]s, handler=~"query|query_range"}[5m]))`, selector),
+					Legend: "{{handler}}",
+				}},
+			},
+		},
+	}
+
+	groups := []monitoringv1.RuleGroup{{
+		Name: "thanos-query",
+		Rules: []monitoringv1.Rule{
+			queryLatencyAlert(opts, "ThanosQueryInstantLatencyHigh", "instant", queryLatency(selector, "query"), instantQueryLatencyThreshold),
+			queryLatencyAlert(opts, "ThanosQueryRangeLatencyHigh", "range", queryLatency(selector, "query_range"), rangeQueryLatencyThreshold),
+		},
+	}}
+
+	return []client.Object{
+		manifests.BuildDashboardConfigMap(name, opts.Namespace, labels, dashboard),
+		manifests.BuildPrometheusRule(name, opts.Namespace, labels, groups),
+	}
+}
+
+func queryLatency(selector, handler string) string {
+	return fmt.Sprintf(`histogram_quantile(0.99, sum by (le) (rate(http_request_duration_seconds_bucket{%s, handler="%s"}[5m])))`, selector, handler)
+}
+
+func queryLatencyAlert(opts Options, alert, kind, latency string, threshold int) monitoringv1.Rule {
+	return monitoringv1.Rule{
+		Alert: alert,
+		Expr:  intstr.FromString(fmt.Sprintf(`%s > %d`, latency, threshold)),
+		For:   ptr.To(monitoringv1.Duration("10m")),
+		Labels: map[string]string{
+			"severity": "critical",
+		},
+		Annotations: map[string]string{
+			"summary":     fmt.Sprintf("Thanos Query has a high latency for %s queries.", kind),
+			"description": fmt.Sprintf("The p99 latency of the %s queries of %s in namespace %s is {{ $value }} seconds.", kind, opts.Owner, opts.Namespace),
+		},
+	}
+}
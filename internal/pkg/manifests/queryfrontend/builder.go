@@ -40,6 +40,48 @@ type Options struct {
 	RangeMaxRetries        int
 	LabelsMaxRetries       int
 	LabelsDefaultTimeRange manifests.Duration
+	// RangeMaxQueryParallelism is the maximum number of split query range requests scheduled in parallel.
+	// 0 leaves the Thanos default in place.
+	RangeMaxQueryParallelism int32
+	// LabelsMaxQueryParallelism is the maximum number of split label requests scheduled in parallel.
+	// 0 leaves the Thanos default in place.
+	LabelsMaxQueryParallelism int32
+	// RangeMaxQueryLength limits the time range of range queries. Empty leaves range queries unlimited.
+	RangeMaxQueryLength manifests.Duration
+	DownstreamConfig    *DownstreamTripperConfig
+	// SessionAffinityTimeoutSeconds enables client IP session affinity on the Service with the given timeout.
+	// No session affinity is configured if zero.
+	SessionAffinityTimeoutSeconds int32
+	// Service configures how the Service is exposed. The Service is a ClusterIP Service if nil.
+	Service *manifests.ServiceOptions
+	// Ingress exposes the query UI and API outside of the cluster. Not built if nil.
+	Ingress *manifests.IngressOptions
+}
+
+// DownstreamTripperConfig is the configuration of the HTTP round tripper used to reach the Queriers.
+type DownstreamTripperConfig struct {
+	ResponseHeaderTimeout manifests.Duration
+	IdleConnTimeout       manifests.Duration
+	MaxConnsPerHost       *int32
+	MaxIdleConnsPerHost   *int32
+}
+
+// String renders the configuration in the format expected by --query-frontend.downstream-tripper-config.
+func (dc DownstreamTripperConfig) String() string {
+	var base string
+	if dc.IdleConnTimeout != "" {
+		base += fmt.Sprintf("idle_conn_timeout: %s\n", dc.IdleConnTimeout)
+	}
+	if dc.ResponseHeaderTimeout != "" {
+		base += fmt.Sprintf("response_header_timeout: %s\n", dc.ResponseHeaderTimeout)
+	}
+	if dc.MaxIdleConnsPerHost != nil {
+		base += fmt.Sprintf("max_idle_conns_per_host: %d\n", *dc.MaxIdleConnsPerHost)
+	}
+	if dc.MaxConnsPerHost != nil {
+		base += fmt.Sprintf("max_conns_per_host: %d\n", *dc.MaxConnsPerHost)
+	}
+	return base
 }
 
 func (opts Options) Build() []client.Object {
@@ -48,14 +90,22 @@ func (opts Options) Build() []client.Object {
 	objectMetaLabels := GetLabels(opts)
 	name := opts.GetGeneratedResourceName()
 
-	objs = append(objs, manifests.BuildServiceAccount(opts.GetGeneratedResourceName(), opts.Namespace, selectorLabels, opts.Annotations))
+	objs = append(objs, manifests.BuildServiceAccount(opts.GetGeneratedResourceName(), opts.Namespace, selectorLabels, manifests.MergeMaps(opts.Annotations, opts.ServiceAccountAnnotations)))
 	objs = append(objs, newQueryFrontendDeployment(opts, selectorLabels, objectMetaLabels))
 	objs = append(objs, newQueryFrontendService(opts, selectorLabels, objectMetaLabels))
 
+	if opts.Ingress != nil {
+		objs = append(objs, manifests.BuildIngress(name, opts.Namespace, objectMetaLabels, *opts.Ingress, name, opts.Service.PortOr(HTTPPortName, HTTPPort)))
+	}
+
 	if opts.PodDisruptionConfig != nil {
 		objs = append(objs, manifests.NewPodDisruptionBudget(name, opts.Namespace, selectorLabels, objectMetaLabels, opts.Annotations, *opts.PodDisruptionConfig))
 	}
 
+	if opts.EnableMetricsService {
+		objs = append(objs, manifests.BuildMetricsService(name, opts.Namespace, objectMetaLabels, selectorLabels, opts.Annotations, HTTPPort))
+	}
+
 	return objs
 }
 
@@ -67,8 +117,7 @@ func (opts Options) Valid() error {
 }
 
 func (opts Options) GetGeneratedResourceName() string {
-	name := fmt.Sprintf("%s-%s", Name, opts.getOwner())
-	return manifests.ValidateAndSanitizeResourceName(name)
+	return manifests.ValidateAndSanitizeResourceName(manifests.GeneratedName(Name, opts.getOwner()))
 }
 
 func (opts Options) getOwner() string {
@@ -139,6 +188,9 @@ func newQueryFrontendDeployment(opts Options, selectorLabels, objectMetaLabels m
 										"ALL",
 									},
 								},
+								SeccompProfile: &corev1.SeccompProfile{
+									Type: corev1.SeccompProfileTypeRuntimeDefault,
+								},
 							},
 							LivenessProbe: &corev1.Probe{
 								ProbeHandler: corev1.ProbeHandler{
@@ -156,6 +208,7 @@ func newQueryFrontendDeployment(opts Options, selectorLabels, objectMetaLabels m
 									},
 								},
 							},
+							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
 						},
 					},
 				},
@@ -182,7 +235,7 @@ func newQueryFrontendService(opts Options, selectorLabels, objectMetaLabels map[
 			Name:        opts.GetGeneratedResourceName(),
 			Namespace:   opts.Namespace,
 			Labels:      objectMetaLabels,
-			Annotations: opts.Annotations,
+			Annotations: opts.GetServiceAnnotations(),
 		},
 		Spec: corev1.ServiceSpec{
 			Ports: []corev1.ServicePort{
@@ -201,6 +254,14 @@ func newQueryFrontendService(opts Options, selectorLabels, objectMetaLabels map[
 		service.Spec.Ports = append(service.Spec.Ports, opts.Additional.ServicePorts...)
 	}
 
+	if opts.SessionAffinityTimeoutSeconds > 0 {
+		service.Spec.SessionAffinity = corev1.ServiceAffinityClientIP
+		service.Spec.SessionAffinityConfig = &corev1.SessionAffinityConfig{
+			ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: ptr.To(opts.SessionAffinityTimeoutSeconds)},
+		}
+	}
+
+	manifests.ApplyServiceOptions(service, opts.Service)
 	return service
 }
 
@@ -208,7 +269,7 @@ func queryFrontendArgs(opts Options) []string {
 	args := []string{
 		"query-frontend",
 		fmt.Sprintf("--http-address=0.0.0.0:%d", HTTPPort),
-		fmt.Sprintf("--query-frontend.downstream-url=http://%s.%s.svc:%d", opts.QueryService, opts.Namespace, opts.QueryPort),
+		fmt.Sprintf("--query-frontend.downstream-url=http://%s:%d", manifests.ServiceHost(opts.QueryService, opts.Namespace), opts.QueryPort),
 		fmt.Sprintf("--query-frontend.log-queries-longer-than=%s", opts.LogQueriesLongerThan),
 		fmt.Sprintf("--query-range.split-interval=%s", opts.RangeSplitInterval),
 		fmt.Sprintf("--labels.split-interval=%s", opts.LabelsSplitInterval),
@@ -225,12 +286,34 @@ func queryFrontendArgs(opts Options) []string {
 		conf := opts.ResponseCacheConfig.InMemoryCacheConfig.String()
 		args = append(args, fmt.Sprintf("--query-range.response-cache-config=%s", conf))
 		args = append(args, fmt.Sprintf("--labels.response-cache-config=%s", conf))
+	} else if opts.ResponseCacheConfig.Memcached != nil {
+		conf := opts.ResponseCacheConfig.Memcached.String()
+		args = append(args, fmt.Sprintf("--query-range.response-cache-config=%s", conf))
+		args = append(args, fmt.Sprintf("--labels.response-cache-config=%s", conf))
 	}
 
 	if opts.CompressResponses {
 		args = append(args, "--query-frontend.compress-responses")
 	}
 
+	if opts.RangeMaxQueryParallelism > 0 {
+		args = append(args, fmt.Sprintf("--query-range.max-query-parallelism=%d", opts.RangeMaxQueryParallelism))
+	}
+
+	if opts.RangeMaxQueryLength != "" {
+		args = append(args, fmt.Sprintf("--query-range.max-query-length=%s", opts.RangeMaxQueryLength))
+	}
+
+	if opts.LabelsMaxQueryParallelism > 0 {
+		args = append(args, fmt.Sprintf("--labels.max-query-parallelism=%d", opts.LabelsMaxQueryParallelism))
+	}
+
+	if opts.DownstreamConfig != nil {
+		if conf := opts.DownstreamConfig.String(); conf != "" {
+			args = append(args, fmt.Sprintf("--query-frontend.downstream-tripper-config=%s", conf))
+		}
+	}
+
 	return manifests.PruneEmptyArgs(args)
 }
 
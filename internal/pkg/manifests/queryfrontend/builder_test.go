@@ -214,6 +214,33 @@ func TestNewQueryFrontendDeployment(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:   "test with downstream limits",
+			golden: "deployment-with-downstream-limits.golden.yaml",
+			opts: Options{
+				Options: manifests.Options{
+					Owner:     "test-qf",
+					Namespace: "ns",
+					Image:     ptr.To("some-custom-image"),
+					Replicas:  2,
+				},
+				QueryService:              "thanos-query",
+				LogQueriesLongerThan:      "5s",
+				RangeSplitInterval:        "1h",
+				LabelsSplitInterval:       "30m",
+				RangeMaxRetries:           2,
+				LabelsMaxRetries:          2,
+				RangeMaxQueryParallelism:  8,
+				RangeMaxQueryLength:       "30d",
+				LabelsMaxQueryParallelism: 4,
+				DownstreamConfig: &DownstreamTripperConfig{
+					ResponseHeaderTimeout: "30s",
+					IdleConnTimeout:       "90s",
+					MaxConnsPerHost:       ptr.To(int32(100)),
+					MaxIdleConnsPerHost:   ptr.To(int32(50)),
+				},
+			},
+		},
 		{
 			name:   "test with otel sidecar enabled",
 			golden: "deployment-with-otel-sidecar.golden.yaml",
@@ -285,6 +312,15 @@ func TestNewQueryFrontendService(t *testing.T) {
 			golden: "service-basic.golden.yaml",
 			opts:   opts,
 		},
+		{
+			name:   "test query frontend service with session affinity",
+			golden: "service-session-affinity.golden.yaml",
+			opts: func() Options {
+				o := opts
+				o.SessionAffinityTimeoutSeconds = 3600
+				return o
+			}(),
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			service := NewQueryFrontendService(tc.opts)
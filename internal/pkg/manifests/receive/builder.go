@@ -1,7 +1,15 @@
 package receive
 
 import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
 	manifestsstore "github.com/thanos-community/thanos-operator/internal/pkg/manifests/store"
@@ -42,12 +50,60 @@ const (
 	// RemoteWritePort is the port number for the remote write port for the Thanos Receive components.
 	RemoteWritePort = 19291
 
+	// CapnProtoMinVersion is the first Thanos version supporting the capnproto replication protocol.
+	CapnProtoMinVersion = "v0.35.0"
+
 	// HashringConfigKey is the key in the ConfigMap for the hashring configuration.
 	HashringConfigKey = "hashrings.json"
 	// EmptyHashringConfig is the empty hashring configuration.
 	EmptyHashringConfig = "[{}]"
+	// HashringConfigHashAnnotation is the pod template annotation used to roll the routers out
+	// when the hashring configuration changes.
+	HashringConfigHashAnnotation = "operator.thanos.io/hashring-config-hash"
+	// HashringConfigVersionAnnotation records the version of the hashring configuration on its ConfigMap.
+	HashringConfigVersionAnnotation = "operator.thanos.io/hashring-config-version"
+	// HashringConfigUpdatedAnnotation records when the hashring configuration last changed, in RFC 3339 format.
+	HashringConfigUpdatedAnnotation = "operator.thanos.io/hashring-config-updated"
+
+	// ReplicaExternalLabel is the external label identifying the ingester replica that ingested a series.
+	// It is added to the ingesters unless it is set explicitly or one of their external labels is already
+	// derived from the pod name.
+	ReplicaExternalLabel = "receive_replica"
+	// HashringExternalLabel is the external label identifying the hashring of the ingester that ingested a series.
+	// It is added to the ingesters unless it is set explicitly or the hashring name is empty.
+	HashringExternalLabel = "receive_hashring"
+	// PodNameReference is the reference to the pod name that ingester external labels can use,
+	// which makes the label differ between the replicas of a series.
+	PodNameReference = "$(POD_NAME)"
 )
 
+// HashringConfigHash returns the hash of a hashring configuration.
+func HashringConfigHash(config string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(config)))
+}
+
+// IsReplicaLabelValue returns true if an ingester external label with the given value differs between ingester replicas.
+func IsReplicaLabelValue(value string) bool {
+	return strings.Contains(value, PodNameReference)
+}
+
+// IngesterExternalLabels returns the external labels of the ingesters of a hashring, which are the given labels
+// with the ReplicaExternalLabel and HashringExternalLabel labels added, so that the blocks and series of each
+// ingester replica are uniquely labeled.
+func IngesterExternalLabels(hashring string, labels map[string]string) map[string]string {
+	out := maps.Clone(labels)
+	if out == nil {
+		out = map[string]string{}
+	}
+	if _, ok := out[ReplicaExternalLabel]; !ok && !slices.ContainsFunc(slices.Collect(maps.Values(labels)), IsReplicaLabelValue) {
+		out[ReplicaExternalLabel] = PodNameReference
+	}
+	if _, ok := out[HashringExternalLabel]; !ok && hashring != "" {
+		out[HashringExternalLabel] = hashring
+	}
+	return out
+}
+
 // IngesterOptions for Thanos Receive components
 type IngesterOptions struct {
 	manifests.Options
@@ -56,17 +112,62 @@ type IngesterOptions struct {
 	StoreLimitsOpts manifests.StoreLimitsOpts
 	StorageConfig   manifests.StorageConfig
 	ObjStoreSecret  corev1.SecretKeySelector
-	ExternalLabels  map[string]string
+	ObjStoreConfig  manifests.ObjStoreConfig
+	// ExternalLabels of the ingesters, to which the labels returned by IngesterExternalLabels are added.
+	ExternalLabels map[string]string
 	// HashringName is the name of the hashring and is a required field.
 	HashringName             string
 	AsyncForwardWorkerCount  string
 	TooFarInFutureTimeWindow manifests.Duration
 	ReplicationProtocol      string
 	GRPCCompression          string
+	// GRPCPort is the port the ingesters serve gRPC on. Defaults to GRPCPort if zero.
+	GRPCPort int32
+	// CapnProtoPort is the port the ingesters serve Cap'n Proto replication on. Defaults to CapnProtoPort if zero.
+	CapnProtoPort int32
+	// ShutdownDrainSeconds delays the termination of the ingester so that it keeps serving
+	// while the routers stop forwarding to it. No delay is added if zero.
+	ShutdownDrainSeconds int64
+	// UploadConcurrency is the number of goroutines used to upload the files of a block. Not set if zero.
+	UploadConcurrency int32
+	// ServiceAccountName is the name of an existing ServiceAccount used by the ingesters.
+	// A ServiceAccount is created for the ingesters if empty.
+	ServiceAccountName string
+	// GRPCTLS is the TLS configuration for the gRPC server.
+	// If not set, the gRPC server is served without TLS.
+	GRPCTLS *manifests.TLSConfig
+	// SpreadAcrossZones requires the ingesters to be spread evenly across zones.
+	SpreadAcrossZones bool
+	// NetworkPolicy restricts the traffic of the ingesters. Not built if nil.
+	NetworkPolicy *NetworkPolicyOptions
 }
 
 type TSDBOpts struct {
 	Retention string
+	// OutOfOrderTimeWindow is how far in the past out of order samples are accepted. Not set if empty.
+	OutOfOrderTimeWindow manifests.Duration
+	// WALCompression enables or disables the compression of the write ahead log. Not set if nil.
+	WALCompression *bool
+	// MinBlockDuration and MaxBlockDuration bound the duration of the TSDB blocks. Not set if empty.
+	MinBlockDuration manifests.Duration
+	MaxBlockDuration manifests.Duration
+}
+
+// tsdbArgsFrom returns the TSDB tuning flags, leaving out those that are not set.
+func tsdbArgsFrom(o TSDBOpts) []string {
+	flags := []string{
+		fmt.Sprintf("--tsdb.out-of-order.time-window=%s", o.OutOfOrderTimeWindow),
+		fmt.Sprintf("--tsdb.min-block-duration=%s", o.MinBlockDuration),
+		fmt.Sprintf("--tsdb.max-block-duration=%s", o.MaxBlockDuration),
+	}
+	if o.WALCompression != nil {
+		if *o.WALCompression {
+			flags = append(flags, "--tsdb.wal-compression")
+		} else {
+			flags = append(flags, "--no-tsdb.wal-compression")
+		}
+	}
+	return manifests.PruneEmptyArgs(flags)
 }
 
 type TenancyOpts struct {
@@ -89,7 +190,58 @@ type RouterOptions struct {
 	ExternalLabels      map[string]string
 	HashringConfig      string
 	ReplicationProtocol string
-	FeatureGateConfig   *FeatureGateConfig
+	// HashringConfigVersion is the version of the HashringConfig, recorded on the hashring ConfigMap together with
+	// HashringConfigUpdateTime. Neither is recorded if zero.
+	HashringConfigVersion    int64
+	HashringConfigUpdateTime time.Time
+	// AsyncForwardWorkerCount is the number of workers forwarding remote write requests. The Thanos default is used if zero.
+	AsyncForwardWorkerCount uint64
+	// ReplicationMaxRetries is the number of times a replication request to an unavailable ingester is retried.
+	// Retries are disabled if zero.
+	ReplicationMaxRetries int32
+	FeatureGateConfig     *FeatureGateConfig
+	// RemoteWriteTLS is the TLS configuration for the remote write server.
+	// If not set, remote write is served over plain HTTP.
+	RemoteWriteTLS *manifests.TLSConfig
+	// GRPCClientTLS is the TLS configuration for the connections to the ingesters.
+	// If not set, the router connects to the ingesters without TLS.
+	GRPCClientTLS *manifests.GRPCClientTLSConfig
+	// ServiceTraffic configures how in-cluster traffic is routed by the router Service.
+	ServiceTraffic *ServiceTrafficOptions
+	// Limits are the write limits enforced by the router. No limits are configured if nil.
+	Limits *LimitsOptions
+	// Service configures how the router Service is exposed. The Service is a ClusterIP Service if nil.
+	Service *manifests.ServiceOptions
+	// ExistingServiceName is the name of an existing Service exposing the routers.
+	// The router Service is not built if set.
+	ExistingServiceName string
+	// ExistingHashringConfigMapName is the name of an existing ConfigMap holding the hashring configuration.
+	// The routers read their hashrings from it and the hashring ConfigMap is not built if set.
+	ExistingHashringConfigMapName string
+	// Ingress exposes the remote write endpoint outside of the cluster. Not built if nil.
+	Ingress *manifests.IngressOptions
+	// NetworkPolicy restricts the traffic of the routers. Not built if nil.
+	NetworkPolicy *NetworkPolicyOptions
+	// RelabelConfigs are the relabeling rules applied to the series of remote write requests. None if empty.
+	RelabelConfigs RelabelConfigsOptions
+	// Tenancy configures how the router determines the tenant of remote write requests.
+	// The Thanos defaults are used for the fields that are empty.
+	Tenancy TenancyOpts
+	// RolloutOnHashringChange records the hash of the HashringConfig on the pod template of the routers,
+	// so that they are rolled out whenever it changes. Ignored with an ExistingHashringConfigMapName.
+	RolloutOnHashringChange bool
+	// Group is the name of a group of routers of the owner. The routers of a group share the hashring ConfigMap
+	// and the limits ConfigMap of the routers of the owner, which are not built for the group, nor are the
+	// Ingress and the monitoring mixin.
+	Group string
+}
+
+// ServiceTrafficOptions configures how in-cluster traffic is routed by a Service.
+type ServiceTrafficOptions struct {
+	// TopologyMode is the value of the topology aware routing annotation. Not set if empty.
+	TopologyMode string
+	// InternalTrafficPolicy is the internal traffic policy of the Service.
+	InternalTrafficPolicy *corev1.ServiceInternalTrafficPolicy
 }
 
 // Build builds the ingester for Thanos Receive
@@ -99,17 +251,26 @@ func (opts IngesterOptions) Build() []client.Object {
 	objectMetaLabels := GetIngesterLabels(opts)
 	name := opts.GetGeneratedResourceName()
 
-	objs = append(objs, manifests.BuildServiceAccount(name, opts.Namespace, selectorLabels, opts.Annotations))
+	if opts.ServiceAccountName == "" {
+		objs = append(objs, manifests.BuildServiceAccount(name, opts.Namespace, selectorLabels, manifests.MergeMaps(opts.Annotations, opts.ServiceAccountAnnotations)))
+	}
 	objs = append(objs, newIngestorService(opts, selectorLabels, objectMetaLabels))
 	objs = append(objs, newIngestorStatefulSet(opts, selectorLabels, objectMetaLabels))
+	if opts.NetworkPolicy != nil {
+		objs = append(objs, newIngesterNetworkPolicy(opts, selectorLabels, objectMetaLabels))
+	}
 
 	if opts.PodDisruptionConfig != nil {
 		objs = append(objs, manifests.NewPodDisruptionBudget(name, opts.Namespace, selectorLabels, objectMetaLabels, opts.Annotations, *opts.PodDisruptionConfig))
 	}
 
+	if opts.EnableMetricsService {
+		objs = append(objs, manifests.BuildMetricsService(name, opts.Namespace, objectMetaLabels, selectorLabels, opts.Annotations, HTTPPort))
+	}
+
 	if opts.ServiceMonitorConfig != nil {
 		smLabels := manifests.MergeMaps(opts.ServiceMonitorConfig.Labels, objectMetaLabels)
-		objs = append(objs, manifests.BuildServiceMonitor(name, opts.Namespace, selectorLabels, smLabels, serviceMonitorOpts(opts.ServiceMonitorConfig)))
+		objs = append(objs, manifests.BuildServiceMonitor(name, opts.Namespace, selectorLabels, smLabels, serviceMonitorOpts(opts.ServiceMonitorConfig, opts.EnableMetricsService)))
 	}
 	return objs
 }
@@ -124,9 +285,17 @@ func (opts IngesterOptions) Valid() error {
 	return nil
 }
 
+func (opts IngesterOptions) grpcPort() int32 {
+	return cmp.Or(opts.GRPCPort, GRPCPort)
+}
+
+func (opts IngesterOptions) capnProtoPort() int32 {
+	return cmp.Or(opts.CapnProtoPort, CapnProtoPort)
+}
+
 func (opts IngesterOptions) GetGeneratedResourceName() string {
-	name := fmt.Sprintf("%s-%s-%s", IngestComponentName, opts.Owner, opts.HashringName)
-	return manifests.ValidateAndSanitizeResourceName(name)
+	name := manifests.GeneratedName(IngestComponentName, opts.Owner, opts.HashringName)
+	return manifests.ValidateAndSanitizeResourceNameToLength(name, manifests.StatefulSetNameMaxLength)
 }
 
 // Build builds the Thanos Receive router components
@@ -136,10 +305,23 @@ func (opts RouterOptions) Build() []client.Object {
 	objectMetaLabels := GetRouterLabels(opts)
 	name := opts.GetGeneratedResourceName()
 
-	objs = append(objs, manifests.BuildServiceAccount(name, opts.Namespace, selectorLabels, opts.Annotations))
-	objs = append(objs, newRouterService(opts, selectorLabels, objectMetaLabels))
+	objs = append(objs, manifests.BuildServiceAccount(name, opts.Namespace, selectorLabels, manifests.MergeMaps(opts.Annotations, opts.ServiceAccountAnnotations)))
+	if opts.ExistingServiceName == "" {
+		objs = append(objs, newRouterService(opts, selectorLabels, objectMetaLabels))
+	}
 	objs = append(objs, newRouterDeployment(opts, selectorLabels, objectMetaLabels))
-	objs = append(objs, newHashringConfigMap(name, opts.Namespace, opts.HashringConfig, objectMetaLabels))
+	if opts.ExistingHashringConfigMapName == "" && opts.Group == "" {
+		objs = append(objs, newHashringConfigMap(name, opts.Namespace, opts.HashringConfig, objectMetaLabels, hashringConfigAnnotations(opts)))
+	}
+	if opts.Ingress != nil && opts.Group == "" {
+		objs = append(objs, newRouterIngress(opts, objectMetaLabels))
+	}
+	if opts.NetworkPolicy != nil {
+		objs = append(objs, newRouterNetworkPolicy(opts, selectorLabels, objectMetaLabels))
+	}
+	if opts.Limits != nil && opts.Group == "" {
+		objs = append(objs, newLimitsConfigMap(name, opts.Namespace, *opts.Limits, objectMetaLabels))
+	}
 
 	if opts.FeatureGateConfig != nil && opts.FeatureGateConfig.KubeResourceSyncEnabled {
 		objs = append(objs, newRouterRole(name, opts.Namespace, objectMetaLabels))
@@ -150,8 +332,12 @@ func (opts RouterOptions) Build() []client.Object {
 		objs = append(objs, manifests.NewPodDisruptionBudget(name, opts.Namespace, selectorLabels, objectMetaLabels, opts.Annotations, *opts.PodDisruptionConfig))
 	}
 
+	if opts.EnableMetricsService {
+		objs = append(objs, manifests.BuildMetricsService(name, opts.Namespace, objectMetaLabels, selectorLabels, opts.Annotations, HTTPPort))
+	}
+
 	if opts.ServiceMonitorConfig != nil {
-		objs = append(objs, manifests.BuildServiceMonitor(name, opts.Namespace, objectMetaLabels, selectorLabels, serviceMonitorOpts(opts.ServiceMonitorConfig)))
+		objs = append(objs, manifests.BuildServiceMonitor(name, opts.Namespace, objectMetaLabels, selectorLabels, serviceMonitorOpts(opts.ServiceMonitorConfig, opts.EnableMetricsService)))
 
 		// Add separate ServiceMonitor for kube-resource-sync metrics when enabled
 		if opts.FeatureGateConfig != nil && opts.FeatureGateConfig.KubeResourceSyncEnabled {
@@ -163,6 +349,10 @@ func (opts RouterOptions) Build() []client.Object {
 			objs = append(objs, manifests.BuildServiceMonitor(kubeResourceSyncSMName, opts.Namespace, objectMetaLabels, selectorLabels, kubeResourceSyncSMOpts))
 		}
 	}
+
+	if opts.MonitoringMixinConfig != nil && opts.Group == "" {
+		objs = append(objs, newRouterMixin(opts, objectMetaLabels)...)
+	}
 	return objs
 }
 
@@ -174,13 +364,41 @@ func (opts RouterOptions) Valid() error {
 }
 
 func (opts RouterOptions) GetGeneratedResourceName() string {
-	name := fmt.Sprintf("%s-%s", RouterComponentName, opts.Owner)
-	return manifests.ValidateAndSanitizeResourceName(name)
+	if opts.Group != "" {
+		return manifests.ValidateAndSanitizeResourceName(manifests.GeneratedName(RouterComponentName, opts.Owner, opts.Group))
+	}
+	return opts.sharedResourceName()
+}
+
+// sharedResourceName returns the name of the routers of the owner outside of any group,
+// which is also the name of the hashring and limits ConfigMaps shared by all routers of the owner.
+func (opts RouterOptions) sharedResourceName() string {
+	return manifests.ValidateAndSanitizeResourceName(manifests.GeneratedName(RouterComponentName, opts.Owner))
+}
+
+// newRouterIngress creates the Ingress or HTTPRoute routing remote writes to the router Service.
+// The existing Service is expected to expose the remote write port on its default port.
+func newRouterIngress(opts RouterOptions, objectMetaLabels map[string]string) client.Object {
+	name := opts.GetGeneratedResourceName()
+	if opts.ExistingServiceName != "" {
+		return manifests.BuildIngress(name, opts.Namespace, objectMetaLabels, *opts.Ingress, opts.ExistingServiceName, RemoteWritePort)
+	}
+	return manifests.BuildIngress(name, opts.Namespace, objectMetaLabels, *opts.Ingress, name, opts.Service.PortOr(RemoteWritePortName, RemoteWritePort))
+}
+
+// hashringConfigMapName returns the name of the ConfigMap the routers read their hashrings from.
+func (opts RouterOptions) hashringConfigMapName() string {
+	if opts.ExistingHashringConfigMapName != "" {
+		return opts.ExistingHashringConfigMapName
+	}
+	return opts.sharedResourceName()
 }
 
 const (
 	ingestObjectStoreEnvVarName = "OBJSTORE_CONFIG"
 
+	remoteWriteTLSServerName = "remote-write"
+
 	dataVolumeName      = "data"
 	dataVolumeMountPath = "/var/thanos/receive"
 )
@@ -255,6 +473,9 @@ func newIngestorStatefulSet(opts IngesterOptions, selectorLabels, objectMetaLabe
 										"ALL",
 									},
 								},
+								SeccompProfile: &corev1.SeccompProfile{
+									Type: corev1.SeccompProfileTypeRuntimeDefault,
+								},
 							},
 							ReadinessProbe: &corev1.Probe{
 								ProbeHandler: corev1.ProbeHandler{
@@ -282,7 +503,7 @@ func newIngestorStatefulSet(opts IngesterOptions, selectorLabels, objectMetaLabe
 								SuccessThreshold:    1,
 								FailureThreshold:    8,
 							},
-							Env: []corev1.EnvVar{
+							Env: append([]corev1.EnvVar{
 								{
 									Name: "POD_NAME",
 									ValueFrom: &corev1.EnvVarSource{
@@ -299,19 +520,7 @@ func newIngestorStatefulSet(opts IngesterOptions, selectorLabels, objectMetaLabe
 										},
 									},
 								},
-								{
-									Name: ingestObjectStoreEnvVarName,
-									ValueFrom: &corev1.EnvVarSource{
-										SecretKeyRef: &corev1.SecretKeySelector{
-											LocalObjectReference: corev1.LocalObjectReference{
-												Name: opts.ObjStoreSecret.Name,
-											},
-											Key:      opts.ObjStoreSecret.Key,
-											Optional: ptr.To(false),
-										},
-									},
-								},
-							},
+							}, opts.ObjStoreConfig.EnvVars(ingestObjectStoreEnvVarName, opts.ObjStoreSecret)...),
 							VolumeMounts: []corev1.VolumeMount{
 								{
 									Name:      dataVolumeName,
@@ -320,11 +529,11 @@ func newIngestorStatefulSet(opts IngesterOptions, selectorLabels, objectMetaLabe
 							},
 							Ports: []corev1.ContainerPort{
 								{
-									ContainerPort: GRPCPort,
+									ContainerPort: opts.grpcPort(),
 									Name:          GRPCPortName,
 								},
 								{
-									ContainerPort: CapnProtoPort,
+									ContainerPort: opts.capnProtoPort(),
 									Name:          CapnProtoPortName,
 								},
 								{
@@ -345,14 +554,42 @@ func newIngestorStatefulSet(opts IngesterOptions, selectorLabels, objectMetaLabe
 			},
 		},
 	}
+	manifests.MountObjStore(&sts.Spec.Template, opts.ObjStoreSecret, opts.ObjStoreConfig)
+	if opts.GRPCTLS != nil {
+		manifests.MountTLS(&sts.Spec.Template, manifests.GRPCTLSServerName, *opts.GRPCTLS)
+	}
+	if opts.ServiceAccountName != "" {
+		sts.Spec.Template.Spec.ServiceAccountName = opts.ServiceAccountName
+	}
+	if opts.ShutdownDrainSeconds > 0 {
+		sts.Spec.Template.Spec.Containers[0].Lifecycle = &corev1.Lifecycle{
+			PreStop: &corev1.LifecycleHandler{
+				Sleep: &corev1.SleepAction{Seconds: opts.ShutdownDrainSeconds},
+			},
+		}
+	}
 	manifests.AugmentWithOptions(sts, opts.Options)
+	if opts.SpreadAcrossZones {
+		sts.Spec.Template.Spec.TopologySpreadConstraints = append(sts.Spec.Template.Spec.TopologySpreadConstraints, corev1.TopologySpreadConstraint{
+			MaxSkew:           1,
+			TopologyKey:       corev1.LabelTopologyZone,
+			WhenUnsatisfiable: corev1.DoNotSchedule,
+			LabelSelector:     &metav1.LabelSelector{MatchLabels: selectorLabels},
+		})
+	}
 	return sts
 }
 
+// DataVolumeClaimName returns the name of the PersistentVolumeClaim of the data volume of the ingester
+// with the given ordinal in the StatefulSet.
+func DataVolumeClaimName(statefulSetName string, ordinal int32) string {
+	return fmt.Sprintf("%s-%s-%d", dataVolumeName, statefulSetName, ordinal)
+}
+
 // NewIngestorService creates a new Service for the Thanos Receive ingester.
 func NewIngestorService(opts IngesterOptions) *corev1.Service {
 	selectorLabels := opts.GetSelectorLabels()
-	svc := newService(opts.GetGeneratedResourceName(), opts.Namespace, selectorLabels, manifests.MergeMaps(opts.Labels, selectorLabels), opts.Annotations)
+	svc := newService(opts.GetGeneratedResourceName(), opts.Namespace, opts.grpcPort(), opts.capnProtoPort(), selectorLabels, ingesterServiceLabels(opts, manifests.MergeMaps(opts.Labels, selectorLabels)), opts.GetServiceAnnotations())
 	svc.Spec.ClusterIP = corev1.ClusterIPNone
 
 	if opts.Additional.ServicePorts != nil {
@@ -363,7 +600,7 @@ func NewIngestorService(opts IngesterOptions) *corev1.Service {
 }
 
 func newIngestorService(opts IngesterOptions, selectorLabels, objectMetaLabels map[string]string) *corev1.Service {
-	svc := newService(opts.GetGeneratedResourceName(), opts.Namespace, selectorLabels, objectMetaLabels, opts.Annotations)
+	svc := newService(opts.GetGeneratedResourceName(), opts.Namespace, opts.grpcPort(), opts.capnProtoPort(), selectorLabels, ingesterServiceLabels(opts, objectMetaLabels), opts.GetServiceAnnotations())
 	svc.Spec.ClusterIP = corev1.ClusterIPNone
 
 	if opts.Additional.ServicePorts != nil {
@@ -373,6 +610,15 @@ func newIngestorService(opts IngesterOptions, selectorLabels, objectMetaLabels m
 	return svc
 }
 
+// ingesterServiceLabels returns the labels of the ingester Service.
+// Services of ingesters serving gRPC over TLS are labeled so that queriers connect to them over TLS.
+func ingesterServiceLabels(opts IngesterOptions, objectMetaLabels map[string]string) map[string]string {
+	if opts.GRPCTLS == nil {
+		return objectMetaLabels
+	}
+	return manifests.MergeMaps(objectMetaLabels, map[string]string{manifests.GRPCTLSLabel: manifests.GRPCTLSLabelValue})
+}
+
 // NewRouterService creates a new Service for the Thanos Receive router.
 func NewRouterService(opts RouterOptions) *corev1.Service {
 	selectorLabels := opts.GetSelectorLabels()
@@ -381,7 +627,7 @@ func NewRouterService(opts RouterOptions) *corev1.Service {
 }
 
 func newRouterService(opts RouterOptions, selectorLabels, objectMetaLabels map[string]string) *corev1.Service {
-	svc := newService(opts.GetGeneratedResourceName(), opts.Namespace, selectorLabels, objectMetaLabels, opts.Annotations)
+	svc := newService(opts.GetGeneratedResourceName(), opts.Namespace, GRPCPort, CapnProtoPort, selectorLabels, objectMetaLabels, opts.GetServiceAnnotations())
 
 	// Add kube-resource-sync metrics port when enabled
 	if opts.FeatureGateConfig != nil && opts.FeatureGateConfig.KubeResourceSyncEnabled {
@@ -397,22 +643,30 @@ func newRouterService(opts RouterOptions, selectorLabels, objectMetaLabels map[s
 	if opts.Additional.ServicePorts != nil {
 		svc.Spec.Ports = append(svc.Spec.Ports, opts.Additional.ServicePorts...)
 	}
+
+	if opts.ServiceTraffic != nil {
+		if opts.ServiceTraffic.TopologyMode != "" {
+			svc.Annotations = manifests.MergeMaps(svc.Annotations, map[string]string{corev1.AnnotationTopologyMode: opts.ServiceTraffic.TopologyMode})
+		}
+		svc.Spec.InternalTrafficPolicy = opts.ServiceTraffic.InternalTrafficPolicy
+	}
+	manifests.ApplyServiceOptions(svc, opts.Service)
 	return svc
 }
 
 // newService creates a new Service for the Thanos Receive components.
-func newService(name, namespace string, selectorLabels, objectMetaLabels map[string]string, annotations map[string]string) *corev1.Service {
+func newService(name, namespace string, grpcPort, capnProtoPort int32, selectorLabels, objectMetaLabels map[string]string, annotations map[string]string) *corev1.Service {
 	servicePorts := []corev1.ServicePort{
 		{
 			Name:       GRPCPortName,
-			Port:       GRPCPort,
-			TargetPort: intstr.FromInt32(GRPCPort),
+			Port:       grpcPort,
+			TargetPort: intstr.FromInt32(grpcPort),
 			Protocol:   "TCP",
 		},
 		{
 			Name:       CapnProtoPortName,
-			Port:       CapnProtoPort,
-			TargetPort: intstr.FromInt32(CapnProtoPort),
+			Port:       capnProtoPort,
+			TargetPort: intstr.FromInt32(capnProtoPort),
 			Protocol:   "TCP",
 		},
 		{
@@ -463,7 +717,7 @@ func NewRouterDeployment(opts RouterOptions) *appsv1.Deployment {
 
 func newRouterDeployment(opts RouterOptions, selectorLabels, objectMetaLabels map[string]string) *appsv1.Deployment {
 	name := opts.GetGeneratedResourceName()
-	volumes := buildRouterVolumes(opts, name)
+	volumes := buildRouterVolumes(opts, opts.hashringConfigMapName())
 	containers := buildRouterContainers(opts)
 	initContainers := buildRouterInitContainers(opts)
 
@@ -479,7 +733,7 @@ func newRouterDeployment(opts RouterOptions, selectorLabels, objectMetaLabels ma
 			Annotations: opts.Annotations,
 		},
 		Spec: appsv1.DeploymentSpec{
-			Replicas: ptr.To(opts.Replicas),
+			Replicas: opts.DeploymentReplicas(),
 			Selector: &metav1.LabelSelector{
 				MatchLabels: selectorLabels,
 			},
@@ -508,6 +762,19 @@ func newRouterDeployment(opts RouterOptions, selectorLabels, objectMetaLabels ma
 			RevisionHistoryLimit: ptr.To(int32(10)),
 		},
 	}
+	if opts.RemoteWriteTLS != nil {
+		manifests.MountTLS(&deployment.Spec.Template, remoteWriteTLSServerName, *opts.RemoteWriteTLS)
+	}
+	if opts.GRPCClientTLS != nil {
+		manifests.MountGRPCClientTLS(&deployment.Spec.Template, *opts.GRPCClientTLS)
+	}
+	if opts.Limits != nil {
+		mountLimits(&deployment.Spec.Template, opts.sharedResourceName())
+	}
+	if opts.RolloutOnHashringChange && opts.ExistingHashringConfigMapName == "" {
+		deployment.Spec.Template.Annotations = map[string]string{HashringConfigHashAnnotation: HashringConfigHash(opts.HashringConfig)}
+	}
+
 	manifests.AugmentWithOptions(deployment, opts.Options)
 	return deployment
 }
@@ -517,14 +784,14 @@ func ingestorArgsFrom(opts IngesterOptions) []string {
 	args = append(args, opts.ToFlags()...)
 
 	args = append(args,
-		fmt.Sprintf("--grpc-address=0.0.0.0:%d", GRPCPort),
+		fmt.Sprintf("--grpc-address=0.0.0.0:%d", opts.grpcPort()),
 		fmt.Sprintf("--http-address=0.0.0.0:%d", HTTPPort),
 		fmt.Sprintf("--remote-write.address=0.0.0.0:%d", RemoteWritePort),
 		fmt.Sprintf("--tsdb.path=%s", dataVolumeMountPath),
 		fmt.Sprintf("--tsdb.retention=%s", opts.Retention),
-		fmt.Sprintf("--objstore.config=$(%s)", ingestObjectStoreEnvVarName),
-		fmt.Sprintf("--receive.local-endpoint=$(POD_NAME).%s.$(POD_NAMESPACE).svc:%d",
-			opts.GetGeneratedResourceName(), GRPCPort),
+		opts.ObjStoreConfig.Flag(ingestObjectStoreEnvVarName),
+		fmt.Sprintf("--receive.local-endpoint=%s:%d",
+			manifests.PodHost("$(POD_NAME)", opts.GetGeneratedResourceName(), "$(POD_NAMESPACE)"), opts.grpcPort()),
 		fmt.Sprintf("--receive.forward.async-workers=%s", opts.AsyncForwardWorkerCount),
 		fmt.Sprintf("--tsdb.too-far-in-future.time-window=%s", opts.TooFarInFutureTimeWindow),
 		fmt.Sprintf("--receive.tenant-header=%s", opts.TenancyOpts.TenantHeader),
@@ -535,13 +802,15 @@ func ingestorArgsFrom(opts IngesterOptions) []string {
 	)
 
 	args = append(args, opts.StoreLimitsOpts.ToFlags()...)
+	args = append(args, tsdbArgsFrom(opts.TSDBOpts)...)
 
-	for k, v := range opts.ExternalLabels {
-		args = append(args, fmt.Sprintf(`--label=%s="%s"`, k, v))
+	externalLabels := IngesterExternalLabels(opts.HashringName, opts.ExternalLabels)
+	for _, k := range slices.Sorted(maps.Keys(externalLabels)) {
+		args = append(args, fmt.Sprintf(`--label=%s="%s"`, k, externalLabels[k]))
 	}
 
 	if opts.ReplicationProtocol == "capnproto" {
-		args = append(args, fmt.Sprintf("--receive.capnproto-address=0.0.0.0:%d", CapnProtoPort))
+		args = append(args, fmt.Sprintf("--receive.capnproto-address=0.0.0.0:%d", opts.capnProtoPort()))
 	}
 
 	// Snappy is the default compression algorithm set on Thanos.
@@ -549,6 +818,14 @@ func ingestorArgsFrom(opts IngesterOptions) []string {
 		args = append(args, fmt.Sprintf("--receive.grpc-compression=%s", opts.GRPCCompression))
 	}
 
+	if opts.UploadConcurrency > 0 {
+		args = append(args, fmt.Sprintf("--shipper.upload-concurrency=%d", opts.UploadConcurrency))
+	}
+
+	if opts.GRPCTLS != nil {
+		args = append(args, manifests.GRPCServerTLSArgs(*opts.GRPCTLS)...)
+	}
+
 	// TODO(saswatamcode): Add some validation.
 	return manifests.PruneEmptyArgs(args)
 }
@@ -557,39 +834,92 @@ func routerArgsFrom(opts RouterOptions) []string {
 	args := []string{"receive"}
 	args = append(args, opts.ToFlags()...)
 
-	grpcDisableEndlessRetry := `{
-  "loadBalancingPolicy":"round_robin",
-  "retryPolicy": {
-    "maxAttempts": 2,
-    "initialBackoff": "0.1s",
-    "backoffMultiplier": 1,
-    "retryableStatusCodes": [
-  	  "UNAVAILABLE"
-    ]
-  }
-}`
-
 	args = append(args,
 		fmt.Sprintf("--grpc-address=0.0.0.0:%d", GRPCPort),
 		fmt.Sprintf("--http-address=0.0.0.0:%d", HTTPPort),
 		fmt.Sprintf("--remote-write.address=0.0.0.0:%d", RemoteWritePort),
 		fmt.Sprintf("--receive.replication-factor=%d", opts.ReplicationFactor),
 		fmt.Sprintf("--receive.hashrings-file=%s/%s", hashringMountPath, HashringConfigKey),
-		fmt.Sprintf("--receive.grpc-service-config=%s", grpcDisableEndlessRetry),
+		fmt.Sprintf("--receive.grpc-service-config=%s", grpcServiceConfig(opts.ReplicationMaxRetries)),
 	)
 	for k, v := range opts.ExternalLabels {
 		args = append(args, fmt.Sprintf(`--label=%s="%s"`, k, v))
 	}
 
+	args = append(args,
+		fmt.Sprintf("--receive.tenant-header=%s", opts.Tenancy.TenantHeader),
+		fmt.Sprintf("--receive.default-tenant-id=%s", opts.Tenancy.DefaultTenantID),
+		fmt.Sprintf("--receive.tenant-label-name=%s", opts.Tenancy.TenantLabelName),
+	)
+
 	if opts.ReplicationProtocol == "capnproto" {
 		args = append(args, fmt.Sprintf("--receive.replication-protocol=%s", opts.ReplicationProtocol))
 	}
 
+	if opts.AsyncForwardWorkerCount > 0 {
+		args = append(args, fmt.Sprintf("--receive.forward.async-workers=%d", opts.AsyncForwardWorkerCount))
+	}
+
+	if opts.RemoteWriteTLS != nil {
+		args = append(args,
+			fmt.Sprintf("--remote-write.server-tls-cert=%s", opts.RemoteWriteTLS.CertFile(remoteWriteTLSServerName)),
+			fmt.Sprintf("--remote-write.server-tls-key=%s", opts.RemoteWriteTLS.KeyFile(remoteWriteTLSServerName)),
+			fmt.Sprintf("--remote-write.server-tls-client-ca=%s", opts.RemoteWriteTLS.ClientCAFile(remoteWriteTLSServerName)),
+		)
+	}
+
+	if opts.GRPCClientTLS != nil {
+		args = append(args, manifests.GRPCClientTLSArgs("--remote-write.client", *opts.GRPCClientTLS)...)
+	}
+
+	if opts.Limits != nil {
+		args = append(args, fmt.Sprintf("--receive.limits-config-file=%s/%s", limitsMountPath, LimitsConfigKey))
+	}
+
+	if len(opts.RelabelConfigs) > 0 {
+		args = append(args, fmt.Sprintf("--receive.relabel-config=%s", opts.RelabelConfigs))
+	}
+
 	return manifests.PruneEmptyArgs(args)
 }
 
+// grpcServiceConfig returns the gRPC service configuration of the connections of the router to the ingesters.
+// Requests failing because an ingester is unavailable are retried maxRetries times, instead of endlessly.
+func grpcServiceConfig(maxRetries int32) string {
+	config := map[string]any{"loadBalancingPolicy": "round_robin"}
+	// gRPC requires at least two attempts for a retry policy
+	if maxRetries > 0 {
+		config["methodConfig"] = []map[string]any{{
+			"name": []map[string]any{{}},
+			"retryPolicy": map[string]any{
+				"maxAttempts":          maxRetries + 1,
+				"initialBackoff":       "0.1s",
+				"maxBackoff":           "1s",
+				"backoffMultiplier":    2,
+				"retryableStatusCodes": []string{"UNAVAILABLE"},
+			},
+		}}
+	}
+	out, err := json.Marshal(config)
+	if err != nil {
+		panic(fmt.Sprintf("failed to marshal gRPC service config: %v", err))
+	}
+	return string(out)
+}
+
+// hashringConfigAnnotations returns the annotations recording the version of the hashring configuration.
+func hashringConfigAnnotations(opts RouterOptions) map[string]string {
+	if opts.HashringConfigVersion == 0 {
+		return nil
+	}
+	return map[string]string{
+		HashringConfigVersionAnnotation: strconv.FormatInt(opts.HashringConfigVersion, 10),
+		HashringConfigUpdatedAnnotation: opts.HashringConfigUpdateTime.UTC().Format(time.RFC3339),
+	}
+}
+
 // newHashringConfigMap creates a skeleton ConfigMap for the hashring configuration.
-func newHashringConfigMap(name, namespace, contents string, objectMetaLabels map[string]string) *corev1.ConfigMap {
+func newHashringConfigMap(name, namespace, contents string, objectMetaLabels, annotations map[string]string) *corev1.ConfigMap {
 	if contents == "" {
 		contents = EmptyHashringConfig
 	}
@@ -599,9 +929,10 @@ func newHashringConfigMap(name, namespace, contents string, objectMetaLabels map
 			APIVersion: corev1.SchemeGroupVersion.String(),
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      name,
-			Labels:    objectMetaLabels,
-			Namespace: namespace,
+			Name:        name,
+			Labels:      objectMetaLabels,
+			Annotations: annotations,
+			Namespace:   namespace,
 		},
 		Data: map[string]string{
 			HashringConfigKey: contents,
@@ -662,10 +993,11 @@ func GetRouterLabels(opts RouterOptions) map[string]string {
 	return manifests.MergeMaps(opts.Labels, l)
 }
 
-func serviceMonitorOpts(from *manifests.ServiceMonitorConfig) manifests.ServiceMonitorOptions {
+func serviceMonitorOpts(from *manifests.ServiceMonitorConfig, metricsService bool) manifests.ServiceMonitorOptions {
 	return manifests.ServiceMonitorOptions{
-		Port:     ptr.To(HTTPPortName),
-		Interval: from.Interval,
+		Port:           ptr.To(HTTPPortName),
+		Interval:       from.Interval,
+		MetricsService: metricsService,
 	}
 }
 
@@ -737,6 +1069,9 @@ func buildThanosRouterContainer(opts RouterOptions) corev1.Container {
 					"ALL",
 				},
 			},
+			SeccompProfile: &corev1.SeccompProfile{
+				Type: corev1.SeccompProfileTypeRuntimeDefault,
+			},
 		},
 		ReadinessProbe: &corev1.Probe{
 			ProbeHandler: corev1.ProbeHandler{
@@ -829,10 +1164,13 @@ func buildKubeResourceSyncContainer(opts RouterOptions) corev1.Container {
 			Capabilities: &corev1.Capabilities{
 				Drop: []corev1.Capability{"ALL"},
 			},
+			SeccompProfile: &corev1.SeccompProfile{
+				Type: corev1.SeccompProfileTypeRuntimeDefault,
+			},
 		},
 		Args: []string{
 			"--resource-type=configmap",
-			"--resource-name=" + opts.GetGeneratedResourceName(),
+			"--resource-name=" + opts.hashringConfigMapName(),
 			"--namespace=" + opts.Namespace,
 			"--write-path=" + hashringMountPath + "/" + HashringConfigKey,
 			"--resource-key=" + HashringConfigKey,
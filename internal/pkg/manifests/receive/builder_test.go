@@ -1,7 +1,13 @@
 package receive
 
 import (
+	"encoding/json"
+	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 
 	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
 	"github.com/thanos-community/thanos-operator/test/utils"
@@ -10,6 +16,8 @@ import (
 	"gotest.tools/v3/golden"
 
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -60,6 +68,138 @@ func TestBuildIngesters(t *testing.T) {
 	utils.ValidateObjectLabelsEqual(t, wantLabels, []client.Object{objs[1], objs[2]}...)
 }
 
+func TestBuildIngestersServiceAccount(t *testing.T) {
+	opts := IngesterOptions{
+		Options: manifests.Options{
+			Owner:                     "any",
+			Namespace:                 "ns",
+			Image:                     ptr.To("some-custom-image"),
+			ServiceAccountAnnotations: map[string]string{"eks.amazonaws.com/role-arn": "arn:aws:iam::123456789012:role/thanos"},
+		},
+		HashringName: "test-hashring",
+	}
+
+	objs := opts.Build()
+	sa, ok := objs[0].(*corev1.ServiceAccount)
+	assert.Assert(t, ok, "expected first object to be a ServiceAccount")
+	assert.Equal(t, sa.GetAnnotations()["eks.amazonaws.com/role-arn"], "arn:aws:iam::123456789012:role/thanos")
+
+	opts.ServiceAccountName = "existing"
+	objs = opts.Build()
+	for _, obj := range objs {
+		_, isSA := obj.(*corev1.ServiceAccount)
+		assert.Assert(t, !isSA, "expected no ServiceAccount to be built")
+	}
+	assert.Equal(t, NewIngestorStatefulSet(opts).Spec.Template.Spec.ServiceAccountName, "existing")
+}
+
+func TestBuildIngestersGRPCTLS(t *testing.T) {
+	opts := IngesterOptions{
+		Options: manifests.Options{
+			Owner:     "any",
+			Namespace: "ns",
+			Image:     ptr.To("some-custom-image"),
+		},
+		HashringName: "test-hashring",
+		GRPCTLS: &manifests.TLSConfig{
+			CertSecret:   "ingester-tls",
+			ClientCA:     &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "ca"}, Key: "ca.crt"},
+			ClientCAHash: "abc",
+		},
+	}
+
+	sts := NewIngestorStatefulSet(opts)
+	args := sts.Spec.Template.Spec.Containers[0].Args
+	assert.Assert(t, slices.Contains(args, "--grpc-server-tls-cert=/etc/thanos/tls/grpc/tls.crt"))
+	assert.Assert(t, slices.Contains(args, "--grpc-server-tls-key=/etc/thanos/tls/grpc/tls.key"))
+	assert.Assert(t, slices.Contains(args, "--grpc-server-tls-client-ca=/etc/thanos/tls/grpc-client-ca/ca.crt"))
+	assert.Equal(t, sts.Spec.Template.Annotations[manifests.ClientCAHashAnnotation(manifests.GRPCTLSServerName)], "abc")
+	assert.Equal(t, NewIngestorService(opts).GetLabels()[manifests.GRPCTLSLabel], manifests.GRPCTLSLabelValue)
+
+	router := RouterOptions{
+		Options:       manifests.Options{Owner: "any", Namespace: "ns"},
+		GRPCClientTLS: &manifests.GRPCClientTLSConfig{CertSecret: "router-tls", ServerName: "ingester.ns.svc"},
+	}
+	args = NewRouterDeployment(router).Spec.Template.Spec.Containers[0].Args
+	assert.Assert(t, slices.Contains(args, "--remote-write.client-tls-secure"))
+	assert.Assert(t, slices.Contains(args, "--remote-write.client-tls-cert=/etc/thanos/tls/grpc-client/tls.crt"))
+	assert.Assert(t, slices.Contains(args, "--remote-write.client-tls-key=/etc/thanos/tls/grpc-client/tls.key"))
+	assert.Assert(t, slices.Contains(args, "--remote-write.client-server-name=ingester.ns.svc"))
+}
+
+func TestIngesterUploadConcurrency(t *testing.T) {
+	opts := IngesterOptions{
+		Options:      manifests.Options{Owner: "any", Namespace: "ns"},
+		HashringName: "test-hashring",
+	}
+	args := NewIngestorStatefulSet(opts).Spec.Template.Spec.Containers[0].Args
+	assert.Assert(t, !slices.ContainsFunc(args, func(arg string) bool { return strings.HasPrefix(arg, "--shipper.upload-concurrency") }))
+
+	opts.UploadConcurrency = 2
+	args = NewIngestorStatefulSet(opts).Spec.Template.Spec.Containers[0].Args
+	assert.Assert(t, slices.Contains(args, "--shipper.upload-concurrency=2"))
+}
+
+func TestIngesterExternalLabels(t *testing.T) {
+	opts := IngesterOptions{
+		Options:      manifests.Options{Owner: "any", Namespace: "ns"},
+		HashringName: "test-hashring",
+	}
+	args := NewIngestorStatefulSet(opts).Spec.Template.Spec.Containers[0].Args
+	assert.Assert(t, slices.Contains(args, `--label=receive_replica="$(POD_NAME)"`))
+	assert.Assert(t, slices.Contains(args, `--label=receive_hashring="test-hashring"`))
+
+	opts.ExternalLabels = map[string]string{"replica": "ingester-$(POD_NAME)", "receive_hashring": "custom"}
+	args = NewIngestorStatefulSet(opts).Spec.Template.Spec.Containers[0].Args
+	assert.Assert(t, slices.Contains(args, `--label=replica="ingester-$(POD_NAME)"`))
+	assert.Assert(t, slices.Contains(args, `--label=receive_hashring="custom"`))
+	assert.Assert(t, !slices.ContainsFunc(args, func(arg string) bool { return strings.HasPrefix(arg, "--label=receive_replica") }))
+}
+
+func TestIngesterTSDBFlags(t *testing.T) {
+	opts := IngesterOptions{
+		Options:      manifests.Options{Owner: "any", Namespace: "ns"},
+		HashringName: "test-hashring",
+	}
+	args := NewIngestorStatefulSet(opts).Spec.Template.Spec.Containers[0].Args
+	assert.Assert(t, !slices.ContainsFunc(args, func(arg string) bool {
+		return strings.Contains(arg, "out-of-order") || strings.Contains(arg, "block-duration") || strings.Contains(arg, "wal-compression")
+	}))
+
+	opts.TSDBOpts = TSDBOpts{
+		Retention:            "1d",
+		OutOfOrderTimeWindow: "30m",
+		WALCompression:       ptr.To(false),
+		MinBlockDuration:     "1h",
+		MaxBlockDuration:     "4h",
+	}
+	args = NewIngestorStatefulSet(opts).Spec.Template.Spec.Containers[0].Args
+	for _, want := range []string{
+		"--tsdb.retention=1d",
+		"--tsdb.out-of-order.time-window=30m",
+		"--no-tsdb.wal-compression",
+		"--tsdb.min-block-duration=1h",
+		"--tsdb.max-block-duration=4h",
+	} {
+		assert.Assert(t, slices.Contains(args, want), "expected %s in %v", want, args)
+	}
+}
+
+func TestIngesterSpreadAcrossZones(t *testing.T) {
+	opts := IngesterOptions{
+		Options:      manifests.Options{Owner: "any", Namespace: "ns"},
+		HashringName: "test-hashring",
+	}
+	assert.Assert(t, len(NewIngestorStatefulSet(opts).Spec.Template.Spec.TopologySpreadConstraints) == 0)
+
+	opts.SpreadAcrossZones = true
+	constraints := NewIngestorStatefulSet(opts).Spec.Template.Spec.TopologySpreadConstraints
+	assert.Assert(t, len(constraints) == 1)
+	assert.Equal(t, constraints[0].TopologyKey, corev1.LabelTopologyZone)
+	assert.Equal(t, constraints[0].WhenUnsatisfiable, corev1.DoNotSchedule)
+	assert.DeepEqual(t, constraints[0].LabelSelector.MatchLabels, opts.GetSelectorLabels())
+}
+
 func TestBuildRouter(t *testing.T) {
 	opts := RouterOptions{
 		Options: manifests.Options{
@@ -94,6 +234,235 @@ func TestBuildRouter(t *testing.T) {
 	utils.ValidateObjectLabelsEqual(t, wantLabels, []client.Object{objs[1], objs[2]}...)
 }
 
+func TestBuildRouterExistingObjects(t *testing.T) {
+	opts := RouterOptions{
+		Options:                       manifests.Options{Owner: "any", Namespace: "ns"},
+		ExistingServiceName:           "remote-write",
+		ExistingHashringConfigMapName: "hashrings",
+	}
+
+	for _, obj := range opts.Build() {
+		_, isService := obj.(*corev1.Service)
+		assert.Assert(t, !isService, "expected no Service to be built")
+		_, isConfigMap := obj.(*corev1.ConfigMap)
+		assert.Assert(t, !isConfigMap, "expected no ConfigMap to be built")
+	}
+	volumes := NewRouterDeployment(opts).Spec.Template.Spec.Volumes
+	assert.Equal(t, volumes[0].ConfigMap.Name, "hashrings")
+
+	opts.FeatureGateConfig = &FeatureGateConfig{KubeResourceSyncEnabled: true}
+	args := NewRouterDeployment(opts).Spec.Template.Spec.Containers[1].Args
+	assert.Assert(t, slices.Contains(args, "--resource-name=hashrings"))
+}
+
+func TestBuildRouterGroup(t *testing.T) {
+	opts := RouterOptions{
+		Options: manifests.Options{Owner: "any", Namespace: "ns"},
+		Limits:  &LimitsOptions{},
+		Ingress: &manifests.IngressOptions{Hosts: []string{"remote-write.example.com"}},
+		Group:   "eu",
+	}
+	assert.Equal(t, opts.GetGeneratedResourceName(), "thanos-receive-router-any-eu")
+
+	var services int
+	for _, obj := range opts.Build() {
+		_, isConfigMap := obj.(*corev1.ConfigMap)
+		assert.Assert(t, !isConfigMap, "expected the ConfigMaps of the routers to be shared, got %s", obj.GetName())
+		_, isIngress := obj.(*networkingv1.Ingress)
+		assert.Assert(t, !isIngress, "expected no Ingress to be built for a group")
+		if _, ok := obj.(*corev1.Service); ok {
+			services++
+			assert.Equal(t, obj.GetName(), "thanos-receive-router-any-eu")
+		}
+	}
+	assert.Equal(t, services, 1)
+
+	deployment := NewRouterDeployment(opts)
+	assert.Equal(t, deployment.Spec.Selector.MatchLabels[manifests.InstanceLabel], "thanos-receive-router-any-eu")
+	var configMaps []string
+	for _, v := range deployment.Spec.Template.Spec.Volumes {
+		if v.ConfigMap != nil {
+			configMaps = append(configMaps, v.ConfigMap.Name)
+		}
+	}
+	assert.DeepEqual(t, configMaps, []string{"thanos-receive-router-any", LimitsConfigMapName("thanos-receive-router-any")})
+}
+
+func TestBuildRouterMonitoringMixin(t *testing.T) {
+	opts := RouterOptions{
+		Options: manifests.Options{
+			Owner:                 "any",
+			Namespace:             "ns",
+			MonitoringMixinConfig: &manifests.MonitoringMixinConfig{Labels: map[string]string{"team": "observability"}},
+		},
+		ExistingServiceName: "remote-write",
+	}
+
+	var rule *monitoringv1.PrometheusRule
+	var dashboard *corev1.ConfigMap
+	for _, obj := range opts.Build() {
+		switch o := obj.(type) {
+		case *monitoringv1.PrometheusRule:
+			rule = o
+		case *corev1.ConfigMap:
+			if o.GetName() == manifests.DashboardName(opts.GetGeneratedResourceName()) {
+				dashboard = o
+			}
+		}
+	}
+	assert.Assert(t, rule != nil, "expected a PrometheusRule to be built")
+	assert.Assert(t, dashboard != nil, "expected a dashboard ConfigMap to be built")
+	assert.Equal(t, rule.GetLabels()["team"], "observability")
+	assert.Equal(t, dashboard.GetLabels()[manifests.GrafanaDashboardLabel], manifests.GrafanaDashboardValue)
+
+	var alerts []string
+	for _, r := range rule.Spec.Groups[0].Rules {
+		alerts = append(alerts, r.Alert)
+		assert.Assert(t, strings.Contains(r.Expr.String(), `namespace="ns", job=~"remote-write|remote-write-metrics"`), r.Expr.String())
+	}
+	assert.DeepEqual(t, alerts, []string{"ThanosReceiveHighReplicationFailures", "ThanosReceiveHashringChurn"})
+	assert.Assert(t, strings.Contains(dashboard.Data[dashboard.GetName()+".json"], `job=~\"thanos-receive-ingester-any-.+\"`))
+
+	opts.MonitoringMixinConfig = nil
+	for _, obj := range opts.Build() {
+		_, isRule := obj.(*monitoringv1.PrometheusRule)
+		assert.Assert(t, !isRule, "expected no PrometheusRule to be built")
+	}
+}
+
+func TestRouterReplicationFlags(t *testing.T) {
+	opts := RouterOptions{
+		Options: manifests.Options{Owner: "any", Namespace: "ns"},
+	}
+	args := NewRouterDeployment(opts).Spec.Template.Spec.Containers[0].Args
+	assert.Assert(t, slices.Contains(args, `--receive.grpc-service-config={"loadBalancingPolicy":"round_robin"}`))
+	assert.Assert(t, !slices.ContainsFunc(args, func(arg string) bool {
+		return strings.HasPrefix(arg, "--receive.replication-protocol") || strings.HasPrefix(arg, "--receive.forward.async-workers")
+	}))
+
+	opts.ReplicationProtocol = "capnproto"
+	opts.AsyncForwardWorkerCount = 10
+	opts.ReplicationMaxRetries = 2
+	args = NewRouterDeployment(opts).Spec.Template.Spec.Containers[0].Args
+	assert.Assert(t, slices.Contains(args, "--receive.replication-protocol=capnproto"))
+	assert.Assert(t, slices.Contains(args, "--receive.forward.async-workers=10"))
+	idx := slices.IndexFunc(args, func(arg string) bool { return strings.HasPrefix(arg, "--receive.grpc-service-config=") })
+	assert.Assert(t, idx >= 0)
+	var config struct {
+		MethodConfig []struct {
+			RetryPolicy struct {
+				MaxAttempts int `json:"maxAttempts"`
+			} `json:"retryPolicy"`
+		} `json:"methodConfig"`
+	}
+	assert.NilError(t, json.Unmarshal([]byte(strings.TrimPrefix(args[idx], "--receive.grpc-service-config=")), &config))
+	assert.Equal(t, config.MethodConfig[0].RetryPolicy.MaxAttempts, 3)
+}
+
+func TestRouterRolloutOnHashringChange(t *testing.T) {
+	opts := RouterOptions{
+		Options:        manifests.Options{Owner: "any", Namespace: "ns"},
+		HashringConfig: `[{"hashring":"default","endpoints":[]}]`,
+	}
+	_, ok := NewRouterDeployment(opts).Spec.Template.Annotations[HashringConfigHashAnnotation]
+	assert.Assert(t, !ok, "expected no hashring config hash without rollouts")
+
+	opts.RolloutOnHashringChange = true
+	before := NewRouterDeployment(opts).Spec.Template.Annotations[HashringConfigHashAnnotation]
+	assert.Equal(t, before, HashringConfigHash(opts.HashringConfig))
+
+	opts.HashringConfig = EmptyHashringConfig
+	after := NewRouterDeployment(opts).Spec.Template.Annotations[HashringConfigHashAnnotation]
+	assert.Assert(t, before != after, "expected the hashring config hash to change with the configuration")
+
+	opts.ExistingHashringConfigMapName = "hashrings"
+	_, ok = NewRouterDeployment(opts).Spec.Template.Annotations[HashringConfigHashAnnotation]
+	assert.Assert(t, !ok, "expected no hashring config hash with an existing ConfigMap")
+}
+
+func TestHashringConfigVersion(t *testing.T) {
+	opts := RouterOptions{
+		Options:        manifests.Options{Owner: "any", Namespace: "ns"},
+		HashringConfig: `[{"hashring":"default","endpoints":[]}]`,
+	}
+	cm := newHashringConfigMap("any", "ns", opts.HashringConfig, nil, hashringConfigAnnotations(opts))
+	assert.Assert(t, cm.Annotations == nil, "expected no version annotations on an unversioned configuration")
+
+	opts.HashringConfigVersion = 7
+	opts.HashringConfigUpdateTime = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cm = newHashringConfigMap("any", "ns", opts.HashringConfig, nil, hashringConfigAnnotations(opts))
+	assert.Equal(t, cm.Annotations[HashringConfigVersionAnnotation], "7")
+	assert.Equal(t, cm.Annotations[HashringConfigUpdatedAnnotation], "2025-01-01T12:00:00Z")
+}
+
+func TestBuildRouterIngress(t *testing.T) {
+	opts := RouterOptions{
+		Options: manifests.Options{Owner: "any", Namespace: "ns"},
+		Service: &manifests.ServiceOptions{Ports: []manifests.ServicePortOptions{{Name: RemoteWritePortName, Port: 443}}},
+		Ingress: &manifests.IngressOptions{Hosts: []string{"remote-write.example.com"}},
+	}
+
+	ingress := opts.Build()[4].(*networkingv1.Ingress)
+	backend := ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service
+	assert.Equal(t, backend.Name, opts.GetGeneratedResourceName())
+	assert.Equal(t, backend.Port.Number, int32(443))
+
+	opts.ExistingServiceName = "remote-write"
+	ingress = opts.Build()[3].(*networkingv1.Ingress)
+	backend = ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service
+	assert.Equal(t, backend.Name, "remote-write")
+	assert.Equal(t, backend.Port.Number, int32(RemoteWritePort))
+}
+
+func TestBuildNetworkPolicies(t *testing.T) {
+	policy := &NetworkPolicyOptions{
+		RemoteWriteSources: []networkingv1.NetworkPolicyPeer{
+			{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": "ingress-nginx"}}},
+		},
+		ObjectStorage: []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/8"}}},
+	}
+
+	for _, tc := range []struct {
+		name   string
+		objs   []client.Object
+		golden string
+	}{
+		{
+			name: "ingester",
+			objs: IngesterOptions{
+				Options:       manifests.Options{Owner: "test-owner", Namespace: "test-namespace"},
+				HashringName:  "default",
+				NetworkPolicy: policy,
+			}.Build(),
+			golden: "ingester-networkpolicy.golden.yaml",
+		},
+		{
+			name: "router",
+			objs: RouterOptions{
+				Options:       manifests.Options{Owner: "test-owner", Namespace: "test-namespace"},
+				NetworkPolicy: policy,
+			}.Build(),
+			golden: "router-networkpolicy.golden.yaml",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var policies []client.Object
+			for _, obj := range tc.objs {
+				if _, ok := obj.(*networkingv1.NetworkPolicy); ok {
+					policies = append(policies, obj)
+				}
+			}
+			assert.Equal(t, len(policies), 1)
+
+			yamlBytes, err := yaml.Marshal(policies[0])
+			if err != nil {
+				t.Fatalf("failed to marshal object to YAML: %v", err)
+			}
+			golden.Assert(t, string(yamlBytes), tc.golden)
+		})
+	}
+}
+
 func TestNewIngestorStatefulSet(t *testing.T) {
 
 	for _, tc := range []struct {
@@ -197,6 +566,17 @@ func TestNewIngestorStatefulSet(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:   "test with shutdown drain",
+			golden: "ingester-statefulset-with-shutdown-drain.golden.yaml",
+			opts: IngesterOptions{
+				Options: manifests.Options{
+					Namespace: "ns",
+					Image:     ptr.To("some-custom-image"),
+				},
+				ShutdownDrainSeconds: 60,
+			},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			ingester := NewIngestorStatefulSet(tc.opts)
@@ -332,6 +712,39 @@ func TestNewRouterDeployment(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:   "test with remote write tls and client ca",
+			golden: "router-deployment-with-remote-write-tls.golden.yaml",
+			opts: RouterOptions{
+				Options: manifests.Options{
+					Owner:     "test-receive",
+					Namespace: "test-ns",
+					Image:     ptr.To("quay.io/thanos/thanos:latest"),
+				},
+				RemoteWriteTLS: &manifests.TLSConfig{
+					CertSecret: "remote-write-tls",
+					ClientCA: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "remote-write-ca"},
+						Key:                  "ca.pem",
+					},
+					ClientCAHash: "abc123",
+				},
+			},
+		},
+		{
+			name:   "test with limits",
+			golden: "router-deployment-with-limits.golden.yaml",
+			opts: RouterOptions{
+				Options: manifests.Options{
+					Owner:     "test-receive",
+					Namespace: "test-ns",
+					Image:     ptr.To("quay.io/thanos/thanos:latest"),
+				},
+				Limits: &LimitsOptions{
+					Default: &WriteLimitsOptions{SeriesLimit: ptr.To(int64(1000))},
+				},
+			},
+		},
 		{
 			name:   "test with kube-resource-sync disabled",
 			golden: "router-deployment-without-kube-resource-sync.golden.yaml",
@@ -429,6 +842,31 @@ func TestNewRouterService(t *testing.T) {
 			golden: "router-service-basic.golden.yaml",
 			opts:   opts,
 		},
+		{
+			name:   "test router service with topology aware routing",
+			golden: "router-service-topology-aware.golden.yaml",
+			opts: func() RouterOptions {
+				o := opts
+				o.ServiceTraffic = &ServiceTrafficOptions{
+					TopologyMode:          "Auto",
+					InternalTrafficPolicy: ptr.To(corev1.ServiceInternalTrafficPolicyCluster),
+				}
+				return o
+			}(),
+		},
+		{
+			name:   "test router service published by external dns",
+			golden: "router-service-external-dns.golden.yaml",
+			opts: func() RouterOptions {
+				o := opts
+				o.ServiceAnnotations = map[string]string{"service.beta.kubernetes.io/aws-load-balancer-type": "nlb"}
+				o.Service = &manifests.ServiceOptions{
+					Type:     corev1.ServiceTypeLoadBalancer,
+					Hostname: "remote-write.example.com",
+				}
+				return o
+			}(),
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			router := NewRouterService(tc.opts)
@@ -472,3 +910,100 @@ func TestBuildRouterGolden(t *testing.T) {
 	}
 	golden.Assert(t, string(yamlBytes), "router-complete.golden.yaml")
 }
+
+func TestLimitsConfig(t *testing.T) {
+	opts := RouterOptions{
+		Options: manifests.Options{
+			Owner:     "test-receive",
+			Namespace: "test-ns",
+		},
+		Limits: &LimitsOptions{
+			Global: GlobalLimitsOptions{
+				MaxConcurrency:    ptr.To(int64(30)),
+				MetaMonitoringURL: "http://prometheus.monitoring.svc:9090",
+			},
+			Default: &WriteLimitsOptions{
+				SizeBytesLimit:  ptr.To(int64(5242880)),
+				SeriesLimit:     ptr.To(int64(1000)),
+				SamplesLimit:    ptr.To(int64(10000)),
+				HeadSeriesLimit: ptr.To(int64(100000)),
+			},
+			Tenants: map[string]WriteLimitsOptions{
+				"unlimited": {SeriesLimit: ptr.To(int64(0)), SamplesLimit: ptr.To(int64(0))},
+				"big":       {HeadSeriesLimit: ptr.To(int64(1000000))},
+			},
+		},
+	}
+
+	var limits *corev1.ConfigMap
+	for _, obj := range opts.Build() {
+		if cm, ok := obj.(*corev1.ConfigMap); ok && cm.GetName() == LimitsConfigMapName(opts.GetGeneratedResourceName()) {
+			limits = cm
+		}
+	}
+	if limits == nil {
+		t.Fatal("expected the limits ConfigMap to be built")
+	}
+	golden.Assert(t, limits.Data[LimitsConfigKey], "limits-config.golden.yaml")
+
+	opts.Limits = nil
+	for _, obj := range opts.Build() {
+		if obj.GetName() == LimitsConfigMapName(opts.GetGeneratedResourceName()) {
+			t.Fatal("expected no limits ConfigMap without limits")
+		}
+	}
+}
+
+func TestRouterRelabelConfigs(t *testing.T) {
+	opts := RouterOptions{
+		Options: manifests.Options{
+			Owner:     "test-receive",
+			Namespace: "test-ns",
+		},
+		RelabelConfigs: RelabelConfigsOptions{
+			{SourceLabels: []string{"__name__"}, Regex: ptr.To("go_.*"), Action: "drop"},
+			{SourceLabels: []string{"cluster", "namespace"}, Separator: ptr.To("/"), TargetLabel: "scope", Action: "replace"},
+			{Regex: ptr.To("pod_template_hash"), Action: "labeldrop"},
+		},
+	}
+
+	golden.Assert(t, opts.RelabelConfigs.String(), "relabel-config.golden.yaml")
+	args := routerArgsFrom(opts)
+	if !slices.Contains(args, "--receive.relabel-config="+opts.RelabelConfigs.String()) {
+		t.Errorf("expected the relabel config flag to be set, got %v", args)
+	}
+
+	opts.RelabelConfigs = nil
+	for _, arg := range routerArgsFrom(opts) {
+		if strings.HasPrefix(arg, "--receive.relabel-config") {
+			t.Errorf("expected no relabel config flag without relabel configs, got %s", arg)
+		}
+	}
+}
+
+func TestNewWriteProbeCronJob(t *testing.T) {
+	opts := WriteProbeOptions{
+		Options: manifests.Options{
+			Owner:     "test-receive",
+			Namespace: "test-ns",
+			PlacementConfig: &manifests.Placement{
+				NodeSelector: map[string]string{"node-role.kubernetes.io/ingest": "true"},
+			},
+		},
+		Schedule:        "*/5 * * * *",
+		DeadlineSeconds: 120,
+		RemoteWriteURL:  "http://thanos-receive-router-test-receive.test-ns.svc:19291/api/v1/receive",
+		QueryURL:        "http://thanos-query-test-query.test-ns.svc:9090",
+	}
+
+	objs := opts.Build()
+	if len(objs) != 1 {
+		t.Fatalf("expected 1 object, got %d", len(objs))
+	}
+
+	yamlBytes, err := yaml.Marshal(objs[0])
+	if err != nil {
+		t.Fatalf("failed to marshal cronjob to YAML: %v", err)
+	}
+	golden.Assert(t, string(yamlBytes), "write-probe-cronjob.golden.yaml")
+}
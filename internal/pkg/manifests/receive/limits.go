@@ -0,0 +1,154 @@
+package receive
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// LimitsConfigKey is the key in the ConfigMap for the limits configuration.
+	LimitsConfigKey = "limits.yaml"
+
+	limitsVolumeName = "limits-config"
+	limitsMountPath  = "/etc/thanos/limits"
+)
+
+// LimitsOptions are the write limits enforced by the Thanos Receive router.
+type LimitsOptions struct {
+	Global GlobalLimitsOptions
+	// Default are the limits applied to every tenant. Not set if nil.
+	Default *WriteLimitsOptions
+	// Tenants are the limits of individual tenants, keyed by tenant ID.
+	Tenants map[string]WriteLimitsOptions
+}
+
+// GlobalLimitsOptions are the limits applied to each router as a whole.
+type GlobalLimitsOptions struct {
+	MaxConcurrency           *int64
+	MetaMonitoringURL        string
+	MetaMonitoringLimitQuery string
+}
+
+// WriteLimitsOptions are the write limits of a tenant. Limits that are nil are not set.
+type WriteLimitsOptions struct {
+	SizeBytesLimit  *int64
+	SeriesLimit     *int64
+	SamplesLimit    *int64
+	HeadSeriesLimit *int64
+}
+
+// limitsConfig is the limits configuration file of Thanos Receive.
+type limitsConfig struct {
+	Write writeLimitsConfig `json:"write"`
+}
+
+type writeLimitsConfig struct {
+	Global  globalLimitsConfig            `json:"global"`
+	Default *tenantLimitsConfig           `json:"default,omitempty"`
+	Tenants map[string]tenantLimitsConfig `json:"tenants,omitempty"`
+}
+
+type globalLimitsConfig struct {
+	MaxConcurrency           *int64 `json:"max_concurrency,omitempty"`             //nolint:tagliatelle // max_concurrency is from thanos config
+	MetaMonitoringURL        string `json:"meta_monitoring_url,omitempty"`         //nolint:tagliatelle // meta_monitoring_url is from thanos config
+	MetaMonitoringLimitQuery string `json:"meta_monitoring_limit_query,omitempty"` //nolint:tagliatelle // meta_monitoring_limit_query is from thanos config
+}
+
+type tenantLimitsConfig struct {
+	Request         *requestLimitsConfig `json:"request,omitempty"`
+	HeadSeriesLimit *int64               `json:"head_series_limit,omitempty"` //nolint:tagliatelle // head_series_limit is from thanos config
+}
+
+type requestLimitsConfig struct {
+	SizeBytesLimit *int64 `json:"size_bytes_limit,omitempty"` //nolint:tagliatelle // size_bytes_limit is from thanos config
+	SeriesLimit    *int64 `json:"series_limit,omitempty"`     //nolint:tagliatelle // series_limit is from thanos config
+	SamplesLimit   *int64 `json:"samples_limit,omitempty"`    //nolint:tagliatelle // samples_limit is from thanos config
+}
+
+func (l WriteLimitsOptions) toConfig() tenantLimitsConfig {
+	config := tenantLimitsConfig{HeadSeriesLimit: l.HeadSeriesLimit}
+	if l.SizeBytesLimit != nil || l.SeriesLimit != nil || l.SamplesLimit != nil {
+		config.Request = &requestLimitsConfig{
+			SizeBytesLimit: l.SizeBytesLimit,
+			SeriesLimit:    l.SeriesLimit,
+			SamplesLimit:   l.SamplesLimit,
+		}
+	}
+	return config
+}
+
+// String renders the limits configuration file of Thanos Receive.
+func (opts LimitsOptions) String() string {
+	config := limitsConfig{
+		Write: writeLimitsConfig{
+			Global: globalLimitsConfig{
+				MaxConcurrency:           opts.Global.MaxConcurrency,
+				MetaMonitoringURL:        opts.Global.MetaMonitoringURL,
+				MetaMonitoringLimitQuery: opts.Global.MetaMonitoringLimitQuery,
+			},
+		},
+	}
+	if opts.Default != nil {
+		config.Write.Default = ptr.To(opts.Default.toConfig())
+	}
+	if len(opts.Tenants) > 0 {
+		config.Write.Tenants = make(map[string]tenantLimitsConfig, len(opts.Tenants))
+		for tenant, limits := range opts.Tenants {
+			config.Write.Tenants[tenant] = limits.toConfig()
+		}
+	}
+
+	// the config only holds strings, integers and maps with string keys, which always marshal
+	b, _ := yaml.Marshal(config)
+	return string(b)
+}
+
+// LimitsConfigMapName returns the name of the ConfigMap holding the limits configuration of the router.
+func LimitsConfigMapName(routerName string) string {
+	return routerName + "-limits"
+}
+
+// newLimitsConfigMap creates the ConfigMap for the limits configuration.
+func newLimitsConfigMap(routerName, namespace string, limits LimitsOptions, objectMetaLabels map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: corev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      LimitsConfigMapName(routerName),
+			Labels:    objectMetaLabels,
+			Namespace: namespace,
+		},
+		Data: map[string]string{
+			LimitsConfigKey: limits.String(),
+		},
+	}
+}
+
+// mountLimits mounts the limits ConfigMap into the router container of the pod template.
+func mountLimits(tpl *corev1.PodTemplateSpec, routerName string) {
+	tpl.Spec.Volumes = append(tpl.Spec.Volumes, corev1.Volume{
+		Name: limitsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: LimitsConfigMapName(routerName),
+				},
+				DefaultMode: ptr.To(int32(420)),
+			},
+		},
+	})
+	for i, c := range tpl.Spec.Containers {
+		if c.Name != RouterComponentName {
+			continue
+		}
+		tpl.Spec.Containers[i].VolumeMounts = append(tpl.Spec.Containers[i].VolumeMounts, corev1.VolumeMount{
+			Name:      limitsVolumeName,
+			MountPath: limitsMountPath,
+		})
+	}
+}
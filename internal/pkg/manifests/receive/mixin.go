@@ -0,0 +1,129 @@
+package receive
+
+import (
+	"fmt"
+	"regexp"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// replicationFailureThreshold is the ratio of failed replications above which an alert fires.
+	replicationFailureThreshold = 0.05
+	// hashringChurnThreshold is the number of hashring configuration changes within 15m above which an alert fires.
+	hashringChurnThreshold = 3
+)
+
+// newRouterMixin builds the dashboard and the alerting rules of the routers and the ingesters of the owner.
+func newRouterMixin(opts RouterOptions, objectMetaLabels map[string]string) []client.Object {
+	name := opts.GetGeneratedResourceName()
+	service := name
+	if opts.ExistingServiceName != "" {
+		service = opts.ExistingServiceName
+	}
+	router := manifests.ScrapeJobSelector(opts.Namespace, service)
+	ingesters := fmt.Sprintf(`namespace="%s", job=~"%s-.+"`, opts.Namespace,
+		regexp.QuoteMeta(manifests.GeneratedName(IngestComponentName, opts.Owner)))
+	labels := manifests.MergeMaps(opts.MonitoringMixinConfig.Labels, objectMetaLabels)
+
+	dashboard := manifests.Dashboard{
+		Title: fmt.Sprintf("Thanos Receive / %s / %s", opts.Namespace, opts.Owner),
+		Panels: []manifests.DashboardPanel{
+			{
+				Title: "Remote write requests",
+				Unit:  "reqps",
+				Queries: []manifests.DashboardQuery{{
+					Expr:   fmt.Sprintf(`sum by (code) (rate(http_requests_total{%s, handler="receive"}[5m]))`, router),
+					Legend: "{{code}}",
+				}},
+			},
+			{
+				Title: "Remote write latency",
+				Unit:  "s",
+				Queries: []manifests.DashboardQuery{{
+					Expr:   fmt.Sprintf(`histogram_quantile(0.99, sum by (le) (rate(http_request_duration_seconds_bucket{%s, handler="receive"}[5m])))`, router),
+					Legend: "p99",
+				}},
+			},
+			{
+				Title: "Replication failures",
+				Unit:  "percentunit",
+				Queries: []manifests.DashboardQuery{{
+					Expr:   replicationFailureRatio(router),
+					Legend: "failed",
+				}},
+			},
+			{
+				Title: "Forward requests",
+				Unit:  "reqps",
+				Queries: []manifests.DashboardQuery{{
+					Expr:   fmt.Sprintf(`sum by (result) (rate(thanos_receive_forward_requests_total{%s}[5m]))`, router),
+					Legend: "{{result}}",
+				}},
+			},
+			{
+				Title: "Hashring configuration changes",
+				Queries: []manifests.DashboardQuery{{
+					Expr:   hashringChanges(router),
+					Legend: "{{pod}}",
+				}},
+			},
+			{
+				Title: "Ingester head series",
+				Queries: []manifests.DashboardQuery{{
+					Expr:   fmt.Sprintf(`sum by (pod) (prometheus_tsdb_head_series{%s})`, ingesters),
+					Legend: "{{pod}}",
+				}},
+			},
+		},
+	}
+
+	groups := []monitoringv1.RuleGroup{{
+		Name: "thanos-receive",
+		Rules: []monitoringv1.Rule{
+			{
+				Alert: "ThanosReceiveHighReplicationFailures",
+				Expr:  intstr.FromString(fmt.Sprintf(`%s > %v`, replicationFailureRatio(router), replicationFailureThreshold)),
+				For:   ptr.To(monitoringv1.Duration("5m")),
+				Labels: map[string]string{
+					"severity": "warning",
+				},
+				Annotations: map[string]string{
+					"summary":     "Thanos Receive is failing to replicate samples.",
+					"description": fmt.Sprintf("{{ $value | humanizePercentage }} of the replication requests of %s in namespace %s are failing.", opts.Owner, opts.Namespace),
+				},
+			},
+			{
+				Alert: "ThanosReceiveHashringChurn",
+				Expr:  intstr.FromString(fmt.Sprintf(`max(%s) > %d`, hashringChanges(router), hashringChurnThreshold)),
+				Labels: map[string]string{
+					"severity": "warning",
+				},
+				Annotations: map[string]string{
+					"summary":     "The Thanos Receive hashring configuration is changing frequently.",
+					"description": fmt.Sprintf("The hashring configuration of %s in namespace %s changed {{ $value }} times in the last 15 minutes.", opts.Owner, opts.Namespace),
+				},
+			},
+		},
+	}}
+
+	return []client.Object{
+		manifests.BuildDashboardConfigMap(name, opts.Namespace, labels, dashboard),
+		manifests.BuildPrometheusRule(name, opts.Namespace, labels, groups),
+	}
+}
+
+func replicationFailureRatio(router string) string {
+	return fmt.Sprintf(`sum(rate(thanos_receive_replications_total{%[1]s, result="error"}[5m])) / sum(rate(thanos_receive_replications_total{%[1]s}[5m]))`, router)
+}
+
+func hashringChanges(router string) string {
+	return fmt.Sprintf(`changes(thanos_receive_config_hash{%s}[15m])`, router)
+}
@@ -0,0 +1,85 @@
+package receive
+
+import (
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+	manifestquery "github.com/thanos-community/thanos-operator/internal/pkg/manifests/query"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+// dnsPort is the port of the cluster DNS, which the ingesters must reach to resolve the object storage endpoint.
+const dnsPort = 53
+
+// NetworkPolicyOptions configures the NetworkPolicies restricting the traffic of the routers and ingesters.
+type NetworkPolicyOptions struct {
+	// RemoteWriteSources are the peers allowed to remote write to the routers, in addition to the write probe.
+	// Remote writes are allowed from any source if empty.
+	RemoteWriteSources []networkingv1.NetworkPolicyPeer
+	// ObjectStorage are the destinations the ingesters are allowed to connect to, in addition to the cluster DNS.
+	// The egress of the ingesters is not restricted if empty.
+	ObjectStorage []networkingv1.NetworkPolicyPeer
+}
+
+// newIngesterNetworkPolicy creates a NetworkPolicy only allowing the routers of the same owner and the queriers
+// of any namespace to connect to the ingesters. The HTTP port stays open to any source, so that metrics can be scraped.
+func newIngesterNetworkPolicy(opts IngesterOptions, selectorLabels, objectMetaLabels map[string]string) *networkingv1.NetworkPolicy {
+	routers := GetRequiredRouterLabels()
+	routers[manifests.OwnerLabel] = manifests.ValidateAndSanitizeNameToValidLabelValue(opts.Owner)
+
+	ingress := []networkingv1.NetworkPolicyIngressRule{
+		{
+			From: []networkingv1.NetworkPolicyPeer{
+				{PodSelector: &metav1.LabelSelector{MatchLabels: routers}},
+				{
+					PodSelector:       &metav1.LabelSelector{MatchLabels: manifestquery.GetRequiredLabels()},
+					NamespaceSelector: &metav1.LabelSelector{},
+				},
+			},
+		},
+		{Ports: []networkingv1.NetworkPolicyPort{networkPolicyPort(corev1.ProtocolTCP, HTTPPort)}},
+	}
+
+	var egress []networkingv1.NetworkPolicyEgressRule
+	if len(opts.NetworkPolicy.ObjectStorage) > 0 {
+		egress = []networkingv1.NetworkPolicyEgressRule{
+			{To: opts.NetworkPolicy.ObjectStorage},
+			{Ports: []networkingv1.NetworkPolicyPort{
+				networkPolicyPort(corev1.ProtocolUDP, dnsPort),
+				networkPolicyPort(corev1.ProtocolTCP, dnsPort),
+			}},
+		}
+	}
+	return manifests.BuildNetworkPolicy(opts.GetGeneratedResourceName(), opts.Namespace, selectorLabels, objectMetaLabels, ingress, egress)
+}
+
+// newRouterNetworkPolicy creates a NetworkPolicy only allowing the remote write sources and the write probe
+// of the same owner to remote write to the routers. The HTTP port stays open to any source, so that metrics can be scraped.
+func newRouterNetworkPolicy(opts RouterOptions, selectorLabels, objectMetaLabels map[string]string) *networkingv1.NetworkPolicy {
+	remoteWrite := networkingv1.NetworkPolicyIngressRule{
+		Ports: []networkingv1.NetworkPolicyPort{networkPolicyPort(corev1.ProtocolTCP, RemoteWritePort)},
+	}
+	if len(opts.NetworkPolicy.RemoteWriteSources) > 0 {
+		probes := GetRequiredWriteProbeLabels()
+		probes[manifests.OwnerLabel] = manifests.ValidateAndSanitizeNameToValidLabelValue(opts.Owner)
+		remoteWrite.From = append([]networkingv1.NetworkPolicyPeer{
+			{PodSelector: &metav1.LabelSelector{MatchLabels: probes}},
+		}, opts.NetworkPolicy.RemoteWriteSources...)
+	}
+
+	ingress := []networkingv1.NetworkPolicyIngressRule{
+		remoteWrite,
+		{Ports: []networkingv1.NetworkPolicyPort{networkPolicyPort(corev1.ProtocolTCP, HTTPPort)}},
+	}
+	return manifests.BuildNetworkPolicy(opts.GetGeneratedResourceName(), opts.Namespace, selectorLabels, objectMetaLabels, ingress, nil)
+}
+
+func networkPolicyPort(protocol corev1.Protocol, port int) networkingv1.NetworkPolicyPort {
+	return networkingv1.NetworkPolicyPort{
+		Protocol: ptr.To(protocol),
+		Port:     ptr.To(intstr.FromInt(port)),
+	}
+}
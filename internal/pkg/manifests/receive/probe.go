@@ -0,0 +1,180 @@
+package receive
+
+import (
+	"fmt"
+
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// WriteProbeComponentName is the name of the Thanos Receive write probe component.
+	WriteProbeComponentName = "thanos-receive-write-probe"
+
+	// DefaultWriteProbeImage is the image used by the write probe if none is set.
+	// It must provide promtool and a shell.
+	DefaultWriteProbeImage = "quay.io/prometheus/prometheus:v3.5.0"
+
+	// WriteProbeMetricName is the name of the canary series written by the write probe.
+	WriteProbeMetricName = "thanos_operator_write_probe"
+
+	writeProbeContainerName = "write-probe"
+	// cronJobNameMaxLength is the maximum length of a CronJob name, so that the names of its Jobs are valid.
+	cronJobNameMaxLength = 52
+	// writeProbeInterval is the interval at which the write probe queries the canary series.
+	writeProbeInterval = 5
+)
+
+// writeProbeScript remote writes a canary series with a unique run label and waits until it can be queried.
+// promtool sends the sample with the current time, so the series is queryable as soon as it was ingested.
+const writeProbeScript = `set -eu
+run="$(date +%s)"
+printf '%s{probe="%s",run="%s"} 1\n' "${METRIC_NAME}" "${PROBE_NAME}" "${run}" > /tmp/probe.prom
+promtool push metrics "${REMOTE_WRITE_URL}" /tmp/probe.prom
+deadline=$((run + DEADLINE_SECONDS))
+until promtool query instant "${QUERY_URL}" "${METRIC_NAME}{probe=\"${PROBE_NAME}\",run=\"${run}\"}" | grep -q .; do
+  if [ "$(date +%s)" -ge "${deadline}" ]; then
+    echo "canary series was not queryable within ${DEADLINE_SECONDS}s"
+    exit 1
+  fi
+  sleep ${INTERVAL_SECONDS}
+done
+echo "canary series was queryable after $(($(date +%s) - run))s"
+`
+
+// WriteProbeOptions for the Thanos Receive write probe.
+// The Image of the embedded Options is the full image reference of the probe and Version is ignored.
+type WriteProbeOptions struct {
+	manifests.Options
+	// Schedule of the probe in Cron format.
+	Schedule string
+	// DeadlineSeconds is the time within which the canary series must become queryable.
+	DeadlineSeconds int32
+	// RemoteWriteURL is the remote write endpoint of the router.
+	RemoteWriteURL string
+	// QueryURL is the HTTP endpoint of the Thanos Query used to verify the canary series.
+	QueryURL string
+}
+
+// Build builds the write probe for Thanos Receive
+func (opts WriteProbeOptions) Build() []client.Object {
+	return []client.Object{NewWriteProbeCronJob(opts)}
+}
+
+func (opts WriteProbeOptions) Valid() error {
+	if opts.Owner == "" {
+		return fmt.Errorf("owner cannot be empty")
+	}
+	return nil
+}
+
+func (opts WriteProbeOptions) GetGeneratedResourceName() string {
+	return manifests.ValidateAndSanitizeResourceNameToLength(manifests.GeneratedName(WriteProbeComponentName, opts.Owner), cronJobNameMaxLength)
+}
+
+// GetRequiredWriteProbeLabels returns a map of labels that can be used to look up thanos receive write probe resources.
+// These labels are guaranteed to be present on all write probe resources created by this package,
+// including the Jobs and Pods created from the CronJob.
+func GetRequiredWriteProbeLabels() map[string]string {
+	l := GetRequiredLabels()
+	l[manifests.ComponentLabel] = WriteProbeComponentName
+	return l
+}
+
+func (opts WriteProbeOptions) GetSelectorLabels() map[string]string {
+	l := GetRequiredWriteProbeLabels()
+	l[manifests.InstanceLabel] = manifests.ValidateAndSanitizeNameToValidLabelValue(opts.GetGeneratedResourceName())
+	l[manifests.OwnerLabel] = manifests.ValidateAndSanitizeNameToValidLabelValue(opts.Owner)
+	return l
+}
+
+// NewWriteProbeCronJob creates a CronJob that remote writes a canary series through the router
+// and fails if it cannot be queried within the deadline.
+func NewWriteProbeCronJob(opts WriteProbeOptions) *batchv1.CronJob {
+	name := opts.GetGeneratedResourceName()
+	objectMetaLabels := manifests.MergeMaps(opts.Labels, opts.GetSelectorLabels())
+	image := ptr.Deref(opts.Image, DefaultWriteProbeImage)
+
+	cronJob := &batchv1.CronJob{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "CronJob",
+			APIVersion: batchv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   opts.Namespace,
+			Labels:      objectMetaLabels,
+			Annotations: opts.Annotations,
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:                   opts.Schedule,
+			ConcurrencyPolicy:          batchv1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: ptr.To(int32(1)),
+			FailedJobsHistoryLimit:     ptr.To(int32(1)),
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: objectMetaLabels,
+				},
+				Spec: batchv1.JobSpec{
+					BackoffLimit: ptr.To(int32(0)),
+					// leave time to push the canary series on top of the deadline
+					ActiveDeadlineSeconds: ptr.To(int64(opts.DeadlineSeconds) + 60),
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: objectMetaLabels,
+						},
+						Spec: corev1.PodSpec{
+							RestartPolicy:                corev1.RestartPolicyNever,
+							AutomountServiceAccountToken: ptr.To(false),
+							SecurityContext:              opts.SecurityContext,
+							Containers: []corev1.Container{
+								{
+									Name:            writeProbeContainerName,
+									Image:           image,
+									ImagePullPolicy: corev1.PullIfNotPresent,
+									Command:         []string{"/bin/sh", "-c", writeProbeScript},
+									Env: []corev1.EnvVar{
+										{Name: "METRIC_NAME", Value: WriteProbeMetricName},
+										{Name: "PROBE_NAME", Value: manifests.ValidateAndSanitizeNameToValidLabelValue(opts.Owner)},
+										{Name: "REMOTE_WRITE_URL", Value: opts.RemoteWriteURL},
+										{Name: "QUERY_URL", Value: opts.QueryURL},
+										{Name: "DEADLINE_SECONDS", Value: fmt.Sprintf("%d", opts.DeadlineSeconds)},
+										{Name: "INTERVAL_SECONDS", Value: fmt.Sprintf("%d", writeProbeInterval)},
+									},
+									SecurityContext: &corev1.SecurityContext{
+										AllowPrivilegeEscalation: ptr.To(false),
+										RunAsNonRoot:             ptr.To(true),
+										Capabilities: &corev1.Capabilities{
+											Drop: []corev1.Capability{
+												"ALL",
+											},
+										},
+										SeccompProfile: &corev1.SeccompProfile{
+											Type: corev1.SeccompProfileTypeRuntimeDefault,
+										},
+									},
+									TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if opts.PlacementConfig != nil {
+		podSpec := &cronJob.Spec.JobTemplate.Spec.Template.Spec
+		podSpec.NodeSelector = opts.PlacementConfig.NodeSelector
+		podSpec.Affinity = opts.PlacementConfig.Affinity
+		podSpec.Tolerations = opts.PlacementConfig.Tolerations
+		podSpec.TopologySpreadConstraints = opts.PlacementConfig.TopologySpreadConstraints
+	}
+	return cronJob
+}
@@ -0,0 +1,42 @@
+package receive
+
+import (
+	"sigs.k8s.io/yaml"
+)
+
+// RelabelConfigOptions is a relabeling rule applied by the router to the series of remote write requests.
+// Fields that are nil or empty are left to the Prometheus defaults.
+type RelabelConfigOptions struct {
+	SourceLabels []string
+	Separator    *string
+	TargetLabel  string
+	Regex        *string
+	Modulus      uint64
+	Replacement  *string
+	Action       string
+}
+
+// relabelConfig is a relabeling rule in the Prometheus configuration format read by Thanos Receive.
+type relabelConfig struct {
+	SourceLabels []string `json:"source_labels,omitempty"` //nolint:tagliatelle // source_labels is from prometheus config
+	Separator    *string  `json:"separator,omitempty"`
+	TargetLabel  string   `json:"target_label,omitempty"` //nolint:tagliatelle // target_label is from prometheus config
+	Regex        *string  `json:"regex,omitempty"`
+	Modulus      uint64   `json:"modulus,omitempty"`
+	Replacement  *string  `json:"replacement,omitempty"`
+	Action       string   `json:"action,omitempty"`
+}
+
+// RelabelConfigsOptions are the relabeling rules applied by the router, in order.
+type RelabelConfigsOptions []RelabelConfigOptions
+
+// String renders the relabel configuration of Thanos Receive.
+func (opts RelabelConfigsOptions) String() string {
+	config := make([]relabelConfig, 0, len(opts))
+	for _, r := range opts {
+		config = append(config, relabelConfig(r))
+	}
+	// the config only holds strings, integers and lists of strings, which always marshal
+	b, _ := yaml.Marshal(config)
+	return string(b)
+}
@@ -0,0 +1,58 @@
+package manifests
+
+import (
+	"sigs.k8s.io/yaml"
+)
+
+// RequestLoggingOptions configures the logging of the requests served by a component.
+type RequestLoggingOptions struct {
+	// HTTP configures the logging of the HTTP requests. HTTP requests are not logged if nil.
+	HTTP *RequestLogging
+	// GRPC configures the logging of the gRPC requests. gRPC requests are not logged if nil.
+	GRPC *RequestLogging
+}
+
+// RequestLogging configures when and at which level requests of a protocol are logged.
+type RequestLogging struct {
+	// Level is the level of the request logs.
+	Level    string
+	LogStart bool
+	LogEnd   bool
+}
+
+// requestLoggingConfig is the request logging configuration format read by Thanos.
+type requestLoggingConfig struct {
+	HTTP *requestLoggingProtocolConfig `json:"http,omitempty"`
+	GRPC *requestLoggingProtocolConfig `json:"grpc,omitempty"`
+}
+
+type requestLoggingProtocolConfig struct {
+	Options requestLoggingOptionsConfig `json:"options"`
+}
+
+type requestLoggingOptionsConfig struct {
+	Level    string                       `json:"level,omitempty"`
+	Decision requestLoggingDecisionConfig `json:"decision"`
+}
+
+type requestLoggingDecisionConfig struct {
+	LogStart bool `json:"log_start"` //nolint:tagliatelle // log_start is from thanos config
+	LogEnd   bool `json:"log_end"`   //nolint:tagliatelle // log_end is from thanos config
+}
+
+func (l *RequestLogging) config() *requestLoggingProtocolConfig {
+	if l == nil {
+		return nil
+	}
+	return &requestLoggingProtocolConfig{Options: requestLoggingOptionsConfig{
+		Level:    l.Level,
+		Decision: requestLoggingDecisionConfig{LogStart: l.LogStart, LogEnd: l.LogEnd},
+	}}
+}
+
+// String renders the request logging configuration of Thanos.
+func (opts RequestLoggingOptions) String() string {
+	// the config only holds strings and booleans, which always marshal
+	b, _ := yaml.Marshal(requestLoggingConfig{HTTP: opts.HTTP.config(), GRPC: opts.GRPC.config()})
+	return string(b)
+}
@@ -0,0 +1,52 @@
+package manifests
+
+import (
+	"testing"
+)
+
+func TestRequestLoggingOptions_String(t *testing.T) {
+	tests := []struct {
+		name string
+		opts RequestLoggingOptions
+		want string
+	}{
+		{
+			name: "http only",
+			opts: RequestLoggingOptions{HTTP: &RequestLogging{Level: "INFO", LogEnd: true}},
+			want: `http:
+  options:
+    decision:
+      log_end: true
+      log_start: false
+    level: INFO
+`,
+		},
+		{
+			name: "http and grpc",
+			opts: RequestLoggingOptions{
+				HTTP: &RequestLogging{Level: "DEBUG", LogStart: true, LogEnd: true},
+				GRPC: &RequestLogging{Level: "ERROR", LogEnd: true},
+			},
+			want: `grpc:
+  options:
+    decision:
+      log_end: true
+      log_start: false
+    level: ERROR
+http:
+  options:
+    decision:
+      log_end: true
+      log_start: true
+    level: DEBUG
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.String(); got != tt.want {
+				t.Errorf("RequestLoggingOptions.String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
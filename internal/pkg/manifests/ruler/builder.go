@@ -45,6 +45,7 @@ type Options struct {
 	Endpoints           []Endpoint
 	RuleFiles           []corev1.ConfigMapKeySelector
 	ObjStoreSecret      corev1.SecretKeySelector
+	ObjStoreConfig      manifests.ObjStoreConfig
 	Retention           manifests.Duration
 	AlertmanagerURL     string
 	ExternalLabels      map[string]string
@@ -68,7 +69,7 @@ func (opts Options) Build() []client.Object {
 	objectMetaLabels := GetLabels(opts)
 	name := opts.GetGeneratedResourceName()
 
-	objs = append(objs, manifests.BuildServiceAccount(opts.GetGeneratedResourceName(), opts.Namespace, selectorLabels, opts.Annotations))
+	objs = append(objs, manifests.BuildServiceAccount(opts.GetGeneratedResourceName(), opts.Namespace, selectorLabels, manifests.MergeMaps(opts.Annotations, opts.ServiceAccountAnnotations)))
 	objs = append(objs, newRulerStatefulSet(opts, selectorLabels, objectMetaLabels))
 	objs = append(objs, newRulerService(opts, selectorLabels, objectMetaLabels))
 
@@ -76,9 +77,13 @@ func (opts Options) Build() []client.Object {
 		objs = append(objs, manifests.NewPodDisruptionBudget(name, opts.Namespace, selectorLabels, objectMetaLabels, opts.Annotations, *opts.PodDisruptionConfig))
 	}
 
+	if opts.EnableMetricsService {
+		objs = append(objs, manifests.BuildMetricsService(name, opts.Namespace, objectMetaLabels, selectorLabels, opts.Annotations, HTTPPort))
+	}
+
 	if opts.ServiceMonitorConfig != nil {
 		smLabels := manifests.MergeMaps(opts.ServiceMonitorConfig.Labels, objectMetaLabels)
-		objs = append(objs, manifests.BuildServiceMonitor(name, opts.Namespace, selectorLabels, smLabels, serviceMonitorOpts(opts.ServiceMonitorConfig)))
+		objs = append(objs, manifests.BuildServiceMonitor(name, opts.Namespace, selectorLabels, smLabels, serviceMonitorOpts(opts.ServiceMonitorConfig, opts.EnableMetricsService)))
 	}
 	return objs
 }
@@ -91,8 +96,7 @@ func (opts Options) Valid() error {
 }
 
 func (opts Options) GetGeneratedResourceName() string {
-	name := fmt.Sprintf("%s-%s", Name, opts.Owner)
-	return manifests.ValidateAndSanitizeResourceName(name)
+	return manifests.ValidateAndSanitizeResourceNameToLength(manifests.GeneratedName(Name, opts.Owner), manifests.StatefulSetNameMaxLength)
 }
 
 const (
@@ -165,6 +169,9 @@ func newRulerStatefulSet(opts Options, selectorLabels, objectMetaLabels map[stri
 					"ALL",
 				},
 			},
+			SeccompProfile: &corev1.SeccompProfile{
+				Type: corev1.SeccompProfileTypeRuntimeDefault,
+			},
 		},
 		ReadinessProbe: &corev1.Probe{
 			ProbeHandler: corev1.ProbeHandler{
@@ -193,7 +200,7 @@ func newRulerStatefulSet(opts Options, selectorLabels, objectMetaLabels map[stri
 			SuccessThreshold:    1,
 			FailureThreshold:    4,
 		},
-		Env: []corev1.EnvVar{
+		Env: append([]corev1.EnvVar{
 			{
 				Name: "NAME",
 				ValueFrom: &corev1.EnvVarSource{
@@ -202,19 +209,7 @@ func newRulerStatefulSet(opts Options, selectorLabels, objectMetaLabels map[stri
 					},
 				},
 			},
-			{
-				Name: rulerObjectStoreEnvVarName,
-				ValueFrom: &corev1.EnvVarSource{
-					SecretKeyRef: &corev1.SecretKeySelector{
-						LocalObjectReference: corev1.LocalObjectReference{
-							Name: opts.ObjStoreSecret.Name,
-						},
-						Key:      opts.ObjStoreSecret.Key,
-						Optional: ptr.To(false),
-					},
-				},
-			},
-		},
+		}, opts.ObjStoreConfig.EnvVars(rulerObjectStoreEnvVarName, opts.ObjStoreSecret)...),
 		VolumeMounts: volumeMounts,
 		Ports: []corev1.ContainerPort{
 			{
@@ -309,6 +304,7 @@ func newRulerStatefulSet(opts Options, selectorLabels, objectMetaLabels map[stri
 		},
 	}
 
+	manifests.MountObjStore(&sts.Spec.Template, opts.ObjStoreSecret, opts.ObjStoreConfig)
 	manifests.AugmentWithOptions(sts, opts.Options)
 	return sts
 }
@@ -342,7 +338,7 @@ func NewRulerService(opts Options) *corev1.Service {
 			Name:        opts.GetGeneratedResourceName(),
 			Namespace:   opts.Namespace,
 			Labels:      objectMetaLabels,
-			Annotations: opts.Annotations,
+			Annotations: opts.GetServiceAnnotations(),
 		},
 		Spec: corev1.ServiceSpec{
 			Selector:  selectorLabels,
@@ -379,7 +375,7 @@ func newRulerService(opts Options, selectorLabels, objectMetaLabels map[string]s
 			Name:        opts.GetGeneratedResourceName(),
 			Namespace:   opts.Namespace,
 			Labels:      objectMetaLabels,
-			Annotations: opts.Annotations,
+			Annotations: opts.GetServiceAnnotations(),
 		},
 		Spec: corev1.ServiceSpec{
 			Selector:  selectorLabels,
@@ -397,7 +393,7 @@ func rulerArgs(opts Options) []string {
 		fmt.Sprintf("--grpc-address=0.0.0.0:%d", GRPCPort),
 		fmt.Sprintf("--tsdb.retention=%s", string(opts.Retention)),
 		fmt.Sprintf("--data-dir=%s", dataVolumeMountPath),
-		fmt.Sprintf("--objstore.config=$(%s)", rulerObjectStoreEnvVarName),
+		opts.ObjStoreConfig.Flag(rulerObjectStoreEnvVarName),
 		fmt.Sprintf("--alertmanagers.url=%s", opts.AlertmanagerURL),
 	)
 
@@ -414,7 +410,7 @@ func rulerArgs(opts Options) []string {
 	}
 
 	for _, endpoint := range opts.Endpoints {
-		args = append(args, fmt.Sprintf("--query=dnssrv+_http._tcp.%s.%s.svc", endpoint.ServiceName, endpoint.Namespace))
+		args = append(args, fmt.Sprintf("--query=dnssrv+_http._tcp.%s", manifests.ServiceHost(endpoint.ServiceName, endpoint.Namespace)))
 	}
 
 	for _, label := range opts.AlertLabelDrop {
@@ -447,10 +443,11 @@ func GetLabels(opts Options) map[string]string {
 	return manifests.SanitizeStoreAPIEndpointLabels(manifests.MergeMaps(lbls, manifestsstore.GetRequiredStoreServiceLabel()))
 }
 
-func serviceMonitorOpts(from *manifests.ServiceMonitorConfig) manifests.ServiceMonitorOptions {
+func serviceMonitorOpts(from *manifests.ServiceMonitorConfig, metricsService bool) manifests.ServiceMonitorOptions {
 	return manifests.ServiceMonitorOptions{
-		Port:     ptr.To(HTTPPortName),
-		Interval: from.Interval,
+		Port:           ptr.To(HTTPPortName),
+		Interval:       from.Interval,
+		MetricsService: metricsService,
 	}
 }
 
@@ -595,6 +592,9 @@ func buildConfigReloaderContainer(opts Options) corev1.Container {
 				},
 			},
 			ReadOnlyRootFilesystem: ptr.To(true),
+			SeccompProfile: &corev1.SeccompProfile{
+				Type: corev1.SeccompProfileTypeRuntimeDefault,
+			},
 		},
 		Args:         args,
 		VolumeMounts: volumeMounts,
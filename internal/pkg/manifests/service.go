@@ -0,0 +1,75 @@
+package manifests
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ExternalDNSHostnameAnnotation is the annotation from which ExternalDNS reads the DNS name to publish for a Service.
+const ExternalDNSHostnameAnnotation = "external-dns.alpha.kubernetes.io/hostname"
+
+// ServiceOptions configures how the Service of a component is exposed.
+type ServiceOptions struct {
+	// Type is the type of the Service. The Service is left as built if empty.
+	Type corev1.ServiceType
+	// Annotations are added to the Service.
+	Annotations map[string]string
+	// Ports overrides the ports of the Service by name.
+	Ports []ServicePortOptions
+	// LoadBalancerSourceRanges restricts the client IP ranges allowed to reach a LoadBalancer Service.
+	LoadBalancerSourceRanges []string
+	// ExternalTrafficPolicy is the external traffic policy of NodePort and LoadBalancer Services.
+	ExternalTrafficPolicy corev1.ServiceExternalTrafficPolicy
+	// Hostname is the external DNS name published by ExternalDNS for the Service. No name is published if empty.
+	Hostname string
+}
+
+// ServicePortOptions overrides a port of a Service.
+type ServicePortOptions struct {
+	// Name is the name of the port to override.
+	Name string
+	// Port is the port exposed by the Service.
+	Port int32
+	// NodePort is the port exposed on each node. Allocated by Kubernetes if zero.
+	NodePort int32
+}
+
+// ApplyServiceOptions applies the options to the Service. Overrides of ports the Service does not have are ignored.
+// The target ports are left unchanged, so that the Service forwards the overridden ports to the container ports.
+func ApplyServiceOptions(svc *corev1.Service, opts *ServiceOptions) {
+	if opts == nil {
+		return
+	}
+	if opts.Type != "" {
+		svc.Spec.Type = opts.Type
+	}
+	if len(opts.Annotations) > 0 {
+		svc.Annotations = MergeMaps(svc.Annotations, opts.Annotations)
+	}
+	if opts.Hostname != "" {
+		svc.Annotations = MergeMaps(svc.Annotations, map[string]string{ExternalDNSHostnameAnnotation: opts.Hostname})
+	}
+	for _, override := range opts.Ports {
+		for i := range svc.Spec.Ports {
+			if svc.Spec.Ports[i].Name != override.Name {
+				continue
+			}
+			svc.Spec.Ports[i].Port = override.Port
+			svc.Spec.Ports[i].NodePort = override.NodePort
+		}
+	}
+	svc.Spec.LoadBalancerSourceRanges = opts.LoadBalancerSourceRanges
+	svc.Spec.ExternalTrafficPolicy = opts.ExternalTrafficPolicy
+}
+
+// PortOr returns the port the Service exposes for the named port, or def if it is not overridden.
+func (opts *ServiceOptions) PortOr(name string, def int32) int32 {
+	if opts == nil {
+		return def
+	}
+	for _, p := range opts.Ports {
+		if p.Name == name {
+			return p.Port
+		}
+	}
+	return def
+}
@@ -23,10 +23,16 @@ type ServiceMonitorOptions struct {
 	// Path is the path on the target service to scrape for metrics.
 	// Defaults to "/metrics" if not specified.
 	Path *string
+	// MetricsService configures the ServiceMonitor to scrape the dedicated metrics Service
+	// built by BuildMetricsService on MetricsPortName, instead of the component Service.
+	MetricsService bool
 }
 
 func BuildServiceMonitor(name, namespace string, objectMetaLabels, selectorLabels map[string]string, opts ServiceMonitorOptions) *monitoringv1.ServiceMonitor {
 	opts = opts.applyDefaults()
+	if opts.MetricsService {
+		selectorLabels = MetricsServiceLabels(selectorLabels)
+	}
 
 	endpoint := monitoringv1.Endpoint{
 		Port: *opts.Port,
@@ -60,6 +66,9 @@ func BuildServiceMonitor(name, namespace string, objectMetaLabels, selectorLabel
 }
 
 func (opts ServiceMonitorOptions) applyDefaults() ServiceMonitorOptions {
+	if opts.MetricsService {
+		opts.Port = ptr.To(MetricsPortName)
+	}
 	if opts.Port == nil {
 		opts.Port = ptr.To("http")
 	}
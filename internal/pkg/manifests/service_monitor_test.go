@@ -38,6 +38,14 @@ func TestBuildServiceMonitor(t *testing.T) {
 				Interval: ptr.To(Duration("60s")),
 			},
 		},
+		{
+			name:   "test service monitor targeting the metrics service",
+			golden: "servicemonitor-metrics-service.golden.yaml",
+			opts: ServiceMonitorOptions{
+				Port:           ptr.To("http"),
+				MetricsService: true,
+			},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			sm := BuildServiceMonitor(name, ns, randObjMeta, randSelectorLabels, tc.opts)
@@ -0,0 +1,60 @@
+package manifests
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestApplyServiceOptions(t *testing.T) {
+	newService := func() *corev1.Service {
+		return &corev1.Service{Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{Name: "http", Port: 10902, TargetPort: intstr.FromInt32(10902)},
+				{Name: "remote-write", Port: 19291, TargetPort: intstr.FromInt32(19291)},
+			},
+		}}
+	}
+
+	svc := newService()
+	ApplyServiceOptions(svc, nil)
+	if svc.Spec.Type != "" || svc.Spec.Ports[1].Port != 19291 {
+		t.Errorf("expected the Service to be left unchanged without options, got %+v", svc.Spec)
+	}
+
+	svc = newService()
+	ApplyServiceOptions(svc, &ServiceOptions{
+		Type:        corev1.ServiceTypeLoadBalancer,
+		Annotations: map[string]string{"service.beta.kubernetes.io/aws-load-balancer-type": "nlb"},
+		Ports: []ServicePortOptions{
+			{Name: "remote-write", Port: 443, NodePort: 30443},
+			{Name: "unknown", Port: 8080},
+		},
+		LoadBalancerSourceRanges: []string{"10.0.0.0/8"},
+		ExternalTrafficPolicy:    corev1.ServiceExternalTrafficPolicyLocal,
+		Hostname:                 "remote-write.example.com",
+	})
+	if svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		t.Errorf("expected a LoadBalancer Service, got %s", svc.Spec.Type)
+	}
+	if svc.Annotations["service.beta.kubernetes.io/aws-load-balancer-type"] != "nlb" {
+		t.Errorf("expected the annotations to be added, got %v", svc.Annotations)
+	}
+	if svc.Annotations[ExternalDNSHostnameAnnotation] != "remote-write.example.com" {
+		t.Errorf("expected the hostname to be published by ExternalDNS, got %v", svc.Annotations)
+	}
+	if len(svc.Spec.Ports) != 2 {
+		t.Fatalf("expected overrides of unknown ports to be ignored, got %+v", svc.Spec.Ports)
+	}
+	rw := svc.Spec.Ports[1]
+	if rw.Port != 443 || rw.NodePort != 30443 || rw.TargetPort != intstr.FromInt32(19291) {
+		t.Errorf("expected port 443 on node port 30443 forwarding to 19291, got %+v", rw)
+	}
+	if svc.Spec.Ports[0].Port != 10902 {
+		t.Errorf("expected the http port to be left unchanged, got %d", svc.Spec.Ports[0].Port)
+	}
+	if svc.Spec.ExternalTrafficPolicy != corev1.ServiceExternalTrafficPolicyLocal || len(svc.Spec.LoadBalancerSourceRanges) != 1 {
+		t.Errorf("expected the load balancer settings to be set, got %+v", svc.Spec)
+	}
+}
@@ -38,6 +38,7 @@ type Options struct {
 	manifests.Options
 	StorageConfig            manifests.StorageConfig
 	ObjStoreSecret           corev1.SecretKeySelector
+	ObjStoreConfig           manifests.ObjStoreConfig
 	IndexCacheConfig         manifests.CacheConfig
 	CachingBucketConfig      manifests.CacheConfig
 	IgnoreDeletionMarksDelay manifests.Duration
@@ -62,7 +63,7 @@ func (opts Options) Build() []client.Object {
 	objectMetaLabels := GetLabels(opts)
 	name := opts.GetGeneratedResourceName()
 
-	objs = append(objs, manifests.BuildServiceAccount(name, opts.Namespace, selectorLabels, opts.Annotations))
+	objs = append(objs, manifests.BuildServiceAccount(name, opts.Namespace, selectorLabels, manifests.MergeMaps(opts.Annotations, opts.ServiceAccountAnnotations)))
 	objs = append(objs, newStoreService(opts, selectorLabels, objectMetaLabels))
 	objs = append(objs, newStoreShardStatefulSet(opts, selectorLabels, objectMetaLabels))
 
@@ -70,8 +71,12 @@ func (opts Options) Build() []client.Object {
 		objs = append(objs, manifests.NewPodDisruptionBudget(name, opts.Namespace, selectorLabels, objectMetaLabels, opts.Annotations, *opts.PodDisruptionConfig))
 	}
 
+	if opts.EnableMetricsService {
+		objs = append(objs, manifests.BuildMetricsService(name, opts.Namespace, objectMetaLabels, selectorLabels, opts.Annotations, HTTPPort))
+	}
+
 	if opts.ServiceMonitorConfig != nil {
-		objs = append(objs, manifests.BuildServiceMonitor(name, opts.Namespace, objectMetaLabels, selectorLabels, serviceMonitorOpts(opts.ServiceMonitorConfig)))
+		objs = append(objs, manifests.BuildServiceMonitor(name, opts.Namespace, objectMetaLabels, selectorLabels, serviceMonitorOpts(opts.ServiceMonitorConfig, opts.EnableMetricsService)))
 	}
 	return objs
 }
@@ -86,11 +91,11 @@ func (opts Options) Valid() error {
 // GetGeneratedResourceName returns the name of the Thanos Store component.
 // If a shard index is provided, the name will be suffixed with the shard index.
 func (opts Options) GetGeneratedResourceName() string {
-	name := fmt.Sprintf("%s-%s", Name, opts.Owner)
 	if opts.ShardIndex == nil {
-		return manifests.ValidateAndSanitizeResourceName(name)
+		return manifests.ValidateAndSanitizeResourceNameToLength(manifests.GeneratedName(Name, opts.Owner), manifests.StatefulSetNameMaxLength)
 	}
-	return manifests.ValidateAndSanitizeResourceName(fmt.Sprintf("%s-shard-%d", name, *opts.ShardIndex))
+	name := manifests.GeneratedName(Name, opts.Owner, fmt.Sprintf("shard-%d", *opts.ShardIndex))
+	return manifests.ValidateAndSanitizeResourceNameToLength(name, manifests.StatefulSetNameMaxLength)
 }
 
 const (
@@ -132,20 +137,7 @@ func newStoreShardStatefulSet(opts Options, selectorLabels, objectMetaLabels map
 		},
 	}
 
-	envVars := []corev1.EnvVar{
-		{
-			Name: storeObjectStoreEnvVarName,
-			ValueFrom: &corev1.EnvVarSource{
-				SecretKeyRef: &corev1.SecretKeySelector{
-					LocalObjectReference: corev1.LocalObjectReference{
-						Name: opts.ObjStoreSecret.Name,
-					},
-					Key:      opts.ObjStoreSecret.Key,
-					Optional: ptr.To(false),
-				},
-			},
-		},
-	}
+	envVars := opts.ObjStoreConfig.EnvVars(storeObjectStoreEnvVarName, opts.ObjStoreSecret)
 
 	if opts.IndexCacheConfig.FromSecret != nil {
 		indexCacheEnv := corev1.EnvVar{
@@ -219,6 +211,9 @@ func newStoreShardStatefulSet(opts Options, selectorLabels, objectMetaLabels map
 										"ALL",
 									},
 								},
+								SeccompProfile: &corev1.SeccompProfile{
+									Type: corev1.SeccompProfileTypeRuntimeDefault,
+								},
 							},
 							ReadinessProbe: &corev1.Probe{
 								ProbeHandler: corev1.ProbeHandler{
@@ -272,6 +267,7 @@ func newStoreShardStatefulSet(opts Options, selectorLabels, objectMetaLabels map
 			},
 		},
 	}
+	manifests.MountObjStore(&sts.Spec.Template, opts.ObjStoreSecret, opts.ObjStoreConfig)
 	manifests.AugmentWithOptions(sts, opts.Options)
 	return sts
 }
@@ -315,7 +311,7 @@ func newService(opts Options, selectorLabels, objectMetaLabels map[string]string
 			Name:        opts.GetGeneratedResourceName(),
 			Namespace:   opts.Namespace,
 			Labels:      objectMetaLabels,
-			Annotations: opts.Annotations,
+			Annotations: opts.GetServiceAnnotations(),
 		},
 		Spec: corev1.ServiceSpec{
 			Selector: selectorLabels,
@@ -331,7 +327,7 @@ func storeArgsFrom(opts Options) []string {
 	args = append(args,
 		fmt.Sprintf("--grpc-address=0.0.0.0:%d", GRPCPort),
 		fmt.Sprintf("--http-address=0.0.0.0:%d", HTTPPort),
-		fmt.Sprintf("--objstore.config=$(%s)", storeObjectStoreEnvVarName),
+		opts.ObjStoreConfig.Flag(storeObjectStoreEnvVarName),
 		fmt.Sprintf("--data-dir=%s", dataVolumeMountPath),
 		fmt.Sprintf("--ignore-deletion-marks-delay=%s", string(opts.IgnoreDeletionMarksDelay)),
 		fmt.Sprintf("--min-time=%s", string(opts.Min)),
@@ -355,12 +351,16 @@ func storeArgsFrom(opts Options) []string {
 		args = append(args, fmt.Sprintf("--index-cache.config=$(%s)", indexCacheConfigEnvVarName))
 	} else if opts.IndexCacheConfig.InMemoryCacheConfig != nil {
 		args = append(args, fmt.Sprintf("--index-cache.config=%s", opts.IndexCacheConfig.InMemoryCacheConfig.String()))
+	} else if opts.IndexCacheConfig.Memcached != nil {
+		args = append(args, fmt.Sprintf("--index-cache.config=%s", opts.IndexCacheConfig.Memcached.String()))
 	}
 
 	if opts.CachingBucketConfig.FromSecret != nil {
 		args = append(args, fmt.Sprintf("--store.caching-bucket.config=$(%s)", cachingBucketConfigEnvVarName))
 	} else if opts.CachingBucketConfig.InMemoryCacheConfig != nil {
 		args = append(args, fmt.Sprintf("--store.caching-bucket.config=%s", opts.CachingBucketConfig.InMemoryCacheConfig.String()))
+	} else if opts.CachingBucketConfig.Memcached != nil {
+		args = append(args, fmt.Sprintf("--store.caching-bucket.config=%s", opts.CachingBucketConfig.Memcached.String()))
 	}
 
 	if len(opts.RelabelConfigs) > 0 {
@@ -435,9 +435,10 @@ func GetLabels(opts Options) map[string]string {
 	return manifests.SanitizeStoreAPIEndpointLabels(lbls)
 }
 
-func serviceMonitorOpts(from *manifests.ServiceMonitorConfig) manifests.ServiceMonitorOptions {
+func serviceMonitorOpts(from *manifests.ServiceMonitorConfig, metricsService bool) manifests.ServiceMonitorOptions {
 	return manifests.ServiceMonitorOptions{
-		Port:     ptr.To(HTTPPortName),
-		Interval: from.Interval,
+		Port:           ptr.To(HTTPPortName),
+		Interval:       from.Interval,
+		MetricsService: metricsService,
 	}
 }
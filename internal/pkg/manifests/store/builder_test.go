@@ -3,6 +3,7 @@ package store
 import (
 	"testing"
 
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"gotest.tools/v3/assert"
 
 	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
@@ -59,6 +60,51 @@ func TestBuildStore(t *testing.T) {
 	utils.ValidateObjectLabelsEqual(t, wantLabels, []client.Object{objs[1], objs[2]}...)
 }
 
+func TestBuildStoreWithMetricsService(t *testing.T) {
+	opts := Options{
+		Options: manifests.Options{
+			Namespace:            "ns",
+			Owner:                "any",
+			EnableMetricsService: true,
+			ServiceMonitorConfig: &manifests.ServiceMonitorConfig{},
+		},
+	}
+
+	objs := opts.Build()
+	if len(objs) != 5 {
+		t.Fatalf("expected 5 objects, got %d", len(objs))
+	}
+
+	svc, ok := objs[3].(*corev1.Service)
+	if !ok {
+		t.Fatalf("expected metrics service, got %T", objs[3])
+	}
+	assert.Equal(t, svc.GetName(), manifests.MetricsServiceName(opts.GetGeneratedResourceName()))
+	assert.DeepEqual(t, svc.Spec.Selector, opts.GetSelectorLabels())
+	assert.Equal(t, len(svc.Spec.Ports), 1)
+	assert.Equal(t, svc.Spec.Ports[0].Name, manifests.MetricsPortName)
+	assert.Equal(t, svc.Spec.Ports[0].Port, int32(HTTPPort))
+
+	// the metrics service must not be discovered as a StoreAPI
+	_, ok = svc.GetLabels()[manifests.DefaultStoreAPILabel]
+	assert.Assert(t, !ok)
+
+	sm, ok := objs[4].(*monitoringv1.ServiceMonitor)
+	if !ok {
+		t.Fatalf("expected service monitor, got %T", objs[4])
+	}
+	assert.Equal(t, sm.Spec.Endpoints[0].Port, manifests.MetricsPortName)
+	for k, v := range sm.Spec.Selector.MatchLabels {
+		assert.Equal(t, svc.GetLabels()[k], v)
+	}
+	for k, v := range sm.Spec.Selector.MatchLabels {
+		if objs[1].GetLabels()[k] != v {
+			return
+		}
+	}
+	t.Error("expected the service monitor not to select the store service")
+}
+
 func TestNewStoreStatefulSet(t *testing.T) {
 	const (
 		owner = "test"
@@ -138,6 +184,24 @@ func TestNewStoreStatefulSet(t *testing.T) {
 				return opts
 			},
 		},
+		{
+			name:   "test objstore config from file with env projection",
+			golden: "statefulset-with-objstore-file.golden.yaml",
+			opts: func() Options {
+				opts := buildDefaultOpts()
+				opts.ObjStoreSecret = corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "objstore"},
+					Key:                  "thanos.yaml",
+				}
+				opts.ObjStoreConfig = manifests.ObjStoreConfig{
+					FromFile: true,
+					Env: []manifests.ObjStoreEnvVar{
+						{Name: "AZURE_CLIENT_ID", Key: "client-id"},
+					},
+				}
+				return opts
+			},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			builtOpts := tc.opts()
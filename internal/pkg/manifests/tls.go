@@ -0,0 +1,216 @@
+package manifests
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	tlsVolumeNamePrefix = "tls-"
+	tlsMountPath        = "/etc/thanos/tls/"
+	tlsCertKey          = corev1.TLSCertKey
+	tlsPrivateKeyKey    = corev1.TLSPrivateKeyKey
+	tlsClientCAKey      = "ca.crt"
+	clientCASuffix      = "-client-ca"
+	grpcClientTLSName   = "grpc-client"
+	serverCASuffix      = "-server-ca"
+
+	// GRPCTLSServerName is the name of the gRPC server of a Thanos component, used for its TLS mount paths.
+	GRPCTLSServerName = "grpc"
+	// GRPCClientCAHashAnnotation is the pod template annotation used to trigger a rollout
+	// when the CA used by the gRPC client to verify servers changes.
+	GRPCClientCAHashAnnotation = "operator.thanos.io/" + grpcClientTLSName + serverCASuffix + "-hash"
+)
+
+// TLSConfig holds the TLS configuration for a server exposed by a Thanos component.
+type TLSConfig struct {
+	// CertSecret is the name of the Secret holding the tls.crt and tls.key keys.
+	CertSecret string
+	// ClientCA is the reference to the CA used to verify client certificates.
+	ClientCA *corev1.SecretKeySelector
+	// ClientCAHash is a hash of the client CA contents.
+	// Thanos only reads the client CA at startup, so a change in the hash triggers a rollout.
+	ClientCAHash string
+}
+
+// ClientCAHashAnnotation returns the pod template annotation used to trigger a rollout
+// when the client CA of the named server changes.
+func ClientCAHashAnnotation(server string) string {
+	return "operator.thanos.io/" + server + clientCASuffix + "-hash"
+}
+
+// CertFile returns the path of the certificate for the named server.
+func (c TLSConfig) CertFile(server string) string {
+	return tlsMountPath + server + "/" + tlsCertKey
+}
+
+// KeyFile returns the path of the private key for the named server.
+func (c TLSConfig) KeyFile(server string) string {
+	return tlsMountPath + server + "/" + tlsPrivateKeyKey
+}
+
+// ClientCAFile returns the path of the client CA for the named server.
+// It returns an empty string if no client CA is configured.
+func (c TLSConfig) ClientCAFile(server string) string {
+	if c.ClientCA == nil {
+		return ""
+	}
+	return tlsMountPath + server + clientCASuffix + "/" + tlsClientCAKey
+}
+
+// MountTLS mounts the Secrets referenced by the TLSConfig for the named server into the first container
+// of the pod template. Secrets are mounted without subPath so that rotated certificates are propagated
+// to the running pods.
+func MountTLS(pt *corev1.PodTemplateSpec, server string, c TLSConfig) {
+	name := tlsVolumeNamePrefix + server
+	pt.Spec.Containers[0].VolumeMounts = append(pt.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      name,
+		ReadOnly:  true,
+		MountPath: tlsMountPath + server,
+	})
+	pt.Spec.Volumes = append(pt.Spec.Volumes, corev1.Volume{
+		Name: name,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: c.CertSecret,
+			},
+		},
+	})
+
+	if c.ClientCA == nil {
+		return
+	}
+
+	caName := name + clientCASuffix
+	pt.Spec.Containers[0].VolumeMounts = append(pt.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      caName,
+		ReadOnly:  true,
+		MountPath: tlsMountPath + server + clientCASuffix,
+	})
+	pt.Spec.Volumes = append(pt.Spec.Volumes, corev1.Volume{
+		Name: caName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: c.ClientCA.Name,
+				Items: []corev1.KeyToPath{
+					{
+						Key:  c.ClientCA.Key,
+						Path: tlsClientCAKey,
+					},
+				},
+			},
+		},
+	})
+
+	if c.ClientCAHash != "" {
+		if pt.Annotations == nil {
+			pt.Annotations = make(map[string]string)
+		}
+		pt.Annotations[ClientCAHashAnnotation(server)] = c.ClientCAHash
+	}
+}
+
+// GRPCServerTLSArgs returns the flags configuring TLS for the gRPC server of a Thanos component.
+func GRPCServerTLSArgs(c TLSConfig) []string {
+	return []string{
+		"--grpc-server-tls-cert=" + c.CertFile(GRPCTLSServerName),
+		"--grpc-server-tls-key=" + c.KeyFile(GRPCTLSServerName),
+		"--grpc-server-tls-client-ca=" + c.ClientCAFile(GRPCTLSServerName),
+	}
+}
+
+// GRPCClientTLSConfig holds the TLS configuration for the gRPC connections of a Thanos component to its peers.
+type GRPCClientTLSConfig struct {
+	// CertSecret is the name of the Secret holding the tls.crt and tls.key keys of the client certificate.
+	CertSecret string
+	// CA is the reference to the CA used to verify server certificates.
+	CA *corev1.SecretKeySelector
+	// CAHash is a hash of the CA contents.
+	// Thanos only reads the CA at startup, so a change in the hash triggers a rollout.
+	CAHash string
+	// ServerName is the name used to verify server certificates.
+	ServerName string
+}
+
+// GRPCClientTLSFromSecret returns the TLS configuration reading the client certificate and the CA verifying
+// the servers from a single Secret, such as a Secret issued by cert-manager.
+func GRPCClientTLSFromSecret(name string) *GRPCClientTLSConfig {
+	return &GRPCClientTLSConfig{
+		CertSecret: name,
+		CA: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			Key:                  tlsClientCAKey,
+		},
+	}
+}
+
+// GRPCClientTLSArgs returns the flags enabling TLS for the gRPC client of a Thanos component.
+// The prefix is the prefix of the client flags, such as --grpc-client or --remote-write.client.
+func GRPCClientTLSArgs(prefix string, c GRPCClientTLSConfig) []string {
+	args := []string{prefix + "-tls-secure"}
+	if c.CertSecret != "" {
+		args = append(args,
+			prefix+"-tls-cert="+tlsMountPath+grpcClientTLSName+"/"+tlsCertKey,
+			prefix+"-tls-key="+tlsMountPath+grpcClientTLSName+"/"+tlsPrivateKeyKey,
+		)
+	}
+	if c.CA != nil {
+		args = append(args, prefix+"-tls-ca="+tlsMountPath+grpcClientTLSName+serverCASuffix+"/"+tlsClientCAKey)
+	}
+	if c.ServerName != "" {
+		args = append(args, prefix+"-server-name="+c.ServerName)
+	}
+	return args
+}
+
+// MountGRPCClientTLS mounts the Secrets referenced by the GRPCClientTLSConfig into the first container
+// of the pod template.
+func MountGRPCClientTLS(pt *corev1.PodTemplateSpec, c GRPCClientTLSConfig) {
+	if c.CertSecret != "" {
+		name := tlsVolumeNamePrefix + grpcClientTLSName
+		pt.Spec.Containers[0].VolumeMounts = append(pt.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      name,
+			ReadOnly:  true,
+			MountPath: tlsMountPath + grpcClientTLSName,
+		})
+		pt.Spec.Volumes = append(pt.Spec.Volumes, corev1.Volume{
+			Name: name,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: c.CertSecret,
+				},
+			},
+		})
+	}
+
+	if c.CA == nil {
+		return
+	}
+
+	caName := tlsVolumeNamePrefix + grpcClientTLSName + serverCASuffix
+	pt.Spec.Containers[0].VolumeMounts = append(pt.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      caName,
+		ReadOnly:  true,
+		MountPath: tlsMountPath + grpcClientTLSName + serverCASuffix,
+	})
+	pt.Spec.Volumes = append(pt.Spec.Volumes, corev1.Volume{
+		Name: caName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: c.CA.Name,
+				Items: []corev1.KeyToPath{
+					{
+						Key:  c.CA.Key,
+						Path: tlsClientCAKey,
+					},
+				},
+			},
+		},
+	})
+
+	if c.CAHash != "" {
+		if pt.Annotations == nil {
+			pt.Annotations = make(map[string]string)
+		}
+		pt.Annotations[GRPCClientCAHashAnnotation] = c.CAHash
+	}
+}
@@ -0,0 +1,68 @@
+package manifests
+
+import (
+	"encoding/json"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// TracingTypeOTLP and TracingTypeJaeger are the tracing providers that can be configured with TracingOptions.
+	TracingTypeOTLP   = "OTLP"
+	TracingTypeJaeger = "JAEGER"
+)
+
+// TracingOptions configures the tracing provider of a component.
+type TracingOptions struct {
+	// Type is the tracing provider, TracingTypeOTLP or TracingTypeJaeger. Defaults to TracingTypeOTLP.
+	Type string
+	// Endpoint is the address of the collector receiving the spans.
+	Endpoint string
+	// Insecure disables TLS for the connection to an OTLP collector.
+	Insecure bool
+	// SamplingRatio is the ratio of traces that are sampled, between 0 and 1.
+	// If empty, all traces are sampled.
+	SamplingRatio string
+}
+
+// tracingConfig is the tracing configuration format read by Thanos.
+type tracingConfig struct {
+	Type   string `json:"type"`
+	Config any    `json:"config"`
+}
+
+type otlpTracingConfig struct {
+	ClientType   string `json:"client_type"` //nolint:tagliatelle // client_type is from thanos config
+	Endpoint     string `json:"endpoint"`
+	Insecure     bool   `json:"insecure,omitempty"`
+	SamplerType  string `json:"sampler_type,omitempty"`  //nolint:tagliatelle // sampler_type is from thanos config
+	SamplerParam string `json:"sampler_param,omitempty"` //nolint:tagliatelle // sampler_param is from thanos config
+}
+
+type jaegerTracingConfig struct {
+	Endpoint     string      `json:"endpoint"`
+	SamplerType  string      `json:"sampler_type"`  //nolint:tagliatelle // sampler_type is from thanos config
+	SamplerParam json.Number `json:"sampler_param"` //nolint:tagliatelle // sampler_param is from thanos config
+}
+
+// String renders the tracing configuration of Thanos.
+func (opts TracingOptions) String() string {
+	config := tracingConfig{Type: TracingTypeOTLP}
+	switch opts.Type {
+	case TracingTypeJaeger:
+		jaeger := jaegerTracingConfig{Endpoint: opts.Endpoint, SamplerType: "const", SamplerParam: "1"}
+		if opts.SamplingRatio != "" {
+			jaeger.SamplerType, jaeger.SamplerParam = "probabilistic", json.Number(opts.SamplingRatio)
+		}
+		config = tracingConfig{Type: TracingTypeJaeger, Config: jaeger}
+	default:
+		otlp := otlpTracingConfig{ClientType: "grpc", Endpoint: opts.Endpoint, Insecure: opts.Insecure}
+		if opts.SamplingRatio != "" {
+			otlp.SamplerType, otlp.SamplerParam = "traceidratiobased", opts.SamplingRatio
+		}
+		config.Config = otlp
+	}
+	// the config only holds strings, booleans and numbers validated by the API, which always marshal
+	b, _ := yaml.Marshal(config)
+	return string(b)
+}
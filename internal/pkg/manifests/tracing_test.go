@@ -0,0 +1,50 @@
+package manifests
+
+import (
+	"testing"
+)
+
+func TestTracingOptions_String(t *testing.T) {
+	tests := []struct {
+		name string
+		opts TracingOptions
+		want string
+	}{
+		{
+			name: "otlp samples all traces by default",
+			opts: TracingOptions{Endpoint: "otel-collector:4317"},
+			want: `config:
+  client_type: grpc
+  endpoint: otel-collector:4317
+type: OTLP
+`,
+		},
+		{
+			name: "jaeger with sampling ratio",
+			opts: TracingOptions{Type: TracingTypeJaeger, Endpoint: "http://jaeger:14268/api/traces", SamplingRatio: "0.25"},
+			want: `config:
+  endpoint: http://jaeger:14268/api/traces
+  sampler_param: 0.25
+  sampler_type: probabilistic
+type: JAEGER
+`,
+		},
+		{
+			name: "jaeger samples all traces by default",
+			opts: TracingOptions{Type: TracingTypeJaeger, Endpoint: "http://jaeger:14268/api/traces"},
+			want: `config:
+  endpoint: http://jaeger:14268/api/traces
+  sampler_param: 1
+  sampler_type: const
+type: JAEGER
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.String(); got != tt.want {
+				t.Errorf("TracingOptions.String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
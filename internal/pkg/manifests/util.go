@@ -2,6 +2,7 @@ package manifests
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -186,6 +187,8 @@ func extractFlag(arg string) string {
 
 // IsGrpcServiceWithLabels returns true if the given object is a gRPC service with required labels.
 // The requiredLabels map is used to match the labels of the object.
+// The gRPC port is the port named "grpc", unless the GRPCPortAnnotation of the service names another port
+// by name or by number.
 // The function returns false if the object is not a service or if it does not have a gRPC port.
 // The function returns true, alongside the port if the object is a service with a gRPC port and has the required labels.
 func IsGrpcServiceWithLabels(obj client.Object, requiredLabels map[string]string) (int32, bool) {
@@ -198,8 +201,13 @@ func IsGrpcServiceWithLabels(obj client.Object, requiredLabels map[string]string
 		return 0, false
 	}
 
+	name := "grpc"
+	if annotated, ok := svc.GetAnnotations()[GRPCPortAnnotation]; ok {
+		name = annotated
+	}
+	number, err := strconv.ParseInt(name, 10, 32)
 	for _, port := range svc.Spec.Ports {
-		if port.Name == "grpc" {
+		if port.Name == name || (err == nil && port.Port == int32(number)) {
 			return port.Port, true
 		}
 	}
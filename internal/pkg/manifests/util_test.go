@@ -122,6 +122,23 @@ func TestIsGrpcServiceWithLabels(t *testing.T) {
 	if port != 9090 {
 		t.Errorf("expected port 9090, got %d", port)
 	}
+
+	// Test for gRPC ports named by the annotation
+	svc.Spec.Ports = []corev1.ServicePort{{Name: "http", Port: 9091}, {Name: "store", Port: 10901}, {Port: 10902}}
+	if _, ok := IsGrpcServiceWithLabels(svc, map[string]string{"app": "thanos-query"}); ok {
+		t.Errorf("expected false without a port named grpc, got true")
+	}
+	for annotation, want := range map[string]int32{"store": 10901, "10902": 10902} {
+		svc.Annotations = map[string]string{GRPCPortAnnotation: annotation}
+		port, ok := IsGrpcServiceWithLabels(svc, map[string]string{"app": "thanos-query"})
+		if !ok || port != want {
+			t.Errorf("expected port %d for annotation %q, got %d, %v", want, annotation, port, ok)
+		}
+	}
+	svc.Annotations = map[string]string{GRPCPortAnnotation: "missing"}
+	if _, ok := IsGrpcServiceWithLabels(svc, map[string]string{"app": "thanos-query"}); ok {
+		t.Errorf("expected false for an annotation naming a missing port, got true")
+	}
 }
 
 func TestHasRequiredLabels(t *testing.T) {
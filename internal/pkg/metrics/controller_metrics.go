@@ -8,23 +8,47 @@ import (
 )
 
 type CommonMetrics struct {
-	FeatureGatesInfo *prometheus.GaugeVec
-	Paused           *prometheus.GaugeVec
+	FeatureGatesInfo         *prometheus.GaugeVec
+	Paused                   *prometheus.GaugeVec
+	ReconcileDurationSeconds *prometheus.HistogramVec
+	ManagedObjects           *prometheus.GaugeVec
+	ObjectOperationsTotal    *prometheus.CounterVec
+	EndpointHealthy          *prometheus.GaugeVec
+
+	managedObjects *managedObjects
+}
+
+// managedObjects holds the names of the objects managed for each resource, by kind.
+type managedObjects struct {
+	mu    sync.Mutex
+	names map[managedObjectsKey]map[string]struct{}
+}
+
+type managedObjectsKey struct {
+	component, resource, namespace, kind string
 }
 
 type ThanosQueryMetrics struct {
 	*CommonMetrics
 	EndpointsConfigured                *prometheus.GaugeVec
 	ServiceWatchesReconciliationsTotal *prometheus.CounterVec
+	ReadProbeSuccess                   *prometheus.GaugeVec
+	ReadProbeLatencySeconds            *prometheus.GaugeVec
 }
 
 type ThanosReceiveMetrics struct {
 	*CommonMetrics
 	HashringsConfigured                 *prometheus.GaugeVec
 	HashringHash                        *prometheus.GaugeVec
+	HashringConfigVersion               *prometheus.GaugeVec
 	HashringTenantsConfigured           *prometheus.GaugeVec
 	HashringEndpointsConfigured         *prometheus.GaugeVec
+	ReplicationCapacityOK               *prometheus.GaugeVec
+	IngesterVolumeZones                 *prometheus.GaugeVec
+	UploadLagSeconds                    *prometheus.GaugeVec
 	EndpointWatchesReconciliationsTotal *prometheus.CounterVec
+	WriteProbeSuccess                   *prometheus.GaugeVec
+	WriteProbeLastSuccessTimestamp      *prometheus.GaugeVec
 }
 
 type ThanosRulerMetrics struct {
@@ -69,11 +93,63 @@ func NewCommonMetrics(reg prometheus.Registerer) *CommonMetrics {
 				Name: "thanos_operator_paused",
 				Help: "Paused state of ThanosOperator",
 			}, []string{"component", "resource", "namespace"}),
+			ReconcileDurationSeconds: promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
+				Name:    "thanos_operator_reconcile_duration_seconds",
+				Help:    "Duration of the reconciles of the resources of each component",
+				Buckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+			}, []string{"component"}),
+			ManagedObjects: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+				Name: "thanos_operator_managed_objects",
+				Help: "Number of objects of each kind managed for a resource",
+			}, []string{"component", "resource", "namespace", "kind"}),
+			ObjectOperationsTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "thanos_operator_object_operations_total",
+				Help: "Total number of objects created, updated or left unchanged when applying the objects of each component",
+			}, []string{"component", "kind", "operation"}),
+			EndpointHealthy: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+				Name: "thanos_operator_endpoint_healthy",
+				Help: "Health of the gRPC endpoints of a resource checked by the operator, 1 if serving at the last check",
+			}, []string{"component", "resource", "namespace", "endpoint"}),
+			managedObjects: &managedObjects{names: make(map[managedObjectsKey]map[string]struct{})},
 		}
 	})
 	return commonMetricsInstance
 }
 
+// AddManagedObject records that the named object of the given kind is managed for a resource of the component.
+func (m *CommonMetrics) AddManagedObject(component, resource, namespace, kind, name string) {
+	m.managedObjects.mu.Lock()
+	defer m.managedObjects.mu.Unlock()
+
+	key := managedObjectsKey{component: component, resource: resource, namespace: namespace, kind: kind}
+	names, ok := m.managedObjects.names[key]
+	if !ok {
+		names = make(map[string]struct{})
+		m.managedObjects.names[key] = names
+	}
+	names[name] = struct{}{}
+	m.ManagedObjects.WithLabelValues(component, resource, namespace, kind).Set(float64(len(names)))
+}
+
+// RemoveManagedObject records that the named object of the given kind is no longer managed for a resource of the component.
+func (m *CommonMetrics) RemoveManagedObject(component, resource, namespace, kind, name string) {
+	m.managedObjects.mu.Lock()
+	defer m.managedObjects.mu.Unlock()
+
+	key := managedObjectsKey{component: component, resource: resource, namespace: namespace, kind: kind}
+	names, ok := m.managedObjects.names[key]
+	if !ok {
+		return
+	}
+	delete(names, name)
+	if len(names) == 0 {
+		delete(m.managedObjects.names, key)
+		m.ManagedObjects.DeleteLabelValues(component, resource, namespace, kind)
+		return
+	}
+	m.ManagedObjects.WithLabelValues(component, resource, namespace, kind).Set(float64(len(names)))
+}
+
 func NewThanosQueryMetrics(reg prometheus.Registerer, commonMetrics *CommonMetrics) ThanosQueryMetrics {
 	return ThanosQueryMetrics{
 		CommonMetrics: commonMetrics,
@@ -85,6 +161,14 @@ func NewThanosQueryMetrics(reg prometheus.Registerer, commonMetrics *CommonMetri
 			Name: "thanos_operator_query_service_event_reconciliations_total",
 			Help: "Total number of reconciliations for ThanosQuery resources due to Service events",
 		}, []string{"resource", "namespace"}),
+		ReadProbeSuccess: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "thanos_operator_query_read_probe_success",
+			Help: "Whether the latest read probe of a ThanosQuery resource succeeded (1) or failed (0)",
+		}, []string{"resource", "namespace"}),
+		ReadProbeLatencySeconds: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "thanos_operator_query_read_probe_latency_seconds",
+			Help: "Latency of the queries of the latest read probe of a ThanosQuery resource",
+		}, []string{"resource", "namespace", "query_type"}),
 	}
 }
 
@@ -99,6 +183,10 @@ func NewThanosReceiveMetrics(reg prometheus.Registerer, commonMetrics *CommonMet
 			Name: "thanos_operator_receive_hashring_hash",
 			Help: "Hash of the hashrings configuration per ThanosReceive resource",
 		}, []string{"resource", "namespace"}),
+		HashringConfigVersion: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "thanos_operator_receive_hashring_config_version",
+			Help: "Version of the hashring configuration applied to the router per ThanosReceive resource",
+		}, []string{"resource", "namespace"}),
 		HashringTenantsConfigured: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
 			Name: "thanos_operator_receive_hashring_tenants_configured",
 			Help: "Number of tenants configured per distinct ThanosReceive hashring",
@@ -107,10 +195,30 @@ func NewThanosReceiveMetrics(reg prometheus.Registerer, commonMetrics *CommonMet
 			Name: "thanos_operator_receive_hashring_endpoints_configured",
 			Help: "Number of configured endpoints for each distinct ThanosReceive hashring",
 		}, []string{"resource", "namespace", "hashring"}),
+		ReplicationCapacityOK: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "thanos_operator_receive_replication_capacity_ok",
+			Help: "Whether a ThanosReceive hashring has at least as many ready ingesters as the replication factor (1) or not (0)",
+		}, []string{"resource", "namespace", "hashring"}),
+		IngesterVolumeZones: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "thanos_operator_receive_ingester_volume_zones",
+			Help: "Number of distinct zones the bound data volumes of a ThanosReceive hashring are pinned to",
+		}, []string{"resource", "namespace", "hashring"}),
+		UploadLagSeconds: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "thanos_operator_receive_upload_lag_seconds",
+			Help: "Time since the ingester of a ThanosReceive hashring that is the furthest behind last uploaded a block to object storage",
+		}, []string{"resource", "namespace", "hashring"}),
 		EndpointWatchesReconciliationsTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
 			Name: "thanos_operator_receive_endpoint_event_reconciliations_total",
 			Help: "Total number of reconciliations for ThanosReceive resources due to EndpointSlice events",
 		}, []string{"resource", "namespace"}),
+		WriteProbeSuccess: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "thanos_operator_receive_write_probe_success",
+			Help: "Whether the latest write probe of a ThanosReceive resource succeeded (1) or failed (0)",
+		}, []string{"resource", "namespace"}),
+		WriteProbeLastSuccessTimestamp: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "thanos_operator_receive_write_probe_last_success_timestamp_seconds",
+			Help: "Timestamp of the last successful write probe of a ThanosReceive resource",
+		}, []string{"resource", "namespace"}),
 	}
 }
 
@@ -0,0 +1,86 @@
+// Package multicluster resolves clients for the workload clusters in which the operator
+// creates child resources, when a resource selects a target cluster.
+package multicluster
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// KubeconfigKey is the key of the Secret holding the kubeconfig of a workload cluster.
+const KubeconfigKey = "kubeconfig"
+
+// Clusters resolves clients for the workload clusters registered with the operator.
+type Clusters struct {
+	reader  client.Reader
+	secrets map[string]types.NamespacedName
+
+	newClient func(kubeconfig []byte) (client.Client, error)
+
+	mu      sync.Mutex
+	clients map[string]cachedClient
+}
+
+type cachedClient struct {
+	hash   string
+	client client.Client
+}
+
+// NewClusters returns a Clusters that reads the kubeconfig of each workload cluster from the given Secrets.
+// The reader should not be backed by a cache, so that the operator does not need to watch all Secrets.
+func NewClusters(reader client.Reader, scheme *runtime.Scheme, secrets map[string]types.NamespacedName) *Clusters {
+	return &Clusters{
+		reader:  reader,
+		secrets: secrets,
+		newClient: func(kubeconfig []byte) (client.Client, error) {
+			cfg, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+			if err != nil {
+				return nil, err
+			}
+			return client.New(cfg, client.Options{Scheme: scheme})
+		},
+		clients: map[string]cachedClient{},
+	}
+}
+
+// Client returns a client for the workload cluster with the given name.
+// The client is rebuilt whenever the kubeconfig stored in the Secret of the cluster changes.
+func (c *Clusters) Client(ctx context.Context, name string) (client.Client, error) {
+	ref, ok := c.secrets[name]
+	if !ok {
+		return nil, fmt.Errorf("target cluster %s is not registered with the operator", name)
+	}
+
+	secret := &corev1.Secret{}
+	if err := c.reader.Get(ctx, ref, secret); err != nil {
+		return nil, fmt.Errorf("failed to get kubeconfig secret %s for target cluster %s: %w", ref.String(), name, err)
+	}
+	kubeconfig, ok := secret.Data[KubeconfigKey]
+	if !ok {
+		return nil, fmt.Errorf("key %s not found in kubeconfig secret %s for target cluster %s", KubeconfigKey, ref.String(), name)
+	}
+	hash := fmt.Sprintf("%x", sha256.Sum256(kubeconfig))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached, ok := c.clients[name]; ok && cached.hash == hash {
+		return cached.client, nil
+	}
+
+	cl, err := c.newClient(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build client for target cluster %s: %w", name, err)
+	}
+	c.clients[name] = cachedClient{hash: hash, client: cl}
+	return cl, nil
+}
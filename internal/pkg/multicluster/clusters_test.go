@@ -0,0 +1,67 @@
+package multicluster
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestClusters_Client(t *testing.T) {
+	ctx := context.Background()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "eu-1-kubeconfig", Namespace: "thanos"},
+		Data:       map[string][]byte{KubeconfigKey: []byte("v1")},
+	}
+	reader := fake.NewFakeClient(
+		secret,
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "no-key", Namespace: "thanos"}},
+	)
+
+	c := NewClusters(reader, scheme.Scheme, map[string]types.NamespacedName{
+		"eu-1":   {Namespace: "thanos", Name: "eu-1-kubeconfig"},
+		"no-key": {Namespace: "thanos", Name: "no-key"},
+		"absent": {Namespace: "thanos", Name: "absent"},
+	})
+
+	var built []string
+	c.newClient = func(kubeconfig []byte) (client.Client, error) {
+		built = append(built, string(kubeconfig))
+		return fake.NewFakeClient(), nil
+	}
+
+	first, err := c.Client(ctx, "eu-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := c.Client(ctx, "eu-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first != second || len(built) != 1 {
+		t.Errorf("expected the client to be reused while the kubeconfig is unchanged, built %d clients", len(built))
+	}
+
+	secret.Data[KubeconfigKey] = []byte("v2")
+	if err := reader.Update(ctx, secret); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := c.Client(ctx, "eu-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(built) != 2 || built[1] != "v2" {
+		t.Errorf("expected the client to be rebuilt from the rotated kubeconfig, built %v", built)
+	}
+
+	for _, name := range []string{"unknown", "no-key", "absent"} {
+		if _, err := c.Client(ctx, name); err == nil {
+			t.Errorf("expected error for target cluster %s", name)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package multicluster
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Flag implements flag.Value for repeatable workload cluster flags of the form <name>=<namespace>/<secret>.
+// The Secret must hold the kubeconfig of the workload cluster under the KubeconfigKey key.
+type Flag map[string]types.NamespacedName
+
+// String returns a comma-separated list of the registered workload clusters.
+func (f *Flag) String() string {
+	clusters := make([]string, 0, len(*f))
+	for name, secret := range *f {
+		clusters = append(clusters, fmt.Sprintf("%s=%s", name, secret.String()))
+	}
+	sort.Strings(clusters)
+	return strings.Join(clusters, ",")
+}
+
+// Set registers a workload cluster after validating the flag value.
+func (f *Flag) Set(value string) error {
+	name, ref, ok := strings.Cut(value, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("invalid target cluster %q, expected <name>=<namespace>/<secret>", value)
+	}
+	namespace, secret, ok := strings.Cut(ref, "/")
+	if !ok || namespace == "" || secret == "" {
+		return fmt.Errorf("invalid kubeconfig secret %q for target cluster %s, expected <namespace>/<secret>", ref, name)
+	}
+	if *f == nil {
+		*f = Flag{}
+	}
+	if _, exists := (*f)[name]; exists {
+		return fmt.Errorf("target cluster %s is registered more than once", name)
+	}
+	(*f)[name] = types.NamespacedName{Namespace: namespace, Name: secret}
+	return nil
+}
@@ -0,0 +1,86 @@
+package multicluster
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestFlag_Set(t *testing.T) {
+	tests := []struct {
+		name        string
+		values      []string
+		want        Flag
+		errContains string
+	}{
+		{
+			name:   "single cluster",
+			values: []string{"eu-1=thanos/eu-1-kubeconfig"},
+			want:   Flag{"eu-1": types.NamespacedName{Namespace: "thanos", Name: "eu-1-kubeconfig"}},
+		},
+		{
+			name:   "multiple clusters",
+			values: []string{"eu-1=thanos/eu-1", "us-1=thanos/us-1"},
+			want: Flag{
+				"eu-1": types.NamespacedName{Namespace: "thanos", Name: "eu-1"},
+				"us-1": types.NamespacedName{Namespace: "thanos", Name: "us-1"},
+			},
+		},
+		{
+			name:        "missing name",
+			values:      []string{"=thanos/eu-1"},
+			errContains: "invalid target cluster",
+		},
+		{
+			name:        "missing secret",
+			values:      []string{"eu-1=thanos"},
+			errContains: "invalid kubeconfig secret",
+		},
+		{
+			name:        "duplicate cluster",
+			values:      []string{"eu-1=thanos/a", "eu-1=thanos/b"},
+			errContains: "registered more than once",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Flag{}
+			var err error
+			for _, v := range tt.values {
+				if err = f.Set(v); err != nil {
+					break
+				}
+			}
+
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("expected error containing %q, got %v", tt.errContains, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(f) != len(tt.want) {
+				t.Fatalf("expected %d clusters, got %d", len(tt.want), len(f))
+			}
+			for name, secret := range tt.want {
+				if f[name] != secret {
+					t.Errorf("expected cluster %s to use secret %s, got %s", name, secret, f[name])
+				}
+			}
+		})
+	}
+}
+
+func TestFlag_String(t *testing.T) {
+	f := Flag{}
+	_ = f.Set("us-1=thanos/us-1")
+	_ = f.Set("eu-1=thanos/eu-1")
+
+	if got, want := f.String(), "eu-1=thanos/eu-1,us-1=thanos/us-1"; got != want {
+		t.Errorf("Flag.String() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,86 @@
+// Package objstore parses the object storage configuration files of Thanos, so that invalid
+// configurations are reported by the operator instead of crashlooping the Thanos components.
+package objstore
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Types are the object storage providers supported by Thanos.
+var Types = []string{"S3", "GCS", "AZURE", "SWIFT", "COS", "ALIYUNOSS", "BOS", "FILESYSTEM", "OCI", "OBS"}
+
+// requiredFields are the fields of the provider configuration without which Thanos fails to start.
+// Providers that can be configured in several ways, such as with either an endpoint or a bucket, are not listed.
+var requiredFields = map[string][]string{
+	"S3":         {"bucket"},
+	"GCS":        {"bucket"},
+	"AZURE":      {"storage_account", "container"},
+	"ALIYUNOSS":  {"bucket"},
+	"BOS":        {"bucket"},
+	"OBS":        {"bucket"},
+	"FILESYSTEM": {"directory"},
+}
+
+// Config is the object storage configuration file of Thanos.
+// See https://thanos.io/tip/thanos/storage.md/#supported-clients for the configuration of each provider.
+type Config struct {
+	Type   string         `json:"type"`
+	Config map[string]any `json:"config"`
+	Prefix string         `json:"prefix,omitempty"`
+}
+
+// Parse parses the object storage configuration file and checks it against the schema of its provider.
+func Parse(data []byte) (*Config, error) {
+	var conf Config
+	if err := yaml.UnmarshalStrict(data, &conf); err != nil {
+		return nil, err
+	}
+
+	idx := slices.IndexFunc(Types, func(t string) bool { return strings.EqualFold(conf.Type, t) })
+	if idx < 0 {
+		return nil, fmt.Errorf("unsupported object storage type %q, must be one of %s", conf.Type, strings.Join(Types, ", "))
+	}
+	conf.Type = Types[idx]
+
+	var missing []string
+	for _, field := range requiredFields[conf.Type] {
+		if value, ok := conf.Config[field]; !ok || value == nil || value == "" {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%s configuration is missing %s", conf.Type, strings.Join(missing, ", "))
+	}
+	return &conf, nil
+}
+
+// bucketFields are the fields of the provider configurations that together locate the bucket, in the order they
+// are joined in the bucket identity.
+var bucketFields = []string{"endpoint", "storage_account", "bucket", "container", "container_name", "directory"}
+
+// Bucket returns an identity of the bucket, and prefix within it, the configuration points at, so that
+// configurations of the same bucket can be told apart from configurations of different buckets regardless of their
+// credentials. It returns an empty string if the configuration does not locate a bucket.
+func (c *Config) Bucket() string {
+	parts := []string{c.Type}
+	located := false
+	for _, field := range bucketFields {
+		value, ok := c.Config[field].(string)
+		if !ok || value == "" {
+			continue
+		}
+		parts = append(parts, strings.TrimSuffix(value, "/"))
+		located = located || field != "endpoint"
+	}
+	if !located {
+		return ""
+	}
+	if prefix := strings.Trim(c.Prefix, "/"); prefix != "" {
+		parts = append(parts, prefix)
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,110 @@
+package objstore
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		data      string
+		wantType  string
+		wantError string
+	}{
+		{
+			name:     "s3",
+			data:     "type: S3\nconfig:\n  bucket: thanos\n  endpoint: s3.amazonaws.com\n",
+			wantType: "S3",
+		},
+		{
+			name:     "lowercase type",
+			data:     "type: gcs\nconfig:\n  bucket: thanos\nprefix: tenant\n",
+			wantType: "GCS",
+		},
+		{
+			name:     "provider without required fields",
+			data:     "type: COS\nconfig:\n  endpoint: https://cos.example.com\n",
+			wantType: "COS",
+		},
+		{
+			name:      "unknown type",
+			data:      "type: FTP\nconfig: {}\n",
+			wantError: `unsupported object storage type "FTP"`,
+		},
+		{
+			name:      "unknown field",
+			data:      "type: S3\nbucket: thanos\n",
+			wantError: `unknown field "bucket"`,
+		},
+		{
+			name:      "invalid yaml",
+			data:      "type: [S3",
+			wantError: "did not find expected",
+		},
+		{
+			name:      "missing bucket",
+			data:      "type: S3\nconfig:\n  endpoint: s3.amazonaws.com\n",
+			wantError: "S3 configuration is missing bucket",
+		},
+		{
+			name:      "empty required fields",
+			data:      "type: AZURE\nconfig:\n  storage_account: \"\"\n",
+			wantError: "AZURE configuration is missing storage_account, container",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			conf, err := Parse([]byte(tc.data))
+			if tc.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantError) {
+					t.Fatalf("expected error containing %q, got %v", tc.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if conf.Type != tc.wantType {
+				t.Errorf("expected type %s, got %s", tc.wantType, conf.Type)
+			}
+		})
+	}
+}
+
+func TestBucket(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "credentials are ignored",
+			data: "type: S3\nconfig:\n  bucket: thanos\n  endpoint: s3.amazonaws.com\n  access_key: a\n  secret_key: b\n",
+			want: "S3/s3.amazonaws.com/thanos",
+		},
+		{
+			name: "prefix",
+			data: "type: gcs\nconfig:\n  bucket: thanos\nprefix: /tenant/\n",
+			want: "GCS/thanos/tenant",
+		},
+		{
+			name: "azure",
+			data: "type: AZURE\nconfig:\n  storage_account: account\n  container: thanos\n",
+			want: "AZURE/account/thanos",
+		},
+		{
+			name: "endpoint only",
+			data: "type: COS\nconfig:\n  endpoint: https://cos.example.com\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			conf, err := Parse([]byte(tc.data))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := conf.Bucket(); got != tc.want {
+				t.Errorf("expected bucket %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
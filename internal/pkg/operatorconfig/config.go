@@ -0,0 +1,114 @@
+// Package operatorconfig reads the configuration file of the operator, which gathers the settings of the operator
+// in a single file that is reloaded when it changes.
+package operatorconfig
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/thanos-community/thanos-operator/internal/pkg/featuregate"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/yaml"
+)
+
+// LogLevels are the log levels of the operator and of the Thanos components.
+var LogLevels = []string{"debug", "info", "warn", "error"}
+
+// Config is the configuration file of the operator.
+// Fields that are not set keep the value of the corresponding command line flag.
+type Config struct {
+	// LogLevel is the log level of the operator, as with --log.level.
+	LogLevel string `json:"logLevel,omitempty"`
+	// EnableFeatures are the features to enable in addition to those enabled with --enable-feature.
+	EnableFeatures []string `json:"enableFeatures,omitempty"`
+	// EnableWebhooks enables the admission webhooks, as with --enable-webhooks.
+	EnableWebhooks *bool `json:"enableWebhooks,omitempty"`
+	// ResyncInterval is the interval at which resources are reconciled, as with --resync-interval.
+	ResyncInterval *metav1.Duration `json:"resyncInterval,omitempty"`
+	// PruneGracePeriod is the time orphaned resources are kept for, as with --prune-grace-period.
+	PruneGracePeriod *metav1.Duration `json:"pruneGracePeriod,omitempty"`
+	// Defaults are the defaults of the Thanos components.
+	Defaults Defaults `json:"defaults,omitempty"`
+}
+
+// Defaults are the defaults of the operator for the Thanos components, used when neither the resource nor a
+// ThanosDefaults sets the value.
+type Defaults struct {
+	// Image is the container image of the Thanos components, without a tag.
+	Image string `json:"image,omitempty"`
+	// Version is the version of Thanos, used as the tag of the container image.
+	Version string `json:"version,omitempty"`
+	// LogLevel is the log level of the Thanos components.
+	LogLevel string `json:"logLevel,omitempty"`
+}
+
+// Load reads and validates the configuration file.
+func Load(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read the configuration file: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse parses and validates the contents of the configuration file.
+func Parse(data []byte) (Config, error) {
+	var conf Config
+	if err := yaml.UnmarshalStrict(data, &conf); err != nil {
+		return Config{}, fmt.Errorf("invalid configuration file: %w", err)
+	}
+	if err := conf.validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid configuration file: %w", err)
+	}
+	return conf, nil
+}
+
+func (c Config) validate() error {
+	for _, level := range []string{c.LogLevel, c.Defaults.LogLevel} {
+		if level != "" && !slices.Contains(LogLevels, level) {
+			return fmt.Errorf("unknown log level %q, available levels: %s", level, strings.Join(LogLevels, ", "))
+		}
+	}
+	for _, feature := range c.EnableFeatures {
+		if !featuregate.IsValidFeature(feature) {
+			return fmt.Errorf("unknown feature %q, available features: %s", feature, strings.Join(featuregate.AllFeatures(), ", "))
+		}
+	}
+	if c.ResyncInterval != nil && c.ResyncInterval.Duration <= 0 {
+		return fmt.Errorf("resyncInterval must be positive")
+	}
+	if c.PruneGracePeriod != nil && c.PruneGracePeriod.Duration < 0 {
+		return fmt.Errorf("pruneGracePeriod must not be negative")
+	}
+	if strings.Contains(c.Defaults.Image, "@") || strings.Contains(c.Defaults.Image[strings.LastIndex(c.Defaults.Image, "/")+1:], ":") {
+		return fmt.Errorf("defaults.image must not have a tag or digest, the tag is set with defaults.version")
+	}
+	return nil
+}
+
+// RequiresRestart returns true if the configurations differ in settings that are only read when the operator starts.
+// The log levels and the defaults of the Thanos components are applied while the operator runs.
+func (c Config) RequiresRestart(other Config) bool {
+	return !sameFeatures(c.EnableFeatures, other.EnableFeatures) ||
+		!equalPtr(c.EnableWebhooks, other.EnableWebhooks) ||
+		!equalPtr(c.ResyncInterval, other.ResyncInterval) ||
+		!equalPtr(c.PruneGracePeriod, other.PruneGracePeriod)
+}
+
+func sameFeatures(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(slices.Compact(a), slices.Compact(b))
+}
+
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,133 @@
+package operatorconfig
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		data        string
+		errContains string
+	}{
+		{
+			name: "valid",
+			data: `
+logLevel: debug
+enableFeatures: [service-monitor]
+enableWebhooks: true
+resyncInterval: 10m
+pruneGracePeriod: 0s
+defaults:
+  image: registry.example.com:5000/thanos/thanos
+  version: v0.39.2
+  logLevel: warn
+`,
+		},
+		{name: "empty"},
+		{name: "unknown field", data: "logLevels: debug", errContains: "unknown field"},
+		{name: "unknown log level", data: "logLevel: trace", errContains: "unknown log level"},
+		{name: "unknown component log level", data: "defaults:\n  logLevel: trace", errContains: "unknown log level"},
+		{name: "unknown feature", data: "enableFeatures: [foo]", errContains: "unknown feature"},
+		{name: "zero resync interval", data: "resyncInterval: 0s", errContains: "resyncInterval"},
+		{name: "negative prune grace period", data: "pruneGracePeriod: -1m", errContains: "pruneGracePeriod"},
+		{name: "image with tag", data: "defaults:\n  image: quay.io/thanos/thanos:v0.39.2", errContains: "defaults.image"},
+		{name: "image with digest", data: "defaults:\n  image: quay.io/thanos/thanos@sha256:abc", errContains: "defaults.image"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse([]byte(tc.data))
+			if tc.errContains == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.errContains) {
+				t.Fatalf("expected an error containing %q, got %v", tc.errContains, err)
+			}
+		})
+	}
+}
+
+func TestRequiresRestart(t *testing.T) {
+	parse := func(data string) Config {
+		t.Helper()
+		conf, err := Parse([]byte(data))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return conf
+	}
+	current := parse("logLevel: info\nenableFeatures: [service-monitor, prometheus-rule]\nresyncInterval: 5m")
+
+	for _, tc := range []struct {
+		name    string
+		data    string
+		restart bool
+	}{
+		{name: "log levels and defaults", data: "logLevel: debug\nenableFeatures: [prometheus-rule, service-monitor, service-monitor]\nresyncInterval: 300s\ndefaults:\n  version: v0.39.2\n  logLevel: warn"},
+		{name: "features", data: "enableFeatures: [service-monitor]\nresyncInterval: 5m", restart: true},
+		{name: "resync interval", data: "enableFeatures: [service-monitor, prometheus-rule]\nresyncInterval: 1m", restart: true},
+		{name: "webhooks", data: "enableFeatures: [service-monitor, prometheus-rule]\nresyncInterval: 5m\nenableWebhooks: false", restart: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := current.RequiresRestart(parse(tc.data)); got != tc.restart {
+				t.Errorf("expected restart %v, got %v", tc.restart, got)
+			}
+		})
+	}
+}
+
+func TestReloader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(data string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("logLevel: info")
+	current, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var applied []Config
+	r := NewReloader(path, time.Hour, current, func(conf Config) { applied = append(applied, conf) }, logr.Discard())
+
+	if err := r.reload(); err != nil || len(applied) != 0 {
+		t.Fatalf("expected an unchanged file to be ignored, got %v and %d applied", err, len(applied))
+	}
+
+	write("logLevel: trace")
+	if err := r.reload(); err != nil || len(applied) != 0 {
+		t.Fatalf("expected an invalid file to be ignored, got %v and %d applied", err, len(applied))
+	}
+
+	write("logLevel: debug")
+	if err := r.reload(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(applied) != 1 || applied[0].LogLevel != "debug" {
+		t.Fatalf("expected the new log level to be applied, got %+v", applied)
+	}
+
+	write("logLevel: debug\nresyncInterval: 1m")
+	if err := r.reload(); !errors.Is(err, ErrRestartRequired) {
+		t.Fatalf("expected ErrRestartRequired, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := r.Start(ctx); err != nil {
+		t.Errorf("expected no error once the context is done, got %v", err)
+	}
+}
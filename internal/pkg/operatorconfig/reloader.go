@@ -0,0 +1,81 @@
+package operatorconfig
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// ErrRestartRequired is returned by the Reloader when the configuration file changes settings that are only
+// read when the operator starts, so that the operator exits and is restarted with them.
+var ErrRestartRequired = errors.New("the configuration file changed settings that are only read at startup")
+
+// Reloader reloads the configuration file when its contents change.
+// The file is polled, so that updates of a mounted ConfigMap, which replace the file through a symlink, are seen.
+type Reloader struct {
+	path     string
+	interval time.Duration
+	current  Config
+	data     []byte
+	apply    func(Config)
+	logger   logr.Logger
+}
+
+// NewReloader returns a Reloader of the configuration file at path, currently holding current, which calls apply
+// with the new configuration when the file changes. Invalid configurations are logged and ignored.
+func NewReloader(path string, interval time.Duration, current Config, apply func(Config), logger logr.Logger) *Reloader {
+	data, _ := os.ReadFile(path)
+	return &Reloader{path: path, interval: interval, current: current, data: data, apply: apply, logger: logger}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, every replica of the operator reloads its configuration.
+func (r *Reloader) NeedLeaderElection() bool {
+	return false
+}
+
+// Start polls the configuration file until the context is done.
+// It returns ErrRestartRequired once the file changes settings that are only read at startup.
+func (r *Reloader) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := r.reload(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (r *Reloader) reload() error {
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		r.logger.Error(err, "failed to read the configuration file, keeping the current configuration", "path", r.path)
+		return nil
+	}
+	if bytes.Equal(data, r.data) {
+		return nil
+	}
+	r.data = data
+
+	conf, err := Parse(data)
+	if err != nil {
+		r.logger.Error(err, "keeping the current configuration", "path", r.path)
+		return nil
+	}
+	if r.current.RequiresRestart(conf) {
+		r.logger.Info("restarting to apply the configuration file", "path", r.path)
+		return ErrRestartRequired
+	}
+	r.apply(conf)
+	r.current = conf
+	r.logger.Info("reloaded the configuration file", "path", r.path)
+	return nil
+}
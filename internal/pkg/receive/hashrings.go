@@ -1,15 +1,21 @@
 package receive
 
 import (
+	"cmp"
 	"crypto/md5"
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"net"
+	"path/filepath"
 	"slices"
 	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/prometheus/prometheus/model/labels"
 
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
 	"github.com/thanos-community/thanos-operator/internal/pkg/manifests/receive"
 
 	discoveryv1 "k8s.io/api/discovery/v1"
@@ -50,7 +56,17 @@ type Endpoint struct {
 
 type HashringMeta struct {
 	DesiredReplicas int
-	Config          HashringConfig
+	// ReadyReplicas is the number of ready members of the hashring, which can differ from the number of endpoints
+	// in the Config if members that are not ready are published.
+	ReadyReplicas int
+	// MinReadyReplicas is the number of ready members the hashring needs before its configuration is updated.
+	MinReadyReplicas int
+	Config           HashringConfig
+}
+
+// belowMinReady returns true if the hashring has fewer ready members than it needs to be updated.
+func (m HashringMeta) belowMinReady() bool {
+	return m.ReadyReplicas < m.MinReadyReplicas
 }
 
 // HashringState represents the desired state of a hashring.
@@ -118,6 +134,43 @@ func FilterEndpointReady() EndpointFilter {
 	}
 }
 
+// FilterEndpointNotTerminating returns an EndpointFilter that leaves out the endpoints of terminating Pods.
+func FilterEndpointNotTerminating() EndpointFilter {
+	return func() func(eps discoveryv1.EndpointSlice) []discoveryv1.Endpoint {
+		return func(eps discoveryv1.EndpointSlice) []discoveryv1.Endpoint {
+			var endpoints []discoveryv1.Endpoint
+			for _, ep := range eps.Endpoints {
+				if ep.Conditions.Terminating == nil || !*ep.Conditions.Terminating {
+					endpoints = append(endpoints, ep)
+				}
+			}
+			return endpoints
+		}
+	}
+}
+
+// FilterEndpointByOrdinal returns an EndpointFilter that keeps the endpoints of the first replicas Pods of the
+// given StatefulSet, so that the Pods being removed by a scale down are left out of the hashring.
+// Endpoints that do not belong to a Pod of the StatefulSet are kept.
+func FilterEndpointByOrdinal(statefulSet string, replicas int) EndpointFilter {
+	return func() func(eps discoveryv1.EndpointSlice) []discoveryv1.Endpoint {
+		return func(eps discoveryv1.EndpointSlice) []discoveryv1.Endpoint {
+			var kept []discoveryv1.Endpoint
+			for _, ep := range eps.Endpoints {
+				if ep.Hostname != nil {
+					if ordinal, ok := strings.CutPrefix(*ep.Hostname, statefulSet+"-"); ok {
+						if i, err := strconv.Atoi(ordinal); err == nil && i >= replicas {
+							continue
+						}
+					}
+				}
+				kept = append(kept, ep)
+			}
+			return kept
+		}
+	}
+}
+
 // EndpointConverter is a function that converts an EndpointSlice to an Endpoint.
 type EndpointConverter func(eps discoveryv1.EndpointSlice, ep discoveryv1.Endpoint) Endpoint
 
@@ -127,7 +180,7 @@ func DefaultEndpointConverter(eps discoveryv1.EndpointSlice, ep discoveryv1.Endp
 	svcName := eps.Labels[discoveryv1.LabelServiceName]
 	ns := eps.GetNamespace()
 	return Endpoint{
-		Address: fmt.Sprintf("%s.%s.%s.svc:%d", *ep.Hostname, svcName, ns, GRPCPort),
+		Address: fmt.Sprintf("%s:%d", manifests.PodHost(*ep.Hostname, svcName, ns), GRPCPort),
 	}
 }
 
@@ -138,14 +191,110 @@ func CapnProtoEndpointConverter(eps discoveryv1.EndpointSlice, ep discoveryv1.En
 	svcName := eps.Labels[discoveryv1.LabelServiceName]
 	ns := eps.GetNamespace()
 	return Endpoint{
-		Address:          fmt.Sprintf("%s.%s.%s.svc:%d", *ep.Hostname, svcName, ns, GRPCPort),
-		CapnProtoAddress: fmt.Sprintf("%s.%s.%s.svc:%d", *ep.Hostname, svcName, ns, CapnProtoPort),
+		Address:          fmt.Sprintf("%s:%d", manifests.PodHost(*ep.Hostname, svcName, ns), GRPCPort),
+		CapnProtoAddress: fmt.Sprintf("%s:%d", manifests.PodHost(*ep.Hostname, svcName, ns), CapnProtoPort),
 	}
 }
 
+// EndpointHostFormat defines how the host of an Endpoint address is built.
+type EndpointHostFormat string
+
+const (
+	// EndpointHostFormatHostname builds the host from the hostname of the endpoint, <hostname>.<service>.<namespace>.svc,
+	// followed by the cluster domain if set.
+	EndpointHostFormatHostname EndpointHostFormat = "Hostname"
+	// EndpointHostFormatIP uses the first address of the endpoint as the host.
+	EndpointHostFormatIP EndpointHostFormat = "IP"
+)
+
+// EndpointAddressOptions controls the format of the addresses of the Endpoints of a hashring.
+type EndpointAddressOptions struct {
+	// HostFormat defines how the host of the addresses is built. Defaults to EndpointHostFormatHostname.
+	HostFormat EndpointHostFormat
+	// GRPCPort is the port of the gRPC address. Defaults to GRPCPort.
+	GRPCPort int32
+	// CapnProto enables the Cap'n Proto address of the Endpoints.
+	CapnProto bool
+	// CapnProtoPort is the port of the Cap'n Proto address. Defaults to CapnProtoPort.
+	CapnProtoPort int32
+}
+
+// Validate returns an error if the options cannot produce valid addresses.
+func (o EndpointAddressOptions) Validate() error {
+	switch o.HostFormat {
+	case "", EndpointHostFormatHostname, EndpointHostFormatIP:
+	default:
+		return fmt.Errorf("unknown endpoint host format %q", o.HostFormat)
+	}
+
+	for _, port := range []int32{o.GRPCPort, o.CapnProtoPort} {
+		if port < 0 || port > 65535 {
+			return fmt.Errorf("invalid endpoint port %d", port)
+		}
+	}
+
+	if !o.CapnProto && o.CapnProtoPort != 0 {
+		return fmt.Errorf("cap'n proto port set without enabling cap'n proto")
+	}
+
+	if o.CapnProto && o.grpcPort() == o.capnProtoPort() {
+		return fmt.Errorf("gRPC and cap'n proto endpoints cannot share port %d", o.grpcPort())
+	}
+	return nil
+}
+
+func (o EndpointAddressOptions) grpcPort() int32 {
+	if o.GRPCPort == 0 {
+		return GRPCPort
+	}
+	return o.GRPCPort
+}
+
+func (o EndpointAddressOptions) capnProtoPort() int32 {
+	if o.CapnProtoPort == 0 {
+		return CapnProtoPort
+	}
+	return o.CapnProtoPort
+}
+
+// NewEndpointConverter returns an EndpointConverter that builds the addresses according to the given options.
+// Endpoints that cannot be addressed in the requested format, such as endpoints without a hostname,
+// are converted to an empty Endpoint.
+func NewEndpointConverter(o EndpointAddressOptions) (EndpointConverter, error) {
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+
+	return func(eps discoveryv1.EndpointSlice, ep discoveryv1.Endpoint) Endpoint {
+		var host string
+		switch o.HostFormat {
+		case EndpointHostFormatIP:
+			if len(ep.Addresses) == 0 {
+				return Endpoint{}
+			}
+			host = ep.Addresses[0]
+		default:
+			if ep.Hostname == nil {
+				return Endpoint{}
+			}
+			host = manifests.PodHost(*ep.Hostname, eps.Labels[discoveryv1.LabelServiceName], eps.GetNamespace())
+		}
+
+		endpoint := Endpoint{
+			Address: net.JoinHostPort(host, strconv.Itoa(int(o.grpcPort()))),
+		}
+		if o.CapnProto {
+			endpoint.CapnProtoAddress = net.JoinHostPort(host, strconv.Itoa(int(o.capnProtoPort())))
+		}
+		return endpoint
+	}, nil
+}
+
 // EndpointSliceListToEndpoints converts a list of EndpointSlices to a list of Endpoints.
 // It uses the provided EndpointConverter to convert each EndpointSlice to an Endpoint.
 // It also applies the provided EndpointFilters to filter the EndpointSlices.
+// The AZ of each Endpoint is taken from the zone of the EndpointSlice endpoint if the converter did not set one.
+// Kubernetes populates the zone from the topology.kubernetes.io/zone label of the node the endpoint runs on.
 func EndpointSliceListToEndpoints(converter EndpointConverter, eps discoveryv1.EndpointSliceList, filters ...EndpointFilter) []Endpoint {
 	var endpoints []Endpoint
 	for _, epSlice := range eps.Items {
@@ -156,19 +305,54 @@ func EndpointSliceListToEndpoints(converter EndpointConverter, eps discoveryv1.E
 
 		svcEndpoints := epSlice.Endpoints
 		for _, ep := range svcEndpoints {
-			endpoints = append(endpoints, converter(epSlice, ep))
+			endpoint := converter(epSlice, ep)
+			if endpoint == (Endpoint{}) {
+				continue
+			}
+			if endpoint.AZ == "" && ep.Zone != nil {
+				endpoint.AZ = *ep.Zone
+			}
+			endpoints = append(endpoints, endpoint)
 		}
 	}
 	sort.Slice(endpoints, func(i, j int) bool {
-		if endpoints[i].Address != "" && endpoints[j].Address != "" {
+		if endpoints[i].Address != "" && endpoints[j].Address != "" && endpoints[i].Address != endpoints[j].Address {
 			return endpoints[i].Address < endpoints[j].Address
 		}
-		if endpoints[i].CapnProtoAddress != "" && endpoints[j].CapnProtoAddress != "" {
+		if endpoints[i].CapnProtoAddress != "" && endpoints[j].CapnProtoAddress != "" && endpoints[i].CapnProtoAddress != endpoints[j].CapnProtoAddress {
 			return endpoints[i].CapnProtoAddress < endpoints[j].CapnProtoAddress
 		}
-		return false
+		return endpoints[i].AZ < endpoints[j].AZ
+	})
+	// the same endpoint can be listed by more than one EndpointSlice, possibly with a stale zone,
+	// so endpoints are deduplicated by address
+	return slices.CompactFunc(endpoints, func(a, b Endpoint) bool {
+		return a.Address == b.Address && a.CapnProtoAddress == b.CapnProtoAddress
 	})
-	return slices.Compact(endpoints)
+}
+
+// ZoneAwareEndpoints returns the endpoints with their AZ if every endpoint has one and the endpoints
+// span at least replicationFactor zones, so that each replica of a series can be placed in a distinct zone.
+// Otherwise, it returns the endpoints without AZ and the hashring is built without zone awareness.
+func ZoneAwareEndpoints(endpoints []Endpoint, replicationFactor int) []Endpoint {
+	zones := make(map[string]struct{})
+	for _, ep := range endpoints {
+		if ep.AZ == "" {
+			zones = nil
+			break
+		}
+		zones[ep.AZ] = struct{}{}
+	}
+	if len(zones) > 0 && len(zones) >= replicationFactor {
+		return endpoints
+	}
+
+	out := make([]Endpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		ep.AZ = ""
+		out = append(out, ep)
+	}
+	return out
 }
 
 // DynamicMerge merges the previous state of hashrings with the desired state.
@@ -185,7 +369,7 @@ func DynamicMerge(previousState Hashrings, fetchedReadyState HashringState, repl
 		// secondly, we allow to tolerate a single missing member. this allows us to account for
 		// voluntary disruptions to the hashring.
 		// todo - allow for more than one missing member based on input from PDB settings etc
-		if len(v.Config.Endpoints) >= replicationFactor && len(v.Config.Endpoints) >= v.DesiredReplicas-1 {
+		if !v.belowMinReady() && len(v.Config.Endpoints) >= replicationFactor && len(v.Config.Endpoints) >= v.DesiredReplicas-1 {
 			mergedState = append(mergedState, metaToHashring(k, v))
 			continue
 		}
@@ -212,7 +396,7 @@ func StaticMerge(previousState Hashrings, fetchedReadyState HashringState, repli
 	}
 
 	for k, v := range fetchedReadyState {
-		if len(v.Config.Endpoints) >= replicationFactor && len(v.Config.Endpoints) >= v.DesiredReplicas {
+		if !v.belowMinReady() && len(v.Config.Endpoints) >= replicationFactor && len(v.Config.Endpoints) >= v.DesiredReplicas {
 			v.Config.Endpoints = trimTo(v.Config.Endpoints, v.DesiredReplicas)
 			mergedState = append(mergedState, metaToHashring(k, v))
 			continue
@@ -236,7 +420,7 @@ func handleUnseenHashrings(desiredState HashringState) Hashrings {
 	var hashrings Hashrings
 	for k, v := range desiredState {
 		// we don't add anything until all members become ready initially
-		if len(v.Config.Endpoints) >= v.DesiredReplicas {
+		if !v.belowMinReady() && len(v.Config.Endpoints) >= v.DesiredReplicas {
 			hashrings = append(hashrings, metaToHashring(k, v))
 		}
 	}
@@ -262,6 +446,77 @@ func isEmptyHashring(hashrings Hashrings) bool {
 	return false
 }
 
+// MatchesTenant returns true if the router would route the writes of the tenant to the hashring,
+// had no other hashring matched it first. A hashring without tenants matches all tenants.
+func (h HashringConfig) MatchesTenant(tenant string) bool {
+	if len(h.Tenants) == 0 {
+		return true
+	}
+	for _, t := range h.Tenants {
+		if h.TenantMatcherType == TenantMatcherGlob {
+			if ok, err := filepath.Match(t, tenant); err == nil && ok {
+				return true
+			}
+			continue
+		}
+		if t == tenant {
+			return true
+		}
+	}
+	return false
+}
+
+// OrderByPriority orders the hashrings by descending priority, keyed by hashring name. The router routes the writes
+// of a tenant to the first hashring matching it, so hashrings with a higher priority take precedence over the
+// hashrings whose tenants overlap with theirs. Hashrings without a priority have a priority of 0, and the relative
+// order of hashrings with the same priority is kept.
+func OrderByPriority(hashrings Hashrings, priorities map[string]int32) Hashrings {
+	ordered := slices.Clone(hashrings)
+	slices.SortStableFunc(ordered, func(a, b HashringConfig) int {
+		return cmp.Compare(priorities[b.Name], priorities[a.Name])
+	})
+	return ordered
+}
+
+// OrderExcludedTenants orders the hashrings so that each hashring comes after the hashrings listing the tenants
+// it excludes, keyed by hashring name. The router routes the writes of a tenant to the first hashring matching it,
+// so this keeps the excluded tenants away from the hashring. Hashrings without tenants are not moved ahead,
+// as they would then match all tenants. The relative order of the hashrings is otherwise kept.
+// It returns an error if the exclusions are circular.
+func OrderExcludedTenants(hashrings Hashrings, excluded map[string][]string) (Hashrings, error) {
+	after := make(map[string][]string, len(excluded))
+	for _, h := range hashrings {
+		for _, tenant := range excluded[h.Name] {
+			for _, other := range hashrings {
+				if other.Name != h.Name && len(other.Tenants) > 0 && other.MatchesTenant(tenant) && !slices.Contains(after[h.Name], other.Name) {
+					after[h.Name] = append(after[h.Name], other.Name)
+				}
+			}
+		}
+	}
+
+	ordered := make(Hashrings, 0, len(hashrings))
+	placed := make(map[string]bool, len(hashrings))
+	for len(ordered) < len(hashrings) {
+		next := -1
+		for i, h := range hashrings {
+			if placed[h.Name] {
+				continue
+			}
+			if !slices.ContainsFunc(after[h.Name], func(name string) bool { return !placed[name] }) {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			return nil, fmt.Errorf("the excluded tenants of the hashrings are circular")
+		}
+		placed[hashrings[next].Name] = true
+		ordered = append(ordered, hashrings[next])
+	}
+	return ordered, nil
+}
+
 // UnmarshalJSON unmarshal the endpoint from JSON.
 func (e *Endpoint) UnmarshalJSON(data []byte) error {
 	// First try to unmarshal as a string.
@@ -275,12 +530,119 @@ func (e *Endpoint) UnmarshalJSON(data []byte) error {
 	var configEndpoint endpointAlias
 	err = json.Unmarshal(data, &configEndpoint)
 	if err == nil {
-		e.Address = configEndpoint.Address
-		e.AZ = configEndpoint.AZ
+		*e = Endpoint(configEndpoint)
 	}
 	return err
 }
 
+// HoldBackEndpointAdditions returns the next hashring configuration with the endpoints that joined the hashrings
+// of the previous configuration held back, and the endpoints that stayed in them kept as they were previously.
+// Endpoints that left a hashring are removed, and added and removed hashrings, tenants and algorithms are taken
+// from the next configuration, so that ingesters leaving the hashrings are no longer routed to straight away.
+// The zones of the endpoints are checked again against the replication factor, as removals can leave too few zones.
+func HoldBackEndpointAdditions(previous, next Hashrings, replicationFactor int) Hashrings {
+	prev := make(map[string]HashringConfig, len(previous))
+	for _, h := range previous {
+		prev[h.Name] = h
+	}
+
+	held := make(Hashrings, 0, len(next))
+	for _, h := range next {
+		p, ok := prev[h.Name]
+		if !ok {
+			held = append(held, h)
+			continue
+		}
+		remaining := make(map[string]bool, len(h.Endpoints))
+		for _, ep := range h.Endpoints {
+			remaining[ep.Address] = true
+		}
+		endpoints := make([]Endpoint, 0, len(p.Endpoints))
+		for _, ep := range p.Endpoints {
+			if remaining[ep.Address] {
+				endpoints = append(endpoints, ep)
+			}
+		}
+		h.Endpoints = ZoneAwareEndpoints(endpoints, replicationFactor)
+		held = append(held, h)
+	}
+	return held
+}
+
+// DiffHashrings summarizes the changes from the previous to the next hashring configuration, one entry per hashring
+// that was added, removed or changed, in the order of the next configuration followed by the removed hashrings.
+func DiffHashrings(previous, next Hashrings) []string {
+	prev := make(map[string]HashringConfig, len(previous))
+	for _, h := range previous {
+		prev[h.Name] = h
+	}
+
+	var diff []string
+	seen := make(map[string]bool, len(next))
+	for _, h := range next {
+		seen[h.Name] = true
+		p, ok := prev[h.Name]
+		if !ok {
+			diff = append(diff, fmt.Sprintf("hashring %s added with %s", h.Name, pluralize(len(h.Endpoints), "endpoint")))
+			continue
+		}
+
+		var changes []string
+		added, removed, changed := diffEndpoints(p.Endpoints, h.Endpoints)
+		if added > 0 {
+			changes = append(changes, pluralize(added, "endpoint")+" added")
+		}
+		if removed > 0 {
+			changes = append(changes, pluralize(removed, "endpoint")+" removed")
+		}
+		if changed > 0 {
+			changes = append(changes, pluralize(changed, "endpoint")+" changed")
+		}
+		if !slices.Equal(p.Tenants, h.Tenants) || p.TenantMatcherType != h.TenantMatcherType {
+			changes = append(changes, "tenants changed")
+		}
+		if p.Algorithm != h.Algorithm {
+			changes = append(changes, fmt.Sprintf("algorithm changed from %q to %q", p.Algorithm, h.Algorithm))
+		}
+		if len(changes) > 0 {
+			diff = append(diff, fmt.Sprintf("hashring %s: %s", h.Name, strings.Join(changes, ", ")))
+		}
+	}
+	for _, h := range previous {
+		if !seen[h.Name] {
+			diff = append(diff, fmt.Sprintf("hashring %s removed", h.Name))
+		}
+	}
+	return diff
+}
+
+// diffEndpoints returns the number of endpoints added, removed and changed from previous to next, by address.
+// An endpoint is changed when its zone or Cap'n Proto address changed.
+func diffEndpoints(previous, next []Endpoint) (added, removed, changed int) {
+	prev := make(map[string]Endpoint, len(previous))
+	for _, ep := range previous {
+		prev[ep.Address] = ep
+	}
+	for _, ep := range next {
+		p, ok := prev[ep.Address]
+		switch {
+		case !ok:
+			added++
+		case p != ep:
+			changed++
+		}
+		delete(prev, ep.Address)
+	}
+	return added, len(prev), changed
+}
+
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
 // HashAsMetricValue hashes the given data and returns a float64 value.
 func HashAsMetricValue(data []byte) float64 {
 	sum := md5.Sum(data)
@@ -1,13 +1,19 @@
 package receive
 
 import (
+	"encoding/json"
+	"fmt"
 	"reflect"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/prometheus/prometheus/model/labels"
+	"gotest.tools/v3/golden"
 
 	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/utils/ptr"
 )
 
@@ -82,6 +88,107 @@ func TestCapnProtoEndpointConverter(t *testing.T) {
 	}
 }
 
+func TestNewEndpointConverter(t *testing.T) {
+	eps := discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Labels: map[string]string{
+				discoveryv1.LabelServiceName: "test-service",
+			},
+		},
+	}
+
+	ep := discoveryv1.Endpoint{
+		Hostname:  ptr.To("test-host"),
+		Addresses: []string{"10.0.0.1"},
+	}
+
+	for _, tc := range []struct {
+		name      string
+		opts      EndpointAddressOptions
+		ep        discoveryv1.Endpoint
+		expected  Endpoint
+		expectErr bool
+	}{
+		{
+			name:     "defaults match the default converter",
+			opts:     EndpointAddressOptions{},
+			ep:       ep,
+			expected: DefaultEndpointConverter(eps, ep),
+		},
+		{
+			name:     "cap'n proto defaults match the cap'n proto converter",
+			opts:     EndpointAddressOptions{CapnProto: true},
+			ep:       ep,
+			expected: CapnProtoEndpointConverter(eps, ep),
+		},
+		{
+			name: "custom ports",
+			opts: EndpointAddressOptions{CapnProto: true, GRPCPort: 10907, CapnProtoPort: 19397},
+			ep:   ep,
+			expected: Endpoint{
+				Address:          "test-host.test-service.default.svc:10907",
+				CapnProtoAddress: "test-host.test-service.default.svc:19397",
+			},
+		},
+		{
+			name:     "ip host format",
+			opts:     EndpointAddressOptions{HostFormat: EndpointHostFormatIP},
+			ep:       ep,
+			expected: Endpoint{Address: "10.0.0.1:10901"},
+		},
+		{
+			name:     "ipv6 host format",
+			opts:     EndpointAddressOptions{HostFormat: EndpointHostFormatIP},
+			ep:       discoveryv1.Endpoint{Addresses: []string{"fd00::1"}},
+			expected: Endpoint{Address: "[fd00::1]:10901"},
+		},
+		{
+			name:     "missing hostname is skipped",
+			opts:     EndpointAddressOptions{},
+			ep:       discoveryv1.Endpoint{Addresses: []string{"10.0.0.1"}},
+			expected: Endpoint{},
+		},
+		{
+			name:      "unknown host format",
+			opts:      EndpointAddressOptions{HostFormat: "URL"},
+			expectErr: true,
+		},
+		{
+			name:      "cap'n proto port without cap'n proto",
+			opts:      EndpointAddressOptions{CapnProtoPort: 19391},
+			expectErr: true,
+		},
+		{
+			name:      "shared ports",
+			opts:      EndpointAddressOptions{CapnProto: true, GRPCPort: 19391},
+			expectErr: true,
+		},
+		{
+			name:      "port out of range",
+			opts:      EndpointAddressOptions{GRPCPort: 70000},
+			expectErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			converter, err := NewEndpointConverter(tc.opts)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result := converter(eps, tc.ep); result != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
 func TestFilterEndpointReady(t *testing.T) {
 	eps := discoveryv1.EndpointSlice{
 		Endpoints: []discoveryv1.Endpoint{
@@ -112,6 +219,44 @@ func TestFilterEndpointReady(t *testing.T) {
 	}
 }
 
+func TestFilterEndpointNotTerminating(t *testing.T) {
+	eps := discoveryv1.EndpointSlice{
+		Endpoints: []discoveryv1.Endpoint{
+			{Addresses: []string{"running"}, Conditions: discoveryv1.EndpointConditions{Terminating: ptr.To(false)}},
+			{Addresses: []string{"unknown"}},
+			{Addresses: []string{"terminating"}, Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(true), Terminating: ptr.To(true)}},
+		},
+	}
+
+	result := FilterEndpointNotTerminating()()(eps)
+	if len(result) != 2 || result[0].Addresses[0] != "running" || result[1].Addresses[0] != "unknown" {
+		t.Errorf("expected the endpoints that are not terminating, got %v", result)
+	}
+}
+
+func TestFilterEndpointByOrdinal(t *testing.T) {
+	eps := discoveryv1.EndpointSlice{
+		Endpoints: []discoveryv1.Endpoint{
+			{Hostname: ptr.To("ingester-0")},
+			{Hostname: ptr.To("ingester-1")},
+			{Hostname: ptr.To("ingester-2")},
+			{Hostname: ptr.To("ingester-10")},
+			{Hostname: ptr.To("other-ingester-5")},
+			{Addresses: []string{"10.0.0.1"}},
+		},
+	}
+
+	result := FilterEndpointByOrdinal("ingester", 2)()(eps)
+	var hosts []string
+	for _, ep := range result {
+		hosts = append(hosts, ptr.Deref(ep.Hostname, ""))
+	}
+	expected := []string{"ingester-0", "ingester-1", "other-ingester-5", ""}
+	if !reflect.DeepEqual(hosts, expected) {
+		t.Errorf("expected endpoints %v, got %v", expected, hosts)
+	}
+}
+
 func TestFilterEndpointByOwnerRef(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -332,6 +477,34 @@ func TestEndpointSliceListToEndpoints(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "WithZone",
+			eps: discoveryv1.EndpointSliceList{
+				Items: []discoveryv1.EndpointSlice{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "default",
+							Labels: map[string]string{
+								discoveryv1.LabelServiceName: "test-service",
+							},
+						},
+						Endpoints: []discoveryv1.Endpoint{
+							{
+								Hostname: ptr.To("test-host"),
+								Zone:     ptr.To("zone-a"),
+							},
+						},
+					},
+				},
+			},
+			converter: DefaultEndpointConverter,
+			expected: []Endpoint{
+				{
+					Address: "test-host.test-service.default.svc:10901",
+					AZ:      "zone-a",
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -349,6 +522,57 @@ func TestEndpointSliceListToEndpoints(t *testing.T) {
 	}
 }
 
+func TestZoneAwareEndpoints(t *testing.T) {
+	endpoints := func(zones ...string) []Endpoint {
+		var eps []Endpoint
+		for i, zone := range zones {
+			eps = append(eps, Endpoint{Address: fmt.Sprintf("host-%d:10901", i), AZ: zone})
+		}
+		return eps
+	}
+
+	tests := []struct {
+		name              string
+		endpoints         []Endpoint
+		replicationFactor int
+		expected          []Endpoint
+	}{
+		{
+			name:              "enough zones",
+			endpoints:         endpoints("a", "b", "c"),
+			replicationFactor: 3,
+			expected:          endpoints("a", "b", "c"),
+		},
+		{
+			name:              "fewer zones than the replication factor",
+			endpoints:         endpoints("a", "b", "a"),
+			replicationFactor: 3,
+			expected:          endpoints("", "", ""),
+		},
+		{
+			name:              "endpoint without zone",
+			endpoints:         endpoints("a", "", "c"),
+			replicationFactor: 1,
+			expected:          endpoints("", "", ""),
+		},
+		{
+			name:              "no zones",
+			endpoints:         endpoints("", ""),
+			replicationFactor: 1,
+			expected:          endpoints("", ""),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ZoneAwareEndpoints(tt.endpoints, tt.replicationFactor)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
 const hashringName = "hashring1"
 
 func TestDynamicMergeEmptyPreviousState(t *testing.T) {
@@ -514,6 +738,43 @@ func TestDynamicMergeDesiredStateNotMet(t *testing.T) {
 	}
 }
 
+func TestMergeBelowMinReadyReplicas(t *testing.T) {
+	previousState := Hashrings{
+		{
+			Name:      hashringName,
+			Endpoints: []Endpoint{{Address: "endpoint1"}, {Address: "endpoint2"}, {Address: "endpoint3"}},
+		},
+	}
+	desiredState := HashringState{
+		hashringName: {
+			DesiredReplicas:  3,
+			ReadyReplicas:    2,
+			MinReadyReplicas: 3,
+			Config: HashringConfig{
+				Endpoints: []Endpoint{{Address: "endpoint1"}, {Address: "endpoint2"}, {Address: "endpoint4"}},
+			},
+		},
+	}
+
+	for name, merge := range map[string]func(Hashrings, HashringState, int) Hashrings{"dynamic": DynamicMerge, "static": StaticMerge} {
+		result := merge(previousState, desiredState, 1)
+		if len(result) != 1 || !reflect.DeepEqual(result[0].Endpoints, previousState[0].Endpoints) {
+			t.Errorf("%s: expected the previous state to be kept below the minimum ready replicas, got %v", name, result)
+		}
+		if result := merge(Hashrings{}, desiredState, 1); len(result) != 0 {
+			t.Errorf("%s: expected no hashring to be added below the minimum ready replicas, got %v", name, result)
+		}
+	}
+
+	meta := desiredState[hashringName]
+	meta.ReadyReplicas = 3
+	desiredState[hashringName] = meta
+	result := DynamicMerge(previousState, desiredState, 1)
+	if len(result) != 1 || result[0].Endpoints[2].Address != "endpoint4" {
+		t.Errorf("expected the hashring to be updated once the minimum ready replicas is met, got %v", result)
+	}
+}
+
 func TestMapToExternalLabels(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -797,3 +1058,376 @@ func TestStaticMergeDesiredStateNotMet(t *testing.T) {
 		t.Errorf("expected hashring name 'hashring1', got '%s'", result[0].Name)
 	}
 }
+
+// corpusEndpoint is an endpoint of an EndpointSlice in the hashring golden corpus.
+type corpusEndpoint struct {
+	hostname string
+	ip       string
+	zone     string
+	ready    bool
+}
+
+func corpusSlice(name, service string, endpoints ...corpusEndpoint) discoveryv1.EndpointSlice {
+	eps := discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "monitoring",
+			Labels: map[string]string{
+				discoveryv1.LabelServiceName: service,
+			},
+		},
+	}
+	for _, e := range endpoints {
+		ep := discoveryv1.Endpoint{
+			Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(e.ready)},
+		}
+		if e.hostname != "" {
+			ep.Hostname = ptr.To(e.hostname)
+		}
+		if e.ip != "" {
+			ep.Addresses = []string{e.ip}
+		}
+		if e.zone != "" {
+			ep.Zone = ptr.To(e.zone)
+		}
+		eps.Endpoints = append(eps.Endpoints, ep)
+	}
+	return eps
+}
+
+// corpusHashring is a hashring of the golden corpus with the EndpointSlices of its ingester Service.
+type corpusHashring struct {
+	name            string
+	desiredReplicas int
+	slices          []discoveryv1.EndpointSlice
+}
+
+// buildCorpusHashrings generates the hashring configuration the same way the ThanosReceive controller does.
+func buildCorpusHashrings(t *testing.T, previous string, opts EndpointAddressOptions, static bool, replicationFactor int, hashrings ...corpusHashring) string {
+	t.Helper()
+	var previousState Hashrings
+	if previous != "" {
+		if err := json.Unmarshal([]byte(previous), &previousState); err != nil {
+			t.Fatalf("failed to unmarshal previous state: %v", err)
+		}
+	}
+
+	converter, err := NewEndpointConverter(opts)
+	if err != nil {
+		t.Fatalf("failed to create endpoint converter: %v", err)
+	}
+
+	state := make(HashringState, len(hashrings))
+	for _, hr := range hashrings {
+		eps := discoveryv1.EndpointSliceList{Items: hr.slices}
+		state[hr.name] = HashringMeta{
+			DesiredReplicas: hr.desiredReplicas,
+			Config: HashringConfig{
+				Name:      hr.name,
+				Endpoints: ZoneAwareEndpoints(EndpointSliceListToEndpoints(converter, eps, FilterEndpointReady()), replicationFactor),
+				Algorithm: AlgorithmKetama,
+			},
+		}
+	}
+
+	merged := DynamicMerge(previousState, state, replicationFactor)
+	if static {
+		merged = StaticMerge(previousState, state, replicationFactor)
+	}
+	if len(merged) == 0 {
+		return ""
+	}
+
+	b, err := json.MarshalIndent(merged, "", "    ")
+	if err != nil {
+		t.Fatalf("failed to marshal hashrings: %v", err)
+	}
+	return string(b)
+}
+
+func TestHashringGoldenCorpus(t *testing.T) {
+	const svc = "thanos-receive-ingester-test-default"
+	ready := func(hostname, zone string) corpusEndpoint {
+		return corpusEndpoint{hostname: hostname, ip: "10.0.0." + hostname[len(hostname)-1:], zone: zone, ready: true}
+	}
+	unready := func(hostname string) corpusEndpoint {
+		e := ready(hostname, "")
+		e.ready = false
+		return e
+	}
+	previousThreeEndpoints := `[{"hashring":"default","endpoints":[` +
+		`{"address":"ingester-0.svc:10901","capnproto_address":"ingester-0.svc:19391","az":""},` +
+		`{"address":"ingester-1.svc:10901","capnproto_address":"ingester-1.svc:19391","az":""},` +
+		`"ingester-2.svc:10901"],"algorithm":"ketama"}]`
+
+	for _, tc := range []struct {
+		name              string
+		previous          string
+		opts              EndpointAddressOptions
+		static            bool
+		replicationFactor int
+		hashrings         []corpusHashring
+	}{
+		{
+			name:              "ready-endpoints",
+			replicationFactor: 3,
+			hashrings: []corpusHashring{{name: "default", desiredReplicas: 3, slices: []discoveryv1.EndpointSlice{
+				corpusSlice("slice-a", svc, ready("ingester-0", ""), ready("ingester-1", ""), ready("ingester-2", "")),
+			}}},
+		},
+		{
+			name:              "unready-endpoints",
+			previous:          previousThreeEndpoints,
+			replicationFactor: 1,
+			hashrings: []corpusHashring{{name: "default", desiredReplicas: 3, slices: []discoveryv1.EndpointSlice{
+				corpusSlice("slice-a", svc, ready("ingester-0", ""), unready("ingester-1"), ready("ingester-2", "")),
+			}}},
+		},
+		{
+			name:              "unready-endpoints-first-rollout",
+			replicationFactor: 1,
+			hashrings: []corpusHashring{{name: "default", desiredReplicas: 3, slices: []discoveryv1.EndpointSlice{
+				corpusSlice("slice-a", svc, ready("ingester-0", ""), unready("ingester-1"), ready("ingester-2", "")),
+			}}},
+		},
+		{
+			name:              "hostname-collision",
+			replicationFactor: 2,
+			hashrings: []corpusHashring{{name: "default", desiredReplicas: 2, slices: []discoveryv1.EndpointSlice{
+				corpusSlice("slice-a", svc, ready("ingester-0", "zone-a"), ready("ingester-1", "zone-b")),
+				// a stale slice still lists ingester-1 in its previous zone
+				corpusSlice("slice-b", svc, ready("ingester-1", "zone-a")),
+			}}},
+		},
+		{
+			name:              "duplicate-addresses-across-slices",
+			opts:              EndpointAddressOptions{HostFormat: EndpointHostFormatIP},
+			replicationFactor: 2,
+			hashrings: []corpusHashring{{name: "default", desiredReplicas: 2, slices: []discoveryv1.EndpointSlice{
+				corpusSlice("slice-a", svc, ready("ingester-0", ""), ready("ingester-1", "")),
+				corpusSlice("slice-b", svc, ready("ingester-1", ""), ready("ingester-0", "")),
+			}}},
+		},
+		{
+			name:              "empty-hashring",
+			replicationFactor: 1,
+			hashrings: []corpusHashring{
+				{name: "default", desiredReplicas: 1, slices: []discoveryv1.EndpointSlice{
+					corpusSlice("slice-a", svc, ready("ingester-0", "")),
+				}},
+				{name: "tenant-a", desiredReplicas: 1},
+			},
+		},
+		{
+			name:              "empty-hashring-keeps-previous-state",
+			previous:          previousThreeEndpoints,
+			replicationFactor: 1,
+			hashrings:         []corpusHashring{{name: "default", desiredReplicas: 3}},
+		},
+		{
+			name:              "az",
+			opts:              EndpointAddressOptions{CapnProto: true},
+			replicationFactor: 3,
+			hashrings: []corpusHashring{{name: "default", desiredReplicas: 3, slices: []discoveryv1.EndpointSlice{
+				corpusSlice("slice-a", svc, ready("ingester-0", "zone-a"), ready("ingester-1", "zone-b"), ready("ingester-2", "zone-c")),
+			}}},
+		},
+		{
+			name:              "az-fewer-zones-than-replication-factor",
+			replicationFactor: 3,
+			hashrings: []corpusHashring{{name: "default", desiredReplicas: 3, slices: []discoveryv1.EndpointSlice{
+				corpusSlice("slice-a", svc, ready("ingester-0", "zone-a"), ready("ingester-1", "zone-b"), ready("ingester-2", "zone-a")),
+			}}},
+		},
+		{
+			name:              "static-scale-down",
+			previous:          previousThreeEndpoints,
+			static:            true,
+			replicationFactor: 1,
+			hashrings: []corpusHashring{{name: "default", desiredReplicas: 2, slices: []discoveryv1.EndpointSlice{
+				corpusSlice("slice-a", svc, ready("ingester-0", ""), ready("ingester-1", ""), ready("ingester-2", "")),
+			}}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out := buildCorpusHashrings(t, tc.previous, tc.opts, tc.static, tc.replicationFactor, tc.hashrings...)
+			golden.Assert(t, out, "hashring-"+tc.name+".golden.json")
+		})
+	}
+}
+
+func FuzzEndpointSliceListToEndpoints(f *testing.F) {
+	f.Add("ingester-0,ingester-1,ingester-2", "zone-a,zone-b,zone-c", uint8(0xff), false, uint8(3))
+	f.Add("ingester-0,ingester-0,ingester-1", "zone-a,zone-b,", uint8(0x05), false, uint8(2))
+	f.Add("ingester-0,,ingester-1", ",,", uint8(0x06), true, uint8(1))
+	f.Add("", "", uint8(0), false, uint8(0))
+
+	f.Fuzz(func(t *testing.T, hostnames, zones string, readyMask uint8, ipMode bool, replicationFactor uint8) {
+		zoneList := strings.Split(zones, ",")
+		var slicesA, slicesB []corpusEndpoint
+		for i, hostname := range strings.Split(hostnames, ",") {
+			// Kubernetes only sets hostnames that are DNS labels and zones that are label values
+			if len(validation.IsDNS1123Label(hostname)) > 0 {
+				hostname = ""
+			}
+			e := corpusEndpoint{hostname: hostname, ready: readyMask&(1<<(i%8)) != 0}
+			if hostname != "" {
+				e.ip = fmt.Sprintf("10.0.%d.%d", len(hostname), hostname[0])
+			}
+			if i < len(zoneList) && len(validation.IsValidLabelValue(zoneList[i])) == 0 {
+				e.zone = zoneList[i]
+			}
+			// spread the endpoints over two slices, with every third endpoint listed in both
+			if i%2 == 0 || i%3 == 0 {
+				slicesA = append(slicesA, e)
+			}
+			if i%2 == 1 || i%3 == 0 {
+				slicesB = append(slicesB, e)
+			}
+		}
+		eps := discoveryv1.EndpointSliceList{Items: []discoveryv1.EndpointSlice{
+			corpusSlice("slice-a", "svc", slicesA...),
+			corpusSlice("slice-b", "svc", slicesB...),
+		}}
+
+		opts := EndpointAddressOptions{CapnProto: true}
+		if ipMode {
+			opts.HostFormat = EndpointHostFormatIP
+		}
+		converter, err := NewEndpointConverter(opts)
+		if err != nil {
+			t.Fatalf("failed to create endpoint converter: %v", err)
+		}
+
+		endpoints := EndpointSliceListToEndpoints(converter, eps, FilterEndpointReady())
+		seen := make(map[string]struct{}, len(endpoints))
+		for i, ep := range endpoints {
+			if ep.Address == "" {
+				t.Fatalf("endpoint %d has no address: %+v", i, ep)
+			}
+			if _, ok := seen[ep.Address]; ok {
+				t.Fatalf("duplicate address %q in %+v", ep.Address, endpoints)
+			}
+			seen[ep.Address] = struct{}{}
+			if i > 0 && endpoints[i-1].Address > ep.Address {
+				t.Fatalf("endpoints are not sorted: %+v", endpoints)
+			}
+
+			b, err := json.Marshal(ep)
+			if err != nil {
+				t.Fatalf("failed to marshal endpoint: %v", err)
+			}
+			var roundTripped Endpoint
+			if err := json.Unmarshal(b, &roundTripped); err != nil {
+				t.Fatalf("failed to unmarshal endpoint: %v", err)
+			}
+			if roundTripped != ep {
+				t.Fatalf("endpoint changed after a JSON round trip: %+v != %+v", roundTripped, ep)
+			}
+		}
+
+		zoneAware := ZoneAwareEndpoints(endpoints, int(replicationFactor))
+		if len(zoneAware) != len(endpoints) {
+			t.Fatalf("expected %d zone aware endpoints, got %d", len(endpoints), len(zoneAware))
+		}
+		var withZone int
+		for i, ep := range zoneAware {
+			if ep.Address != endpoints[i].Address {
+				t.Fatalf("zone aware endpoints changed address: %+v != %+v", ep, endpoints[i])
+			}
+			if ep.AZ != "" {
+				withZone++
+			}
+		}
+		if withZone != 0 && withZone != len(zoneAware) {
+			t.Fatalf("expected all or no endpoints to have a zone, got %+v", zoneAware)
+		}
+	})
+}
+
+func TestOrderExcludedTenants(t *testing.T) {
+	hashrings := Hashrings{
+		{Name: "a-default"},
+		{Name: "b-prod", Tenants: []string{"prod-*"}, TenantMatcherType: TenantMatcherGlob},
+		{Name: "c-legacy", Tenants: []string{"prod-legacy"}},
+		{Name: "d-team", Tenants: []string{"team"}},
+	}
+
+	ordered, err := OrderExcludedTenants(hashrings, map[string][]string{
+		"a-default": {"prod-eu", "team"},
+		"b-prod":    {"prod-legacy"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var names []string
+	for _, h := range ordered {
+		names = append(names, h.Name)
+	}
+	if want := []string{"c-legacy", "b-prod", "d-team", "a-default"}; !slices.Equal(names, want) {
+		t.Errorf("expected order %v, got %v", want, names)
+	}
+
+	if _, err := OrderExcludedTenants(hashrings, map[string][]string{
+		"b-prod":   {"prod-legacy"},
+		"c-legacy": {"prod-legacy"},
+	}); err == nil {
+		t.Error("expected an error for circular exclusions")
+	}
+}
+
+func TestOrderByPriority(t *testing.T) {
+	hashrings := Hashrings{
+		{Name: "a-default"},
+		{Name: "b-teams", Tenants: []string{"team-*"}, TenantMatcherType: TenantMatcherGlob},
+		{Name: "c-team-a", Tenants: []string{"team-a"}},
+		{Name: "d-team-b", Tenants: []string{"team-b"}},
+	}
+
+	ordered := OrderByPriority(hashrings, map[string]int32{
+		"a-default": -1,
+		"c-team-a":  10,
+		"d-team-b":  10,
+	})
+	var names []string
+	for _, h := range ordered {
+		names = append(names, h.Name)
+	}
+	if want := []string{"c-team-a", "d-team-b", "b-teams", "a-default"}; !slices.Equal(names, want) {
+		t.Errorf("expected order %v, got %v", want, names)
+	}
+	if hashrings[0].Name != "a-default" {
+		t.Error("expected the hashrings to be left unchanged")
+	}
+}
+
+func TestDiffHashrings(t *testing.T) {
+	previous := Hashrings{
+		{Name: "a", Endpoints: []Endpoint{{Address: "a-0"}, {Address: "a-1"}}},
+		{Name: "b", Endpoints: []Endpoint{{Address: "b-0"}}, Tenants: []string{"team-b"}},
+		{Name: "c", Endpoints: []Endpoint{{Address: "c-0"}}},
+		{Name: "d", Endpoints: []Endpoint{{Address: "d-0", AZ: "zone-a"}, {Address: "d-1"}, {Address: "d-2"}}},
+		{Name: "old", Endpoints: []Endpoint{{Address: "old-0"}}},
+	}
+	next := Hashrings{
+		{Name: "a", Endpoints: []Endpoint{{Address: "a-1"}, {Address: "a-2"}, {Address: "a-3"}}},
+		{Name: "b", Endpoints: []Endpoint{{Address: "b-0"}}, Tenants: []string{"team-b", "team-c"}},
+		{Name: "c", Endpoints: []Endpoint{{Address: "c-0"}}},
+		{Name: "d", Endpoints: []Endpoint{{Address: "d-0", AZ: "zone-b"}, {Address: "d-1", CapnProtoAddress: "d-1:19391"}, {Address: "d-2"}}},
+		{Name: "new", Endpoints: []Endpoint{{Address: "new-0"}}},
+	}
+
+	expect := []string{
+		"hashring a: 2 endpoints added, 1 endpoint removed",
+		"hashring b: tenants changed",
+		"hashring d: 2 endpoints changed",
+		"hashring new added with 1 endpoint",
+		"hashring old removed",
+	}
+	if diff := DiffHashrings(previous, next); !slices.Equal(diff, expect) {
+		t.Errorf("expected diff %q, got %q", expect, diff)
+	}
+	if diff := DiffHashrings(next, next); len(diff) != 0 {
+		t.Errorf("expected no diff between identical configurations, got %q", diff)
+	}
+}
@@ -0,0 +1,302 @@
+// Package validate validates resources of the operator offline, with the checks the API server
+// and the admission webhooks run when they are applied.
+package validate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+	"github.com/thanos-community/thanos-operator/api/v1beta1"
+	webhookv1alpha1 "github.com/thanos-community/thanos-operator/internal/webhook/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/cel"
+	structuraldefaulting "k8s.io/apiextensions-apiserver/pkg/apiserver/schema/defaulting"
+	structuralpruning "k8s.io/apiextensions-apiserver/pkg/apiserver/schema/pruning"
+	apiservervalidation "k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	celconfig "k8s.io/apiserver/pkg/apis/cel"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+	"sigs.k8s.io/yaml"
+)
+
+// Result is the outcome of the validation of a resource.
+type Result struct {
+	// Resource identifies the resource as <kind>/<name>.
+	Resource string
+	// Errors are the reasons the resource would be rejected.
+	Errors []string
+	// Warnings are issues that do not prevent the resource from being applied.
+	Warnings []string
+}
+
+// crdSchema holds the validators built from the schema of a version of a CustomResourceDefinition.
+type crdSchema struct {
+	structural *structuralschema.Structural
+	openAPI    apiservervalidation.SchemaValidator
+	cel        *cel.Validator
+}
+
+// Validator validates resources against the schemas and CEL rules of the CustomResourceDefinitions
+// and the admission webhooks of the operator.
+type Validator struct {
+	schemas   map[schema.GroupVersionKind]crdSchema
+	namespace string
+	cluster   client.Reader
+}
+
+// NewValidator returns a Validator for the CustomResourceDefinitions in the root of crds.
+// Resources without a namespace are validated in the given namespace.
+// Secrets referenced by the resources are read from the cluster if cluster is not nil.
+func NewValidator(crds fs.FS, namespace string, cluster client.Reader) (*Validator, error) {
+	files, err := fs.Glob(crds, "*.yaml")
+	if err != nil {
+		return nil, err
+	}
+	v := &Validator{schemas: make(map[schema.GroupVersionKind]crdSchema), namespace: namespace, cluster: cluster}
+	for _, file := range files {
+		data, err := fs.ReadFile(crds, file)
+		if err != nil {
+			return nil, err
+		}
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := yaml.Unmarshal(data, crd); err != nil {
+			return nil, fmt.Errorf("failed to decode CustomResourceDefinition %s: %w", path.Base(file), err)
+		}
+		for _, version := range crd.Spec.Versions {
+			if version.Schema == nil || version.Schema.OpenAPIV3Schema == nil {
+				continue
+			}
+			s, err := newCRDSchema(version.Schema.OpenAPIV3Schema)
+			if err != nil {
+				return nil, fmt.Errorf("failed to build schema of %s %s: %w", crd.GetName(), version.Name, err)
+			}
+			v.schemas[schema.GroupVersionKind{Group: crd.Spec.Group, Version: version.Name, Kind: crd.Spec.Names.Kind}] = s
+		}
+	}
+	return v, nil
+}
+
+func newCRDSchema(in *apiextensionsv1.JSONSchemaProps) (crdSchema, error) {
+	props := &apiextensions.JSONSchemaProps{}
+	if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(in, props, nil); err != nil {
+		return crdSchema{}, err
+	}
+	structural, err := structuralschema.NewStructural(props)
+	if err != nil {
+		return crdSchema{}, err
+	}
+	openAPI, _, err := apiservervalidation.NewSchemaValidator(props)
+	if err != nil {
+		return crdSchema{}, err
+	}
+	return crdSchema{
+		structural: structural,
+		openAPI:    openAPI,
+		cel:        cel.NewValidator(structural, true, celconfig.PerCallLimit),
+	}, nil
+}
+
+// Decode decodes the YAML or JSON documents in r.
+func Decode(r io.Reader) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		raw := runtime.RawExtension{}
+		if err := decoder.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objs, nil
+			}
+			return nil, err
+		}
+		if len(raw.Raw) == 0 || string(raw.Raw) == "null" {
+			continue
+		}
+		// decode as the API server does, so that integers are not decoded as floats
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(raw.Raw); err != nil {
+			return nil, err
+		}
+		objs = append(objs, obj)
+	}
+}
+
+// Validate validates the resources of the operator in objs.
+// Secrets in objs are used to resolve the Secrets referenced by the resources before falling back
+// to the cluster. Other objects are ignored.
+func (v *Validator) Validate(ctx context.Context, objs []*unstructured.Unstructured) ([]Result, error) {
+	reader := &secretReader{secrets: make(map[client.ObjectKey]*corev1.Secret), cluster: v.cluster}
+	for _, obj := range objs {
+		if obj.GroupVersionKind() != corev1.SchemeGroupVersion.WithKind("Secret") {
+			continue
+		}
+		secret := &corev1.Secret{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, secret); err != nil {
+			return nil, fmt.Errorf("failed to decode Secret %s: %w", obj.GetName(), err)
+		}
+		for k, val := range secret.StringData {
+			if secret.Data == nil {
+				secret.Data = make(map[string][]byte)
+			}
+			secret.Data[k] = []byte(val)
+		}
+		reader.secrets[client.ObjectKey{Namespace: v.namespaceOf(obj), Name: secret.GetName()}] = secret
+	}
+
+	var results []Result
+	for _, obj := range objs {
+		if obj.GroupVersionKind().Group != v1alpha1.GroupVersion.Group {
+			continue
+		}
+		results = append(results, v.validate(ctx, reader, obj))
+	}
+	return results, nil
+}
+
+// Default drops the unknown fields of the resources of the operator in objs and applies the defaults of their
+// schemas in place, as the API server does when they are created. Objects without a namespace are set to the
+// namespace of the Validator.
+func (v *Validator) Default(objs []*unstructured.Unstructured) {
+	for _, obj := range objs {
+		obj.SetNamespace(v.namespaceOf(obj))
+		s, ok := v.schemas[obj.GroupVersionKind()]
+		if !ok {
+			continue
+		}
+		structuralpruning.Prune(obj.Object, s.structural, true)
+		structuraldefaulting.Default(obj.Object, s.structural)
+	}
+}
+
+func (v *Validator) namespaceOf(obj *unstructured.Unstructured) string {
+	if ns := obj.GetNamespace(); ns != "" {
+		return ns
+	}
+	return v.namespace
+}
+
+func (v *Validator) validate(ctx context.Context, reader *secretReader, in *unstructured.Unstructured) Result {
+	gvk := in.GroupVersionKind()
+	result := Result{Resource: gvk.Kind + "/" + in.GetName()}
+	s, ok := v.schemas[gvk]
+	if !ok {
+		result.Errors = append(result.Errors, fmt.Sprintf("no CustomResourceDefinition for %s", gvk))
+		return result
+	}
+
+	// validate a copy with unknown fields dropped and defaults applied, as the API server does
+	obj := in.DeepCopy()
+	obj.SetNamespace(v.namespaceOf(in))
+	unknown := structuralpruning.PruneWithOptions(obj.Object, s.structural, true, structuralschema.UnknownFieldPathOptions{TrackUnknownFieldPaths: true})
+	for _, field := range unknown {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("unknown field %q is dropped", field))
+	}
+	structuraldefaulting.Default(obj.Object, s.structural)
+
+	for _, err := range apiservervalidation.ValidateCustomResource(nil, obj.Object, s.openAPI) {
+		result.Errors = append(result.Errors, err.Error())
+	}
+	if s.cel != nil {
+		errs, _ := s.cel.Validate(ctx, nil, s.structural, obj.Object, nil, celconfig.RuntimeCELCostBudget)
+		for _, err := range errs {
+			result.Errors = append(result.Errors, err.Error())
+		}
+	}
+	// the webhooks decode the resource, which only succeeds once it matches its schema
+	if len(result.Errors) > 0 {
+		return result
+	}
+
+	err := v.validateWebhook(ctx, reader, obj)
+	var status apierrors.APIStatus
+	if errors.As(err, &status) && status.Status().Details != nil {
+		for _, cause := range status.Status().Details.Causes {
+			msg := fmt.Sprintf("%s: %s", cause.Field, cause.Message)
+			// without a cluster, Secrets that are not part of the input cannot be resolved
+			if reader.cluster == nil && cause.Type == metav1.CauseTypeFieldValueNotFound {
+				result.Warnings = append(result.Warnings, msg+" (Secret not found in the input, set a kubeconfig to resolve it)")
+				continue
+			}
+			result.Errors = append(result.Errors, msg)
+		}
+	} else if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
+	return result
+}
+
+// validateWebhook runs the validation of the admission webhook of the resource, if it has one.
+func (v *Validator) validateWebhook(ctx context.Context, reader client.Reader, obj *unstructured.Unstructured) error {
+	switch obj.GetKind() {
+	case "ThanosReceive":
+		receiver := &v1alpha1.ThanosReceive{}
+		if err := decodeHub(obj, receiver, &v1beta1.ThanosReceive{}); err != nil {
+			return err
+		}
+		_, err := webhookv1alpha1.NewThanosReceiveValidator(reader).ValidateCreate(ctx, receiver)
+		return err
+	case "ThanosQuery":
+		query := &v1alpha1.ThanosQuery{}
+		if err := decodeHub(obj, query, &v1beta1.ThanosQuery{}); err != nil {
+			return err
+		}
+		_, err := (&webhookv1alpha1.ThanosQueryValidator{}).ValidateCreate(ctx, query)
+		return err
+	}
+	return nil
+}
+
+// decodeHub decodes the resource into the hub version validated by the webhooks,
+// converting it with the spoke if it is written in the v1beta1 version.
+func decodeHub(obj *unstructured.Unstructured, hub conversion.Hub, spoke conversion.Convertible) error {
+	if obj.GroupVersionKind().Version != v1beta1.GroupVersion.Version {
+		return runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, hub)
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, spoke); err != nil {
+		return err
+	}
+	return spoke.ConvertTo(hub)
+}
+
+// secretReader reads the Secrets from the input, falling back to the cluster if set.
+type secretReader struct {
+	secrets map[client.ObjectKey]*corev1.Secret
+	cluster client.Reader
+}
+
+// Get implements client.Reader.
+func (r *secretReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if secret, ok := obj.(*corev1.Secret); ok {
+		if found, ok := r.secrets[key]; ok {
+			found.DeepCopyInto(secret)
+			return nil
+		}
+	}
+	if r.cluster == nil {
+		return apierrors.NewNotFound(corev1.Resource("secrets"), key.Name)
+	}
+	return r.cluster.Get(ctx, key, obj, opts...)
+}
+
+// List implements client.Reader.
+func (r *secretReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if r.cluster == nil {
+		return nil
+	}
+	return r.cluster.List(ctx, list, opts...)
+}
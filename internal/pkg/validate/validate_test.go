@@ -0,0 +1,179 @@
+package validate
+
+import (
+	"context"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/thanos-community/thanos-operator/config/crd"
+)
+
+const receiver = `
+apiVersion: monitoring.thanos.io/v1alpha1
+kind: ThanosReceive
+metadata:
+  name: example
+spec:
+  ingesterSpec:
+    defaultObjectStorageConfig:
+      name: objstore
+      key: thanos.yaml
+    hashrings:
+    - name: default
+      replicas: 3
+      storage:
+        size: 1Gi
+      tsdbConfig:
+        retention: 2h
+      tenancyConfig:
+        tenantMatcherType: exact
+        tenants: [a]
+  routerSpec:
+    replicas: 1
+    replicationFactor: 1
+`
+
+const objstore = `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: objstore
+stringData:
+  thanos.yaml: |
+    type: FILESYSTEM
+    config:
+      directory: /data
+`
+
+func TestValidate(t *testing.T) {
+	bases, err := fs.Sub(crd.Bases, "bases")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := NewValidator(bases, "default", nil)
+	if err != nil {
+		t.Fatalf("failed to create validator: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name          string
+		input         string
+		expectErr     string
+		expectWarning string
+	}{
+		{
+			name:  "valid",
+			input: receiver + "---\n" + objstore,
+		},
+		{
+			name:          "secret not in input",
+			input:         receiver,
+			expectWarning: `spec.ingesterSpec.defaultObjectStorageConfig.name: Not found: "objstore"`,
+		},
+		{
+			name:          "unknown field",
+			input:         receiver + "  unknownField: true\n---\n" + objstore,
+			expectWarning: `unknown field "spec.unknownField" is dropped`,
+		},
+		{
+			name:      "schema",
+			input:     strings.Replace(receiver, "replicationFactor: 1", "replicationFactor: 2", 1) + "---\n" + objstore,
+			expectErr: "spec.routerSpec.replicationFactor",
+		},
+		{
+			name:      "CEL rule",
+			input:     strings.Replace(receiver, "replicationFactor: 1", "replicationFactor: 5", 1) + "---\n" + objstore,
+			expectErr: "Ingester replicas must be greater than or equal to the Router replicas",
+		},
+		{
+			name: "autoscaling bounds",
+			input: strings.Replace(receiver, "      replicas: 3\n", `      replicas: 3
+      autoscaling:
+        minReplicas: 4
+        maxReplicas: 2
+`, 1) + "---\n" + objstore,
+			expectErr: "minReplicas cannot be greater than maxReplicas",
+		},
+		{
+			name: "inline object storage",
+			input: strings.Replace(receiver, `      name: objstore
+      key: thanos.yaml
+`, `      inline: |
+        type: FILESYSTEM
+        config:
+          directory: /data
+`, 1),
+		},
+		{
+			name: "inline object storage with a secret",
+			input: strings.Replace(receiver, "      key: thanos.yaml\n", `      key: thanos.yaml
+      inline: "type: FILESYSTEM"
+`, 1) + "---\n" + objstore,
+			expectErr: "name cannot be set with configMap or inline",
+		},
+		{
+			name: "webhook",
+			input: strings.Replace(receiver, "  routerSpec:", `    - name: other
+      storage:
+        size: 1Gi
+      tsdbConfig:
+        retention: 2h
+      tenancyConfig:
+        tenantMatcherType: exact
+        tenants: [a]
+  routerSpec:`, 1) + "---\n" + objstore,
+			expectErr: "tenant is already matched by hashring default",
+		},
+		{
+			name: "v1beta1 webhook",
+			input: strings.Replace(strings.Replace(receiver, "v1alpha1", "v1beta1", 1), "  routerSpec:", `    - name: other
+      storage:
+        size: 1Gi
+      tsdbConfig:
+        retention: 2h
+      tenancyConfig:
+        tenantMatcherType: exact
+        tenants: [a]
+  routerSpec:`, 1) + "---\n" + objstore,
+			expectErr: "tenant is already matched by hashring default",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			objs, err := Decode(strings.NewReader(tc.input))
+			if err != nil {
+				t.Fatalf("failed to decode input: %v", err)
+			}
+			results, err := v.Validate(context.Background(), objs)
+			if err != nil {
+				t.Fatalf("failed to validate: %v", err)
+			}
+			if len(results) != 1 || results[0].Resource != "ThanosReceive/example" {
+				t.Fatalf("expected a result for ThanosReceive/example, got %+v", results)
+			}
+
+			result := results[0]
+			if tc.expectErr == "" && len(result.Errors) > 0 {
+				t.Errorf("expected no errors, got %q", result.Errors)
+			}
+			if tc.expectErr != "" && !containsSubstring(result.Errors, tc.expectErr) {
+				t.Errorf("expected an error containing %q, got %q", tc.expectErr, result.Errors)
+			}
+			if tc.expectWarning == "" && len(result.Warnings) > 0 {
+				t.Errorf("expected no warnings, got %q", result.Warnings)
+			}
+			if tc.expectWarning != "" && !containsSubstring(result.Warnings, tc.expectWarning) {
+				t.Errorf("expected a warning containing %q, got %q", tc.expectWarning, result.Warnings)
+			}
+		})
+	}
+}
+
+func containsSubstring(messages []string, substr string) bool {
+	for _, msg := range messages {
+		if strings.Contains(msg, substr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package v1alpha1
+
+import (
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// The flags below are set by the operator and relied upon by the objects it generates around the containers,
+// such as the ports of the Services and probes, the mounted volumes and the hashring configuration.
+// Overriding them with additional args leaves the components unreachable or misconfigured.
+var (
+	routerManagedFlags = []string{
+		"--grpc-address",
+		"--http-address",
+		"--remote-write.address",
+		"--receive.hashrings-file",
+	}
+	ingesterManagedFlags = []string{
+		"--grpc-address",
+		"--http-address",
+		"--remote-write.address",
+		"--tsdb.path",
+		"--receive.local-endpoint",
+		"--objstore.config",
+		"--objstore.config-file",
+	}
+	queryManagedFlags = []string{
+		"--grpc-address",
+		"--http-address",
+	}
+	queryFrontendManagedFlags = []string{
+		"--http-address",
+		"--query-frontend.downstream-url",
+	}
+)
+
+// validateAdditionalArgs checks that the additional args are flags and do not override a flag managed by the operator.
+func validateAdditionalArgs(args, managed []string, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for i, arg := range args {
+		if !strings.HasPrefix(arg, "--") {
+			errs = append(errs, field.Invalid(path.Index(i), arg, "must be a flag of the form --flag or --flag=value"))
+			continue
+		}
+		flag, _, _ := strings.Cut(arg, "=")
+		if slices.Contains(managed, flag) {
+			errs = append(errs, field.Forbidden(path.Index(i), "flag "+flag+" is managed by the operator"))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,22 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// validateDeletionProtection rejects the deletion of a resource protected by its deletionProtection field
+// or by the deletion protection annotation.
+func validateDeletionProtection(obj client.Object, resource string, protection *bool) error {
+	if !ptr.Deref(protection, false) && obj.GetAnnotations()[v1alpha1.DeletionProtectionAnnotation] != "true" {
+		return nil
+	}
+	return apierrors.NewForbidden(v1alpha1.GroupVersion.WithResource(resource).GroupResource(), obj.GetName(),
+		fmt.Errorf("deletion protection is enabled, unset spec.deletionProtection and the %s annotation first", v1alpha1.DeletionProtectionAnnotation))
+}
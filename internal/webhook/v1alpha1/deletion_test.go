@@ -0,0 +1,44 @@
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestValidateDeletionProtection(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		annotations map[string]string
+		protection  *bool
+		wantError   bool
+	}{
+		{name: "unprotected"},
+		{name: "protection disabled", protection: ptr.To(false)},
+		{name: "protected by spec", protection: ptr.To(true), wantError: true},
+		{name: "protected by annotation", annotations: map[string]string{v1alpha1.DeletionProtectionAnnotation: "true"}, wantError: true},
+		{name: "annotation not true", annotations: map[string]string{v1alpha1.DeletionProtectionAnnotation: "false"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			meta := metav1.ObjectMeta{Name: "test", Namespace: "ns", Annotations: tc.annotations}
+			receiver := &v1alpha1.ThanosReceive{ObjectMeta: meta, Spec: v1alpha1.ThanosReceiveSpec{DeletionProtection: tc.protection}}
+			query := &v1alpha1.ThanosQuery{ObjectMeta: meta, Spec: v1alpha1.ThanosQuerySpec{DeletionProtection: tc.protection}}
+
+			_, receiveErr := NewThanosReceiveValidator(nil).ValidateDelete(context.Background(), receiver)
+			_, queryErr := (&ThanosQueryValidator{}).ValidateDelete(context.Background(), query)
+			for _, err := range []error{receiveErr, queryErr} {
+				if tc.wantError && !apierrors.IsForbidden(err) {
+					t.Errorf("expected the deletion to be forbidden, got %v", err)
+				}
+				if !tc.wantError && err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}
+		})
+	}
+}
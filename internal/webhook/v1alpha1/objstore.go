@@ -0,0 +1,82 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+	"github.com/thanos-community/thanos-operator/internal/pkg/objstore"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// validateObjectStorageConfig checks that the Secret or the ConfigMap referenced by the ObjectStorageConfig exists
+// and that its key holds a valid object storage configuration, or that the inline configuration is valid.
+// A missing Secret or ConfigMap is allowed if the reference is optional.
+func validateObjectStorageConfig(ctx context.Context, c client.Reader, namespace string, config v1alpha1.ObjectStorageConfig, path *field.Path) *field.Error {
+	switch {
+	case config.Inline != nil:
+		if _, err := objstore.Parse([]byte(*config.Inline)); err != nil {
+			return field.Invalid(path.Child("inline"), "", fmt.Sprintf("invalid object storage configuration: %v", err))
+		}
+		return nil
+	case config.ConfigMap != nil:
+		return validateObjectStorageConfigMap(ctx, c, namespace, *config.ConfigMap, path.Child("configMap"))
+	}
+
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: config.Name}, secret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return field.InternalError(path, fmt.Errorf("failed to get secret %s: %w", config.Name, err))
+		}
+		if ptr.Deref(config.Optional, false) {
+			return nil
+		}
+		return field.NotFound(path.Child("name"), config.Name)
+	}
+
+	data, ok := secret.Data[config.Key]
+	if !ok {
+		if ptr.Deref(config.Optional, false) {
+			return nil
+		}
+		return field.Invalid(path.Child("key"), config.Key, fmt.Sprintf("key not found in secret %s", config.Name))
+	}
+
+	if _, err := objstore.Parse(data); err != nil {
+		return field.Invalid(path.Child("key"), config.Key, fmt.Sprintf("invalid object storage configuration in secret %s: %v", config.Name, err))
+	}
+	return nil
+}
+
+// validateObjectStorageConfigMap checks that the key of the ConfigMap holds a valid object storage configuration.
+func validateObjectStorageConfigMap(ctx context.Context, c client.Reader, namespace string, selector corev1.ConfigMapKeySelector, path *field.Path) *field.Error {
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: selector.Name}, cm); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return field.InternalError(path, fmt.Errorf("failed to get configmap %s: %w", selector.Name, err))
+		}
+		if ptr.Deref(selector.Optional, false) {
+			return nil
+		}
+		return field.NotFound(path.Child("name"), selector.Name)
+	}
+
+	data, ok := cm.Data[selector.Key]
+	if !ok {
+		if ptr.Deref(selector.Optional, false) {
+			return nil
+		}
+		return field.Invalid(path.Child("key"), selector.Key, fmt.Sprintf("key not found in configmap %s", selector.Name))
+	}
+
+	if _, err := objstore.Parse([]byte(data)); err != nil {
+		return field.Invalid(path.Child("key"), selector.Key, fmt.Sprintf("invalid object storage configuration in configmap %s: %v", selector.Name, err))
+	}
+	return nil
+}
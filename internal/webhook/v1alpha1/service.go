@@ -0,0 +1,28 @@
+package v1alpha1
+
+import (
+	"slices"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// validateServicePorts checks that the port overrides of the Service name ports the Service exposes,
+// as overrides of unknown ports would otherwise be ignored.
+func validateServicePorts(service *v1alpha1.ServiceConfig, ports []string, additional []corev1.ServicePort, path *field.Path) field.ErrorList {
+	if service == nil {
+		return nil
+	}
+	for _, p := range additional {
+		ports = append(ports, p.Name)
+	}
+	var errs field.ErrorList
+	for i, p := range service.Ports {
+		if !slices.Contains(ports, p.Name) {
+			errs = append(errs, field.NotSupported(path.Index(i).Child("name"), p.Name, ports))
+		}
+	}
+	return errs
+}
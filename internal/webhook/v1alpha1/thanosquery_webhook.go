@@ -0,0 +1,78 @@
+package v1alpha1
+
+import (
+	"context"
+
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests/queryfrontend"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// SetupThanosQueryWebhookWithManager registers the validating webhook for ThanosQuery with the manager.
+func SetupThanosQueryWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &v1alpha1.ThanosQuery{}).
+		WithValidator(&ThanosQueryValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-monitoring-thanos-io-v1alpha1-thanosquery,mutating=false,failurePolicy=fail,sideEffects=None,groups=monitoring.thanos.io,resources=thanosqueries,verbs=create;update;delete,versions=v1alpha1,name=vthanosquery-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// ThanosQueryValidator validates ThanosQuery resources on creation and update
+// with the checks that cannot be expressed as CEL rules in the CRD, and rejects the deletion of protected resources.
+type ThanosQueryValidator struct{}
+
+// ValidateCreate implements admission.Validator.
+func (v *ThanosQueryValidator) ValidateCreate(_ context.Context, obj *v1alpha1.ThanosQuery) (admission.Warnings, error) {
+	return nil, v.validate(obj)
+}
+
+// ValidateUpdate implements admission.Validator.
+func (v *ThanosQueryValidator) ValidateUpdate(_ context.Context, _, newObj *v1alpha1.ThanosQuery) (admission.Warnings, error) {
+	return nil, v.validate(newObj)
+}
+
+// ValidateDelete implements admission.Validator. It rejects the deletion of protected resources.
+func (v *ThanosQueryValidator) ValidateDelete(_ context.Context, obj *v1alpha1.ThanosQuery) (admission.Warnings, error) {
+	return nil, validateDeletionProtection(obj, "thanosqueries", obj.Spec.DeletionProtection)
+}
+
+func (v *ThanosQueryValidator) validate(query *v1alpha1.ThanosQuery) error {
+	spec := field.NewPath("spec")
+	var errs field.ErrorList
+
+	errs = append(errs, validateLabelSelector(query.Spec.StoreLabelSelector, spec.Child("customStoreLabelSelector"))...)
+	errs = append(errs, validateAdditionalArgs(query.Spec.Args, queryManagedFlags, spec.Child("additionalArgs"))...)
+	if query.Spec.QueryFrontend != nil {
+		errs = append(errs, validateLabelSelector(query.Spec.QueryFrontend.QueryLabelSelector, spec.Child("queryFrontend", "queryLabelSelector"))...)
+		errs = append(errs, validateAdditionalArgs(query.Spec.QueryFrontend.Args, queryFrontendManagedFlags, spec.Child("queryFrontend", "additionalArgs"))...)
+		errs = append(errs, validateServicePorts(query.Spec.QueryFrontend.Service, []string{queryfrontend.HTTPPortName}, query.Spec.QueryFrontend.ServicePorts, spec.Child("queryFrontend", "service", "ports"))...)
+	}
+	if query.Spec.ReadProbe != nil && query.Spec.ReadProbe.Query != nil {
+		if _, err := parser.ParseExpr(*query.Spec.ReadProbe.Query); err != nil {
+			errs = append(errs, field.Invalid(spec.Child("readProbe", "query"), *query.Spec.ReadProbe.Query, err.Error()))
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(v1alpha1.GroupVersion.WithKind("ThanosQuery").GroupKind(), query.GetName(), errs)
+}
+
+func validateLabelSelector(selector *metav1.LabelSelector, path *field.Path) field.ErrorList {
+	if selector == nil {
+		return nil
+	}
+	if _, err := metav1.LabelSelectorAsSelector(selector); err != nil {
+		return field.ErrorList{field.Invalid(path, selector, err.Error())}
+	}
+	return nil
+}
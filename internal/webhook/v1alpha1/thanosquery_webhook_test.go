@@ -0,0 +1,122 @@
+package v1alpha1
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestThanosQueryValidator(t *testing.T) {
+	v := &ThanosQueryValidator{}
+
+	for _, tc := range []struct {
+		name      string
+		spec      v1alpha1.ThanosQuerySpec
+		wantError string
+	}{
+		{
+			name: "valid",
+			spec: v1alpha1.ThanosQuerySpec{
+				StoreLabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "historical"}},
+				QueryFrontend: &v1alpha1.QueryFrontendSpec{
+					QueryLabelSelector: &metav1.LabelSelector{
+						MatchExpressions: []metav1.LabelSelectorRequirement{
+							{Key: "tier", Operator: metav1.LabelSelectorOpIn, Values: []string{"historical"}},
+						},
+					},
+				},
+				ReadProbe: &v1alpha1.ReadProbeSpec{Query: ptr.To(`sum(up{job="thanos"})`)},
+			},
+		},
+		{
+			name: "invalid store selector",
+			spec: v1alpha1.ThanosQuerySpec{
+				StoreLabelSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "tier", Operator: metav1.LabelSelectorOpIn},
+					},
+				},
+			},
+			wantError: "spec.customStoreLabelSelector",
+		},
+		{
+			name: "invalid query frontend selector",
+			spec: v1alpha1.ThanosQuerySpec{
+				QueryFrontend: &v1alpha1.QueryFrontendSpec{
+					QueryLabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "not valid"}},
+				},
+			},
+			wantError: "spec.queryFrontend.queryLabelSelector",
+		},
+		{
+			name: "invalid read probe query",
+			spec: v1alpha1.ThanosQuerySpec{
+				ReadProbe: &v1alpha1.ReadProbeSpec{Query: ptr.To("sum(up")},
+			},
+			wantError: "spec.readProbe.query",
+		},
+		{
+			name: "additional args override managed flag",
+			spec: v1alpha1.ThanosQuerySpec{
+				Additional: v1alpha1.Additional{Args: []string{"--query.auto-downsampling", "--grpc-address=0.0.0.0:10902"}},
+			},
+			wantError: "spec.additionalArgs[1]: Forbidden: flag --grpc-address is managed by the operator",
+		},
+		{
+			name: "query frontend service override of additional port",
+			spec: v1alpha1.ThanosQuerySpec{
+				QueryFrontend: &v1alpha1.QueryFrontendSpec{
+					Service: &v1alpha1.ServiceConfig{Ports: []v1alpha1.ServicePortConfig{{Name: "admin", Port: 8443}}},
+					Additional: v1alpha1.Additional{
+						ServicePorts: []corev1.ServicePort{{Name: "admin", Port: 8080}},
+					},
+				},
+			},
+		},
+		{
+			name: "query frontend service override of unknown port",
+			spec: v1alpha1.ThanosQuerySpec{
+				QueryFrontend: &v1alpha1.QueryFrontendSpec{
+					Service: &v1alpha1.ServiceConfig{Ports: []v1alpha1.ServicePortConfig{{Name: "grpc", Port: 10901}}},
+				},
+			},
+			wantError: "spec.queryFrontend.service.ports[0].name: Unsupported value: \"grpc\"",
+		},
+		{
+			name: "query frontend additional args override managed flag",
+			spec: v1alpha1.ThanosQuerySpec{
+				QueryFrontend: &v1alpha1.QueryFrontendSpec{
+					Additional: v1alpha1.Additional{Args: []string{"--query-frontend.downstream-url=http://localhost:9090"}},
+				},
+			},
+			wantError: "spec.queryFrontend.additionalArgs[0]: Forbidden: flag --query-frontend.downstream-url is managed by the operator",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			query := &v1alpha1.ThanosQuery{
+				ObjectMeta: metav1.ObjectMeta{Name: "query", Namespace: "ns"},
+				Spec:       tc.spec,
+			}
+
+			_, createErr := v.ValidateCreate(context.Background(), query)
+			_, updateErr := v.ValidateUpdate(context.Background(), query, query)
+			for _, err := range []error{createErr, updateErr} {
+				if tc.wantError == "" {
+					if err != nil {
+						t.Errorf("unexpected error: %v", err)
+					}
+					continue
+				}
+				if err == nil || !strings.Contains(err.Error(), tc.wantError) {
+					t.Errorf("expected error containing %q, got %v", tc.wantError, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,294 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/relabel"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+	"github.com/thanos-community/thanos-operator/internal/pkg/manifests"
+	manifestreceive "github.com/thanos-community/thanos-operator/internal/pkg/manifests/receive"
+	"github.com/thanos-community/thanos-operator/internal/pkg/receive"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// SetupThanosReceiveWebhookWithManager registers the validating webhook for ThanosReceive with the manager.
+func SetupThanosReceiveWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &v1alpha1.ThanosReceive{}).
+		WithValidator(NewThanosReceiveValidator(mgr.GetClient())).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-monitoring-thanos-io-v1alpha1-thanosreceive,mutating=false,failurePolicy=fail,sideEffects=None,groups=monitoring.thanos.io,resources=thanosreceives,verbs=create;update;delete,versions=v1alpha1,name=vthanosreceive-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// ThanosReceiveValidator validates ThanosReceive resources on creation and update
+// with the checks that cannot be expressed as CEL rules in the CRD, and rejects the deletion of protected resources.
+type ThanosReceiveValidator struct {
+	client client.Reader
+}
+
+// NewThanosReceiveValidator returns a ThanosReceiveValidator reading referenced objects with the given client.
+func NewThanosReceiveValidator(c client.Reader) *ThanosReceiveValidator {
+	return &ThanosReceiveValidator{client: c}
+}
+
+// ValidateCreate implements admission.Validator.
+func (v *ThanosReceiveValidator) ValidateCreate(ctx context.Context, obj *v1alpha1.ThanosReceive) (admission.Warnings, error) {
+	return nil, v.validate(ctx, obj)
+}
+
+// ValidateUpdate implements admission.Validator.
+func (v *ThanosReceiveValidator) ValidateUpdate(ctx context.Context, _, newObj *v1alpha1.ThanosReceive) (admission.Warnings, error) {
+	return nil, v.validate(ctx, newObj)
+}
+
+// ValidateDelete implements admission.Validator. It rejects the deletion of protected resources.
+func (v *ThanosReceiveValidator) ValidateDelete(_ context.Context, obj *v1alpha1.ThanosReceive) (admission.Warnings, error) {
+	return nil, validateDeletionProtection(obj, "thanosreceives", obj.Spec.DeletionProtection)
+}
+
+func (v *ThanosReceiveValidator) validate(ctx context.Context, receiver *v1alpha1.ThanosReceive) error {
+	spec := field.NewPath("spec")
+	ingester := spec.Child("ingesterSpec")
+	var errs field.ErrorList
+
+	// the Secrets of resources deployed to a workload cluster live in that cluster
+	if receiver.Spec.TargetCluster == nil {
+		if err := validateObjectStorageConfig(ctx, v.client, receiver.GetNamespace(), receiver.Spec.Ingester.DefaultObjectStorageConfig, ingester.Child("defaultObjectStorageConfig")); err != nil {
+			errs = append(errs, err)
+		}
+		for i, hashring := range receiver.Spec.Ingester.Hashrings {
+			if hashring.ObjectStorageConfig == nil {
+				continue
+			}
+			if err := validateObjectStorageConfig(ctx, v.client, receiver.GetNamespace(), *hashring.ObjectStorageConfig, ingester.Child("hashrings").Index(i).Child("objectStorageConfig")); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	errs = append(errs, validateTenantMatchers(receiver.Spec.Ingester.Hashrings, ingester.Child("hashrings"))...)
+	errs = append(errs, validateExactTenants(receiver.Spec.Ingester.Hashrings, ingester.Child("hashrings"))...)
+	errs = append(errs, validateExcludedTenants(receiver.Spec.Ingester.Hashrings, ingester.Child("hashrings"))...)
+	externalLabelErrs, err := v.validateExternalLabels(ctx, receiver, ingester.Child("hashrings"))
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	errs = append(errs, externalLabelErrs...)
+	errs = append(errs, validateReplicationProtocol(receiver.Spec.Router, receiver.Spec.Ingester.Hashrings, spec)...)
+	errs = append(errs, validateAdditionalArgs(receiver.Spec.Router.Args, routerManagedFlags, spec.Child("routerSpec", "additionalArgs"))...)
+	errs = append(errs, validateServicePorts(receiver.Spec.Router.Service, routerServicePorts, receiver.Spec.Router.ServicePorts, spec.Child("routerSpec", "service", "ports"))...)
+	errs = append(errs, validateRelabelConfigs(receiver.Spec.Router.RelabelConfigs, spec.Child("routerSpec", "relabelConfigs"))...)
+	errs = append(errs, validateAdditionalArgs(receiver.Spec.Ingester.Args, ingesterManagedFlags, ingester.Child("additionalArgs"))...)
+	for i, hashring := range receiver.Spec.Ingester.Hashrings {
+		errs = append(errs, validateTSDBConfig(hashring.TSDBConfig, ingester.Child("hashrings").Index(i).Child("tsdbConfig"))...)
+		errs = append(errs, validateAdditionalArgs(hashring.AdditionalArgs, ingesterManagedFlags, ingester.Child("hashrings").Index(i).Child("additionalArgs"))...)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(v1alpha1.GroupVersion.WithKind("ThanosReceive").GroupKind(), receiver.GetName(), errs)
+}
+
+// routerServicePorts are the ports of the router Service that can be overridden.
+var routerServicePorts = []string{
+	manifestreceive.GRPCPortName,
+	manifestreceive.CapnProtoPortName,
+	manifestreceive.HTTPPortName,
+	manifestreceive.RemoteWritePortName,
+}
+
+// validateRelabelConfigs checks that the regular expressions of the relabeling rules compile,
+// as the routers would otherwise fail to start.
+func validateRelabelConfigs(configs []v1alpha1.RelabelConfig, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for i, config := range configs {
+		if config.Regex == nil {
+			continue
+		}
+		if _, err := relabel.NewRegexp(*config.Regex); err != nil {
+			errs = append(errs, field.Invalid(path.Index(i).Child("regex"), *config.Regex, err.Error()))
+		}
+	}
+	return errs
+}
+
+// validateExternalLabels checks that the external labels of the ingesters that are the same for all the replicas
+// of a hashring are not replica labels, either of another hashring or of a ThanosQuery in the namespace of the receiver.
+// Queriers drop replica labels to deduplicate series, which would merge the series of different hashrings.
+func (v *ThanosReceiveValidator) validateExternalLabels(ctx context.Context, receiver *v1alpha1.ThanosReceive, path *field.Path) (field.ErrorList, error) {
+	hashrings := receiver.Spec.Ingester.Hashrings
+	replicaLabels := map[string]string{}
+	for _, hashring := range hashrings {
+		for name, value := range manifestreceive.IngesterExternalLabels(hashring.Name, hashring.ExternalLabels) {
+			if _, ok := replicaLabels[name]; !ok && manifestreceive.IsReplicaLabelValue(value) {
+				replicaLabels[name] = fmt.Sprintf("hashring %s", hashring.Name)
+			}
+		}
+	}
+
+	// the replica label added by the operator must differ between replicas even where it is set explicitly
+	if _, ok := replicaLabels[manifestreceive.ReplicaExternalLabel]; !ok {
+		replicaLabels[manifestreceive.ReplicaExternalLabel] = "the ingesters"
+	}
+
+	queries := &v1alpha1.ThanosQueryList{}
+	if err := v.client.List(ctx, queries, client.InNamespace(receiver.GetNamespace())); err != nil {
+		return nil, fmt.Errorf("failed to list ThanosQuery resources: %w", err)
+	}
+	for _, query := range queries.Items {
+		for _, name := range query.Spec.ReplicaLabels {
+			if _, ok := replicaLabels[name]; !ok {
+				replicaLabels[name] = fmt.Sprintf("ThanosQuery %s", query.GetName())
+			}
+		}
+	}
+
+	var errs field.ErrorList
+	for i, hashring := range hashrings {
+		labels := manifestreceive.IngesterExternalLabels(hashring.Name, hashring.ExternalLabels)
+		for _, name := range slices.Sorted(maps.Keys(labels)) {
+			if manifestreceive.IsReplicaLabelValue(labels[name]) {
+				continue
+			}
+			if owner, ok := replicaLabels[name]; ok {
+				errs = append(errs, field.Invalid(path.Index(i).Child("externalLabels").Key(name), labels[name],
+					fmt.Sprintf("label is a replica label of %s, its value must be derived from %s", owner, manifestreceive.PodNameReference)))
+			}
+		}
+	}
+	return errs, nil
+}
+
+// validateReplicationProtocol checks that the routers, and the ingesters of the hashrings replicated to with it,
+// run a Thanos version supporting the capnproto replication protocol when it is used.
+func validateReplicationProtocol(router v1alpha1.RouterSpec, hashrings []v1alpha1.IngesterHashringSpec, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	protocol := ptr.Deref(router.ReplicationProtocol, v1alpha1.ReplicationProtocolGRPC)
+	if protocol == v1alpha1.ReplicationProtocolCapnProto && !commonFieldsVersionAtLeast(router.CommonFields, manifestreceive.CapnProtoMinVersion) {
+		errs = append(errs, field.Invalid(path.Child("routerSpec", "replicationProtocol"), protocol,
+			fmt.Sprintf("requires the routers to run Thanos %s or later", manifestreceive.CapnProtoMinVersion)))
+	}
+	for i, hashring := range hashrings {
+		hashringProtocol := protocol
+		if hashring.EndpointAddress != nil {
+			hashringProtocol = ptr.Deref(hashring.EndpointAddress.ReplicationProtocol, protocol)
+		}
+		if hashringProtocol == v1alpha1.ReplicationProtocolCapnProto && !commonFieldsVersionAtLeast(hashring.CommonFields, manifestreceive.CapnProtoMinVersion) {
+			errs = append(errs, field.Invalid(path.Child("ingesterSpec", "hashrings").Index(i).Child("version"), ptr.Deref(hashring.Version, ""),
+				fmt.Sprintf("the capnproto replication protocol requires Thanos %s or later, set endpointAddress.replicationProtocol to grpc for this hashring", manifestreceive.CapnProtoMinVersion)))
+		}
+	}
+	return errs
+}
+
+// commonFieldsVersionAtLeast returns false if the component runs a Thanos version lower than minVersion.
+func commonFieldsVersionAtLeast(common v1alpha1.CommonFields, minVersion string) bool {
+	return manifests.Options{Image: common.Image, Version: common.Version}.VersionAtLeast(minVersion)
+}
+
+// validateTSDBConfig checks that the block durations of the TSDB configuration are not inverted,
+// as the ingesters would otherwise fail to start.
+func validateTSDBConfig(config v1alpha1.TSDBConfig, path *field.Path) field.ErrorList {
+	if config.MinBlockDuration == nil || config.MaxBlockDuration == nil {
+		return nil
+	}
+	minDuration, minErr := model.ParseDuration(string(*config.MinBlockDuration))
+	maxDuration, maxErr := model.ParseDuration(string(*config.MaxBlockDuration))
+	// the pattern of the Duration type is checked by the CRD
+	if minErr != nil || maxErr != nil || maxDuration >= minDuration {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(path.Child("maxBlockDuration"), *config.MaxBlockDuration, "must not be lower than minBlockDuration")}
+}
+
+// validateTenantMatchers checks that the tenants of hashrings matching tenants exactly are not glob patterns,
+// which would only match tenants with the same literal name, and that the patterns of glob hashrings are well formed.
+func validateTenantMatchers(hashrings []v1alpha1.IngesterHashringSpec, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for i, hashring := range hashrings {
+		if hashring.TenancyConfig == nil {
+			continue
+		}
+		glob := hashring.TenancyConfig.TenantMatcherType == string(receive.TenantMatcherGlob)
+		for j, tenant := range hashring.TenancyConfig.Tenants {
+			tenantPath := path.Index(i).Child("tenancyConfig", "tenants").Index(j)
+			switch {
+			case glob:
+				if _, err := filepath.Match(tenant, ""); err != nil {
+					errs = append(errs, field.Invalid(tenantPath, tenant, "invalid glob pattern: "+err.Error()))
+				}
+			case strings.ContainsAny(tenant, `*?[\`):
+				errs = append(errs, field.Invalid(tenantPath, tenant, "tenant is a glob pattern, which requires the glob tenantMatcherType"))
+			}
+		}
+	}
+	return errs
+}
+
+// validateExactTenants checks that a tenant is matched exactly by at most one hashring,
+// as the router would otherwise route its writes to whichever hashring comes first.
+func validateExactTenants(hashrings []v1alpha1.IngesterHashringSpec, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	owners := make(map[string]string)
+	for i, hashring := range hashrings {
+		if hashring.TenancyConfig == nil || (hashring.TenancyConfig.TenantMatcherType != "" && hashring.TenancyConfig.TenantMatcherType != string(receive.TenantMatcherTypeExact)) {
+			continue
+		}
+		for j, tenant := range hashring.TenancyConfig.Tenants {
+			if owner, ok := owners[tenant]; ok && owner != hashring.Name {
+				errs = append(errs, field.Invalid(path.Index(i).Child("tenancyConfig", "tenants").Index(j), tenant, "tenant is already matched by hashring "+owner))
+				continue
+			}
+			owners[tenant] = hashring.Name
+		}
+	}
+	return errs
+}
+
+// validateExcludedTenants checks that each tenant excluded from a hashring is listed by another hashring,
+// which the router then routes it to, and that the exclusions are not circular.
+func validateExcludedTenants(hashrings []v1alpha1.IngesterHashringSpec, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	configs := make(receive.Hashrings, 0, len(hashrings))
+	excluded := make(map[string][]string)
+	for _, hashring := range hashrings {
+		config := receive.HashringConfig{Name: hashring.Name}
+		if hashring.TenancyConfig != nil {
+			config.Tenants = hashring.TenancyConfig.Tenants
+			config.TenantMatcherType = receive.TenantMatcher(hashring.TenancyConfig.TenantMatcherType)
+			excluded[hashring.Name] = hashring.TenancyConfig.ExcludeTenants
+		}
+		configs = append(configs, config)
+	}
+
+	for i, hashring := range hashrings {
+		for j, tenant := range excluded[hashring.Name] {
+			if !slices.ContainsFunc(configs, func(c receive.HashringConfig) bool {
+				return c.Name != hashring.Name && len(c.Tenants) > 0 && c.MatchesTenant(tenant)
+			}) {
+				errs = append(errs, field.Invalid(path.Index(i).Child("tenancyConfig", "excludeTenants").Index(j), tenant, "tenant is not listed by any other hashring"))
+			}
+		}
+	}
+	if len(errs) == 0 {
+		if _, err := receive.OrderExcludedTenants(configs, excluded); err != nil {
+			errs = append(errs, field.Invalid(path, len(hashrings), err.Error()))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,524 @@
+package v1alpha1
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/thanos-community/thanos-operator/api/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestThanosReceiveValidator(t *testing.T) {
+	const ns = "ns"
+	secret := func(name, config string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+			Data:       map[string][]byte{"thanos.yaml": []byte(config)},
+		}
+	}
+	objStore := func(name string) v1alpha1.ObjectStorageConfig {
+		return v1alpha1.ObjectStorageConfig{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			Key:                  "thanos.yaml",
+		}
+	}
+	hashring := func(name string, matcher string, tenants ...string) v1alpha1.IngesterHashringSpec {
+		return v1alpha1.IngesterHashringSpec{
+			Name:     name,
+			Replicas: 1,
+			TenancyConfig: &v1alpha1.TenancyConfig{
+				Tenants:           tenants,
+				TenantMatcherType: matcher,
+			},
+		}
+	}
+
+	excluding := func(h v1alpha1.IngesterHashringSpec, tenants ...string) v1alpha1.IngesterHashringSpec {
+		h.TenancyConfig.ExcludeTenants = tenants
+		return h
+	}
+
+	labeled := func(h v1alpha1.IngesterHashringSpec, labels v1alpha1.ExternalLabels) v1alpha1.IngesterHashringSpec {
+		h.ExternalLabels = labels
+		return h
+	}
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&v1alpha1.ThanosQuery{
+			ObjectMeta: metav1.ObjectMeta{Name: "query", Namespace: ns},
+			Spec:       v1alpha1.ThanosQuerySpec{ReplicaLabels: []string{"prometheus_replica"}},
+		},
+		secret("valid", "type: S3\nconfig:\n  bucket: thanos\n"),
+		secret("lowercase", "type: gcs\nconfig:\n  bucket: thanos\n"),
+		secret("unknown-type", "type: FTP\nconfig: {}\n"),
+		secret("unknown-field", "type: S3\nbucket: thanos\n"),
+		secret("not-yaml", "type: [S3"),
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "filesystem", Namespace: ns},
+			Data:       map[string]string{"thanos.yaml": "type: FILESYSTEM\nconfig:\n  directory: /data\n"},
+		},
+	).Build()
+	v := NewThanosReceiveValidator(c)
+
+	for _, tc := range []struct {
+		name      string
+		spec      v1alpha1.ThanosReceiveSpec
+		wantError string
+	}{
+		{
+			name: "valid",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Ingester: v1alpha1.IngesterSpec{
+					DefaultObjectStorageConfig: objStore("valid"),
+					Hashrings: []v1alpha1.IngesterHashringSpec{
+						hashring("a", "exact", "tenant-a"),
+						hashring("b", "exact", "tenant-b"),
+						hashring("c", "glob", "tenant-*"),
+					},
+				},
+			},
+		},
+		{
+			name: "lowercase type",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Ingester: v1alpha1.IngesterSpec{DefaultObjectStorageConfig: objStore("lowercase")},
+			},
+		},
+		{
+			name: "missing secret",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Ingester: v1alpha1.IngesterSpec{DefaultObjectStorageConfig: objStore("missing")},
+			},
+			wantError: "spec.ingesterSpec.defaultObjectStorageConfig.name: Not found",
+		},
+		{
+			name: "missing optional secret",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Ingester: v1alpha1.IngesterSpec{
+					DefaultObjectStorageConfig: v1alpha1.ObjectStorageConfig{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "missing"},
+						Key:                  "thanos.yaml",
+						Optional:             ptr.To(true),
+					},
+				},
+			},
+		},
+		{
+			name: "inline",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Ingester: v1alpha1.IngesterSpec{
+					DefaultObjectStorageConfig: v1alpha1.ObjectStorageConfig{Inline: ptr.To("type: FILESYSTEM\nconfig:\n  directory: /data\n")},
+				},
+			},
+		},
+		{
+			name: "invalid inline",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Ingester: v1alpha1.IngesterSpec{
+					DefaultObjectStorageConfig: v1alpha1.ObjectStorageConfig{Inline: ptr.To("type: FTP\nconfig: {}\n")},
+				},
+			},
+			wantError: "spec.ingesterSpec.defaultObjectStorageConfig.inline: Invalid value",
+		},
+		{
+			name: "configmap",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Ingester: v1alpha1.IngesterSpec{
+					DefaultObjectStorageConfig: v1alpha1.ObjectStorageConfig{ConfigMap: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "filesystem"},
+						Key:                  "thanos.yaml",
+					}},
+				},
+			},
+		},
+		{
+			name: "missing configmap",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Ingester: v1alpha1.IngesterSpec{
+					DefaultObjectStorageConfig: v1alpha1.ObjectStorageConfig{ConfigMap: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "missing"},
+						Key:                  "thanos.yaml",
+					}},
+				},
+			},
+			wantError: "spec.ingesterSpec.defaultObjectStorageConfig.configMap.name: Not found",
+		},
+		{
+			name: "missing secret in workload cluster is not checked",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Ingester:      v1alpha1.IngesterSpec{DefaultObjectStorageConfig: objStore("missing")},
+				TargetCluster: ptr.To("workload"),
+			},
+		},
+		{
+			name: "missing key",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Ingester: v1alpha1.IngesterSpec{
+					DefaultObjectStorageConfig: v1alpha1.ObjectStorageConfig{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "valid"},
+						Key:                  "objstore.yaml",
+					},
+				},
+			},
+			wantError: "key not found in secret valid",
+		},
+		{
+			name: "unknown type",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Ingester: v1alpha1.IngesterSpec{DefaultObjectStorageConfig: objStore("unknown-type")},
+			},
+			wantError: `unsupported object storage type "FTP"`,
+		},
+		{
+			name: "unknown field",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Ingester: v1alpha1.IngesterSpec{DefaultObjectStorageConfig: objStore("unknown-field")},
+			},
+			wantError: "invalid object storage configuration in secret unknown-field",
+		},
+		{
+			name: "invalid yaml",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Ingester: v1alpha1.IngesterSpec{DefaultObjectStorageConfig: objStore("not-yaml")},
+			},
+			wantError: "invalid object storage configuration in secret not-yaml",
+		},
+		{
+			name: "invalid hashring secret",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Ingester: v1alpha1.IngesterSpec{
+					DefaultObjectStorageConfig: objStore("valid"),
+					Hashrings: []v1alpha1.IngesterHashringSpec{
+						{Name: "a", Replicas: 1, ObjectStorageConfig: ptr.To(objStore("missing"))},
+					},
+				},
+			},
+			wantError: "spec.ingesterSpec.hashrings[0].objectStorageConfig.name: Not found",
+		},
+		{
+			name: "overlapping exact tenants",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Ingester: v1alpha1.IngesterSpec{
+					DefaultObjectStorageConfig: objStore("valid"),
+					Hashrings: []v1alpha1.IngesterHashringSpec{
+						hashring("a", "exact", "tenant-a", "tenant-b"),
+						hashring("b", "", "tenant-b"),
+					},
+				},
+			},
+			wantError: "spec.ingesterSpec.hashrings[1].tenancyConfig.tenants[0]: Invalid value: \"tenant-b\": tenant is already matched by hashring a",
+		},
+		{
+			name: "tenant matched exactly and by glob",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Ingester: v1alpha1.IngesterSpec{
+					DefaultObjectStorageConfig: objStore("valid"),
+					Hashrings: []v1alpha1.IngesterHashringSpec{
+						hashring("a", "exact", "tenant-a"),
+						hashring("b", "glob", "tenant-a"),
+					},
+				},
+			},
+		},
+		{
+			name: "exact tenant with glob pattern",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Ingester: v1alpha1.IngesterSpec{
+					DefaultObjectStorageConfig: objStore("valid"),
+					Hashrings: []v1alpha1.IngesterHashringSpec{
+						hashring("a", "", "team-*"),
+					},
+				},
+			},
+			wantError: "spec.ingesterSpec.hashrings[0].tenancyConfig.tenants[0]: Invalid value: \"team-*\": tenant is a glob pattern, which requires the glob tenantMatcherType",
+		},
+		{
+			name: "malformed glob pattern",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Ingester: v1alpha1.IngesterSpec{
+					DefaultObjectStorageConfig: objStore("valid"),
+					Hashrings: []v1alpha1.IngesterHashringSpec{
+						hashring("a", "glob", "team-[a"),
+					},
+				},
+			},
+			wantError: "spec.ingesterSpec.hashrings[0].tenancyConfig.tenants[0]: Invalid value: \"team-[a\": invalid glob pattern",
+		},
+		{
+			name: "excluded tenant with dedicated hashring",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Ingester: v1alpha1.IngesterSpec{
+					DefaultObjectStorageConfig: objStore("valid"),
+					Hashrings: []v1alpha1.IngesterHashringSpec{
+						excluding(hashring("default", ""), "tenant-a"),
+						hashring("dedicated", "glob", "tenant-*"),
+					},
+				},
+			},
+		},
+		{
+			name: "excluded tenant without dedicated hashring",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Ingester: v1alpha1.IngesterSpec{
+					DefaultObjectStorageConfig: objStore("valid"),
+					Hashrings: []v1alpha1.IngesterHashringSpec{
+						excluding(hashring("default", ""), "tenant-a"),
+						hashring("other", "exact", "tenant-b"),
+					},
+				},
+			},
+			wantError: "spec.ingesterSpec.hashrings[0].tenancyConfig.excludeTenants[0]: Invalid value: \"tenant-a\": tenant is not listed by any other hashring",
+		},
+		{
+			name: "circular excluded tenants",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Ingester: v1alpha1.IngesterSpec{
+					DefaultObjectStorageConfig: objStore("valid"),
+					Hashrings: []v1alpha1.IngesterHashringSpec{
+						excluding(hashring("a", "glob", "tenant-*"), "tenant-b"),
+						excluding(hashring("b", "glob", "tenant-?"), "tenant-a"),
+					},
+				},
+			},
+			wantError: "the excluded tenants of the hashrings are circular",
+		},
+		{
+			name: "external labels",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Ingester: v1alpha1.IngesterSpec{
+					DefaultObjectStorageConfig: objStore("valid"),
+					Hashrings: []v1alpha1.IngesterHashringSpec{
+						labeled(hashring("a", "exact", "tenant-a"), v1alpha1.ExternalLabels{"replica": "$(POD_NAME)", "region": "eu"}),
+						labeled(hashring("b", "exact", "tenant-b"), v1alpha1.ExternalLabels{"replica": "b-$(POD_NAME)", "region": "us"}),
+					},
+				},
+			},
+		},
+		{
+			name: "external label collides with replica label of another hashring",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Ingester: v1alpha1.IngesterSpec{
+					DefaultObjectStorageConfig: objStore("valid"),
+					Hashrings: []v1alpha1.IngesterHashringSpec{
+						labeled(hashring("a", "exact", "tenant-a"), v1alpha1.ExternalLabels{"region": "eu"}),
+						labeled(hashring("b", "exact", "tenant-b"), v1alpha1.ExternalLabels{"receive_replica": "b"}),
+					},
+				},
+			},
+			wantError: `spec.ingesterSpec.hashrings[1].externalLabels[receive_replica]: Invalid value: "b": label is a replica label of hashring a`,
+		},
+		{
+			name: "static replica label",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Ingester: v1alpha1.IngesterSpec{
+					DefaultObjectStorageConfig: objStore("valid"),
+					Hashrings: []v1alpha1.IngesterHashringSpec{
+						labeled(hashring("a", "exact", "tenant-a"), v1alpha1.ExternalLabels{"replica": "$(POD_NAME)", "receive_replica": "a"}),
+					},
+				},
+			},
+			wantError: `spec.ingesterSpec.hashrings[0].externalLabels[receive_replica]: Invalid value: "a": label is a replica label of the ingesters`,
+		},
+		{
+			name: "external label collides with replica label of a query",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Ingester: v1alpha1.IngesterSpec{
+					DefaultObjectStorageConfig: objStore("valid"),
+					Hashrings: []v1alpha1.IngesterHashringSpec{
+						labeled(hashring("a", "exact", "tenant-a"), v1alpha1.ExternalLabels{"prometheus_replica": "eu"}),
+					},
+				},
+			},
+			wantError: `spec.ingesterSpec.hashrings[0].externalLabels[prometheus_replica]: Invalid value: "eu": label is a replica label of ThanosQuery query`,
+		},
+		{
+			name: "capnproto replication",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Router: v1alpha1.RouterSpec{ReplicationProtocol: ptr.To(v1alpha1.ReplicationProtocolCapnProto)},
+				Ingester: v1alpha1.IngesterSpec{
+					DefaultObjectStorageConfig: objStore("valid"),
+					Hashrings: []v1alpha1.IngesterHashringSpec{
+						hashring("a", "exact", "tenant-a"),
+						func() v1alpha1.IngesterHashringSpec {
+							h := hashring("b", "exact", "tenant-b")
+							h.Version = ptr.To("v0.34.0")
+							h.EndpointAddress = &v1alpha1.EndpointAddressConfig{ReplicationProtocol: ptr.To(v1alpha1.ReplicationProtocolGRPC)}
+							return h
+						}(),
+					},
+				},
+			},
+		},
+		{
+			name: "capnproto replication with old router",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Router: v1alpha1.RouterSpec{
+					CommonFields:        v1alpha1.CommonFields{Version: ptr.To("v0.34.0")},
+					ReplicationProtocol: ptr.To(v1alpha1.ReplicationProtocolCapnProto),
+				},
+				Ingester: v1alpha1.IngesterSpec{DefaultObjectStorageConfig: objStore("valid")},
+			},
+			wantError: `spec.routerSpec.replicationProtocol: Invalid value: "capnproto": requires the routers to run Thanos v0.35.0 or later`,
+		},
+		{
+			name: "capnproto replication with old hashring",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Router: v1alpha1.RouterSpec{ReplicationProtocol: ptr.To(v1alpha1.ReplicationProtocolCapnProto)},
+				Ingester: v1alpha1.IngesterSpec{
+					DefaultObjectStorageConfig: objStore("valid"),
+					Hashrings: []v1alpha1.IngesterHashringSpec{
+						func() v1alpha1.IngesterHashringSpec {
+							h := hashring("a", "exact", "tenant-a")
+							h.Version = ptr.To("v0.34.0")
+							return h
+						}(),
+					},
+				},
+			},
+			wantError: `spec.ingesterSpec.hashrings[0].version: Invalid value: "v0.34.0"`,
+		},
+		{
+			name: "inverted block durations",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Ingester: v1alpha1.IngesterSpec{
+					DefaultObjectStorageConfig: objStore("valid"),
+					Hashrings: []v1alpha1.IngesterHashringSpec{
+						func() v1alpha1.IngesterHashringSpec {
+							h := hashring("a", "exact", "tenant-a")
+							h.TSDBConfig = v1alpha1.TSDBConfig{MinBlockDuration: ptr.To(v1alpha1.Duration("2h")), MaxBlockDuration: ptr.To(v1alpha1.Duration("90m"))}
+							return h
+						}(),
+					},
+				},
+			},
+			wantError: `spec.ingesterSpec.hashrings[0].tsdbConfig.maxBlockDuration: Invalid value: "90m": must not be lower than minBlockDuration`,
+		},
+		{
+			name: "additional args",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Router: v1alpha1.RouterSpec{
+					Additional: v1alpha1.Additional{Args: []string{"--receive.forward-timeout=10s"}},
+				},
+				Ingester: v1alpha1.IngesterSpec{
+					DefaultObjectStorageConfig: objStore("valid"),
+					Hashrings: []v1alpha1.IngesterHashringSpec{
+						func() v1alpha1.IngesterHashringSpec {
+							h := hashring("a", "exact", "tenant-a")
+							h.AdditionalArgs = []string{"--tsdb.max-exemplars=100"}
+							return h
+						}(),
+					},
+				},
+			},
+		},
+		{
+			name: "router additional args override managed flag",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Router: v1alpha1.RouterSpec{
+					Additional: v1alpha1.Additional{Args: []string{"--receive.hashrings-file=/tmp/hashrings.json"}},
+				},
+				Ingester: v1alpha1.IngesterSpec{DefaultObjectStorageConfig: objStore("valid")},
+			},
+			wantError: "spec.routerSpec.additionalArgs[0]: Forbidden: flag --receive.hashrings-file is managed by the operator",
+		},
+		{
+			name: "hashring additional args override managed flag",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Ingester: v1alpha1.IngesterSpec{
+					DefaultObjectStorageConfig: objStore("valid"),
+					Hashrings: []v1alpha1.IngesterHashringSpec{
+						func() v1alpha1.IngesterHashringSpec {
+							h := hashring("a", "exact", "tenant-a")
+							h.AdditionalArgs = []string{"--tsdb.path=/tmp"}
+							return h
+						}(),
+					},
+				},
+			},
+			wantError: "spec.ingesterSpec.hashrings[0].additionalArgs[0]: Forbidden: flag --tsdb.path is managed by the operator",
+		},
+		{
+			name: "router service port override",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Router: v1alpha1.RouterSpec{
+					Service: &v1alpha1.ServiceConfig{
+						Type:  corev1.ServiceTypeLoadBalancer,
+						Ports: []v1alpha1.ServicePortConfig{{Name: "remote-write", Port: 443}},
+					},
+				},
+				Ingester: v1alpha1.IngesterSpec{DefaultObjectStorageConfig: objStore("valid")},
+			},
+		},
+		{
+			name: "router service override of unknown port",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Router: v1alpha1.RouterSpec{
+					Service: &v1alpha1.ServiceConfig{Ports: []v1alpha1.ServicePortConfig{{Name: "remote_write", Port: 443}}},
+				},
+				Ingester: v1alpha1.IngesterSpec{DefaultObjectStorageConfig: objStore("valid")},
+			},
+			wantError: "spec.routerSpec.service.ports[0].name: Unsupported value: \"remote_write\"",
+		},
+		{
+			name: "router relabel configs",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Router: v1alpha1.RouterSpec{
+					RelabelConfigs: []v1alpha1.RelabelConfig{{SourceLabels: []string{"__name__"}, Regex: ptr.To("go_.*"), Action: "drop"}},
+				},
+				Ingester: v1alpha1.IngesterSpec{DefaultObjectStorageConfig: objStore("valid")},
+			},
+		},
+		{
+			name: "router relabel config with invalid regex",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Router: v1alpha1.RouterSpec{
+					RelabelConfigs: []v1alpha1.RelabelConfig{{SourceLabels: []string{"__name__"}, Regex: ptr.To("go_(.*"), Action: "drop"}},
+				},
+				Ingester: v1alpha1.IngesterSpec{DefaultObjectStorageConfig: objStore("valid")},
+			},
+			wantError: "spec.routerSpec.relabelConfigs[0].regex: Invalid value",
+		},
+		{
+			name: "additional args not a flag",
+			spec: v1alpha1.ThanosReceiveSpec{
+				Ingester: v1alpha1.IngesterSpec{
+					DefaultObjectStorageConfig: objStore("valid"),
+					Additional:                 v1alpha1.Additional{Args: []string{"10s"}},
+				},
+			},
+			wantError: "spec.ingesterSpec.additionalArgs[0]: Invalid value: \"10s\": must be a flag",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			receiver := &v1alpha1.ThanosReceive{
+				ObjectMeta: metav1.ObjectMeta{Name: "receive", Namespace: ns},
+				Spec:       tc.spec,
+			}
+
+			_, createErr := v.ValidateCreate(context.Background(), receiver)
+			_, updateErr := v.ValidateUpdate(context.Background(), receiver, receiver)
+			for _, err := range []error{createErr, updateErr} {
+				if tc.wantError == "" {
+					if err != nil {
+						t.Errorf("unexpected error: %v", err)
+					}
+					continue
+				}
+				if err == nil || !strings.Contains(err.Error(), tc.wantError) {
+					t.Errorf("expected error containing %q, got %v", tc.wantError, err)
+				}
+			}
+		})
+	}
+}
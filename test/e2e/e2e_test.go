@@ -267,7 +267,7 @@ var _ = Describe("controller", Ordered, func() {
 							CommonFields: v1alpha1.CommonFields{
 								Version: getThanosVersion(),
 							},
-							Replicas:          1,
+							Replicas:          ptr.To(int32(1)),
 							ReplicationFactor: 1,
 							HashringPolicy:    ptr.To(v1alpha1.HashringPolicyStatic),
 							ExternalLabels: map[string]string{
@@ -380,6 +380,17 @@ var _ = Describe("controller", Ordered, func() {
 					return utils.DoRemoteWriteRequest(c, utils.DefaultRemoteWriteRequest(), namespace, matchLabels, receive.RemoteWritePort)
 				}, time.Minute*2, time.Second*1).Should(Succeed())
 			})
+
+			It("should pass the remote write receiver conformance checks", Label("conformance"), func() {
+				matchLabels := map[string]string{
+					manifests.ComponentLabel: receive.RouterComponentName,
+					manifests.OwnerLabel:     receiveName,
+				}
+				results, err := utils.DoRemoteWriteConformance(c, namespace, matchLabels, receive.RemoteWritePort)
+				Expect(err).To(BeNil())
+				AddReportEntry("remote write conformance", results.String())
+				Expect(results.Failed()).To(BeEmpty(), results.String())
+			})
 		})
 
 		Context("When ThanosReceive with capnproto replication protocol is created", func() {
@@ -420,7 +431,7 @@ var _ = Describe("controller", Ordered, func() {
 							CommonFields: v1alpha1.CommonFields{
 								Version: getThanosVersion(),
 							},
-							Replicas:            1,
+							Replicas:            ptr.To(int32(1)),
 							ReplicationFactor:   1,
 							ReplicationProtocol: ptr.To(v1alpha1.ReplicationProtocolCapnProto),
 							HashringPolicy:      ptr.To(v1alpha1.HashringPolicyStatic),
@@ -525,7 +536,7 @@ var _ = Describe("controller", Ordered, func() {
 								"some-label": "xyz",
 							},
 						},
-						Replicas: 1,
+						Replicas: ptr.To(int32(1)),
 						ReplicaLabels: []string{
 							"prometheus_replica",
 							"replica",
@@ -551,7 +562,7 @@ var _ = Describe("controller", Ordered, func() {
 						deploymentName,
 						namespace,
 						0,
-						fmt.Sprintf("--endpoint=dnssrv+_grpc._tcp.%s.thanos-operator-system.svc", svcName),
+						fmt.Sprintf("--endpoint=dns+%s.thanos-operator-system.svc:%d", svcName, receive.GRPCPort),
 					)
 				}, time.Minute*1, time.Second*1).Should(BeTrue())
 			})
@@ -0,0 +1,266 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/prompb"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// remoteWriteVersion is the version of the remote write protocol sent in the X-Prometheus-Remote-Write-Version header.
+const remoteWriteVersion = "0.1.0"
+
+// RemoteWriteConformanceResult is the result of a remote write receiver conformance check.
+type RemoteWriteConformanceResult struct {
+	Name string
+	Err  error
+}
+
+// RemoteWriteConformanceResults are the results of a run of the remote write receiver conformance checks.
+type RemoteWriteConformanceResults []RemoteWriteConformanceResult
+
+// Failed returns the results of the checks that failed.
+func (r RemoteWriteConformanceResults) Failed() RemoteWriteConformanceResults {
+	var failed RemoteWriteConformanceResults
+	for _, res := range r {
+		if res.Err != nil {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+// String formats the results as one line per check, so that they can be published in the test report.
+func (r RemoteWriteConformanceResults) String() string {
+	var sb strings.Builder
+	for _, res := range r {
+		if res.Err != nil {
+			fmt.Fprintf(&sb, "FAIL %s: %v\n", res.Name, res.Err)
+			continue
+		}
+		fmt.Fprintf(&sb, "PASS %s\n", res.Name)
+	}
+	return sb.String()
+}
+
+// conformanceCase is a remote write receiver conformance check. All the requests but the last must be accepted,
+// and the last one must be accepted if accept is set or rejected as a client error otherwise.
+type conformanceCase struct {
+	name     string
+	requests func(series string, now time.Time) [][]byte
+	accept   bool
+}
+
+// conformanceCases are the checks of the Prometheus remote write 1.0 specification that apply to receivers.
+var conformanceCases = []conformanceCase{
+	{
+		name: "accepts a sample",
+		requests: func(series string, now time.Time) [][]byte {
+			return [][]byte{encodeWriteRequest(writeRequest(series, now, 1))}
+		},
+		accept: true,
+	},
+	{
+		name: "accepts a stale marker",
+		requests: func(series string, now time.Time) [][]byte {
+			return [][]byte{
+				encodeWriteRequest(writeRequest(series, now, 1)),
+				encodeWriteRequest(writeRequest(series, now.Add(time.Second), math.Float64frombits(value.StaleNaN))),
+			}
+		},
+		accept: true,
+	},
+	{
+		name: "accepts a batch of series",
+		requests: func(series string, now time.Time) [][]byte {
+			req := &prompb.WriteRequest{}
+			for i := range 100 {
+				ts := writeRequest(series, now, float64(i)).Timeseries[0]
+				ts.Labels = append(ts.Labels, prompb.Label{Name: "series", Value: fmt.Sprint(i)})
+				for j := 1; j < 10; j++ {
+					ts.Samples = append(ts.Samples, prompb.Sample{Value: float64(j), Timestamp: now.Add(time.Duration(j) * time.Second).UnixMilli()})
+				}
+				req.Timeseries = append(req.Timeseries, ts)
+			}
+			return [][]byte{encodeWriteRequest(req)}
+		},
+		accept: true,
+	},
+	{
+		name: "rejects a body not compressed with snappy",
+		requests: func(series string, now time.Time) [][]byte {
+			raw, _ := writeRequest(series, now, 1).Marshal()
+			return [][]byte{raw}
+		},
+	},
+	{
+		name: "rejects a body that is not a write request",
+		requests: func(string, time.Time) [][]byte {
+			return [][]byte{snappy.Encode(nil, []byte("not a protobuf message"))}
+		},
+	},
+	{
+		name: "rejects a series without labels",
+		requests: func(_ string, now time.Time) [][]byte {
+			return [][]byte{encodeWriteRequest(&prompb.WriteRequest{Timeseries: []prompb.TimeSeries{
+				{Samples: []prompb.Sample{{Value: 1, Timestamp: now.UnixMilli()}}},
+			}})}
+		},
+	},
+	{
+		name: "rejects a series with unsorted labels",
+		requests: func(series string, now time.Time) [][]byte {
+			req := writeRequest(series, now, 1)
+			ts := &req.Timeseries[0]
+			ts.Labels[0], ts.Labels[1] = ts.Labels[1], ts.Labels[0]
+			return [][]byte{encodeWriteRequest(req)}
+		},
+	},
+	{
+		name: "rejects an out of order sample",
+		requests: func(series string, now time.Time) [][]byte {
+			return [][]byte{
+				encodeWriteRequest(writeRequest(series, now, 1)),
+				encodeWriteRequest(writeRequest(series, now.Add(-time.Minute), 1)),
+			}
+		},
+	},
+	{
+		name: "rejects a duplicate sample with a different value",
+		requests: func(series string, now time.Time) [][]byte {
+			return [][]byte{
+				encodeWriteRequest(writeRequest(series, now, 1)),
+				encodeWriteRequest(writeRequest(series, now, 2)),
+			}
+		},
+	},
+}
+
+// RunRemoteWriteConformance runs the remote write receiver conformance checks against the endpoint.
+// Each check writes to its own series, named after the check and the time of the run, so that runs do not conflict.
+func RunRemoteWriteConformance(endpoint string, roundTripper http.RoundTripper) RemoteWriteConformanceResults {
+	if roundTripper == nil {
+		roundTripper = http.DefaultTransport
+	}
+	httpClient := &http.Client{Transport: roundTripper, Timeout: 5 * time.Second}
+	now := time.Now()
+
+	results := make(RemoteWriteConformanceResults, 0, len(conformanceCases))
+	for i, tc := range conformanceCases {
+		series := fmt.Sprintf("conformance_%d_%d", i, now.UnixNano())
+		results = append(results, RemoteWriteConformanceResult{
+			Name: tc.name,
+			Err:  runConformanceCase(httpClient, endpoint, tc, series, now),
+		})
+	}
+	return results
+}
+
+func runConformanceCase(httpClient *http.Client, endpoint string, tc conformanceCase, series string, now time.Time) error {
+	requests := tc.requests(series, now)
+	for i, body := range requests {
+		status, err := sendRemoteWrite(httpClient, endpoint, body)
+		if err != nil {
+			return err
+		}
+		last := i == len(requests)-1
+		switch {
+		case (!last || tc.accept) && (status < 200 || status >= 300):
+			return fmt.Errorf("request %d: expected a 2xx status, got %d", i+1, status)
+		case last && !tc.accept && (status < 400 || status >= 500):
+			return fmt.Errorf("request %d: expected a 4xx status, got %d", i+1, status)
+		}
+	}
+	return nil
+}
+
+// sendRemoteWrite sends the body with the headers required by the remote write specification and returns the status code.
+func sendRemoteWrite(httpClient *http.Client, endpoint string, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("User-Agent", "thanos-operator-e2e")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", remoteWriteVersion)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
+
+// writeRequest returns a write request holding a single sample of the series, with sorted labels.
+func writeRequest(series string, ts time.Time, v float64) *prompb.WriteRequest {
+	return &prompb.WriteRequest{Timeseries: []prompb.TimeSeries{
+		{
+			Labels: []prompb.Label{
+				{Name: "__name__", Value: series},
+				{Name: "job", Value: "conformance"},
+			},
+			Samples: []prompb.Sample{{Value: v, Timestamp: ts.UnixMilli()}},
+		},
+	}}
+}
+
+func encodeWriteRequest(req *prompb.WriteRequest) []byte {
+	raw, _ := req.Marshal()
+	return snappy.Encode(nil, raw)
+}
+
+// DoRemoteWriteConformance runs the remote write receiver conformance checks against the remote write endpoint
+// of the Thanos Receive router in the namespace.
+func DoRemoteWriteConformance(c client.Client, namespace string, matchLabels map[string]string, port int32) (RemoteWriteConformanceResults, error) {
+	ctx := context.Background()
+	cancelFn, err := portForwardToRouter(ctx, c, namespace, matchLabels, port)
+	if err != nil {
+		return nil, err
+	}
+	defer cancelFn()
+	return RunRemoteWriteConformance(fmt.Sprintf("http://localhost:%d/api/v1/receive", port), nil), nil
+}
+
+// portForwardToRouter forwards the port of the first router pod matching the labels in the namespace to localhost.
+func portForwardToRouter(ctx context.Context, c client.Client, namespace string, matchLabels map[string]string, port int32) (func(), error) {
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.MatchingLabels(matchLabels), client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	if len(pods.Items) == 0 {
+		return nil, fmt.Errorf("no router pods found")
+	}
+	return StartPortForward(ctx, intstr.IntOrString{IntVal: port}, "https", pods.Items[0].Name, namespace)
+}
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+)
+
+// conformingReceiver is a minimal remote write receiver following the specification.
+type conformingReceiver struct {
+	mtx     sync.Mutex
+	samples map[string]prompb.Sample
+}
+
+func (h *conformingReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	compressed, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	raw, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req := &prompb.WriteRequest{}
+	if err := req.Unmarshal(raw); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	for _, ts := range req.Timeseries {
+		if len(ts.Labels) == 0 || !sort.SliceIsSorted(ts.Labels, func(i, j int) bool { return ts.Labels[i].Name < ts.Labels[j].Name }) {
+			http.Error(w, "invalid labels", http.StatusBadRequest)
+			return
+		}
+		var key string
+		for _, l := range ts.Labels {
+			key += l.Name + "=" + l.Value + ","
+		}
+		for _, s := range ts.Samples {
+			last, ok := h.samples[key]
+			if ok && (s.Timestamp < last.Timestamp || s.Timestamp == last.Timestamp && s.Value != last.Value) {
+				http.Error(w, "out of order sample", http.StatusConflict)
+				return
+			}
+			h.samples[key] = s
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestRunRemoteWriteConformance(t *testing.T) {
+	srv := httptest.NewServer(&conformingReceiver{samples: map[string]prompb.Sample{}})
+	defer srv.Close()
+
+	results := RunRemoteWriteConformance(srv.URL, nil)
+	if len(results) != len(conformanceCases) {
+		t.Fatalf("expected %d results, got %d", len(conformanceCases), len(results))
+	}
+	if failed := results.Failed(); len(failed) > 0 {
+		t.Errorf("expected a conforming receiver to pass all the checks, got:\n%s", failed)
+	}
+
+	acceptAll := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer acceptAll.Close()
+
+	results = RunRemoteWriteConformance(acceptAll.URL, nil)
+	for _, res := range results {
+		rejects := false
+		for _, tc := range conformanceCases {
+			if tc.name == res.Name {
+				rejects = !tc.accept
+			}
+		}
+		if rejects && res.Err == nil {
+			t.Errorf("expected %q to fail against a receiver accepting all requests", res.Name)
+		}
+		if !rejects && res.Err != nil {
+			t.Errorf("expected %q to pass against a receiver accepting all requests, got %v", res.Name, res.Err)
+		}
+	}
+}
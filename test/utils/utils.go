@@ -598,21 +598,7 @@ func (s *setHeadersTransport) RoundTrip(req *http.Request) (*http.Response, erro
 
 // DoRemoteWriteRequest sends a remote write request to the remote write endpoint for the Thanos Receive in the namespace.
 func DoRemoteWriteRequest(c client.Client, req RemoteWriteRequest, namespace string, matchLabels map[string]string, port int32) error {
-	ctx := context.Background()
-	selector := client.MatchingLabels(matchLabels)
-	router := &corev1.PodList{}
-	err := c.List(ctx, router, selector, &client.ListOptions{Namespace: namespace})
-	if err != nil {
-		return err
-	}
-
-	if len(router.Items) == 0 {
-		return fmt.Errorf("no router pods found")
-	}
-
-	pod := router.Items[0].Name
-	portIntStr := intstr.IntOrString{IntVal: port}
-	cancelFn, err := StartPortForward(ctx, portIntStr, "https", pod, namespace)
+	cancelFn, err := portForwardToRouter(context.Background(), c, namespace, matchLabels, port)
 	if err != nil {
 		return err
 	}